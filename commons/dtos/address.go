@@ -0,0 +1,18 @@
+package dtos
+
+// Address holds the components of the address of a location. Components
+// unknown to the geocoding provider are left empty.
+type Address struct {
+	HouseNumber	string	`json:"house_number,omitempty" form:"house_number" example:"55"`
+	Street		string	`json:"street,omitempty" form:"street" example:"Fruit Street"`
+	City		string	`json:"city,omitempty" form:"city" example:"Boston"`
+	Postcode	string	`json:"postcode,omitempty" form:"postcode" example:"02114"`
+	County		string	`json:"county,omitempty" form:"county" example:"Suffolk County"`
+	Country		string	`json:"country,omitempty" form:"country" example:"United States"`
+	CountryCode	string	`json:"country_code,omitempty" form:"country_code" example:"us"`
+}
+
+// IsEmpty reports whether none of the components is set.
+func (a Address) IsEmpty() bool {
+	return a == Address{}
+}
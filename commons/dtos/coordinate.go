@@ -0,0 +1,61 @@
+package dtos
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// CoordinatePrecision is the number of decimal places coordinates are kept
+// with, about 1 cm.
+const CoordinatePrecision = 7
+
+// Coordinate is a latitude or a longitude in degrees. It is serialized as a
+// string, as the consumers of the first version of the APIs expect, and
+// deserialized from both strings and numbers.
+type Coordinate float64
+
+// ParseCoordinate parses a finite coordinate.
+func ParseCoordinate(s string) (Coordinate, error) {
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, fmt.Errorf("invalid coordinate '%s'", s)
+	}
+	return Coordinate(val), nil
+}
+
+// String formats the coordinate with CoordinatePrecision decimal places.
+func (c Coordinate) String() string {
+	return strconv.FormatFloat(float64(c), 'f', CoordinatePrecision, 64)
+}
+
+func (c Coordinate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+func (c *Coordinate) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+
+	val, err := ParseCoordinate(s)
+	if err != nil {
+		return err
+	}
+	*c = val
+	return nil
+}
+
+// ValidCoordinates reports whether the latitude and the longitude are
+// within their ranges.
+func ValidCoordinates(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// RoundCoordinate rounds a coordinate to CoordinatePrecision decimal places.
+func RoundCoordinate(c float64) float64 {
+	scale := math.Pow10(CoordinatePrecision)
+	return math.Round(c*scale) / scale
+}
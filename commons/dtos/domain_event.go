@@ -0,0 +1,35 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+// EventsExchange is the topic exchange domain events are published to,
+// with their type as the routing key.
+const EventsExchange = "events"
+
+type EventType string
+
+const (
+	AppointmentBooked	EventType = "appointment.booked"
+	AppointmentCancelled	EventType = "appointment.cancelled"
+	MessageReceived		EventType = "message.received"
+	LabResultRecorded	EventType = "lab_result.recorded"
+)
+
+// DomainEvent tells the users concerned that something happened which
+// they should learn about right away. It never carries clinical content;
+// clients fetch the resource if they need more than its ID.
+type DomainEvent struct {
+	ID		uuid.UUID		`json:"id"`
+	Type		EventType		`json:"type"`
+	// Recipients are the users to notify.
+	Recipients	[]uuid.UUID		`json:"recipients"`
+	// ResourceID is the ID of what the event is about: the appointment,
+	// the conversation or, as lab results are browsed per patient, the
+	// patient whose results were recorded.
+	ResourceID	uuid.UUID		`json:"resource_id"`
+	OccurredAt	time.Time		`json:"occurred_at"`
+}
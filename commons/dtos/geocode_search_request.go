@@ -0,0 +1,5 @@
+package dtos
+
+type GeocodeSearchRequest struct {
+	Address string `json:"address" binding:"required" example:"55 Fruit Street, Boston"`
+}
@@ -1,8 +1,12 @@
 package dtos
 
+// Location is a place in OpenStreetMap, identified by its type and ID. The
+// type of locations stored before it was tracked may be unknown.
 type Location struct {
-	ID	int64	`gorm:"uniqueIndex;" json:"osm_id" binding:"required" example:"90394480"`
+	ID	int64	`gorm:"uniqueIndex:idx_location_osm_object,priority:2;" json:"osm_id" binding:"required" example:"90394480"`
+	OsmType	OsmType	`gorm:"uniqueIndex:idx_location_osm_object,priority:1;size:1;not null;default:''" json:"osm_type,omitempty" enums:"N,W,R" example:"W"`
 	Lat	string	`json:"lat" binding:"required" example:"52.5487921"`
 	Lon	string 	`json:"lon" binding:"required" example:"-1.8164308"`
 	Name	string 	`json:"display_name" binding:"required" example:"135, Pilkington Avenue, Maney, Sutton Coldfield, Birmingham, West Midlands, England, B72 1LH, United Kingdom"`
+	Address	Address	`gorm:"embedded" json:"address"`
 }
@@ -1,6 +1,9 @@
 package dtos
 
 type LocationLookupRequest struct {
-	ID int64 `json:"id" binding:"required" example:"90394480"`
+	ID	int64	`json:"id" binding:"required" example:"90394480"`
+	// OsmType may be left out for IDs of unknown type, which are looked
+	// up as nodes, ways and relations in turn.
+	OsmType	OsmType	`json:"osm_type,omitempty" enums:"N,W,R" example:"W"`
 }
 
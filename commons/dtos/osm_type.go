@@ -0,0 +1,77 @@
+package dtos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OsmType is the type of an OpenStreetMap object. IDs are unique only
+// within a type, e.g. node 1 and way 1 are different objects.
+type OsmType string
+
+const (
+	OsmNode		OsmType = "N"
+	OsmWay		OsmType = "W"
+	OsmRelation	OsmType = "R"
+)
+
+// ParseOsmType parses a type given by its letter, as in Photon, or its
+// name, as in Nominatim, ignoring case.
+func ParseOsmType(s string) (OsmType, error) {
+	switch strings.ToLower(s) {
+	case "n", "node":
+		return OsmNode, nil
+	case "w", "way":
+		return OsmWay, nil
+	case "r", "relation":
+		return OsmRelation, nil
+	default:
+		return "", fmt.Errorf("invalid OSM type '%s'", s)
+	}
+}
+
+// ParseOsmID parses an ID optionally prefixed by the letter of its type,
+// e.g. R2315704. IDs without a prefix have an empty type.
+func ParseOsmID(s string) (OsmType, int64, error) {
+	var osmType OsmType
+	if s != "" && (s[0] < '0' || s[0] > '9') {
+		var err error
+		if osmType, err = ParseOsmType(s[:1]); err != nil {
+			return "", 0, err
+		}
+		s = s[1:]
+	}
+
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, fmt.Errorf("invalid OSM ID '%s'", s)
+	}
+	return osmType, id, nil
+}
+
+// FormatOsmID prefixes the ID with the letter of its type, if known.
+func FormatOsmID(osmType OsmType, id int64) string {
+	return string(osmType) + strconv.FormatInt(id, 10)
+}
+
+// UnmarshalJSON accepts the letters and the names of the types. Empty
+// strings leave the type unknown.
+func (t *OsmType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*t = ""
+		return nil
+	}
+
+	val, err := ParseOsmType(s)
+	if err != nil {
+		return err
+	}
+	*t = val
+	return nil
+}
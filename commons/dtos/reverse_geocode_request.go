@@ -1,6 +1,6 @@
 package dtos
 
 type ReverseGeocodeRequest struct {
-	Lat string `json:"lat" binding:"required" example:"40.7579554"`
-	Lon string `json:"lon" binding:"required" example:"-73.9855319"`
+	Lat Coordinate `json:"lat" binding:"required" swaggertype:"string" example:"40.7579554"`
+	Lon Coordinate `json:"lon" binding:"required" swaggertype:"string" example:"-73.9855319"`
 }
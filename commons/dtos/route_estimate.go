@@ -0,0 +1,9 @@
+package dtos
+
+// RouteEstimate is the length and the travel time of the fastest route by
+// a mode of travel.
+type RouteEstimate struct {
+	Mode		TravelMode	`json:"mode" enums:"driving,walking,cycling" example:"driving"`
+	DistanceMeters	float64		`json:"distance_meters" example:"5364.2"`
+	DurationSeconds	float64		`json:"duration_seconds" example:"712.5"`
+}
@@ -0,0 +1,11 @@
+package dtos
+
+// RouteRequest asks for estimates of routes between two points by the given
+// modes of travel, or by all modes offered when none is given.
+type RouteRequest struct {
+	FromLat	Coordinate	`json:"from_lat" binding:"required" swaggertype:"string" example:"42.3736158"`
+	FromLon	Coordinate	`json:"from_lon" binding:"required" swaggertype:"string" example:"-71.1097335"`
+	ToLat	Coordinate	`json:"to_lat" binding:"required" swaggertype:"string" example:"42.3628605"`
+	ToLon	Coordinate	`json:"to_lon" binding:"required" swaggertype:"string" example:"-71.068753"`
+	Modes	[]TravelMode	`json:"modes,omitempty"`
+}
@@ -0,0 +1,30 @@
+package dtos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TravelMode is a way of getting to a place, for which routes are
+// estimated.
+type TravelMode string
+
+const (
+	Driving	TravelMode = "driving"
+	Walking	TravelMode = "walking"
+	Cycling	TravelMode = "cycling"
+)
+
+// TravelModes are all the modes, in the order estimates are listed in.
+var TravelModes = []TravelMode{Driving, Walking, Cycling}
+
+// ParseTravelMode parses the name of a mode, ignoring case.
+func ParseTravelMode(s string) (TravelMode, error) {
+	mode := TravelMode(strings.ToLower(strings.TrimSpace(s)))
+	for _, m := range TravelModes {
+		if m == mode {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("invalid travel mode '%s'", s)
+}
@@ -0,0 +1,28 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"igaku/commons/models"
+)
+
+// UserSearchRequest filters users for the `search_users` RPC. Empty
+// filters match every user.
+type UserSearchRequest struct {
+	IDs		[]uuid.UUID	`json:"ids,omitempty"`
+	Role		models.Role	`json:"role,omitempty"`
+	Username	string		`json:"username,omitempty"`
+	// UsernamePrefix matches usernames starting with it, ignoring case.
+	UsernamePrefix	string		`json:"username_prefix,omitempty"`
+	// Email matches the whole address, ignoring case.
+	Email		string		`json:"email,omitempty"`
+	Offset		int		`json:"offset"`
+	Limit		int		`json:"limit"`
+}
+
+// UserSearchResult holds a page of users, without their passwords, and the
+// number of users matching the filters.
+type UserSearchResult struct {
+	Users	[]models.User	`json:"users"`
+	Total	int64		`json:"total"`
+}
@@ -0,0 +1,151 @@
+package batch
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"time"
+
+	"igaku/geo-service/models"
+)
+
+// createBatchSize is the number of rows inserted by a single statement.
+const createBatchSize = 200
+
+// PostgresStore keeps jobs in PostgreSQL, so that any replica can report
+// their progress and take over the jobs of a stopped one.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Create(
+	job *models.BatchJob, rows []models.BatchRow,
+) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(rows, createBatchSize).Error
+	})
+}
+
+func (s *PostgresStore) FindJob(id uuid.UUID) (*models.BatchJob, error) {
+	var job models.BatchJob
+	err := s.db.First(&job, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *PostgresStore) FindRows(jobID uuid.UUID) ([]models.BatchRow, error) {
+	var rows []models.BatchRow
+	err := s.db.
+		Where("job_id = ?", jobID).
+		Order("row_index").
+		Find(&rows).
+		Error
+	return rows, err
+}
+
+func (s *PostgresStore) Claim(
+	now, staleBefore time.Time,
+) (*models.BatchJob, error) {
+	var claimed *models.BatchJob
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Jobs locked by another replica are skipped rather than
+		// waited for, so that each job is processed by one replica.
+		var job models.BatchJob
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(
+				"status = ? OR (status = ? AND updated_at < ?)",
+				models.BatchQueued, models.BatchRunning, staleBefore,
+			).
+			Order("created_at").
+			First(&job).
+			Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		job.Status = models.BatchRunning
+		job.UpdatedAt = now
+		err = tx.Model(&job).
+			Updates(map[string]any{
+				"status": job.Status, "updated_at": job.UpdatedAt,
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		claimed = &job
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+func (s *PostgresStore) SaveRow(row *models.BatchRow, now time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(row).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.BatchJob{}).
+			Where("id = ?", row.JobID).
+			Updates(map[string]any{
+				"processed": gorm.Expr("processed + 1"),
+				"updated_at": now,
+			}).
+			Error
+	})
+}
+
+func (s *PostgresStore) Finish(id uuid.UUID, now time.Time) error {
+	return s.db.Model(&models.BatchJob{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status": models.BatchCompleted,
+			"updated_at": now,
+			"finished_at": now,
+		}).
+		Error
+}
+
+func (s *PostgresStore) DeleteFinished(before time.Time) (int64, error) {
+	var deleted int64
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		finished := tx.Model(&models.BatchJob{}).
+			Select("id").
+			Where("finished_at < ?", before)
+
+		err := tx.Where("job_id IN (?)", finished).
+			Delete(&models.BatchRow{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Where("finished_at < ?", before).Delete(&models.BatchJob{})
+		deleted = res.RowsAffected
+		return res.Error
+	})
+	return deleted, err
+}
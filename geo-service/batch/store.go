@@ -0,0 +1,148 @@
+package batch
+
+import (
+	"github.com/google/uuid"
+
+	"slices"
+	"sync"
+	"time"
+
+	"igaku/geo-service/models"
+)
+
+// Store keeps batch jobs and their rows.
+type Store interface {
+	// Create saves a queued job along with its rows.
+	Create(job *models.BatchJob, rows []models.BatchRow) error
+	// FindJob returns nil when there is no such job.
+	FindJob(id uuid.UUID) (*models.BatchJob, error)
+	// FindRows returns the rows of the job in order.
+	FindRows(jobID uuid.UUID) ([]models.BatchRow, error)
+	// Claim marks the oldest queued job, or a running job not updated
+	// since staleBefore, as running and returns it. It returns nil when
+	// there is no such job.
+	Claim(now, staleBefore time.Time) (*models.BatchJob, error)
+	// SaveRow saves the result of a row and counts it as processed.
+	SaveRow(row *models.BatchRow, now time.Time) error
+	// Finish marks the job as completed.
+	Finish(id uuid.UUID, now time.Time) error
+	// DeleteFinished removes the jobs finished before the given time,
+	// returning how many there were.
+	DeleteFinished(before time.Time) (int64, error)
+}
+
+// MemoryStore keeps jobs in memory, visible only to the replica which
+// accepted them.
+type MemoryStore struct {
+	mu	sync.Mutex
+	jobs	map[uuid.UUID]*models.BatchJob
+	rows	map[uuid.UUID][]models.BatchRow
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs: map[uuid.UUID]*models.BatchJob{},
+		rows: map[uuid.UUID][]models.BatchRow{},
+	}
+}
+
+func (s *MemoryStore) Create(
+	job *models.BatchJob, rows []models.BatchRow,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *job
+	s.jobs[job.ID] = &stored
+	s.rows[job.ID] = slices.Clone(rows)
+	return nil
+}
+
+func (s *MemoryStore) FindJob(id uuid.UUID) (*models.BatchJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *job
+	return &found, nil
+}
+
+func (s *MemoryStore) FindRows(jobID uuid.UUID) ([]models.BatchRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.rows[jobID]), nil
+}
+
+func (s *MemoryStore) Claim(
+	now, staleBefore time.Time,
+) (*models.BatchJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var oldest *models.BatchJob
+	for _, job := range s.jobs {
+		claimable := job.Status == models.BatchQueued ||
+			(job.Status == models.BatchRunning &&
+				job.UpdatedAt.Before(staleBefore))
+		if claimable &&
+			(oldest == nil || job.CreatedAt.Before(oldest.CreatedAt)) {
+			oldest = job
+		}
+	}
+	if oldest == nil {
+		return nil, nil
+	}
+
+	oldest.Status = models.BatchRunning
+	oldest.UpdatedAt = now
+	claimed := *oldest
+	return &claimed, nil
+}
+
+func (s *MemoryStore) SaveRow(row *models.BatchRow, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := s.rows[row.JobID]
+	for i := range rows {
+		if rows[i].Row == row.Row {
+			rows[i] = *row
+		}
+	}
+	if job, ok := s.jobs[row.JobID]; ok {
+		job.Processed++
+		job.UpdatedAt = now
+	}
+	return nil
+}
+
+func (s *MemoryStore) Finish(id uuid.UUID, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[id]; ok {
+		job.Status = models.BatchCompleted
+		job.UpdatedAt = now
+		job.FinishedAt = &now
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteFinished(before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for id, job := range s.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(before) {
+			delete(s.jobs, id)
+			delete(s.rows, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"igaku/geo-service/dtos"
+)
+
+// Entry is a cached result. Negative entries record that there was no
+// result, so that lookups of unknown places are not repeated either.
+type Entry struct {
+	Value		[]byte
+	Negative	bool
+	ExpiresAt	time.Time
+}
+
+// Store keeps cache entries. Expired entries are never returned.
+type Store interface {
+	Get(key string, now time.Time) (*Entry, error)
+	Set(key string, entry Entry) error
+}
+
+// Cache looks entries up in memory first and then in the optional
+// persistent store, copying entries found there into memory. Failures of
+// the persistent store are logged and treated as misses, as the cache
+// must never make a lookup fail.
+type Cache struct {
+	memory		Store
+	persistent	Store
+	mu		sync.Mutex
+	counters	map[string]*counters
+}
+
+type counters struct {
+	memoryHits	atomic.Int64
+	persistentHits	atomic.Int64
+	negativeHits	atomic.Int64
+	misses		atomic.Int64
+}
+
+// New creates a cache. The persistent store may be nil.
+func New(memory Store, persistent Store) *Cache {
+	return &Cache{
+		memory: memory,
+		persistent: persistent,
+		counters: map[string]*counters{},
+	}
+}
+
+// Get returns the entry of the key, counting the hit or miss for the
+// operation.
+func (c *Cache) Get(operation, key string) (*Entry, bool) {
+	now := time.Now()
+	counters := c.operation(operation)
+
+	entry, _ := c.memory.Get(key, now)
+	if entry != nil {
+		counters.memoryHits.Add(1)
+	} else if c.persistent != nil {
+		var err error
+		entry, err = c.persistent.Get(key, now)
+		if err != nil {
+			log.Printf("Failed to read geo cache entry '%s': %v", key, err)
+		}
+		if entry != nil {
+			counters.persistentHits.Add(1)
+			c.memory.Set(key, *entry)
+		}
+	}
+
+	if entry == nil {
+		counters.misses.Add(1)
+		return nil, false
+	}
+	if entry.Negative {
+		counters.negativeHits.Add(1)
+	}
+	return entry, true
+}
+
+// Set caches the value of the key for the given time. A nil value is
+// cached as a negative entry.
+func (c *Cache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	entry := Entry{
+		Value: value,
+		Negative: value == nil,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	c.memory.Set(key, entry)
+	if c.persistent != nil {
+		if err := c.persistent.Set(key, entry); err != nil {
+			log.Printf("Failed to write geo cache entry '%s': %v", key, err)
+		}
+	}
+}
+
+// Metrics returns the hits and misses of each operation so far.
+func (c *Cache) Metrics() dtos.CacheMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metrics := dtos.CacheMetrics{
+		Persistent: c.persistent != nil,
+		Operations: map[string]dtos.CacheOperationMetrics{},
+	}
+	for operation, counters := range c.counters {
+		metrics.Operations[operation] = dtos.CacheOperationMetrics{
+			MemoryHits: counters.memoryHits.Load(),
+			PersistentHits: counters.persistentHits.Load(),
+			NegativeHits: counters.negativeHits.Load(),
+			Misses: counters.misses.Load(),
+		}
+	}
+	return metrics
+}
+
+func (c *Cache) operation(operation string) *counters {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counters[operation] == nil {
+		c.counters[operation] = &counters{}
+	}
+	return c.counters[operation]
+}
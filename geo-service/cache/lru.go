@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is an in-memory store holding a bounded number of entries, evicting
+// the least recently used one when full.
+type LRU struct {
+	mu		sync.Mutex
+	capacity	int
+	order		*list.List
+	items		map[string]*list.Element
+}
+
+type lruItem struct {
+	key	string
+	entry	Entry
+}
+
+func NewLRU(capacity int) *LRU {
+	return &LRU{
+		capacity: capacity,
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+func (l *LRU) Get(key string, now time.Time) (*Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.items[key]
+	if !ok {
+		return nil, nil
+	}
+
+	item := elem.Value.(*lruItem)
+	if !now.Before(item.entry.ExpiresAt) {
+		l.order.Remove(elem)
+		delete(l.items, key)
+		return nil, nil
+	}
+
+	l.order.MoveToFront(elem)
+	entry := item.entry
+	return &entry, nil
+}
+
+func (l *LRU) Set(key string, entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.items[key]; ok {
+		elem.Value.(*lruItem).entry = entry
+		l.order.MoveToFront(elem)
+		return nil
+	}
+
+	l.items[key] = l.order.PushFront(&lruItem{key: key, entry: entry})
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+// Len returns the number of entries held, including expired ones not
+// evicted yet.
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
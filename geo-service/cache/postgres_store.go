@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"time"
+
+	"igaku/geo-service/models"
+)
+
+// PostgresStore keeps cache entries in PostgreSQL, so that they survive
+// restarts and are shared by the replicas of the service.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Get(key string, now time.Time) (*Entry, error) {
+	var record models.CacheEntry
+	err := s.db.
+		Where("key = ? AND expires_at > ?", key, now).
+		First(&record).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{
+		Value: record.Value,
+		Negative: record.Negative,
+		ExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+func (s *PostgresStore) Set(key string, entry Entry) error {
+	record := models.CacheEntry{
+		Key: key,
+		Value: entry.Value,
+		Negative: entry.Negative,
+		ExpiresAt: entry.ExpiresAt,
+	}
+	return s.db.
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&record).
+		Error
+}
+
+// DeleteExpired removes the entries expired by the given time, returning
+// how many there were.
+func (s *PostgresStore) DeleteExpired(now time.Time) (int64, error) {
+	res := s.db.Where("expires_at <= ?", now).Delete(&models.CacheEntry{})
+	return res.RowsAffected, res.Error
+}
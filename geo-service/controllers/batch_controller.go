@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"igaku/geo-service/dtos"
+	"igaku/geo-service/middleware"
+	"igaku/geo-service/services"
+	commonsDtos "igaku/commons/dtos"
+	igakuErrors "igaku/geo-service/errors"
+)
+
+// batchMaxFileSize is the size of the largest CSV file accepted, 1 MiB.
+const batchMaxFileSize = 1 << 20
+
+type BatchController struct {
+	service services.BatchService
+}
+
+func NewBatchController(service services.BatchService) *BatchController {
+	return &BatchController{
+		service: service,
+	}
+}
+
+// Submit queues a batch geocoding job.
+// @Summary	Submit a batch geocoding job
+// @Description	Queues a job geocoding up to 1000 addresses in the background, at the pace allowed by the geocoding providers. The addresses are given either as a JSON list or as an uploaded CSV file with a header, up to 1 MiB. The addresses of CSV files are taken from the 'address' column, or else assembled from the columns 'house_number', 'street', 'city', 'postcode', 'county', 'country' and 'country_code'. The progress of the job is reported at /geo/batch/{id}.
+// @Tags	Batch Geocoding
+// @Accept	json
+// @Accept	mpfd
+// @Produce	json
+// @Param	batch body dtos.BatchRequest false "Addresses, unless a file is uploaded"
+// @Param	file formData file false "CSV file with the addresses"
+// @Success	202 {object} models.BatchJob "Accepted"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - No addresses, too many or invalid CSV file"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Security	BearerAuth
+// @Router	/geo/batch [post]
+func (ctrl *BatchController) Submit(c *gin.Context) {
+	queries, ok := readBatch(c)
+	if !ok {
+		return
+	}
+
+	job, err := ctrl.service.Submit(c.GetString("id"), queries)
+	if err != nil {
+		handleBatchError(c, err, "Failed to submit the batch job")
+		return
+	}
+
+	c.Header("Location", "/geo/batch/"+job.ID.String())
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Get reports the progress of a batch geocoding job.
+// @Summary	Get a batch geocoding job
+// @Description	Reports the status and the progress of a batch geocoding job of the current user.
+// @Tags	Batch Geocoding
+// @Produce	json
+// @Param	id path string true "Job ID (UUIDv4 format)"
+// @Success	200 {object} models.BatchJob "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - Invalid ID"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Batch job not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Security	BearerAuth
+// @Router	/geo/batch/{id} [get]
+func (ctrl *BatchController) Get(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := ctrl.service.Get(c.GetString("id"), id)
+	if err != nil {
+		handleBatchError(c, err, "Failed to retrieve the batch job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// Results returns the results of a batch geocoding job.
+// @Summary	Get the results of a batch geocoding job
+// @Description	Returns the addresses of a batch geocoding job of the current user with the best match of each, in order. Rows not processed yet are pending. The confidence of a match is the share of the words of the address found in it; ambiguous addresses match places far apart from each other, so the best match may be the wrong one.
+// @Tags	Batch Geocoding
+// @Produce	json
+// @Param	id path string true "Job ID (UUIDv4 format)"
+// @Success	200 {object} []models.BatchRow "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - Invalid ID"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Batch job not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Security	BearerAuth
+// @Router	/geo/batch/{id}/results [get]
+func (ctrl *BatchController) Results(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	rows, err := ctrl.service.Results(c.GetString("id"), id)
+	if err != nil {
+		handleBatchError(c, err, "Failed to retrieve the batch results")
+		return
+	}
+
+	c.JSON(http.StatusOK, rows)
+}
+
+func (ctrl *BatchController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/geo/batch")
+	routes.Use(middleware.Authenticate())
+	{
+		routes.POST("", ctrl.Submit)
+		routes.GET("/:id", ctrl.Get)
+		routes.GET("/:id/results", ctrl.Results)
+	}
+}
+
+// readBatch reads the addresses from an uploaded CSV file or from a JSON
+// list. On failure it writes an error response and returns false.
+func readBatch(c *gin.Context) ([]dtos.BatchQuery, bool) {
+	if c.ContentType() != gin.MIMEMultipartPOSTForm {
+		var req dtos.BatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: "Invalid request payload",
+			})
+			return nil, false
+		}
+
+		queries := make([]dtos.BatchQuery, len(req.Addresses))
+		for i, address := range req.Addresses {
+			queries[i].Address = address
+		}
+		return queries, true
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "A CSV file is required",
+		})
+		return nil, false
+	}
+	if header.Size > batchMaxFileSize {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "The CSV file must not be larger than 1 MiB",
+		})
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: "Failed to read the CSV file",
+		})
+		return nil, false
+	}
+	defer file.Close()
+
+	queries, err := services.ParseBatchCSV(file)
+	if err != nil {
+		handleBatchError(c, err, "Failed to read the CSV file")
+		return nil, false
+	}
+	return queries, true
+}
+
+// parseJobID parses the id path parameter. On failure it writes an error
+// response and returns false.
+func parseJobID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func handleBatchError(c *gin.Context, err error, fallback string) {
+	var invalidErr *igakuErrors.InvalidBatchError
+	switch {
+	case errors.As(err, &invalidErr):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.BatchJobNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"igaku/geo-service/cache"
+)
+
+type CacheController struct {
+	cache *cache.Cache
+}
+
+func NewCacheController(cache *cache.Cache) *CacheController {
+	return &CacheController{cache: cache}
+}
+
+// Metrics returns the hits and misses of the geocoding cache.
+// @Summary	Get cache metrics
+// @Description	Returns the number of hits, in memory and in the persistent cache, hits on cached lack of results, and misses of each cached operation since the service started.
+// @Tags	Geolocation
+// @Produce	json
+// @Success	200 {object} dtos.CacheMetrics "Success"
+// @Router	/geo/cache/metrics [get]
+func (ctrl *CacheController) Metrics(c *gin.Context) {
+	c.JSON(http.StatusOK, ctrl.cache.Metrics())
+}
+
+func (ctrl *CacheController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/geo/cache")
+	{
+		routes.GET("/metrics", ctrl.Metrics)
+	}
+}
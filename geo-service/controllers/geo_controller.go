@@ -2,10 +2,13 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"igaku/geo-service/middleware"
 	"igaku/geo-service/services"
 	commonsDtos "igaku/commons/dtos"
 	igakuErrors "igaku/geo-service/errors"
@@ -31,34 +34,244 @@ func NewGeoController(service services.GeoService) *GeoController {
 // @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request"
 // @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
 // @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
 // @Router	/geo/search/{address} [get]
 func (ctrl *GeoController) Search(c *gin.Context) {
 	address := c.Param("address")
 	locations, err := ctrl.service.Search(address)
 
 	if err != nil {
-		if errors.Is(err, &igakuErrors.InvalidAddressError{}) {
+		handleGeoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// SearchStructured returns a list of geographical locations matching the given address components.
+// @Summary	Lookup a location from address components
+// @Description	Performs geocoding of an address given by its components, e.g. the street and the city, instead of a single textual description. At least one component is required.
+// @Tags	Geolocation
+// @Produce	json
+// @Param	house_number query string false "House number"
+// @Param	street query string false "Street"
+// @Param	city query string false "City, town or village"
+// @Param	postcode query string false "Postcode"
+// @Param	county query string false "County"
+// @Param	country query string false "Country"
+// @Param	country_code query string false "ISO 3166-1 alpha-2 country code"
+// @Success	200 {object} []commonsDtos.Location "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Router	/geo/search [get]
+func (ctrl *GeoController) SearchStructured(c *gin.Context) {
+	var address commonsDtos.Address
+	if err := c.ShouldBindQuery(&address); err != nil || address.IsEmpty() {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "At least one address component is required",
+		})
+		return
+	}
+
+	locations, err := ctrl.service.SearchStructured(address)
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// Autocomplete returns completions of an address being typed.
+// @Summary	Autocomplete an address
+// @Description	Returns the places matching the beginning of an address, for suggesting completions as the user types. Prefixes shorter than 3 characters have no completions and are answered without asking the geocoding provider, so clients may send them freely; they should still wait for a pause in typing before sending a request. Completions are cached and may be cached by the client as well.
+// @Tags	Geolocation
+// @Produce	json
+// @Param	q query string true "Beginning of the address"
+// @Param	limit query int false "Maximum number of completions (default 5, max 10)"
+// @Success	200 {object} []commonsDtos.Location "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Security	BearerAuth
+// @Router	/geo/autocomplete [get]
+func (ctrl *GeoController) Autocomplete(c *gin.Context) {
+	limit := 0
+	if str := c.Query("limit"); str != "" {
+		var err error
+		limit, err = strconv.Atoi(str)
+		if err != nil || limit <= 0 {
 			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
-				Message: err.Error(),
-			})
-		} else if errors.Is(err, &igakuErrors.TimeoutError{}) {
-			c.JSON(http.StatusRequestTimeout, commonsDtos.ErrorResponse{
-				Message: err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
-				Message: "Failed to perform a lookup",
+				Message: "Parameter 'limit' must be a positive number",
 			})
+			return
 		}
+	}
+
+	locations, err := ctrl.service.Autocomplete(c.Query("q"), limit)
+	if err != nil {
+		handleGeoError(c, err)
 		return
 	}
 
+	c.Header("Cache-Control", "private, max-age=300")
 	c.JSON(http.StatusOK, locations)
 }
 
+// Reverse returns the location at the given coordinates.
+// @Summary	Lookup a location from coordinates
+// @Description	Performs reverse geocoding, i.e. finds the place at the given coordinates.
+// @Tags	Geolocation
+// @Produce	json
+// @Param	lat query number true "Latitude, between -90 and 90"
+// @Param	lon query number true "Longitude, between -180 and 180"
+// @Success	200 {object} commonsDtos.Location "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - Invalid coordinates"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - There is nothing at the coordinates"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Security	BearerAuth
+// @Router	/geo/reverse [get]
+func (ctrl *GeoController) Reverse(c *gin.Context) {
+	lat, lon, ok := parseCoordinates(c)
+	if !ok {
+		return
+	}
+
+	location, err := ctrl.service.Reverse(lat, lon)
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+	if location == nil {
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: "Unable to geocode",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// Lookup returns the location with the given OpenStreetMap ID.
+// @Summary	Lookup a location by ID
+// @Description	Returns the place with the given OpenStreetMap ID, prefixed by the letter of its type, e.g. W117853077 for a way. IDs without a type are looked up as nodes, ways and relations in turn.
+// @Tags	Geolocation
+// @Produce	json
+// @Param	id path string true "OpenStreetMap ID, optionally prefixed by its type (N, W or R)"
+// @Success	200 {object} commonsDtos.Location "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - Invalid ID"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - There is no object with such ID"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Security	BearerAuth
+// @Router	/geo/lookup/{id} [get]
+func (ctrl *GeoController) Lookup(c *gin.Context) {
+	osmType, id, ok := parseOsmID(c)
+	if !ok {
+		return
+	}
+
+	location, err := ctrl.service.Lookup(osmType, id)
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+	if location == nil {
+		respondNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
 func (ctrl *GeoController) RegisterRoutes(router *gin.Engine) {
 	routes := router.Group("/geo")
 	{
+		routes.GET("/search", ctrl.SearchStructured)
 		routes.GET("/search/:address", ctrl.Search)
 	}
+
+	authenticated := router.Group("/geo")
+	authenticated.Use(middleware.Authenticate())
+	{
+		authenticated.GET("/autocomplete", ctrl.Autocomplete)
+		authenticated.GET("/reverse", ctrl.Reverse)
+		authenticated.GET("/lookup/:id", ctrl.Lookup)
+	}
+}
+
+// parseCoordinates parses the lat and lon query parameters. On failure it
+// writes an error response and returns false.
+func parseCoordinates(c *gin.Context) (float64, float64, bool) {
+	return parsePoint(c, "lat", "lon")
+}
+
+// parsePoint parses the coordinates given by the named query parameters.
+// On failure it writes an error response and returns false.
+func parsePoint(c *gin.Context, latParam, lonParam string) (float64, float64, bool) {
+	lat, latErr := commonsDtos.ParseCoordinate(c.Query(latParam))
+	lon, lonErr := commonsDtos.ParseCoordinate(c.Query(lonParam))
+	if latErr != nil || lonErr != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: fmt.Sprintf(
+				"Parameters '%s' and '%s' must be numbers",
+				latParam, lonParam,
+			),
+		})
+		return 0, 0, false
+	}
+
+	return float64(lat), float64(lon), true
+}
+
+// parseOsmID parses the id path parameter, an OSM ID optionally prefixed
+// by its type. On failure it writes an error response and returns false.
+func parseOsmID(c *gin.Context) (commonsDtos.OsmType, int64, bool) {
+	osmType, id, err := commonsDtos.ParseOsmID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid ID",
+		})
+		return "", 0, false
+	}
+
+	return osmType, id, true
+}
+
+func respondNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+		Message: "There is no object with such ID",
+	})
+}
+
+func handleGeoError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidAddressError{}),
+		errors.Is(err, &igakuErrors.InvalidCoordinatesError{}),
+		errors.Is(err, &igakuErrors.UnsupportedTravelModeError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.TimeoutError{}):
+		c.JSON(http.StatusRequestTimeout, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.RateLimitedError{}):
+		c.JSON(http.StatusServiceUnavailable, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: "Failed to perform a lookup",
+		})
+	}
 }
@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"igaku/geo-service/dtos"
+	"igaku/geo-service/middleware"
+	"igaku/geo-service/services"
+	commonsDtos "igaku/commons/dtos"
+)
+
+// geoJSONContentType is the media type of GeoJSON (RFC 7946).
+const geoJSONContentType = "application/geo+json"
+
+// GeoV2Controller serves the second version of the geo API, which returns
+// numeric coordinates and can return GeoJSON.
+type GeoV2Controller struct {
+	service services.GeoService
+}
+
+func NewGeoV2Controller(service services.GeoService) *GeoV2Controller {
+	return &GeoV2Controller{service: service}
+}
+
+// Search returns a list of geographical locations corresponding to the given address.
+// @Summary	Lookup a location from address
+// @Description	Performs geocoding of the given textual description or address. Coordinates are numbers, unlike in the first version of the API. With `format=geojson` the locations are returned as a GeoJSON feature collection.
+// @Tags	Geolocation v2
+// @Produce	json
+// @Produce	application/geo+json
+// @Param	address path string true "Textual description or address"
+// @Param	format query string false "Response format" Enums(json, geojson)
+// @Success	200 {object} []dtos.LocationV2 "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Router	/geo/v2/search/{address} [get]
+func (ctrl *GeoV2Controller) Search(c *gin.Context) {
+	geoJSON, ok := parseFormat(c)
+	if !ok {
+		return
+	}
+
+	locations, err := ctrl.service.Search(c.Param("address"))
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+
+	respondWithLocations(c, locations, geoJSON)
+}
+
+// SearchStructured returns a list of geographical locations matching the given address components.
+// @Summary	Lookup a location from address components
+// @Description	Performs geocoding of an address given by its components. At least one component is required. Coordinates are numbers, unlike in the first version of the API. With `format=geojson` the locations are returned as a GeoJSON feature collection.
+// @Tags	Geolocation v2
+// @Produce	json
+// @Produce	application/geo+json
+// @Param	house_number query string false "House number"
+// @Param	street query string false "Street"
+// @Param	city query string false "City, town or village"
+// @Param	postcode query string false "Postcode"
+// @Param	county query string false "County"
+// @Param	country query string false "Country"
+// @Param	country_code query string false "ISO 3166-1 alpha-2 country code"
+// @Param	format query string false "Response format" Enums(json, geojson)
+// @Success	200 {object} []dtos.LocationV2 "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Router	/geo/v2/search [get]
+func (ctrl *GeoV2Controller) SearchStructured(c *gin.Context) {
+	geoJSON, ok := parseFormat(c)
+	if !ok {
+		return
+	}
+
+	var address commonsDtos.Address
+	if err := c.ShouldBindQuery(&address); err != nil || address.IsEmpty() {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "At least one address component is required",
+		})
+		return
+	}
+
+	locations, err := ctrl.service.SearchStructured(address)
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+
+	respondWithLocations(c, locations, geoJSON)
+}
+
+// Reverse returns the location at the given coordinates.
+// @Summary	Lookup a location from coordinates
+// @Description	Performs reverse geocoding, i.e. finds the place at the given coordinates. With `format=geojson` the location is returned as a GeoJSON feature.
+// @Tags	Geolocation v2
+// @Produce	json
+// @Produce	application/geo+json
+// @Param	lat query number true "Latitude, between -90 and 90"
+// @Param	lon query number true "Longitude, between -180 and 180"
+// @Param	format query string false "Response format" Enums(json, geojson)
+// @Success	200 {object} dtos.LocationV2 "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - Invalid coordinates"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - There is nothing at the coordinates"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Security	BearerAuth
+// @Router	/geo/v2/reverse [get]
+func (ctrl *GeoV2Controller) Reverse(c *gin.Context) {
+	geoJSON, ok := parseFormat(c)
+	if !ok {
+		return
+	}
+
+	lat, lon, ok := parseCoordinates(c)
+	if !ok {
+		return
+	}
+
+	location, err := ctrl.service.Reverse(lat, lon)
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+	if location == nil {
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: "Unable to geocode",
+		})
+		return
+	}
+
+	respondWithLocation(c, location, geoJSON)
+}
+
+// Lookup returns the location with the given OpenStreetMap ID.
+// @Summary	Lookup a location by ID
+// @Description	Returns the place with the given OpenStreetMap ID, prefixed by the letter of its type, e.g. W117853077 for a way. IDs without a type are looked up as nodes, ways and relations in turn. With `format=geojson` the location is returned as a GeoJSON feature.
+// @Tags	Geolocation v2
+// @Produce	json
+// @Produce	application/geo+json
+// @Param	id path string true "OpenStreetMap ID, optionally prefixed by its type (N, W or R)"
+// @Param	format query string false "Response format" Enums(json, geojson)
+// @Success	200 {object} dtos.LocationV2 "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - Invalid ID"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - There is no object with such ID"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the geocoding provider"
+// @Security	BearerAuth
+// @Router	/geo/v2/lookup/{id} [get]
+func (ctrl *GeoV2Controller) Lookup(c *gin.Context) {
+	geoJSON, ok := parseFormat(c)
+	if !ok {
+		return
+	}
+
+	osmType, id, ok := parseOsmID(c)
+	if !ok {
+		return
+	}
+
+	location, err := ctrl.service.Lookup(osmType, id)
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+	if location == nil {
+		respondNotFound(c)
+		return
+	}
+
+	respondWithLocation(c, location, geoJSON)
+}
+
+func (ctrl *GeoV2Controller) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/geo/v2")
+	{
+		routes.GET("/search", ctrl.SearchStructured)
+		routes.GET("/search/:address", ctrl.Search)
+		routes.GET("/reverse", middleware.Authenticate(), ctrl.Reverse)
+		routes.GET("/lookup/:id", middleware.Authenticate(), ctrl.Lookup)
+	}
+}
+
+// parseFormat reports whether GeoJSON was asked for, responding with an
+// error for unknown formats.
+func parseFormat(c *gin.Context) (bool, bool) {
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		return false, true
+	case "geojson":
+		return true, true
+	default:
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Parameter 'format' must be 'json' or 'geojson'",
+		})
+		return false, false
+	}
+}
+
+// respondWithLocations responds with the locations converted to the second
+// version of the API. Locations with invalid coordinates, which no
+// provider is expected to return, are left out.
+func respondWithLocations(
+	c *gin.Context, locations []commonsDtos.Location, geoJSON bool,
+) {
+	converted := make([]dtos.LocationV2, 0, len(locations))
+	for _, location := range locations {
+		v2, err := dtos.NewLocationV2(location)
+		if err != nil {
+			log.Printf(
+				"Skipping location %d with invalid coordinates: %v",
+				location.ID, err,
+			)
+			continue
+		}
+		converted = append(converted, *v2)
+	}
+
+	if geoJSON {
+		c.Header("Content-Type", geoJSONContentType)
+		c.JSON(http.StatusOK, dtos.NewGeoJSONFeatureCollection(converted))
+		return
+	}
+	c.JSON(http.StatusOK, converted)
+}
+
+func respondWithLocation(
+	c *gin.Context, location *commonsDtos.Location, geoJSON bool,
+) {
+	v2, err := dtos.NewLocationV2(*location)
+	if err != nil {
+		log.Printf(
+			"Location %d has invalid coordinates: %v", location.ID, err,
+		)
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: "Failed to perform a lookup",
+		})
+		return
+	}
+
+	if geoJSON {
+		c.Header("Content-Type", geoJSONContentType)
+		c.JSON(http.StatusOK, v2.Feature())
+		return
+	}
+	c.JSON(http.StatusOK, v2)
+}
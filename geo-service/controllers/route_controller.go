@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"igaku/geo-service/middleware"
+	"igaku/geo-service/services"
+	commonsDtos "igaku/commons/dtos"
+)
+
+type RouteController struct {
+	service services.RouteService
+}
+
+func NewRouteController(service services.RouteService) *RouteController {
+	return &RouteController{
+		service: service,
+	}
+}
+
+// Route estimates the distance and the travel time between two points.
+// @Summary	Estimate a route
+// @Description	Estimates the length and the duration of the fastest route between two points by each of the given modes of travel, or by all modes offered by the routing engine when none is given. Modes by which the points are not connected, e.g. driving to an island, are left out.
+// @Tags	Routing
+// @Produce	json
+// @Param	from_lat query number true "Latitude of the start, between -90 and 90"
+// @Param	from_lon query number true "Longitude of the start, between -180 and 180"
+// @Param	to_lat query number true "Latitude of the destination, between -90 and 90"
+// @Param	to_lon query number true "Longitude of the destination, between -180 and 180"
+// @Param	mode query []string false "Modes of travel" collectionFormat(multi) Enums(driving, walking, cycling)
+// @Success	200 {object} []commonsDtos.RouteEstimate "Success"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Invalid Request - Invalid coordinates or unsupported mode"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	408 {object} commonsDtos.ErrorResponse "Request Timeout"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Too many requests to the routing engine"
+// @Security	BearerAuth
+// @Router	/geo/route [get]
+func (ctrl *RouteController) Route(c *gin.Context) {
+	fromLat, fromLon, ok := parsePoint(c, "from_lat", "from_lon")
+	if !ok {
+		return
+	}
+	toLat, toLon, ok := parsePoint(c, "to_lat", "to_lon")
+	if !ok {
+		return
+	}
+
+	req := commonsDtos.RouteRequest{
+		FromLat: commonsDtos.Coordinate(fromLat),
+		FromLon: commonsDtos.Coordinate(fromLon),
+		ToLat: commonsDtos.Coordinate(toLat),
+		ToLon: commonsDtos.Coordinate(toLon),
+	}
+	for _, str := range c.QueryArray("mode") {
+		mode, err := commonsDtos.ParseTravelMode(str)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: "Parameter 'mode' must be one of driving, walking or cycling",
+			})
+			return
+		}
+		req.Modes = append(req.Modes, mode)
+	}
+
+	estimates, err := ctrl.service.Route(req)
+	if err != nil {
+		handleGeoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, estimates)
+}
+
+func (ctrl *RouteController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/geo")
+	routes.Use(middleware.Authenticate())
+	{
+		routes.GET("/route", ctrl.Route)
+	}
+}
@@ -15,34 +15,978 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/geo/autocomplete": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the places matching the beginning of an address, for suggesting completions as the user types. Prefixes shorter than 3 characters have no completions and are answered without asking the geocoding provider, so clients may send them freely; they should still wait for a pause in typing before sending a request. Completions are cached and may be cached by the client as well.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Geolocation"
+                ],
+                "summary": "Autocomplete an address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Beginning of the address",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of completions (default 5, max 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.Location"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues a job geocoding up to 1000 addresses in the background, at the pace allowed by the geocoding providers. The addresses are given either as a JSON list or as an uploaded CSV file with a header, up to 1 MiB. The addresses of CSV files are taken from the 'address' column, or else assembled from the columns 'house_number', 'street', 'city', 'postcode', 'county', 'country' and 'country_code'. The progress of the job is reported at /geo/batch/{id}.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batch Geocoding"
+                ],
+                "summary": "Submit a batch geocoding job",
+                "parameters": [
+                    {
+                        "description": "Addresses, unless a file is uploaded",
+                        "name": "batch",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BatchRequest"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file with the addresses",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - No addresses, too many or invalid CSV file",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/batch/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reports the status and the progress of a batch geocoding job of the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batch Geocoding"
+                ],
+                "summary": "Get a batch geocoding job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Batch job not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/batch/{id}/results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the addresses of a batch geocoding job of the current user with the best match of each, in order. Rows not processed yet are pending. The confidence of a match is the share of the words of the address found in it; ambiguous addresses match places far apart from each other, so the best match may be the wrong one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batch Geocoding"
+                ],
+                "summary": "Get the results of a batch geocoding job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BatchRow"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Batch job not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/cache/metrics": {
+            "get": {
+                "description": "Returns the number of hits, in memory and in the persistent cache, hits on cached lack of results, and misses of each cached operation since the service started.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Geolocation"
+                ],
+                "summary": "Get cache metrics",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CacheMetrics"
+                        }
+                    }
+                }
+            }
+        },
         "/geo/health": {
             "get": {
-                "description": "Returns an OK message",
+                "description": "Returns an OK message",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Check health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/lookup/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the place with the given OpenStreetMap ID, prefixed by the letter of its type, e.g. W117853077 for a way. IDs without a type are looked up as nodes, ways and relations in turn.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Geolocation"
+                ],
+                "summary": "Lookup a location by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OpenStreetMap ID, optionally prefixed by its type (N, W or R)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - There is no object with such ID",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/reverse": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Performs reverse geocoding, i.e. finds the place at the given coordinates.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Geolocation"
+                ],
+                "summary": "Lookup a location from coordinates",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude, between -90 and 90",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, between -180 and 180",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - Invalid coordinates",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - There is nothing at the coordinates",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/route": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Estimates the length and the duration of the fastest route between two points by each of the given modes of travel, or by all modes offered by the routing engine when none is given. Modes by which the points are not connected, e.g. driving to an island, are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Routing"
+                ],
+                "summary": "Estimate a route",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude of the start, between -90 and 90",
+                        "name": "from_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the start, between -180 and 180",
+                        "name": "from_lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the destination, between -90 and 90",
+                        "name": "to_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the destination, between -180 and 180",
+                        "name": "to_lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "driving",
+                                "walking",
+                                "cycling"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Modes of travel",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.RouteEstimate"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - Invalid coordinates or unsupported mode",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the routing engine",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/search": {
+            "get": {
+                "description": "Performs geocoding of an address given by its components, e.g. the street and the city, instead of a single textual description. At least one component is required.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Geolocation"
+                ],
+                "summary": "Lookup a location from address components",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "House number",
+                        "name": "house_number",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Street",
+                        "name": "street",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "City, town or village",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Postcode",
+                        "name": "postcode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "County",
+                        "name": "county",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 country code",
+                        "name": "country_code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.Location"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/search/{address}": {
+            "get": {
+                "description": "Performs geocoding, i.e. conversion of the given textual description or addres into geographic coordinates.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Geolocation"
+                ],
+                "summary": "Lookup a location from address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Textual description or address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.Location"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/v2/lookup/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the place with the given OpenStreetMap ID, prefixed by the letter of its type, e.g. W117853077 for a way. IDs without a type are looked up as nodes, ways and relations in turn. With ` + "`" + `format=geojson` + "`" + ` the location is returned as a GeoJSON feature.",
+                "produces": [
+                    "application/json",
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "Geolocation v2"
+                ],
+                "summary": "Lookup a location by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "OpenStreetMap ID, optionally prefixed by its type (N, W or R)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "geojson"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.LocationV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - There is no object with such ID",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/v2/reverse": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Performs reverse geocoding, i.e. finds the place at the given coordinates. With ` + "`" + `format=geojson` + "`" + ` the location is returned as a GeoJSON feature.",
                 "produces": [
-                    "text/plain"
+                    "application/json",
+                    "application/geo+json"
                 ],
                 "tags": [
-                    "Health"
+                    "Geolocation v2"
+                ],
+                "summary": "Lookup a location from coordinates",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude, between -90 and 90",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude, between -180 and 180",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "geojson"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Check health",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Success",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dtos.LocationV2"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request - Invalid coordinates",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - There is nothing at the coordinates",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/geo/search/{address}": {
+        "/geo/v2/search": {
             "get": {
-                "description": "Performs geocoding, i.e. conversion of the given textual description or addres into geographic coordinates.",
+                "description": "Performs geocoding of an address given by its components. At least one component is required. Coordinates are numbers, unlike in the first version of the API. With ` + "`" + `format=geojson` + "`" + ` the locations are returned as a GeoJSON feature collection.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/geo+json"
                 ],
                 "tags": [
-                    "Geolocation"
+                    "Geolocation v2"
+                ],
+                "summary": "Lookup a location from address components",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "House number",
+                        "name": "house_number",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Street",
+                        "name": "street",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "City, town or village",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Postcode",
+                        "name": "postcode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "County",
+                        "name": "county",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 country code",
+                        "name": "country_code",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "geojson"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.LocationV2"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/v2/search/{address}": {
+            "get": {
+                "description": "Performs geocoding of the given textual description or address. Coordinates are numbers, unlike in the first version of the API. With ` + "`" + `format=geojson` + "`" + ` the locations are returned as a GeoJSON feature collection.",
+                "produces": [
+                    "application/json",
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "Geolocation v2"
                 ],
                 "summary": "Lookup a location from address",
                 "parameters": [
@@ -52,6 +996,16 @@ const docTemplate = `{
                         "name": "address",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "geojson"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -60,7 +1014,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.Location"
+                                "$ref": "#/definitions/dtos.LocationV2"
                             }
                         }
                     },
@@ -81,12 +1035,110 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dtos.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Too many requests to the geocoding provider",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "dtos.Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Boston"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "United States"
+                },
+                "country_code": {
+                    "type": "string",
+                    "example": "us"
+                },
+                "county": {
+                    "type": "string",
+                    "example": "Suffolk County"
+                },
+                "house_number": {
+                    "type": "string",
+                    "example": "55"
+                },
+                "postcode": {
+                    "type": "string",
+                    "example": "02114"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Fruit Street"
+                }
+            }
+        },
+        "dtos.BatchRequest": {
+            "type": "object",
+            "required": [
+                "addresses"
+            ],
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "55 Fruit Street",
+                        " Boston",
+                        "330 Brookline Avenue",
+                        " Boston"
+                    ]
+                }
+            }
+        },
+        "dtos.CacheMetrics": {
+            "type": "object",
+            "properties": {
+                "operations": {
+                    "description": "Operations are keyed by the cached operation: search, reverse or\nlookup.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dtos.CacheOperationMetrics"
+                    }
+                },
+                "persistent": {
+                    "description": "Persistent tells whether the PostgreSQL cache is enabled.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dtos.CacheOperationMetrics": {
+            "type": "object",
+            "properties": {
+                "memory_hits": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "misses": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "negative_hits": {
+                    "description": "NegativeHits are the hits, of either kind, on cached lack of\nresults.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "persistent_hits": {
+                    "type": "integer",
+                    "example": 15
+                }
+            }
+        },
         "dtos.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -105,6 +1157,9 @@ const docTemplate = `{
                 "osm_id"
             ],
             "properties": {
+                "address": {
+                    "$ref": "#/definitions/dtos.Address"
+                },
                 "display_name": {
                     "type": "string",
                     "example": "135, Pilkington Avenue, Maney, Sutton Coldfield, Birmingham, West Midlands, England, B72 1LH, United Kingdom"
@@ -120,8 +1175,247 @@ const docTemplate = `{
                 "osm_id": {
                     "type": "integer",
                     "example": 90394480
+                },
+                "osm_type": {
+                    "enum": [
+                        "N",
+                        "W",
+                        "R"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dtos.OsmType"
+                        }
+                    ],
+                    "example": "W"
+                }
+            }
+        },
+        "dtos.LocationV2": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/dtos.Address"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "Massachusetts General Hospital, 55, Fruit Street, West End, Boston, Suffolk County, Massachusetts, 02114, United States"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 42.3628605
+                },
+                "lon": {
+                    "type": "number",
+                    "example": -71.068753
+                },
+                "osm_id": {
+                    "type": "integer",
+                    "example": 117853077
+                },
+                "osm_type": {
+                    "enum": [
+                        "N",
+                        "W",
+                        "R"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dtos.OsmType"
+                        }
+                    ],
+                    "example": "W"
+                }
+            }
+        },
+        "dtos.OsmType": {
+            "type": "string",
+            "enum": [
+                "N",
+                "W",
+                "R"
+            ],
+            "x-enum-varnames": [
+                "OsmNode",
+                "OsmWay",
+                "OsmRelation"
+            ]
+        },
+        "dtos.RouteEstimate": {
+            "type": "object",
+            "properties": {
+                "distance_meters": {
+                    "type": "number",
+                    "example": 5364.2
+                },
+                "duration_seconds": {
+                    "type": "number",
+                    "example": 712.5
+                },
+                "mode": {
+                    "enum": [
+                        "driving",
+                        "walking",
+                        "cycling"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dtos.TravelMode"
+                        }
+                    ],
+                    "example": "driving"
+                }
+            }
+        },
+        "dtos.TravelMode": {
+            "type": "string",
+            "enum": [
+                "driving",
+                "walking",
+                "cycling"
+            ],
+            "x-enum-varnames": [
+                "Driving",
+                "Walking",
+                "Cycling"
+            ]
+        },
+        "models.BatchJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-02T09:30:00Z"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2025-06-02T09:35:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9b2f4a8e-6c1d-4e3a-8f5b-2d7c9e0a1b3c"
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "progress": {
+                    "description": "Progress is the share of processed rows, between 0 and 1.",
+                    "type": "number",
+                    "example": 0.48
+                },
+                "status": {
+                    "enum": [
+                        "queued",
+                        "running",
+                        "completed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BatchJobStatus"
+                        }
+                    ],
+                    "example": "running"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 250
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the last row was processed. Running jobs which\nhave not been updated for a while were abandoned by a stopped\nreplica and are taken over by another one.",
+                    "type": "string",
+                    "example": "2025-06-02T09:32:00Z"
+                }
+            }
+        },
+        "models.BatchJobStatus": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "completed"
+            ],
+            "x-enum-varnames": [
+                "BatchQueued",
+                "BatchRunning",
+                "BatchCompleted"
+            ]
+        },
+        "models.BatchRow": {
+            "type": "object",
+            "properties": {
+                "ambiguous": {
+                    "description": "Ambiguous tells that places far apart from each other match the\naddress, so the best match may be the wrong one.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "candidates": {
+                    "description": "Candidates is the number of places found.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "confidence": {
+                    "description": "Confidence is the share of the words of the address found in the\nbest match, between 0 and 1.",
+                    "type": "number",
+                    "example": 0.8
+                },
+                "error": {
+                    "type": "string"
+                },
+                "location": {
+                    "description": "Location is the best match.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dtos.Location"
+                        }
+                    ]
+                },
+                "query": {
+                    "type": "string",
+                    "example": "55 Fruit Street, Boston"
+                },
+                "row": {
+                    "description": "Row is the position of the address in the job, from 1.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "matched",
+                        "not_found",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BatchRowStatus"
+                        }
+                    ],
+                    "example": "matched"
                 }
             }
+        },
+        "models.BatchRowStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "matched",
+                "not_found",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "RowPending",
+                "RowMatched",
+                "RowNotFound",
+                "RowFailed"
+            ]
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,16 @@
+package dtos
+
+import (
+	commonDtos "igaku/commons/dtos"
+)
+
+type BatchRequest struct {
+	Addresses	[]string	`json:"addresses" binding:"required" example:"55 Fruit Street, Boston,330 Brookline Avenue, Boston"`
+}
+
+// BatchQuery is an address of a batch job, given either as free-form text
+// or by its components.
+type BatchQuery struct {
+	Address		string
+	Components	commonDtos.Address
+}
@@ -0,0 +1,18 @@
+package dtos
+
+type CacheOperationMetrics struct {
+	MemoryHits	int64	`json:"memory_hits" example:"120"`
+	PersistentHits	int64	`json:"persistent_hits" example:"15"`
+	// NegativeHits are the hits, of either kind, on cached lack of
+	// results.
+	NegativeHits	int64	`json:"negative_hits" example:"3"`
+	Misses		int64	`json:"misses" example:"42"`
+}
+
+type CacheMetrics struct {
+	// Persistent tells whether the PostgreSQL cache is enabled.
+	Persistent	bool					`json:"persistent" example:"true"`
+	// Operations are keyed by the cached operation: search, reverse or
+	// lookup.
+	Operations	map[string]CacheOperationMetrics	`json:"operations"`
+}
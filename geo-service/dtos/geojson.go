@@ -0,0 +1,44 @@
+package dtos
+
+import "igaku/commons/dtos"
+
+// GeoJSONFeatureCollection is a list of locations in the GeoJSON format
+// (RFC 7946).
+type GeoJSONFeatureCollection struct {
+	Type		string		`json:"type" example:"FeatureCollection"`
+	Features	[]GeoJSONFeature	`json:"features"`
+}
+
+// GeoJSONFeature is a location in the GeoJSON format (RFC 7946).
+type GeoJSONFeature struct {
+	Type		string			`json:"type" example:"Feature"`
+	Geometry	GeoJSONGeometry		`json:"geometry"`
+	Properties	GeoJSONProperties	`json:"properties"`
+}
+
+type GeoJSONGeometry struct {
+	Type		string		`json:"type" example:"Point"`
+	// Coordinates are the longitude and the latitude.
+	Coordinates	[]float64	`json:"coordinates" example:"-71.068753,42.3628605"`
+}
+
+type GeoJSONProperties struct {
+	ID	int64		`json:"osm_id" example:"117853077"`
+	OsmType	dtos.OsmType	`json:"osm_type,omitempty" enums:"N,W,R" example:"W"`
+	Name	string		`json:"display_name" example:"Massachusetts General Hospital, 55, Fruit Street, West End, Boston, Suffolk County, Massachusetts, 02114, United States"`
+	Address	dtos.Address	`json:"address"`
+}
+
+func NewGeoJSONFeatureCollection(
+	locations []LocationV2,
+) GeoJSONFeatureCollection {
+	features := make([]GeoJSONFeature, 0, len(locations))
+	for _, location := range locations {
+		features = append(features, location.Feature())
+	}
+
+	return GeoJSONFeatureCollection{
+		Type: "FeatureCollection",
+		Features: features,
+	}
+}
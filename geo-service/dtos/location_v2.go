@@ -0,0 +1,61 @@
+package dtos
+
+import (
+	"strconv"
+
+	"igaku/commons/dtos"
+)
+
+// LocationV2 is a location with numeric coordinates, as returned by the
+// second version of the API.
+type LocationV2 struct {
+	ID	int64		`json:"osm_id" example:"117853077"`
+	OsmType	dtos.OsmType	`json:"osm_type,omitempty" enums:"N,W,R" example:"W"`
+	Lat	float64		`json:"lat" example:"42.3628605"`
+	Lon	float64		`json:"lon" example:"-71.068753"`
+	Name	string		`json:"display_name" example:"Massachusetts General Hospital, 55, Fruit Street, West End, Boston, Suffolk County, Massachusetts, 02114, United States"`
+	Address	dtos.Address	`json:"address"`
+}
+
+// NewLocationV2 converts a location, failing when its coordinates are not
+// valid numbers within range.
+func NewLocationV2(location dtos.Location) (*LocationV2, error) {
+	lat, err := strconv.ParseFloat(location.Lat, 64)
+	if err != nil {
+		return nil, err
+	}
+	lon, err := strconv.ParseFloat(location.Lon, 64)
+	if err != nil {
+		return nil, err
+	}
+	if !dtos.ValidCoordinates(lat, lon) {
+		return nil, strconv.ErrRange
+	}
+
+	return &LocationV2{
+		ID: location.ID,
+		OsmType: location.OsmType,
+		Lat: dtos.RoundCoordinate(lat),
+		Lon: dtos.RoundCoordinate(lon),
+		Name: location.Name,
+		Address: location.Address,
+	}, nil
+}
+
+// Feature describes the location as a GeoJSON feature.
+func (l LocationV2) Feature() GeoJSONFeature {
+	return GeoJSONFeature{
+		Type: "Feature",
+		Geometry: GeoJSONGeometry{
+			Type: "Point",
+			// GeoJSON puts the longitude first.
+			Coordinates: []float64{l.Lon, l.Lat},
+		},
+		Properties: GeoJSONProperties{
+			ID: l.ID,
+			OsmType: l.OsmType,
+			Name: l.Name,
+			Address: l.Address,
+		},
+	}
+}
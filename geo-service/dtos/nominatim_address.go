@@ -0,0 +1,39 @@
+package dtos
+
+import "igaku/commons/dtos"
+
+// NominatimAddress is the address of a place as returned by Nominatim with
+// `addressdetails`. Which of the fields naming the city is set depends on
+// the size of the settlement.
+type NominatimAddress struct {
+	HouseNumber	string	`json:"house_number"`
+	Road		string	`json:"road"`
+	City		string	`json:"city"`
+	Town		string	`json:"town"`
+	Village		string	`json:"village"`
+	Hamlet		string	`json:"hamlet"`
+	Municipality	string	`json:"municipality"`
+	Postcode	string	`json:"postcode"`
+	County		string	`json:"county"`
+	Country		string	`json:"country"`
+	CountryCode	string	`json:"country_code"`
+}
+
+func (a NominatimAddress) ToAddress() dtos.Address {
+	city := a.City
+	for _, name := range []string{a.Town, a.Village, a.Hamlet, a.Municipality} {
+		if city == "" {
+			city = name
+		}
+	}
+
+	return dtos.Address{
+		HouseNumber: a.HouseNumber,
+		Street: a.Road,
+		City: city,
+		Postcode: a.Postcode,
+		County: a.County,
+		Country: a.Country,
+		CountryCode: a.CountryCode,
+	}
+}
@@ -0,0 +1,23 @@
+package dtos
+
+import "igaku/commons/dtos"
+
+// NominatimLocation is a location as returned by Nominatim, which names the
+// type of the object, e.g. way, and the address components differently.
+type NominatimLocation struct {
+	dtos.Location
+	// Address shadows the address of the location, as Nominatim names
+	// the components differently.
+	Address NominatimAddress `json:"address"`
+}
+
+func (nl NominatimLocation) ToLocation() dtos.Location {
+	return dtos.Location{
+		ID: nl.ID,
+		OsmType: nl.OsmType,
+		Lat: nl.Lat,
+		Lon: nl.Lon,
+		Name: nl.Name,
+		Address: nl.Address.ToAddress(),
+	}
+}
@@ -0,0 +1,7 @@
+package errors
+
+type BatchJobNotFoundError struct {}
+
+func (m *BatchJobNotFoundError) Error() string {
+	return "Batch job not found"
+}
@@ -0,0 +1,9 @@
+package errors
+
+type InvalidBatchError struct {
+	Message string
+}
+
+func (m *InvalidBatchError) Error() string {
+	return m.Message
+}
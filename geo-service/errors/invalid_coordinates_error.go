@@ -0,0 +1,7 @@
+package errors
+
+type InvalidCoordinatesError struct {}
+
+func (m *InvalidCoordinatesError) Error() string {
+	return "Latitude must be between -90 and 90 and longitude between -180 and 180"
+}
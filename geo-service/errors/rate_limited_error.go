@@ -0,0 +1,7 @@
+package errors
+
+type RateLimitedError struct {}
+
+func (m *RateLimitedError) Error() string {
+	return "Too many requests to the geocoding provider"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type UnsupportedOperationError struct {}
+
+func (m *UnsupportedOperationError) Error() string {
+	return "Operation not supported by the geocoding provider"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type UnsupportedTravelModeError struct {}
+
+func (m *UnsupportedTravelModeError) Error() string {
+	return "Travel mode not supported by the routing engine"
+}
@@ -0,0 +1,105 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"igaku/geo-service/errors"
+)
+
+// Limiter paces requests to an external API.
+type Limiter interface {
+	// Wait blocks until a request may be sent. Requests which could not be
+	// sent before the deadline of the context fail right away with
+	// RateLimitedError instead of queuing.
+	Wait(ctx context.Context) error
+}
+
+// reserve takes a token from a bucket refilled with one token every
+// interval, up to burst tokens. When the bucket is empty the token is
+// borrowed, leaving it below zero, so that waiting requests are served in
+// the order they came. It returns the new number of tokens and how long
+// the caller has to wait.
+func reserve(
+	tokens float64, updatedAt, now time.Time,
+	interval time.Duration, burst int,
+) (float64, time.Duration) {
+	if elapsed := now.Sub(updatedAt); elapsed > 0 {
+		tokens += float64(elapsed) / float64(interval)
+	}
+	if tokens > float64(burst) {
+		tokens = float64(burst)
+	}
+
+	tokens--
+	if tokens >= 0 {
+		return tokens, 0
+	}
+	return tokens, time.Duration(-tokens * float64(interval))
+}
+
+// fitsDeadline reports whether waiting for the given time ends before the
+// deadline of the context.
+func fitsDeadline(ctx context.Context, now time.Time, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || !now.Add(wait).After(deadline)
+}
+
+// sleep waits for the given time or until the context is done.
+func sleep(ctx context.Context, wait time.Duration) error {
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type tokenBucket struct {
+	mu		sync.Mutex
+	interval	time.Duration
+	burst		int
+	tokens		float64
+	updatedAt	time.Time
+}
+
+// NewTokenBucket creates a limiter allowing one request every interval,
+// with bursts of up to burst requests, kept in the memory of the process.
+func NewTokenBucket(interval time.Duration, burst int) Limiter {
+	return &tokenBucket{
+		interval: interval,
+		burst: burst,
+		tokens: float64(burst),
+		updatedAt: time.Now(),
+	}
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	tokens, wait := reserve(b.tokens, b.updatedAt, now, b.interval, b.burst)
+	if !fitsDeadline(ctx, now, wait) {
+		b.mu.Unlock()
+		return &errors.RateLimitedError{}
+	}
+	b.tokens, b.updatedAt = tokens, now
+	b.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		// The request will not be sent, so its token goes to the next
+		// one instead of holding it back.
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"igaku/geo-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/geo-service/errors"
+)
+
+type postgresTokenBucket struct {
+	db		*gorm.DB
+	name		string
+	interval	time.Duration
+	burst		int
+}
+
+// NewPostgresTokenBucket creates a limiter like NewTokenBucket, keeping the
+// bucket in PostgreSQL so that all replicas of the service share it.
+// Buckets are told apart by name.
+func NewPostgresTokenBucket(
+	db *gorm.DB, name string, interval time.Duration, burst int,
+) Limiter {
+	return &postgresTokenBucket{
+		db: db,
+		name: name,
+		interval: interval,
+		burst: burst,
+	}
+}
+
+func (b *postgresTokenBucket) Wait(ctx context.Context) error {
+	var wait time.Duration
+	err := b.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.RateLimit{
+				Name: b.name,
+				Tokens: float64(b.burst),
+				UpdatedAt: now,
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		var bucket models.RateLimit
+		err = tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("name = ?", b.name).
+			First(&bucket).
+			Error
+		if err != nil {
+			return err
+		}
+
+		// The clock is read again, as acquiring the lock may have
+		// taken a while.
+		now = time.Now()
+		var tokens float64
+		tokens, wait = reserve(
+			bucket.Tokens, bucket.UpdatedAt, now, b.interval, b.burst,
+		)
+		if !fitsDeadline(ctx, now, wait) {
+			return &igakuErrors.RateLimitedError{}
+		}
+
+		return tx.
+			Model(&bucket).
+			Updates(map[string]any{"tokens": tokens, "updated_at": now}).
+			Error
+	})
+	if errors.Is(err, &igakuErrors.RateLimitedError{}) {
+		return err
+	}
+	if err != nil {
+		log.Printf("Failed to reserve a request to the external API: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		b.release()
+		return err
+	}
+	return nil
+}
+
+// release returns the token of a request which will not be sent. The
+// context of the request is done by then, so it is not used.
+func (b *postgresTokenBucket) release() {
+	err := b.db.
+		Model(&models.RateLimit{}).
+		Where("name = ?", b.name).
+		Update("tokens", gorm.Expr("tokens + 1")).
+		Error
+	if err != nil {
+		log.Printf("Failed to release a request to the external API: %v", err)
+	}
+}
@@ -1,23 +1,38 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"igaku/geo-service/batch"
+	"igaku/geo-service/cache"
 	"igaku/geo-service/controllers"
 	"igaku/geo-service/docs"
+	"igaku/geo-service/limiter"
+	"igaku/geo-service/providers"
 	"igaku/geo-service/servers"
 	"igaku/geo-service/services"
+	"igaku/geo-service/utils"
+	commonsDtos "igaku/commons/dtos"
 )
 
 // @title		Igaku Geo API
 // @version		0.0.1
 // @host		localhost:4000
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
 func main() {
 	router := gin.Default()
 	docs.SwaggerInfo.BasePath = "/"
@@ -25,18 +40,96 @@ func main() {
 	healthController := controllers.NewHealthController()
 	healthController.RegisterRoutes(router)
 
-	nominatimURL := os.Getenv("NOMINATIM_URL")
-	if nominatimURL == "" {
-		nominatimURL = "https://nominatim.openstreetmap.org"
+	cacheSize := envInt("GEO_CACHE_SIZE", 1000)
+	positiveTTL := envInt("GEO_CACHE_TTL_IN_SECONDS", 7*24*60*60)
+	negativeTTL := envInt("GEO_CACHE_NEGATIVE_TTL_IN_SECONDS", 60*60)
+
+	db, err := utils.InitDatabase()
+	if err != nil {
+		log.Fatalf("Failed to connect to the cache database: %v", err)
+	}
+
+	var persistentCache cache.Store
+	if db != nil {
+		store := cache.NewPostgresStore(db)
+		if purged, err := store.DeleteExpired(time.Now()); err != nil {
+			log.Printf("Failed to purge expired cache entries: %v", err)
+		} else {
+			log.Printf("Purged %d expired cache entries", purged)
+		}
+		persistentCache = store
 	}
 
-	geoService := services.NewGeoService(nominatimURL)
+	var geoProviders []providers.Provider
+	for _, name := range strings.Split(os.Getenv("GEO_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		provider, err := newProvider(name, db)
+		if err != nil {
+			log.Fatalf("Failed to set up the '%s' provider: %v", name, err)
+		}
+		geoProviders = append(geoProviders, provider)
+	}
+	if len(geoProviders) == 0 {
+		provider, _ := newProvider("nominatim", db)
+		geoProviders = append(geoProviders, provider)
+	}
+
+	geoCache := cache.New(cache.NewLRU(cacheSize), persistentCache)
+	geoService := services.NewCachedGeoService(
+		services.NewGeoService(geoProviders...),
+		geoCache,
+		services.CacheTTL{
+			Positive: time.Duration(positiveTTL) * time.Second,
+			Negative: time.Duration(negativeTTL) * time.Second,
+		},
+	)
+
+	cacheController := controllers.NewCacheController(geoCache)
+	cacheController.RegisterRoutes(router)
+
 	geoController := controllers.NewGeoController(geoService)
 	geoController.RegisterRoutes(router)
 
+	geoV2Controller := controllers.NewGeoV2Controller(geoService)
+	geoV2Controller.RegisterRoutes(router)
+
+	var batchStore batch.Store = batch.NewMemoryStore()
+	if db != nil {
+		store := batch.NewPostgresStore(db)
+		retention := time.Duration(
+			envInt("GEO_BATCH_RETENTION_IN_HOURS", 7*24),
+		) * time.Hour
+		if purged, err := store.DeleteFinished(time.Now().Add(-retention)); err != nil {
+			log.Printf("Failed to purge finished batch jobs: %v", err)
+		} else {
+			log.Printf("Purged %d finished batch jobs", purged)
+		}
+		batchStore = store
+	}
+
+	batchService := services.NewBatchService(
+		batchStore, geoService, 5*time.Second,
+	)
+	batchWorker := services.NewBatchWorker(batchService, 2*time.Second)
+	batchWorker.Start()
+	defer batchWorker.Shutdown()
+
+	batchController := controllers.NewBatchController(batchService)
+	batchController.RegisterRoutes(router)
+
+	routeService := services.NewRouteService(newRoutingEngine(db))
+
+	routeController := controllers.NewRouteController(routeService)
+	routeController.RegisterRoutes(router)
+
 	amqpURI := os.Getenv("RABBITMQ_URL")
 
-	rbServer, err := servers.NewRabbitMQServer(amqpURI, geoService)
+	rbServer, err := servers.NewRabbitMQServer(
+		amqpURI, geoService, routeService,
+	)
 	if err != nil {
 		log.Fatalf("[RabbitMQ] Failed to start listeners: %v", err)
 	}
@@ -53,3 +146,75 @@ func main() {
 
 	router.Run()
 }
+
+// newProvider creates the geocoding provider of the given name.
+func newProvider(name string, db *gorm.DB) (providers.Provider, error) {
+	switch name {
+	case "nominatim":
+		nominatimURL := os.Getenv("NOMINATIM_URL")
+		if nominatimURL == "" {
+			nominatimURL = "https://nominatim.openstreetmap.org"
+		}
+		return providers.NewNominatimProvider(
+			nominatimURL, newLimiter(name, db),
+		), nil
+	case "photon":
+		photonURL := os.Getenv("PHOTON_URL")
+		if photonURL == "" {
+			photonURL = "https://photon.komoot.io"
+		}
+		return providers.NewPhotonProvider(
+			photonURL, newLimiter(name, db),
+		), nil
+	case "gazetteer":
+		return providers.NewGazetteerProvider(os.Getenv("GAZETTEER_PATH"))
+	default:
+		return nil, fmt.Errorf("unknown provider")
+	}
+}
+
+// newRoutingEngine creates the routing engine, querying the OSRM compatible
+// server at OSRM_<MODE>_URL for each mode, e.g. OSRM_WALKING_URL. Without
+// any configured server only driving is routed, by the demo server of
+// OSRM.
+func newRoutingEngine(db *gorm.DB) providers.RoutingEngine {
+	urls := map[commonsDtos.TravelMode]string{}
+	for _, mode := range commonsDtos.TravelModes {
+		name := "OSRM_" + strings.ToUpper(string(mode)) + "_URL"
+		if osrmURL := os.Getenv(name); osrmURL != "" {
+			urls[mode] = osrmURL
+		}
+	}
+	if len(urls) == 0 {
+		urls[commonsDtos.Driving] = "https://router.project-osrm.org"
+	}
+
+	return providers.NewOSRMEngine(urls, newLimiter("osrm", db))
+}
+
+// newLimiter creates the limiter of requests to a provider, configured by
+// the <NAME>_RATE_LIMIT_* variables. Public servers allow a single request
+// per second. Without a database each replica limits only its own
+// requests.
+func newLimiter(name string, db *gorm.DB) limiter.Limiter {
+	prefix := strings.ToUpper(name)
+	interval := time.Duration(
+		envInt(prefix+"_RATE_LIMIT_INTERVAL_IN_MS", 1000),
+	) * time.Millisecond
+	burst := envInt(prefix+"_RATE_LIMIT_BURST", 1)
+
+	if db != nil {
+		return limiter.NewPostgresTokenBucket(db, name, interval, burst)
+	}
+	return limiter.NewTokenBucket(interval, burst)
+}
+
+// envInt reads a positive integer from the environment, falling back to
+// the default when it is unset or invalid.
+func envInt(name string, fallback int) int {
+	val, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || val <= 0 {
+		return fallback
+	}
+	return val
+}
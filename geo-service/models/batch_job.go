@@ -0,0 +1,68 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	commonDtos "igaku/commons/dtos"
+)
+
+type BatchJobStatus string
+
+const (
+	BatchQueued	BatchJobStatus = "queued"
+	BatchRunning	BatchJobStatus = "running"
+	BatchCompleted	BatchJobStatus = "completed"
+)
+
+// BatchJob is a list of addresses geocoded in the background.
+type BatchJob struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primaryKey" json:"id" example:"9b2f4a8e-6c1d-4e3a-8f5b-2d7c9e0a1b3c"`
+	// OwnerID is the user who submitted the job, the only one allowed to
+	// see it.
+	OwnerID		string		`gorm:"not null;index" json:"-"`
+	Status		BatchJobStatus	`gorm:"not null;index" json:"status" enums:"queued,running,completed" example:"running"`
+	Total		int		`gorm:"not null" json:"total" example:"250"`
+	Processed	int		`gorm:"not null" json:"processed" example:"120"`
+	// Progress is the share of processed rows, between 0 and 1.
+	Progress	float64		`gorm:"-" json:"progress" example:"0.48"`
+	CreatedAt	time.Time	`gorm:"not null;index" json:"created_at" example:"2025-06-02T09:30:00Z"`
+	// UpdatedAt is when the last row was processed. Running jobs which
+	// have not been updated for a while were abandoned by a stopped
+	// replica and are taken over by another one.
+	UpdatedAt	time.Time	`gorm:"not null" json:"updated_at" example:"2025-06-02T09:32:00Z"`
+	FinishedAt	*time.Time	`json:"finished_at,omitempty" example:"2025-06-02T09:35:00Z"`
+}
+
+type BatchRowStatus string
+
+const (
+	RowPending	BatchRowStatus = "pending"
+	RowMatched	BatchRowStatus = "matched"
+	RowNotFound	BatchRowStatus = "not_found"
+	RowFailed	BatchRowStatus = "failed"
+)
+
+// BatchRow is an address of a batch job and the result of geocoding it.
+type BatchRow struct {
+	JobID		uuid.UUID		`gorm:"type:uuid;primaryKey" json:"-"`
+	// Row is the position of the address in the job, from 1.
+	Row		int			`gorm:"column:row_index;primaryKey;autoIncrement:false" json:"row" example:"1"`
+	Query		string			`gorm:"not null" json:"query" example:"55 Fruit Street, Boston"`
+	// Components are set for addresses given by their components, which
+	// are searched for with structured search.
+	Components	commonDtos.Address	`gorm:"embedded;embeddedPrefix:query_" json:"-"`
+	Status		BatchRowStatus		`gorm:"not null" json:"status" enums:"pending,matched,not_found,failed" example:"matched"`
+	// Location is the best match.
+	Location	*commonDtos.Location	`gorm:"type:jsonb;serializer:json" json:"location,omitempty"`
+	// Candidates is the number of places found.
+	Candidates	int			`gorm:"not null" json:"candidates" example:"2"`
+	// Confidence is the share of the words of the address found in the
+	// best match, between 0 and 1.
+	Confidence	float64			`gorm:"not null" json:"confidence" example:"0.8"`
+	// Ambiguous tells that places far apart from each other match the
+	// address, so the best match may be the wrong one.
+	Ambiguous	bool			`gorm:"not null" json:"ambiguous" example:"false"`
+	Error		string			`json:"error,omitempty"`
+}
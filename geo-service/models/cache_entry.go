@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// CacheEntry is a geocoding result kept in the persistent cache.
+type CacheEntry struct {
+	Key		string		`gorm:"primaryKey"`
+	// Value is the JSON of the result, empty for negative entries.
+	Value		[]byte
+	Negative	bool		`gorm:"not null"`
+	ExpiresAt	time.Time	`gorm:"not null;index"`
+}
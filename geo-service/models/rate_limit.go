@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+// RateLimit is the token bucket of a limiter shared by the replicas of the
+// service.
+type RateLimit struct {
+	Name		string		`gorm:"primaryKey"`
+	Tokens		float64		`gorm:"not null"`
+	UpdatedAt	time.Time	`gorm:"not null"`
+}
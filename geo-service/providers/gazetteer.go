@@ -0,0 +1,209 @@
+package providers
+
+import (
+	"encoding/csv"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"igaku/geo-service/errors"
+	commonDtos "igaku/commons/dtos"
+)
+
+// gazetteerSearchLimit is the maximum number of places a search returns,
+// as with the other providers.
+const gazetteerSearchLimit = 10
+
+type gazetteerPlace struct {
+	location commonDtos.Location
+	lat, lon float64
+	// name is the lowercase name, which searches are matched against.
+	name string
+}
+
+type gazetteerProvider struct {
+	places []gazetteerPlace
+	// byID maps the IDs to the indexes of the places, as IDs of different
+	// types may be equal.
+	byID map[int64][]int
+}
+
+// NewGazetteerProvider creates a provider answering offline from a
+// gazetteer, a CSV file with the columns osm_id, lat, lon and name, which
+// is loaded into memory. IDs may be prefixed with the letter of their type,
+// e.g. W117853077.
+func NewGazetteerProvider(path string) (Provider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("Failed to open gazetteer: %v", err)
+		return nil, err
+	}
+	defer file.Close()
+
+	return loadGazetteer(file)
+}
+
+func loadGazetteer(r io.Reader) (Provider, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+
+	// Skips the header.
+	if _, err := reader.Read(); err != nil {
+		log.Printf("Failed to read gazetteer header: %v", err)
+		return nil, err
+	}
+
+	provider := &gazetteerProvider{byID: map[int64][]int{}}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Failed to read gazetteer: %v", err)
+			return nil, err
+		}
+
+		osmType, id, err := commonDtos.ParseOsmID(record[0])
+		if err != nil {
+			log.Printf("Invalid gazetteer ID '%s': %v", record[0], err)
+			return nil, err
+		}
+		lat, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			log.Printf("Invalid gazetteer latitude '%s': %v", record[1], err)
+			return nil, err
+		}
+		lon, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			log.Printf("Invalid gazetteer longitude '%s': %v", record[2], err)
+			return nil, err
+		}
+
+		provider.byID[id] = append(provider.byID[id], len(provider.places))
+		provider.places = append(provider.places, gazetteerPlace{
+			location: commonDtos.Location{
+				ID: id,
+				OsmType: osmType,
+				Lat: record[1],
+				Lon: record[2],
+				Name: record[3],
+			},
+			lat: lat,
+			lon: lon,
+			name: strings.ToLower(record[3]),
+		})
+	}
+
+	return provider, nil
+}
+
+func (s *gazetteerProvider) Name() string {
+	return "gazetteer"
+}
+
+// Search returns the places whose name contains every word of the address.
+func (s *gazetteerProvider) Search(address string) ([]commonDtos.Location, error) {
+	return s.match(address, gazetteerSearchLimit)
+}
+
+// Autocomplete matches the prefix as Search does, as the words are
+// matched anywhere in the names.
+func (s *gazetteerProvider) Autocomplete(
+	prefix string, limit int,
+) ([]commonDtos.Location, error) {
+	return s.match(prefix, limit)
+}
+
+// match returns at most limit places whose name contains every word of
+// the address.
+func (s *gazetteerProvider) match(
+	address string, limit int,
+) ([]commonDtos.Location, error) {
+	terms := strings.FieldsFunc(strings.ToLower(address), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(terms) == 0 {
+		return nil, &errors.InvalidAddressError{}
+	}
+
+	var locations []commonDtos.Location
+	for _, place := range s.places {
+		if len(locations) == limit {
+			break
+		}
+
+		matches := true
+		for _, term := range terms {
+			if !strings.Contains(place.name, term) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			locations = append(locations, place.location)
+		}
+	}
+	return locations, nil
+}
+
+// SearchStructured searches for the components joined into a free-form
+// query.
+func (s *gazetteerProvider) SearchStructured(
+	address commonDtos.Address,
+) ([]commonDtos.Location, error) {
+	return s.Search(JoinAddress(address))
+}
+
+// Reverse returns the place nearest to the coordinates.
+func (s *gazetteerProvider) Reverse(lat, lon float64) (*commonDtos.Location, error) {
+	nearest := -1
+	nearestDistance := math.Inf(1)
+	for i, place := range s.places {
+		distance := haversine(lat, lon, place.lat, place.lon)
+		if distance < nearestDistance {
+			nearest, nearestDistance = i, distance
+		}
+	}
+	if nearest < 0 {
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Unable to geocode",
+		}
+	}
+
+	location := s.places[nearest].location
+	return &location, nil
+}
+
+// Lookup returns the place with the ID. Places of unknown type match any
+// type.
+func (s *gazetteerProvider) Lookup(
+	osmType commonDtos.OsmType, id int64,
+) (*commonDtos.Location, error) {
+	for _, i := range s.byID[id] {
+		location := s.places[i].location
+		if osmType == "" || location.OsmType == "" ||
+			location.OsmType == osmType {
+			return &location, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// haversine returns the distance in kilometres between two points.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*
+			math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
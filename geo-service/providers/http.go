@@ -0,0 +1,123 @@
+package providers
+
+import (
+	"context"
+	stdErrors "errors"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+)
+
+// defaultUserAgent identifies the service to the providers, whose usage
+// policies forbid generic user agents.
+const defaultUserAgent = "Igaku-Geo/0.0.1"
+
+// httpClient sends the requests of a provider backed by a web API.
+type httpClient struct {
+	timeout time.Duration
+	userAgent string
+	limiter limiter.Limiter
+	// inFlight coalesces identical requests, so that callers asking for
+	// the same query at once are answered with a single request.
+	inFlight singleflight.Group
+}
+
+// httpResponse is the status and the body of a response of a provider.
+type httpResponse struct {
+	statusCode int
+	body []byte
+}
+
+func newHTTPClient(
+	timeout time.Duration, userAgent string, limiter limiter.Limiter,
+) *httpClient {
+	return &httpClient{
+		timeout: timeout,
+		userAgent: userAgent,
+		limiter: limiter,
+	}
+}
+
+// envTimeout reads a timeout in seconds from the environment, defaulting to
+// 10 seconds.
+func envTimeout(name string) time.Duration {
+	t, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || t <= 0 {
+		t = 10
+	}
+	return time.Duration(t) * time.Second
+}
+
+// fetch sends a GET request, once the limiter allows it. The
+// timeout covers both the time spent waiting for the limiter and the
+// request itself.
+func (s *httpClient) fetch(requestUrl string) (*httpResponse, error) {
+	res, err, _ := s.inFlight.Do(requestUrl, func() (any, error) {
+		ctx, cancel := context.WithTimeout(
+			context.Background(), s.timeout,
+		)
+		defer cancel()
+
+		if err := s.limiter.Wait(ctx); err != nil {
+			if stdErrors.Is(err, &errors.RateLimitedError{}) {
+				log.Printf("Request to external API rate limited\n")
+				return nil, err
+			}
+			if stdErrors.Is(err, context.DeadlineExceeded) {
+				log.Printf("Request to external API timed out: %v\n", err)
+				return nil, &errors.TimeoutError{}
+			}
+			return nil, &errors.ExternalApiRequestError{
+				Message: "Failed to perform a lookup",
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+		if err != nil {
+			log.Printf("Failed to create request: %v\n", err)
+			return nil, &errors.ExternalApiRequestError{
+				Message: "Failed to create request",
+			}
+		}
+
+		req.Header.Set("User-Agent", s.userAgent)
+		req.Header.Set("Accept", "*/*")
+
+		client := &http.Client{}
+		res, err := client.Do(req)
+
+		if err != nil {
+			if err, ok := err.(interface{ Timeout() bool }); ok && err.Timeout() {
+				log.Printf("Request to external API timed out: %v\n", err)
+				return nil, &errors.TimeoutError{}
+			}
+			log.Printf("Failed to connect to external API: %v\n", err)
+			return nil, &errors.ExternalApiRequestError{
+				Message: "Failed to perform a lookup",
+			}
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			log.Printf("Failed to read response body: %v\n", err)
+			return nil, &errors.ExternalApiRequestError{
+				Message: "Failed to read response body",
+			}
+		}
+
+		return &httpResponse{statusCode: res.StatusCode, body: body}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*httpResponse), nil
+}
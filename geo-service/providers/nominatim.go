@@ -0,0 +1,231 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"igaku/geo-service/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+	commonDtos "igaku/commons/dtos"
+)
+
+type nominatimProvider struct {
+	nominatimURL string
+	client *httpClient
+}
+
+// NewNominatimProvider creates a provider querying the Nominatim server at
+// the given URL, paced by the limiter.
+func NewNominatimProvider(
+	nominatimURL string, limiter limiter.Limiter,
+) Provider {
+	userAgent := os.Getenv("NOMINATIM_USER_AGENT")
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
+	return &nominatimProvider{
+		nominatimURL: nominatimURL,
+		client: newHTTPClient(
+			envTimeout("NOMINATIM_TIMEOUT"), userAgent, limiter,
+		),
+	}
+}
+
+func (s *nominatimProvider) Name() string {
+	return "nominatim"
+}
+
+func (s *nominatimProvider) Search(address string) ([]commonDtos.Location, error) {
+	escaped := url.QueryEscape(address)
+
+	requestUrl := fmt.Sprintf(
+		"%s/search?q=%s&format=json&addressdetails=1",
+		s.nominatimURL, escaped,
+	)
+
+	return s.search(requestUrl)
+}
+
+func (s *nominatimProvider) SearchStructured(
+	address commonDtos.Address,
+) ([]commonDtos.Location, error) {
+	params := url.Values{}
+	street := strings.TrimSpace(address.HouseNumber + " " + address.Street)
+	for param, value := range map[string]string{
+		"street": street,
+		"city": address.City,
+		"postalcode": address.Postcode,
+		"county": address.County,
+		"country": address.Country,
+		"countrycodes": address.CountryCode,
+	} {
+		if value != "" {
+			params.Set(param, value)
+		}
+	}
+	params.Set("format", "json")
+	params.Set("addressdetails", "1")
+
+	requestUrl := fmt.Sprintf(
+		"%s/search?%s",
+		s.nominatimURL, params.Encode(),
+	)
+
+	return s.search(requestUrl)
+}
+
+// Autocomplete is not offered, as the usage policy of Nominatim forbids
+// searching as the user types.
+func (s *nominatimProvider) Autocomplete(
+	prefix string, limit int,
+) ([]commonDtos.Location, error) {
+	return nil, &errors.UnsupportedOperationError{}
+}
+
+func (s *nominatimProvider) search(
+	requestUrl string,
+) ([]commonDtos.Location, error) {
+	res, err := s.client.fetch(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.statusCode != 200 {
+		log.Printf(
+			"Request to external API failed with status code: %d\n",
+			res.statusCode,
+		)
+		if res.statusCode == 400 {
+			return nil, &errors.InvalidAddressError{}
+		}
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to perform a lookup",
+		}
+	}
+
+	var allLocations []dtos.NominatimLocation
+	if err := json.Unmarshal(res.body, &allLocations); err != nil {
+		log.Printf("Failed to parse JSON: %v\n", err)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to parse external API response",
+		}
+	}
+
+	var locations []commonDtos.Location
+	for _, loc := range allLocations {
+		locations = append(locations, loc.ToLocation())
+	}
+
+	return locations, nil
+}
+
+func (s *nominatimProvider) Reverse(lat, lon float64) (*commonDtos.Location, error) {
+	requestUrl := fmt.Sprintf(
+		"%s/reverse?lat=%s&lon=%s&format=json&addressdetails=1",
+		s.nominatimURL,
+		commonDtos.Coordinate(lat), commonDtos.Coordinate(lon),
+	)
+
+	res, err := s.client.fetch(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	var errMap map[string]interface{}
+	if json.Unmarshal(res.body, &errMap) == nil {
+		if errBody, ok := errMap["error"].(map[string]interface{}); ok {
+			if val, ok := errBody["message"].(string); ok {
+				msg := fmt.Sprintf("%v", val)
+				ret := &errors.ExternalApiRequestError{
+					Message: msg,
+				}
+				return nil, ret
+			}
+		} else if val, ok := errMap["error"].(string); ok {
+			// Nominatim answers points with nothing around, e.g. in
+			// the middle of an ocean, with an error of its own.
+			if val == "Unable to geocode" {
+				return nil, nil
+			}
+			msg := fmt.Sprintf("%v", val)
+			return nil, &errors.ExternalApiRequestError{Message: msg}
+		}
+	}
+
+	if res.statusCode != 200 {
+		log.Printf(
+			"Request to external API failed with status code: %d\n",
+			res.statusCode,
+		)
+		if res.statusCode == 400 {
+			return nil, &errors.InvalidAddressError{}
+		}
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to perform a lookup",
+		}
+	}
+
+	var location dtos.NominatimLocation
+	if err := json.Unmarshal(res.body, &location); err != nil {
+		log.Printf("Failed to parse JSON: %v\n", err)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to parse external API response",
+		}
+	}
+
+	converted := location.ToLocation()
+	return &converted, nil
+}
+
+func (s *nominatimProvider) Lookup(
+	osmType commonDtos.OsmType, id int64,
+) (*commonDtos.Location, error) {
+	osmIDs := commonDtos.FormatOsmID(osmType, id)
+	if osmType == "" {
+		osmIDs = strings.Join([]string{
+			commonDtos.FormatOsmID(commonDtos.OsmNode, id),
+			commonDtos.FormatOsmID(commonDtos.OsmWay, id),
+			commonDtos.FormatOsmID(commonDtos.OsmRelation, id),
+		}, ",")
+	}
+
+	requestUrl := fmt.Sprintf(
+		"%s/lookup?osm_ids=%s&format=json&addressdetails=1",
+		s.nominatimURL, osmIDs,
+	)
+
+	res, err := s.client.fetch(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.statusCode != 200 {
+		log.Printf(
+			"Request to external API failed with status code: %d\n",
+			res.statusCode,
+		)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to perform a lookup",
+		}
+	}
+
+	var locations []dtos.NominatimLocation
+	if err := json.Unmarshal(res.body, &locations); err != nil {
+		log.Printf("Failed to parse JSON: %v\n", err)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to parse external API response",
+		}
+	}
+
+	if len(locations) <= 0 {
+		return nil, nil
+	}
+	location := locations[0].ToLocation()
+	return &location, nil
+}
@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+	commonDtos "igaku/commons/dtos"
+)
+
+type osrmEngine struct {
+	// urls are the servers routing each mode. An OSRM server routes a
+	// single profile, so each mode usually has its own server.
+	urls map[commonDtos.TravelMode]string
+	client *httpClient
+}
+
+// osrmRoutes is the response of the route service of OSRM.
+type osrmRoutes struct {
+	Code	string	`json:"code"`
+	Routes	[]struct {
+		// Distance is in metres and duration in seconds.
+		Distance	float64	`json:"distance"`
+		Duration	float64	`json:"duration"`
+	} `json:"routes"`
+}
+
+// NewOSRMEngine creates an engine querying the OSRM compatible servers
+// given for each mode, paced by the limiter. Modes without a server are not
+// routed.
+func NewOSRMEngine(
+	urls map[commonDtos.TravelMode]string, limiter limiter.Limiter,
+) RoutingEngine {
+	return &osrmEngine{
+		urls: urls,
+		client: newHTTPClient(
+			envTimeout("OSRM_TIMEOUT"), defaultUserAgent, limiter,
+		),
+	}
+}
+
+func (s *osrmEngine) Name() string {
+	return "osrm"
+}
+
+func (s *osrmEngine) Modes() []commonDtos.TravelMode {
+	var modes []commonDtos.TravelMode
+	for _, mode := range commonDtos.TravelModes {
+		if _, ok := s.urls[mode]; ok {
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+func (s *osrmEngine) Route(
+	mode commonDtos.TravelMode, fromLat, fromLon, toLat, toLon float64,
+) (*commonDtos.RouteEstimate, error) {
+	osrmURL, ok := s.urls[mode]
+	if !ok {
+		return nil, &errors.UnsupportedTravelModeError{}
+	}
+
+	// OSRM takes the longitude first. The geometry of the route is not
+	// needed, only its summary.
+	requestUrl := fmt.Sprintf(
+		"%s/route/v1/%s/%s,%s;%s,%s?overview=false",
+		osrmURL, mode,
+		commonDtos.Coordinate(fromLon), commonDtos.Coordinate(fromLat),
+		commonDtos.Coordinate(toLon), commonDtos.Coordinate(toLat),
+	)
+
+	res, err := s.client.fetch(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Points which cannot be connected are reported with a client error
+	// rather than with an empty list of routes.
+	if res.statusCode != 200 && res.statusCode != 400 {
+		log.Printf(
+			"Request to external API failed with status code: %d\n",
+			res.statusCode,
+		)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to estimate the route",
+		}
+	}
+
+	var routes osrmRoutes
+	if err := json.Unmarshal(res.body, &routes); err != nil {
+		log.Printf("Failed to parse JSON: %v\n", err)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to parse external API response",
+		}
+	}
+
+	switch routes.Code {
+	case "Ok":
+	case "NoRoute", "NoSegment":
+		return nil, nil
+	default:
+		log.Printf("Failed to estimate the route: %s\n", routes.Code)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to estimate the route",
+		}
+	}
+
+	if len(routes.Routes) == 0 {
+		return nil, nil
+	}
+	return &commonDtos.RouteEstimate{
+		Mode: mode,
+		DistanceMeters: routes.Routes[0].Distance,
+		DurationSeconds: routes.Routes[0].Duration,
+	}, nil
+}
@@ -0,0 +1,197 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+	commonDtos "igaku/commons/dtos"
+)
+
+type photonProvider struct {
+	photonURL string
+	client *httpClient
+}
+
+// photonFeatures is the GeoJSON feature collection returned by Photon.
+type photonFeatures struct {
+	Features []struct {
+		Geometry struct {
+			// Coordinates are the longitude and the latitude.
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties struct {
+			OsmID		int64	`json:"osm_id"`
+			OsmType		string	`json:"osm_type"`
+			Name		string	`json:"name"`
+			HouseNumber	string	`json:"housenumber"`
+			Street		string	`json:"street"`
+			District	string	`json:"district"`
+			City		string	`json:"city"`
+			County		string	`json:"county"`
+			State		string	`json:"state"`
+			Postcode	string	`json:"postcode"`
+			Country		string	`json:"country"`
+			CountryCode	string	`json:"countrycode"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// NewPhotonProvider creates a provider querying the Photon server at the
+// given URL, paced by the limiter. Photon cannot look places up by their
+// OSM ID.
+func NewPhotonProvider(photonURL string, limiter limiter.Limiter) Provider {
+	return &photonProvider{
+		photonURL: photonURL,
+		client: newHTTPClient(
+			envTimeout("PHOTON_TIMEOUT"), defaultUserAgent, limiter,
+		),
+	}
+}
+
+func (s *photonProvider) Name() string {
+	return "photon"
+}
+
+func (s *photonProvider) Search(address string) ([]commonDtos.Location, error) {
+	requestUrl := fmt.Sprintf(
+		"%s/api?q=%s&limit=10",
+		s.photonURL, url.QueryEscape(address),
+	)
+
+	features, err := s.query(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+	return features.locations(), nil
+}
+
+// SearchStructured searches for the components joined into a free-form
+// query, as Photon has no structured search.
+func (s *photonProvider) SearchStructured(
+	address commonDtos.Address,
+) ([]commonDtos.Location, error) {
+	return s.Search(JoinAddress(address))
+}
+
+func (s *photonProvider) Autocomplete(
+	prefix string, limit int,
+) ([]commonDtos.Location, error) {
+	requestUrl := fmt.Sprintf(
+		"%s/api?q=%s&limit=%d",
+		s.photonURL, url.QueryEscape(prefix), limit,
+	)
+
+	features, err := s.query(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+	return features.locations(), nil
+}
+
+func (s *photonProvider) Reverse(lat, lon float64) (*commonDtos.Location, error) {
+	requestUrl := fmt.Sprintf(
+		"%s/reverse?lat=%s&lon=%s&limit=1",
+		s.photonURL,
+		commonDtos.Coordinate(lat), commonDtos.Coordinate(lon),
+	)
+
+	features, err := s.query(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := features.locations()
+	if len(locations) == 0 {
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Unable to geocode",
+		}
+	}
+	return &locations[0], nil
+}
+
+func (s *photonProvider) Lookup(
+	osmType commonDtos.OsmType, id int64,
+) (*commonDtos.Location, error) {
+	return nil, &errors.UnsupportedOperationError{}
+}
+
+func (s *photonProvider) query(requestUrl string) (*photonFeatures, error) {
+	res, err := s.client.fetch(requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.statusCode != 200 {
+		log.Printf(
+			"Request to external API failed with status code: %d\n",
+			res.statusCode,
+		)
+		if res.statusCode == 400 {
+			return nil, &errors.InvalidAddressError{}
+		}
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to perform a lookup",
+		}
+	}
+
+	var features photonFeatures
+	if err := json.Unmarshal(res.body, &features); err != nil {
+		log.Printf("Failed to parse JSON: %v\n", err)
+		return nil, &errors.ExternalApiRequestError{
+			Message: "Failed to parse external API response",
+		}
+	}
+	return &features, nil
+}
+
+// locations converts the features to locations.
+func (f *photonFeatures) locations() []commonDtos.Location {
+	var locations []commonDtos.Location
+	for _, feature := range f.Features {
+		props := feature.Properties
+		coords := feature.Geometry.Coordinates
+		if len(coords) < 2 {
+			continue
+		}
+		osmType, err := commonDtos.ParseOsmType(props.OsmType)
+		if err != nil {
+			log.Printf("Skipping a feature of unknown type: %v", err)
+			continue
+		}
+
+		street := strings.TrimSpace(props.HouseNumber + " " + props.Street)
+		var parts []string
+		for _, part := range []string{
+			props.Name, street, props.District, props.City,
+			props.State, props.Postcode, props.Country,
+		} {
+			if part != "" && (len(parts) == 0 || parts[len(parts)-1] != part) {
+				parts = append(parts, part)
+			}
+		}
+
+		locations = append(locations, commonDtos.Location{
+			ID: props.OsmID,
+			OsmType: osmType,
+			Lat: strconv.FormatFloat(coords[1], 'f', 7, 64),
+			Lon: strconv.FormatFloat(coords[0], 'f', 7, 64),
+			Name: strings.Join(parts, ", "),
+			Address: commonDtos.Address{
+				HouseNumber: props.HouseNumber,
+				Street: props.Street,
+				City: props.City,
+				Postcode: props.Postcode,
+				County: props.County,
+				Country: props.Country,
+				CountryCode: strings.ToLower(props.CountryCode),
+			},
+		})
+	}
+	return locations
+}
@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"strings"
+
+	commonDtos "igaku/commons/dtos"
+)
+
+// Provider is a geocoding service. Providers report failures of the service
+// with TimeoutError, RateLimitedError or ExternalApiRequestError, after which
+// the next provider is tried, and operations they do not offer with
+// UnsupportedOperationError.
+type Provider interface {
+	// Name identifies the provider in the configuration and the logs.
+	Name() string
+	Search(address string) ([]commonDtos.Location, error)
+	// SearchStructured searches for the address given by its components.
+	SearchStructured(address commonDtos.Address) ([]commonDtos.Location, error)
+	// Autocomplete returns at most limit places matching the beginning of
+	// an address being typed.
+	Autocomplete(prefix string, limit int) ([]commonDtos.Location, error)
+	// Reverse returns the place at the coordinates, which have been
+	// validated.
+	Reverse(lat, lon float64) (*commonDtos.Location, error)
+	// Lookup returns the object with the given type and ID. Objects of
+	// unknown type are looked up as nodes, ways and relations in turn.
+	Lookup(osmType commonDtos.OsmType, id int64) (*commonDtos.Location, error)
+}
+
+// JoinAddress joins the components of an address into a free-form query,
+// for providers without structured search and for describing addresses.
+// The country code is left out, as free-form queries match it against
+// names.
+func JoinAddress(address commonDtos.Address) string {
+	street := strings.TrimSpace(address.HouseNumber + " " + address.Street)
+
+	var parts []string
+	for _, part := range []string{
+		street, address.City, address.Postcode, address.County,
+		address.Country,
+	} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
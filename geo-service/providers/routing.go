@@ -0,0 +1,21 @@
+package providers
+
+import (
+	commonDtos "igaku/commons/dtos"
+)
+
+// RoutingEngine estimates routes between two points. Like geocoding
+// providers, engines report failures with TimeoutError, RateLimitedError or
+// ExternalApiRequestError.
+type RoutingEngine interface {
+	// Name identifies the engine in the logs.
+	Name() string
+	// Modes are the modes of travel the engine can route.
+	Modes() []commonDtos.TravelMode
+	// Route estimates the fastest route by the mode between the
+	// coordinates, which have been validated. It returns nil when the
+	// points are not connected, e.g. by road.
+	Route(
+		mode commonDtos.TravelMode, fromLat, fromLon, toLat, toLon float64,
+	) (*commonDtos.RouteEstimate, error)
+}
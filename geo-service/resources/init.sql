@@ -0,0 +1,14 @@
+INSERT INTO cache_entries (key, value, negative, expires_at)
+VALUES
+    (
+        'lookup:W117853077',
+        convert_to('{"osm_id":117853077,"osm_type":"W","lat":"42.3628605","lon":"-71.0687530","display_name":"Massachusetts General Hospital, 55, Fruit Street, West End, Boston, Suffolk County, Massachusetts, 02114, United States","address":{"house_number":"55","street":"Fruit Street","city":"Boston","postcode":"02114","county":"Suffolk County","country":"United States","country_code":"us"}}', 'UTF8'),
+        false,
+        NOW() + INTERVAL '7 days'
+    ),
+    (
+        'lookup:1',
+        NULL,
+        true,
+        NOW() - INTERVAL '1 hour'
+    );
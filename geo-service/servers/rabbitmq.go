@@ -16,14 +16,16 @@ import (
 )
 
 type RabbitMQServer struct {
-	conn    *amqp.Connection
-	ch      *amqp.Channel
-	service services.GeoService
+	conn         *amqp.Connection
+	ch           *amqp.Channel
+	service      services.GeoService
+	routeService services.RouteService
 }
 
 func NewRabbitMQServer(
 	amqpURI string,
 	service services.GeoService,
+	routeService services.RouteService,
 ) (*RabbitMQServer, error) {
 	conn, err := amqp.Dial(amqpURI)
 	if err != nil {
@@ -38,7 +40,9 @@ func NewRabbitMQServer(
 		return nil, &commonsErrors.MessageBrokerError{}
 	}
 
-	return &RabbitMQServer{conn: conn, ch: ch, service: service}, nil
+	return &RabbitMQServer{
+		conn: conn, ch: ch, service: service, routeService: routeService,
+	}, nil
 }
 
 func (s *RabbitMQServer) Start() error {
@@ -58,6 +62,22 @@ func (s *RabbitMQServer) Start() error {
 		return &commonsErrors.MessageBrokerError{}
 	}
 
+	if err := s.startGeocodeSearchListener(); err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to start 'GeocodeSearchListener': %v",
+			err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	if err := s.startRouteEstimateListener(); err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to start 'RouteEstimateListener': %v",
+			err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
 	return nil
 }
 
@@ -113,11 +133,24 @@ func (s *RabbitMQServer) startReverseGeocodeListener() error {
 				continue
 			}
 
-			location, err := s.service.Reverse(req.Lat, req.Lon)
+			location, err := s.service.Reverse(
+				float64(req.Lat), float64(req.Lon),
+			)
 
 			var externErr *igakuErrors.ExternalApiRequestError
 			if err != nil {
-				if errors.Is(err, &igakuErrors.TimeoutError{}) {
+				if errors.Is(err, &igakuErrors.InvalidCoordinatesError{}) {
+					s.sendErrorResponse(
+						d,
+						"INVALID_REQUEST",
+						err.Error(),
+					)
+					continue
+				}
+				// Requests which could not be sent in time because of
+				// the rate limit are timeouts to the caller.
+				if errors.Is(err, &igakuErrors.TimeoutError{}) ||
+					errors.Is(err, &igakuErrors.RateLimitedError{}) {
 					s.sendErrorResponse(
 						d,
 						"TIMEOUT",
@@ -138,6 +171,14 @@ func (s *RabbitMQServer) startReverseGeocodeListener() error {
 				}
 				continue
 			}
+			if location == nil {
+				s.sendErrorResponse(
+					d,
+					"EXTERNAL",
+					"Unable to geocode",
+				)
+				continue
+			}
 
 			locationBytes, err := json.Marshal(location)
 			if err != nil {
@@ -199,11 +240,14 @@ func (s *RabbitMQServer) startLocationLookupListener() error {
 				continue
 			}
 
-			location, err := s.service.Lookup(req.ID)
+			location, err := s.service.Lookup(req.OsmType, req.ID)
 
 			var externErr *igakuErrors.ExternalApiRequestError
 			if err != nil {
-				if errors.Is(err, &igakuErrors.TimeoutError{}) {
+				// Requests which could not be sent in time because of
+				// the rate limit are timeouts to the caller.
+				if errors.Is(err, &igakuErrors.TimeoutError{}) ||
+					errors.Is(err, &igakuErrors.RateLimitedError{}) {
 					s.sendErrorResponse(
 						d,
 						"TIMEOUT",
@@ -253,6 +297,201 @@ func (s *RabbitMQServer) startLocationLookupListener() error {
 
 }
 
+func (s *RabbitMQServer) startGeocodeSearchListener() error {
+	queueName := "geocode_search"
+
+	q, err := s.ch.QueueDeclare(queueName, false, false, false, false, nil)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare a queue '%s': %v",
+			queueName, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	if err = s.ch.Qos(1, 0, false); err != nil {
+		log.Printf("[RabbitMQ] Failed to set QoS: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	msgs, err := s.ch.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to register a consumer: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	go func() {
+		log.Printf(" [*] Awaiting RPC requests on queue '%s'", q.Name)
+		for d := range msgs {
+			var req dtos.GeocodeSearchRequest
+			log.Printf(
+				"Received RPC request for geocoding, ID: %s",
+				d.CorrelationId,
+			)
+
+			if err := json.Unmarshal(d.Body, &req); err != nil {
+				log.Printf(
+					"Failed to unmarshal an RPC request: %v",
+					err,
+				)
+				s.sendErrorResponse(
+					d, "INVALID_REQUEST", err.Error(),
+				)
+				continue
+			}
+
+			locations, err := s.service.Search(req.Address)
+
+			var externErr *igakuErrors.ExternalApiRequestError
+			if err != nil {
+				if errors.Is(err, &igakuErrors.InvalidAddressError{}) {
+					s.sendErrorResponse(
+						d,
+						"INVALID_REQUEST",
+						err.Error(),
+					)
+				} else if errors.Is(err, &igakuErrors.TimeoutError{}) ||
+					errors.Is(err, &igakuErrors.RateLimitedError{}) {
+					// Requests which could not be sent in time because
+					// of the rate limit are timeouts to the caller.
+					s.sendErrorResponse(
+						d,
+						"TIMEOUT",
+						err.Error(),
+					)
+				} else if errors.As(err, &externErr) {
+					s.sendErrorResponse(
+						d,
+						"EXTERNAL",
+						err.Error(),
+					)
+				} else {
+					s.sendErrorResponse(
+						d,
+						"INTERNAL",
+						"Failed to perform geocoding",
+					)
+				}
+				continue
+			}
+
+			if locations == nil {
+				locations = []dtos.Location{}
+			}
+
+			resp, err := json.Marshal(locations)
+			if err != nil {
+				s.sendErrorResponse(
+					d,
+					"INTERNAL",
+					"Failed to marshal location data",
+				)
+				continue
+			}
+
+			s.sendResponse(d, resp)
+		}
+	}()
+
+	return nil
+}
+
+func (s *RabbitMQServer) startRouteEstimateListener() error {
+	queueName := "route_estimate"
+
+	q, err := s.ch.QueueDeclare(queueName, false, false, false, false, nil)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare a queue '%s': %v",
+			queueName, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	if err = s.ch.Qos(1, 0, false); err != nil {
+		log.Printf("[RabbitMQ] Failed to set QoS: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	msgs, err := s.ch.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to register a consumer: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	go func() {
+		log.Printf(" [*] Awaiting RPC requests on queue '%s'", q.Name)
+		for d := range msgs {
+			var req dtos.RouteRequest
+			log.Printf(
+				"Received RPC request for route estimate, ID: %s",
+				d.CorrelationId,
+			)
+
+			if err := json.Unmarshal(d.Body, &req); err != nil {
+				log.Printf(
+					"Failed to unmarshal an RPC request: %v",
+					err,
+				)
+				s.sendErrorResponse(
+					d, "INVALID_REQUEST", err.Error(),
+				)
+				continue
+			}
+
+			estimates, err := s.routeService.Route(req)
+
+			var externErr *igakuErrors.ExternalApiRequestError
+			if err != nil {
+				if errors.Is(err, &igakuErrors.InvalidCoordinatesError{}) ||
+					errors.Is(err, &igakuErrors.UnsupportedTravelModeError{}) {
+					s.sendErrorResponse(
+						d,
+						"INVALID_REQUEST",
+						err.Error(),
+					)
+				} else if errors.Is(err, &igakuErrors.TimeoutError{}) ||
+					errors.Is(err, &igakuErrors.RateLimitedError{}) {
+					// Requests which could not be sent in time because
+					// of the rate limit are timeouts to the caller.
+					s.sendErrorResponse(
+						d,
+						"TIMEOUT",
+						err.Error(),
+					)
+				} else if errors.As(err, &externErr) {
+					s.sendErrorResponse(
+						d,
+						"EXTERNAL",
+						err.Error(),
+					)
+				} else {
+					s.sendErrorResponse(
+						d,
+						"INTERNAL",
+						"Failed to estimate the route",
+					)
+				}
+				continue
+			}
+
+			resp, err := json.Marshal(estimates)
+			if err != nil {
+				s.sendErrorResponse(
+					d,
+					"INTERNAL",
+					"Failed to marshal route data",
+				)
+				continue
+			}
+
+			s.sendResponse(d, resp)
+		}
+	}()
+
+	return nil
+}
+
 func (s *RabbitMQServer) sendResponse(d amqp.Delivery, data []byte) {
 	resp := dtos.RPCResponse{Data: data}
 	respBytes, err := json.Marshal(resp)
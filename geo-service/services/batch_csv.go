@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"igaku/geo-service/dtos"
+	igakuErrors "igaku/geo-service/errors"
+)
+
+// ParseBatchCSV reads the addresses of a batch job from a CSV file with a
+// header. Addresses are taken from the 'address' column, or else assembled
+// from the columns named after address components, i.e. 'house_number',
+// 'street', 'city', 'postcode', 'county', 'country' and 'country_code', and
+// searched for with structured search.
+func ParseBatchCSV(r io.Reader) ([]dtos.BatchQuery, error) {
+	reader := csv.NewReader(r)
+	// Spreadsheets leave out trailing empty cells.
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &igakuErrors.InvalidBatchError{
+			Message: "The CSV file is empty",
+		}
+	}
+	if err != nil {
+		return nil, invalidCSV(err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		// Spreadsheets save UTF-8 files with a byte order mark.
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	_, hasAddress := columns["address"]
+	hasComponents := false
+	for _, name := range []string{
+		"house_number", "street", "city", "postcode", "county", "country",
+		"country_code",
+	} {
+		if _, ok := columns[name]; ok {
+			hasComponents = true
+		}
+	}
+	if !hasAddress && !hasComponents {
+		return nil, &igakuErrors.InvalidBatchError{
+			Message: "The CSV file must have an 'address' column " +
+				"or columns of address components",
+		}
+	}
+
+	var queries []dtos.BatchQuery
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, invalidCSV(err)
+		}
+		if len(queries) == BatchMaxRows {
+			return nil, tooManyRows()
+		}
+
+		cell := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		var query dtos.BatchQuery
+		if hasAddress {
+			query.Address = cell("address")
+		} else {
+			query.Components.HouseNumber = cell("house_number")
+			query.Components.Street = cell("street")
+			query.Components.City = cell("city")
+			query.Components.Postcode = cell("postcode")
+			query.Components.County = cell("county")
+			query.Components.Country = cell("country")
+			query.Components.CountryCode = strings.ToLower(cell("country_code"))
+		}
+		queries = append(queries, query)
+	}
+
+	return queries, nil
+}
+
+func invalidCSV(err error) error {
+	return &igakuErrors.InvalidBatchError{
+		Message: fmt.Sprintf("Invalid CSV file: %v", err),
+	}
+}
+
+func tooManyRows() error {
+	return &igakuErrors.InvalidBatchError{
+		Message: fmt.Sprintf(
+			"A batch may have at most %d addresses", BatchMaxRows,
+		),
+	}
+}
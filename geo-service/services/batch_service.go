@@ -0,0 +1,301 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"context"
+	stdErrors "errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"igaku/geo-service/batch"
+	"igaku/geo-service/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/models"
+	"igaku/geo-service/providers"
+	commonDtos "igaku/commons/dtos"
+)
+
+const (
+	// BatchMaxRows is the largest number of addresses of a batch job.
+	BatchMaxRows = 1000
+	// AmbiguityDistanceKm is how far from the best match another match
+	// has to be for the address to be ambiguous. Closer matches are
+	// usually parts of the same place, e.g. a building and its entrance.
+	AmbiguityDistanceKm = 1.0
+	// batchRetries is how many times an address is retried after the
+	// provider timed out or was rate limited.
+	batchRetries = 3
+	// batchStaleAfter is how long a running job may go without progress
+	// before another replica takes it over.
+	batchStaleAfter = 5 * time.Minute
+)
+
+type BatchService interface {
+	// Submit queues a job geocoding the addresses on behalf of the owner.
+	// It fails with InvalidBatchError when there are no addresses or more
+	// than BatchMaxRows.
+	Submit(ownerID string, queries []dtos.BatchQuery) (*models.BatchJob, error)
+	// Get returns a job of the owner, failing with BatchJobNotFoundError
+	// when the owner has no such job.
+	Get(ownerID string, id uuid.UUID) (*models.BatchJob, error)
+	// Results returns the rows of a job of the owner, processed or not.
+	Results(ownerID string, id uuid.UUID) ([]models.BatchRow, error)
+	// ProcessNext processes the next job waiting, one address at a time,
+	// so that the rate limits of the providers are respected. It returns
+	// false when there was no job. Once ctx is done it stops between
+	// addresses, leaving the rest of the job to be taken over later.
+	ProcessNext(ctx context.Context, now time.Time) (bool, error)
+}
+
+type batchService struct {
+	store		batch.Store
+	geoService	GeoService
+	retryDelay	time.Duration
+}
+
+// NewBatchService creates a service geocoding the addresses of jobs with
+// the geocoding service, waiting retryDelay before retrying an address
+// after the provider timed out or was rate limited.
+func NewBatchService(
+	store batch.Store, geoService GeoService, retryDelay time.Duration,
+) BatchService {
+	return &batchService{
+		store: store, geoService: geoService, retryDelay: retryDelay,
+	}
+}
+
+func (s *batchService) Submit(
+	ownerID string, queries []dtos.BatchQuery,
+) (*models.BatchJob, error) {
+	if len(queries) == 0 {
+		return nil, &errors.InvalidBatchError{
+			Message: "At least one address is required",
+		}
+	}
+	if len(queries) > BatchMaxRows {
+		return nil, tooManyRows()
+	}
+
+	now := time.Now()
+	job := &models.BatchJob{
+		ID: uuid.New(),
+		OwnerID: ownerID,
+		Status: models.BatchQueued,
+		Total: len(queries),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	rows := make([]models.BatchRow, len(queries))
+	for i, query := range queries {
+		rows[i] = models.BatchRow{
+			JobID: job.ID,
+			Row: i + 1,
+			Query: strings.TrimSpace(query.Address),
+			Components: query.Components,
+			Status: models.RowPending,
+		}
+		if !query.Components.IsEmpty() {
+			rows[i].Query = providers.JoinAddress(query.Components)
+		}
+	}
+
+	if err := s.store.Create(job, rows); err != nil {
+		log.Printf("Failed to save a batch job: %v", err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (s *batchService) Get(
+	ownerID string, id uuid.UUID,
+) (*models.BatchJob, error) {
+	job, err := s.store.FindJob(id)
+	if err != nil {
+		log.Printf("Failed to find a batch job: %v", err)
+		return nil, err
+	}
+	if job == nil || job.OwnerID != ownerID {
+		return nil, &errors.BatchJobNotFoundError{}
+	}
+
+	if job.Total > 0 {
+		job.Progress = float64(job.Processed) / float64(job.Total)
+	}
+	return job, nil
+}
+
+func (s *batchService) Results(
+	ownerID string, id uuid.UUID,
+) ([]models.BatchRow, error) {
+	if _, err := s.Get(ownerID, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.store.FindRows(id)
+	if err != nil {
+		log.Printf("Failed to find the rows of a batch job: %v", err)
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *batchService) ProcessNext(
+	ctx context.Context, now time.Time,
+) (bool, error) {
+	job, err := s.store.Claim(now, now.Add(-batchStaleAfter))
+	if err != nil || job == nil {
+		return false, err
+	}
+	log.Printf("Processing batch job %s of %d addresses", job.ID, job.Total)
+
+	rows, err := s.store.FindRows(job.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, row := range rows {
+		// Rows processed before the job was taken over are kept.
+		if row.Status != models.RowPending {
+			continue
+		}
+
+		s.geocode(ctx, &row)
+		if row.Status == models.RowPending {
+			log.Printf("Stopped processing batch job %s", job.ID)
+			return false, nil
+		}
+		if err := s.store.SaveRow(&row, time.Now()); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.store.Finish(job.ID, time.Now()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// geocode searches for the address of the row and records the result.
+// The row is left pending when ctx is done before it is geocoded.
+func (s *batchService) geocode(ctx context.Context, row *models.BatchRow) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	if row.Query == "" && row.Components.IsEmpty() {
+		row.Status = models.RowNotFound
+		return
+	}
+
+	var locations []commonDtos.Location
+	var err error
+	for attempt := 0; ; attempt++ {
+		if row.Components.IsEmpty() {
+			locations, err = s.geoService.Search(row.Query)
+		} else {
+			locations, err = s.geoService.SearchStructured(row.Components)
+		}
+
+		transient := stdErrors.Is(err, &errors.TimeoutError{}) ||
+			stdErrors.Is(err, &errors.RateLimitedError{})
+		if !transient || attempt == batchRetries {
+			break
+		}
+		select {
+		case <-time.After(s.retryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	if err != nil {
+		row.Status = models.RowFailed
+		row.Error = err.Error()
+		return
+	}
+	if len(locations) == 0 {
+		row.Status = models.RowNotFound
+		return
+	}
+
+	row.Status = models.RowMatched
+	row.Location = &locations[0]
+	row.Candidates = len(locations)
+	row.Confidence = Confidence(row.Query, locations[0])
+	row.Ambiguous = IsAmbiguous(locations)
+}
+
+// Confidence is the share of the words of the query found in the name or
+// the address of the location, rounded to two decimal places.
+func Confidence(query string, location commonDtos.Location) float64 {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	address := location.Address
+	haystack := strings.ToLower(strings.Join([]string{
+		location.Name, address.HouseNumber, address.Street, address.City,
+		address.Postcode, address.County, address.Country,
+		address.CountryCode,
+	}, " "))
+
+	found := 0
+	for _, word := range words {
+		if strings.Contains(haystack, word) {
+			found++
+		}
+	}
+	return math.Round(float64(found)/float64(len(words))*100) / 100
+}
+
+// IsAmbiguous tells whether any of the locations is farther than
+// AmbiguityDistanceKm from the first one.
+func IsAmbiguous(locations []commonDtos.Location) bool {
+	if len(locations) < 2 {
+		return false
+	}
+
+	lat, latErr := commonDtos.ParseCoordinate(locations[0].Lat)
+	lon, lonErr := commonDtos.ParseCoordinate(locations[0].Lon)
+	if latErr != nil || lonErr != nil {
+		return false
+	}
+
+	for _, other := range locations[1:] {
+		otherLat, latErr := commonDtos.ParseCoordinate(other.Lat)
+		otherLon, lonErr := commonDtos.ParseCoordinate(other.Lon)
+		if latErr != nil || lonErr != nil {
+			continue
+		}
+		distance := distanceKm(
+			float64(lat), float64(lon),
+			float64(otherLat), float64(otherLon),
+		)
+		if distance > AmbiguityDistanceKm {
+			return true
+		}
+	}
+	return false
+}
+
+// distanceKm is the great-circle distance between two points.
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*
+			math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// BatchWorker processes batch jobs in the background, one at a time, so
+// that a replica sends requests for a single job at once and the rate
+// limits of the providers are left to interactive requests as well.
+type BatchWorker struct {
+	service		BatchService
+	interval	time.Duration
+	ctx		context.Context
+	cancel		context.CancelFunc
+	wg		sync.WaitGroup
+}
+
+// NewBatchWorker creates a worker looking for waiting jobs every interval.
+func NewBatchWorker(service BatchService, interval time.Duration) *BatchWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BatchWorker{
+		service: service,
+		interval: interval,
+		ctx: ctx,
+		cancel: cancel,
+	}
+}
+
+func (w *BatchWorker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		log.Printf(" [*] Looking for batch jobs every %s", w.interval)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			w.processWaiting()
+
+			select {
+			case <-ticker.C:
+			case <-w.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the worker between two addresses and waits for it, so
+// that no row is saved after it returns.
+func (w *BatchWorker) Shutdown() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// processWaiting processes jobs until none is waiting.
+func (w *BatchWorker) processWaiting() {
+	for w.ctx.Err() == nil {
+		processed, err := w.service.ProcessNext(w.ctx, time.Now())
+		if err != nil {
+			log.Printf("Failed to process a batch job: %v", err)
+			return
+		}
+		if !processed {
+			return
+		}
+	}
+}
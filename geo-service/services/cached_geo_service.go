@@ -0,0 +1,194 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"igaku/geo-service/cache"
+	commonDtos "igaku/commons/dtos"
+)
+
+// CacheTTL tells how long results are cached. Negative results, i.e.
+// searches and lookups without a result, are usually kept for less time
+// as the place may get mapped.
+type CacheTTL struct {
+	Positive	time.Duration
+	Negative	time.Duration
+}
+
+type cachedGeoService struct {
+	service	GeoService
+	cache	*cache.Cache
+	ttl	CacheTTL
+}
+
+// NewCachedGeoService caches the results of the service, keyed by the
+// normalized query. Errors are never cached.
+func NewCachedGeoService(
+	service GeoService, cache *cache.Cache, ttl CacheTTL,
+) GeoService {
+	return &cachedGeoService{service: service, cache: cache, ttl: ttl}
+}
+
+func (s *cachedGeoService) Search(
+	address string,
+) ([]commonDtos.Location, error) {
+	key := "search:" + normalizeAddress(address)
+
+	var locations []commonDtos.Location
+	if s.get("search", key, &locations) {
+		return locations, nil
+	}
+
+	locations, err := s.service.Search(address)
+	if err != nil {
+		return nil, err
+	}
+
+	s.set(key, locations, len(locations) == 0)
+	return locations, nil
+}
+
+func (s *cachedGeoService) SearchStructured(
+	address commonDtos.Address,
+) ([]commonDtos.Location, error) {
+	key := "structured:" + strings.Join([]string{
+		normalizeAddress(address.HouseNumber),
+		normalizeAddress(address.Street),
+		normalizeAddress(address.City),
+		normalizeAddress(address.Postcode),
+		normalizeAddress(address.County),
+		normalizeAddress(address.Country),
+		normalizeAddress(address.CountryCode),
+	}, "|")
+
+	var locations []commonDtos.Location
+	if s.get("structured_search", key, &locations) {
+		return locations, nil
+	}
+
+	locations, err := s.service.SearchStructured(address)
+	if err != nil {
+		return nil, err
+	}
+
+	s.set(key, locations, len(locations) == 0)
+	return locations, nil
+}
+
+// Autocomplete shares the entries of prefixes differing only in case and
+// spacing, so that the same prefix typed again, e.g. after a correction,
+// is answered from the cache. Short prefixes are not cached, as they have
+// no completions.
+func (s *cachedGeoService) Autocomplete(
+	prefix string, limit int,
+) ([]commonDtos.Location, error) {
+	if IsShortPrefix(prefix) {
+		return s.service.Autocomplete(prefix, limit)
+	}
+
+	limit = AutocompleteLimit(limit)
+	key := fmt.Sprintf("autocomplete:%d:%s", limit, normalizeAddress(prefix))
+
+	var locations []commonDtos.Location
+	if s.get("autocomplete", key, &locations) {
+		return locations, nil
+	}
+
+	locations, err := s.service.Autocomplete(prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.set(key, locations, len(locations) == 0)
+	return locations, nil
+}
+
+func (s *cachedGeoService) Reverse(
+	lat, lon float64,
+) (*commonDtos.Location, error) {
+	// About 1 cm, well below the precision of reverse geocoding.
+	key := fmt.Sprintf(
+		"reverse:%s,%s",
+		commonDtos.Coordinate(lat), commonDtos.Coordinate(lon),
+	)
+
+	var location *commonDtos.Location
+	if s.get("reverse", key, &location) {
+		return location, nil
+	}
+
+	location, err := s.service.Reverse(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	s.set(key, location, location == nil)
+	return location, nil
+}
+
+// Lookup keys the entries by the type and the ID, e.g. lookup:W117853077,
+// and objects of unknown type by the ID alone.
+func (s *cachedGeoService) Lookup(
+	osmType commonDtos.OsmType, id int64,
+) (*commonDtos.Location, error) {
+	key := "lookup:" + commonDtos.FormatOsmID(osmType, id)
+
+	var location *commonDtos.Location
+	if s.get("lookup", key, &location) {
+		return location, nil
+	}
+
+	location, err := s.service.Lookup(osmType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.set(key, location, location == nil)
+	return location, nil
+}
+
+// get decodes the cached result of the key into value, reporting whether
+// there was one. Negative entries leave value zero.
+func (s *cachedGeoService) get(operation, key string, value any) bool {
+	entry, ok := s.cache.Get(operation, key)
+	if !ok {
+		return false
+	}
+	if entry.Negative {
+		return true
+	}
+
+	if err := json.Unmarshal(entry.Value, value); err != nil {
+		log.Printf("Failed to decode geo cache entry '%s': %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (s *cachedGeoService) set(key string, value any, negative bool) {
+	if negative {
+		s.cache.Set(key, nil, s.ttl.Negative)
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Failed to encode geo cache entry '%s': %v", key, err)
+		return
+	}
+	s.cache.Set(key, data, s.ttl.Positive)
+}
+
+var commaRE = regexp.MustCompile(`\s*,\s*`)
+
+// normalizeAddress makes addresses differing only in case and spacing
+// share their cache entry.
+func normalizeAddress(address string) string {
+	address = strings.Join(strings.Fields(strings.ToLower(address)), " ")
+	return commaRE.ReplaceAllString(address, ",")
+}
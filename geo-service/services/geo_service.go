@@ -1,277 +1,163 @@
 package services
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
+	stdErrors "errors"
 	"log"
-	"net/http"
-	"net/url"
-	"os"
-	"strconv"
-	"time"
+	"strings"
+	"unicode/utf8"
 
-	"igaku/geo-service/dtos"
 	"igaku/geo-service/errors"
+	"igaku/geo-service/providers"
 	commonDtos "igaku/commons/dtos"
 )
 
+const (
+	// AutocompleteMinLength is the length of the shortest prefix which is
+	// completed. Shorter prefixes match too many places to be useful, and
+	// would cost a request to the provider on every keystroke.
+	AutocompleteMinLength		= 3
+	AutocompleteDefaultLimit	= 5
+	AutocompleteMaxLimit		= 10
+)
+
 type GeoService interface {
 	Search(address string) ([]commonDtos.Location, error)
-	Reverse(lat, lon string) (*commonDtos.Location, error)
-	Lookup(id int64) (*commonDtos.Location, error)
+	SearchStructured(address commonDtos.Address) ([]commonDtos.Location, error)
+	// Autocomplete returns at most limit places matching the beginning of
+	// an address being typed. Prefixes shorter than AutocompleteMinLength
+	// have no completions.
+	Autocomplete(prefix string, limit int) ([]commonDtos.Location, error)
+	// Reverse returns the place at the coordinates, failing with
+	// InvalidCoordinatesError when they are out of range.
+	Reverse(lat, lon float64) (*commonDtos.Location, error)
+	// Lookup returns the object with the given type and ID. Objects of
+	// unknown type are looked up as nodes, ways and relations in turn.
+	Lookup(osmType commonDtos.OsmType, id int64) (*commonDtos.Location, error)
 }
 
 type geoService struct {
-	nominatimURL string
-	nominatimTimeout time.Duration
+	providers []providers.Provider
 }
 
-func NewGeoService(nominatimURL string) GeoService {
-	t, err := strconv.Atoi(os.Getenv("NOMINATIM_TIMEOUT"))
-	if err != nil || t <= 0 {
-		t = 10
-	}
-	timeout := time.Duration(t) * time.Second
-
-	return &geoService{
-		nominatimURL: nominatimURL,
-		nominatimTimeout: timeout,
-	}
+// NewGeoService creates a service asking the providers in the given order.
+// When a provider fails, by timing out or otherwise, or does not offer the
+// operation, the next one is asked.
+func NewGeoService(providers ...providers.Provider) GeoService {
+	return &geoService{providers: providers}
 }
 
 func (s *geoService) Search(address string) ([]commonDtos.Location, error) {
-	escaped := url.QueryEscape(address)
-
-	requestUrl := fmt.Sprintf(
-		"%s/search?q=%s&format=json",
-		s.nominatimURL, escaped,
+	return withFallback(s.providers, "search",
+		func(p providers.Provider) ([]commonDtos.Location, error) {
+			return p.Search(address)
+		},
 	)
-
-	req, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		log.Printf("Failed to create request: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to create request",
-		}
-	}
-
-	req.Header.Set("User-Agent", "curl/8.17.0")
-	req.Header.Set("Accept", "*/*")
-
-	ctx, cancel := context.WithTimeout(
-		context.Background(), s.nominatimTimeout,
-	)
-	defer cancel()
-	req = req.WithContext(ctx)
-
-	client := &http.Client{}
-	res, err := client.Do(req)
-
-	if err != nil {
-		if err, ok := err.(interface{ Timeout() bool }); ok && err.Timeout() {
-			log.Printf("Request to external API timed out: %v\n", err)
-			return nil, &errors.TimeoutError{}
-		}
-		log.Printf("Failed to connect to external API: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to perform a lookup",
-		}
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != 200 {
-		log.Printf(
-			"Request to external API failed with status code: %d\n",
-			res.StatusCode,
-		)
-		if res.StatusCode == 400 {
-			return nil, &errors.InvalidAddressError{}
-		}
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to perform a lookup",
-		}
-	}
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Printf("Failed to read response body: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to read response body",
-		}
-	}
-
-	var allLocations []dtos.LocationWithType
-	if err := json.Unmarshal(body, &allLocations); err != nil {
-		log.Printf("Failed to parse JSON: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to parse external API response",
-		}
-	}
-
-	var locations []commonDtos.Location
-	for _, loc := range allLocations {
-		if loc.Type != "relation" {
-			locations = append(locations, loc.StripType())
-		}
-	}
-
-	return locations, nil
 }
 
-func (s *geoService) Reverse(lat, lon string) (*commonDtos.Location, error) {
-	requestUrl := fmt.Sprintf(
-		"%s/reverse?lat=%s&lon=%s&format=json",
-		s.nominatimURL, lat, lon,
+func (s *geoService) SearchStructured(
+	address commonDtos.Address,
+) ([]commonDtos.Location, error) {
+	return withFallback(s.providers, "search",
+		func(p providers.Provider) ([]commonDtos.Location, error) {
+			return p.SearchStructured(address)
+		},
 	)
+}
 
-	req, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		log.Printf("Failed to create request: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to create request",
-		}
+func (s *geoService) Autocomplete(
+	prefix string, limit int,
+) ([]commonDtos.Location, error) {
+	prefix = strings.TrimSpace(prefix)
+	if IsShortPrefix(prefix) {
+		return []commonDtos.Location{}, nil
 	}
+	limit = AutocompleteLimit(limit)
 
-	req.Header.Set("User-Agent", "curl/8.17.0")
-	req.Header.Set("Accept", "*/*")
-
-	ctx, cancel := context.WithTimeout(
-		context.Background(), s.nominatimTimeout,
+	return withFallback(s.providers, "autocomplete",
+		func(p providers.Provider) ([]commonDtos.Location, error) {
+			return p.Autocomplete(prefix, limit)
+		},
 	)
-	defer cancel()
-	req = req.WithContext(ctx)
-
-	client := &http.Client{}
-	res, err := client.Do(req)
-
-	if err != nil {
-		if err, ok := err.(interface{ Timeout() bool }); ok && err.Timeout() {
-			log.Printf("Request to external API timed out: %v\n", err)
-			return nil, &errors.TimeoutError{}
-		}
-		log.Printf("Failed to connect to external API: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to perform a lookup",
-		}
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Printf("Failed to read response body: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to read response body",
-		}
-	}
+}
 
-	var errMap map[string]interface{}
-	if json.Unmarshal(body, &errMap) == nil {
-		if errBody, ok := errMap["error"].(map[string]interface{}); ok {
-			if val, ok := errBody["message"].(string); ok {
-				msg := fmt.Sprintf("%v", val)
-				ret := &errors.ExternalApiRequestError{
-					Message: msg,
-				}
-				return nil, ret
-			}
-		} else if val, ok := errMap["error"].(string); ok {
-			msg := fmt.Sprintf("%v", val)
-			return nil, &errors.ExternalApiRequestError{Message: msg}
-		}
-	}
+// IsShortPrefix tells whether the prefix is too short to be completed.
+func IsShortPrefix(prefix string) bool {
+	return utf8.RuneCountInString(strings.TrimSpace(prefix)) <
+		AutocompleteMinLength
+}
 
-	if res.StatusCode != 200 {
-		log.Printf(
-			"Request to external API failed with status code: %d\n",
-			res.StatusCode,
-		)
-		if res.StatusCode == 400 {
-			return nil, &errors.InvalidAddressError{}
-		}
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to perform a lookup",
-		}
+// AutocompleteLimit clamps the number of completions, defaulting to
+// AutocompleteDefaultLimit.
+func AutocompleteLimit(limit int) int {
+	if limit <= 0 {
+		return AutocompleteDefaultLimit
 	}
+	return min(limit, AutocompleteMaxLimit)
+}
 
-	var location commonDtos.Location
-	if err := json.Unmarshal(body, &location); err != nil {
-		log.Printf("Failed to parse JSON: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to parse external API response",
-		}
+func (s *geoService) Reverse(lat, lon float64) (*commonDtos.Location, error) {
+	if !commonDtos.ValidCoordinates(lat, lon) {
+		return nil, &errors.InvalidCoordinatesError{}
 	}
 
-	return &location, nil
+	return withFallback(s.providers, "reverse",
+		func(p providers.Provider) (*commonDtos.Location, error) {
+			return p.Reverse(lat, lon)
+		},
+	)
 }
 
-func (s *geoService) Lookup(id int64) (*commonDtos.Location, error) {
-	requestUrl := fmt.Sprintf(
-		"%s/lookup?osm_ids=N%d,W%d&format=json",
-		s.nominatimURL, id, id,
+func (s *geoService) Lookup(
+	osmType commonDtos.OsmType, id int64,
+) (*commonDtos.Location, error) {
+	return withFallback(s.providers, "lookup",
+		func(p providers.Provider) (*commonDtos.Location, error) {
+			return p.Lookup(osmType, id)
+		},
 	)
+}
 
-	req, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		log.Printf("Failed to create request: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to create request",
+// withFallback performs the operation with the first provider able to.
+// Errors other than failures of the provider, e.g. invalid addresses, are
+// returned right away. When every provider fails, the error of the last
+// one is returned.
+func withFallback[T any](
+	providers []providers.Provider, operation string,
+	call func(providers.Provider) (T, error),
+) (T, error) {
+	var zero T
+	var lastErr error
+	for _, provider := range providers {
+		result, err := call(provider)
+		if err == nil {
+			return result, nil
 		}
-	}
-
-	req.Header.Set("User-Agent", "curl/8.17.0")
-	req.Header.Set("Accept", "*/*")
-
-	ctx, cancel := context.WithTimeout(
-		context.Background(), s.nominatimTimeout,
-	)
-	defer cancel()
-	req = req.WithContext(ctx)
 
-	client := &http.Client{}
-	res, err := client.Do(req)
-
-	if err != nil {
-		if err, ok := err.(interface{ Timeout() bool }); ok && err.Timeout() {
-			log.Printf("Request to external API timed out: %v\n", err)
-			return nil, &errors.TimeoutError{}
+		if stdErrors.Is(err, &errors.UnsupportedOperationError{}) {
+			continue
 		}
-		log.Printf("Failed to connect to external API: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to perform a lookup",
+		if !isProviderFailure(err) {
+			return zero, err
 		}
-	}
-	defer res.Body.Close()
 
-	if res.StatusCode != 200 {
 		log.Printf(
-			"Request to external API failed with status code: %d\n",
-			res.StatusCode,
+			"Provider '%s' failed to %s, trying the next one: %v",
+			provider.Name(), operation, err,
 		)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to perform a lookup",
-		}
-	}
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Printf("Failed to read response body: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to read response body",
-		}
+		lastErr = err
 	}
 
-	var locations []commonDtos.Location
-	if err := json.Unmarshal(body, &locations); err != nil {
-		log.Printf("Failed to parse JSON: %v\n", err)
-		return nil, &errors.ExternalApiRequestError{
-			Message: "Failed to parse external API response",
+	if lastErr == nil {
+		return zero, &errors.ExternalApiRequestError{
+			Message: "No geocoding provider supports the operation",
 		}
 	}
+	return zero, lastErr
+}
 
-	if len(locations) <= 0 {
-		return nil, nil
-	}
-	return &locations[0], nil
+func isProviderFailure(err error) bool {
+	var externErr *errors.ExternalApiRequestError
+	return stdErrors.Is(err, &errors.TimeoutError{}) ||
+		stdErrors.Is(err, &errors.RateLimitedError{}) ||
+		stdErrors.As(err, &externErr)
 }
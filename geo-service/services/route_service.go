@@ -0,0 +1,68 @@
+package services
+
+import (
+	"slices"
+
+	"igaku/geo-service/errors"
+	"igaku/geo-service/providers"
+	commonDtos "igaku/commons/dtos"
+)
+
+type RouteService interface {
+	// Route estimates the routes between the points of the request by
+	// each of its modes, or by all modes the engine offers when none is
+	// given. Modes by which the points are not connected are left out.
+	// It fails with InvalidCoordinatesError when the coordinates are out
+	// of range and with UnsupportedTravelModeError when the engine does
+	// not offer a mode.
+	Route(req commonDtos.RouteRequest) ([]commonDtos.RouteEstimate, error)
+}
+
+type routeService struct {
+	engine providers.RoutingEngine
+}
+
+func NewRouteService(engine providers.RoutingEngine) RouteService {
+	return &routeService{engine: engine}
+}
+
+func (s *routeService) Route(
+	req commonDtos.RouteRequest,
+) ([]commonDtos.RouteEstimate, error) {
+	if !commonDtos.ValidCoordinates(float64(req.FromLat), float64(req.FromLon)) ||
+		!commonDtos.ValidCoordinates(float64(req.ToLat), float64(req.ToLon)) {
+		return nil, &errors.InvalidCoordinatesError{}
+	}
+
+	offered := s.engine.Modes()
+	modes := req.Modes
+	if len(modes) == 0 {
+		modes = offered
+	}
+	for _, mode := range modes {
+		if !slices.Contains(offered, mode) {
+			return nil, &errors.UnsupportedTravelModeError{}
+		}
+	}
+
+	estimates := []commonDtos.RouteEstimate{}
+	for _, mode := range commonDtos.TravelModes {
+		if !slices.Contains(modes, mode) {
+			continue
+		}
+
+		estimate, err := s.engine.Route(
+			mode,
+			float64(req.FromLat), float64(req.FromLon),
+			float64(req.ToLat), float64(req.ToLon),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if estimate != nil {
+			estimates = append(estimates, *estimate)
+		}
+	}
+
+	return estimates, nil
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"igaku/geo-service/controllers"
+	"igaku/geo-service/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/models"
+	"igaku/geo-service/tests/mocks"
+)
+
+func serveBatch(
+	mockService *mocks.BatchService, method, path, contentType string,
+	body io.Reader,
+) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	controllers.NewBatchController(mockService).RegisterRoutes(router)
+
+	req, _ := http.NewRequest(method, path, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", genToken())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBatchController_Submit_JSON(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	job := &models.BatchJob{
+		ID: uuid.New(), Status: models.BatchQueued, Total: 2,
+	}
+	mockService.On("Submit", mock.Anything, []dtos.BatchQuery{
+		{Address: "55 Fruit Street, Boston"}, {Address: "Springfield"},
+	}).Return(job, nil).Once()
+
+	rec := serveBatch(
+		mockService, http.MethodPost, "/geo/batch", "application/json",
+		strings.NewReader(
+			`{"addresses": ["55 Fruit Street, Boston", "Springfield"]}`,
+		),
+	)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "/geo/batch/"+job.ID.String(), rec.Header().Get("Location"))
+	var returned models.BatchJob
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &returned))
+	assert.Equal(t, job.ID, returned.ID)
+	assert.Equal(t, models.BatchQueued, returned.Status)
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchController_Submit_CSV(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	job := &models.BatchJob{ID: uuid.New(), Status: models.BatchQueued}
+	mockService.On("Submit", mock.Anything, []dtos.BatchQuery{
+		{Address: "55 Fruit Street, Boston"},
+	}).Return(job, nil).Once()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "addresses.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("address\n\"55 Fruit Street, Boston\"\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	rec := serveBatch(
+		mockService, http.MethodPost, "/geo/batch",
+		writer.FormDataContentType(), &body,
+	)
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchController_Submit_Invalid(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	mockService.On("Submit", mock.Anything, []dtos.BatchQuery{}).
+		Return(nil, &errors.InvalidBatchError{Message: "No addresses"}).
+		Once()
+
+	for name, body := range map[string]string{
+		"Malformed": `{"addresses": "Boston"}`,
+		"Missing": `{}`,
+		"Empty": `{"addresses": []}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := serveBatch(
+				mockService, http.MethodPost, "/geo/batch",
+				"application/json", strings.NewReader(body),
+			)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
+
+func TestBatchController_Get(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	job := &models.BatchJob{
+		ID: uuid.New(), Status: models.BatchRunning, Total: 4, Processed: 1,
+		Progress: 0.25,
+	}
+	mockService.On("Get", mock.Anything, job.ID).Return(job, nil).Once()
+
+	rec := serveBatch(
+		mockService, http.MethodGet, "/geo/batch/"+job.ID.String(), "", nil,
+	)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var returned models.BatchJob
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &returned))
+	assert.Equal(t, 0.25, returned.Progress)
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchController_Results(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	id := uuid.New()
+	rows := []models.BatchRow{{
+		Row: 1, Query: "Springfield", Status: models.RowMatched,
+		Location: &springfieldIL, Candidates: 2, Confidence: 1,
+		Ambiguous: true,
+	}}
+	mockService.On("Results", mock.Anything, id).Return(rows, nil).Once()
+
+	rec := serveBatch(
+		mockService, http.MethodGet, "/geo/batch/"+id.String()+"/results",
+		"", nil,
+	)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var returned []models.BatchRow
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &returned))
+	assert.Equal(t, rows, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchController_Get_Errors(t *testing.T) {
+	mockService := new(mocks.BatchService)
+	missing := uuid.New()
+	mockService.On("Get", mock.Anything, missing).
+		Return(nil, &errors.BatchJobNotFoundError{}).
+		Once()
+
+	rec := serveBatch(
+		mockService, http.MethodGet, "/geo/batch/"+missing.String(), "", nil,
+	)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serveBatch(mockService, http.MethodGet, "/geo/batch/42", "", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
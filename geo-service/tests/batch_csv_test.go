@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	geoDtos "igaku/geo-service/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/services"
+)
+
+func TestParseBatchCSV_AddressColumn(t *testing.T) {
+	queries, err := services.ParseBatchCSV(strings.NewReader(
+		"name,Address\n" +
+		"Mass General,\"55 Fruit Street, Boston\"\n" +
+		"Unknown\n",
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t, []geoDtos.BatchQuery{
+		{Address: "55 Fruit Street, Boston"},
+		{Address: ""},
+	}, queries)
+}
+
+func TestParseBatchCSV_ComponentColumns(t *testing.T) {
+	queries, err := services.ParseBatchCSV(strings.NewReader(
+		"\ufeffhouse_number,street,city,country_code\n" +
+		"55,Fruit Street,Boston,US\n",
+	))
+	require.NoError(t, err)
+
+	assert.Equal(t, []geoDtos.BatchQuery{{
+		Components: dtos.Address{
+			HouseNumber: "55", Street: "Fruit Street", City: "Boston",
+			CountryCode: "us",
+		},
+	}}, queries)
+}
+
+func TestParseBatchCSV_Invalid(t *testing.T) {
+	tooMany := "address\n" + strings.Repeat(
+		"Boston\n", services.BatchMaxRows+1,
+	)
+
+	for name, csv := range map[string]string{
+		"Empty": "",
+		"NoAddressColumns": "name,phone\nMass General,617-726-2000\n",
+		"Malformed": "address\n\"55 Fruit Street\n",
+		"TooManyRows": tooMany,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := services.ParseBatchCSV(strings.NewReader(csv))
+
+			var invalidErr *errors.InvalidBatchError
+			assert.ErrorAs(t, err, &invalidErr)
+		})
+	}
+
+	_, err := services.ParseBatchCSV(strings.NewReader(tooMany))
+	assert.Contains(t, err.Error(), fmt.Sprint(services.BatchMaxRows))
+}
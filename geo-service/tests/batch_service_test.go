@@ -0,0 +1,304 @@
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/batch"
+	geoDtos "igaku/geo-service/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/models"
+	"igaku/geo-service/services"
+	"igaku/geo-service/tests/mocks"
+)
+
+const batchOwner = "0b6f13da-efb9-4221-9e89-e2729ae90030"
+
+var springfieldIL = dtos.Location{
+	ID: 1, Lat: "39.7990175", Lon: "-89.6439575",
+	Name: "Springfield, Sangamon County, Illinois, United States",
+}
+
+var springfieldMA = dtos.Location{
+	ID: 2, Lat: "42.1018764", Lon: "-72.5886727",
+	Name: "Springfield, Hampden County, Massachusetts, United States",
+}
+
+func submitBatch(
+	t *testing.T, service services.BatchService, queries ...geoDtos.BatchQuery,
+) *models.BatchJob {
+	t.Helper()
+
+	job, err := service.Submit(batchOwner, queries)
+	require.NoError(t, err)
+	return job
+}
+
+func TestBatchService_ProcessNext(t *testing.T) {
+	geoService := new(mocks.GeoService)
+	service := services.NewBatchService(batch.NewMemoryStore(), geoService, 0)
+
+	geoService.On("Search", "55 Fruit Street, Boston").
+		Return([]dtos.Location{massGeneral}, nil).
+		Once()
+	geoService.On("Search", "Springfield").
+		Return([]dtos.Location{springfieldIL, springfieldMA}, nil).
+		Once()
+	geoService.On("Search", "Nowhere").
+		Return([]dtos.Location{}, nil).
+		Once()
+	geoService.On("SearchStructured", dtos.Address{City: "Boston"}).
+		Return(nil, &errors.ExternalApiRequestError{Message: "Failed"}).
+		Once()
+
+	job := submitBatch(t, service,
+		geoDtos.BatchQuery{Address: " 55 Fruit Street, Boston "},
+		geoDtos.BatchQuery{Address: "Springfield"},
+		geoDtos.BatchQuery{Address: "Nowhere"},
+		geoDtos.BatchQuery{Components: dtos.Address{City: "Boston"}},
+		geoDtos.BatchQuery{},
+	)
+	assert.Equal(t, models.BatchQueued, job.Status)
+	assert.Equal(t, 5, job.Total)
+
+	processed, err := service.ProcessNext(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	job, err = service.Get(batchOwner, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchCompleted, job.Status)
+	assert.Equal(t, 5, job.Processed)
+	assert.Equal(t, 1.0, job.Progress)
+	assert.NotNil(t, job.FinishedAt)
+
+	rows, err := service.Results(batchOwner, job.ID)
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+
+	assert.Equal(t, 1, rows[0].Row)
+	assert.Equal(t, models.RowMatched, rows[0].Status)
+	assert.Equal(t, massGeneral.ID, rows[0].Location.ID)
+	assert.False(t, rows[0].Ambiguous)
+
+	assert.Equal(t, models.RowMatched, rows[1].Status)
+	assert.Equal(t, 2, rows[1].Candidates)
+	assert.True(t, rows[1].Ambiguous)
+	assert.Equal(t, 1.0, rows[1].Confidence)
+
+	assert.Equal(t, models.RowNotFound, rows[2].Status)
+
+	assert.Equal(t, "Boston", rows[3].Query)
+	assert.Equal(t, models.RowFailed, rows[3].Status)
+	assert.Equal(t, "Failed", rows[3].Error)
+
+	assert.Equal(t, models.RowNotFound, rows[4].Status)
+	geoService.AssertExpectations(t)
+
+	processed, err = service.ProcessNext(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.False(t, processed, "Expected no job left")
+}
+
+func TestBatchService_RetriesWhenRateLimited(t *testing.T) {
+	geoService := new(mocks.GeoService)
+	service := services.NewBatchService(batch.NewMemoryStore(), geoService, 0)
+
+	geoService.On("Search", "Boston").
+		Return(nil, &errors.RateLimitedError{}).
+		Twice()
+	geoService.On("Search", "Boston").
+		Return([]dtos.Location{massGeneral}, nil).
+		Once()
+	geoService.On("Search", "Cambridge").
+		Return(nil, &errors.TimeoutError{})
+
+	job := submitBatch(t, service,
+		geoDtos.BatchQuery{Address: "Boston"},
+		geoDtos.BatchQuery{Address: "Cambridge"},
+	)
+	_, err := service.ProcessNext(context.Background(), time.Now())
+	require.NoError(t, err)
+
+	rows, err := service.Results(batchOwner, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RowMatched, rows[0].Status)
+	assert.Equal(t, models.RowFailed, rows[1].Status)
+	geoService.AssertNumberOfCalls(t, "Search", 3+4)
+}
+
+func TestBatchService_TakesOverStaleJobs(t *testing.T) {
+	store := batch.NewMemoryStore()
+	geoService := new(mocks.GeoService)
+	service := services.NewBatchService(store, geoService, 0)
+
+	job := submitBatch(t, service,
+		geoDtos.BatchQuery{Address: "Boston"},
+		geoDtos.BatchQuery{Address: "Springfield"},
+	)
+
+	// Another replica claimed the job and processed the first row
+	// before stopping.
+	start := time.Now().Add(-time.Hour)
+	claimed, err := store.Claim(start, start)
+	require.NoError(t, err)
+	require.Equal(t, job.ID, claimed.ID)
+	require.NoError(t, store.SaveRow(&models.BatchRow{
+		JobID: job.ID, Row: 1, Query: "Boston", Status: models.RowNotFound,
+	}, start))
+
+	processed, err := service.ProcessNext(context.Background(), start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, processed, "Expected a recently updated job to be left alone")
+
+	geoService.On("Search", "Springfield").
+		Return([]dtos.Location{springfieldIL}, nil).
+		Once()
+
+	processed, err = service.ProcessNext(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	job, err = service.Get(batchOwner, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchCompleted, job.Status)
+	assert.Equal(t, 2, job.Processed)
+	geoService.AssertExpectations(t)
+}
+
+func TestBatchService_Submit_Invalid(t *testing.T) {
+	service := services.NewBatchService(
+		batch.NewMemoryStore(), new(mocks.GeoService), 0,
+	)
+
+	var invalidErr *errors.InvalidBatchError
+	_, err := service.Submit(batchOwner, nil)
+	assert.ErrorAs(t, err, &invalidErr)
+
+	queries := make([]geoDtos.BatchQuery, services.BatchMaxRows+1)
+	_, err = service.Submit(batchOwner, queries)
+	assert.ErrorAs(t, err, &invalidErr)
+}
+
+func TestBatchService_Get_OtherOwner(t *testing.T) {
+	service := services.NewBatchService(
+		batch.NewMemoryStore(), new(mocks.GeoService), 0,
+	)
+	job := submitBatch(t, service, geoDtos.BatchQuery{Address: "Boston"})
+
+	_, err := service.Get(uuid.NewString(), job.ID)
+	assert.ErrorIs(t, err, &errors.BatchJobNotFoundError{})
+
+	_, err = service.Results(uuid.NewString(), job.ID)
+	assert.ErrorIs(t, err, &errors.BatchJobNotFoundError{})
+
+	_, err = service.Get(batchOwner, uuid.New())
+	assert.ErrorIs(t, err, &errors.BatchJobNotFoundError{})
+}
+
+func TestConfidence(t *testing.T) {
+	location := dtos.Location{
+		Name: "Massachusetts General Hospital, 55, Fruit Street, Boston",
+		Address: dtos.Address{Postcode: "02114"},
+	}
+
+	assert.Equal(t, 1.0, services.Confidence("55 Fruit Street, Boston 02114", location))
+	assert.Equal(t, 0.75, services.Confidence("55 Fruit Street, Cambridge", location))
+	assert.Equal(t, 0.0, services.Confidence("", location))
+}
+
+func TestIsAmbiguous(t *testing.T) {
+	entrance := dtos.Location{Lat: "39.7995", Lon: "-89.6435"}
+
+	assert.False(t, services.IsAmbiguous([]dtos.Location{springfieldIL}))
+	assert.False(t, services.IsAmbiguous(
+		[]dtos.Location{springfieldIL, entrance},
+	))
+	assert.True(t, services.IsAmbiguous(
+		[]dtos.Location{springfieldIL, entrance, springfieldMA},
+	))
+}
+
+func TestMemoryStore_DeleteFinished(t *testing.T) {
+	store := batch.NewMemoryStore()
+	finished := time.Now().Add(-48 * time.Hour)
+	job := &models.BatchJob{ID: uuid.New(), Status: models.BatchQueued}
+	require.NoError(t, store.Create(job, nil))
+	require.NoError(t, store.Finish(job.ID, finished))
+
+	deleted, err := store.DeleteFinished(finished)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	deleted, err = store.DeleteFinished(time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	found, err := store.FindJob(job.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestBatchService_ProcessNext_StopsBetweenRows(t *testing.T) {
+	geoService := new(mocks.GeoService)
+	service := services.NewBatchService(batch.NewMemoryStore(), geoService, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	geoService.On("Search", "Boston").
+		Run(func(mock.Arguments) { cancel() }).
+		Return([]dtos.Location{massGeneral}, nil).
+		Once()
+
+	job := submitBatch(t, service,
+		geoDtos.BatchQuery{Address: "Boston"},
+		geoDtos.BatchQuery{Address: "Springfield"},
+	)
+
+	processed, err := service.ProcessNext(ctx, time.Now())
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	job, err = service.Get(batchOwner, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchRunning, job.Status)
+	assert.Equal(t, 1, job.Processed)
+
+	rows, err := service.Results(batchOwner, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RowMatched, rows[0].Status)
+	assert.Equal(t, models.RowPending, rows[1].Status)
+	geoService.AssertExpectations(t)
+}
+
+func TestBatchWorker_ShutdownWaitsForJob(t *testing.T) {
+	batchService := new(mocks.BatchService)
+	worker := services.NewBatchWorker(batchService, time.Hour)
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	batchService.On("ProcessNext", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-args.Get(0).(context.Context).Done()
+			time.Sleep(50 * time.Millisecond)
+			finished.Store(true)
+		}).
+		Return(false, nil).
+		Once()
+
+	worker.Start()
+	<-started
+	worker.Shutdown()
+
+	assert.True(t, finished.Load(), "Expected the job to stop before Shutdown returned")
+	batchService.AssertExpectations(t)
+}
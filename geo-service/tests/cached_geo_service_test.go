@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/cache"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/services"
+	"igaku/geo-service/tests/mocks"
+)
+
+func newTestCachedGeoService(
+	mockService *mocks.GeoService,
+) (services.GeoService, *cache.Cache) {
+	geoCache := cache.New(cache.NewLRU(100), nil)
+	service := services.NewCachedGeoService(
+		mockService, geoCache,
+		services.CacheTTL{Positive: time.Hour, Negative: time.Minute},
+	)
+	return service, geoCache
+}
+
+func TestCachedGeoService_Search_NormalizesQuery(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	service, geoCache := newTestCachedGeoService(mockService)
+
+	expected := []dtos.Location{
+		{ID: 7093269751, Lat: "35.6835456", Lon: "139.7015260", Name: "代々木"},
+	}
+	mockService.On("Search", "Yoyogi, Tokyo").Return(expected, nil).Once()
+
+	locations, err := service.Search("Yoyogi, Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, expected, locations)
+
+	locations, err = service.Search("  yoyogi,tokyo ")
+	require.NoError(t, err)
+	assert.Equal(t, expected, locations)
+
+	mockService.AssertExpectations(t)
+	search := geoCache.Metrics().Operations["search"]
+	assert.EqualValues(t, 1, search.MemoryHits)
+	assert.EqualValues(t, 1, search.Misses)
+}
+
+func TestCachedGeoService_Autocomplete(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	service, geoCache := newTestCachedGeoService(mockService)
+
+	expected := []dtos.Location{{ID: 1, Name: "Boston"}}
+	mockService.On("Autocomplete", "Bost", 5).Return(expected, nil).Once()
+	mockService.On("Autocomplete", "Bo", 5).Return([]dtos.Location{}, nil)
+
+	for _, prefix := range []string{"Bost", " bost"} {
+		locations, err := service.Autocomplete(prefix, 5)
+		require.NoError(t, err)
+		assert.Equal(t, expected, locations)
+	}
+	for i := 0; i < 2; i++ {
+		_, err := service.Autocomplete("Bo", 5)
+		require.NoError(t, err)
+	}
+
+	mockService.AssertNumberOfCalls(t, "Autocomplete", 3)
+	autocomplete := geoCache.Metrics().Operations["autocomplete"]
+	assert.EqualValues(t, 1, autocomplete.MemoryHits)
+	assert.EqualValues(t, 1, autocomplete.Misses)
+}
+
+func TestCachedGeoService_Lookup_NegativeCaching(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	service, geoCache := newTestCachedGeoService(mockService)
+
+	mockService.On("Lookup", dtos.OsmType(""), int64(1)).Return(nil, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		location, err := service.Lookup("", 1)
+		require.NoError(t, err)
+		assert.Nil(t, location)
+	}
+
+	mockService.AssertExpectations(t)
+	assert.EqualValues(t, 1, geoCache.Metrics().Operations["lookup"].NegativeHits)
+}
+
+func TestCachedGeoService_ErrorsAreNotCached(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	service, _ := newTestCachedGeoService(mockService)
+
+	location := &dtos.Location{ID: 1, Lat: "40.7579554", Lon: "-73.9855319"}
+	mockService.
+		On("Reverse", 40.7579554, -73.9855319).
+		Return(nil, &errors.TimeoutError{}).
+		Once()
+	mockService.
+		On("Reverse", 40.7579554, -73.9855319).
+		Return(location, nil).
+		Once()
+
+	_, err := service.Reverse(40.7579554, -73.9855319)
+	assert.ErrorIs(t, err, &errors.TimeoutError{})
+
+	res, err := service.Reverse(40.7579554, -73.9855319)
+	require.NoError(t, err)
+	assert.Equal(t, location, res)
+
+	// Points closer than the precision of coordinates share the entry.
+	res, err = service.Reverse(40.757955401, -73.985531899)
+	require.NoError(t, err)
+	assert.Equal(t, location, res)
+	mockService.AssertExpectations(t)
+}
+
+func TestCachedGeoService_Reverse_NegativeCaching(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	service, geoCache := newTestCachedGeoService(mockService)
+
+	mockService.On("Reverse", 0.0, -30.0).Return(nil, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		location, err := service.Reverse(0, -30)
+		require.NoError(t, err)
+		assert.Nil(t, location)
+	}
+
+	mockService.AssertExpectations(t)
+	assert.EqualValues(t, 1, geoCache.Metrics().Operations["reverse"].NegativeHits)
+}
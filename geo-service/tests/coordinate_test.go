@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+)
+
+func TestCoordinate_SerializedAsString(t *testing.T) {
+	data, err := json.Marshal(dtos.ReverseGeocodeRequest{
+		Lat: 40.7579554, Lon: -73.98553,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"lat":"40.7579554","lon":"-73.9855300"}`, string(data))
+}
+
+func TestCoordinate_DeserializedFromStringsAndNumbers(t *testing.T) {
+	var req dtos.ReverseGeocodeRequest
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"lat":"40.7579554","lon":-73.9855319}`), &req,
+	))
+	assert.Equal(t, dtos.Coordinate(40.7579554), req.Lat)
+	assert.Equal(t, dtos.Coordinate(-73.9855319), req.Lon)
+
+	for _, body := range []string{
+		`{"lat":"foo","lon":"0"}`,
+		`{"lat":"NaN","lon":"0"}`,
+		`{"lat":"0","lon":"1&format=xml"}`,
+		`{"lat":null,"lon":"0"}`,
+	} {
+		assert.Error(t, json.Unmarshal([]byte(body), &req), body)
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/providers"
+)
+
+const gazetteer = `osm_id,lat,lon,name
+7093269751,35.6835456,139.7015260,"Yoyogi Station, Shibuya, Tokyo, Japan"
+2558121954,35.6580339,139.7016358,"Shibuya Station, Shibuya, Tokyo, Japan"
+W117853077,42.3628605,-71.0687530,"Massachusetts General Hospital, Boston, United States"
+R117853077,42.3628605,-71.0687530,"Massachusetts General Hospital campus, Boston, United States"
+`
+
+func newTestGazetteer(t *testing.T) providers.Provider {
+	path := filepath.Join(t.TempDir(), "gazetteer.csv")
+	require.NoError(t, os.WriteFile(path, []byte(gazetteer), 0o644))
+
+	provider, err := providers.NewGazetteerProvider(path)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestGazetteerProvider_Search(t *testing.T) {
+	provider := newTestGazetteer(t)
+
+	locations, err := provider.Search("station, SHIBUYA")
+	require.NoError(t, err)
+	assert.Len(t, locations, 2)
+
+	locations, err = provider.Search("yoyogi station")
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, int64(7093269751), locations[0].ID)
+}
+
+func TestGazetteerProvider_Autocomplete(t *testing.T) {
+	provider := newTestGazetteer(t)
+
+	locations, err := provider.Autocomplete("stat", 1)
+	require.NoError(t, err)
+	assert.Len(t, locations, 1)
+
+	locations, err = provider.Autocomplete("General Hospital camp", 5)
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, dtos.OsmRelation, locations[0].OsmType)
+}
+
+func TestGazetteerProvider_Reverse_ReturnsNearestPlace(t *testing.T) {
+	provider := newTestGazetteer(t)
+
+	location, err := provider.Reverse(35.6590, 139.7005)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2558121954), location.ID)
+}
+
+func TestGazetteerProvider_Lookup(t *testing.T) {
+	provider := newTestGazetteer(t)
+
+	location, err := provider.Lookup(dtos.OsmWay, 117853077)
+	require.NoError(t, err)
+	require.NotNil(t, location)
+	assert.Equal(t, "42.3628605", location.Lat)
+	assert.Equal(t, dtos.OsmWay, location.OsmType)
+
+	location, err = provider.Lookup(dtos.OsmRelation, 117853077)
+	require.NoError(t, err)
+	require.NotNil(t, location)
+	assert.Equal(t, dtos.OsmRelation, location.OsmType)
+
+	location, err = provider.Lookup(dtos.OsmNode, 117853077)
+	require.NoError(t, err)
+	assert.Nil(t, location)
+
+	location, err = provider.Lookup("", 7093269751)
+	require.NoError(t, err)
+	require.NotNil(t, location)
+	assert.Empty(t, location.OsmType)
+
+	location, err = provider.Lookup("", 1)
+	require.NoError(t, err)
+	assert.Nil(t, location)
+}
+
+func TestGazetteerProvider_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gazetteer.csv")
+	require.NoError(t, os.WriteFile(
+		path, []byte("osm_id,lat,lon,name\nx,1,2,Nowhere\n"), 0o644,
+	))
+
+	_, err := providers.NewGazetteerProvider(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/geo-service/cache"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	lru := cache.NewLRU(2)
+	now := time.Now()
+	expires := now.Add(time.Hour)
+
+	lru.Set("a", cache.Entry{Value: []byte("1"), ExpiresAt: expires})
+	lru.Set("b", cache.Entry{Value: []byte("2"), ExpiresAt: expires})
+	entry, _ := lru.Get("a", now)
+	require.NotNil(t, entry)
+	lru.Set("c", cache.Entry{Value: []byte("3"), ExpiresAt: expires})
+
+	assert.Equal(t, 2, lru.Len())
+	entry, _ = lru.Get("b", now)
+	assert.Nil(t, entry, "Expected the least recently used entry evicted")
+	entry, _ = lru.Get("a", now)
+	assert.NotNil(t, entry)
+	entry, _ = lru.Get("c", now)
+	assert.NotNil(t, entry)
+}
+
+func TestLRU_Expiry(t *testing.T) {
+	lru := cache.NewLRU(10)
+	now := time.Now()
+
+	lru.Set("a", cache.Entry{Value: []byte("1"), ExpiresAt: now.Add(time.Minute)})
+
+	entry, _ := lru.Get("a", now)
+	assert.NotNil(t, entry)
+	entry, _ = lru.Get("a", now.Add(time.Minute))
+	assert.Nil(t, entry)
+	assert.Equal(t, 0, lru.Len())
+}
+
+func TestCache_PersistentHitIsCopiedToMemory(t *testing.T) {
+	memory := cache.NewLRU(10)
+	persistent := cache.NewLRU(10)
+	persistent.Set("lookup:1", cache.Entry{
+		Value: []byte("{}"), ExpiresAt: time.Now().Add(time.Hour),
+	})
+	c := cache.New(memory, persistent)
+
+	_, ok := c.Get("lookup", "lookup:1")
+	require.True(t, ok)
+	_, ok = c.Get("lookup", "lookup:1")
+	require.True(t, ok)
+	_, ok = c.Get("lookup", "lookup:2")
+	require.False(t, ok)
+
+	metrics := c.Metrics()
+	assert.True(t, metrics.Persistent)
+	lookup := metrics.Operations["lookup"]
+	assert.EqualValues(t, 1, lookup.PersistentHits)
+	assert.EqualValues(t, 1, lookup.MemoryHits)
+	assert.EqualValues(t, 1, lookup.Misses)
+}
+
+func TestCache_NegativeEntries(t *testing.T) {
+	c := cache.New(cache.NewLRU(10), nil)
+
+	c.Set("search:nowhere", nil, time.Hour)
+	// Nothing is cached without a TTL.
+	c.Set("search:elsewhere", []byte("[]"), 0)
+
+	entry, ok := c.Get("search", "search:nowhere")
+	require.True(t, ok)
+	assert.True(t, entry.Negative)
+	_, ok = c.Get("search", "search:elsewhere")
+	assert.False(t, ok)
+
+	search := c.Metrics().Operations["search"]
+	assert.EqualValues(t, 1, search.NegativeHits)
+	assert.EqualValues(t, 1, search.Misses)
+}
@@ -7,11 +7,15 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"igaku/commons/dtos"
+	"igaku/commons/models"
+	"igaku/commons/utils"
 	"igaku/geo-service/controllers"
 	"igaku/geo-service/errors"
 	"igaku/geo-service/tests/mocks"
@@ -27,6 +31,15 @@ func setupGeoRouter(t *testing.T, mockGeoService *mocks.GeoService) *gin.Engine
 	return router
 }
 
+// genToken returns a token of a patient, as any user may geocode.
+func genToken() string {
+	token, _ := utils.GenerateJWTToken(
+		&models.User{Role: models.Patient},
+		time.Now(), time.Now().Add(time.Hour),
+	)
+	return token
+}
+
 func TestGeoController_Search_SingleResult(t *testing.T) {
 	mockService := new(mocks.GeoService)
 	router := setupGeoRouter(t, mockService)
@@ -157,6 +170,32 @@ func TestGeoController_Search_Timeout(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGeoController_Search_RateLimited(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	address := "sakura-dori,shibuya"
+	mockService.
+		On("Search", address).
+		Return(nil, &errors.RateLimitedError{}).
+		Once()
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("/geo/search/%s", address),
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestGeoController_Search_ExtraParams(t *testing.T) {
 	mockService := new(mocks.GeoService)
 	router := setupGeoRouter(t, mockService)
@@ -220,3 +259,208 @@ func TestGeoController_Search_InternalError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestGeoController_SearchStructured(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	address := dtos.Address{Street: "Fruit Street", City: "Boston"}
+	locations := []dtos.Location{
+		{ID: 117853077, Lat: "42.3628605", Lon: "-71.0687530", Address: address},
+	}
+	mockService.
+		On("SearchStructured", address).
+		Return(locations, nil).
+		Once()
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"/geo/search?street=Fruit+Street&city=Boston",
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body []dtos.Location
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, locations, body)
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoController_SearchStructured_NoComponents(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	req, err := http.NewRequest(http.MethodGet, "/geo/search", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "SearchStructured", mock.Anything)
+}
+
+func TestGeoController_Reverse(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	location := &dtos.Location{
+		ID: 117853077, Lat: "42.3628605", Lon: "-71.0687530",
+	}
+	mockService.On("Reverse", 42.3628605, -71.068753).
+		Return(location, nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/geo/reverse?lat=42.3628605&lon=-71.068753", nil,
+	)
+	req.Header.Set("Authorization", genToken())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response dtos.Location
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, *location, response)
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoController_Reverse_InvalidCoordinates(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	mockService.On("Reverse", 91.0, 0.0).
+		Return(nil, &errors.InvalidCoordinatesError{}).
+		Once()
+
+	for _, query := range []string{"lat=91&lon=0", "lat=north&lon=0", "lat=1"} {
+		req, _ := http.NewRequest(
+			http.MethodGet, "/geo/reverse?"+query, nil,
+		)
+		req.Header.Set("Authorization", genToken())
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoController_Reverse_NothingAround(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	mockService.On("Reverse", 0.0, -30.0).Return(nil, nil).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/geo/reverse?lat=0&lon=-30", nil)
+	req.Header.Set("Authorization", genToken())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoController_Lookup(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	location := &dtos.Location{
+		ID: 117853077,
+		OsmType: dtos.OsmWay,
+		Name: "Massachusetts General Hospital",
+	}
+	mockService.On("Lookup", dtos.OsmWay, int64(117853077)).
+		Return(location, nil).
+		Once()
+	mockService.On("Lookup", dtos.OsmType(""), int64(1)).
+		Return(nil, nil).
+		Once()
+
+	for path, code := range map[string]int{
+		"/geo/lookup/W117853077": http.StatusOK,
+		"/geo/lookup/1": http.StatusNotFound,
+		"/geo/lookup/abc": http.StatusBadRequest,
+		"/geo/lookup/X1": http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", genToken())
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, code, rec.Code, path)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoController_Authentication(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+	v2Router := setupGeoV2Router(mockService)
+
+	for _, path := range []string{
+		"/geo/reverse?lat=0&lon=0",
+		"/geo/lookup/1",
+		"/geo/autocomplete?q=Boston",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+	}
+	for _, path := range []string{
+		"/geo/v2/reverse?lat=0&lon=0",
+		"/geo/v2/lookup/1",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "invalid")
+		rec := httptest.NewRecorder()
+		v2Router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+	}
+	mockService.AssertNotCalled(t, "Reverse", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "Lookup", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "Autocomplete", mock.Anything, mock.Anything)
+}
+
+func TestGeoController_Autocomplete(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoRouter(t, mockService)
+
+	mockService.On("Autocomplete", "Bost", 3).
+		Return([]dtos.Location{{ID: 1, Name: "Boston"}}, nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/geo/autocomplete?q=Bost&limit=3", nil,
+	)
+	req.Header.Set("Authorization", genToken())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Cache-Control"), "max-age")
+	var locations []dtos.Location
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &locations))
+	assert.Len(t, locations, 1)
+
+	req, _ = http.NewRequest(
+		http.MethodGet, "/geo/autocomplete?q=Bost&limit=-1", nil,
+	)
+	req.Header.Set("Authorization", genToken())
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -1,84 +1,147 @@
 package tests
 
 import (
-	"encoding/json"
-	"net/http"
-	"net/http/httptest"
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
-	"igaku/geo-service/dtos"
+	"igaku/commons/dtos"
+	"igaku/geo-service/errors"
 	"igaku/geo-service/services"
-	commonDtos "igaku/commons/dtos"
+	"igaku/geo-service/tests/mocks"
 )
 
-var server *httptest.Server
-
-func TestMain(m *testing.M) {
-	server = httptest.NewServer(
-		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			address := r.URL.Query().Get("q")
-
-			if address == "yoyogi,tokyo" {
-				w.Header().Set(
-					"Content-Type", "application/json",
-				)
-				json.NewEncoder(w).Encode([]dtos.LocationWithType{
-					{
-						Location: commonDtos.Location{
-							ID: 17054674,
-							Lat: "35.6823040",
-							Lon: "139.6917362",
-							Name: "代々木, 渋谷区, 東京都, 151-0053, 日本",
-						},
-						Type: "relation",
-					},
-					{
-						Location: commonDtos.Location{
-							ID: 7093269751,
-							Lat: "35.6835456",
-							Lon: "139.7015260",
-							Name: "代々木, 四谷角筈線, 代々木一丁目, 代々木, 渋谷区, 東京都, 151-0053, 日本",
-						},
-						Type: "node",
-					},
-					{
-						Location: commonDtos.Location{
-							ID: 2558121954,
-							Lat: "35.6839514",
-							Lon: "139.7020806",
-							Name: "代々木, 代々木駅(北口), 代々木一丁目, 代々木, 渋谷区, 東京都, 151-0053,  日本",
-						},
-						Type: "node",
-					},
-				})
-			}
-		}),
-	)
-	defer server.Close()
-
-	exitCode := m.Run()
-
-	os.Exit(exitCode)
+func TestGeoService_FallsBackWhenProviderTimesOut(t *testing.T) {
+	first := &mocks.Provider{ProviderName: "first"}
+	second := &mocks.Provider{ProviderName: "second"}
+	service := services.NewGeoService(first, second)
+
+	location := &dtos.Location{ID: 7, Lat: "35.6", Lon: "139.7"}
+	first.On("Reverse", 35.6, 139.7).
+		Return(nil, &errors.TimeoutError{}).
+		Once()
+	second.On("Reverse", 35.6, 139.7).
+		Return(location, nil).
+		Once()
+
+	result, err := service.Reverse(35.6, 139.7)
+	require.NoError(t, err)
+	assert.Equal(t, location, result)
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
 }
 
-func TestGeoService_Search_FiltersOutRelationItems(t *testing.T) {
-	service := services.NewGeoService(server.URL)
+func TestGeoService_SkipsProvidersNotSupportingOperation(t *testing.T) {
+	first := &mocks.Provider{ProviderName: "first"}
+	second := &mocks.Provider{ProviderName: "second"}
+	service := services.NewGeoService(first, second)
+
+	first.On("Lookup", dtos.OsmWay, int64(7)).
+		Return(nil, &errors.UnsupportedOperationError{}).
+		Once()
+	second.On("Lookup", dtos.OsmWay, int64(7)).
+		Return(nil, nil).
+		Once()
 
-	locations, err := service.Search("yoyogi,tokyo")
+	result, err := service.Lookup(dtos.OsmWay, 7)
 	require.NoError(t, err)
+	assert.Nil(t, result)
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
+}
+
+func TestGeoService_DoesNotFallBackOnInvalidAddress(t *testing.T) {
+	first := &mocks.Provider{ProviderName: "first"}
+	second := &mocks.Provider{ProviderName: "second"}
+	service := services.NewGeoService(first, second)
+
+	first.On("Search", "?").
+		Return(nil, &errors.InvalidAddressError{}).
+		Once()
+
+	_, err := service.Search("?")
+	assert.ErrorIs(t, err, &errors.InvalidAddressError{})
+	first.AssertExpectations(t)
+	second.AssertNotCalled(t, "Search", "?")
+}
+
+func TestGeoService_ReturnsLastErrorWhenAllProvidersFail(t *testing.T) {
+	first := &mocks.Provider{ProviderName: "first"}
+	second := &mocks.Provider{ProviderName: "second"}
+	service := services.NewGeoService(first, second)
+
+	first.On("Search", "shibuya").
+		Return(nil, &errors.TimeoutError{}).
+		Once()
+	second.On("Search", "shibuya").
+		Return(nil, &errors.ExternalApiRequestError{Message: "Down"}).
+		Once()
+
+	_, err := service.Search("shibuya")
+	var externErr *errors.ExternalApiRequestError
+	require.ErrorAs(t, err, &externErr)
+	assert.Equal(t, "Down", externErr.Message)
+}
+
+func TestGeoService_NoProviderSupportsOperation(t *testing.T) {
+	only := &mocks.Provider{ProviderName: "only"}
+	service := services.NewGeoService(only)
 
-	require.NotEmpty(t, locations)
+	only.On("Lookup", dtos.OsmWay, int64(7)).
+		Return(nil, &errors.UnsupportedOperationError{}).
+		Once()
 
-	var ids []int64;
-	for _, loc := range locations {
-		ids = append(ids, loc.ID)
+	_, err := service.Lookup(dtos.OsmWay, 7)
+	var externErr *errors.ExternalApiRequestError
+	assert.ErrorAs(t, err, &externErr)
+}
+
+func TestGeoService_Reverse_RejectsCoordinatesOutOfRange(t *testing.T) {
+	provider := &mocks.Provider{ProviderName: "provider"}
+	service := services.NewGeoService(provider)
+
+	for _, coords := range [][2]float64{{90.1, 0}, {-91, 0}, {0, 180.5}, {0, -181}} {
+		_, err := service.Reverse(coords[0], coords[1])
+		assert.ErrorIs(t, err, &errors.InvalidCoordinatesError{})
 	}
+	provider.AssertNotCalled(t, "Reverse", mock.Anything, mock.Anything)
+}
 
-	assert.Contains(t, ids, int64(7093269751))
-	assert.Contains(t, ids, int64(2558121954))
-	assert.NotContains(t, ids, int64(17054674))
+func TestGeoService_Autocomplete_SkipsShortPrefixes(t *testing.T) {
+	provider := &mocks.Provider{ProviderName: "first"}
+	service := services.NewGeoService(provider)
+
+	for _, prefix := range []string{"", "Bo", "  代々 "} {
+		locations, err := service.Autocomplete(prefix, 5)
+		require.NoError(t, err)
+		assert.Empty(t, locations)
+	}
+	provider.AssertNotCalled(t, "Autocomplete", mock.Anything, mock.Anything)
+}
+
+func TestGeoService_Autocomplete_LimitsAndFallsBack(t *testing.T) {
+	first := &mocks.Provider{ProviderName: "first"}
+	second := &mocks.Provider{ProviderName: "second"}
+	service := services.NewGeoService(first, second)
+
+	expected := []dtos.Location{{ID: 1, Name: "Boston"}}
+	first.On("Autocomplete", mock.Anything, mock.Anything).
+		Return(nil, &errors.UnsupportedOperationError{})
+	second.On("Autocomplete", "Bost", services.AutocompleteMaxLimit).
+		Return(expected, nil).
+		Once()
+	second.On("Autocomplete", "Bost", services.AutocompleteDefaultLimit).
+		Return(expected, nil).
+		Once()
+
+	locations, err := service.Autocomplete(" Bost ", 100)
+	require.NoError(t, err)
+	assert.Equal(t, expected, locations)
+
+	locations, err = service.Autocomplete("Bost", 0)
+	require.NoError(t, err)
+	assert.Equal(t, expected, locations)
+	second.AssertExpectations(t)
 }
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/controllers"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/tests/mocks"
+	geoDtos "igaku/geo-service/dtos"
+)
+
+var massGeneral = dtos.Location{
+	ID: 117853077,
+	Lat: "42.3628605",
+	Lon: "-71.0687530",
+	Name: "Massachusetts General Hospital, 55, Fruit Street, Boston",
+	Address: dtos.Address{City: "Boston"},
+}
+
+func setupGeoV2Router(mockGeoService *mocks.GeoService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.Default()
+	controllers.NewGeoV2Controller(mockGeoService).RegisterRoutes(router)
+	return router
+}
+
+// serveGeoV2 sends an authenticated request to the router.
+func serveGeoV2(router *gin.Engine, url string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Authorization", genToken())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGeoV2Controller_Search_NumericCoordinates(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoV2Router(mockService)
+
+	mockService.
+		On("Search", "boston").
+		Return([]dtos.Location{massGeneral}, nil).
+		Once()
+
+	rec := serveGeoV2(router, "/geo/v2/search/boston")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body []map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body, 1)
+	assert.Equal(t, 42.3628605, body[0]["lat"])
+	assert.Equal(t, -71.068753, body[0]["lon"])
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoV2Controller_Search_GeoJSON(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoV2Router(mockService)
+
+	mockService.
+		On("Search", "boston").
+		Return([]dtos.Location{massGeneral}, nil).
+		Once()
+
+	rec := serveGeoV2(router, "/geo/v2/search/boston?format=geojson")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "application/geo+json")
+	var body geoDtos.GeoJSONFeatureCollection
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "FeatureCollection", body.Type)
+	require.Len(t, body.Features, 1)
+	assert.Equal(t, "Point", body.Features[0].Geometry.Type)
+	assert.Equal(
+		t, []float64{-71.068753, 42.3628605},
+		body.Features[0].Geometry.Coordinates,
+		"Expected the longitude first",
+	)
+	assert.Equal(t, int64(117853077), body.Features[0].Properties.ID)
+	assert.Equal(t, "Boston", body.Features[0].Properties.Address.City)
+}
+
+func TestGeoV2Controller_Search_UnknownFormat(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoV2Router(mockService)
+
+	rec := serveGeoV2(router, "/geo/v2/search/boston?format=kml")
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertNotCalled(t, "Search", mock.Anything)
+}
+
+func TestGeoV2Controller_Reverse(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoV2Router(mockService)
+
+	mockService.
+		On("Reverse", 42.3628605, -71.068753).
+		Return(&massGeneral, nil).
+		Once()
+
+	rec := serveGeoV2(router, "/geo/v2/reverse?lat=42.3628605&lon=-71.068753&format=geojson")
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body geoDtos.GeoJSONFeature
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Feature", body.Type)
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoV2Controller_Reverse_InvalidCoordinates(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoV2Router(mockService)
+
+	mockService.
+		On("Reverse", 91.0, 0.0).
+		Return(nil, &errors.InvalidCoordinatesError{}).
+		Once()
+
+	for _, url := range []string{
+		"/geo/v2/reverse?lat=abc&lon=0",
+		"/geo/v2/reverse?lat=NaN&lon=0",
+		"/geo/v2/reverse?lon=0",
+		"/geo/v2/reverse?lat=91&lon=0",
+	} {
+		rec := serveGeoV2(router, url)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, url)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGeoV2Controller_Lookup_NotFound(t *testing.T) {
+	mockService := new(mocks.GeoService)
+	router := setupGeoV2Router(mockService)
+
+	mockService.
+		On("Lookup", dtos.OsmRelation, int64(1)).
+		Return(nil, nil).
+		Once()
+
+	rec := serveGeoV2(router, "/geo/v2/lookup/R1")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serveGeoV2(router, "/geo/v2/lookup/abc")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockService.AssertExpectations(t)
+}
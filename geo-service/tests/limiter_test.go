@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+)
+
+func TestTokenBucket_AllowsBurst(t *testing.T) {
+	bucket := limiter.NewTokenBucket(time.Hour, 3)
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, bucket.Wait(context.Background()))
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestTokenBucket_QueuesRequests(t *testing.T) {
+	interval := 50 * time.Millisecond
+	bucket := limiter.NewTokenBucket(interval, 1)
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, bucket.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 2*interval)
+}
+
+func TestTokenBucket_RejectsRequestsPastDeadline(t *testing.T) {
+	interval := 200 * time.Millisecond
+	bucket := limiter.NewTokenBucket(interval, 1)
+	require.NoError(t, bucket.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), interval/2,
+	)
+	defer cancel()
+
+	start := time.Now()
+	assert.ErrorIs(t, bucket.Wait(ctx), &errors.RateLimitedError{})
+	assert.Less(t, time.Since(start), interval/4,
+		"Expected the request rejected without waiting")
+
+	// A rejected request does not hold back the next one.
+	require.NoError(t, bucket.Wait(context.Background()))
+	assert.Less(t, time.Since(start), 2*interval)
+}
+
+func TestTokenBucket_CancelledRequestReleasesToken(t *testing.T) {
+	interval := 200 * time.Millisecond
+	bucket := limiter.NewTokenBucket(interval, 1)
+	require.NoError(t, bucket.Wait(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queued := make(chan error)
+	go func() { queued <- bucket.Wait(ctx) }()
+	time.Sleep(interval / 4)
+	cancel()
+	assert.ErrorIs(t, <-queued, context.Canceled)
+
+	// The next request takes the place of the cancelled one instead of
+	// queuing behind it.
+	start := time.Now()
+	require.NoError(t, bucket.Wait(context.Background()))
+	assert.Less(t, time.Since(start), interval)
+}
@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/geo-service/dtos"
+	"igaku/geo-service/models"
+)
+
+type BatchService struct {
+	mock.Mock
+}
+
+func (m *BatchService) Submit(
+	ownerID string, queries []dtos.BatchQuery,
+) (*models.BatchJob, error) {
+	args := m.Called(ownerID, queries)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BatchJob), nil
+}
+
+func (m *BatchService) Get(
+	ownerID string, id uuid.UUID,
+) (*models.BatchJob, error) {
+	args := m.Called(ownerID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BatchJob), nil
+}
+
+func (m *BatchService) Results(
+	ownerID string, id uuid.UUID,
+) ([]models.BatchRow, error) {
+	args := m.Called(ownerID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BatchRow), nil
+}
+
+func (m *BatchService) ProcessNext(
+	ctx context.Context, now time.Time,
+) (bool, error) {
+	args := m.Called(ctx, now)
+	return args.Bool(0), args.Error(1)
+}
@@ -14,27 +14,47 @@ func (m *GeoService) Search(
 ) ([]dtos.Location, error) {
 	args := m.Called(address)
 	if args.Get(0) == nil {
-		return nil, args.Get(1).(error)
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dtos.Location), nil
+}
+
+func (m *GeoService) SearchStructured(
+	address dtos.Address,
+) ([]dtos.Location, error) {
+	args := m.Called(address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dtos.Location), nil
+}
+
+func (m *GeoService) Autocomplete(
+	prefix string, limit int,
+) ([]dtos.Location, error) {
+	args := m.Called(prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
 	return args.Get(0).([]dtos.Location), nil
 }
 
 func (m *GeoService) Reverse(
-	lat, lon string,
+	lat, lon float64,
 ) (*dtos.Location, error) {
 	args := m.Called(lat, lon)
 	if args.Get(0) == nil {
-		return nil, args.Get(1).(error)
+		return nil, args.Error(1)
 	}
 	return args.Get(0).(*dtos.Location), nil
 }
 
 func (m *GeoService) Lookup(
-	id int64,
+	osmType dtos.OsmType, id int64,
 ) (*dtos.Location, error) {
-	args := m.Called(id)
+	args := m.Called(osmType, id)
 	if args.Get(0) == nil {
-		return nil, args.Get(1).(error)
+		return nil, args.Error(1)
 	}
 	return args.Get(0).(*dtos.Location), nil
 }
@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"igaku/commons/dtos"
+)
+
+type Provider struct {
+	mock.Mock
+	ProviderName string
+}
+
+func (m *Provider) Name() string {
+	return m.ProviderName
+}
+
+func (m *Provider) Search(
+	address string,
+) ([]dtos.Location, error) {
+	args := m.Called(address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dtos.Location), nil
+}
+
+func (m *Provider) SearchStructured(
+	address dtos.Address,
+) ([]dtos.Location, error) {
+	args := m.Called(address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dtos.Location), nil
+}
+
+func (m *Provider) Autocomplete(
+	prefix string, limit int,
+) ([]dtos.Location, error) {
+	args := m.Called(prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dtos.Location), nil
+}
+
+func (m *Provider) Reverse(
+	lat, lon float64,
+) (*dtos.Location, error) {
+	args := m.Called(lat, lon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.Location), nil
+}
+
+func (m *Provider) Lookup(
+	osmType dtos.OsmType, id int64,
+) (*dtos.Location, error) {
+	args := m.Called(osmType, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.Location), nil
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"igaku/commons/dtos"
+)
+
+type RouteService struct {
+	mock.Mock
+}
+
+func (m *RouteService) Route(
+	req dtos.RouteRequest,
+) ([]dtos.RouteEstimate, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dtos.RouteEstimate), nil
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"igaku/commons/dtos"
+)
+
+type RoutingEngine struct {
+	mock.Mock
+	EngineModes []dtos.TravelMode
+}
+
+func (m *RoutingEngine) Name() string {
+	return "mock"
+}
+
+func (m *RoutingEngine) Modes() []dtos.TravelMode {
+	return m.EngineModes
+}
+
+func (m *RoutingEngine) Route(
+	mode dtos.TravelMode, fromLat, fromLon, toLat, toLon float64,
+) (*dtos.RouteEstimate, error) {
+	args := m.Called(mode, fromLat, fromLon, toLat, toLon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.RouteEstimate), nil
+}
@@ -0,0 +1,299 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+	"igaku/geo-service/providers"
+	commonDtos "igaku/commons/dtos"
+)
+
+var server *httptest.Server
+
+func TestMain(m *testing.M) {
+	server = httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			address := r.URL.Query().Get("q")
+
+			if address == "yoyogi,tokyo" {
+				w.Header().Set(
+					"Content-Type", "application/json",
+				)
+				w.Write([]byte(`[
+					{
+						"osm_id": 17054674, "osm_type": "relation",
+						"lat": "35.6823040", "lon": "139.6917362",
+						"display_name": "代々木, 渋谷区, 東京都, 151-0053, 日本"
+					},
+					{
+						"osm_id": 7093269751, "osm_type": "node",
+						"lat": "35.6835456", "lon": "139.7015260",
+						"display_name": "代々木, 四谷角筈線, 代々木一丁目, 代々木, 渋谷区, 東京都, 151-0053, 日本"
+					},
+					{
+						"osm_id": 2558121954, "osm_type": "node",
+						"lat": "35.6839514", "lon": "139.7020806",
+						"display_name": "代々木, 代々木駅(北口), 代々木一丁目, 代々木, 渋谷区, 東京都, 151-0053,  日本"
+					}
+				]`))
+			}
+		}),
+	)
+	defer server.Close()
+
+	exitCode := m.Run()
+
+	os.Exit(exitCode)
+}
+
+func TestNominatimProvider_Search_KeepsOsmTypes(t *testing.T) {
+	service := providers.NewNominatimProvider(
+		server.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+
+	locations, err := service.Search("yoyogi,tokyo")
+	require.NoError(t, err)
+
+	types := map[int64]commonDtos.OsmType{}
+	for _, loc := range locations {
+		types[loc.ID] = loc.OsmType
+	}
+
+	assert.Equal(t, map[int64]commonDtos.OsmType{
+		17054674: commonDtos.OsmRelation,
+		7093269751: commonDtos.OsmNode,
+		2558121954: commonDtos.OsmNode,
+	}, types)
+}
+
+func TestNominatimProvider_Lookup_UnknownType(t *testing.T) {
+	var osmIDs string
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			osmIDs = r.URL.Query().Get("osm_ids")
+			w.Write([]byte("[]"))
+		}),
+	)
+	defer nominatim.Close()
+
+	provider := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+
+	_, err := provider.Lookup("", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "N1,W1,R1", osmIDs)
+
+	_, err = provider.Lookup(commonDtos.OsmRelation, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "R1", osmIDs)
+}
+
+func TestNominatimProvider_IdentifiesItself(t *testing.T) {
+	var userAgent string
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent = r.Header.Get("User-Agent")
+			w.Write([]byte("[]"))
+		}),
+	)
+	defer nominatim.Close()
+
+	service := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	_, err := service.Search("shibuya")
+	require.NoError(t, err)
+
+	assert.Contains(t, userAgent, "Igaku")
+}
+
+func TestNominatimProvider_CoalescesIdenticalRequests(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			<-release
+			w.Write([]byte(`[{"osm_id":1,"lat":"35.6","lon":"139.7"}]`))
+		}),
+	)
+	defer nominatim.Close()
+
+	service := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			location, err := service.Lookup(commonDtos.OsmNode, 1)
+			assert.NoError(t, err)
+			assert.NotNil(t, location)
+		}()
+	}
+
+	// Gives the callers time to join the request in flight.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestNominatimProvider_RateLimited(t *testing.T) {
+	var requests atomic.Int32
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Write([]byte("[]"))
+		}),
+	)
+	defer nominatim.Close()
+
+	// The second request would have to wait past the timeout.
+	t.Setenv("NOMINATIM_TIMEOUT", "1")
+	service := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Hour, 1),
+	)
+
+	_, err := service.Search("shibuya")
+	require.NoError(t, err)
+
+	_, err = service.Search("shinjuku")
+	assert.ErrorIs(t, err, &errors.RateLimitedError{})
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestNominatimProvider_Lookup_AddressComponents(t *testing.T) {
+	var addressDetails string
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addressDetails = r.URL.Query().Get("addressdetails")
+			w.Write([]byte(`[{
+				"osm_id": 1, "osm_type": "way", "lat": "42.3", "lon": "-71.0",
+				"display_name": "Somerville",
+				"address": {
+					"house_number": "1", "road": "Elm Street",
+					"town": "Somerville", "postcode": "02144",
+					"county": "Middlesex County", "country": "United States",
+					"country_code": "us"
+				}
+			}]`))
+		}),
+	)
+	defer nominatim.Close()
+
+	provider := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	location, err := provider.Lookup(commonDtos.OsmWay, 1)
+	require.NoError(t, err)
+	require.NotNil(t, location)
+
+	assert.Equal(t, "1", addressDetails)
+	assert.Equal(t, commonDtos.OsmWay, location.OsmType)
+	assert.Equal(t, commonDtos.Address{
+		HouseNumber: "1",
+		Street: "Elm Street",
+		City: "Somerville",
+		Postcode: "02144",
+		County: "Middlesex County",
+		Country: "United States",
+		CountryCode: "us",
+	}, location.Address)
+}
+
+func TestNominatimProvider_SearchStructured(t *testing.T) {
+	var query url.Values
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			w.Write([]byte("[]"))
+		}),
+	)
+	defer nominatim.Close()
+
+	provider := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	_, err := provider.SearchStructured(commonDtos.Address{
+		HouseNumber: "55",
+		Street: "Fruit Street",
+		City: "Boston",
+		CountryCode: "us",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "55 Fruit Street", query.Get("street"))
+	assert.Equal(t, "Boston", query.Get("city"))
+	assert.Equal(t, "us", query.Get("countrycodes"))
+	assert.False(t, query.Has("q"))
+	assert.False(t, query.Has("postalcode"))
+}
+
+func TestNominatimProvider_Autocomplete_Unsupported(t *testing.T) {
+	requests := 0
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Write([]byte("[]"))
+		}),
+	)
+	defer nominatim.Close()
+
+	service := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	_, err := service.Autocomplete("shibu", 5)
+
+	assert.ErrorIs(t, err, &errors.UnsupportedOperationError{})
+	assert.Zero(t, requests, "Expected Nominatim not to be asked")
+}
+
+func TestNominatimProvider_Reverse_NothingAround(t *testing.T) {
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"error":"Unable to geocode"}`))
+		}),
+	)
+	defer nominatim.Close()
+
+	provider := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+
+	location, err := provider.Reverse(0, -30)
+	require.NoError(t, err)
+	assert.Nil(t, location)
+}
+
+func TestNominatimProvider_Reverse_Error(t *testing.T) {
+	nominatim := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"error":{"code":500,"message":"Internal error"}}`))
+		}),
+	)
+	defer nominatim.Close()
+
+	provider := providers.NewNominatimProvider(
+		nominatim.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+
+	_, err := provider.Reverse(0, -30)
+	var externErr *errors.ExternalApiRequestError
+	assert.ErrorAs(t, err, &externErr)
+}
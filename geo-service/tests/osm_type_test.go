@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+)
+
+func TestParseOsmID(t *testing.T) {
+	for input, expected := range map[string]struct {
+		osmType	dtos.OsmType
+		id	int64
+	}{
+		"117853077": {"", 117853077},
+		"W117853077": {dtos.OsmWay, 117853077},
+		"r2315704": {dtos.OsmRelation, 2315704},
+		"N1": {dtos.OsmNode, 1},
+	} {
+		osmType, id, err := dtos.ParseOsmID(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected.osmType, osmType, input)
+		assert.Equal(t, expected.id, id, input)
+	}
+
+	for _, input := range []string{"", "W", "X1", "W-1", "0", "1W"} {
+		_, _, err := dtos.ParseOsmID(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestOsmType_DeserializedFromLettersAndNames(t *testing.T) {
+	var locations []dtos.Location
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"osm_id": 1, "osm_type": "relation"},
+		{"osm_id": 2, "osm_type": "W"},
+		{"osm_id": 3}
+	]`), &locations))
+
+	assert.Equal(t, dtos.OsmRelation, locations[0].OsmType)
+	assert.Equal(t, dtos.OsmWay, locations[1].OsmType)
+	assert.Empty(t, locations[2].OsmType)
+
+	data, err := json.Marshal(locations[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"osm_type":"R"`)
+
+	var location dtos.Location
+	assert.Error(t, json.Unmarshal([]byte(`{"osm_type": "area"}`), &location))
+}
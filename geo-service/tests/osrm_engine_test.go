@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+	"igaku/geo-service/providers"
+)
+
+const osrmResponse = `{
+	"code": "Ok",
+	"routes": [
+		{"distance": 5364.2, "duration": 712.5, "weight": 712.5, "legs": []}
+	],
+	"waypoints": []
+}`
+
+// newOSRMStub starts a server answering like OSRM with the given status and
+// body, recording the path of the last request.
+func newOSRMStub(status int, body string, path *string) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*path = r.URL.Path
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}),
+	)
+}
+
+func TestOSRMEngine_Route(t *testing.T) {
+	var path string
+	osrm := newOSRMStub(http.StatusOK, osrmResponse, &path)
+	defer osrm.Close()
+
+	engine := providers.NewOSRMEngine(
+		map[dtos.TravelMode]string{dtos.Walking: osrm.URL},
+		limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	estimate, err := engine.Route(
+		dtos.Walking, 42.3736158, -71.1097335, 42.3628605, -71.068753,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		"/route/v1/walking/-71.1097335,42.3736158;-71.0687530,42.3628605",
+		path,
+	)
+	require.NotNil(t, estimate)
+	assert.Equal(t, dtos.RouteEstimate{
+		Mode: dtos.Walking, DistanceMeters: 5364.2, DurationSeconds: 712.5,
+	}, *estimate)
+}
+
+func TestOSRMEngine_Modes(t *testing.T) {
+	engine := providers.NewOSRMEngine(
+		map[dtos.TravelMode]string{
+			dtos.Cycling: "http://bike", dtos.Driving: "http://car",
+		},
+		limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+
+	assert.Equal(
+		t, []dtos.TravelMode{dtos.Driving, dtos.Cycling}, engine.Modes(),
+	)
+
+	_, err := engine.Route(dtos.Walking, 0, 0, 1, 1)
+	assert.ErrorIs(t, err, &errors.UnsupportedTravelModeError{})
+}
+
+func TestOSRMEngine_Route_NoRoute(t *testing.T) {
+	var path string
+	osrm := newOSRMStub(
+		http.StatusBadRequest,
+		`{"code": "NoRoute", "message": "Impossible route between points"}`,
+		&path,
+	)
+	defer osrm.Close()
+
+	engine := providers.NewOSRMEngine(
+		map[dtos.TravelMode]string{dtos.Driving: osrm.URL},
+		limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	estimate, err := engine.Route(dtos.Driving, 35.6, 139.7, 21.3, -157.8)
+	require.NoError(t, err)
+	assert.Nil(t, estimate)
+}
+
+func TestOSRMEngine_Route_Failure(t *testing.T) {
+	for name, tc := range map[string]struct {
+		status	int
+		body	string
+	}{
+		"ServerError": {http.StatusBadGateway, "Bad Gateway"},
+		"InvalidQuery": {
+			http.StatusBadRequest,
+			`{"code": "InvalidQuery", "message": "Query string malformed"}`,
+		},
+		"InvalidJSON": {http.StatusOK, "<html></html>"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var path string
+			osrm := newOSRMStub(tc.status, tc.body, &path)
+			defer osrm.Close()
+
+			engine := providers.NewOSRMEngine(
+				map[dtos.TravelMode]string{dtos.Driving: osrm.URL},
+				limiter.NewTokenBucket(time.Millisecond, 10),
+			)
+			_, err := engine.Route(dtos.Driving, 35.6, 139.7, 35.7, 139.8)
+
+			var externErr *errors.ExternalApiRequestError
+			assert.ErrorAs(t, err, &externErr)
+		})
+	}
+}
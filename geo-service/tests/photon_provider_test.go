@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+	"igaku/geo-service/providers"
+)
+
+const photonResponse = `{
+	"type": "FeatureCollection",
+	"features": [
+		{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [139.7015260, 35.6835456]},
+			"properties": {
+				"osm_id": 7093269751, "osm_type": "N", "name": "代々木",
+				"city": "渋谷区", "country": "日本", "countrycode": "JP"
+			}
+		},
+		{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [139.6917362, 35.6823040]},
+			"properties": {"osm_id": 17054674, "osm_type": "R", "name": "代々木"}
+		}
+	]
+}`
+
+func TestPhotonProvider_Search(t *testing.T) {
+	var path, query string
+	photon := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, query = r.URL.Path, r.URL.Query().Get("q")
+			w.Write([]byte(photonResponse))
+		}),
+	)
+	defer photon.Close()
+
+	provider := providers.NewPhotonProvider(
+		photon.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	locations, err := provider.Search("yoyogi")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api", path)
+	assert.Equal(t, "yoyogi", query)
+	require.Len(t, locations, 2)
+	assert.Equal(t, int64(7093269751), locations[0].ID)
+	assert.Equal(t, dtos.OsmNode, locations[0].OsmType)
+	assert.Equal(t, "35.6835456", locations[0].Lat)
+	assert.Equal(t, "139.7015260", locations[0].Lon)
+	assert.Equal(t, "代々木, 渋谷区, 日本", locations[0].Name)
+	assert.Equal(t, "渋谷区", locations[0].Address.City)
+	assert.Equal(t, "jp", locations[0].Address.CountryCode)
+	assert.Equal(t, dtos.OsmRelation, locations[1].OsmType)
+}
+
+func TestPhotonProvider_Autocomplete(t *testing.T) {
+	var query, limit string
+	photon := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("q")
+			limit = r.URL.Query().Get("limit")
+			w.Write([]byte(photonResponse))
+		}),
+	)
+	defer photon.Close()
+
+	provider := providers.NewPhotonProvider(
+		photon.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	locations, err := provider.Autocomplete("yoyo", 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, "yoyo", query)
+	assert.Equal(t, "3", limit)
+	assert.Len(t, locations, 2)
+}
+
+func TestPhotonProvider_Reverse_NoResult(t *testing.T) {
+	photon := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"type": "FeatureCollection", "features": []}`))
+		}),
+	)
+	defer photon.Close()
+
+	provider := providers.NewPhotonProvider(
+		photon.URL, limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	_, err := provider.Reverse(0, 0)
+
+	var externErr *errors.ExternalApiRequestError
+	assert.ErrorAs(t, err, &externErr)
+}
+
+func TestPhotonProvider_Lookup_Unsupported(t *testing.T) {
+	provider := providers.NewPhotonProvider(
+		"http://photon.invalid", limiter.NewTokenBucket(time.Millisecond, 10),
+	)
+	_, err := provider.Lookup(dtos.OsmNode, 7093269751)
+	assert.ErrorIs(t, err, &errors.UnsupportedOperationError{})
+}
@@ -0,0 +1,127 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/batch"
+	"igaku/geo-service/models"
+	"igaku/geo-service/utils"
+	testUtils "igaku/commons/utils"
+)
+
+func createBatchJob(
+	t *testing.T, store batch.Store, createdAt time.Time, queries ...string,
+) *models.BatchJob {
+	t.Helper()
+
+	job := &models.BatchJob{
+		ID: uuid.New(), OwnerID: batchOwner, Status: models.BatchQueued,
+		Total: len(queries), CreatedAt: createdAt, UpdatedAt: createdAt,
+	}
+	rows := make([]models.BatchRow, len(queries))
+	for i, query := range queries {
+		rows[i] = models.BatchRow{
+			JobID: job.ID, Row: i + 1, Query: query,
+			Status: models.RowPending,
+		}
+	}
+	require.NoError(t, store.Create(job, rows))
+	return job
+}
+
+func TestPostgresBatchStore(t *testing.T) {
+	t.Run("ClaimsOldestQueuedJob", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		store := batch.NewPostgresStore(db)
+		now := time.Now()
+		older := createBatchJob(t, store, now.Add(-time.Minute), "Boston")
+		newer := createBatchJob(t, store, now, "Springfield")
+
+		claimed, err := store.Claim(now, now.Add(-5*time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, older.ID, claimed.ID)
+		assert.Equal(t, models.BatchRunning, claimed.Status)
+
+		claimed, err = store.Claim(now, now.Add(-5*time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, newer.ID, claimed.ID)
+
+		claimed, err = store.Claim(now, now.Add(-5*time.Minute))
+		require.NoError(t, err)
+		assert.Nil(t, claimed, "Expected running jobs not to be claimed")
+
+		later := now.Add(10 * time.Minute)
+		claimed, err = store.Claim(later, later.Add(-5*time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, claimed, "Expected stale jobs to be claimed")
+	})
+
+	t.Run("SavesRowsAndFinishes", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		store := batch.NewPostgresStore(db)
+		now := time.Now()
+		job := createBatchJob(t, store, now, "Springfield", "Nowhere")
+
+		location := dtos.Location{
+			ID: 1, Lat: "39.7990175", Lon: "-89.6439575", Name: "Springfield",
+		}
+		require.NoError(t, store.SaveRow(&models.BatchRow{
+			JobID: job.ID, Row: 1, Query: "Springfield",
+			Status: models.RowMatched, Location: &location, Candidates: 2,
+			Confidence: 1, Ambiguous: true,
+		}, now))
+
+		found, err := store.FindJob(job.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, 1, found.Processed)
+
+		rows, err := store.FindRows(job.ID)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, models.RowMatched, rows[0].Status)
+		assert.Equal(t, location, *rows[0].Location)
+		assert.True(t, rows[0].Ambiguous)
+		assert.Equal(t, models.RowPending, rows[1].Status)
+		assert.Nil(t, rows[1].Location)
+
+		require.NoError(t, store.Finish(job.ID, now))
+		deleted, err := store.DeleteFinished(now.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		deleted, err = store.DeleteFinished(now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		found, err = store.FindJob(job.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+		rows, err = store.FindRows(job.ID)
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+}
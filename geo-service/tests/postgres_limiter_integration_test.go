@@ -0,0 +1,92 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/geo-service/errors"
+	"igaku/geo-service/limiter"
+	"igaku/geo-service/utils"
+	testUtils "igaku/commons/utils"
+)
+
+func TestPostgresTokenBucket(t *testing.T) {
+	t.Run("SharedBetweenReplicas", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		interval := 200 * time.Millisecond
+		replicas := []limiter.Limiter{
+			limiter.NewPostgresTokenBucket(db, "nominatim", interval, 1),
+			limiter.NewPostgresTokenBucket(db, "nominatim", interval, 1),
+		}
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for _, replica := range replicas {
+			for range 2 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					assert.NoError(t, replica.Wait(ctx))
+				}()
+			}
+		}
+		wg.Wait()
+
+		assert.GreaterOrEqual(t, time.Since(start), 3*interval)
+	})
+
+	t.Run("RejectsRequestsPastDeadline", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		bucket := limiter.NewPostgresTokenBucket(db, "nominatim", time.Hour, 1)
+		require.NoError(t, bucket.Wait(ctx))
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		assert.ErrorIs(
+			t, bucket.Wait(timeoutCtx), &errors.RateLimitedError{},
+		)
+	})
+
+	t.Run("CancelledRequestReleasesToken", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		interval := time.Second
+		bucket := limiter.NewPostgresTokenBucket(db, "nominatim", interval, 1)
+		require.NoError(t, bucket.Wait(ctx))
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		queued := make(chan error)
+		go func() { queued <- bucket.Wait(cancelCtx) }()
+		time.Sleep(interval / 4)
+		cancel()
+		assert.ErrorIs(t, <-queued, context.Canceled)
+
+		start := time.Now()
+		require.NoError(t, bucket.Wait(ctx))
+		assert.Less(t, time.Since(start), interval/2)
+	})
+}
@@ -0,0 +1,71 @@
+//go:build integration
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/geo-service/cache"
+	"igaku/geo-service/utils"
+	testUtils "igaku/commons/utils"
+)
+
+func TestPostgresStore(t *testing.T) {
+	t.Run("GetSkipsExpiredEntries", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		store := cache.NewPostgresStore(db)
+		now := time.Now()
+
+		entry, err := store.Get("lookup:W117853077", now)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.False(t, entry.Negative)
+		assert.Contains(t, string(entry.Value), "Massachusetts General Hospital")
+
+		entry, err = store.Get("lookup:1", now)
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+
+		purged, err := store.DeleteExpired(now)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, purged)
+	})
+
+	t.Run("SetOverwrites", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		store := cache.NewPostgresStore(db)
+		now := time.Now()
+
+		err := store.Set("search:nowhere", cache.Entry{
+			Negative: true, ExpiresAt: now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+		err = store.Set("search:nowhere", cache.Entry{
+			Value: []byte("[]"), ExpiresAt: now.Add(2 * time.Hour),
+		})
+		require.NoError(t, err)
+
+		entry, err := store.Get("search:nowhere", now)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		assert.False(t, entry.Negative)
+		assert.Equal(t, "[]", string(entry.Value))
+	})
+}
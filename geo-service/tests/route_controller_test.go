@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/controllers"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/tests/mocks"
+)
+
+const bostonRouteQuery = "from_lat=42.3736158&from_lon=-71.1097335" +
+	"&to_lat=42.3628605&to_lon=-71.068753"
+
+func serveRoute(mockService *mocks.RouteService, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	controllers.NewRouteController(mockService).RegisterRoutes(router)
+
+	req, _ := http.NewRequest(http.MethodGet, "/geo/route?"+query, nil)
+	req.Header.Set("Authorization", genToken())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouteController_Route(t *testing.T) {
+	mockService := new(mocks.RouteService)
+	req := boston
+	req.Modes = []dtos.TravelMode{dtos.Walking, dtos.Cycling}
+	estimates := []dtos.RouteEstimate{
+		{Mode: dtos.Walking, DistanceMeters: 4900, DurationSeconds: 3500},
+		{Mode: dtos.Cycling, DistanceMeters: 5200, DurationSeconds: 1100},
+	}
+	mockService.On("Route", req).Return(estimates, nil).Once()
+
+	rec := serveRoute(
+		mockService, bostonRouteQuery+"&mode=walking&mode=Cycling",
+	)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var returned []dtos.RouteEstimate
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &returned))
+	assert.Equal(t, estimates, returned)
+	mockService.AssertExpectations(t)
+}
+
+func TestRouteController_Route_InvalidParams(t *testing.T) {
+	for name, query := range map[string]string{
+		"MissingDestination": "from_lat=42.37&from_lon=-71.10",
+		"InvalidStart": "from_lat=north&from_lon=-71.10&to_lat=42.36&to_lon=-71.06",
+		"InvalidMode": bostonRouteQuery + "&mode=flying",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(mocks.RouteService)
+			rec := serveRoute(mockService, query)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mockService.AssertNotCalled(t, "Route")
+		})
+	}
+}
+
+func TestRouteController_Route_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err	error
+		status	int
+	}{
+		"UnsupportedMode": {
+			&errors.UnsupportedTravelModeError{}, http.StatusBadRequest,
+		},
+		"InvalidCoordinates": {
+			&errors.InvalidCoordinatesError{}, http.StatusBadRequest,
+		},
+		"Timeout": {&errors.TimeoutError{}, http.StatusRequestTimeout},
+		"RateLimited": {
+			&errors.RateLimitedError{}, http.StatusServiceUnavailable,
+		},
+		"External": {
+			&errors.ExternalApiRequestError{Message: "Failed"},
+			http.StatusInternalServerError,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(mocks.RouteService)
+			mockService.On("Route", boston).Return(nil, tc.err).Once()
+
+			rec := serveRoute(mockService, bostonRouteQuery)
+
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
+
+func TestRouteController_Route_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	mockService := new(mocks.RouteService)
+	controllers.NewRouteController(mockService).RegisterRoutes(router)
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/geo/route?"+bostonRouteQuery, nil,
+	)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockService.AssertNotCalled(t, "Route")
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"igaku/commons/dtos"
+	"igaku/geo-service/errors"
+	"igaku/geo-service/services"
+	"igaku/geo-service/tests/mocks"
+)
+
+var boston = dtos.RouteRequest{
+	FromLat: 42.3736158, FromLon: -71.1097335,
+	ToLat: 42.3628605, ToLon: -71.068753,
+}
+
+func TestRouteService_Route_AllModes(t *testing.T) {
+	engine := &mocks.RoutingEngine{
+		EngineModes: []dtos.TravelMode{dtos.Driving, dtos.Walking},
+	}
+	service := services.NewRouteService(engine)
+
+	driving := &dtos.RouteEstimate{
+		Mode: dtos.Driving, DistanceMeters: 6100, DurationSeconds: 720,
+	}
+	walking := &dtos.RouteEstimate{
+		Mode: dtos.Walking, DistanceMeters: 4900, DurationSeconds: 3500,
+	}
+	engine.On(
+		"Route", dtos.Driving, 42.3736158, -71.1097335, 42.3628605, -71.068753,
+	).Return(driving, nil).Once()
+	engine.On(
+		"Route", dtos.Walking, 42.3736158, -71.1097335, 42.3628605, -71.068753,
+	).Return(walking, nil).Once()
+
+	estimates, err := service.Route(boston)
+	require.NoError(t, err)
+	assert.Equal(t, []dtos.RouteEstimate{*driving, *walking}, estimates)
+	engine.AssertExpectations(t)
+}
+
+func TestRouteService_Route_LeavesOutUnconnectedModes(t *testing.T) {
+	engine := &mocks.RoutingEngine{
+		EngineModes: []dtos.TravelMode{dtos.Driving, dtos.Walking},
+	}
+	service := services.NewRouteService(engine)
+
+	engine.On(
+		"Route", dtos.Walking, 42.3736158, -71.1097335, 42.3628605, -71.068753,
+	).Return(nil, nil).Once()
+
+	req := boston
+	req.Modes = []dtos.TravelMode{dtos.Walking}
+	estimates, err := service.Route(req)
+	require.NoError(t, err)
+	assert.Empty(t, estimates)
+	assert.NotNil(t, estimates)
+	engine.AssertExpectations(t)
+}
+
+func TestRouteService_Route_UnsupportedMode(t *testing.T) {
+	engine := &mocks.RoutingEngine{
+		EngineModes: []dtos.TravelMode{dtos.Driving},
+	}
+	service := services.NewRouteService(engine)
+
+	req := boston
+	req.Modes = []dtos.TravelMode{dtos.Driving, dtos.Cycling}
+	_, err := service.Route(req)
+	assert.ErrorIs(t, err, &errors.UnsupportedTravelModeError{})
+	engine.AssertNotCalled(t, "Route")
+}
+
+func TestRouteService_Route_InvalidCoordinates(t *testing.T) {
+	engine := &mocks.RoutingEngine{
+		EngineModes: []dtos.TravelMode{dtos.Driving},
+	}
+	service := services.NewRouteService(engine)
+
+	req := boston
+	req.ToLat = 91
+	_, err := service.Route(req)
+	assert.ErrorIs(t, err, &errors.InvalidCoordinatesError{})
+}
+
+func TestRouteService_Route_EngineFailure(t *testing.T) {
+	engine := &mocks.RoutingEngine{
+		EngineModes: []dtos.TravelMode{dtos.Driving},
+	}
+	service := services.NewRouteService(engine)
+
+	engine.On(
+		"Route", dtos.Driving, 42.3736158, -71.1097335, 42.3628605, -71.068753,
+	).Return(nil, &errors.TimeoutError{}).Once()
+
+	_, err := service.Route(boston)
+	assert.ErrorIs(t, err, &errors.TimeoutError{})
+}
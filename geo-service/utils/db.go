@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"fmt"
+	"log"
+	"os"
+
+	"igaku/geo-service/models"
+	commonsErrors "igaku/commons/errors"
+)
+
+func MigrateSchema(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&models.CacheEntry{},
+		&models.RateLimit{},
+		&models.BatchJob{},
+		&models.BatchRow{},
+	); err != nil {
+		log.Printf("Failed to migrate DB schema: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+// InitDatabase connects to the database of the persistent cache and the
+// shared rate limiter. It returns nil when no database is configured.
+func InitDatabase() (*gorm.DB, error) {
+	host := os.Getenv("POSTGRES_HOST")
+	if host == "" {
+		return nil, nil
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s "+
+		"user=%s "+
+		"password=%s "+
+		"dbname=%s "+
+		"port=5432 "+
+		"sslmode=disable "+
+		"TimeZone=Europe/Warsaw",
+		host,
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+	)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		log.Printf("Failed to connect to database: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	if err := MigrateSchema(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"net/http"
+
+	"igaku/commons/dtos"
+)
+
+// userIDFromContext returns the ID of the authenticated user. On failure
+// it writes an error response and returns false.
+func userIDFromContext(c *gin.Context) (uuid.UUID, bool) {
+	idStr, exists := c.Get("id")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "User ID not found in context",
+		})
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(idStr.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "Invalid user ID format in token",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// parseIDParam parses the UUID path parameter of the given name. On
+// failure it writes an error response and returns false.
+func parseIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Message: "Invalid UUID format",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+	"time"
+
+	"igaku/notification-service/middleware"
+	"igaku/notification-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/notification-service/errors"
+)
+
+type NotificationController struct {
+	service		services.NotificationService
+	hub		services.Hub
+	// heartbeat is how often an idle stream is written to, so that
+	// proxies do not close it.
+	heartbeat	time.Duration
+}
+
+func NewNotificationController(
+	service services.NotificationService,
+	hub services.Hub,
+	heartbeat time.Duration,
+) *NotificationController {
+	return &NotificationController{
+		service: service,
+		hub: hub,
+		heartbeat: heartbeat,
+	}
+}
+
+// Stream streams the notifications of the currently logged-in user.
+// @Summary	Stream notifications
+// @Description	Streams the notifications of the currently logged-in user as Server-Sent Events while the connection is open. Each notification is sent as a `notification` event and idle streams get a `heartbeat` event. The stream ends with an `expired` event when the token expires, after which clients reconnect with a fresh token. As browsers cannot set headers on an EventSource, the token may be passed in the `access_token` query parameter. Notifications sent while disconnected are kept in the inbox.
+// @Tags	Notifications
+// @Produce	text/event-stream
+// @Param	access_token query string false "JWT token, instead of the Authorization header"
+// @Success	200 {object} models.Notification "Stream of notifications"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Security	BearerAuth
+// @Router	/notification/stream [get]
+func (ctrl *NotificationController) Stream(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	notifications, unsubscribe := ctrl.hub.Subscribe(userID)
+	defer unsubscribe()
+
+	var expired <-chan time.Time
+	if expiresAt, exists := c.Get("expires_at"); exists {
+		timer := time.NewTimer(time.Until(expiresAt.(time.Time)))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	heartbeat := time.NewTicker(ctrl.heartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keeps nginx from buffering the stream.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	for {
+		select {
+		case notification, open := <-notifications:
+			if !open {
+				return
+			}
+			c.SSEvent("notification", notification)
+		case <-heartbeat.C:
+			c.SSEvent("heartbeat", time.Now())
+		case <-expired:
+			c.SSEvent("expired", "Token has expired")
+			c.Writer.Flush()
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// List lists the notifications of the currently logged-in user.
+// @Summary	List notifications
+// @Description	Lists the notifications of the currently logged-in user, newest first.
+// @Tags	Notifications
+// @Produce	json
+// @Param	unread query bool false "Only list unread notifications"
+// @Success	200 {array} models.Notification "Successfully retrieved notifications"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid 'unread' parameter"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve notifications"
+// @Security	BearerAuth
+// @Router	/notification/notifications [get]
+func (ctrl *NotificationController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var query struct {
+		Unread	bool	`form:"unread"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid 'unread' parameter",
+		})
+		return
+	}
+
+	notifications, err := ctrl.service.List(userID, query.Unread)
+	if err != nil {
+		handleNotificationError(c, err, "Failed to retrieve notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// Unread counts the unread notifications of the currently logged-in user.
+// @Summary	Count unread notifications
+// @Description	Counts the notifications of the currently logged-in user which they have not read yet.
+// @Tags	Notifications
+// @Produce	json
+// @Success	200 {object} dtos.UnreadCount "Successfully counted unread notifications"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to count unread notifications"
+// @Security	BearerAuth
+// @Router	/notification/notifications/unread [get]
+func (ctrl *NotificationController) Unread(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	count, err := ctrl.service.Unread(userID)
+	if err != nil {
+		handleNotificationError(c, err, "Failed to count unread notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, count)
+}
+
+// MarkRead marks a notification as read.
+// @Summary	Mark notification as read
+// @Description	Marks a notification of the currently logged-in user as read. Notifications read before keep the time they were first read.
+// @Tags	Notifications
+// @Produce	json
+// @Param	id path string true "Notification ID (UUIDv4 format)"
+// @Success	200 {object} models.Notification "Successfully marked notification as read"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Notification not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to mark notification as read"
+// @Security	BearerAuth
+// @Router	/notification/notifications/{id}/read [post]
+func (ctrl *NotificationController) MarkRead(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	notification, err := ctrl.service.MarkRead(id, userID)
+	if err != nil {
+		handleNotificationError(c, err, "Failed to mark notification as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+// MarkAllRead marks all notifications as read.
+// @Summary	Mark all notifications as read
+// @Description	Marks all notifications of the currently logged-in user as read.
+// @Tags	Notifications
+// @Success	204 "Successfully marked notifications as read"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to mark notifications as read"
+// @Security	BearerAuth
+// @Router	/notification/notifications/read [post]
+func (ctrl *NotificationController) MarkAllRead(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.MarkAllRead(userID); err != nil {
+		handleNotificationError(c, err, "Failed to mark notifications as read")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *NotificationController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/notification")
+	routes.Use(
+		middleware.Authenticate(),
+		middleware.Authorize(
+			commonsModels.Patient, commonsModels.Doctor, commonsModels.Admin,
+		),
+	)
+	{
+		routes.GET("/stream", ctrl.Stream)
+		routes.GET("/notifications", ctrl.List)
+		routes.GET("/notifications/unread", ctrl.Unread)
+		routes.POST("/notifications/read", ctrl.MarkAllRead)
+		routes.POST("/notifications/:id/read", ctrl.MarkRead)
+	}
+}
+
+func handleNotificationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.NotificationNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
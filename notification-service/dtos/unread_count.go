@@ -0,0 +1,5 @@
+package dtos
+
+type UnreadCount struct {
+	Unread	int64	`json:"unread" example:"3"`
+}
@@ -0,0 +1,7 @@
+package errors
+
+type NotificationNotFoundError struct{}
+
+func (m *NotificationNotFoundError) Error() string {
+	return "Notification not found"
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"igaku/notification-service/repositories"
+	"igaku/notification-service/servers"
+	"igaku/notification-service/services"
+	"igaku/notification-service/utils"
+)
+
+// @title		Igaku Notification API
+// @version		0.0.1
+// @host		localhost:4000
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+func main() {
+	db, err := utils.InitDatabase()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	notificationRepo := repositories.NewGormNotificationRepository(db)
+	notificationService := services.NewNotificationService(notificationRepo)
+	hub := services.NewHub()
+
+	amqpURI := os.Getenv("RABBITMQ_URL")
+	rbServer, err := servers.NewRabbitMQServer(
+		amqpURI, notificationService, hub,
+	)
+	failOnError(err, "[RabbitMQ] Failed to initialize server")
+	defer rbServer.Shutdown()
+
+	err = rbServer.Start()
+	failOnError(err, "[RabbitMQ] Failed to start listeners")
+
+	heartbeat, err := strconv.Atoi(
+		os.Getenv("NOTIFICATION_HEARTBEAT_IN_SECONDS"),
+	)
+	if err != nil || heartbeat <= 0 {
+		heartbeat = 30
+	}
+	apiServer := servers.NewApiServer(
+		notificationService, hub, time.Duration(heartbeat)*time.Second,
+	)
+	apiServer.Start()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(
+		context.Background(), 10*time.Second,
+	)
+	defer cancelShutdown()
+
+	if err = apiServer.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shutdown REST API")
+	}
+}
+
+func failOnError(err error, msg string) {
+	if err != nil {
+		log.Panicf("%s: %s", msg, err)
+	}
+}
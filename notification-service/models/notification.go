@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/commons/dtos"
+)
+
+// Notification is the entry of a domain event in the inbox of one of the
+// users it concerns. A user gets at most one notification per event, so
+// redelivered events are not repeated.
+type Notification struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"7e1f0c2a-9b3d-4f5e-8a6b-1c2d3e4f5a6b"`
+	EventID		uuid.UUID	`gorm:"type:uuid;not null;uniqueIndex:idx_notification_event_user" json:"event_id" example:"3a4b5c6d-7e8f-4a0b-9c1d-2e3f4a5b6c7d"`
+	UserID		uuid.UUID	`gorm:"type:uuid;not null;uniqueIndex:idx_notification_event_user;index" json:"user_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	Type		dtos.EventType	`gorm:"not null" json:"type" example:"appointment.booked"`
+	// ResourceID is the ID of what the notification is about.
+	ResourceID	uuid.UUID	`gorm:"type:uuid;not null" json:"resource_id" example:"5d8c7a8e-3b1f-4e2a-9c6d-8f7e6d5c4b3a"`
+	CreatedAt	time.Time	`gorm:"not null;index" json:"created_at" example:"2025-06-01T09:00:00Z"`
+	ReadAt		*time.Time	`json:"read_at,omitempty" example:"2025-06-01T09:30:00Z"`
+}
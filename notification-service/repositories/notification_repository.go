@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"log"
+	"time"
+
+	"igaku/notification-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/notification-service/errors"
+)
+
+type NotificationRepository interface {
+	// CreateAll stores the notifications, skipping those of events already
+	// delivered to the user. It returns the notifications actually stored.
+	CreateAll(
+		notifications []models.Notification,
+	) ([]models.Notification, error)
+	// FindByUserID returns the notifications of the user, newest first.
+	FindByUserID(
+		userID uuid.UUID, unreadOnly bool,
+	) ([]models.Notification, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	// MarkRead marks a notification of the user as read. Notifications of
+	// others are reported as not found.
+	MarkRead(id, userID uuid.UUID, at time.Time) (*models.Notification, error)
+	// MarkAllRead marks all notifications of the user as read, returning
+	// how many were unread.
+	MarkAllRead(userID uuid.UUID, at time.Time) (int64, error)
+}
+
+type gormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &gormNotificationRepository{db: db}
+}
+
+func (r *gormNotificationRepository) CreateAll(
+	notifications []models.Notification,
+) ([]models.Notification, error) {
+	created := []models.Notification{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range notifications {
+			res := tx.
+				Clauses(clause.OnConflict{
+					Columns: []clause.Column{
+						{Name: "event_id"}, {Name: "user_id"},
+					},
+					DoNothing: true,
+				}).
+				Create(&notifications[i])
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected > 0 {
+				created = append(created, notifications[i])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to create notifications: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return created, nil
+}
+
+func (r *gormNotificationRepository) FindByUserID(
+	userID uuid.UUID, unreadOnly bool,
+) ([]models.Notification, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	err := query.Order("created_at DESC").Find(&notifications).Error
+	if err != nil {
+		log.Printf("Failed to find notifications: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return notifications, nil
+}
+
+func (r *gormNotificationRepository) CountUnread(
+	userID uuid.UUID,
+) (int64, error) {
+	var count int64
+	err := r.db.
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).
+		Error
+	if err != nil {
+		log.Printf("Failed to count unread notifications: %v", err)
+		return 0, &commonsErrors.DatabaseError{}
+	}
+	return count, nil
+}
+
+func (r *gormNotificationRepository) MarkRead(
+	id, userID uuid.UUID, at time.Time,
+) (*models.Notification, error) {
+	// Notifications read before keep their first read time.
+	err := r.db.
+		Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", at).
+		Error
+	if err != nil {
+		log.Printf("Failed to mark notification as read: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	var notification models.Notification
+	res := r.db.
+		Where("id = ? AND user_id = ?", id, userID).
+		Limit(1).
+		Find(&notification)
+	if res.Error != nil {
+		log.Printf("Failed to find notification: %v", res.Error)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	if res.RowsAffected == 0 {
+		return nil, &igakuErrors.NotificationNotFoundError{}
+	}
+	return &notification, nil
+}
+
+func (r *gormNotificationRepository) MarkAllRead(
+	userID uuid.UUID, at time.Time,
+) (int64, error) {
+	res := r.db.
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	if res.Error != nil {
+		log.Printf("Failed to mark notifications as read: %v", res.Error)
+		return 0, &commonsErrors.DatabaseError{}
+	}
+	return res.RowsAffected, nil
+}
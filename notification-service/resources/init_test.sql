@@ -0,0 +1,29 @@
+INSERT INTO notifications (id, event_id, user_id, type, resource_id, created_at, read_at)
+VALUES
+    (
+        '7e1f0c2a-9b3d-4f5e-8a6b-1c2d3e4f5a6b',
+        '3a4b5c6d-7e8f-4a0b-9c1d-2e3f4a5b6c7d',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'appointment.booked',
+        '5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a',
+        NOW() - INTERVAL '3 days',
+        NOW() - INTERVAL '3 days'
+    ),
+    (
+        '8f2a1d3b-0c4e-4a6f-9b7c-2d3e4f5a6b7c',
+        '3a4b5c6d-7e8f-4a0b-9c1d-2e3f4a5b6c7d',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        'appointment.booked',
+        '5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a',
+        NOW() - INTERVAL '3 days',
+        NULL
+    ),
+    (
+        '9a3b2e4c-1d5f-4b7a-8c8d-3e4f5a6b7c8d',
+        '4b5c6d7e-8f9a-4b1c-8d2e-3f4a5b6c7d8e',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        'message.received',
+        '0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f',
+        NOW() - INTERVAL '1 day',
+        NULL
+    );
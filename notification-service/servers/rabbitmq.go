@@ -0,0 +1,235 @@
+package servers
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"igaku/notification-service/models"
+	"igaku/notification-service/services"
+	commonsErrors "igaku/commons/errors"
+	"igaku/commons/dtos"
+)
+
+const (
+	// eventsQueue is shared by all replicas, so that each event is stored
+	// once.
+	eventsQueue		= "notifications.events"
+	// pushExchange fans stored notifications out to every replica, each of
+	// which pushes them to the clients connected to it.
+	pushExchange	= "notifications"
+)
+
+type RabbitMQServer struct {
+	conn	*amqp.Connection
+	ch	*amqp.Channel
+	service	services.NotificationService
+	hub	services.Hub
+}
+
+func NewRabbitMQServer(
+	amqpURI string,
+	service services.NotificationService,
+	hub services.Hub,
+) (*RabbitMQServer, error) {
+	conn, err := amqp.Dial(amqpURI)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to connect: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		log.Printf("[RabbitMQ] Failed to open a channel: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	return &RabbitMQServer{
+		conn: conn, ch: ch, service: service, hub: hub,
+	}, nil
+}
+
+func (s *RabbitMQServer) Start() error {
+	err := s.ch.ExchangeDeclare(
+		pushExchange, "fanout", true, false, false, false, nil,
+	)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare an exchange '%s': %v",
+			pushExchange, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.StartPushListener()
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to start `PushListener`: %v",
+			err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.StartEventListener()
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to start `EventListener`: %v",
+			err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	return nil
+}
+
+func (s *RabbitMQServer) Shutdown() {
+	if s.ch != nil { s.ch.Close() }
+	if s.conn != nil { s.conn.Close() }
+}
+
+// StartEventListener stores the notifications of domain events and
+// publishes the new ones for the replicas to push.
+func (s *RabbitMQServer) StartEventListener() error {
+	err := s.ch.ExchangeDeclare(
+		dtos.EventsExchange, "topic", true, false, false, false, nil,
+	)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare an exchange '%s': %v",
+			dtos.EventsExchange, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	q, err := s.ch.QueueDeclare(eventsQueue, true, false, false, false, nil)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare a queue '%s': %v",
+			eventsQueue, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.ch.QueueBind(q.Name, "#", dtos.EventsExchange, false, nil)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to bind a queue '%s': %v",
+			q.Name, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.ch.Qos(1, 0, false)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to set QoS: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	msgs, err := s.ch.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to register a consumer: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	go func() {
+		log.Printf(" [*] Awaiting domain events on queue '%s'", q.Name)
+		for d := range msgs {
+			var event dtos.DomainEvent
+			if err := json.Unmarshal(d.Body, &event); err != nil {
+				log.Printf("Failed to unmarshal a domain event: %v", err)
+				d.Nack(false, false)
+				continue
+			}
+
+			notifications, err := s.service.Handle(event)
+			if err != nil {
+				log.Printf(
+					"Failed to store notifications of event %s: %v",
+					event.ID, err,
+				)
+				d.Nack(false, true)
+				continue
+			}
+
+			// Notifications which are stored but not pushed are still
+			// in the inbox of their users.
+			for _, notification := range notifications {
+				s.push(notification)
+			}
+			d.Ack(false)
+		}
+	}()
+
+	return nil
+}
+
+// StartPushListener pushes the stored notifications to the clients
+// connected to this replica.
+func (s *RabbitMQServer) StartPushListener() error {
+	q, err := s.ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to declare a push queue: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.ch.QueueBind(q.Name, "", pushExchange, false, nil)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to bind a queue '%s': %v",
+			q.Name, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	msgs, err := s.ch.Consume(q.Name, "", true, true, false, false, nil)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to register a consumer: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	go func() {
+		log.Printf(" [*] Awaiting notifications on queue '%s'", q.Name)
+		for d := range msgs {
+			var notification models.Notification
+			if err := json.Unmarshal(d.Body, &notification); err != nil {
+				log.Printf("Failed to unmarshal a notification: %v", err)
+				continue
+			}
+
+			s.hub.Deliver(notification)
+		}
+	}()
+
+	return nil
+}
+
+func (s *RabbitMQServer) push(notification models.Notification) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		log.Printf(
+			"Failed to marshal notification %s: %v", notification.ID, err,
+		)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	err = s.ch.PublishWithContext(
+		ctx, pushExchange, "", false, false,
+		amqp.Publishing{
+			ContentType:	"text/json",
+			Body:		body,
+		},
+	)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to publish notification %s: %v",
+			notification.ID, err,
+		)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"log"
+	"sync"
+
+	"igaku/notification-service/models"
+)
+
+// subscriberBuffer is the number of notifications kept for a slow client.
+// Further notifications are dropped from its stream; they stay in the
+// inbox.
+const subscriberBuffer = 16
+
+// Hub fans notifications out to the clients connected to this replica.
+type Hub interface {
+	// Subscribe streams the notifications of the user until the returned
+	// function is called.
+	Subscribe(userID uuid.UUID) (<-chan models.Notification, func())
+	// Deliver pushes the notification to every connection of its user.
+	Deliver(notification models.Notification)
+}
+
+type hub struct {
+	mu		sync.Mutex
+	subscribers	map[uuid.UUID]map[chan models.Notification]struct{}
+}
+
+func NewHub() Hub {
+	return &hub{
+		subscribers: map[uuid.UUID]map[chan models.Notification]struct{}{},
+	}
+}
+
+func (h *hub) Subscribe(
+	userID uuid.UUID,
+) (<-chan models.Notification, func()) {
+	ch := make(chan models.Notification, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = map[chan models.Notification]struct{}{}
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (h *hub) Deliver(notification models.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+			log.Printf(
+				"Dropped notification %s for a slow client of user %s",
+				notification.ID, notification.UserID,
+			)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/notification-service/dtos"
+	"igaku/notification-service/models"
+	"igaku/notification-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+)
+
+// NotificationService keeps the notification inbox of every user.
+type NotificationService interface {
+	// Handle stores a notification of the event for each of its recipients
+	// and returns the new ones. Notifications of an event delivered before
+	// are not stored again.
+	Handle(event commonsDtos.DomainEvent) ([]models.Notification, error)
+	// List returns the notifications of the user, newest first.
+	List(userID uuid.UUID, unreadOnly bool) ([]models.Notification, error)
+	Unread(userID uuid.UUID) (*dtos.UnreadCount, error)
+	MarkRead(id, userID uuid.UUID) (*models.Notification, error)
+	MarkAllRead(userID uuid.UUID) error
+}
+
+type notificationService struct {
+	repo repositories.NotificationRepository
+}
+
+func NewNotificationService(
+	repo repositories.NotificationRepository,
+) NotificationService {
+	return &notificationService{repo: repo}
+}
+
+func (s *notificationService) Handle(
+	event commonsDtos.DomainEvent,
+) ([]models.Notification, error) {
+	now := time.Now()
+	seen := map[uuid.UUID]bool{}
+	notifications := []models.Notification{}
+	for _, userID := range event.Recipients {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		notifications = append(notifications, models.Notification{
+			ID: uuid.New(),
+			EventID: event.ID,
+			UserID: userID,
+			Type: event.Type,
+			ResourceID: event.ResourceID,
+			CreatedAt: now,
+		})
+	}
+
+	return s.repo.CreateAll(notifications)
+}
+
+func (s *notificationService) List(
+	userID uuid.UUID, unreadOnly bool,
+) ([]models.Notification, error) {
+	return s.repo.FindByUserID(userID, unreadOnly)
+}
+
+func (s *notificationService) Unread(
+	userID uuid.UUID,
+) (*dtos.UnreadCount, error) {
+	count, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.UnreadCount{Unread: count}, nil
+}
+
+func (s *notificationService) MarkRead(
+	id, userID uuid.UUID,
+) (*models.Notification, error) {
+	return s.repo.MarkRead(id, userID, time.Now())
+}
+
+func (s *notificationService) MarkAllRead(userID uuid.UUID) error {
+	_, err := s.repo.MarkAllRead(userID, time.Now())
+	return err
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"igaku/notification-service/models"
+	"igaku/notification-service/services"
+)
+
+func TestHub_DeliversToEveryConnectionOfTheUser(t *testing.T) {
+	hub := services.NewHub()
+
+	userID, otherID := uuid.New(), uuid.New()
+	first, unsubscribeFirst := hub.Subscribe(userID)
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := hub.Subscribe(userID)
+	defer unsubscribeSecond()
+	other, unsubscribeOther := hub.Subscribe(otherID)
+	defer unsubscribeOther()
+
+	notification := models.Notification{ID: uuid.New(), UserID: userID}
+	hub.Deliver(notification)
+
+	require.Len(t, first, 1)
+	assert.Equal(t, notification.ID, (<-first).ID)
+	require.Len(t, second, 1)
+	assert.Equal(t, notification.ID, (<-second).ID)
+	assert.Empty(t, other)
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub := services.NewHub()
+
+	userID := uuid.New()
+	notifications, unsubscribe := hub.Subscribe(userID)
+	unsubscribe()
+	// Unsubscribing twice, as deferred calls may, is harmless.
+	unsubscribe()
+
+	hub.Deliver(models.Notification{ID: uuid.New(), UserID: userID})
+
+	_, open := <-notifications
+	assert.False(t, open)
+}
+
+func TestHub_SlowClientDoesNotBlock(t *testing.T) {
+	hub := services.NewHub()
+
+	userID := uuid.New()
+	notifications, unsubscribe := hub.Subscribe(userID)
+	defer unsubscribe()
+
+	for i := 0; i < 100; i++ {
+		hub.Deliver(models.Notification{ID: uuid.New(), UserID: userID})
+	}
+
+	assert.Equal(t, cap(notifications), len(notifications))
+}
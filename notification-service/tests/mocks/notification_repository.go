@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/notification-service/models"
+)
+
+type NotificationRepository struct {
+	mock.Mock
+}
+
+func (m *NotificationRepository) CreateAll(
+	notifications []models.Notification,
+) ([]models.Notification, error) {
+	args := m.Called(notifications)
+
+	var r0 []models.Notification
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Notification)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *NotificationRepository) FindByUserID(
+	userID uuid.UUID, unreadOnly bool,
+) ([]models.Notification, error) {
+	args := m.Called(userID, unreadOnly)
+
+	var r0 []models.Notification
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Notification)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *NotificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepository) MarkRead(
+	id, userID uuid.UUID, at time.Time,
+) (*models.Notification, error) {
+	args := m.Called(id, userID, at)
+
+	var r0 *models.Notification
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Notification)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *NotificationRepository) MarkAllRead(
+	userID uuid.UUID, at time.Time,
+) (int64, error) {
+	args := m.Called(userID, at)
+
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"igaku/notification-service/controllers"
+	"igaku/notification-service/dtos"
+	"igaku/notification-service/models"
+	"igaku/notification-service/services"
+	"igaku/notification-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	commonsUtils "igaku/commons/utils"
+	igakuErrors "igaku/notification-service/errors"
+)
+
+func genToken(
+	t *testing.T, id uuid.UUID, role commonsModels.Role, ttl time.Duration,
+) string {
+	t.Helper()
+
+	user := &commonsModels.User{ID: id, Role: role}
+	token, err := commonsUtils.GenerateJWTToken(
+		user, time.Now(), time.Now().Add(ttl),
+	)
+	require.NoError(t, err)
+
+	return token
+}
+
+func setupNotificationRouter(
+	repo *mocks.NotificationRepository, hub services.Hub,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewNotificationService(repo)
+	notificationController := controllers.NewNotificationController(
+		service, hub, 10*time.Millisecond,
+	)
+
+	router := gin.Default()
+	notificationController.RegisterRoutes(router)
+	return router
+}
+
+func TestNotificationController_Stream(t *testing.T) {
+	hub := services.NewHub()
+	router := setupNotificationRouter(new(mocks.NotificationRepository), hub)
+
+	userID := uuid.New()
+	// Browsers pass the token in the query as they cannot set headers on
+	// an EventSource. The stream ends when the token expires.
+	token := genToken(t, userID, commonsModels.Patient, 2*time.Second)
+	req, _ := http.NewRequest(
+		http.MethodGet, "/notification/stream?access_token="+token, nil,
+	)
+	w := httptest.NewRecorder()
+
+	notification := models.Notification{ID: uuid.New(), UserID: userID}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				hub.Deliver(notification)
+			}
+		}
+	}()
+	router.ServeHTTP(w, req)
+	close(done)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream"))
+	body := w.Body.String()
+	assert.Contains(t, body, "event:notification")
+	assert.Contains(t, body, notification.ID.String())
+	assert.Contains(t, body, "event:heartbeat")
+	assert.True(t, strings.HasSuffix(body, "event:expired\ndata:Token has expired\n\n"))
+}
+
+func TestNotificationController_Stream_NoToken(t *testing.T) {
+	router := setupNotificationRouter(
+		new(mocks.NotificationRepository), services.NewHub(),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "/notification/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestNotificationController_List_Unread(t *testing.T) {
+	mockRepo := new(mocks.NotificationRepository)
+	router := setupNotificationRouter(mockRepo, services.NewHub())
+
+	userID := uuid.New()
+	notifications := []models.Notification{{ID: uuid.New(), UserID: userID}}
+	mockRepo.On("FindByUserID", userID, true).Return(notifications, nil).Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/notification/notifications?unread=true", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Doctor, time.Hour))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res []models.Notification
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Len(t, res, 1)
+	assert.Equal(t, notifications[0].ID, res[0].ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNotificationController_Unread(t *testing.T) {
+	mockRepo := new(mocks.NotificationRepository)
+	router := setupNotificationRouter(mockRepo, services.NewHub())
+
+	userID := uuid.New()
+	mockRepo.On("CountUnread", userID).Return(int64(2), nil)
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/notification/notifications/unread", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Admin, time.Hour))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var count dtos.UnreadCount
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &count))
+	assert.EqualValues(t, 2, count.Unread)
+}
+
+func TestNotificationController_MarkRead_OtherUsersNotification(t *testing.T) {
+	mockRepo := new(mocks.NotificationRepository)
+	router := setupNotificationRouter(mockRepo, services.NewHub())
+
+	userID, id := uuid.New(), uuid.New()
+	mockRepo.On("MarkRead", id, userID, mock.Anything).
+		Return(nil, &igakuErrors.NotificationNotFoundError{})
+
+	req, _ := http.NewRequest(
+		http.MethodPost, "/notification/notifications/"+id.String()+"/read", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Patient, time.Hour))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNotificationController_MarkAllRead(t *testing.T) {
+	mockRepo := new(mocks.NotificationRepository)
+	router := setupNotificationRouter(mockRepo, services.NewHub())
+
+	userID := uuid.New()
+	mockRepo.On("MarkAllRead", userID, mock.Anything).Return(int64(3), nil).Once()
+
+	req, _ := http.NewRequest(
+		http.MethodPost, "/notification/notifications/read", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Patient, time.Hour))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/notification-service/models"
+	"igaku/notification-service/repositories"
+	"igaku/notification-service/utils"
+	commonsDtos "igaku/commons/dtos"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/notification-service/errors"
+)
+
+func TestGormNotificationRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+	bookedEventID := uuid.MustParse("3a4b5c6d-7e8f-4a0b-9c1d-2e3f4a5b6c7d")
+
+	t.Run("CreateAll_SkipsRedeliveredEvents", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormNotificationRepository(db)
+		now := time.Now()
+		resourceID := uuid.New()
+		newEventID := uuid.New()
+		created, err := repo.CreateAll([]models.Notification{
+			{
+				ID: uuid.New(), EventID: bookedEventID, UserID: doctorID,
+				Type: commonsDtos.AppointmentBooked,
+				ResourceID: resourceID, CreatedAt: now,
+			},
+			{
+				ID: uuid.New(), EventID: newEventID, UserID: patientID,
+				Type: commonsDtos.LabResultRecorded,
+				ResourceID: patientID, CreatedAt: now,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, created, 1)
+		assert.Equal(t, newEventID, created[0].EventID)
+
+		notifications, err := repo.FindByUserID(doctorID, false)
+		require.NoError(t, err)
+		assert.Len(t, notifications, 2)
+	})
+
+	t.Run("ReadState", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormNotificationRepository(db)
+
+		count, err := repo.CountUnread(doctorID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		unread, err := repo.FindByUserID(doctorID, true)
+		require.NoError(t, err)
+		require.Len(t, unread, 2)
+		assert.Equal(t, commonsDtos.MessageReceived, unread[0].Type)
+
+		// The patient cannot mark the doctor's notification as read.
+		_, err = repo.MarkRead(unread[0].ID, patientID, time.Now())
+		assert.ErrorIs(t, err, &igakuErrors.NotificationNotFoundError{})
+
+		notification, err := repo.MarkRead(unread[0].ID, doctorID, time.Now())
+		require.NoError(t, err)
+		require.NotNil(t, notification.ReadAt)
+
+		read, err := repo.MarkAllRead(doctorID, time.Now())
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, read)
+
+		count, err = repo.CountUnread(doctorID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+	})
+}
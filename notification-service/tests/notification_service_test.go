@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+	"time"
+
+	"igaku/notification-service/models"
+	"igaku/notification-service/services"
+	"igaku/notification-service/tests/mocks"
+	commonsDtos "igaku/commons/dtos"
+)
+
+func TestNotificationService_Handle(t *testing.T) {
+	mockRepo := new(mocks.NotificationRepository)
+	service := services.NewNotificationService(mockRepo)
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	event := commonsDtos.DomainEvent{
+		ID: uuid.New(),
+		Type: commonsDtos.AppointmentBooked,
+		// A recipient listed twice gets a single notification.
+		Recipients: []uuid.UUID{patientID, doctorID, patientID},
+		ResourceID: uuid.New(),
+		OccurredAt: time.Now(),
+	}
+	mockRepo.
+		On("CreateAll", mock.MatchedBy(func(ns []models.Notification) bool {
+			if len(ns) != 2 {
+				return false
+			}
+			for _, n := range ns {
+				if n.EventID != event.ID ||
+					n.Type != event.Type ||
+					n.ResourceID != event.ResourceID ||
+					n.ReadAt != nil {
+					return false
+				}
+			}
+			return ns[0].UserID == patientID && ns[1].UserID == doctorID
+		})).
+		// The doctor got the event before.
+		Return([]models.Notification{{ID: uuid.New(), UserID: patientID}}, nil).
+		Once()
+
+	created, err := service.Handle(event)
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	assert.Equal(t, patientID, created[0].UserID)
+	mockRepo.AssertExpectations(t)
+}
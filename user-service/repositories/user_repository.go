@@ -10,6 +10,7 @@ import (
 
 	"igaku/user-service/errors"
 	"igaku/user-service/utils"
+	commonsDtos "igaku/commons/dtos"
 	commonsErrors "igaku/commons/errors"
 	"igaku/commons/models"
 )
@@ -22,6 +23,9 @@ type UserRepository interface {
 		orderBy models.UserOrderableField, orderMethod utils.Ordering,
 	) ([]models.User, error)
 	CountAll() (int64, error)
+	// Search returns a page of the users matching the filters and the
+	// number of all matching users.
+	Search(req commonsDtos.UserSearchRequest) ([]models.User, int64, error)
 	Persist(user *models.User) (error)
 }
 
@@ -78,6 +82,55 @@ func (r *gormUserRepository) CountAll() (int64, error) {
 	return count, nil
 }
 
+func (r *gormUserRepository) Search(
+	req commonsDtos.UserSearchRequest,
+) ([]models.User, int64, error) {
+	query := r.db.Model(&models.User{})
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	}
+	if req.Role != "" {
+		query = query.Where("role = ?", req.Role)
+	}
+	if req.Username != "" {
+		query = query.Where("username = ?", req.Username)
+	}
+	if req.UsernamePrefix != "" {
+		query = query.Where(
+			"LOWER(username) LIKE ?",
+			escapeLike(strings.ToLower(req.UsernamePrefix))+"%",
+		)
+	}
+	if req.Email != "" {
+		query = query.Where("LOWER(email) = ?", strings.ToLower(req.Email))
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Failed to count users: %v", err)
+		return nil, 0, &commonsErrors.DatabaseError{}
+	}
+
+	var users []models.User
+	err := query.
+		Order("username asc").
+		Offset(req.Offset).
+		Limit(req.Limit).
+		Find(&users).
+		Error
+	if err != nil {
+		log.Printf("Failed to search users: %v", err)
+		return nil, 0, &commonsErrors.DatabaseError{}
+	}
+
+	return users, count, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r *gormUserRepository) Persist(user *models.User) error {
 	err := r.db.Create(user).Error
 	if err != nil {
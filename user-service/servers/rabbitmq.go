@@ -2,6 +2,7 @@ package servers
 
 import (
 	"encoding/json"
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 
 	"context"
@@ -51,6 +52,24 @@ func (s *RabbitMQServer) Start() error {
 		return &commonsErrors.MessageBrokerError{}
 	}
 
+	err = s.StartFindByIDListener()
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to start `FindByIDListener`: %v",
+			err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.StartSearchListener()
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to start `SearchListener`: %v",
+			err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
 	err = s.StartPersistListener()
 	if err != nil {
 		log.Printf(
@@ -160,6 +179,206 @@ func (s *RabbitMQServer) StartFindByUsernameListener() error {
 	return nil
 }
 
+func (s *RabbitMQServer) StartFindByIDListener() error {
+	queueName := "find_by_id"
+
+	q, err := s.ch.QueueDeclare(queueName, false, false, false, false, nil)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare a queue '%s': %v",
+			queueName, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.ch.Qos(1, 0, false)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to set QoS: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	msgs, err := s.ch.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to register a consumer: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	go func() {
+		log.Printf(" [*] Awaiting RPC requests on queue '%s'", q.Name)
+		for d := range msgs {
+			idStr := string(d.Body)
+			log.Printf(
+				"Received RPC request for user ID: %s, ID: %s",
+				idStr, d.CorrelationId,
+			)
+
+			var resp dtos.RPCResponse
+			var userBytes []byte
+			var user *models.User
+
+			id, err := uuid.Parse(idStr)
+			if err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "INVALID_REQUEST",
+					Message: err.Error(),
+				}
+				goto send_response
+			}
+
+			user, err = s.service.GetAccountByID(id)
+			if err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "NOT_FOUND",
+					Message: err.Error(),
+				}
+				goto send_response
+			}
+
+			userBytes, err = json.Marshal(user)
+			if err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "INTERNAL",
+					Message: err.Error(),
+				}
+				goto send_response
+			}
+
+			resp.Data = userBytes
+
+		send_response:
+			respBytes, err := json.Marshal(resp)
+			if err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "INTERNAL",
+					Message: err.Error(),
+				}
+			}
+
+			publishCtx, cancelPublish := context.WithTimeout(
+				context.Background(), 8*time.Second,
+			)
+
+			err = s.ch.PublishWithContext(publishCtx,
+				"", d.ReplyTo, false, false,
+				amqp.Publishing{
+					ContentType:   "text/json",
+					CorrelationId: d.CorrelationId,
+					Body:          respBytes,
+				})
+			cancelPublish()
+
+			if err != nil {
+				log.Printf(
+					"Failed to publish reply for ID %s: %v",
+					d.CorrelationId, err,
+				)
+			} else {
+				d.Ack(false)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *RabbitMQServer) StartSearchListener() error {
+	queueName := "search_users"
+
+	q, err := s.ch.QueueDeclare(queueName, false, false, false, false, nil)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare a queue '%s': %v",
+			queueName, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	err = s.ch.Qos(1, 0, false)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to set QoS: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	msgs, err := s.ch.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to register a consumer: %v", err)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	go func() {
+		log.Printf(" [*] Awaiting RPC requests on queue '%s'", q.Name)
+		for d := range msgs {
+			log.Printf("Received user search RPC request, ID: %s", d.CorrelationId)
+
+			var req dtos.UserSearchRequest
+			var resp dtos.RPCResponse
+			var result *dtos.UserSearchResult
+			var resultBytes []byte
+
+			if err := json.Unmarshal(d.Body, &req); err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "INVALID_REQUEST",
+					Message: err.Error(),
+				}
+				goto send_response
+			}
+
+			result, err = s.service.SearchAccounts(req)
+			if err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "DATABASE_ERROR",
+					Message: err.Error(),
+				}
+				goto send_response
+			}
+
+			resultBytes, err = json.Marshal(result)
+			if err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "INTERNAL",
+					Message: err.Error(),
+				}
+				goto send_response
+			}
+
+			resp.Data = resultBytes
+
+		send_response:
+			respBytes, err := json.Marshal(resp)
+			if err != nil {
+				resp.Error = &dtos.RPCError{
+					Code: "INTERNAL",
+					Message: err.Error(),
+				}
+			}
+
+			publishCtx, cancelPublish := context.WithTimeout(
+				context.Background(), 8*time.Second,
+			)
+
+			err = s.ch.PublishWithContext(publishCtx,
+				"", d.ReplyTo, false, false,
+				amqp.Publishing{
+					ContentType:   "text/json",
+					CorrelationId: d.CorrelationId,
+					Body:          respBytes,
+				})
+			cancelPublish()
+
+			if err != nil {
+				log.Printf(
+					"Failed to publish reply for ID %s: %v",
+					d.CorrelationId, err,
+				)
+			} else {
+				d.Ack(false)
+			}
+		}
+	}()
+
+	return nil
+}
+
 func (s *RabbitMQServer) StartPersistListener() error {
 	queueName := "persist"
 
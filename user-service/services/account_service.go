@@ -9,6 +9,7 @@ import (
 	"igaku/user-service/repositories"
 	"igaku/user-service/utils"
 	"igaku/commons/models"
+	commonsDtos "igaku/commons/dtos"
 )
 
 type AccountService interface {
@@ -19,9 +20,16 @@ type AccountService interface {
 		orderMethod utils.Ordering,
 	) (*dtos.PaginatedResponse, error)
 	GetAccountByUsername(username string) (*models.User, error)
+	GetAccountByID(id uuid.UUID) (*models.User, error)
+	// SearchAccounts returns a page of users matching the filters, with
+	// their passwords left out.
+	SearchAccounts(req commonsDtos.UserSearchRequest) (*commonsDtos.UserSearchResult, error)
 	Persist(user *models.User) error
 }
 
+// maxSearchLimit caps the page size of account searches.
+const maxSearchLimit = 100
+
 type accountService struct {
        repo repositories.UserRepository
 }
@@ -40,6 +48,38 @@ func (s *accountService) GetAccountByUsername(username string) (*models.User, er
 	return user, nil
 }
 
+func (s *accountService) GetAccountByID(id uuid.UUID) (*models.User, error) {
+	user, err := s.repo.FindByID(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *accountService) SearchAccounts(
+	req commonsDtos.UserSearchRequest,
+) (*commonsDtos.UserSearchResult, error) {
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+	if req.Limit < 1 || req.Limit > maxSearchLimit {
+		req.Limit = maxSearchLimit
+	}
+
+	users, total, err := s.repo.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		users[i].Password = ""
+	}
+
+	return &commonsDtos.UserSearchResult{Users: users, Total: total}, nil
+}
+
 // TODO: Rename to CreateAccount
 func (s *accountService) Persist(user *models.User) error {
 	err := s.repo.Persist(user)
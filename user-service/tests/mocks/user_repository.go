@@ -5,6 +5,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"igaku/commons/models"
+	commonsDtos "igaku/commons/dtos"
 	"igaku/user-service/utils"
 )
 
@@ -60,6 +61,19 @@ func (m *UserRepository) CountAll() (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *UserRepository) Search(
+	req commonsDtos.UserSearchRequest,
+) ([]models.User, int64, error) {
+	args := m.Called(req)
+
+	var r0 []models.User
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.User)
+	}
+
+	return r0, args.Get(1).(int64), args.Error(2)
+}
+
 func (m *UserRepository) Persist(user *models.User) (error) {
 	args := m.Called(user)
 
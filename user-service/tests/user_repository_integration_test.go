@@ -16,11 +16,41 @@ import (
 	"igaku/user-service/repositories"
 	"igaku/user-service/utils"
 	"igaku/commons/models"
+	commonsDtos "igaku/commons/dtos"
 	igakuErrors "igaku/commons/errors"
 	testUtils "igaku/commons/utils"
 )
 
 func TestGormUserRepository(t *testing.T) {
+	t.Run("Search_FiltersByRoleAndUsername", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormUserRepository(db)
+
+		users, total, err := repo.Search(commonsDtos.UserSearchRequest{
+			Role: models.Doctor,
+			UsernamePrefix: "GHO",
+			Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, users, 1)
+		assert.Equal(t, "ghouse", users[0].Username)
+
+		users, total, err = repo.Search(commonsDtos.UserSearchRequest{
+			UsernamePrefix: "%",
+			Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Zero(t, total, "Expected wildcards to be matched literally")
+		assert.Empty(t, users)
+	})
+
 	t.Run("FindByID_Success", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
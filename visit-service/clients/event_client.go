@@ -0,0 +1,106 @@
+package clients
+
+import (
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	commonsErrors "igaku/commons/errors"
+	"igaku/commons/dtos"
+)
+
+type EventClient interface {
+	Publish(
+		eventType dtos.EventType, resourceID uuid.UUID,
+		recipients ...uuid.UUID,
+	) error
+	Shutdown()
+}
+
+type eventClient struct {
+	url	string
+	conn	*amqp.Connection
+	ch	*amqp.Channel
+}
+
+func NewEventClient(url string) (EventClient, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to connect: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		log.Printf("[RabbitMQ] Failed to create a channel: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	err = ch.ExchangeDeclare(
+		dtos.EventsExchange, "topic", true, false, false, false, nil,
+	)
+	if err != nil {
+		ch.Close()
+		conn.Close()
+		log.Printf(
+			"[RabbitMQ] Failed to declare an exchange '%s': %v",
+			dtos.EventsExchange, err,
+		)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	return &eventClient{url: url, conn: conn, ch: ch}, nil
+}
+
+func (c *eventClient) Shutdown() {
+	if c.ch != nil {
+		c.ch.Close()
+	}
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+func (c *eventClient) Publish(
+	eventType dtos.EventType, resourceID uuid.UUID, recipients ...uuid.UUID,
+) error {
+	event := dtos.DomainEvent{
+		ID: uuid.New(),
+		Type: eventType,
+		Recipients: recipients,
+		ResourceID: resourceID,
+		OccurredAt: time.Now(),
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Println("Failed to marshal the `DomainEvent`")
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	err = c.ch.PublishWithContext(
+		ctx, dtos.EventsExchange, string(eventType), false, false,
+		amqp.Publishing{
+			ContentType:	"text/json",
+			DeliveryMode:	amqp.Persistent,
+			MessageId:	event.ID.String(),
+			Body:		body,
+		},
+	)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to publish '%s' event: %v",
+			eventType, err,
+		)
+		return &commonsErrors.MessageBrokerError{}
+	}
+
+	return nil
+}
@@ -16,8 +16,15 @@ import (
 )
 
 type GeoClient interface {
-	ReverseGeocode(lat, lon string) (*dtos.Location, error)
-	LookupLocation(id int64) (*dtos.Location, error)
+	ReverseGeocode(lat, lon float64) (*dtos.Location, error)
+	// LookupLocation returns the object with the given type and ID, or
+	// nil if there is none. Objects of unknown type are looked up as
+	// nodes, ways and relations in turn.
+	LookupLocation(osmType dtos.OsmType, id int64) (*dtos.Location, error)
+	Search(address string) ([]dtos.Location, error)
+	// EstimateRoute estimates the routes between two points by the modes
+	// of the request, or by all modes offered when none is given.
+	EstimateRoute(req dtos.RouteRequest) ([]dtos.RouteEstimate, error)
 	Shutdown()
 }
 
@@ -132,8 +139,11 @@ func (c *geoClient) call(routingKey string, body []byte) ([]byte, error) {
 	}
 }
 
-func (c *geoClient) ReverseGeocode(lat, lon string) (*dtos.Location, error) {
-	req := dtos.ReverseGeocodeRequest{Lat: lat, Lon: lon}
+func (c *geoClient) ReverseGeocode(lat, lon float64) (*dtos.Location, error) {
+	req := dtos.ReverseGeocodeRequest{
+		Lat: dtos.Coordinate(lat),
+		Lon: dtos.Coordinate(lon),
+	}
 	reqBytes, err := json.Marshal(req)
 	if err != nil {
 		log.Printf(
@@ -164,7 +174,9 @@ func (c *geoClient) ReverseGeocode(lat, lon string) (*dtos.Location, error) {
 	}
 
 	if rpcResp.Error != nil {
-		if rpcResp.Error.Code == "TIMEOUT" || rpcResp.Error.Code == "EXTERNAL" {
+		if rpcResp.Error.Code == "TIMEOUT" ||
+			rpcResp.Error.Code == "EXTERNAL" ||
+			rpcResp.Error.Code == "INVALID_REQUEST" {
 			return nil, fmt.Errorf(rpcResp.Error.Message)
 		}
 		errmsg := fmt.Sprintf(
@@ -185,8 +197,10 @@ func (c *geoClient) ReverseGeocode(lat, lon string) (*dtos.Location, error) {
 	return &location, nil
 }
 
-func (c *geoClient) LookupLocation(id int64) (*dtos.Location, error) {
-	req := dtos.LocationLookupRequest{ID: id}
+func (c *geoClient) LookupLocation(
+	osmType dtos.OsmType, id int64,
+) (*dtos.Location, error) {
+	req := dtos.LocationLookupRequest{ID: id, OsmType: osmType}
 	reqBytes, err := json.Marshal(req)
 	if err != nil {
 		log.Printf(
@@ -241,3 +255,114 @@ func (c *geoClient) LookupLocation(id int64) (*dtos.Location, error) {
 	}
 	return &location, nil
 }
+
+func (c *geoClient) Search(address string) ([]dtos.Location, error) {
+	req := dtos.GeocodeSearchRequest{Address: address}
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf(
+			"Failed to marshal a geocode search request: %v",
+			err,
+		)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	reply, err := c.call("geocode_search", reqBytes)
+	if err != nil {
+		errmsg := fmt.Sprintf(
+			"[RabbitMQ] Failed to publish request for geocoding: %v",
+			err,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var rpcResp dtos.RPCResponse
+	if err := json.Unmarshal(reply, &rpcResp); err != nil {
+		errmsg := fmt.Sprintf(
+			"[RabbitMQ] Failed to unmarshal RPC response: %v",
+			err,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	if rpcResp.Error != nil {
+		if rpcResp.Error.Code == "TIMEOUT" ||
+			rpcResp.Error.Code == "EXTERNAL" ||
+			rpcResp.Error.Code == "INVALID_REQUEST" {
+			return nil, fmt.Errorf(rpcResp.Error.Message)
+		}
+		errmsg := fmt.Sprintf(
+			"Geo service internal error: %s",
+			rpcResp.Error.Message,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var locations []dtos.Location
+	if err := json.Unmarshal(rpcResp.Data, &locations); err != nil {
+		errmsg := fmt.Sprintf("Failed to unmarshal locations: %v", err)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	return locations, nil
+}
+
+func (c *geoClient) EstimateRoute(
+	req dtos.RouteRequest,
+) ([]dtos.RouteEstimate, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		log.Printf(
+			"Failed to marshal a route estimate request: %v",
+			err,
+		)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	reply, err := c.call("route_estimate", reqBytes)
+	if err != nil {
+		errmsg := fmt.Sprintf(
+			"[RabbitMQ] Failed to publish request for route estimate: %v",
+			err,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var rpcResp dtos.RPCResponse
+	if err := json.Unmarshal(reply, &rpcResp); err != nil {
+		errmsg := fmt.Sprintf(
+			"[RabbitMQ] Failed to unmarshal RPC response: %v",
+			err,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	if rpcResp.Error != nil {
+		if rpcResp.Error.Code == "TIMEOUT" ||
+			rpcResp.Error.Code == "EXTERNAL" ||
+			rpcResp.Error.Code == "INVALID_REQUEST" {
+			return nil, fmt.Errorf(rpcResp.Error.Message)
+		}
+		errmsg := fmt.Sprintf(
+			"Geo service internal error: %s",
+			rpcResp.Error.Message,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var estimates []dtos.RouteEstimate
+	if err := json.Unmarshal(rpcResp.Data, &estimates); err != nil {
+		errmsg := fmt.Sprintf("Failed to unmarshal route estimates: %v", err)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	return estimates, nil
+}
@@ -0,0 +1,110 @@
+package clients
+
+import (
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	commonsErrors "igaku/commons/errors"
+	"igaku/commons/dtos"
+)
+
+type MailClient interface {
+	SendMail(to []string, msg []byte) error
+	Shutdown()
+}
+
+type mailClient struct {
+	url	string
+	conn	*amqp.Connection
+	ch	*amqp.Channel
+}
+
+type idleMailClient struct {}
+
+func NewMailClient(url string) (MailClient, error) {
+	isMailEnabled := os.Getenv("MAIL_ENABLED") != ""
+	if !isMailEnabled {
+		return &idleMailClient{}, nil
+	}
+
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to connect: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		log.Printf("[RabbitMQ] Failed to create a channel: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	return &mailClient{url: url, conn: conn, ch: ch}, nil
+}
+
+func (c *mailClient) Shutdown() {
+	if c.ch != nil {
+		c.ch.Close()
+	}
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+func (c *idleMailClient) Shutdown() {}
+
+func (c *mailClient) SendMail(to []string, msg []byte) error {
+	exchangeName := "mail"
+
+	err := c.ch.ExchangeDeclare(
+		exchangeName, "fanout", true, false, false, false, nil,
+	)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to declare an exchange '%s': %v",
+			exchangeName, err,
+		)
+		return &commonsErrors.MailSendingError{
+			Err: &commonsErrors.MessageBrokerError{},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	sendMailReq := dtos.SendMailRequest{To: to, Msg: msg}
+	body, err := json.Marshal(sendMailReq)
+	if err != nil {
+		log.Println("Failed to marshal the `SendMailRequest`")
+		return &commonsErrors.MailSendingError{}
+	}
+
+	err = c.ch.PublishWithContext(
+		ctx, exchangeName, "", false, false,
+		amqp.Publishing{
+			ContentType:	"text/json",
+			Body:		body,
+		},
+	)
+	if err != nil {
+		log.Printf(
+			"[RabbitMQ] Failed to publish request to `mail` queue: %v",
+			err,
+		)
+		return &commonsErrors.MailSendingError{
+			Err: &commonsErrors.MessageBrokerError{},
+		}
+	}
+
+	return nil
+}
+
+func (c *idleMailClient) SendMail(_ []string, _ []byte) error {
+	return nil
+}
@@ -0,0 +1,211 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"igaku/commons/dtos"
+	"igaku/commons/models"
+	commonsErrors "igaku/commons/errors"
+)
+
+type UserClient interface {
+	FindByID(id uuid.UUID) (*models.User, error)
+	Search(req dtos.UserSearchRequest) (*dtos.UserSearchResult, error)
+	Shutdown()
+}
+
+type userClient struct {
+	url		string
+	conn		*amqp.Connection
+	ch		*amqp.Channel
+	replyMsgs	<-chan amqp.Delivery
+	pendingCalls	sync.Map
+}
+
+func NewUserClient(url string) (UserClient, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to connect: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		log.Printf("[RabbitMQ] Failed to create a channel: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	replyMsgs, err := ch.Consume(
+		"amq.rabbitmq.reply-to", "",
+		true, true, false, false, nil,
+	)
+	if err != nil {
+		ch.Close()
+		conn.Close()
+		log.Printf(
+			"[RabbitMQ] Failed to consume `reply-to` queue: %v",
+			err,
+		)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	client := &userClient{
+		url: url, conn: conn, ch: ch, replyMsgs: replyMsgs,
+	}
+
+	go client.listen()
+
+	return client, nil
+}
+
+func (c *userClient) listen() {
+	for msg := range c.replyMsgs {
+		if val, ok := c.pendingCalls.Load(msg.CorrelationId); ok {
+			res := val.(*responseChan)
+			select {
+				case res.ch <- msg.Body:
+				default:
+			}
+			c.pendingCalls.Delete(msg.CorrelationId)
+		}
+	}
+}
+
+func (c *userClient) Shutdown() {
+	if c.ch != nil {
+		c.ch.Close()
+	}
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+func (c *userClient) call(routingKey string, body []byte) ([]byte, error) {
+	corrID := uuid.New().String()
+	res := &responseChan{
+		ch:	make(chan []byte, 1),
+		err:	make(chan error, 1),
+	}
+
+	c.pendingCalls.Store(corrID, res)
+
+	err := c.ch.Publish(
+		"",
+		routingKey,
+		false,
+		false,
+		amqp.Publishing{
+			ContentType:	"application/json",
+			CorrelationId:	corrID,
+			ReplyTo:	"amq.rabbitmq.reply-to",
+			Body:		body,
+		},
+	)
+	if err != nil {
+		c.pendingCalls.Delete(corrID)
+		log.Printf("[RabbitMQ] Failed to publish a message: %v", err)
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	select {
+	case reply := <-res.ch:
+		return reply, nil
+	case <-ctx.Done():
+		c.pendingCalls.Delete(corrID)
+		log.Println("[RabbitMQ] Timeout waiting for RPC response")
+		return nil, &commonsErrors.MessageBrokerError{}
+	}
+}
+
+func (c *userClient) FindByID(id uuid.UUID) (*models.User, error) {
+	reply, err := c.call("find_by_id", []byte(id.String()))
+	if err != nil {
+		errmsg := fmt.Sprintf(
+			"[RabbitMQ] Failed to publish request for user ID '%s': %v",
+			id, err,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var rpcResp dtos.RPCResponse
+	if err := json.Unmarshal(reply, &rpcResp); err != nil {
+		errmsg := fmt.Sprintf(
+			"[RabbitMQ] Failed to unmarshal RPC response: %v", err,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	if rpcResp.Error != nil {
+		if rpcResp.Error.Code == "NOT_FOUND" {
+			log.Printf("User not found: %s", rpcResp.Error.Message)
+			return nil, &commonsErrors.UserNotFoundError{}
+		}
+		errmsg := fmt.Sprintf(
+			"User service internal error: %s",
+			rpcResp.Error.Message,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var user models.User
+	if err := json.Unmarshal(rpcResp.Data, &user); err != nil {
+		errmsg := fmt.Sprintf("Failed to unmarshal a user: %v", err)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	return &user, nil
+}
+
+func (c *userClient) Search(
+	req dtos.UserSearchRequest,
+) (*dtos.UserSearchResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("Failed to marshal user search request: %v", err)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	reply, err := c.call("search_users", body)
+	if err != nil {
+		log.Printf("[RabbitMQ] Failed to publish user search request: %v", err)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var rpcResp dtos.RPCResponse
+	if err := json.Unmarshal(reply, &rpcResp); err != nil {
+		errmsg := fmt.Sprintf(
+			"[RabbitMQ] Failed to unmarshal RPC response: %v", err,
+		)
+		log.Println(errmsg)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	if rpcResp.Error != nil {
+		log.Printf("User service search error: %s", rpcResp.Error.Message)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	var result dtos.UserSearchResult
+	if err := json.Unmarshal(rpcResp.Data, &result); err != nil {
+		log.Printf("Failed to unmarshal user search result: %v", err)
+		return nil, &commonsErrors.InternalError{}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// defaultSlotSearchWindow is how far ahead free slots are listed when the
+// caller does not specify an end of the search range.
+const defaultSlotSearchWindow = 14 * 24 * time.Hour
+
+type AppointmentController struct {
+	service services.AppointmentService
+}
+
+func NewAppointmentController(service services.AppointmentService) *AppointmentController {
+	return &AppointmentController{service: service}
+}
+
+// CreateSlot publishes a new bookable slot.
+// @Summary	Create slot
+// @Description	Publishes a bookable slot at an organization. Doctors create slots for themselves, admins have to specify the doctor.
+// @Tags	Appointments
+// @Accept	json
+// @Produce	json
+// @Param	slot body dtos.SlotRequest true "Slot details"
+// @Success	201 {object} models.Slot "Successfully created slot"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or time range"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Organization not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to create slot"
+// @Security	BearerAuth
+// @Router	/visit/slots [post]
+func (ctrl *AppointmentController) CreateSlot(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.SlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	doctorID := userID
+	if role == commonsModels.Admin {
+		if req.DoctorID == nil {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: "Doctor ID is required",
+			})
+			return
+		}
+		doctorID = *req.DoctorID
+	}
+
+	slot, err := ctrl.service.CreateSlot(doctorID, req)
+	if err != nil {
+		if errors.Is(err, &igakuErrors.InvalidTimeRangeError{}) {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else if errors.Is(err, &igakuErrors.OrganizationNotFoundError{}) {
+			c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+				Message: "Failed to create slot",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, slot)
+}
+
+// ListFreeSlots lists slots that can still be booked.
+// @Summary	List free slots
+// @Description	Lists free slots starting within the given time range, optionally narrowed down to a doctor and an organization. The range defaults to the next 14 days.
+// @Tags	Appointments
+// @Produce	json
+// @Param	doctor_id query string false "Doctor ID (UUIDv4 format)"
+// @Param	organization_id query string false "Organization ID (UUIDv4 format)"
+// @Param	from query string false "Start of the range (RFC 3339)"
+// @Param	to query string false "End of the range (RFC 3339)"
+// @Success	200 {array} models.Slot "Successfully retrieved free slots"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve slots"
+// @Security	BearerAuth
+// @Router	/visit/slots [get]
+func (ctrl *AppointmentController) ListFreeSlots(c *gin.Context) {
+	doctorID, ok := optionalUUIDQuery(c, "doctor_id")
+	if !ok {
+		return
+	}
+	orgID, ok := optionalUUIDQuery(c, "organization_id")
+	if !ok {
+		return
+	}
+
+	from := time.Now()
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: "Invalid 'from' parameter",
+			})
+			return
+		}
+		from = parsed
+	}
+
+	to := from.Add(defaultSlotSearchWindow)
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: "Invalid 'to' parameter",
+			})
+			return
+		}
+		to = parsed
+	}
+
+	slots, err := ctrl.service.ListFreeSlots(doctorID, orgID, from, to)
+	if err != nil {
+		if errors.Is(err, &igakuErrors.InvalidTimeRangeError{}) {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+				Message: "Failed to retrieve slots",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+// Book books a free slot for the current patient.
+// @Summary	Book slot
+// @Description	Books a free slot for the currently logged-in patient. Patients with a home address get estimates of the travel to the organization by every mode the geo service offers.
+// @Tags	Appointments
+// @Produce	json
+// @Param	id path string true "Slot ID (UUIDv4 format)"
+// @Success	201 {object} models.Appointment "Successfully booked appointment"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Slot not found"
+// @Failure	409 {object} commonsDtos.ErrorResponse "Conflict - Slot is no longer available"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to book slot"
+// @Security	BearerAuth
+// @Router	/visit/slots/{id}/book [post]
+func (ctrl *AppointmentController) Book(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	slotID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	appt, err := ctrl.service.Book(slotID, patientID)
+	if err != nil {
+		if errors.Is(err, &igakuErrors.SlotNotFoundError{}) {
+			c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else if errors.Is(err, &igakuErrors.SlotUnavailableError{}) {
+			c.JSON(http.StatusConflict, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+				Message: "Failed to book slot",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, appt)
+}
+
+// ListAppointments lists the appointments of the current user.
+// @Summary	List appointments
+// @Description	Lists the appointments of the currently logged-in patient, or of the currently logged-in doctor.
+// @Tags	Appointments
+// @Produce	json
+// @Success	200 {array} models.Appointment "Successfully retrieved appointments"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve appointments"
+// @Security	BearerAuth
+// @Router	/visit/appointments [get]
+func (ctrl *AppointmentController) ListAppointments(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+
+	appts, err := ctrl.service.ListAppointments(userID, role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: "Failed to retrieve appointments",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, appts)
+}
+
+// Cancel cancels an appointment.
+// @Summary	Cancel appointment
+// @Description	Cancels an appointment of the currently logged-in patient or doctor. Admins may cancel any appointment. The freed slot is offered to the waitlist.
+// @Tags	Appointments
+// @Produce	json
+// @Param	id path string true "Appointment ID (UUIDv4 format)"
+// @Success	200 {object} models.Appointment "Successfully cancelled appointment"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Appointment not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to cancel appointment"
+// @Security	BearerAuth
+// @Router	/visit/appointments/{id}/cancel [post]
+func (ctrl *AppointmentController) Cancel(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	appt, err := ctrl.service.Cancel(id, userID, role)
+	if err != nil {
+		if errors.Is(err, &igakuErrors.AppointmentNotFoundError{}) {
+			c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else if errors.Is(err, &igakuErrors.AccessDeniedError{}) {
+			c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+				Message: "Failed to cancel appointment",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, appt)
+}
+
+func (ctrl *AppointmentController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit")
+	routes.Use(middleware.Authenticate())
+	{
+		routes.GET("/slots", ctrl.ListFreeSlots)
+		routes.POST(
+			"/slots",
+			middleware.Authorize(commonsModels.Doctor, commonsModels.Admin),
+			ctrl.CreateSlot,
+		)
+		routes.POST(
+			"/slots/:id/book",
+			middleware.Authorize(commonsModels.Patient),
+			ctrl.Book,
+		)
+		routes.GET(
+			"/appointments",
+			middleware.Authorize(commonsModels.Patient, commonsModels.Doctor),
+			ctrl.ListAppointments,
+		)
+		routes.POST(
+			"/appointments/:id/cancel",
+			middleware.Authorize(
+				commonsModels.Patient, commonsModels.Doctor, commonsModels.Admin,
+			),
+			ctrl.Cancel,
+		)
+	}
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type CareTeamController struct {
+	service services.CareTeamService
+}
+
+func NewCareTeamController(service services.CareTeamService) *CareTeamController {
+	return &CareTeamController{service: service}
+}
+
+// List retrieves the care team of a patient.
+// @Summary	Get care team
+// @Description	Lists the doctors currently on the care team of a patient, the primary doctor first. Only the patient, admins and the members of the care team may see it.
+// @Tags	Care Teams
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Success	200 {array} models.CareAssignment "Successfully retrieved care team"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve care team"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/care-team [get]
+func (ctrl *CareTeamController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	team, err := ctrl.service.List(patientID, userID, role)
+	if err != nil {
+		handleCareTeamError(c, err, "Failed to retrieve care team")
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+// Assign places a doctor on the care team of a patient.
+// @Summary	Assign care team member
+// @Description	Places a doctor on the care team of a patient at one of the organizations. Assigning a primary doctor makes the previous one a regular member. Doctors assigned this way stay on the care team until removed, regardless of appointments.
+// @Tags	Care Teams
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	assignment body dtos.CareAssignmentRequest true "Assignment details"
+// @Success	201 {object} models.CareAssignment "Successfully assigned care team member"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload, patient or doctor"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Organization not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to assign care team member"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/care-team [post]
+func (ctrl *CareTeamController) Assign(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.CareAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	assignment, err := ctrl.service.Assign(patientID, userID, req)
+	if err != nil {
+		handleCareTeamError(c, err, "Failed to assign care team member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// Remove removes a doctor from the care team of a patient.
+// @Summary	Remove care team member
+// @Description	Removes an assignment from the care team of a patient. The doctor loses access to the patient unless they are still on the care team at another organization or the patient's consent permits them.
+// @Tags	Care Teams
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	assignmentId path string true "Assignment ID (UUIDv4 format)"
+// @Success	200 {object} models.CareAssignment "Successfully removed care team member"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Care team member not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to remove care team member"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/care-team/{assignmentId} [delete]
+func (ctrl *CareTeamController) Remove(c *gin.Context) {
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "assignmentId")
+	if !ok {
+		return
+	}
+
+	assignment, err := ctrl.service.Remove(id, patientID)
+	if err != nil {
+		handleCareTeamError(c, err, "Failed to remove care team member")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// Patients lists the patients of the currently logged-in doctor.
+// @Summary	List own patients
+// @Description	Lists the care team assignments of the currently logged-in doctor, i.e. the patients they treat, one entry per patient and organization.
+// @Tags	Care Teams
+// @Produce	json
+// @Success	200 {array} models.CareAssignment "Successfully retrieved patients"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve patients"
+// @Security	BearerAuth
+// @Router	/visit/patients [get]
+func (ctrl *CareTeamController) Patients(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	assignments, err := ctrl.service.Patients(userID)
+	if err != nil {
+		handleCareTeamError(c, err, "Failed to retrieve patients")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (ctrl *CareTeamController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/patients")
+	routes.Use(middleware.Authenticate())
+	{
+		adminOnly := middleware.Authorize(commonsModels.Admin)
+
+		routes.GET("", middleware.Authorize(commonsModels.Doctor), ctrl.Patients)
+		routes.GET("/:id/care-team", ctrl.List)
+		routes.POST("/:id/care-team", adminOnly, ctrl.Assign)
+		routes.DELETE("/:id/care-team/:assignmentId", adminOnly, ctrl.Remove)
+	}
+}
+
+func handleCareTeamError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidCareTeamMemberError{}),
+		errors.Is(err, &igakuErrors.InvalidCareTeamPatientError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AccessDeniedError{}):
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.CareAssignmentNotFoundError{}),
+		errors.Is(err, &igakuErrors.OrganizationNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type ConsentController struct {
+	service services.ConsentService
+}
+
+func NewConsentController(service services.ConsentService) *ConsentController {
+	return &ConsentController{service: service}
+}
+
+// Grant records a consent of the currently logged-in patient.
+// @Summary	Grant consent
+// @Description	Permits or denies a doctor, or every doctor working at an organization, access to categories of the patient's clinical data (records, genomics, lab_results, documents, encounters, prescriptions), optionally for a limited time. A deny withdraws the access doctors have as members of the patient's care team and takes precedence over any permit, but not over emergency access, of which the patient is notified by mail.
+// @Tags	Consents
+// @Accept	json
+// @Produce	json
+// @Param	consent body dtos.ConsentRequest true "Consent details"
+// @Success	201 {object} models.Consent "Successfully granted consent"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload, category, grantee or time range"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to grant consent"
+// @Security	BearerAuth
+// @Router	/visit/consents [post]
+func (ctrl *ConsentController) Grant(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.ConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	consent, err := ctrl.service.Grant(userID, req)
+	if err != nil {
+		handleConsentError(c, err, "Failed to grant consent")
+		return
+	}
+
+	c.JSON(http.StatusCreated, consent)
+}
+
+// List lists the consents of the currently logged-in patient.
+// @Summary	List consents
+// @Description	Lists the consents of the currently logged-in patient, newest first, including revoked and expired ones unless only those in force are requested.
+// @Tags	Consents
+// @Produce	json
+// @Param	active query bool false "Only list consents currently in force"
+// @Success	200 {array} models.Consent "Successfully retrieved consents"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve consents"
+// @Security	BearerAuth
+// @Router	/visit/consents [get]
+func (ctrl *ConsentController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	consents, err := ctrl.service.List(userID, c.Query("active") == "true")
+	if err != nil {
+		handleConsentError(c, err, "Failed to retrieve consents")
+		return
+	}
+
+	c.JSON(http.StatusOK, consents)
+}
+
+// Revoke revokes a consent of the currently logged-in patient.
+// @Summary	Revoke consent
+// @Description	Revokes a consent with immediate effect. The consent is kept in the history.
+// @Tags	Consents
+// @Produce	json
+// @Param	id path string true "Consent ID (UUIDv4 format)"
+// @Success	200 {object} models.Consent "Successfully revoked consent"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Consent not found"
+// @Failure	409 {object} commonsDtos.ErrorResponse "Conflict - Consent already revoked"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to revoke consent"
+// @Security	BearerAuth
+// @Router	/visit/consents/{id} [delete]
+func (ctrl *ConsentController) Revoke(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	consent, err := ctrl.service.Revoke(id, userID)
+	if err != nil {
+		handleConsentError(c, err, "Failed to revoke consent")
+		return
+	}
+
+	c.JSON(http.StatusOK, consent)
+}
+
+// History lists the changes to the consents of the currently logged-in
+// patient.
+// @Summary	Get consent history
+// @Description	Lists every grant and revocation of the currently logged-in patient's consents, newest first, as they were at the time.
+// @Tags	Consents
+// @Produce	json
+// @Success	200 {array} models.ConsentEvent "Successfully retrieved history"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve history"
+// @Security	BearerAuth
+// @Router	/visit/consents/history [get]
+func (ctrl *ConsentController) History(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	events, err := ctrl.service.History(userID)
+	if err != nil {
+		handleConsentError(c, err, "Failed to retrieve history")
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (ctrl *ConsentController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/consents")
+	routes.Use(middleware.Authenticate(), middleware.Authorize(commonsModels.Patient))
+	{
+		routes.POST("", ctrl.Grant)
+		routes.GET("", ctrl.List)
+		routes.GET("/history", ctrl.History)
+		routes.DELETE("/:id", ctrl.Revoke)
+	}
+}
+
+func handleConsentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidConsentCategoryError{}),
+		errors.Is(err, &igakuErrors.InvalidGranteeError{}),
+		errors.Is(err, &igakuErrors.InvalidTimeRangeError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.ConsentNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.ConsentRevokedError{}):
+		c.JSON(http.StatusConflict, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"net/http"
+	"time"
+
+	"igaku/commons/dtos"
+	"igaku/commons/models"
+)
+
+// userIDFromContext returns the ID of the authenticated user. On failure
+// it writes an error response and returns false.
+func userIDFromContext(c *gin.Context) (uuid.UUID, bool) {
+	idStr, exists := c.Get("id")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "User ID not found in context",
+		})
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(idStr.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "Invalid user ID format in token",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// roleFromContext returns the role of the authenticated user. On failure
+// it writes an error response and returns false.
+func roleFromContext(c *gin.Context) (models.Role, bool) {
+	roleVal, exists := c.Get("role")
+	if !exists {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "User role not found in context",
+		})
+		return "", false
+	}
+
+	role, ok := roleVal.(models.Role)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "Invalid role type in context",
+		})
+		return "", false
+	}
+
+	return role, true
+}
+
+// parseIDParam parses the UUID path parameter of the given name. On
+// failure it writes an error response and returns false.
+func parseIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Message: "Invalid UUID format",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// optionalUUIDQuery parses an optional UUID query parameter. On failure it
+// writes an error response and returns false.
+func optionalUUIDQuery(c *gin.Context, name string) (*uuid.UUID, bool) {
+	str := c.Query(name)
+	if str == "" {
+		return nil, true
+	}
+
+	id, err := uuid.Parse(str)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Message: "Invalid '" + name + "' parameter",
+		})
+		return nil, false
+	}
+
+	return &id, true
+}
+
+// optionalTimeQuery parses an optional RFC 3339 query parameter. On
+// failure it writes an error response and returns false.
+func optionalTimeQuery(c *gin.Context, name string) (*time.Time, bool) {
+	str := c.Query(name)
+	if str == "" {
+		return nil, true
+	}
+
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Message: "Invalid '" + name + "' parameter",
+		})
+		return nil, false
+	}
+
+	return &t, true
+}
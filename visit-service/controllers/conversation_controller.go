@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type ConversationController struct {
+	service services.ConversationService
+}
+
+func NewConversationController(
+	service services.ConversationService,
+) *ConversationController {
+	return &ConversationController{service: service}
+}
+
+// Start starts a conversation of the currently logged-in user.
+// @Summary	Start conversation
+// @Description	Starts a conversation between a patient and a doctor with its first message. Patients write to doctors and doctors to patients. The doctor must be on the patient's care team, unless the conversation is about an appointment of the two. A document of the patient may be attached if the doctor may see the patient's documents. The recipient is notified by mail, without the contents of the message.
+// @Tags	Messaging
+// @Accept	json
+// @Produce	json
+// @Param	conversation body dtos.ConversationRequest true "Conversation details"
+// @Success	201 {object} models.Conversation "Successfully started conversation"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - No care relationship or access to the attachment"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Appointment or document not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to start conversation"
+// @Security	BearerAuth
+// @Router	/visit/conversations [post]
+func (ctrl *ConversationController) Start(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.ConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	conversation, err := ctrl.service.Start(userID, role, req)
+	if err != nil {
+		handleConversationError(c, err, "Failed to start conversation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, conversation)
+}
+
+// List lists the conversations of the currently logged-in user.
+// @Summary	List conversations
+// @Description	Lists the conversations of the currently logged-in user, most recently active first, with the number of unread messages in each.
+// @Tags	Messaging
+// @Produce	json
+// @Success	200 {array} models.Conversation "Successfully retrieved conversations"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve conversations"
+// @Security	BearerAuth
+// @Router	/visit/conversations [get]
+func (ctrl *ConversationController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	conversations, err := ctrl.service.List(userID)
+	if err != nil {
+		handleConversationError(c, err, "Failed to retrieve conversations")
+		return
+	}
+
+	c.JSON(http.StatusOK, conversations)
+}
+
+// Unread counts the unread messages of the currently logged-in user.
+// @Summary	Count unread messages
+// @Description	Counts the messages sent to the currently logged-in user which they have not read yet, across all of their conversations.
+// @Tags	Messaging
+// @Produce	json
+// @Success	200 {object} dtos.UnreadCount "Successfully counted unread messages"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to count unread messages"
+// @Security	BearerAuth
+// @Router	/visit/conversations/unread [get]
+func (ctrl *ConversationController) Unread(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	count, err := ctrl.service.Unread(userID)
+	if err != nil {
+		handleConversationError(c, err, "Failed to count unread messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, count)
+}
+
+// Messages lists the messages of a conversation.
+// @Summary	List messages
+// @Description	Lists the messages of a conversation of the currently logged-in user, oldest first. Messages read by their recipient carry the time they were read.
+// @Tags	Messaging
+// @Produce	json
+// @Param	id path string true "Conversation ID (UUIDv4 format)"
+// @Success	200 {array} models.Message "Successfully retrieved messages"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Conversation not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve messages"
+// @Security	BearerAuth
+// @Router	/visit/conversations/{id}/messages [get]
+func (ctrl *ConversationController) Messages(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	messages, err := ctrl.service.Messages(id, userID)
+	if err != nil {
+		handleConversationError(c, err, "Failed to retrieve messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+// Send sends a message to a conversation.
+// @Summary	Send message
+// @Description	Sends a message to a conversation of the currently logged-in user. A document of the patient may be attached if the doctor may see the patient's documents. The recipient is notified by mail, without the contents of the message.
+// @Tags	Messaging
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Conversation ID (UUIDv4 format)"
+// @Param	message body dtos.MessageRequest true "Message"
+// @Success	201 {object} models.Message "Successfully sent message"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format or request payload"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - No access to the attachment"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Conversation or document not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to send message"
+// @Security	BearerAuth
+// @Router	/visit/conversations/{id}/messages [post]
+func (ctrl *ConversationController) Send(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.MessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	message, err := ctrl.service.Send(id, userID, req)
+	if err != nil {
+		handleConversationError(c, err, "Failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// MarkRead marks the messages of a conversation as read.
+// @Summary	Mark conversation as read
+// @Description	Marks the messages sent to the currently logged-in user in a conversation as read, which the sender sees as a read receipt.
+// @Tags	Messaging
+// @Param	id path string true "Conversation ID (UUIDv4 format)"
+// @Success	204 "Successfully marked messages as read"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Conversation not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to mark messages as read"
+// @Security	BearerAuth
+// @Router	/visit/conversations/{id}/read [post]
+func (ctrl *ConversationController) MarkRead(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.MarkRead(id, userID); err != nil {
+		handleConversationError(c, err, "Failed to mark messages as read")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *ConversationController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/conversations")
+	routes.Use(
+		middleware.Authenticate(),
+		middleware.Authorize(commonsModels.Patient, commonsModels.Doctor),
+	)
+	{
+		routes.POST("", ctrl.Start)
+		routes.GET("", ctrl.List)
+		routes.GET("/unread", ctrl.Unread)
+		routes.GET("/:id/messages", ctrl.Messages)
+		routes.POST("/:id/messages", ctrl.Send)
+		routes.POST("/:id/read", ctrl.MarkRead)
+	}
+}
+
+func handleConversationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.CareRelationshipRequiredError{}),
+		errors.Is(err, &igakuErrors.AccessDeniedError{}):
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.ConversationNotFoundError{}),
+		errors.Is(err, &igakuErrors.AppointmentNotFoundError{}),
+		errors.Is(err, &igakuErrors.DocumentNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type DoctorSpecialtyController struct {
+	service services.DoctorSpecialtyService
+}
+
+func NewDoctorSpecialtyController(service services.DoctorSpecialtyService) *DoctorSpecialtyController {
+	return &DoctorSpecialtyController{service: service}
+}
+
+// List retrieves the specialties of a doctor.
+// @Summary	Get doctor specialties
+// @Description	Lists the specialties of a doctor in alphabetical order.
+// @Tags	Doctors
+// @Produce	json
+// @Param	id path string true "Doctor ID (UUIDv4 format)"
+// @Success	200 {array} string "Successfully retrieved specialties"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve specialties"
+// @Router	/visit/doctors/{id}/specialties [get]
+func (ctrl *DoctorSpecialtyController) List(c *gin.Context) {
+	doctorID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	specialties, err := ctrl.service.List(doctorID)
+	if err != nil {
+		handleDoctorSpecialtyError(c, err, "Failed to retrieve specialties")
+		return
+	}
+
+	c.JSON(http.StatusOK, specialties)
+}
+
+// Replace sets the specialties of a doctor.
+// @Summary	Set doctor specialties
+// @Description	Replaces the specialties of a doctor. Specialties are stored in lower case; duplicates are dropped.
+// @Tags	Doctors
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Doctor ID (UUIDv4 format)"
+// @Param	specialties body dtos.DoctorSpecialtiesRequest true "New specialties"
+// @Success	200 {array} string "Successfully set specialties"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or user is not a doctor"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to set specialties"
+// @Security	BearerAuth
+// @Router	/visit/doctors/{id}/specialties [put]
+func (ctrl *DoctorSpecialtyController) Replace(c *gin.Context) {
+	doctorID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.DoctorSpecialtiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	specialties, err := ctrl.service.Replace(doctorID, req.Specialties)
+	if err != nil {
+		handleDoctorSpecialtyError(c, err, "Failed to set specialties")
+		return
+	}
+
+	c.JSON(http.StatusOK, specialties)
+}
+
+func (ctrl *DoctorSpecialtyController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/doctors")
+	{
+		routes.GET("/:id/specialties", ctrl.List)
+		routes.PUT(
+			"/:id/specialties",
+			middleware.Authenticate(),
+			middleware.Authorize(commonsModels.Admin),
+			ctrl.Replace,
+		)
+	}
+}
+
+func handleDoctorSpecialtyError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidDoctorError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
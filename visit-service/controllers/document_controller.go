@@ -0,0 +1,425 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// maxDocumentFormOverhead is the room left for the form fields and
+// multipart framing of an upload.
+const maxDocumentFormOverhead = 64 << 10
+
+// maxDocumentFieldSize is the largest form field accepted along with a
+// document.
+const maxDocumentFieldSize = 1 << 10
+
+type DocumentController struct {
+	service services.DocumentService
+}
+
+func NewDocumentController(service services.DocumentService) *DocumentController {
+	return &DocumentController{service: service}
+}
+
+// Upload uploads a document of a patient.
+// @Summary	Upload document
+// @Description	Uploads a PDF, JPEG, PNG or WebP file of up to 25 MiB, such as a scan or a referral, to the record of a patient. The type is detected from the content. The form fields must precede the file. Patients may upload their own documents, doctors those of their patients; a linked encounter must be one of the patient's and, for doctors, one they led.
+// @Tags	Documents
+// @Accept	multipart/form-data
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	title formData string false "Title, defaults to the file name"
+// @Param	category formData string false "Category, defaults to other" Enums(referral, scan, lab_report, imaging, other)
+// @Param	encounter_id formData string false "Encounter ID (UUIDv4 format)"
+// @Param	file formData file true "Document"
+// @Success	201 {object} models.Document "Successfully uploaded document"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Missing file, empty document or invalid form fields"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter not found"
+// @Failure	413 {object} commonsDtos.ErrorResponse "Request Entity Too Large - Document too large"
+// @Failure	415 {object} commonsDtos.ErrorResponse "Unsupported Media Type - Unsupported document type"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to upload document"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/documents [post]
+func (ctrl *DocumentController) Upload(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(
+		c.Writer, c.Request.Body,
+		models.MaxDocumentSize+maxDocumentFormOverhead,
+	)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid multipart form",
+		})
+		return
+	}
+
+	// The parts are read in order so that the file is streamed rather than
+	// buffered.
+	var upload dtos.DocumentUpload
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: "Missing file",
+			})
+			return
+		}
+		if err != nil {
+			handleMultipartError(c, err)
+			return
+		}
+
+		if part.FormName() == "file" {
+			upload.FileName = part.FileName()
+			doc, err := ctrl.service.Upload(patientID, userID, role, upload, part)
+			if err != nil {
+				handleDocumentError(c, err, "Failed to upload document")
+				return
+			}
+			c.JSON(http.StatusCreated, doc)
+			return
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxDocumentFieldSize))
+		if err != nil {
+			handleMultipartError(c, err)
+			return
+		}
+		if !setDocumentField(c, &upload, part.FormName(), string(value)) {
+			return
+		}
+	}
+}
+
+// List lists the documents of a patient.
+// @Summary	List documents
+// @Description	Lists the documents of a patient, newest first.
+// @Tags	Documents
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Success	200 {array} models.Document "Successfully retrieved documents"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve documents"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/documents [get]
+func (ctrl *DocumentController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	docs, err := ctrl.service.List(patientID, userID, role)
+	if err != nil {
+		handleDocumentError(c, err, "Failed to retrieve documents")
+		return
+	}
+
+	c.JSON(http.StatusOK, docs)
+}
+
+// GetByID retrieves the metadata of a document.
+// @Summary	Get document
+// @Description	Retrieves the metadata of a document. The content is downloaded through a link.
+// @Tags	Documents
+// @Produce	json
+// @Param	id path string true "Document ID (UUIDv4 format)"
+// @Success	200 {object} models.Document "Successfully retrieved document"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Document not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve document"
+// @Security	BearerAuth
+// @Router	/visit/documents/{id} [get]
+func (ctrl *DocumentController) GetByID(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	doc, err := ctrl.service.Get(id, userID, role)
+	if err != nil {
+		handleDocumentError(c, err, "Failed to retrieve document")
+		return
+	}
+
+	c.JSON(http.StatusOK, doc)
+}
+
+// Delete removes a document.
+// @Summary	Delete document
+// @Description	Removes a document and its content. Only the uploader and admins may remove a document; emergency access tokens may not.
+// @Tags	Documents
+// @Param	id path string true "Document ID (UUIDv4 format)"
+// @Success	204 "Successfully deleted document"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Document not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to delete document"
+// @Security	BearerAuth
+// @Router	/visit/documents/{id} [delete]
+func (ctrl *DocumentController) Delete(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(id, userID, role); err != nil {
+		handleDocumentError(c, err, "Failed to delete document")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Link issues a download link for a document.
+// @Summary	Create document link
+// @Description	Issues a short-lived URL through which the document can be downloaded without a bearer token, e.g. by a browser. The link only works for the user it was issued to, as long as they still have access to the patient.
+// @Tags	Documents
+// @Produce	json
+// @Param	id path string true "Document ID (UUIDv4 format)"
+// @Success	201 {object} dtos.DocumentLink "Successfully issued link"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Document not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to issue link"
+// @Security	BearerAuth
+// @Router	/visit/documents/{id}/link [post]
+func (ctrl *DocumentController) Link(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	link, err := ctrl.service.Link(id, userID, role)
+	if err != nil {
+		handleDocumentError(c, err, "Failed to issue link")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// Download downloads the content of a document through a link.
+// @Summary	Download document
+// @Description	Downloads the content of a document. The query parameters come from a link issued by the create document link endpoint.
+// @Tags	Documents
+// @Produce	octet-stream
+// @Param	id path string true "Document ID (UUIDv4 format)"
+// @Param	user query string true "User the link was issued to"
+// @Param	role query string true "Role of the user"
+// @Param	expires query int true "Expiry of the link (Unix time)"
+// @Param	signature query string true "Signature of the link"
+// @Success	200 {file} file "Document content"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Invalid or expired link, or access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Document not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve document"
+// @Router	/visit/documents/{id}/content [get]
+func (ctrl *DocumentController) Download(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// Malformed parameters can only fail the signature check.
+	userID, _ := uuid.Parse(c.Query("user"))
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+	params := dtos.DocumentLinkParams{
+		UserID: userID,
+		Role: commonsModels.Role(c.Query("role")),
+		Expires: expires,
+		Signature: c.Query("signature"),
+	}
+
+	doc, content, err := ctrl.service.Open(id, params)
+	if err != nil {
+		handleDocumentError(c, err, "Failed to retrieve document")
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(
+		http.StatusOK, doc.Size, doc.ContentType, content,
+		map[string]string{
+			"Content-Disposition": mime.FormatMediaType(
+				"attachment", map[string]string{"filename": doc.FileName},
+			),
+			"ETag": "\"" + doc.Checksum + "\"",
+			"Cache-Control": "private, no-store",
+		},
+	)
+}
+
+func (ctrl *DocumentController) RegisterRoutes(router *gin.Engine) {
+	patients := router.Group("/visit/patients/:id/documents")
+	patients.Use(middleware.Authenticate())
+	{
+		patients.POST(
+			"",
+			middleware.Authorize(commonsModels.Patient, commonsModels.Doctor),
+			ctrl.Upload,
+		)
+		patients.GET("", ctrl.List)
+	}
+
+	documents := router.Group("/visit/documents")
+	{
+		documents.GET("/:id", middleware.Authenticate(), ctrl.GetByID)
+		// Emergency access is read-only.
+		documents.DELETE(
+			"/:id",
+			middleware.Authenticate(),
+			middleware.Authorize(
+				commonsModels.Patient, commonsModels.Doctor, commonsModels.Admin,
+			),
+			ctrl.Delete,
+		)
+		documents.POST("/:id/link", middleware.Authenticate(), ctrl.Link)
+		// The link itself authorizes the download.
+		documents.GET("/:id/content", ctrl.Download)
+	}
+}
+
+// setDocumentField reads a form field of an upload. On failure it writes
+// an error response and returns false.
+func setDocumentField(
+	c *gin.Context, upload *dtos.DocumentUpload, name, value string,
+) bool {
+	value = strings.TrimSpace(value)
+	switch name {
+	case "title":
+		upload.Title = value
+	case "category":
+		upload.Category = models.DocumentCategory(value)
+	case "encounter_id":
+		if value == "" {
+			return true
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: "Invalid encounter ID",
+			})
+			return false
+		}
+		upload.EncounterID = &id
+	}
+	return true
+}
+
+func handleMultipartError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, commonsDtos.ErrorResponse{
+			Message: "Document too large",
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+		Message: "Invalid multipart form",
+	})
+}
+
+func handleDocumentError(c *gin.Context, err error, fallback string) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, &igakuErrors.EmptyDocumentError{}),
+		errors.Is(err, &igakuErrors.InvalidDocumentCategoryError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AccessDeniedError{}),
+		errors.Is(err, &igakuErrors.InvalidDocumentLinkError{}):
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.DocumentNotFoundError{}),
+		errors.Is(err, &igakuErrors.EncounterNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.DocumentTooLargeError{}):
+		c.JSON(http.StatusRequestEntityTooLarge, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, commonsDtos.ErrorResponse{
+			Message: "Document too large",
+		})
+	case errors.Is(err, &igakuErrors.UnsupportedDocumentTypeError{}):
+		c.JSON(http.StatusUnsupportedMediaType, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type EmergencyAccessController struct {
+	service services.EmergencyAccessService
+}
+
+func NewEmergencyAccessController(
+	service services.EmergencyAccessService,
+) *EmergencyAccessController {
+	return &EmergencyAccessController{service: service}
+}
+
+// Request grants the currently logged-in doctor emergency access to a
+// patient.
+// @Summary	Request emergency access
+// @Description	Grants "break-the-glass" access to a patient outside the doctor's care team, for a limited time and regardless of the patient's consents. A justification of at least 20 characters is required. The returned token is only valid for reading the clinical data of the patient (records, summary, lab results, documents); every read made with it is logged. The patient is notified by mail and every access is reviewed by an admin.
+// @Tags	Emergency Access
+// @Accept	json
+// @Produce	json
+// @Param	access body dtos.EmergencyAccessRequest true "Emergency access details"
+// @Success	201 {object} dtos.EmergencyAccessGrant "Successfully granted emergency access"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload, patient or justification"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to grant emergency access"
+// @Security	BearerAuth
+// @Router	/visit/emergency-access [post]
+func (ctrl *EmergencyAccessController) Request(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.EmergencyAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	grant, err := ctrl.service.Request(userID, req)
+	if err != nil {
+		handleEmergencyAccessError(c, err, "Failed to grant emergency access")
+		return
+	}
+
+	c.JSON(http.StatusCreated, grant)
+}
+
+// List lists the emergency accesses of the currently logged-in doctor.
+// @Summary	List own emergency accesses
+// @Description	Lists the emergency accesses granted to the currently logged-in doctor, newest first, along with their review outcome.
+// @Tags	Emergency Access
+// @Produce	json
+// @Success	200 {array} models.EmergencyAccess "Successfully retrieved emergency accesses"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve emergency accesses"
+// @Security	BearerAuth
+// @Router	/visit/emergency-access [get]
+func (ctrl *EmergencyAccessController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	accesses, err := ctrl.service.List(userID)
+	if err != nil {
+		handleEmergencyAccessError(c, err, "Failed to retrieve emergency accesses")
+		return
+	}
+
+	c.JSON(http.StatusOK, accesses)
+}
+
+// Queue lists the emergency accesses awaiting review.
+// @Summary	Get emergency access review queue
+// @Description	Lists the emergency accesses with the review status, oldest first. Lists those awaiting review by default.
+// @Tags	Emergency Access
+// @Produce	json
+// @Param	status query string false "Review status (pending, approved, flagged)"
+// @Success	200 {array} models.EmergencyAccess "Successfully retrieved emergency accesses"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid review status"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve emergency accesses"
+// @Security	BearerAuth
+// @Router	/visit/emergency-access/queue [get]
+func (ctrl *EmergencyAccessController) Queue(c *gin.Context) {
+	status := models.EmergencyReviewStatus(c.Query("status"))
+
+	accesses, err := ctrl.service.Queue(status)
+	if err != nil {
+		handleEmergencyAccessError(c, err, "Failed to retrieve emergency accesses")
+		return
+	}
+
+	c.JSON(http.StatusOK, accesses)
+}
+
+// Review records the outcome of the review of an emergency access.
+// @Summary	Review emergency access
+// @Description	Approves an emergency access as justified or flags it as unjustified. Flagging an access still in force ends it immediately.
+// @Tags	Emergency Access
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Emergency access ID (UUIDv4 format)"
+// @Param	review body dtos.EmergencyReviewRequest true "Review outcome"
+// @Success	200 {object} models.EmergencyAccess "Successfully reviewed emergency access"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format or request payload"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Emergency access not found"
+// @Failure	409 {object} commonsDtos.ErrorResponse "Conflict - Emergency access already reviewed"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to review emergency access"
+// @Security	BearerAuth
+// @Router	/visit/emergency-access/{id}/review [post]
+func (ctrl *EmergencyAccessController) Review(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.EmergencyReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	access, err := ctrl.service.Review(id, userID, req)
+	if err != nil {
+		handleEmergencyAccessError(c, err, "Failed to review emergency access")
+		return
+	}
+
+	c.JSON(http.StatusOK, access)
+}
+
+// Events lists the reads made under an emergency access.
+// @Summary	Get emergency access log
+// @Description	Lists every read of the patient's clinical data made under an emergency access, oldest first, with the categories of data read.
+// @Tags	Emergency Access
+// @Produce	json
+// @Param	id path string true "Emergency access ID (UUIDv4 format)"
+// @Success	200 {array} models.EmergencyAccessEvent "Successfully retrieved emergency access log"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Emergency access not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve emergency access log"
+// @Security	BearerAuth
+// @Router	/visit/emergency-access/{id}/events [get]
+func (ctrl *EmergencyAccessController) Events(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	events, err := ctrl.service.Events(id)
+	if err != nil {
+		handleEmergencyAccessError(c, err, "Failed to retrieve emergency access log")
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (ctrl *EmergencyAccessController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/emergency-access")
+	routes.Use(middleware.Authenticate())
+	{
+		doctorOnly := middleware.Authorize(commonsModels.Doctor)
+		adminOnly := middleware.Authorize(commonsModels.Admin)
+
+		routes.POST("", doctorOnly, ctrl.Request)
+		routes.GET("", doctorOnly, ctrl.List)
+		routes.GET("/queue", adminOnly, ctrl.Queue)
+		routes.POST("/:id/review", adminOnly, ctrl.Review)
+		routes.GET("/:id/events", adminOnly, ctrl.Events)
+	}
+}
+
+func handleEmergencyAccessError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidJustificationError{}),
+		errors.Is(err, &igakuErrors.InvalidEmergencyPatientError{}),
+		errors.Is(err, &igakuErrors.InvalidReviewStatusError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.EmergencyAccessNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.EmergencyAccessReviewedError{}):
+		c.JSON(http.StatusConflict, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,501 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// maxAttachmentSize is the largest file accepted as an encounter
+// attachment.
+const maxAttachmentSize = 10 << 20
+
+type EncounterController struct {
+	service services.EncounterService
+}
+
+func NewEncounterController(service services.EncounterService) *EncounterController {
+	return &EncounterController{service: service}
+}
+
+// Create records the encounter of an appointment.
+// @Summary	Create encounter
+// @Description	Records the notes, ICD-10 diagnoses and vitals of an appointment. Only the treating doctor may create the encounter, and only once per appointment.
+// @Tags	Encounters
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Appointment ID (UUIDv4 format)"
+// @Param	notes body dtos.EncounterNotesRequest true "Encounter notes"
+// @Success	201 {object} models.Encounter "Successfully created encounter"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or diagnosis code"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Appointment not found"
+// @Failure	409 {object} commonsDtos.ErrorResponse "Conflict - Encounter already exists or appointment cancelled"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to create encounter"
+// @Security	BearerAuth
+// @Router	/visit/appointments/{id}/encounter [post]
+func (ctrl *EncounterController) Create(c *gin.Context) {
+	doctorID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	apptID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.EncounterNotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	enc, err := ctrl.service.Create(apptID, doctorID, req)
+	if err != nil {
+		handleEncounterError(c, err, "Failed to create encounter")
+		return
+	}
+
+	c.JSON(http.StatusCreated, enc)
+}
+
+// GetByAppointment retrieves the encounter of an appointment.
+// @Summary	Get encounter by appointment
+// @Description	Retrieves the encounter of an appointment with its full amendment history. Only the patient, the treating doctor, delegated doctors, doctors allowed to see the patient's encounters by their care team or consents and doctors with emergency access to the patient have access; the patient's consent may also deny delegated doctors access.
+// @Tags	Encounters
+// @Produce	json
+// @Param	id path string true "Appointment ID (UUIDv4 format)"
+// @Success	200 {object} models.Encounter "Successfully retrieved encounter"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve encounter"
+// @Security	BearerAuth
+// @Router	/visit/appointments/{id}/encounter [get]
+func (ctrl *EncounterController) GetByAppointment(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	apptID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	enc, err := ctrl.service.GetByAppointment(apptID, userID, role)
+	if err != nil {
+		handleEncounterError(c, err, "Failed to retrieve encounter")
+		return
+	}
+
+	c.JSON(http.StatusOK, enc)
+}
+
+// List lists the encounters accessible to the current user.
+// @Summary	List encounters
+// @Description	Lists the encounters the currently logged-in user is the patient or treating doctor of, or has been delegated.
+// @Tags	Encounters
+// @Produce	json
+// @Success	200 {array} models.Encounter "Successfully retrieved encounters"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve encounters"
+// @Security	BearerAuth
+// @Router	/visit/encounters [get]
+func (ctrl *EncounterController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	encs, err := ctrl.service.List(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: "Failed to retrieve encounters",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, encs)
+}
+
+// GetByID retrieves an encounter.
+// @Summary	Get encounter by ID
+// @Description	Retrieves an encounter with its full amendment history. Only the patient, the treating doctor, delegated doctors, doctors allowed to see the patient's encounters by their care team or consents and doctors with emergency access to the patient have access; the patient's consent may also deny delegated doctors access.
+// @Tags	Encounters
+// @Produce	json
+// @Param	id path string true "Encounter ID (UUIDv4 format)"
+// @Success	200 {object} models.Encounter "Successfully retrieved encounter"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve encounter"
+// @Security	BearerAuth
+// @Router	/visit/encounters/{id} [get]
+func (ctrl *EncounterController) GetByID(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	enc, err := ctrl.service.Get(id, userID, role)
+	if err != nil {
+		handleEncounterError(c, err, "Failed to retrieve encounter")
+		return
+	}
+
+	c.JSON(http.StatusOK, enc)
+}
+
+// Amend appends an amendment to an encounter.
+// @Summary	Amend encounter
+// @Description	Records a new revision of the encounter notes. Earlier revisions are kept unchanged. Only the treating doctor may amend the encounter.
+// @Tags	Encounters
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Encounter ID (UUIDv4 format)"
+// @Param	amendment body dtos.EncounterAmendmentRequest true "Amended notes and reason"
+// @Success	201 {object} models.EncounterRevision "Successfully amended encounter"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or diagnosis code"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to amend encounter"
+// @Security	BearerAuth
+// @Router	/visit/encounters/{id}/amendments [post]
+func (ctrl *EncounterController) Amend(c *gin.Context) {
+	doctorID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.EncounterAmendmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	rev, err := ctrl.service.Amend(id, doctorID, req)
+	if err != nil {
+		handleEncounterError(c, err, "Failed to amend encounter")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rev)
+}
+
+// AddAttachment attaches a file to an encounter.
+// @Summary	Add encounter attachment
+// @Description	Attaches a file of up to 10 MiB to an encounter. Attachments cannot be removed. Only the treating doctor may add attachments.
+// @Tags	Encounters
+// @Accept	multipart/form-data
+// @Produce	json
+// @Param	id path string true "Encounter ID (UUIDv4 format)"
+// @Param	file formData file true "Attachment"
+// @Success	201 {object} models.EncounterAttachment "Successfully added attachment"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Missing file"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter not found"
+// @Failure	413 {object} commonsDtos.ErrorResponse "Request Entity Too Large - Attachment too large"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to add attachment"
+// @Security	BearerAuth
+// @Router	/visit/encounters/{id}/attachments [post]
+func (ctrl *EncounterController) AddAttachment(c *gin.Context) {
+	doctorID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Missing file",
+		})
+		return
+	}
+
+	if fileHeader.Size > maxAttachmentSize {
+		c.JSON(http.StatusRequestEntityTooLarge, commonsDtos.ErrorResponse{
+			Message: "Attachment too large",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Failed to read file",
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxAttachmentSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Failed to read file",
+		})
+		return
+	}
+
+	contentType := fileHeader.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	att, err := ctrl.service.AddAttachment(
+		id, doctorID, fileHeader.Filename, contentType, data,
+	)
+	if err != nil {
+		handleEncounterError(c, err, "Failed to add attachment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, att)
+}
+
+// GetAttachment downloads an encounter attachment.
+// @Summary	Download encounter attachment
+// @Description	Downloads the content of an encounter attachment. Only the patient, the treating doctor, delegated doctors, doctors allowed to see the patient's encounters by their care team or consents and doctors with emergency access to the patient have access; the patient's consent may also deny delegated doctors access.
+// @Tags	Encounters
+// @Produce	octet-stream
+// @Param	id path string true "Encounter ID (UUIDv4 format)"
+// @Param	attachmentId path string true "Attachment ID (UUIDv4 format)"
+// @Success	200 {file} file "Attachment content"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter or attachment not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve attachment"
+// @Security	BearerAuth
+// @Router	/visit/encounters/{id}/attachments/{attachmentId} [get]
+func (ctrl *EncounterController) GetAttachment(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	attID, ok := parseIDParam(c, "attachmentId")
+	if !ok {
+		return
+	}
+
+	att, err := ctrl.service.GetAttachment(id, attID, userID, role)
+	if err != nil {
+		handleEncounterError(c, err, "Failed to retrieve attachment")
+		return
+	}
+
+	c.Header(
+		"Content-Disposition",
+		mime.FormatMediaType(
+			"attachment", map[string]string{"filename": att.FileName},
+		),
+	)
+	c.Header("ETag", "\""+att.Checksum+"\"")
+	c.Data(http.StatusOK, att.ContentType, att.Data)
+}
+
+// Delegate grants another doctor access to an encounter.
+// @Summary	Delegate encounter access
+// @Description	Grants another doctor read access to an encounter. Only the patient and the treating doctor may delegate access.
+// @Tags	Encounters
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Encounter ID (UUIDv4 format)"
+// @Param	delegation body dtos.DelegationRequest true "Delegated doctor"
+// @Success	201 {object} models.EncounterDelegation "Successfully delegated access"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or delegate"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter or user not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to delegate access"
+// @Security	BearerAuth
+// @Router	/visit/encounters/{id}/delegations [post]
+func (ctrl *EncounterController) Delegate(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.DelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	del, err := ctrl.service.Delegate(id, userID, req.DoctorID)
+	if err != nil {
+		handleEncounterError(c, err, "Failed to delegate access")
+		return
+	}
+
+	c.JSON(http.StatusCreated, del)
+}
+
+// RevokeDelegation withdraws a doctor's delegated access to an encounter.
+// @Summary	Revoke encounter delegation
+// @Description	Withdraws the read access previously delegated to a doctor. Only the patient and the treating doctor may revoke access.
+// @Tags	Encounters
+// @Param	id path string true "Encounter ID (UUIDv4 format)"
+// @Param	doctorId path string true "Delegated doctor ID (UUIDv4 format)"
+// @Success	204 "Successfully revoked delegation"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Encounter not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to revoke delegation"
+// @Security	BearerAuth
+// @Router	/visit/encounters/{id}/delegations/{doctorId} [delete]
+func (ctrl *EncounterController) RevokeDelegation(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	doctorID, ok := parseIDParam(c, "doctorId")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.RevokeDelegation(id, userID, doctorID); err != nil {
+		handleEncounterError(c, err, "Failed to revoke delegation")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SearchDiagnoses searches the ICD-10 dictionary.
+// @Summary	Search ICD-10 codes
+// @Description	Returns up to 25 ICD-10 codes whose code starts with, or whose description contains, the query.
+// @Tags	Encounters
+// @Produce	json
+// @Param	q query string true "Code prefix or part of the description"
+// @Success	200 {array} models.ICD10Code "Matching ICD-10 codes"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Missing query"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Security	BearerAuth
+// @Router	/visit/diagnoses [get]
+func (ctrl *EncounterController) SearchDiagnoses(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Missing query",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ctrl.service.SearchDiagnoses(query))
+}
+
+func (ctrl *EncounterController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit")
+	routes.Use(middleware.Authenticate())
+	{
+		doctorOnly := middleware.Authorize(commonsModels.Doctor)
+
+		routes.GET("/diagnoses", doctorOnly, ctrl.SearchDiagnoses)
+		routes.POST("/appointments/:id/encounter", doctorOnly, ctrl.Create)
+		routes.GET("/appointments/:id/encounter", ctrl.GetByAppointment)
+		routes.GET("/encounters", ctrl.List)
+		routes.GET("/encounters/:id", ctrl.GetByID)
+		routes.POST("/encounters/:id/amendments", doctorOnly, ctrl.Amend)
+		routes.POST("/encounters/:id/attachments", doctorOnly, ctrl.AddAttachment)
+		routes.GET("/encounters/:id/attachments/:attachmentId", ctrl.GetAttachment)
+		routes.POST(
+			"/encounters/:id/delegations",
+			middleware.Authorize(commonsModels.Patient, commonsModels.Doctor),
+			ctrl.Delegate,
+		)
+		routes.DELETE(
+			"/encounters/:id/delegations/:doctorId",
+			middleware.Authorize(commonsModels.Patient, commonsModels.Doctor),
+			ctrl.RevokeDelegation,
+		)
+	}
+}
+
+func handleEncounterError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidDiagnosisCodeError{}),
+		errors.Is(err, &igakuErrors.InvalidDelegateError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AccessDeniedError{}):
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AppointmentNotFoundError{}),
+		errors.Is(err, &igakuErrors.EncounterNotFoundError{}),
+		errors.Is(err, &igakuErrors.AttachmentNotFoundError{}),
+		errors.Is(err, &commonsErrors.UserNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.EncounterAlreadyExistsError{}),
+		errors.Is(err, &igakuErrors.AppointmentCancelledError{}):
+		c.JSON(http.StatusConflict, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
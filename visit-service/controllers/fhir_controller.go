@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"igaku/visit-service/fhir"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// fhirBasePath is where the FHIR API is mounted.
+const fhirBasePath = "/visit/fhir"
+
+// maxFHIRResourceSize is the largest resource accepted for validation.
+const maxFHIRResourceSize = 1 << 20
+
+type FHIRController struct {
+	service services.FHIRService
+}
+
+func NewFHIRController(service services.FHIRService) *FHIRController {
+	return &FHIRController{service: service}
+}
+
+// Metadata returns the CapabilityStatement of the FHIR API.
+// @Summary	FHIR CapabilityStatement
+// @Description	Describes the resources, interactions and search parameters supported by the FHIR R4 facade.
+// @Tags	FHIR
+// @Produce	application/fhir+json
+// @Success	200 {object} fhir.CapabilityStatement "CapabilityStatement"
+// @Router	/visit/fhir/metadata [get]
+func (ctrl *FHIRController) Metadata(c *gin.Context) {
+	writeFHIR(c, http.StatusOK, fhir.NewCapabilityStatement(fhirBaseURL(c), time.Now()))
+}
+
+// Search searches resources of a type.
+// @Summary	FHIR search
+// @Description	Searches Patient, Practitioner, Organization, Location or Appointment resources and returns a searchset Bundle. Pages are selected with `_count` (at most 100) and `_offset`; unknown search parameters are rejected. Patients only find themselves and their own appointments, doctors their own appointments.
+// @Tags	FHIR
+// @Produce	application/fhir+json
+// @Param	type path string true "Resource type" Enums(Patient, Practitioner, Organization, Location, Appointment)
+// @Param	_count query int false "Page size (default: 20)" minimum(0) maximum(100)
+// @Param	_offset query int false "Number of matches to skip" minimum(0)
+// @Success	200 {object} fhir.Bundle "Search results"
+// @Failure	400 {object} fhir.OperationOutcome "Bad Request - Unsupported or invalid search parameter"
+// @Failure	401 {object} dtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	404 {object} fhir.OperationOutcome "Not Found - Unsupported resource type"
+// @Failure	500 {object} fhir.OperationOutcome "Internal Server Error - Search failed"
+// @Security	BearerAuth
+// @Router	/visit/fhir/{type} [get]
+func (ctrl *FHIRController) Search(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	resourceType, ok := fhirResourceType(c)
+	if !ok {
+		return
+	}
+
+	search, err := fhir.ParseSearch(resourceType, c.Request.URL.Query())
+	if err != nil {
+		handleFHIRError(c, err)
+		return
+	}
+
+	result, err := ctrl.service.Search(resourceType, search, userID, role)
+	if err != nil {
+		handleFHIRError(c, err)
+		return
+	}
+
+	writeFHIR(
+		c, http.StatusOK,
+		fhir.NewSearchBundle(fhirBaseURL(c), resourceType, search, result),
+	)
+}
+
+// Read reads a resource.
+// @Summary	FHIR read
+// @Description	Reads a Patient, Practitioner, Organization, Location or Appointment resource.
+// @Tags	FHIR
+// @Produce	application/fhir+json
+// @Param	type path string true "Resource type" Enums(Patient, Practitioner, Organization, Location, Appointment)
+// @Param	id path string true "Logical ID"
+// @Success	200 {object} object "Resource"
+// @Failure	401 {object} dtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} fhir.OperationOutcome "Forbidden - Access denied"
+// @Failure	404 {object} fhir.OperationOutcome "Not Found - Resource not found"
+// @Failure	500 {object} fhir.OperationOutcome "Internal Server Error - Read failed"
+// @Security	BearerAuth
+// @Router	/visit/fhir/{type}/{id} [get]
+func (ctrl *FHIRController) Read(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	resourceType, ok := fhirResourceType(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		writeFHIR(c, http.StatusNotFound, fhir.NewOperationOutcome(
+			"error", "not-found", resourceType+" not found",
+		))
+		return
+	}
+
+	resource, err := ctrl.service.Read(resourceType, id, userID, role)
+	if err != nil {
+		handleFHIRError(c, err)
+		return
+	}
+
+	writeFHIR(c, http.StatusOK, resource)
+}
+
+// Validate validates a resource against its base structure.
+// @Summary	FHIR $validate
+// @Description	Checks a JSON resource against the base R4 structure of its type: known elements, cardinalities, primitive formats and required code bindings. The outcome lists every issue found.
+// @Tags	FHIR
+// @Accept	application/fhir+json
+// @Produce	application/fhir+json
+// @Param	type path string true "Resource type" Enums(Patient, Practitioner, Organization, Location, Appointment)
+// @Param	resource body object true "Resource to validate"
+// @Success	200 {object} fhir.OperationOutcome "Validation outcome"
+// @Failure	401 {object} dtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	404 {object} fhir.OperationOutcome "Not Found - Unsupported resource type"
+// @Failure	413 {object} fhir.OperationOutcome "Request Entity Too Large - Resource too large"
+// @Security	BearerAuth
+// @Router	/visit/fhir/{type}/$validate [post]
+func (ctrl *FHIRController) Validate(c *gin.Context) {
+	resourceType, ok := fhirResourceType(c)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxFHIRResourceSize+1))
+	if err != nil {
+		writeFHIR(c, http.StatusBadRequest, fhir.NewOperationOutcome(
+			"error", "structure", "Failed to read the resource",
+		))
+		return
+	}
+	if len(body) > maxFHIRResourceSize {
+		writeFHIR(c, http.StatusRequestEntityTooLarge, fhir.NewOperationOutcome(
+			"error", "too-costly", "Resource too large",
+		))
+		return
+	}
+
+	writeFHIR(c, http.StatusOK, fhir.Validate(body, resourceType))
+}
+
+func (ctrl *FHIRController) RegisterRoutes(router *gin.Engine) {
+	router.GET(fhirBasePath+"/metadata", ctrl.Metadata)
+
+	routes := router.Group(fhirBasePath)
+	routes.Use(middleware.Authenticate())
+	{
+		routes.GET("/:type", ctrl.Search)
+		routes.GET("/:type/:id", ctrl.Read)
+		routes.POST("/:type/$validate", ctrl.Validate)
+	}
+}
+
+// fhirBaseURL returns the absolute URL of the FHIR API, as seen by the
+// client through the reverse proxy.
+func fhirBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	host := c.Request.Host
+	if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+
+	return scheme + "://" + host + fhirBasePath
+}
+
+// fhirResourceType returns the resource type from the path. It aborts with
+// 404 when the type is not served.
+func fhirResourceType(c *gin.Context) (string, bool) {
+	resourceType := c.Param("type")
+	if !slices.Contains(fhir.ResourceTypes, resourceType) {
+		writeFHIR(c, http.StatusNotFound, fhir.NewOperationOutcome(
+			"error", "not-supported", "Unsupported resource type "+resourceType,
+		))
+		return "", false
+	}
+	return resourceType, true
+}
+
+func writeFHIR(c *gin.Context, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Failed to marshal FHIR resource: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, fhir.ContentType+"; charset=utf-8", body)
+}
+
+func handleFHIRError(c *gin.Context, err error) {
+	var invalidParam *igakuErrors.InvalidSearchParameterError
+	switch {
+	case errors.As(err, &invalidParam):
+		writeFHIR(c, http.StatusBadRequest, fhir.NewOperationOutcome(
+			"error", "invalid", err.Error(),
+		))
+	case errors.Is(err, &igakuErrors.AccessDeniedError{}):
+		writeFHIR(c, http.StatusForbidden, fhir.NewOperationOutcome(
+			"error", "forbidden", err.Error(),
+		))
+	case errors.Is(err, &commonsErrors.UserNotFoundError{}),
+		errors.Is(err, &igakuErrors.OrganizationNotFoundError{}),
+		errors.Is(err, &igakuErrors.AppointmentNotFoundError{}):
+		writeFHIR(c, http.StatusNotFound, fhir.NewOperationOutcome(
+			"error", "not-found", err.Error(),
+		))
+	default:
+		writeFHIR(c, http.StatusInternalServerError, fhir.NewOperationOutcome(
+			"fatal", "exception", "Failed to process the FHIR request",
+		))
+	}
+}
@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type HealthRecordController struct {
+	service services.HealthRecordService
+}
+
+func NewHealthRecordController(service services.HealthRecordService) *HealthRecordController {
+	return &HealthRecordController{service: service}
+}
+
+// Summary retrieves the medical history of a patient.
+// @Summary	Get patient summary
+// @Description	Retrieves the allergies, chronic conditions, immunizations, medication history, pharmacogenomic results and active prescriptions of a patient. Patients see their own summary, admins any. Doctors see the summary of patients whose care team they are on or whose consent permits them, except for the sections the patient denied them. Doctors with emergency access to the patient see all of it, and every read is logged.
+// @Tags	Health Records
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Success	200 {object} models.PatientSummary "Successfully retrieved summary"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve summary"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/summary [get]
+func (ctrl *HealthRecordController) Summary(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	summary, err := ctrl.service.Summary(patientID, userID, role)
+	if err != nil {
+		handleHealthRecordError(c, err, "Failed to retrieve summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// List lists the health records of a patient.
+// @Summary	List health records
+// @Description	Lists the entries of one kind from the medical history of a patient.
+// @Tags	Health Records
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	kind path string true "Kind of record" Enums(allergies, conditions, immunizations, medications, genotypes)
+// @Success	200 {array} object "Successfully retrieved records"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Unknown kind of record"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve records"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/records/{kind} [get]
+func (ctrl *HealthRecordController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	kind, _, ok := recordKindParam(c)
+	if !ok {
+		return
+	}
+
+	records, err := ctrl.service.List(kind, patientID, userID, role)
+	if err != nil {
+		handleHealthRecordError(c, err, "Failed to retrieve records")
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// Create records an entry in the medical history of a patient.
+// @Summary	Create health record
+// @Description	Records an allergy, chronic condition, immunization, medication or pharmacogenomic result. The body matches the kind: dtos.AllergyRequest, dtos.ConditionRequest, dtos.ImmunizationRequest, dtos.MedicationStatementRequest or dtos.GenotypeRequest. Patients may record self-reported entries; doctors may link entries to one of their encounters with the patient.
+// @Tags	Health Records
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	kind path string true "Kind of record" Enums(allergies, conditions, immunizations, medications, genotypes)
+// @Param	record body dtos.AllergyRequest true "Record details"
+// @Success	201 {object} object "Successfully created record"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload, condition code or phenotype"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Unknown kind of record or encounter not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to create record"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/records/{kind} [post]
+func (ctrl *HealthRecordController) Create(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	kind, req, ok := recordKindParam(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	record, err := ctrl.service.Create(kind, patientID, userID, role, req)
+	if err != nil {
+		handleHealthRecordError(c, err, "Failed to create record")
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// Update replaces an entry in the medical history of a patient.
+// @Summary	Update health record
+// @Description	Replaces the details of a record. Patients may only update entries they recorded themselves which no one else has changed since.
+// @Tags	Health Records
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	kind path string true "Kind of record" Enums(allergies, conditions, immunizations, medications, genotypes)
+// @Param	recordId path string true "Record ID (UUIDv4 format)"
+// @Param	record body dtos.AllergyRequest true "Record details"
+// @Success	200 {object} object "Successfully updated record"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload, condition code or phenotype"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Record not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to update record"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/records/{kind}/{recordId} [put]
+func (ctrl *HealthRecordController) Update(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	recordID, ok := parseIDParam(c, "recordId")
+	if !ok {
+		return
+	}
+	kind, req, ok := recordKindParam(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	record, err := ctrl.service.Update(kind, recordID, patientID, userID, role, req)
+	if err != nil {
+		handleHealthRecordError(c, err, "Failed to update record")
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// Delete removes an entry from the medical history of a patient.
+// @Summary	Delete health record
+// @Description	Removes a record, e.g. one filed against the wrong patient. Patients may only remove entries they recorded themselves which no one else has changed since.
+// @Tags	Health Records
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	kind path string true "Kind of record" Enums(allergies, conditions, immunizations, medications, genotypes)
+// @Param	recordId path string true "Record ID (UUIDv4 format)"
+// @Success	204 "Successfully deleted record"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Record not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to delete record"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/records/{kind}/{recordId} [delete]
+func (ctrl *HealthRecordController) Delete(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	recordID, ok := parseIDParam(c, "recordId")
+	if !ok {
+		return
+	}
+	kind, _, ok := recordKindParam(c)
+	if !ok {
+		return
+	}
+
+	err := ctrl.service.Delete(kind, recordID, patientID, userID, role)
+	if err != nil {
+		handleHealthRecordError(c, err, "Failed to delete record")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *HealthRecordController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/patients/:id")
+	routes.Use(middleware.Authenticate())
+	{
+		routes.GET("/summary", ctrl.Summary)
+		routes.GET("/records/:kind", ctrl.List)
+		routes.POST("/records/:kind", ctrl.Create)
+		routes.PUT("/records/:kind/:recordId", ctrl.Update)
+		routes.DELETE("/records/:kind/:recordId", ctrl.Delete)
+	}
+}
+
+// recordKindParam parses the kind of health record from the path, along
+// with an empty request of that kind. It aborts with 404 when the kind is
+// unknown.
+func recordKindParam(
+	c *gin.Context,
+) (models.HealthRecordKind, dtos.HealthRecordRequest, bool) {
+	kind := models.HealthRecordKind(c.Param("kind"))
+	req, ok := dtos.NewHealthRecordRequest(kind)
+	if !ok {
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: "Unknown kind of health record",
+		})
+		return "", nil, false
+	}
+	return kind, req, true
+}
+
+func handleHealthRecordError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidDiagnosisCodeError{}),
+		errors.Is(err, &igakuErrors.InvalidPhenotypeError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AccessDeniedError{}):
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.HealthRecordNotFoundError{}),
+		errors.Is(err, &igakuErrors.EncounterNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type HomeAddressController struct {
+	service services.HomeAddressService
+}
+
+func NewHomeAddressController(service services.HomeAddressService) *HomeAddressController {
+	return &HomeAddressController{service: service}
+}
+
+// Get retrieves the home address of the current patient.
+// @Summary	Get home address
+// @Description	Retrieves the home address of the currently logged-in patient, along with the place it was geocoded to.
+// @Tags	Home Address
+// @Produce	json
+// @Success	200 {object} models.HomeAddress "Successfully retrieved home address"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Home address not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve home address"
+// @Security	BearerAuth
+// @Router	/visit/home-address [get]
+func (ctrl *HomeAddressController) Get(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	address, err := ctrl.service.Get(patientID)
+	if err != nil {
+		handleHomeAddressError(c, err, "Failed to retrieve home address")
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// Set replaces the home address of the current patient.
+// @Summary	Set home address
+// @Description	Geocodes the given address and saves it as the home address of the currently logged-in patient. Booking confirmations include estimates of the travel from the home address to the organization.
+// @Tags	Home Address
+// @Accept	json
+// @Produce	json
+// @Param	address body dtos.HomeAddressRequest true "Home address"
+// @Success	200 {object} models.HomeAddress "Successfully saved home address"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or address not found"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to save home address"
+// @Failure	503 {object} commonsDtos.ErrorResponse "Service Unavailable - Failed to geocode the address"
+// @Security	BearerAuth
+// @Router	/visit/home-address [put]
+func (ctrl *HomeAddressController) Set(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.HomeAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	address, err := ctrl.service.Set(patientID, req.Address)
+	if err != nil {
+		handleHomeAddressError(c, err, "Failed to save home address")
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// Delete removes the home address of the current patient.
+// @Summary	Delete home address
+// @Description	Removes the home address of the currently logged-in patient. Booking confirmations no longer include travel estimates.
+// @Tags	Home Address
+// @Success	204 "Successfully deleted home address"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Home address not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to delete home address"
+// @Security	BearerAuth
+// @Router	/visit/home-address [delete]
+func (ctrl *HomeAddressController) Delete(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Delete(patientID); err != nil {
+		handleHomeAddressError(c, err, "Failed to delete home address")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *HomeAddressController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/home-address")
+	routes.Use(middleware.Authenticate(), middleware.Authorize(commonsModels.Patient))
+	{
+		routes.GET("", ctrl.Get)
+		routes.PUT("", ctrl.Set)
+		routes.DELETE("", ctrl.Delete)
+	}
+}
+
+func handleHomeAddressError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.AddressNotFoundError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.HomeAddressNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.GeocodingFailedError{}):
+		c.JSON(http.StatusServiceUnavailable, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"io"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// maxLabImportSize is the largest HL7 file accepted for import.
+const maxLabImportSize = 5 << 20
+
+type LabResultController struct {
+	service services.LabResultService
+}
+
+func NewLabResultController(service services.LabResultService) *LabResultController {
+	return &LabResultController{service: service}
+}
+
+// Create records a lab result.
+// @Summary	Record lab result
+// @Description	Records a numeric lab result of a patient. Values of common analytes are converted to their canonical unit, and every value is flagged against the given reference range or, when none is given, the usual range of the analyte. Only doctors on the patient's care team, or whose consent permits them to see lab results, may record results.
+// @Tags	Lab Results
+// @Accept	json
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	result body dtos.LabResultRequest true "Result details"
+// @Success	201 {object} models.LabResult "Successfully recorded result"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload, LOINC code, unit or reference range"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to record result"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/lab-results [post]
+func (ctrl *LabResultController) Create(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.LabResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	result, err := ctrl.service.Create(patientID, userID, role, req)
+	if err != nil {
+		handleLabResultError(c, err, "Failed to record result")
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// List lists the lab results of a patient.
+// @Summary	List lab results
+// @Description	Lists the lab results of a patient, newest first, optionally narrowed down to an analyte and a time range.
+// @Tags	Lab Results
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	code query string false "LOINC code of the analyte"
+// @Param	from query string false "Start of the range (RFC 3339)"
+// @Param	to query string false "End of the range (RFC 3339)"
+// @Success	200 {array} models.LabResult "Successfully retrieved results"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid query parameters"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve results"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/lab-results [get]
+func (ctrl *LabResultController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	filter, ok := labResultFilter(c, c.Query("code"))
+	if !ok {
+		return
+	}
+
+	results, err := ctrl.service.List(patientID, userID, role, filter)
+	if err != nil {
+		handleLabResultError(c, err, "Failed to retrieve results")
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// Series retrieves the history of an analyte.
+// @Summary	Get lab time series
+// @Description	Retrieves the values of one analyte of a patient over time, oldest first, in the canonical unit of the analyte, along with its usual reference range.
+// @Tags	Lab Results
+// @Produce	json
+// @Param	id path string true "Patient ID (UUIDv4 format)"
+// @Param	code path string true "LOINC code of the analyte"
+// @Param	from query string false "Start of the range (RFC 3339)"
+// @Param	to query string false "End of the range (RFC 3339)"
+// @Success	200 {object} models.LabSeries "Successfully retrieved series"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid LOINC code or query parameters"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve series"
+// @Security	BearerAuth
+// @Router	/visit/patients/{id}/lab-results/{code}/series [get]
+func (ctrl *LabResultController) Series(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	patientID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	filter, ok := labResultFilter(c, c.Param("code"))
+	if !ok {
+		return
+	}
+
+	series, err := ctrl.service.Series(patientID, userID, role, filter)
+	if err != nil {
+		handleLabResultError(c, err, "Failed to retrieve series")
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// Import imports lab results from an HL7 file.
+// @Summary	Import HL7 lab results
+// @Description	Imports the results of the ORU^R01 messages in an HL7 v2 file. PID-3 must hold the Igaku ID of the patient and OBX-3 a LOINC code; only final or corrected numeric results are imported. Messages or observations which cannot be imported are listed in the response, and results imported before are skipped. Doctors may import results of their patients, admins of anyone.
+// @Tags	Lab Results
+// @Accept	multipart/form-data
+// @Produce	json
+// @Param	file formData file true "HL7 v2 file"
+// @Success	200 {object} dtos.LabImportResult "Import summary"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Missing file or invalid HL7"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	413 {object} commonsDtos.ErrorResponse "Request Entity Too Large - File too large"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to import results"
+// @Security	BearerAuth
+// @Router	/visit/lab-results/import [post]
+func (ctrl *LabResultController) Import(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Missing file",
+		})
+		return
+	}
+
+	if fileHeader.Size > maxLabImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, commonsDtos.ErrorResponse{
+			Message: "File too large",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Failed to read file",
+		})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxLabImportSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Failed to read file",
+		})
+		return
+	}
+
+	summary, err := ctrl.service.Import(data, userID, role)
+	if err != nil {
+		handleLabResultError(c, err, "Failed to import results")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func (ctrl *LabResultController) RegisterRoutes(router *gin.Engine) {
+	patients := router.Group("/visit/patients/:id/lab-results")
+	patients.Use(middleware.Authenticate())
+	{
+		patients.POST("", middleware.Authorize(commonsModels.Doctor), ctrl.Create)
+		patients.GET("", ctrl.List)
+		patients.GET("/:code/series", ctrl.Series)
+	}
+
+	router.POST(
+		"/visit/lab-results/import",
+		middleware.Authenticate(),
+		middleware.Authorize(commonsModels.Doctor, commonsModels.Admin),
+		ctrl.Import,
+	)
+}
+
+// labResultFilter reads the time range of a lab result query. On failure
+// it writes an error response and returns false.
+func labResultFilter(c *gin.Context, code string) (dtos.LabResultFilter, bool) {
+	from, ok := optionalTimeQuery(c, "from")
+	if !ok {
+		return dtos.LabResultFilter{}, false
+	}
+	to, ok := optionalTimeQuery(c, "to")
+	if !ok {
+		return dtos.LabResultFilter{}, false
+	}
+
+	return dtos.LabResultFilter{LOINCCode: code, From: from, To: to}, true
+}
+
+func handleLabResultError(c *gin.Context, err error, fallback string) {
+	var invalidMessage *igakuErrors.InvalidLabMessageError
+	switch {
+	case errors.As(err, &invalidMessage),
+		errors.Is(err, &igakuErrors.InvalidLabCodeError{}),
+		errors.Is(err, &igakuErrors.UnsupportedLabUnitError{}),
+		errors.Is(err, &igakuErrors.InvalidReferenceRangeError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AccessDeniedError{}):
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"igaku/visit-service/services"
 	"igaku/commons/dtos"
+	visitDtos "igaku/visit-service/dtos"
 	igakuErrors "igaku/visit-service/errors"
 )
 
@@ -58,9 +59,67 @@ func (ctrl *OrganizationController) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, org)
 }
 
+// Nearest finds the organizations closest to an address or a point.
+// @Summary	Find nearest organizations
+// @Description	Lists the organizations within a radius of an address, geocoded by the geo service, or of a point given by 'lat' and 'lon', closest first. Distances are great-circle distances in kilometres. With 'specialty', only organizations where a doctor of the specialty has slots are listed.
+// @Tags	Organizations
+// @Produce	json
+// @Param	address query string false "Address to search around, instead of 'lat' and 'lon'"
+// @Param	lat query number false "Latitude of the point to search around"
+// @Param	lon query number false "Longitude of the point to search around"
+// @Param	radius_km query number false "Search radius in kilometres (default 10, max 500)"
+// @Param	specialty query string false "Specialty of the organizations' doctors"
+// @Param	limit query int false "Maximum number of organizations (default 20, max 100)"
+// @Success	200 {array} models.NearbyOrganization "Successfully retrieved organizations"
+// @Failure	400 {object} dtos.ErrorResponse "Bad Request - Invalid search parameters"
+// @Failure	404 {object} dtos.ErrorResponse "Not Found - Address not found"
+// @Failure	500 {object} dtos.ErrorResponse "Internal Server Error - Failed to retrieve organizations"
+// @Failure	503 {object} dtos.ErrorResponse "Service Unavailable - Failed to geocode the address"
+// @Router	/visit/organizations/nearest [get]
+func (ctrl *OrganizationController) Nearest(c *gin.Context) {
+	var query visitDtos.NearestOrganizationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Message: "Invalid search parameters",
+		})
+		return
+	}
+
+	orgs, err := ctrl.service.FindNearest(query)
+	if err != nil {
+		handleOrganizationError(c, err, "Failed to retrieve organizations")
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
 func (ctrl *OrganizationController) RegisterRoutes(router *gin.Engine) {
 	routes := router.Group("/visit")
 	{
+		routes.GET("/organizations/nearest", ctrl.Nearest)
 		routes.GET("/organizations/:id", ctrl.GetByID)
 	}
 }
+
+func handleOrganizationError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, &igakuErrors.InvalidSearchLocationError{}):
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AddressNotFoundError{}),
+		errors.Is(err, &igakuErrors.OrganizationNotFoundError{}):
+		c.JSON(http.StatusNotFound, dtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.GeocodingFailedError{}):
+		c.JSON(http.StatusServiceUnavailable, dtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
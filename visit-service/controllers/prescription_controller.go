@@ -0,0 +1,407 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"mime"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type PrescriptionController struct {
+	service services.PrescriptionService
+}
+
+func NewPrescriptionController(service services.PrescriptionService) *PrescriptionController {
+	return &PrescriptionController{service: service}
+}
+
+// Issue issues a prescription.
+// @Summary	Issue prescription
+// @Description	Issues a prescription for a drug from the drug dictionary. The drug is checked against the patient's active prescriptions, recorded allergies and pharmacogenomic results; severe warnings must be overridden with a reason. Only doctors allowed to see the patient's prescriptions, health records and genomic data may issue prescriptions.
+// @Tags	Prescriptions
+// @Accept	json
+// @Produce	json
+// @Param	prescription body dtos.PrescriptionRequest true "Prescription details"
+// @Success	201 {object} models.Prescription "Successfully issued prescription"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload, drug, patient or validity period"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions or access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Patient or encounter not found"
+// @Failure	409 {object} dtos.InteractionConflictResponse "Conflict - Severe interaction requires an override reason"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to issue prescription"
+// @Security	BearerAuth
+// @Router	/visit/prescriptions [post]
+func (ctrl *PrescriptionController) Issue(c *gin.Context) {
+	doctorID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.PrescriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	p, err := ctrl.service.Issue(doctorID, role, req)
+	if err != nil {
+		handlePrescriptionError(c, err, "Failed to issue prescription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, p)
+}
+
+// Check previews the interaction warnings for a drug.
+// @Summary	Check drug interactions
+// @Description	Checks a drug against the patient's active prescriptions, recorded allergies and pharmacogenomic results without issuing a prescription. Warnings are ordered from the most severe. The doctor must be allowed to see the patient's prescriptions, health records and genomic data.
+// @Tags	Prescriptions
+// @Accept	json
+// @Produce	json
+// @Param	check body dtos.InteractionCheckRequest true "Patient and drug"
+// @Success	200 {array} models.InteractionWarning "Interaction warnings"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or drug"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions or access denied"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to check interactions"
+// @Security	BearerAuth
+// @Router	/visit/prescriptions/check [post]
+func (ctrl *PrescriptionController) Check(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.InteractionCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	warnings, err := ctrl.service.Check(
+		req.PatientID, userID, role, req.DrugID,
+	)
+	if err != nil {
+		handlePrescriptionError(c, err, "Failed to check interactions")
+		return
+	}
+
+	c.JSON(http.StatusOK, warnings)
+}
+
+// List lists the prescriptions of the current user.
+// @Summary	List prescriptions
+// @Description	Lists the prescriptions of the currently logged-in patient, or those issued by the currently logged-in doctor. Patients may restrict the list to prescriptions which can currently be dispensed.
+// @Tags	Prescriptions
+// @Produce	json
+// @Param	active query bool false "Only list active prescriptions (patients only)"
+// @Success	200 {array} models.Prescription "Successfully retrieved prescriptions"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve prescriptions"
+// @Security	BearerAuth
+// @Router	/visit/prescriptions [get]
+func (ctrl *PrescriptionController) List(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+
+	ps, err := ctrl.service.List(userID, role, c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: "Failed to retrieve prescriptions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ps)
+}
+
+// GetByID retrieves a prescription.
+// @Summary	Get prescription by ID
+// @Description	Retrieves a prescription of the currently logged-in patient, issued by the currently logged-in doctor or of a patient whose prescriptions the user may see, including under emergency access, along with the current pharmacogenomic recommendations for the drug.
+// @Tags	Prescriptions
+// @Produce	json
+// @Param	id path string true "Prescription ID (UUIDv4 format)"
+// @Success	200 {object} models.Prescription "Successfully retrieved prescription"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Prescription not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve prescription"
+// @Security	BearerAuth
+// @Router	/visit/prescriptions/{id} [get]
+func (ctrl *PrescriptionController) GetByID(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	p, err := ctrl.service.Get(id, userID, role)
+	if err != nil {
+		handlePrescriptionError(c, err, "Failed to retrieve prescription")
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// GetPDF renders a printable prescription.
+// @Summary	Download prescription PDF
+// @Description	Renders a printable prescription including its verification code.
+// @Tags	Prescriptions
+// @Produce	application/pdf
+// @Param	id path string true "Prescription ID (UUIDv4 format)"
+// @Success	200 {file} file "Prescription PDF"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Prescription not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to render prescription"
+// @Security	BearerAuth
+// @Router	/visit/prescriptions/{id}/pdf [get]
+func (ctrl *PrescriptionController) GetPDF(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	pdf, err := ctrl.service.RenderPDF(id, userID, role)
+	if err != nil {
+		handlePrescriptionError(c, err, "Failed to render prescription")
+		return
+	}
+
+	c.Header(
+		"Content-Disposition",
+		mime.FormatMediaType(
+			"inline",
+			map[string]string{"filename": "prescription-" + id.String() + ".pdf"},
+		),
+	)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// Cancel cancels a prescription.
+// @Summary	Cancel prescription
+// @Description	Cancels a prescription which has not been fully dispensed or expired yet. Only the issuing doctor and admins may cancel prescriptions.
+// @Tags	Prescriptions
+// @Produce	json
+// @Param	id path string true "Prescription ID (UUIDv4 format)"
+// @Success	200 {object} models.Prescription "Successfully cancelled prescription"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Prescription not found"
+// @Failure	409 {object} commonsDtos.ErrorResponse "Conflict - Prescription is not active"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to cancel prescription"
+// @Security	BearerAuth
+// @Router	/visit/prescriptions/{id}/cancel [post]
+func (ctrl *PrescriptionController) Cancel(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	role, ok := roleFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	p, err := ctrl.service.Cancel(id, userID, role)
+	if err != nil {
+		handlePrescriptionError(c, err, "Failed to cancel prescription")
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// Dispense records that a prescription has been dispensed.
+// @Summary	Dispense prescription
+// @Description	Records a fill of the prescription with the given verification code. The prescription becomes dispensed once all refills have been used.
+// @Tags	Prescriptions
+// @Accept	json
+// @Produce	json
+// @Param	dispense body dtos.DispenseRequest true "Verification code"
+// @Success	200 {object} models.Prescription "Successfully dispensed prescription"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Prescription not found"
+// @Failure	409 {object} commonsDtos.ErrorResponse "Conflict - Prescription is not active"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to dispense prescription"
+// @Security	BearerAuth
+// @Router	/visit/prescriptions/dispense [post]
+func (ctrl *PrescriptionController) Dispense(c *gin.Context) {
+	var req dtos.DispenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	p, err := ctrl.service.Dispense(req.VerificationCode)
+	if err != nil {
+		handlePrescriptionError(c, err, "Failed to dispense prescription")
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// Verify checks a printed prescription.
+// @Summary	Verify prescription
+// @Description	Confirms that a printed prescription is genuine and shows whether it can still be dispensed. The patient is not disclosed.
+// @Tags	Prescriptions
+// @Produce	json
+// @Param	code path string true "Verification code"
+// @Success	200 {object} dtos.PrescriptionVerification "Prescription found"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Prescription not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to verify prescription"
+// @Router	/visit/prescriptions/verify/{code} [get]
+func (ctrl *PrescriptionController) Verify(c *gin.Context) {
+	v, err := ctrl.service.Verify(c.Param("code"))
+	if err != nil {
+		handlePrescriptionError(c, err, "Failed to verify prescription")
+		return
+	}
+
+	c.JSON(http.StatusOK, v)
+}
+
+// SearchDrugs searches the drug dictionary.
+// @Summary	Search drugs
+// @Description	Returns up to 25 drugs whose name or ATC code contains the query.
+// @Tags	Prescriptions
+// @Produce	json
+// @Param	q query string true "Part of the drug name or ATC code"
+// @Success	200 {array} models.Drug "Matching drugs"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Missing query"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Security	BearerAuth
+// @Router	/visit/drugs [get]
+func (ctrl *PrescriptionController) SearchDrugs(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Missing query",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ctrl.service.SearchDrugs(query))
+}
+
+func (ctrl *PrescriptionController) RegisterRoutes(router *gin.Engine) {
+	router.GET("/visit/prescriptions/verify/:code", ctrl.Verify)
+
+	routes := router.Group("/visit")
+	routes.Use(middleware.Authenticate())
+	{
+		doctorOnly := middleware.Authorize(commonsModels.Doctor)
+
+		routes.GET("/drugs", doctorOnly, ctrl.SearchDrugs)
+		routes.POST("/prescriptions", doctorOnly, ctrl.Issue)
+		routes.POST("/prescriptions/check", doctorOnly, ctrl.Check)
+		routes.GET(
+			"/prescriptions",
+			middleware.Authorize(commonsModels.Patient, commonsModels.Doctor),
+			ctrl.List,
+		)
+		routes.POST(
+			"/prescriptions/dispense",
+			middleware.Authorize(commonsModels.Doctor, commonsModels.Admin),
+			ctrl.Dispense,
+		)
+		routes.GET("/prescriptions/:id", ctrl.GetByID)
+		routes.GET("/prescriptions/:id/pdf", ctrl.GetPDF)
+		routes.POST(
+			"/prescriptions/:id/cancel",
+			middleware.Authorize(commonsModels.Doctor, commonsModels.Admin),
+			ctrl.Cancel,
+		)
+	}
+}
+
+func handlePrescriptionError(c *gin.Context, err error, fallback string) {
+	var severe *igakuErrors.SevereInteractionError
+	switch {
+	case errors.As(err, &severe):
+		c.JSON(http.StatusConflict, dtos.InteractionConflictResponse{
+			Message: severe.Error(),
+			Warnings: severe.Warnings,
+		})
+	case errors.Is(err, &igakuErrors.UnknownDrugError{}),
+		errors.Is(err, &igakuErrors.InvalidPatientError{}),
+		errors.Is(err, &igakuErrors.InvalidTimeRangeError{}):
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.AccessDeniedError{}):
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.PrescriptionNotFoundError{}),
+		errors.Is(err, &igakuErrors.EncounterNotFoundError{}),
+		errors.Is(err, &commonsErrors.UserNotFoundError{}):
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	case errors.Is(err, &igakuErrors.PrescriptionNotActiveError{}):
+		c.JSON(http.StatusConflict, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
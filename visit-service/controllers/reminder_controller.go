@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"net/http"
+
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/commons/dtos"
+)
+
+type ReminderController struct {
+	service services.ReminderService
+}
+
+func NewReminderController(service services.ReminderService) *ReminderController {
+	return &ReminderController{service: service}
+}
+
+// GetPreferences retrieves the reminder preferences of the current user.
+// @Summary	Get reminder preferences
+// @Description	Retrieves which appointment reminders are sent to the currently logged-in user. Users who never changed their preferences get all reminders.
+// @Tags	Reminders
+// @Produce	json
+// @Success	200 {object} models.ReminderPreference "Successfully retrieved reminder preferences"
+// @Failure	401 {object} dtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	500 {object} dtos.ErrorResponse "Internal Server Error - Failed to retrieve reminder preferences"
+// @Security	BearerAuth
+// @Router	/visit/reminders/preferences [get]
+func (ctrl *ReminderController) GetPreferences(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	pref, err := ctrl.service.GetPreferences(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "Failed to retrieve reminder preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// UpdatePreferences replaces the reminder preferences of the current user.
+// @Summary	Update reminder preferences
+// @Description	Enables or disables the 24 hours and 1 hour appointment reminders for the currently logged-in user.
+// @Tags	Reminders
+// @Accept	json
+// @Produce	json
+// @Param	preferences body models.ReminderPreference true "Reminder preferences"
+// @Success	200 {object} models.ReminderPreference "Successfully updated reminder preferences"
+// @Failure	400 {object} dtos.ErrorResponse "Bad Request - Invalid request payload"
+// @Failure	401 {object} dtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	500 {object} dtos.ErrorResponse "Internal Server Error - Failed to update reminder preferences"
+// @Security	BearerAuth
+// @Router	/visit/reminders/preferences [put]
+func (ctrl *ReminderController) UpdatePreferences(c *gin.Context) {
+	userID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var pref models.ReminderPreference
+	if err := c.ShouldBindJSON(&pref); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+	pref.UserID = userID
+
+	if err := ctrl.service.UpdatePreferences(&pref); err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.ErrorResponse{
+			Message: "Failed to update reminder preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+func (ctrl *ReminderController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/reminders")
+	routes.Use(middleware.Authenticate())
+	{
+		routes.GET("/preferences", ctrl.GetPreferences)
+		routes.PUT("/preferences", ctrl.UpdatePreferences)
+	}
+}
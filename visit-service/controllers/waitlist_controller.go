@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"errors"
+	"net/http"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/middleware"
+	"igaku/visit-service/services"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type WaitlistController struct {
+	service services.WaitlistService
+}
+
+func NewWaitlistController(service services.WaitlistService) *WaitlistController {
+	return &WaitlistController{service: service}
+}
+
+// Join puts the current patient on the waitlist.
+// @Summary	Join waitlist
+// @Description	Registers interest in a doctor and/or organization within a date range. When a matching slot is freed, it is held for the longest waiting patient and offered by mail.
+// @Tags	Waitlist
+// @Accept	json
+// @Produce	json
+// @Param	entry body dtos.WaitlistRequest true "Waitlist entry details"
+// @Success	201 {object} models.WaitlistEntry "Successfully joined waitlist"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid request payload or time range"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to join waitlist"
+// @Security	BearerAuth
+// @Router	/visit/waitlist [post]
+func (ctrl *WaitlistController) Join(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.WaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+			Message: "Invalid request payload",
+		})
+		return
+	}
+
+	entry, err := ctrl.service.Join(patientID, req)
+	if err != nil {
+		if errors.Is(err, &igakuErrors.InvalidTimeRangeError{}) {
+			c.JSON(http.StatusBadRequest, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+				Message: "Failed to join waitlist",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// List lists the waitlist entries of the current patient.
+// @Summary	List waitlist entries
+// @Description	Lists all waitlist entries of the currently logged-in patient.
+// @Tags	Waitlist
+// @Produce	json
+// @Success	200 {array} models.WaitlistEntry "Successfully retrieved waitlist entries"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Insufficient permissions"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to retrieve waitlist entries"
+// @Security	BearerAuth
+// @Router	/visit/waitlist [get]
+func (ctrl *WaitlistController) List(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	entries, err := ctrl.service.List(patientID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: "Failed to retrieve waitlist entries",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// Withdraw removes a waitlist entry of the current patient.
+// @Summary	Withdraw from waitlist
+// @Description	Withdraws a waitlist entry of the currently logged-in patient. A slot held for the entry is released.
+// @Tags	Waitlist
+// @Produce	json
+// @Param	id path string true "Waitlist entry ID (UUIDv4 format)"
+// @Success	204 "Successfully withdrawn"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Waitlist entry not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to withdraw from waitlist"
+// @Security	BearerAuth
+// @Router	/visit/waitlist/{id} [delete]
+func (ctrl *WaitlistController) Withdraw(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.Withdraw(id, patientID); err != nil {
+		if errors.Is(err, &igakuErrors.WaitlistEntryNotFoundError{}) {
+			c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else if errors.Is(err, &igakuErrors.AccessDeniedError{}) {
+			c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+				Message: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+				Message: "Failed to withdraw from waitlist",
+			})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptOffer books the slot held by a waitlist offer.
+// @Summary	Accept waitlist offer
+// @Description	Books the slot held for the currently logged-in patient, provided the offer has not expired.
+// @Tags	Waitlist
+// @Produce	json
+// @Param	id path string true "Offer ID (UUIDv4 format)"
+// @Success	201 {object} models.Appointment "Successfully booked appointment"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Offer not found"
+// @Failure	410 {object} commonsDtos.ErrorResponse "Gone - Offer has expired"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to accept offer"
+// @Security	BearerAuth
+// @Router	/visit/waitlist/offers/{id}/accept [post]
+func (ctrl *WaitlistController) AcceptOffer(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	appt, err := ctrl.service.AcceptOffer(id, patientID)
+	if err != nil {
+		ctrl.handleOfferError(c, err, "Failed to accept offer")
+		return
+	}
+
+	c.JSON(http.StatusCreated, appt)
+}
+
+// DeclineOffer gives up the slot held by a waitlist offer.
+// @Summary	Decline waitlist offer
+// @Description	Releases the slot held for the currently logged-in patient, who stays on the waitlist. The slot is offered to the next patient in line.
+// @Tags	Waitlist
+// @Produce	json
+// @Param	id path string true "Offer ID (UUIDv4 format)"
+// @Success	204 "Successfully declined offer"
+// @Failure	400 {object} commonsDtos.ErrorResponse "Bad Request - Invalid UUID format"
+// @Failure	401 {object} commonsDtos.ErrorResponse "Unauthorized - Invalid or missing token"
+// @Failure	403 {object} commonsDtos.ErrorResponse "Forbidden - Access denied"
+// @Failure	404 {object} commonsDtos.ErrorResponse "Not Found - Offer not found"
+// @Failure	500 {object} commonsDtos.ErrorResponse "Internal Server Error - Failed to decline offer"
+// @Security	BearerAuth
+// @Router	/visit/waitlist/offers/{id}/decline [post]
+func (ctrl *WaitlistController) DeclineOffer(c *gin.Context) {
+	patientID, ok := userIDFromContext(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.service.DeclineOffer(id, patientID); err != nil {
+		ctrl.handleOfferError(c, err, "Failed to decline offer")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *WaitlistController) handleOfferError(
+	c *gin.Context, err error, fallback string,
+) {
+	if errors.Is(err, &igakuErrors.OfferNotFoundError{}) {
+		c.JSON(http.StatusNotFound, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	} else if errors.Is(err, &igakuErrors.AccessDeniedError{}) {
+		c.JSON(http.StatusForbidden, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	} else if errors.Is(err, &igakuErrors.OfferExpiredError{}) {
+		c.JSON(http.StatusGone, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	} else if errors.Is(err, &igakuErrors.SlotUnavailableError{}) {
+		c.JSON(http.StatusConflict, commonsDtos.ErrorResponse{
+			Message: err.Error(),
+		})
+	} else {
+		c.JSON(http.StatusInternalServerError, commonsDtos.ErrorResponse{
+			Message: fallback,
+		})
+	}
+}
+
+func (ctrl *WaitlistController) RegisterRoutes(router *gin.Engine) {
+	routes := router.Group("/visit/waitlist")
+	routes.Use(middleware.Authenticate())
+	routes.Use(middleware.Authorize(commonsModels.Patient))
+	{
+		routes.POST("", ctrl.Join)
+		routes.GET("", ctrl.List)
+		routes.DELETE("/:id", ctrl.Withdraw)
+		routes.POST("/offers/:id/accept", ctrl.AcceptOffer)
+		routes.POST("/offers/:id/decline", ctrl.DeclineOffer)
+	}
+}
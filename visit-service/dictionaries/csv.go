@@ -0,0 +1,32 @@
+package dictionaries
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+)
+
+// readCSV reads a dictionary file, skipping its header row. Every record
+// must have exactly the given number of fields.
+func readCSV(path string, fields int) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dictionary: %w", err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = fields
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dictionary %s: %w", path, err)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("dictionary %s is empty", path)
+	}
+
+	return records[1:], nil
+}
@@ -0,0 +1,84 @@
+package dictionaries
+
+import (
+	"sort"
+	"strings"
+
+	"igaku/visit-service/models"
+)
+
+type DrugDictionary interface {
+	Lookup(id string) (*models.Drug, bool)
+	// Search returns up to limit drugs whose name or ATC code contains the
+	// query.
+	Search(query string, limit int) []models.Drug
+}
+
+type drugDictionary struct {
+	drugs	[]models.Drug
+	byID	map[string]*models.Drug
+}
+
+// LoadDrugDictionary reads a CSV file with
+// `id,name,atc_code,form,strength` records.
+func LoadDrugDictionary(path string) (DrugDictionary, error) {
+	records, err := readCSV(path, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	drugs := make([]models.Drug, 0, len(records))
+	for _, rec := range records {
+		drugs = append(drugs, models.Drug{
+			ID: strings.TrimSpace(rec[0]),
+			Name: strings.TrimSpace(rec[1]),
+			ATCCode: strings.ToUpper(strings.TrimSpace(rec[2])),
+			Form: strings.TrimSpace(rec[3]),
+			Strength: strings.TrimSpace(rec[4]),
+		})
+	}
+
+	return NewDrugDictionary(drugs), nil
+}
+
+func NewDrugDictionary(drugs []models.Drug) DrugDictionary {
+	sorted := make([]models.Drug, len(drugs))
+	copy(sorted, drugs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	byID := make(map[string]*models.Drug, len(sorted))
+	for i := range sorted {
+		byID[sorted[i].ID] = &sorted[i]
+	}
+
+	return &drugDictionary{drugs: sorted, byID: byID}
+}
+
+func (d *drugDictionary) Lookup(id string) (*models.Drug, bool) {
+	drug, ok := d.byID[strings.TrimSpace(id)]
+	if !ok {
+		return nil, false
+	}
+
+	result := *drug
+	return &result, true
+}
+
+func (d *drugDictionary) Search(query string, limit int) []models.Drug {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	results := []models.Drug{}
+	for _, drug := range d.drugs {
+		if len(results) >= limit {
+			break
+		}
+		if strings.Contains(strings.ToLower(drug.Name), query) ||
+			strings.Contains(strings.ToLower(drug.ATCCode), query) {
+			results = append(results, drug)
+		}
+	}
+
+	return results
+}
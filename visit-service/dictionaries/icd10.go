@@ -0,0 +1,87 @@
+package dictionaries
+
+import (
+	"sort"
+	"strings"
+
+	"igaku/visit-service/models"
+)
+
+type ICD10Dictionary interface {
+	// Lookup returns the dictionary entry for a code, ignoring case and
+	// surrounding whitespace.
+	Lookup(code string) (*models.ICD10Code, bool)
+	// Search returns up to limit entries whose code starts with, or whose
+	// description contains, the query.
+	Search(query string, limit int) []models.ICD10Code
+}
+
+type icd10Dictionary struct {
+	codes	[]models.ICD10Code
+	byCode	map[string]*models.ICD10Code
+}
+
+// LoadICD10Dictionary reads a CSV file with `code,description` records.
+func LoadICD10Dictionary(path string) (ICD10Dictionary, error) {
+	records, err := readCSV(path, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]models.ICD10Code, 0, len(records))
+	for _, rec := range records {
+		codes = append(codes, models.ICD10Code{
+			Code: normalizeICD10Code(rec[0]),
+			Description: strings.TrimSpace(rec[1]),
+		})
+	}
+
+	return NewICD10Dictionary(codes), nil
+}
+
+func NewICD10Dictionary(codes []models.ICD10Code) ICD10Dictionary {
+	sorted := make([]models.ICD10Code, len(codes))
+	copy(sorted, codes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Code < sorted[j].Code
+	})
+
+	byCode := make(map[string]*models.ICD10Code, len(sorted))
+	for i := range sorted {
+		byCode[sorted[i].Code] = &sorted[i]
+	}
+
+	return &icd10Dictionary{codes: sorted, byCode: byCode}
+}
+
+func (d *icd10Dictionary) Lookup(code string) (*models.ICD10Code, bool) {
+	entry, ok := d.byCode[normalizeICD10Code(code)]
+	if !ok {
+		return nil, false
+	}
+
+	result := *entry
+	return &result, true
+}
+
+func (d *icd10Dictionary) Search(query string, limit int) []models.ICD10Code {
+	code := normalizeICD10Code(query)
+	text := strings.ToLower(strings.TrimSpace(query))
+
+	results := []models.ICD10Code{}
+	for _, entry := range d.codes {
+		if len(results) >= limit {
+			break
+		}
+		if strings.HasPrefix(entry.Code, code) ||
+			strings.Contains(strings.ToLower(entry.Description), text) {
+			results = append(results, entry)
+		}
+	}
+
+	return results
+}
+
+func normalizeICD10Code(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,126 @@
+package dictionaries
+
+import (
+	"fmt"
+	"strings"
+
+	"igaku/visit-service/models"
+)
+
+type InteractionDataset interface {
+	// Interactions returns the rules matching the pair of drugs, in
+	// either order.
+	Interactions(a, b models.Drug) []models.InteractionRule
+	// AllergyRules returns the rules relating the allergen to the drug.
+	AllergyRules(allergen string, drug models.Drug) []models.AllergyRule
+}
+
+type interactionDataset struct {
+	interactions	[]models.InteractionRule
+	allergies	map[string][]models.AllergyRule
+}
+
+// LoadInteractionDataset reads the drug-drug interactions from a CSV file
+// with `atc_a,atc_b,severity,description` records, and the allergen
+// classes from one with `allergen,atc_prefix,severity,description`
+// records.
+func LoadInteractionDataset(
+	interactionsPath, allergiesPath string,
+) (InteractionDataset, error) {
+	records, err := readCSV(interactionsPath, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	interactions := make([]models.InteractionRule, 0, len(records))
+	for _, rec := range records {
+		severity, err := parseSeverity(rec[2])
+		if err != nil {
+			return nil, err
+		}
+		interactions = append(interactions, models.InteractionRule{
+			ATCA: strings.ToUpper(strings.TrimSpace(rec[0])),
+			ATCB: strings.ToUpper(strings.TrimSpace(rec[1])),
+			Severity: severity,
+			Description: strings.TrimSpace(rec[3]),
+		})
+	}
+
+	records, err = readCSV(allergiesPath, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	allergies := make([]models.AllergyRule, 0, len(records))
+	for _, rec := range records {
+		severity, err := parseSeverity(rec[2])
+		if err != nil {
+			return nil, err
+		}
+		allergies = append(allergies, models.AllergyRule{
+			Allergen: rec[0],
+			ATCPrefix: strings.ToUpper(strings.TrimSpace(rec[1])),
+			Severity: severity,
+			Description: strings.TrimSpace(rec[3]),
+		})
+	}
+
+	return NewInteractionDataset(interactions, allergies), nil
+}
+
+func NewInteractionDataset(
+	interactions []models.InteractionRule, allergies []models.AllergyRule,
+) InteractionDataset {
+	byAllergen := make(map[string][]models.AllergyRule)
+	for _, rule := range allergies {
+		key := NormalizeAllergen(rule.Allergen)
+		byAllergen[key] = append(byAllergen[key], rule)
+	}
+
+	return &interactionDataset{
+		interactions: interactions,
+		allergies: byAllergen,
+	}
+}
+
+func (d *interactionDataset) Interactions(a, b models.Drug) []models.InteractionRule {
+	var rules []models.InteractionRule
+	for _, rule := range d.interactions {
+		if matchesATC(a, rule.ATCA) && matchesATC(b, rule.ATCB) ||
+			matchesATC(a, rule.ATCB) && matchesATC(b, rule.ATCA) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (d *interactionDataset) AllergyRules(
+	allergen string, drug models.Drug,
+) []models.AllergyRule {
+	var rules []models.AllergyRule
+	for _, rule := range d.allergies[NormalizeAllergen(allergen)] {
+		if matchesATC(drug, rule.ATCPrefix) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// NormalizeAllergen makes allergen names comparable regardless of case,
+// surrounding whitespace and a plural `s`.
+func NormalizeAllergen(allergen string) string {
+	allergen = strings.ToLower(strings.TrimSpace(allergen))
+	return strings.TrimSuffix(allergen, "s")
+}
+
+func matchesATC(drug models.Drug, prefix string) bool {
+	return drug.ATCCode != "" && strings.HasPrefix(drug.ATCCode, prefix)
+}
+
+func parseSeverity(s string) (models.InteractionSeverity, error) {
+	severity := models.InteractionSeverity(strings.ToLower(strings.TrimSpace(s)))
+	if severity.Rank() == 0 {
+		return "", fmt.Errorf("unknown severity %q", s)
+	}
+	return severity, nil
+}
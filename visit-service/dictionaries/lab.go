@@ -0,0 +1,105 @@
+package dictionaries
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"igaku/visit-service/models"
+)
+
+type LabDictionary interface {
+	// Lookup returns the analyte with the given LOINC code.
+	Lookup(code string) (*models.LabAnalyte, bool)
+}
+
+type labDictionary struct {
+	byCode map[string]*models.LabAnalyte
+}
+
+// LoadLabDictionary reads the analytes from a CSV file with
+// `loinc_code,name,unit,reference_low,reference_high,critical_low,critical_high`
+// records, where empty limits are open, and the unit conversions from one
+// with `loinc_code,unit,factor` records.
+func LoadLabDictionary(analytesPath, unitsPath string) (LabDictionary, error) {
+	records, err := readCSV(analytesPath, 7)
+	if err != nil {
+		return nil, err
+	}
+
+	analytes := make([]models.LabAnalyte, 0, len(records))
+	index := make(map[string]int, len(records))
+	for _, rec := range records {
+		limits := make([]*float64, 4)
+		for i := range limits {
+			if limits[i], err = parseLimit(rec[3+i]); err != nil {
+				return nil, err
+			}
+		}
+
+		code := strings.TrimSpace(rec[0])
+		index[code] = len(analytes)
+		analytes = append(analytes, models.LabAnalyte{
+			Code: code,
+			Name: strings.TrimSpace(rec[1]),
+			Unit: strings.TrimSpace(rec[2]),
+			ReferenceLow: limits[0],
+			ReferenceHigh: limits[1],
+			CriticalLow: limits[2],
+			CriticalHigh: limits[3],
+			Factors: map[string]float64{},
+		})
+	}
+
+	records, err = readCSV(unitsPath, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range records {
+		i, ok := index[strings.TrimSpace(rec[0])]
+		if !ok {
+			return nil, fmt.Errorf("unit conversion for unknown analyte %q", rec[0])
+		}
+		factor, err := strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+		if err != nil || factor <= 0 {
+			return nil, fmt.Errorf("invalid conversion factor %q", rec[2])
+		}
+		analytes[i].Factors[models.NormalizeLabUnit(rec[1])] = factor
+	}
+
+	return NewLabDictionary(analytes), nil
+}
+
+func NewLabDictionary(analytes []models.LabAnalyte) LabDictionary {
+	byCode := make(map[string]*models.LabAnalyte, len(analytes))
+	for i := range analytes {
+		analyte := analytes[i]
+		byCode[analyte.Code] = &analyte
+	}
+
+	return &labDictionary{byCode: byCode}
+}
+
+func (d *labDictionary) Lookup(code string) (*models.LabAnalyte, bool) {
+	analyte, ok := d.byCode[strings.TrimSpace(code)]
+	if !ok {
+		return nil, false
+	}
+
+	result := *analyte
+	return &result, true
+}
+
+func parseLimit(s string) (*float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	limit, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid limit %q", s)
+	}
+	return &limit, nil
+}
@@ -0,0 +1,72 @@
+package dictionaries
+
+import (
+	"strings"
+
+	"igaku/visit-service/models"
+)
+
+type PharmacogenomicGuidelines interface {
+	// Recommendations returns the rules for the gene and phenotype which
+	// cover the drug.
+	Recommendations(gene, phenotype string, drug models.Drug) []models.PharmacogenomicRule
+}
+
+type pharmacogenomicGuidelines struct {
+	byGenotype map[string][]models.PharmacogenomicRule
+}
+
+// LoadPharmacogenomicGuidelines reads a CSV file with
+// `gene,phenotype,atc_prefix,severity,recommendation` records.
+func LoadPharmacogenomicGuidelines(path string) (PharmacogenomicGuidelines, error) {
+	records, err := readCSV(path, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]models.PharmacogenomicRule, 0, len(records))
+	for _, rec := range records {
+		severity, err := parseSeverity(rec[3])
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, models.PharmacogenomicRule{
+			Gene: rec[0],
+			Phenotype: rec[1],
+			ATCPrefix: strings.ToUpper(strings.TrimSpace(rec[2])),
+			Severity: severity,
+			Recommendation: strings.TrimSpace(rec[4]),
+		})
+	}
+
+	return NewPharmacogenomicGuidelines(rules), nil
+}
+
+func NewPharmacogenomicGuidelines(
+	rules []models.PharmacogenomicRule,
+) PharmacogenomicGuidelines {
+	byGenotype := make(map[string][]models.PharmacogenomicRule)
+	for _, rule := range rules {
+		key := genotypeKey(rule.Gene, rule.Phenotype)
+		byGenotype[key] = append(byGenotype[key], rule)
+	}
+
+	return &pharmacogenomicGuidelines{byGenotype: byGenotype}
+}
+
+func (g *pharmacogenomicGuidelines) Recommendations(
+	gene, phenotype string, drug models.Drug,
+) []models.PharmacogenomicRule {
+	var matches []models.PharmacogenomicRule
+	for _, rule := range g.byGenotype[genotypeKey(gene, phenotype)] {
+		if strings.HasPrefix(drug.ATCCode, rule.ATCPrefix) {
+			matches = append(matches, rule)
+		}
+	}
+	return matches
+}
+
+func genotypeKey(gene, phenotype string) string {
+	return strings.ToUpper(strings.TrimSpace(gene)) + "|" +
+		strings.ToLower(strings.TrimSpace(phenotype))
+}
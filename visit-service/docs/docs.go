@@ -15,40 +15,71 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/visit/health": {
+        "/visit/appointments": {
             "get": {
-                "description": "Returns an OK message",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the appointments of the currently logged-in patient, or of the currently logged-in doctor.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Appointments"
                 ],
-                "summary": "Check health",
+                "summary": "List appointments",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfully retrieved appointments",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Appointment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve appointments",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/visit/organizations/{id}": {
-            "get": {
-                "description": "Retrieves details for a specific organization using its UUID.",
+        "/visit/appointments/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels an appointment of the currently logged-in patient or doctor. Admins may cancel any appointment. The freed slot is offered to the waitlist.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "Appointments"
                 ],
-                "summary": "Get organization by ID",
+                "summary": "Cancel appointment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID (UUIDv4 format)",
+                        "description": "Appointment ID (UUIDv4 format)",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -56,9 +87,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved organization",
+                        "description": "Successfully cancelled appointment",
                         "schema": {
-                            "$ref": "#/definitions/models.Organization"
+                            "$ref": "#/definitions/models.Appointment"
                         }
                     },
                     "400": {
@@ -67,66 +98,7171 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found - Organization not found",
+                        "description": "Not Found - Appointment not found",
                         "schema": {
                             "$ref": "#/definitions/dtos.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error - Failed to retrieve organization",
+                        "description": "Internal Server Error - Failed to cancel appointment",
                         "schema": {
                             "$ref": "#/definitions/dtos.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dtos.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Specific error message"
+        },
+        "/visit/appointments/{id}/encounter": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the encounter of an appointment with its full amendment history. Only the patient, the treating doctor, delegated doctors, doctors allowed to see the patient's encounters by their care team or consents and doctors with emergency access to the patient have access; the patient's consent may also deny delegated doctors access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Get encounter by appointment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved encounter",
+                        "schema": {
+                            "$ref": "#/definitions/models.Encounter"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve encounter",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the notes, ICD-10 diagnoses and vitals of an appointment. Only the treating doctor may create the encounter, and only once per appointment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Create encounter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Appointment ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Encounter notes",
+                        "name": "notes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.EncounterNotesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created encounter",
+                        "schema": {
+                            "$ref": "#/definitions/models.Encounter"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or diagnosis code",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Appointment not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - Encounter already exists or appointment cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to create encounter",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "dtos.Location": {
-            "type": "object",
-            "required": [
-                "display_name",
-                "lat",
-                "lon",
-                "osm_id"
-            ],
-            "properties": {
-                "display_name": {
-                    "type": "string",
-                    "example": "135, Pilkington Avenue, Maney, Sutton Coldfield, Birmingham, West Midlands, England, B72 1LH, United Kingdom"
-                },
-                "lat": {
-                    "type": "string",
-                    "example": "52.5487921"
-                },
-                "lon": {
-                    "type": "string",
-                    "example": "-1.8164308"
-                },
-                "osm_id": {
-                    "type": "integer",
-                    "example": 90394480
+        "/visit/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the consents of the currently logged-in patient, newest first, including revoked and expired ones unless only those in force are requested.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "List consents",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only list consents currently in force",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved consents",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Consent"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve consents",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Permits or denies a doctor, or every doctor working at an organization, access to categories of the patient's clinical data (records, genomics, lab_results, documents, encounters, prescriptions), optionally for a limited time. A deny withdraws the access doctors have as members of the patient's care team and takes precedence over any permit, but not over emergency access, of which the patient is notified by mail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Grant consent",
+                "parameters": [
+                    {
+                        "description": "Consent details",
+                        "name": "consent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ConsentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully granted consent",
+                        "schema": {
+                            "$ref": "#/definitions/models.Consent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload, category, grantee or time range",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to grant consent",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "models.Organization": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string",
-                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
-                },
+        "/visit/consents/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every grant and revocation of the currently logged-in patient's consents, newest first, as they were at the time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Get consent history",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ConsentEvent"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve history",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/consents/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes a consent with immediate effect. The consent is kept in the history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Consents"
+                ],
+                "summary": "Revoke consent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Consent ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully revoked consent",
+                        "schema": {
+                            "$ref": "#/definitions/models.Consent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Consent not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - Consent already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to revoke consent",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/conversations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the conversations of the currently logged-in user, most recently active first, with the number of unread messages in each.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "List conversations",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved conversations",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Conversation"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve conversations",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a conversation between a patient and a doctor with its first message. Patients write to doctors and doctors to patients. The doctor must be on the patient's care team, unless the conversation is about an appointment of the two. A document of the patient may be attached if the doctor may see the patient's documents. The recipient is notified by mail, without the contents of the message.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Start conversation",
+                "parameters": [
+                    {
+                        "description": "Conversation details",
+                        "name": "conversation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ConversationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully started conversation",
+                        "schema": {
+                            "$ref": "#/definitions/models.Conversation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - No care relationship or access to the attachment",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Appointment or document not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to start conversation",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/conversations/unread": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Counts the messages sent to the currently logged-in user which they have not read yet, across all of their conversations.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Count unread messages",
+                "responses": {
+                    "200": {
+                        "description": "Successfully counted unread messages",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UnreadCount"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to count unread messages",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/conversations/{id}/messages": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the messages of a conversation of the currently logged-in user, oldest first. Messages read by their recipient carry the time they were read.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "List messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved messages",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Message"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Conversation not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve messages",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a message to a conversation of the currently logged-in user. A document of the patient may be attached if the doctor may see the patient's documents. The recipient is notified by mail, without the contents of the message.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Send message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Message",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.MessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully sent message",
+                        "schema": {
+                            "$ref": "#/definitions/models.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format or request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - No access to the attachment",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Conversation or document not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to send message",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/conversations/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks the messages sent to the currently logged-in user in a conversation as read, which the sender sees as a read receipt.",
+                "tags": [
+                    "Messaging"
+                ],
+                "summary": "Mark conversation as read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conversation ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully marked messages as read"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Conversation not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to mark messages as read",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/diagnoses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns up to 25 ICD-10 codes whose code starts with, or whose description contains, the query.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Search ICD-10 codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Code prefix or part of the description",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching ICD-10 codes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ICD10Code"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Missing query",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/doctors/{id}/specialties": {
+            "get": {
+                "description": "Lists the specialties of a doctor in alphabetical order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctors"
+                ],
+                "summary": "Get doctor specialties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved specialties",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve specialties",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the specialties of a doctor. Specialties are stored in lower case; duplicates are dropped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Doctors"
+                ],
+                "summary": "Set doctor specialties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New specialties",
+                        "name": "specialties",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DoctorSpecialtiesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully set specialties",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or user is not a doctor",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to set specialties",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/documents/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the metadata of a document. The content is downloaded through a link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Get document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved document",
+                        "schema": {
+                            "$ref": "#/definitions/models.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve document",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a document and its content. Only the uploader and admins may remove a document; emergency access tokens may not.",
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Delete document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully deleted document"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to delete document",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/documents/{id}/content": {
+            "get": {
+                "description": "Downloads the content of a document. The query parameters come from a link issued by the create document link endpoint.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Download document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User the link was issued to",
+                        "name": "user",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role of the user",
+                        "name": "role",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expiry of the link (Unix time)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Signature of the link",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Invalid or expired link, or access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve document",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/documents/{id}/link": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a short-lived URL through which the document can be downloaded without a bearer token, e.g. by a browser. The link only works for the user it was issued to, as long as they still have access to the patient.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Create document link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Document ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully issued link",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DocumentLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to issue link",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/drugs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns up to 25 drugs whose name or ATC code contains the query.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Search drugs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the drug name or ATC code",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching drugs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Drug"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Missing query",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/emergency-access": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the emergency accesses granted to the currently logged-in doctor, newest first, along with their review outcome.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Emergency Access"
+                ],
+                "summary": "List own emergency accesses",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved emergency accesses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EmergencyAccess"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve emergency accesses",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Grants \"break-the-glass\" access to a patient outside the doctor's care team, for a limited time and regardless of the patient's consents. A justification of at least 20 characters is required. The returned token is only valid for reading the clinical data of the patient (records, summary, lab results, documents); every read made with it is logged. The patient is notified by mail and every access is reviewed by an admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Emergency Access"
+                ],
+                "summary": "Request emergency access",
+                "parameters": [
+                    {
+                        "description": "Emergency access details",
+                        "name": "access",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.EmergencyAccessRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully granted emergency access",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.EmergencyAccessGrant"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload, patient or justification",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to grant emergency access",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/emergency-access/queue": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the emergency accesses with the review status, oldest first. Lists those awaiting review by default.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Emergency Access"
+                ],
+                "summary": "Get emergency access review queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review status (pending, approved, flagged)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved emergency accesses",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EmergencyAccess"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid review status",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve emergency accesses",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/emergency-access/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every read of the patient's clinical data made under an emergency access, oldest first, with the categories of data read.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Emergency Access"
+                ],
+                "summary": "Get emergency access log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Emergency access ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved emergency access log",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EmergencyAccessEvent"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Emergency access not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve emergency access log",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/emergency-access/{id}/review": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approves an emergency access as justified or flags it as unjustified. Flagging an access still in force ends it immediately.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Emergency Access"
+                ],
+                "summary": "Review emergency access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Emergency access ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review outcome",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.EmergencyReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully reviewed emergency access",
+                        "schema": {
+                            "$ref": "#/definitions/models.EmergencyAccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format or request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Emergency access not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - Emergency access already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to review emergency access",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/encounters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the encounters the currently logged-in user is the patient or treating doctor of, or has been delegated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "List encounters",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved encounters",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Encounter"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve encounters",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/encounters/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves an encounter with its full amendment history. Only the patient, the treating doctor, delegated doctors, doctors allowed to see the patient's encounters by their care team or consents and doctors with emergency access to the patient have access; the patient's consent may also deny delegated doctors access.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Get encounter by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Encounter ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved encounter",
+                        "schema": {
+                            "$ref": "#/definitions/models.Encounter"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve encounter",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/encounters/{id}/amendments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a new revision of the encounter notes. Earlier revisions are kept unchanged. Only the treating doctor may amend the encounter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Amend encounter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Encounter ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amended notes and reason",
+                        "name": "amendment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.EncounterAmendmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully amended encounter",
+                        "schema": {
+                            "$ref": "#/definitions/models.EncounterRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or diagnosis code",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to amend encounter",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/encounters/{id}/attachments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaches a file of up to 10 MiB to an encounter. Attachments cannot be removed. Only the treating doctor may add attachments.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Add encounter attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Encounter ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Attachment",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully added attachment",
+                        "schema": {
+                            "$ref": "#/definitions/models.EncounterAttachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Missing file",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large - Attachment too large",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to add attachment",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/encounters/{id}/attachments/{attachmentId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads the content of an encounter attachment. Only the patient, the treating doctor, delegated doctors, doctors allowed to see the patient's encounters by their care team or consents and doctors with emergency access to the patient have access; the patient's consent may also deny delegated doctors access.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Download encounter attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Encounter ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID (UUIDv4 format)",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attachment content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter or attachment not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve attachment",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/encounters/{id}/delegations": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Grants another doctor read access to an encounter. Only the patient and the treating doctor may delegate access.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Delegate encounter access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Encounter ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Delegated doctor",
+                        "name": "delegation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DelegationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully delegated access",
+                        "schema": {
+                            "$ref": "#/definitions/models.EncounterDelegation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or delegate",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to delegate access",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/encounters/{id}/delegations/{doctorId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraws the read access previously delegated to a doctor. Only the patient and the treating doctor may revoke access.",
+                "tags": [
+                    "Encounters"
+                ],
+                "summary": "Revoke encounter delegation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Encounter ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delegated doctor ID (UUIDv4 format)",
+                        "name": "doctorId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully revoked delegation"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to revoke delegation",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/fhir/metadata": {
+            "get": {
+                "description": "Describes the resources, interactions and search parameters supported by the FHIR R4 facade.",
+                "produces": [
+                    "application/fhir+json"
+                ],
+                "tags": [
+                    "FHIR"
+                ],
+                "summary": "FHIR CapabilityStatement",
+                "responses": {
+                    "200": {
+                        "description": "CapabilityStatement",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.CapabilityStatement"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/fhir/{type}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Searches Patient, Practitioner, Organization, Location or Appointment resources and returns a searchset Bundle. Pages are selected with ` + "`" + `_count` + "`" + ` (at most 100) and ` + "`" + `_offset` + "`" + `; unknown search parameters are rejected. Patients only find themselves and their own appointments, doctors their own appointments.",
+                "produces": [
+                    "application/fhir+json"
+                ],
+                "tags": [
+                    "FHIR"
+                ],
+                "summary": "FHIR search",
+                "parameters": [
+                    {
+                        "enum": [
+                            "Patient",
+                            "Practitioner",
+                            "Organization",
+                            "Location",
+                            "Appointment"
+                        ],
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Page size (default: 20)",
+                        "name": "_count",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "description": "Number of matches to skip",
+                        "name": "_offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Search results",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.Bundle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Unsupported or invalid search parameter",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Unsupported resource type",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Search failed",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/fhir/{type}/$validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Checks a JSON resource against the base R4 structure of its type: known elements, cardinalities, primitive formats and required code bindings. The outcome lists every issue found.",
+                "consumes": [
+                    "application/fhir+json"
+                ],
+                "produces": [
+                    "application/fhir+json"
+                ],
+                "tags": [
+                    "FHIR"
+                ],
+                "summary": "FHIR $validate",
+                "parameters": [
+                    {
+                        "enum": [
+                            "Patient",
+                            "Practitioner",
+                            "Organization",
+                            "Location",
+                            "Appointment"
+                        ],
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resource to validate",
+                        "name": "resource",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Validation outcome",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Unsupported resource type",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large - Resource too large",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/fhir/{type}/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reads a Patient, Practitioner, Organization, Location or Appointment resource.",
+                "produces": [
+                    "application/fhir+json"
+                ],
+                "tags": [
+                    "FHIR"
+                ],
+                "summary": "FHIR read",
+                "parameters": [
+                    {
+                        "enum": [
+                            "Patient",
+                            "Practitioner",
+                            "Organization",
+                            "Location",
+                            "Appointment"
+                        ],
+                        "type": "string",
+                        "description": "Resource type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Logical ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resource",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Resource not found",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Read failed",
+                        "schema": {
+                            "$ref": "#/definitions/fhir.OperationOutcome"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/health": {
+            "get": {
+                "description": "Returns an OK message",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Check health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/home-address": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the home address of the currently logged-in patient, along with the place it was geocoded to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Home Address"
+                ],
+                "summary": "Get home address",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved home address",
+                        "schema": {
+                            "$ref": "#/definitions/models.HomeAddress"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Home address not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve home address",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Geocodes the given address and saves it as the home address of the currently logged-in patient. Booking confirmations include estimates of the travel from the home address to the organization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Home Address"
+                ],
+                "summary": "Set home address",
+                "parameters": [
+                    {
+                        "description": "Home address",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.HomeAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully saved home address",
+                        "schema": {
+                            "$ref": "#/definitions/models.HomeAddress"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or address not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to save home address",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Failed to geocode the address",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the home address of the currently logged-in patient. Booking confirmations no longer include travel estimates.",
+                "tags": [
+                    "Home Address"
+                ],
+                "summary": "Delete home address",
+                "responses": {
+                    "204": {
+                        "description": "Successfully deleted home address"
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Home address not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to delete home address",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/lab-results/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Imports the results of the ORU^R01 messages in an HL7 v2 file. PID-3 must hold the Igaku ID of the patient and OBX-3 a LOINC code; only final or corrected numeric results are imported. Messages or observations which cannot be imported are listed in the response, and results imported before are skipped. Doctors may import results of their patients, admins of anyone.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab Results"
+                ],
+                "summary": "Import HL7 lab results",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "HL7 v2 file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import summary",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.LabImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Missing file or invalid HL7",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large - File too large",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to import results",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/organizations/nearest": {
+            "get": {
+                "description": "Lists the organizations within a radius of an address, geocoded by the geo service, or of a point given by 'lat' and 'lon', closest first. Distances are great-circle distances in kilometres. With 'specialty', only organizations where a doctor of the specialty has slots are listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Find nearest organizations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Address to search around, instead of 'lat' and 'lon'",
+                        "name": "address",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the point to search around",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the point to search around",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Search radius in kilometres (default 10, max 500)",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Specialty of the organizations' doctors",
+                        "name": "specialty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of organizations (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved organizations",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NearbyOrganization"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid search parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Address not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve organizations",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable - Failed to geocode the address",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/organizations/{id}": {
+            "get": {
+                "description": "Retrieves details for a specific organization using its UUID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Organizations"
+                ],
+                "summary": "Get organization by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organization ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved organization",
+                        "schema": {
+                            "$ref": "#/definitions/models.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve organization",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the care team assignments of the currently logged-in doctor, i.e. the patients they treat, one entry per patient and organization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Care Teams"
+                ],
+                "summary": "List own patients",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved patients",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CareAssignment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve patients",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/care-team": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the doctors currently on the care team of a patient, the primary doctor first. Only the patient, admins and the members of the care team may see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Care Teams"
+                ],
+                "summary": "Get care team",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved care team",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CareAssignment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve care team",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Places a doctor on the care team of a patient at one of the organizations. Assigning a primary doctor makes the previous one a regular member. Doctors assigned this way stay on the care team until removed, regardless of appointments.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Care Teams"
+                ],
+                "summary": "Assign care team member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assignment details",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CareAssignmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully assigned care team member",
+                        "schema": {
+                            "$ref": "#/definitions/models.CareAssignment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload, patient or doctor",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to assign care team member",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/care-team/{assignmentId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an assignment from the care team of a patient. The doctor loses access to the patient unless they are still on the care team at another organization or the patient's consent permits them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Care Teams"
+                ],
+                "summary": "Remove care team member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Assignment ID (UUIDv4 format)",
+                        "name": "assignmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully removed care team member",
+                        "schema": {
+                            "$ref": "#/definitions/models.CareAssignment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Care team member not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to remove care team member",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/documents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the documents of a patient, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "List documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved documents",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Document"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve documents",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads a PDF, JPEG, PNG or WebP file of up to 25 MiB, such as a scan or a referral, to the record of a patient. The type is detected from the content. The form fields must precede the file. Patients may upload their own documents, doctors those of their patients; a linked encounter must be one of the patient's and, for doctors, one they led.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Documents"
+                ],
+                "summary": "Upload document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title, defaults to the file name",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "referral",
+                            "scan",
+                            "lab_report",
+                            "imaging",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Category, defaults to other",
+                        "name": "category",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Encounter ID (UUIDv4 format)",
+                        "name": "encounter_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Document",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully uploaded document",
+                        "schema": {
+                            "$ref": "#/definitions/models.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Missing file, empty document or invalid form fields",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large - Document too large",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type - Unsupported document type",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to upload document",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/lab-results": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the lab results of a patient, newest first, optionally narrowed down to an analyte and a time range.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab Results"
+                ],
+                "summary": "List lab results",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "LOINC code of the analyte",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved results",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.LabResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve results",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a numeric lab result of a patient. Values of common analytes are converted to their canonical unit, and every value is flagged against the given reference range or, when none is given, the usual range of the analyte. Only doctors on the patient's care team, or whose consent permits them to see lab results, may record results.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab Results"
+                ],
+                "summary": "Record lab result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Result details",
+                        "name": "result",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.LabResultRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully recorded result",
+                        "schema": {
+                            "$ref": "#/definitions/models.LabResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload, LOINC code, unit or reference range",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to record result",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/lab-results/{code}/series": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the values of one analyte of a patient over time, oldest first, in the canonical unit of the analyte, along with its usual reference range.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lab Results"
+                ],
+                "summary": "Get lab time series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "LOINC code of the analyte",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved series",
+                        "schema": {
+                            "$ref": "#/definitions/models.LabSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid LOINC code or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve series",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/records/{kind}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the entries of one kind from the medical history of a patient.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health Records"
+                ],
+                "summary": "List health records",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "allergies",
+                            "conditions",
+                            "immunizations",
+                            "medications",
+                            "genotypes"
+                        ],
+                        "type": "string",
+                        "description": "Kind of record",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved records",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Unknown kind of record",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve records",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records an allergy, chronic condition, immunization, medication or pharmacogenomic result. The body matches the kind: dtos.AllergyRequest, dtos.ConditionRequest, dtos.ImmunizationRequest, dtos.MedicationStatementRequest or dtos.GenotypeRequest. Patients may record self-reported entries; doctors may link entries to one of their encounters with the patient.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health Records"
+                ],
+                "summary": "Create health record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "allergies",
+                            "conditions",
+                            "immunizations",
+                            "medications",
+                            "genotypes"
+                        ],
+                        "type": "string",
+                        "description": "Kind of record",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Record details",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.AllergyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created record",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload, condition code or phenotype",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Unknown kind of record or encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to create record",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/records/{kind}/{recordId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the details of a record. Patients may only update entries they recorded themselves which no one else has changed since.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health Records"
+                ],
+                "summary": "Update health record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "allergies",
+                            "conditions",
+                            "immunizations",
+                            "medications",
+                            "genotypes"
+                        ],
+                        "type": "string",
+                        "description": "Kind of record",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Record ID (UUIDv4 format)",
+                        "name": "recordId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Record details",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.AllergyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated record",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload, condition code or phenotype",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Record not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to update record",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a record, e.g. one filed against the wrong patient. Patients may only remove entries they recorded themselves which no one else has changed since.",
+                "tags": [
+                    "Health Records"
+                ],
+                "summary": "Delete health record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "allergies",
+                            "conditions",
+                            "immunizations",
+                            "medications",
+                            "genotypes"
+                        ],
+                        "type": "string",
+                        "description": "Kind of record",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Record ID (UUIDv4 format)",
+                        "name": "recordId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully deleted record"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Record not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to delete record",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/patients/{id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the allergies, chronic conditions, immunizations, medication history, pharmacogenomic results and active prescriptions of a patient. Patients see their own summary, admins any. Doctors see the summary of patients whose care team they are on or whose consent permits them, except for the sections the patient denied them. Doctors with emergency access to the patient see all of it, and every read is logged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health Records"
+                ],
+                "summary": "Get patient summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Patient ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved summary",
+                        "schema": {
+                            "$ref": "#/definitions/models.PatientSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve summary",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/prescriptions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the prescriptions of the currently logged-in patient, or those issued by the currently logged-in doctor. Patients may restrict the list to prescriptions which can currently be dispensed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "List prescriptions",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only list active prescriptions (patients only)",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved prescriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Prescription"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve prescriptions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issues a prescription for a drug from the drug dictionary. The drug is checked against the patient's active prescriptions, recorded allergies and pharmacogenomic results; severe warnings must be overridden with a reason. Only doctors allowed to see the patient's prescriptions, health records and genomic data may issue prescriptions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Issue prescription",
+                "parameters": [
+                    {
+                        "description": "Prescription details",
+                        "name": "prescription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PrescriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully issued prescription",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload, drug, patient or validity period",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions or access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Patient or encounter not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - Severe interaction requires an override reason",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.InteractionConflictResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to issue prescription",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/prescriptions/check": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Checks a drug against the patient's active prescriptions, recorded allergies and pharmacogenomic results without issuing a prescription. Warnings are ordered from the most severe. The doctor must be allowed to see the patient's prescriptions, health records and genomic data.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Check drug interactions",
+                "parameters": [
+                    {
+                        "description": "Patient and drug",
+                        "name": "check",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.InteractionCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Interaction warnings",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.InteractionWarning"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or drug",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions or access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to check interactions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/prescriptions/dispense": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records a fill of the prescription with the given verification code. The prescription becomes dispensed once all refills have been used.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Dispense prescription",
+                "parameters": [
+                    {
+                        "description": "Verification code",
+                        "name": "dispense",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DispenseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully dispensed prescription",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Prescription not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - Prescription is not active",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to dispense prescription",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/prescriptions/verify/{code}": {
+            "get": {
+                "description": "Confirms that a printed prescription is genuine and shows whether it can still be dispensed. The patient is not disclosed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Verify prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Verification code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prescription found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PrescriptionVerification"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Prescription not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to verify prescription",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/prescriptions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a prescription of the currently logged-in patient, issued by the currently logged-in doctor or of a patient whose prescriptions the user may see, including under emergency access, along with the current pharmacogenomic recommendations for the drug.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Get prescription by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prescription ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved prescription",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Prescription not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve prescription",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/prescriptions/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels a prescription which has not been fully dispensed or expired yet. Only the issuing doctor and admins may cancel prescriptions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Cancel prescription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prescription ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully cancelled prescription",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prescription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Prescription not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - Prescription is not active",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to cancel prescription",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/prescriptions/{id}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renders a printable prescription including its verification code.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Prescriptions"
+                ],
+                "summary": "Download prescription PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prescription ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prescription PDF",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Prescription not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to render prescription",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/reminders/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves which appointment reminders are sent to the currently logged-in user. Users who never changed their preferences get all reminders.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reminders"
+                ],
+                "summary": "Get reminder preferences",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved reminder preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReminderPreference"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve reminder preferences",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enables or disables the 24 hours and 1 hour appointment reminders for the currently logged-in user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reminders"
+                ],
+                "summary": "Update reminder preferences",
+                "parameters": [
+                    {
+                        "description": "Reminder preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReminderPreference"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated reminder preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReminderPreference"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to update reminder preferences",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/slots": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists free slots starting within the given time range, optionally narrowed down to a doctor and an organization. The range defaults to the next 14 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Appointments"
+                ],
+                "summary": "List free slots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Doctor ID (UUIDv4 format)",
+                        "name": "doctor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID (UUIDv4 format)",
+                        "name": "organization_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the range (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the range (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved free slots",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Slot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve slots",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publishes a bookable slot at an organization. Doctors create slots for themselves, admins have to specify the doctor.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Appointments"
+                ],
+                "summary": "Create slot",
+                "parameters": [
+                    {
+                        "description": "Slot details",
+                        "name": "slot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SlotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created slot",
+                        "schema": {
+                            "$ref": "#/definitions/models.Slot"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or time range",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Organization not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to create slot",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/slots/{id}/book": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Books a free slot for the currently logged-in patient. Patients with a home address get estimates of the travel to the organization by every mode the geo service offers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Appointments"
+                ],
+                "summary": "Book slot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slot ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully booked appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Slot not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - Slot is no longer available",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to book slot",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/waitlist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists all waitlist entries of the currently logged-in patient.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "List waitlist entries",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved waitlist entries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WaitlistEntry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to retrieve waitlist entries",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registers interest in a doctor and/or organization within a date range. When a matching slot is freed, it is held for the longest waiting patient and offered by mail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Join waitlist",
+                "parameters": [
+                    {
+                        "description": "Waitlist entry details",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.WaitlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully joined waitlist",
+                        "schema": {
+                            "$ref": "#/definitions/models.WaitlistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid request payload or time range",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Insufficient permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to join waitlist",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/waitlist/offers/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Books the slot held for the currently logged-in patient, provided the offer has not expired.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Accept waitlist offer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Offer ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully booked appointment",
+                        "schema": {
+                            "$ref": "#/definitions/models.Appointment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Offer not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone - Offer has expired",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to accept offer",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/waitlist/offers/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Releases the slot held for the currently logged-in patient, who stays on the waitlist. The slot is offered to the next patient in line.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Decline waitlist offer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Offer ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully declined offer"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Offer not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to decline offer",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/visit/waitlist/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraws a waitlist entry of the currently logged-in patient. A slot held for the entry is released.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Waitlist"
+                ],
+                "summary": "Withdraw from waitlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Waitlist entry ID (UUIDv4 format)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successfully withdrawn"
+                    },
+                    "400": {
+                        "description": "Bad Request - Invalid UUID format",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - Invalid or missing token",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found - Waitlist entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error - Failed to withdraw from waitlist",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dtos.Address": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Boston"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "United States"
+                },
+                "country_code": {
+                    "type": "string",
+                    "example": "us"
+                },
+                "county": {
+                    "type": "string",
+                    "example": "Suffolk County"
+                },
+                "house_number": {
+                    "type": "string",
+                    "example": "55"
+                },
+                "postcode": {
+                    "type": "string",
+                    "example": "02114"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Fruit Street"
+                }
+            }
+        },
+        "dtos.AllergyRequest": {
+            "type": "object",
+            "required": [
+                "severity",
+                "substance"
+            ],
+            "properties": {
+                "encounter_id": {
+                    "description": "EncounterID links the entry to the encounter it was recorded\nduring.",
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "reaction": {
+                    "type": "string",
+                    "example": "Hives"
+                },
+                "severity": {
+                    "enum": [
+                        "minor",
+                        "moderate",
+                        "severe"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.InteractionSeverity"
+                        }
+                    ],
+                    "example": "moderate"
+                },
+                "substance": {
+                    "type": "string",
+                    "example": "penicillin"
+                }
+            }
+        },
+        "dtos.CareAssignmentRequest": {
+            "type": "object",
+            "required": [
+                "doctor_id",
+                "organization_id"
+            ],
+            "properties": {
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "role": {
+                    "description": "Role defaults to member.",
+                    "enum": [
+                        "primary",
+                        "member"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CareTeamRole"
+                        }
+                    ],
+                    "example": "primary"
+                }
+            }
+        },
+        "dtos.ConsentRequest": {
+            "type": "object",
+            "required": [
+                "categories",
+                "grantee_id",
+                "grantee_type"
+            ],
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.ConsentCategory"
+                    },
+                    "example": [
+                        "records",
+                        "lab_results"
+                    ]
+                },
+                "decision": {
+                    "description": "Decision defaults to permit.",
+                    "enum": [
+                        "permit",
+                        "deny"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ConsentDecision"
+                        }
+                    ],
+                    "example": "permit"
+                },
+                "grantee_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "grantee_type": {
+                    "enum": [
+                        "doctor",
+                        "organization"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GranteeType"
+                        }
+                    ],
+                    "example": "doctor"
+                },
+                "valid_from": {
+                    "description": "ValidFrom defaults to the time the consent is given.",
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "valid_until": {
+                    "description": "ValidUntil is left out for consents given until revoked.",
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                }
+            }
+        },
+        "dtos.ConversationRequest": {
+            "type": "object",
+            "required": [
+                "message",
+                "participant_id",
+                "subject"
+            ],
+            "properties": {
+                "appointment_id": {
+                    "description": "AppointmentID ties the conversation to an appointment of the\nparticipants. Without it, the doctor must be on the patient's care\nteam.",
+                    "type": "string",
+                    "example": "5d8c7a8e-3b1f-4e2a-9c6d-8f7e6d5c4b3a"
+                },
+                "message": {
+                    "$ref": "#/definitions/dtos.MessageRequest"
+                },
+                "participant_id": {
+                    "description": "ParticipantID is the doctor the patient writes to, or the patient the\ndoctor writes to.",
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Blood pressure readings"
+                }
+            }
+        },
+        "dtos.DelegationRequest": {
+            "type": "object",
+            "required": [
+                "doctor_id"
+            ],
+            "properties": {
+                "doctor_id": {
+                    "type": "string",
+                    "example": "b8f5a9e2-3c1d-4f7a-9e6b-2d4c8a1f3e5b"
+                }
+            }
+        },
+        "dtos.DiagnosisRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "G44.209"
+                },
+                "primary": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "dtos.DispenseRequest": {
+            "type": "object",
+            "required": [
+                "verification_code"
+            ],
+            "properties": {
+                "verification_code": {
+                    "type": "string",
+                    "example": "7K2M-QX4P-9TBR"
+                }
+            }
+        },
+        "dtos.DoctorSpecialtiesRequest": {
+            "type": "object",
+            "required": [
+                "specialties"
+            ],
+            "properties": {
+                "specialties": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "cardiology",
+                        "internal medicine"
+                    ]
+                }
+            }
+        },
+        "dtos.DocumentLink": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:12:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/visit/documents/9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a/content?expires=1748859120\u0026role=patient\u0026signature=Jq3v...\u0026user=0b6f13da-efb9-4221-9e89-e2729ae90030"
+                }
+            }
+        },
+        "dtos.EmergencyAccessGrant": {
+            "type": "object",
+            "properties": {
+                "access": {
+                    "$ref": "#/definitions/models.EmergencyAccess"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "dtos.EmergencyAccessRequest": {
+            "type": "object",
+            "required": [
+                "justification",
+                "patient_id"
+            ],
+            "properties": {
+                "justification": {
+                    "description": "Justification explains why the access cannot wait. It is shown to\nthe patient and the reviewing admin.",
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Patient unconscious in the emergency room, allergies unknown"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                }
+            }
+        },
+        "dtos.EmergencyReviewRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Confirmed with the emergency room"
+                },
+                "status": {
+                    "enum": [
+                        "approved",
+                        "flagged"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EmergencyReviewStatus"
+                        }
+                    ],
+                    "example": "approved"
+                }
+            }
+        },
+        "dtos.EncounterAmendmentRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "assessment": {
+                    "type": "string",
+                    "example": "Tension-type headache."
+                },
+                "diagnoses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DiagnosisRequest"
+                    }
+                },
+                "objective": {
+                    "type": "string",
+                    "example": "Alert and oriented, no focal neurological deficits."
+                },
+                "plan": {
+                    "type": "string",
+                    "example": "Ibuprofen as needed, follow up in two weeks."
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Corrected blood pressure reading"
+                },
+                "subjective": {
+                    "type": "string",
+                    "example": "Headache for three days, worse in the morning."
+                },
+                "vitals": {
+                    "$ref": "#/definitions/models.Vitals"
+                }
+            }
+        },
+        "dtos.EncounterNotesRequest": {
+            "type": "object",
+            "properties": {
+                "assessment": {
+                    "type": "string",
+                    "example": "Tension-type headache."
+                },
+                "diagnoses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DiagnosisRequest"
+                    }
+                },
+                "objective": {
+                    "type": "string",
+                    "example": "Alert and oriented, no focal neurological deficits."
+                },
+                "plan": {
+                    "type": "string",
+                    "example": "Ibuprofen as needed, follow up in two weeks."
+                },
+                "subjective": {
+                    "type": "string",
+                    "example": "Headache for three days, worse in the morning."
+                },
+                "vitals": {
+                    "$ref": "#/definitions/models.Vitals"
+                }
+            }
+        },
+        "dtos.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Specific error message"
+                }
+            }
+        },
+        "dtos.HomeAddressRequest": {
+            "type": "object",
+            "required": [
+                "address"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "10 Brattle Street, Cambridge"
+                }
+            }
+        },
+        "dtos.InteractionCheckRequest": {
+            "type": "object",
+            "required": [
+                "drug_id",
+                "patient_id"
+            ],
+            "properties": {
+                "drug_id": {
+                    "type": "string",
+                    "example": "ibuprofen-400-tab"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                }
+            }
+        },
+        "dtos.InteractionConflictResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Severe interaction requires an override reason"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InteractionWarning"
+                    }
+                }
+            }
+        },
+        "dtos.LabImportIssue": {
+            "type": "object",
+            "properties": {
+                "control_id": {
+                    "type": "string",
+                    "example": "MSG00001"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Unsupported unit 'mmol/mol' for 4548-4"
+                }
+            }
+        },
+        "dtos.LabImportResult": {
+            "type": "object",
+            "properties": {
+                "duplicates": {
+                    "description": "Duplicates counts results which had already been imported.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "messages": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rejected": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.LabImportIssue"
+                    }
+                }
+            }
+        },
+        "dtos.LabResultRequest": {
+            "type": "object",
+            "required": [
+                "loinc_code",
+                "observed_at",
+                "unit",
+                "value"
+            ],
+            "properties": {
+                "loinc_code": {
+                    "type": "string",
+                    "example": "2345-7"
+                },
+                "name": {
+                    "description": "Name is taken from the lab dictionary for known analytes and\ndefaults to the code for others.",
+                    "type": "string",
+                    "example": "Glucose"
+                },
+                "observed_at": {
+                    "type": "string",
+                    "example": "2025-06-02T08:00:00Z"
+                },
+                "reference_high": {
+                    "type": "number",
+                    "example": 99
+                },
+                "reference_low": {
+                    "description": "ReferenceLow and ReferenceHigh are in Unit. They default to the\ndictionary range of the analyte.",
+                    "type": "number",
+                    "example": 70
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "mg/dL"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 101
+                }
+            }
+        },
+        "dtos.Location": {
+            "type": "object",
+            "required": [
+                "display_name",
+                "lat",
+                "lon",
+                "osm_id"
+            ],
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/dtos.Address"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "135, Pilkington Avenue, Maney, Sutton Coldfield, Birmingham, West Midlands, England, B72 1LH, United Kingdom"
+                },
+                "lat": {
+                    "type": "string",
+                    "example": "52.5487921"
+                },
+                "lon": {
+                    "type": "string",
+                    "example": "-1.8164308"
+                },
+                "osm_id": {
+                    "type": "integer",
+                    "example": 90394480
+                },
+                "osm_type": {
+                    "enum": [
+                        "N",
+                        "W",
+                        "R"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dtos.OsmType"
+                        }
+                    ],
+                    "example": "W"
+                }
+            }
+        },
+        "dtos.MessageRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 5000,
+                    "example": "My readings from this week are attached."
+                },
+                "document_id": {
+                    "description": "DocumentID attaches one of the patient's documents, uploaded\nbeforehand.",
+                    "type": "string",
+                    "example": "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"
+                }
+            }
+        },
+        "dtos.OsmType": {
+            "type": "string",
+            "enum": [
+                "N",
+                "W",
+                "R"
+            ],
+            "x-enum-varnames": [
+                "OsmNode",
+                "OsmWay",
+                "OsmRelation"
+            ]
+        },
+        "dtos.PrescriptionRequest": {
+            "type": "object",
+            "required": [
+                "dosage",
+                "drug_id",
+                "patient_id",
+                "quantity",
+                "valid_until"
+            ],
+            "properties": {
+                "dosage": {
+                    "type": "string",
+                    "example": "1 tablet once daily in the evening"
+                },
+                "drug_id": {
+                    "type": "string",
+                    "example": "atorvastatin-20-tab"
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "override_reason": {
+                    "description": "OverrideReason is required when the drug has a severe interaction\nwith the patient's medication or allergies.",
+                    "type": "string",
+                    "example": "Benefit outweighs the bleeding risk; INR monitored weekly"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 30
+                },
+                "refills": {
+                    "type": "integer",
+                    "maximum": 11,
+                    "minimum": 0,
+                    "example": 2
+                },
+                "valid_from": {
+                    "description": "ValidFrom defaults to the time of issuance.",
+                    "type": "string",
+                    "example": "2025-06-02T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2025-09-02T00:00:00Z"
+                }
+            }
+        },
+        "dtos.PrescriptionVerification": {
+            "type": "object",
+            "properties": {
+                "dosage": {
+                    "type": "string",
+                    "example": "1 tablet once daily in the evening"
+                },
+                "drug_name": {
+                    "type": "string",
+                    "example": "Atorvastatin"
+                },
+                "fills": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "form": {
+                    "type": "string",
+                    "example": "tablet"
+                },
+                "issued_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:15:00Z"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "refills": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PrescriptionStatus"
+                        }
+                    ],
+                    "example": "issued"
+                },
+                "strength": {
+                    "type": "string",
+                    "example": "20 mg"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2025-06-02T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2025-09-02T00:00:00Z"
+                },
+                "verification_code": {
+                    "type": "string",
+                    "example": "7K2M-QX4P-9TBR"
+                }
+            }
+        },
+        "dtos.RouteEstimate": {
+            "type": "object",
+            "properties": {
+                "distance_meters": {
+                    "type": "number",
+                    "example": 5364.2
+                },
+                "duration_seconds": {
+                    "type": "number",
+                    "example": 712.5
+                },
+                "mode": {
+                    "enum": [
+                        "driving",
+                        "walking",
+                        "cycling"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dtos.TravelMode"
+                        }
+                    ],
+                    "example": "driving"
+                }
+            }
+        },
+        "dtos.SlotRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "organization_id",
+                "starts_at"
+            ],
+            "properties": {
+                "doctor_id": {
+                    "description": "Only admins may create slots on behalf of other doctors. Doctors\nalways create slots for themselves.",
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:00:00Z"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-06-02T09:30:00Z"
+                }
+            }
+        },
+        "dtos.TravelMode": {
+            "type": "string",
+            "enum": [
+                "driving",
+                "walking",
+                "cycling"
+            ],
+            "x-enum-varnames": [
+                "Driving",
+                "Walking",
+                "Cycling"
+            ]
+        },
+        "dtos.UnreadCount": {
+            "type": "object",
+            "properties": {
+                "unread": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "dtos.WaitlistRequest": {
+            "type": "object",
+            "required": [
+                "from",
+                "to"
+            ],
+            "properties": {
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-06-14T00:00:00Z"
+                }
+            }
+        },
+        "fhir.Bundle": {
+            "type": "object",
+            "properties": {
+                "entry": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.BundleEntry"
+                    }
+                },
+                "link": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.BundleLink"
+                    }
+                },
+                "resourceType": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.BundleEntry": {
+            "type": "object",
+            "properties": {
+                "fullUrl": {
+                    "type": "string"
+                },
+                "resource": {},
+                "search": {
+                    "$ref": "#/definitions/fhir.BundleEntrySearch"
+                }
+            }
+        },
+        "fhir.BundleEntrySearch": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.BundleLink": {
+            "type": "object",
+            "properties": {
+                "relation": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.CapabilityImplementation": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.CapabilityInteraction": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.CapabilityOperation": {
+            "type": "object",
+            "properties": {
+                "definition": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.CapabilityResource": {
+            "type": "object",
+            "properties": {
+                "interaction": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.CapabilityInteraction"
+                    }
+                },
+                "operation": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.CapabilityOperation"
+                    }
+                },
+                "profile": {
+                    "type": "string"
+                },
+                "searchParam": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.SearchParam"
+                    }
+                },
+                "searchRevInclude": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.CapabilityRest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "type": "string"
+                },
+                "resource": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.CapabilityResource"
+                    }
+                },
+                "security": {
+                    "$ref": "#/definitions/fhir.CapabilitySecurity"
+                }
+            }
+        },
+        "fhir.CapabilitySecurity": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.CodeableConcept"
+                    }
+                }
+            }
+        },
+        "fhir.CapabilitySoftware": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.CapabilityStatement": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "fhirVersion": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "implementation": {
+                    "$ref": "#/definitions/fhir.CapabilityImplementation"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
+                },
+                "rest": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.CapabilityRest"
+                    }
+                },
+                "software": {
+                    "$ref": "#/definitions/fhir.CapabilitySoftware"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.CodeableConcept": {
+            "type": "object",
+            "properties": {
+                "coding": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.Coding"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.Coding": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "display": {
+                    "type": "string"
+                },
+                "system": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.OperationOutcome": {
+            "type": "object",
+            "properties": {
+                "issue": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fhir.OperationOutcomeIssue"
+                    }
+                },
+                "resourceType": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.OperationOutcomeIssue": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "diagnostics": {
+                    "type": "string"
+                },
+                "expression": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "severity": {
+                    "type": "string"
+                }
+            }
+        },
+        "fhir.SearchParam": {
+            "type": "object",
+            "properties": {
+                "documentation": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Allergy": {
+            "type": "object",
+            "properties": {
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "reaction": {
+                    "type": "string",
+                    "example": "Hives"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2025-01-15T09:00:00Z"
+                },
+                "recorded_by": {
+                    "description": "RecordedBy is the patient for self-reported entries, otherwise the\ndoctor who recorded the entry.",
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "severity": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.InteractionSeverity"
+                        }
+                    ],
+                    "example": "moderate"
+                },
+                "substance": {
+                    "description": "Substance is either a drug name or an allergen class such as\n` + "`" + `penicillin` + "`" + ` or ` + "`" + `nsaid` + "`" + `.",
+                    "type": "string",
+                    "example": "penicillin"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-02-01T11:30:00Z"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                }
+            }
+        },
+        "models.Appointment": {
+            "type": "object",
+            "properties": {
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "slot_id": {
+                    "type": "string",
+                    "example": "3f1e2d4c-5b6a-4978-8c9d-0e1f2a3b4c5d"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-06-02T09:30:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AppointmentStatus"
+                        }
+                    ],
+                    "example": "booked"
+                },
+                "travel_estimates": {
+                    "description": "TravelEstimates estimate the travel from the home address of the\npatient to the organization. They are given in booking\nconfirmations only, and only to patients with a home address.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.RouteEstimate"
+                    }
+                }
+            }
+        },
+        "models.AppointmentStatus": {
+            "type": "string",
+            "enum": [
+                "booked",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "Booked",
+                "Cancelled"
+            ]
+        },
+        "models.CareAssignment": {
+            "type": "object",
+            "properties": {
+                "assigned_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:00:00Z"
+                },
+                "assigned_by": {
+                    "description": "AssignedBy is the admin who made a manual assignment.",
+                    "type": "string",
+                    "example": "7f8e9d0c-1b2a-4c3d-9e4f-5a6b7c8d9e0f"
+                },
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "ended_at": {
+                    "type": "string",
+                    "example": "2025-09-01T09:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6d7e8f9a-0b1c-4d2e-8f3a-4b5c6d7e8f9a"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CareTeamRole"
+                        }
+                    ],
+                    "example": "member"
+                },
+                "source": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CareAssignmentSource"
+                        }
+                    ],
+                    "example": "appointment"
+                }
+            }
+        },
+        "models.CareAssignmentSource": {
+            "type": "string",
+            "enum": [
+                "appointment",
+                "manual"
+            ],
+            "x-enum-varnames": [
+                "AppointmentSource",
+                "ManualSource"
+            ]
+        },
+        "models.CareTeamRole": {
+            "type": "string",
+            "enum": [
+                "primary",
+                "member"
+            ],
+            "x-enum-varnames": [
+                "PrimaryDoctor",
+                "CareTeamMember"
+            ]
+        },
+        "models.ChronicCondition": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "I10"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Essential (primary) hypertension"
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Controlled with medication"
+                },
+                "onset_date": {
+                    "type": "string",
+                    "example": "2019-04-01T00:00:00Z"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2025-01-15T09:00:00Z"
+                },
+                "recorded_by": {
+                    "description": "RecordedBy is the patient for self-reported entries, otherwise the\ndoctor who recorded the entry.",
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ConditionStatus"
+                        }
+                    ],
+                    "example": "active"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-02-01T11:30:00Z"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                }
+            }
+        },
+        "models.ConditionStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "resolved"
+            ],
+            "x-enum-varnames": [
+                "ConditionActive",
+                "ConditionResolved"
+            ]
+        },
+        "models.Consent": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConsentCategory"
+                    },
+                    "example": [
+                        "records",
+                        "lab_results"
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:00:00Z"
+                },
+                "decision": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ConsentDecision"
+                        }
+                    ],
+                    "example": "permit"
+                },
+                "grantee_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "grantee_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GranteeType"
+                        }
+                    ],
+                    "example": "doctor"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2025-07-01T09:00:00Z"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "valid_until": {
+                    "description": "ValidUntil is nil for consents given until revoked.",
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                }
+            }
+        },
+        "models.ConsentAction": {
+            "type": "string",
+            "enum": [
+                "granted",
+                "revoked"
+            ],
+            "x-enum-varnames": [
+                "ConsentGranted",
+                "ConsentRevoked"
+            ]
+        },
+        "models.ConsentCategory": {
+            "type": "string",
+            "enum": [
+                "records",
+                "genomics",
+                "lab_results",
+                "documents",
+                "encounters",
+                "prescriptions"
+            ],
+            "x-enum-varnames": [
+                "RecordsCategory",
+                "GenomicsCategory",
+                "LabResultsCategory",
+                "DocumentsCategory",
+                "EncountersCategory",
+                "PrescriptionsCategory"
+            ]
+        },
+        "models.ConsentDecision": {
+            "type": "string",
+            "enum": [
+                "permit",
+                "deny"
+            ],
+            "x-enum-varnames": [
+                "Permit",
+                "Deny"
+            ]
+        },
+        "models.ConsentEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ConsentAction"
+                        }
+                    ],
+                    "example": "granted"
+                },
+                "actor_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConsentCategory"
+                    },
+                    "example": [
+                        "records",
+                        "lab_results"
+                    ]
+                },
+                "consent_id": {
+                    "type": "string",
+                    "example": "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+                },
+                "decision": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ConsentDecision"
+                        }
+                    ],
+                    "example": "permit"
+                },
+                "grantee_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "grantee_type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.GranteeType"
+                        }
+                    ],
+                    "example": "doctor"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:00:00Z"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                }
+            }
+        },
+        "models.Conversation": {
+            "type": "object",
+            "properties": {
+                "appointment_id": {
+                    "description": "AppointmentID is set for conversations about an appointment.",
+                    "type": "string",
+                    "example": "5d8c7a8e-3b1f-4e2a-9c6d-8f7e6d5c4b3a"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:00:00Z"
+                },
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "last_message_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:30:00Z"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "Blood pressure readings"
+                },
+                "unread": {
+                    "description": "Unread is the number of messages the user listing the conversations\nhas not read yet.",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.Diagnosis": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "G44.209"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Tension-type headache, unspecified, not intractable"
+                },
+                "primary": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.Document": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DocumentCategory"
+                        }
+                    ],
+                    "example": "referral"
+                },
+                "checksum": {
+                    "description": "Checksum is the hex-encoded SHA-256 of the content.",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:07:00Z"
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "file_name": {
+                    "type": "string",
+                    "example": "referral.pdf"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 48213
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Cardiology referral"
+                },
+                "uploaded_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                }
+            }
+        },
+        "models.DocumentCategory": {
+            "type": "string",
+            "enum": [
+                "referral",
+                "scan",
+                "lab_report",
+                "imaging",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "ReferralDocument",
+                "ScanDocument",
+                "LabReportDocument",
+                "ImagingDocument",
+                "OtherDocument"
+            ]
+        },
+        "models.Drug": {
+            "type": "object",
+            "properties": {
+                "atc_code": {
+                    "type": "string",
+                    "example": "C10AA05"
+                },
+                "form": {
+                    "type": "string",
+                    "example": "tablet"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "atorvastatin-20-tab"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Atorvastatin"
+                },
+                "strength": {
+                    "type": "string",
+                    "example": "20 mg"
+                }
+            }
+        },
+        "models.EmergencyAccess": {
+            "type": "object",
+            "properties": {
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-06-01T10:00:00Z"
+                },
+                "granted_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "8f9a0b1c-2d3e-4f5a-9b6c-7d8e9f0a1b2c"
+                },
+                "justification": {
+                    "type": "string",
+                    "example": "Patient unconscious in the emergency room, allergies unknown"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "review_note": {
+                    "type": "string",
+                    "example": "Confirmed with the emergency room"
+                },
+                "reviewed_at": {
+                    "type": "string",
+                    "example": "2025-06-02T09:00:00Z"
+                },
+                "reviewed_by": {
+                    "type": "string",
+                    "example": "99ab51c4-a544-4352-a8df-4632ff8b105d"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:30:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EmergencyReviewStatus"
+                        }
+                    ],
+                    "example": "pending"
+                }
+            }
+        },
+        "models.EmergencyAccessEvent": {
+            "type": "object",
+            "properties": {
+                "access_id": {
+                    "type": "string",
+                    "example": "8f9a0b1c-2d3e-4f5a-9b6c-7d8e9f0a1b2c"
+                },
+                "accessed_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:05:00Z"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConsentCategory"
+                    },
+                    "example": [
+                        "records",
+                        "lab_results"
+                    ]
+                },
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9a0b1c2d-3e4f-4a5b-8c6d-7e8f9a0b1c2d"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                }
+            }
+        },
+        "models.EmergencyReviewStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "flagged"
+            ],
+            "x-enum-varnames": [
+                "PendingReview",
+                "ApprovedReview",
+                "FlaggedReview"
+            ]
+        },
+        "models.Encounter": {
+            "type": "object",
+            "properties": {
+                "appointment_id": {
+                    "type": "string",
+                    "example": "5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a"
+                },
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EncounterAttachment"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:05:00Z"
+                },
+                "delegations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EncounterDelegation"
+                    }
+                },
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EncounterRevision"
+                    }
+                }
+            }
+        },
+        "models.EncounterAttachment": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "Checksum is the hex-encoded SHA-256 of the content.",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:07:00Z"
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "file_name": {
+                    "type": "string",
+                    "example": "ecg.pdf"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 48213
+                },
+                "uploaded_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                }
+            }
+        },
+        "models.EncounterDelegation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:10:00Z"
+                },
+                "doctor_id": {
+                    "type": "string",
+                    "example": "b8f5a9e2-3c1d-4f7a-9e6b-2d4c8a1f3e5b"
+                },
+                "granted_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                }
+            }
+        },
+        "models.EncounterRevision": {
+            "type": "object",
+            "properties": {
+                "assessment": {
+                    "type": "string",
+                    "example": "Tension-type headache."
+                },
+                "author_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:05:00Z"
+                },
+                "diagnoses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Diagnosis"
+                    }
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "2e3f4a5b-6c7d-4e8f-9a0b-1c2d3e4f5a6b"
+                },
+                "objective": {
+                    "type": "string",
+                    "example": "Alert and oriented, no focal neurological deficits."
+                },
+                "plan": {
+                    "type": "string",
+                    "example": "Ibuprofen as needed, follow up in two weeks."
+                },
+                "reason": {
+                    "description": "Reason explains an amendment. It is empty for the original record.",
+                    "type": "string",
+                    "example": "Corrected blood pressure reading"
+                },
+                "subjective": {
+                    "type": "string",
+                    "example": "Headache for three days, worse in the morning."
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "vitals": {
+                    "$ref": "#/definitions/models.Vitals"
+                }
+            }
+        },
+        "models.Genotype": {
+            "type": "object",
+            "properties": {
+                "diplotype": {
+                    "type": "string",
+                    "example": "*4/*4"
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "gene": {
+                    "type": "string",
+                    "example": "CYP2D6"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"
+                },
+                "laboratory": {
+                    "type": "string",
+                    "example": "MGH Molecular Diagnostics"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "phenotype": {
+                    "type": "string",
+                    "example": "poor metabolizer"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2025-01-15T09:00:00Z"
+                },
+                "recorded_by": {
+                    "description": "RecordedBy is the patient for self-reported entries, otherwise the\ndoctor who recorded the entry.",
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "tested_on": {
+                    "type": "string",
+                    "example": "2024-11-20T00:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-02-01T11:30:00Z"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                }
+            }
+        },
+        "models.GranteeType": {
+            "type": "string",
+            "enum": [
+                "doctor",
+                "organization"
+            ],
+            "x-enum-varnames": [
+                "DoctorGrantee",
+                "OrganizationGrantee"
+            ]
+        },
+        "models.HomeAddress": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "10 Brattle Street, Cambridge"
+                },
+                "display_name": {
+                    "type": "string",
+                    "example": "10, Brattle Street, Harvard Square, Cambridge, Middlesex County, Massachusetts, 02138, United States"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 42.3736158
+                },
+                "lon": {
+                    "type": "number",
+                    "example": -71.1097335
+                }
+            }
+        },
+        "models.ICD10Code": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "G44.209"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Tension-type headache, unspecified, not intractable"
+                }
+            }
+        },
+        "models.Immunization": {
+            "type": "object",
+            "properties": {
+                "administered_on": {
+                    "type": "string",
+                    "example": "2024-10-03T00:00:00Z"
+                },
+                "dose_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"
+                },
+                "lot_number": {
+                    "type": "string",
+                    "example": "AB1234"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2025-01-15T09:00:00Z"
+                },
+                "recorded_by": {
+                    "description": "RecordedBy is the patient for self-reported entries, otherwise the\ndoctor who recorded the entry.",
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-02-01T11:30:00Z"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "vaccine": {
+                    "type": "string",
+                    "example": "Tetanus, diphtheria (Td)"
+                }
+            }
+        },
+        "models.InteractionSeverity": {
+            "type": "string",
+            "enum": [
+                "minor",
+                "moderate",
+                "severe"
+            ],
+            "x-enum-varnames": [
+                "Minor",
+                "Moderate",
+                "Severe"
+            ]
+        },
+        "models.InteractionWarning": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "NSAIDs increase the risk of serious bleeding in patients on warfarin."
+                },
+                "kind": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WarningKind"
+                        }
+                    ],
+                    "example": "drug_interaction"
+                },
+                "severity": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.InteractionSeverity"
+                        }
+                    ],
+                    "example": "severe"
+                },
+                "substance": {
+                    "type": "string",
+                    "example": "Warfarin"
+                }
+            }
+        },
+        "models.LabFlag": {
+            "type": "string",
+            "enum": [
+                "N",
+                "L",
+                "H",
+                "LL",
+                "HH"
+            ],
+            "x-enum-varnames": [
+                "LabNormal",
+                "LabLow",
+                "LabHigh",
+                "LabCriticalLow",
+                "LabCriticalHigh"
+            ]
+        },
+        "models.LabPoint": {
+            "type": "object",
+            "properties": {
+                "flag": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LabFlag"
+                        }
+                    ],
+                    "example": "H"
+                },
+                "observed_at": {
+                    "type": "string",
+                    "example": "2025-06-02T08:00:00Z"
+                },
+                "result_id": {
+                    "type": "string",
+                    "example": "6f7a8b9c-0d1e-4f2a-9b3c-4d5e6f7a8b9c"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 5.6
+                }
+            }
+        },
+        "models.LabResult": {
+            "type": "object",
+            "properties": {
+                "flag": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LabFlag"
+                        }
+                    ],
+                    "example": "H"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6f7a8b9c-0d1e-4f2a-9b3c-4d5e6f7a8b9c"
+                },
+                "loinc_code": {
+                    "type": "string",
+                    "example": "2345-7"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Glucose"
+                },
+                "observed_at": {
+                    "type": "string",
+                    "example": "2025-06-02T08:00:00Z"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:05:00Z"
+                },
+                "recorded_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "reference_high": {
+                    "type": "number",
+                    "example": 5.5
+                },
+                "reference_low": {
+                    "description": "ReferenceLow and ReferenceHigh bound the normal range, in Unit. Either\nmay be open.",
+                    "type": "number",
+                    "example": 3.9
+                },
+                "reported_unit": {
+                    "type": "string",
+                    "example": "mg/dL"
+                },
+                "reported_value": {
+                    "type": "number",
+                    "example": 101
+                },
+                "source": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LabSource"
+                        }
+                    ],
+                    "example": "hl7"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "mmol/L"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 5.6
+                }
+            }
+        },
+        "models.LabSeries": {
+            "type": "object",
+            "properties": {
+                "loinc_code": {
+                    "type": "string",
+                    "example": "2345-7"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Glucose"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LabPoint"
+                    }
+                },
+                "reference_high": {
+                    "type": "number",
+                    "example": 5.5
+                },
+                "reference_low": {
+                    "type": "number",
+                    "example": 3.9
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "mmol/L"
+                }
+            }
+        },
+        "models.LabSource": {
+            "type": "string",
+            "enum": [
+                "manual",
+                "hl7"
+            ],
+            "x-enum-varnames": [
+                "LabSourceManual",
+                "LabSourceHL7"
+            ]
+        },
+        "models.MedicationStatement": {
+            "type": "object",
+            "properties": {
+                "dosage": {
+                    "type": "string",
+                    "example": "500 mg twice daily"
+                },
+                "drug_name": {
+                    "type": "string",
+                    "example": "Metformin"
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "ended_on": {
+                    "type": "string",
+                    "example": "2023-03-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2025-01-15T09:00:00Z"
+                },
+                "recorded_by": {
+                    "description": "RecordedBy is the patient for self-reported entries, otherwise the\ndoctor who recorded the entry.",
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "started_on": {
+                    "type": "string",
+                    "example": "2021-03-01T00:00:00Z"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-02-01T11:30:00Z"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                }
+            }
+        },
+        "models.Message": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "My readings from this week are attached."
+                },
+                "conversation_id": {
+                    "type": "string",
+                    "example": "0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "document_id": {
+                    "type": "string",
+                    "example": "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a"
+                },
+                "read_at": {
+                    "description": "ReadAt is when the recipient read the message.",
+                    "type": "string",
+                    "example": "2025-06-01T10:00:00Z"
+                },
+                "sender_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "sent_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:30:00Z"
+                }
+            }
+        },
+        "models.NearbyOrganization": {
+            "type": "object",
+            "properties": {
+                "distance_km": {
+                    "type": "number",
+                    "example": 1.27
+                },
+                "id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "location": {
+                    "$ref": "#/definitions/dtos.Location"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "The Lowell General Hospital"
+                }
+            }
+        },
+        "models.Organization": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
                 "location": {
                     "$ref": "#/definitions/dtos.Location"
                 },
@@ -135,6 +7271,355 @@ const docTemplate = `{
                     "example": "The Lowell General Hospital"
                 }
             }
+        },
+        "models.PatientSummary": {
+            "type": "object",
+            "properties": {
+                "active_prescriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Prescription"
+                    }
+                },
+                "allergies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Allergy"
+                    }
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChronicCondition"
+                    }
+                },
+                "genotypes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Genotype"
+                    }
+                },
+                "immunizations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Immunization"
+                    }
+                },
+                "medications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MedicationStatement"
+                    }
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                }
+            }
+        },
+        "models.Prescription": {
+            "type": "object",
+            "properties": {
+                "atc_code": {
+                    "type": "string",
+                    "example": "C10AA05"
+                },
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "dosage": {
+                    "type": "string",
+                    "example": "1 tablet once daily in the evening"
+                },
+                "drug_id": {
+                    "type": "string",
+                    "example": "atorvastatin-20-tab"
+                },
+                "drug_name": {
+                    "type": "string",
+                    "example": "Atorvastatin"
+                },
+                "encounter_id": {
+                    "type": "string",
+                    "example": "7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+                },
+                "fills": {
+                    "description": "Fills counts how many times the prescription has been dispensed.\nIt is fully dispensed once Fills exceeds Refills.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "form": {
+                    "type": "string",
+                    "example": "tablet"
+                },
+                "guidance": {
+                    "description": "Guidance holds the current pharmacogenomic recommendations for the\ndrug. It is not stored and only filled in when a prescription is\nviewed.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InteractionWarning"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6f5e4d3c-2b1a-4c9d-8e7f-6a5b4c3d2e1f"
+                },
+                "issued_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:15:00Z"
+                },
+                "override_reason": {
+                    "description": "OverrideReason is the doctor's justification for prescribing despite\na severe warning.",
+                    "type": "string",
+                    "example": "Benefit outweighs the bleeding risk; INR monitored weekly"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "refills": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PrescriptionStatus"
+                        }
+                    ],
+                    "example": "issued"
+                },
+                "strength": {
+                    "type": "string",
+                    "example": "20 mg"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2025-06-02T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2025-09-02T00:00:00Z"
+                },
+                "verification_code": {
+                    "type": "string",
+                    "example": "7K2M-QX4P-9TBR"
+                },
+                "warnings": {
+                    "description": "Warnings are the interaction and allergy findings shown to the\ndoctor when the prescription was issued.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InteractionWarning"
+                    }
+                }
+            }
+        },
+        "models.PrescriptionStatus": {
+            "type": "string",
+            "enum": [
+                "issued",
+                "dispensed",
+                "cancelled",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "PrescriptionIssued",
+                "PrescriptionDispensed",
+                "PrescriptionCancelled",
+                "PrescriptionExpired"
+            ]
+        },
+        "models.ReminderPreference": {
+            "type": "object",
+            "properties": {
+                "day_before": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "hour_before": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.Slot": {
+            "type": "object",
+            "properties": {
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2025-06-02T10:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3f1e2d4c-5b6a-4978-8c9d-0e1f2a3b4c5d"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-06-02T09:30:00Z"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SlotStatus"
+                        }
+                    ],
+                    "example": "free"
+                }
+            }
+        },
+        "models.SlotStatus": {
+            "type": "string",
+            "enum": [
+                "free",
+                "held",
+                "taken"
+            ],
+            "x-enum-varnames": [
+                "Free",
+                "Held",
+                "Taken"
+            ]
+        },
+        "models.Vitals": {
+            "type": "object",
+            "properties": {
+                "diastolic_bp": {
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 20,
+                    "example": 80
+                },
+                "heart_rate": {
+                    "type": "integer",
+                    "maximum": 300,
+                    "minimum": 20,
+                    "example": 72
+                },
+                "height_cm": {
+                    "type": "number",
+                    "maximum": 300,
+                    "example": 175
+                },
+                "oxygen_saturation": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 50,
+                    "example": 98
+                },
+                "respiratory_rate": {
+                    "type": "integer",
+                    "maximum": 80,
+                    "minimum": 4,
+                    "example": 16
+                },
+                "systolic_bp": {
+                    "type": "integer",
+                    "maximum": 300,
+                    "minimum": 40,
+                    "example": 120
+                },
+                "temperature_c": {
+                    "type": "number",
+                    "maximum": 45,
+                    "minimum": 25,
+                    "example": 36.6
+                },
+                "weight_kg": {
+                    "type": "number",
+                    "maximum": 500,
+                    "example": 70.5
+                }
+            }
+        },
+        "models.WaitlistEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-05-20T12:00:00Z"
+                },
+                "doctor_id": {
+                    "type": "string",
+                    "example": "e2c66717-12bb-4b6a-b7b6-3be939e170ad"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "example": "86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"
+                },
+                "patient_id": {
+                    "type": "string",
+                    "example": "0b6f13da-efb9-4221-9e89-e2729ae90030"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WaitlistEntryStatus"
+                        }
+                    ],
+                    "example": "waiting"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-06-14T00:00:00Z"
+                }
+            }
+        },
+        "models.WaitlistEntryStatus": {
+            "type": "string",
+            "enum": [
+                "waiting",
+                "offered",
+                "fulfilled",
+                "withdrawn"
+            ],
+            "x-enum-varnames": [
+                "Waiting",
+                "Offered",
+                "Fulfilled",
+                "Withdrawn"
+            ]
+        },
+        "models.WarningKind": {
+            "type": "string",
+            "enum": [
+                "drug_interaction",
+                "allergy",
+                "duplicate_therapy",
+                "pharmacogenomic"
+            ],
+            "x-enum-varnames": [
+                "DrugInteraction",
+                "AllergyConflict",
+                "DuplicateTherapy",
+                "Pharmacogenomic"
+            ]
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,25 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+// AppointmentFilter narrows an appointment search. Nil fields match every
+// appointment.
+type AppointmentFilter struct {
+	ID		*uuid.UUID
+	PatientID	*uuid.UUID
+	DoctorID	*uuid.UUID
+	OrganizationID	*uuid.UUID
+	Status		*models.AppointmentStatus
+	// StartsFrom and StartsBefore bound the start time, inclusively and
+	// exclusively.
+	StartsFrom	*time.Time
+	StartsBefore	*time.Time
+	Offset		int
+	Limit		int
+}
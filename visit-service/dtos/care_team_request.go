@@ -0,0 +1,14 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"igaku/visit-service/models"
+)
+
+type CareAssignmentRequest struct {
+	DoctorID	uuid.UUID		`json:"doctor_id" binding:"required" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	OrganizationID	uuid.UUID		`json:"organization_id" binding:"required" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
+	// Role defaults to member.
+	Role		models.CareTeamRole	`json:"role,omitempty" binding:"omitempty,oneof=primary member" example:"primary"`
+}
@@ -0,0 +1,21 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type ConsentRequest struct {
+	GranteeType	models.GranteeType	`json:"grantee_type" binding:"required,oneof=doctor organization" example:"doctor"`
+	GranteeID	uuid.UUID		`json:"grantee_id" binding:"required" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	// Decision defaults to permit.
+	Decision	models.ConsentDecision	`json:"decision,omitempty" binding:"omitempty,oneof=permit deny" example:"permit"`
+	Categories	[]models.ConsentCategory	`json:"categories" binding:"required,min=1" example:"records,lab_results"`
+	// ValidFrom defaults to the time the consent is given.
+	ValidFrom	*time.Time		`json:"valid_from,omitempty" example:"2025-06-01T00:00:00Z"`
+	// ValidUntil is left out for consents given until revoked.
+	ValidUntil	*time.Time		`json:"valid_until,omitempty" example:"2025-12-01T00:00:00Z"`
+}
@@ -0,0 +1,28 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+)
+
+type MessageRequest struct {
+	Body		string		`json:"body" binding:"required,max=5000" example:"My readings from this week are attached."`
+	// DocumentID attaches one of the patient's documents, uploaded
+	// beforehand.
+	DocumentID	*uuid.UUID	`json:"document_id,omitempty" example:"9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"`
+}
+
+type ConversationRequest struct {
+	// ParticipantID is the doctor the patient writes to, or the patient the
+	// doctor writes to.
+	ParticipantID	uuid.UUID	`json:"participant_id" binding:"required" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	// AppointmentID ties the conversation to an appointment of the
+	// participants. Without it, the doctor must be on the patient's care
+	// team.
+	AppointmentID	*uuid.UUID	`json:"appointment_id,omitempty" example:"5d8c7a8e-3b1f-4e2a-9c6d-8f7e6d5c4b3a"`
+	Subject		string		`json:"subject" binding:"required,max=200" example:"Blood pressure readings"`
+	Message		MessageRequest	`json:"message" binding:"required"`
+}
+
+type UnreadCount struct {
+	Unread	int64	`json:"unread" example:"3"`
+}
@@ -0,0 +1,5 @@
+package dtos
+
+type DoctorSpecialtiesRequest struct {
+	Specialties	[]string	`json:"specialties" binding:"required,dive,required" example:"cardiology,internal medicine"`
+}
@@ -0,0 +1,35 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/visit-service/models"
+	commonsModels "igaku/commons/models"
+)
+
+// DocumentUpload holds the form fields sent along with a document.
+type DocumentUpload struct {
+	// Title defaults to the file name.
+	Title		string
+	// Category defaults to other.
+	Category	models.DocumentCategory
+	EncounterID	*uuid.UUID
+	FileName	string
+}
+
+// DocumentLink is a short-lived URL to download a document without a
+// bearer token.
+type DocumentLink struct {
+	URL		string		`json:"url" example:"/visit/documents/9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a/content?expires=1748859120&role=patient&signature=Jq3v...&user=0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	ExpiresAt	time.Time	`json:"expires_at" example:"2025-06-02T10:12:00Z"`
+}
+
+// DocumentLinkParams are the query parameters of a document link.
+type DocumentLinkParams struct {
+	UserID		uuid.UUID
+	Role		commonsModels.Role
+	Expires		int64
+	Signature	string
+}
@@ -0,0 +1,26 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"igaku/visit-service/models"
+)
+
+type EmergencyAccessRequest struct {
+	PatientID	uuid.UUID	`json:"patient_id" binding:"required" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	// Justification explains why the access cannot wait. It is shown to
+	// the patient and the reviewing admin.
+	Justification	string		`json:"justification" binding:"required,max=2000" example:"Patient unconscious in the emergency room, allergies unknown"`
+}
+
+// EmergencyAccessGrant carries the token to use for the emergency access.
+// It is only ever returned once, when the access is granted.
+type EmergencyAccessGrant struct {
+	Access	*models.EmergencyAccess	`json:"access"`
+	Token	string			`json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+type EmergencyReviewRequest struct {
+	Status	models.EmergencyReviewStatus	`json:"status" binding:"required,oneof=approved flagged" example:"approved"`
+	Note	string				`json:"note,omitempty" binding:"max=2000" example:"Confirmed with the emergency room"`
+}
@@ -0,0 +1,32 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"igaku/visit-service/models"
+)
+
+type DiagnosisRequest struct {
+	Code	string	`json:"code" binding:"required" example:"G44.209"`
+	Primary	bool	`json:"primary" example:"true"`
+}
+
+// EncounterNotesRequest carries the full content of an encounter revision.
+// Amendments replace the notes as a whole rather than patching them.
+type EncounterNotesRequest struct {
+	Subjective	string			`json:"subjective" example:"Headache for three days, worse in the morning."`
+	Objective	string			`json:"objective" example:"Alert and oriented, no focal neurological deficits."`
+	Assessment	string			`json:"assessment" example:"Tension-type headache."`
+	Plan		string			`json:"plan" example:"Ibuprofen as needed, follow up in two weeks."`
+	Diagnoses	[]DiagnosisRequest	`json:"diagnoses" binding:"dive"`
+	Vitals		models.Vitals		`json:"vitals"`
+}
+
+type EncounterAmendmentRequest struct {
+	EncounterNotesRequest
+	Reason	string	`json:"reason" binding:"required" example:"Corrected blood pressure reading"`
+}
+
+type DelegationRequest struct {
+	DoctorID	uuid.UUID	`json:"doctor_id" binding:"required" example:"b8f5a9e2-3c1d-4f7a-9e6b-2d4c8a1f3e5b"`
+}
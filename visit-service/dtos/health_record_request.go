@@ -0,0 +1,129 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+// HealthRecordRequest is implemented by the requests creating or replacing
+// an entry of the patient's medical history.
+type HealthRecordRequest interface {
+	// Apply copies the request onto a record of the matching kind.
+	Apply(record models.Recordable)
+	Encounter() *uuid.UUID
+}
+
+// NewHealthRecordRequest returns an empty request for the given kind.
+func NewHealthRecordRequest(kind models.HealthRecordKind) (HealthRecordRequest, bool) {
+	switch kind {
+	case models.AllergyRecord:
+		return &AllergyRequest{}, true
+	case models.ConditionRecord:
+		return &ConditionRequest{}, true
+	case models.ImmunizationRecord:
+		return &ImmunizationRequest{}, true
+	case models.MedicationRecord:
+		return &MedicationStatementRequest{}, true
+	case models.GenotypeRecord:
+		return &GenotypeRequest{}, true
+	default:
+		return nil, false
+	}
+}
+
+type RecordSource struct {
+	// EncounterID links the entry to the encounter it was recorded
+	// during.
+	EncounterID	*uuid.UUID	`json:"encounter_id,omitempty" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+}
+
+func (r *RecordSource) Encounter() *uuid.UUID {
+	return r.EncounterID
+}
+
+type AllergyRequest struct {
+	RecordSource
+	Substance	string				`json:"substance" binding:"required" example:"penicillin"`
+	Reaction	string				`json:"reaction" example:"Hives"`
+	Severity	models.InteractionSeverity	`json:"severity" binding:"required,oneof=minor moderate severe" example:"moderate"`
+}
+
+func (r *AllergyRequest) Apply(record models.Recordable) {
+	a := record.(*models.Allergy)
+	a.Substance = r.Substance
+	a.Reaction = r.Reaction
+	a.Severity = r.Severity
+}
+
+type ConditionRequest struct {
+	RecordSource
+	// Code is an ICD-10 code; the description is taken from the
+	// dictionary.
+	Code		string			`json:"code" binding:"required" example:"I10"`
+	Status		models.ConditionStatus	`json:"status" binding:"required,oneof=active resolved" example:"active"`
+	OnsetDate	*time.Time		`json:"onset_date,omitempty" example:"2019-04-01T00:00:00Z"`
+	Notes		string			`json:"notes,omitempty" example:"Controlled with medication"`
+}
+
+func (r *ConditionRequest) Apply(record models.Recordable) {
+	c := record.(*models.ChronicCondition)
+	c.Code = r.Code
+	c.Status = r.Status
+	c.OnsetDate = r.OnsetDate
+	c.Notes = r.Notes
+}
+
+type ImmunizationRequest struct {
+	RecordSource
+	Vaccine		string		`json:"vaccine" binding:"required" example:"Tetanus, diphtheria (Td)"`
+	DoseNumber	int		`json:"dose_number" binding:"required,min=1,max=20" example:"1"`
+	AdministeredOn	time.Time	`json:"administered_on" binding:"required" example:"2024-10-03T00:00:00Z"`
+	LotNumber	string		`json:"lot_number,omitempty" example:"AB1234"`
+}
+
+func (r *ImmunizationRequest) Apply(record models.Recordable) {
+	i := record.(*models.Immunization)
+	i.Vaccine = r.Vaccine
+	i.DoseNumber = r.DoseNumber
+	i.AdministeredOn = r.AdministeredOn
+	i.LotNumber = r.LotNumber
+}
+
+type MedicationStatementRequest struct {
+	RecordSource
+	DrugName	string		`json:"drug_name" binding:"required" example:"Metformin"`
+	Dosage		string		`json:"dosage,omitempty" example:"500 mg twice daily"`
+	StartedOn	*time.Time	`json:"started_on,omitempty" example:"2021-03-01T00:00:00Z"`
+	EndedOn		*time.Time	`json:"ended_on,omitempty" example:"2023-03-01T00:00:00Z"`
+}
+
+func (r *MedicationStatementRequest) Apply(record models.Recordable) {
+	m := record.(*models.MedicationStatement)
+	m.DrugName = r.DrugName
+	m.Dosage = r.Dosage
+	m.StartedOn = r.StartedOn
+	m.EndedOn = r.EndedOn
+}
+
+type GenotypeRequest struct {
+	RecordSource
+	Gene		string		`json:"gene" binding:"required" example:"CYP2D6"`
+	Diplotype	string		`json:"diplotype,omitempty" example:"*4/*4"`
+	// Phenotype is one of the standardized terms, such as
+	// `poor metabolizer` or `decreased function`.
+	Phenotype	string		`json:"phenotype" binding:"required" example:"poor metabolizer"`
+	TestedOn	*time.Time	`json:"tested_on,omitempty" example:"2024-11-20T00:00:00Z"`
+	Laboratory	string		`json:"laboratory,omitempty" example:"MGH Molecular Diagnostics"`
+}
+
+func (r *GenotypeRequest) Apply(record models.Recordable) {
+	g := record.(*models.Genotype)
+	g.Gene = r.Gene
+	g.Diplotype = r.Diplotype
+	g.Phenotype = r.Phenotype
+	g.TestedOn = r.TestedOn
+	g.Laboratory = r.Laboratory
+}
@@ -0,0 +1,5 @@
+package dtos
+
+type HomeAddressRequest struct {
+	Address	string	`json:"address" binding:"required,max=500" example:"10 Brattle Street, Cambridge"`
+}
@@ -0,0 +1,44 @@
+package dtos
+
+import (
+	"time"
+)
+
+type LabResultRequest struct {
+	LOINCCode	string		`json:"loinc_code" binding:"required" example:"2345-7"`
+	// Name is taken from the lab dictionary for known analytes and
+	// defaults to the code for others.
+	Name		string		`json:"name,omitempty" example:"Glucose"`
+	Value		*float64	`json:"value" binding:"required" example:"101"`
+	Unit		string		`json:"unit" binding:"required" example:"mg/dL"`
+	// ReferenceLow and ReferenceHigh are in Unit. They default to the
+	// dictionary range of the analyte.
+	ReferenceLow	*float64	`json:"reference_low,omitempty" example:"70"`
+	ReferenceHigh	*float64	`json:"reference_high,omitempty" example:"99"`
+	ObservedAt	time.Time	`json:"observed_at" binding:"required" example:"2025-06-02T08:00:00Z"`
+}
+
+// LabResultFilter narrows down the lab results of a patient. Empty fields
+// match every result.
+type LabResultFilter struct {
+	LOINCCode	string
+	From		*time.Time
+	To		*time.Time
+}
+
+// LabImportResult summarizes the import of an HL7 file.
+type LabImportResult struct {
+	Messages	int		`json:"messages" example:"2"`
+	Imported	int		`json:"imported" example:"7"`
+	// Duplicates counts results which had already been imported.
+	Duplicates	int		`json:"duplicates" example:"0"`
+	Rejected	[]LabImportIssue	`json:"rejected"`
+}
+
+// LabImportIssue explains why a message, or a single observation when Line
+// points at an OBX segment, was not imported.
+type LabImportIssue struct {
+	ControlID	string	`json:"control_id,omitempty" example:"MSG00001"`
+	Line		int	`json:"line" example:"5"`
+	Reason		string	`json:"reason" example:"Unsupported unit 'mmol/mol' for 4548-4"`
+}
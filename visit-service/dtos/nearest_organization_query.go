@@ -0,0 +1,23 @@
+package dtos
+
+const (
+	DefaultSearchRadiusKm	= 10.0
+	MaxSearchRadiusKm	= 500.0
+	DefaultNearestLimit	= 20
+	MaxNearestLimit		= 100
+)
+
+// NearestOrganizationQuery locates the organizations around either an
+// address, geocoded by the geo service, or a pair of coordinates.
+type NearestOrganizationQuery struct {
+	Address		string		`form:"address" example:"55 Fruit Street, Boston"`
+	Lat		*float64	`form:"lat" example:"42.3628605"`
+	Lon		*float64	`form:"lon" example:"-71.0687530"`
+	// RadiusKm defaults to DefaultSearchRadiusKm.
+	RadiusKm	float64		`form:"radius_km" binding:"omitempty,gt=0,max=500" example:"10"`
+	// Specialty keeps only the organizations where a doctor of the
+	// specialty has slots.
+	Specialty	string		`form:"specialty" example:"cardiology"`
+	// Limit defaults to DefaultNearestLimit.
+	Limit		int		`form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+}
@@ -0,0 +1,57 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type PrescriptionRequest struct {
+	PatientID	uuid.UUID	`json:"patient_id" binding:"required" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	EncounterID	*uuid.UUID	`json:"encounter_id,omitempty" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	DrugID		string		`json:"drug_id" binding:"required" example:"atorvastatin-20-tab"`
+	Dosage		string		`json:"dosage" binding:"required" example:"1 tablet once daily in the evening"`
+	Quantity	int		`json:"quantity" binding:"required,min=1,max=1000" example:"30"`
+	Refills		int		`json:"refills" binding:"min=0,max=11" example:"2"`
+	// ValidFrom defaults to the time of issuance.
+	ValidFrom	*time.Time	`json:"valid_from,omitempty" example:"2025-06-02T00:00:00Z"`
+	ValidUntil	time.Time	`json:"valid_until" binding:"required" example:"2025-09-02T00:00:00Z"`
+	// OverrideReason is required when the drug has a severe interaction
+	// with the patient's medication or allergies.
+	OverrideReason	string		`json:"override_reason,omitempty" example:"Benefit outweighs the bleeding risk; INR monitored weekly"`
+}
+
+type InteractionCheckRequest struct {
+	PatientID	uuid.UUID	`json:"patient_id" binding:"required" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DrugID		string		`json:"drug_id" binding:"required" example:"ibuprofen-400-tab"`
+}
+
+// InteractionConflictResponse is returned when a prescription is blocked
+// by severe warnings.
+type InteractionConflictResponse struct {
+	Message		string				`json:"message" example:"Severe interaction requires an override reason"`
+	Warnings	[]models.InteractionWarning	`json:"warnings"`
+}
+
+type DispenseRequest struct {
+	VerificationCode	string	`json:"verification_code" binding:"required" example:"7K2M-QX4P-9TBR"`
+}
+
+// PrescriptionVerification is what anyone holding a printed prescription
+// may learn about it. It deliberately leaves out who the patient is.
+type PrescriptionVerification struct {
+	VerificationCode	string		`json:"verification_code" example:"7K2M-QX4P-9TBR"`
+	Status			models.PrescriptionStatus`json:"status" example:"issued"`
+	DrugName		string		`json:"drug_name" example:"Atorvastatin"`
+	Form			string		`json:"form" example:"tablet"`
+	Strength		string		`json:"strength" example:"20 mg"`
+	Dosage			string		`json:"dosage" example:"1 tablet once daily in the evening"`
+	Quantity		int		`json:"quantity" example:"30"`
+	Refills			int		`json:"refills" example:"2"`
+	Fills			int		`json:"fills" example:"0"`
+	ValidFrom		time.Time	`json:"valid_from" example:"2025-06-02T00:00:00Z"`
+	ValidUntil		time.Time	`json:"valid_until" example:"2025-09-02T00:00:00Z"`
+	IssuedAt		time.Time	`json:"issued_at" example:"2025-06-02T10:15:00Z"`
+}
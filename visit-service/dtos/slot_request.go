@@ -0,0 +1,16 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type SlotRequest struct {
+	// Only admins may create slots on behalf of other doctors. Doctors
+	// always create slots for themselves.
+	DoctorID	*uuid.UUID	`json:"doctor_id,omitempty" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	OrganizationID	uuid.UUID	`json:"organization_id" binding:"required" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
+	StartsAt	time.Time	`json:"starts_at" binding:"required" example:"2025-06-02T09:30:00Z"`
+	EndsAt		time.Time	`json:"ends_at" binding:"required" example:"2025-06-02T10:00:00Z"`
+}
@@ -0,0 +1,14 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type WaitlistRequest struct {
+	DoctorID	*uuid.UUID	`json:"doctor_id,omitempty" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	OrganizationID	*uuid.UUID	`json:"organization_id,omitempty" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
+	From		time.Time	`json:"from" binding:"required" example:"2025-06-01T00:00:00Z"`
+	To		time.Time	`json:"to" binding:"required" example:"2025-06-14T00:00:00Z"`
+}
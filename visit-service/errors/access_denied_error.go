@@ -0,0 +1,7 @@
+package errors
+
+type AccessDeniedError struct{}
+
+func (m *AccessDeniedError) Error() string {
+	return "Access denied"
+}
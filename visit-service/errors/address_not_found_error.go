@@ -0,0 +1,7 @@
+package errors
+
+type AddressNotFoundError struct{}
+
+func (m *AddressNotFoundError) Error() string {
+	return "Address not found"
+}
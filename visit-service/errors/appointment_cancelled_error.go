@@ -0,0 +1,7 @@
+package errors
+
+type AppointmentCancelledError struct{}
+
+func (m *AppointmentCancelledError) Error() string {
+	return "Appointment has been cancelled"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type AppointmentNotFoundError struct{}
+
+func (m *AppointmentNotFoundError) Error() string {
+	return "Appointment not found"
+}
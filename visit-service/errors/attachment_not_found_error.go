@@ -0,0 +1,7 @@
+package errors
+
+type AttachmentNotFoundError struct{}
+
+func (m *AttachmentNotFoundError) Error() string {
+	return "Attachment not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type CareAssignmentNotFoundError struct{}
+
+func (m *CareAssignmentNotFoundError) Error() string {
+	return "Care team member not found"
+}
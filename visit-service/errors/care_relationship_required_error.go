@@ -0,0 +1,7 @@
+package errors
+
+type CareRelationshipRequiredError struct{}
+
+func (m *CareRelationshipRequiredError) Error() string {
+	return "Conversations require a care relationship or a shared appointment"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type ConsentNotFoundError struct{}
+
+func (m *ConsentNotFoundError) Error() string {
+	return "Consent not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type ConsentRevokedError struct{}
+
+func (m *ConsentRevokedError) Error() string {
+	return "Consent already revoked"
+}
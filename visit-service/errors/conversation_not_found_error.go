@@ -0,0 +1,7 @@
+package errors
+
+type ConversationNotFoundError struct{}
+
+func (m *ConversationNotFoundError) Error() string {
+	return "Conversation not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type DocumentNotFoundError struct{}
+
+func (m *DocumentNotFoundError) Error() string {
+	return "Document not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type DocumentTooLargeError struct{}
+
+func (m *DocumentTooLargeError) Error() string {
+	return "Document too large"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type EmergencyAccessNotFoundError struct{}
+
+func (m *EmergencyAccessNotFoundError) Error() string {
+	return "Emergency access not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type EmergencyAccessReviewedError struct{}
+
+func (m *EmergencyAccessReviewedError) Error() string {
+	return "Emergency access already reviewed"
+}
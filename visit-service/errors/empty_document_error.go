@@ -0,0 +1,7 @@
+package errors
+
+type EmptyDocumentError struct{}
+
+func (m *EmptyDocumentError) Error() string {
+	return "Document is empty"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type EncounterAlreadyExistsError struct{}
+
+func (m *EncounterAlreadyExistsError) Error() string {
+	return "Encounter already exists for this appointment"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type EncounterNotFoundError struct{}
+
+func (m *EncounterNotFoundError) Error() string {
+	return "Encounter not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type GeocodingFailedError struct{}
+
+func (m *GeocodingFailedError) Error() string {
+	return "Failed to geocode the address"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type HealthRecordNotFoundError struct{}
+
+func (m *HealthRecordNotFoundError) Error() string {
+	return "Health record not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type HomeAddressNotFoundError struct{}
+
+func (m *HomeAddressNotFoundError) Error() string {
+	return "Home address not found"
+}
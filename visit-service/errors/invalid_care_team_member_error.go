@@ -0,0 +1,7 @@
+package errors
+
+type InvalidCareTeamMemberError struct{}
+
+func (m *InvalidCareTeamMemberError) Error() string {
+	return "Care team member must be a doctor"
+}
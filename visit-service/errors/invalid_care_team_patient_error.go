@@ -0,0 +1,7 @@
+package errors
+
+type InvalidCareTeamPatientError struct{}
+
+func (m *InvalidCareTeamPatientError) Error() string {
+	return "Care team must belong to a patient"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidConsentCategoryError struct{}
+
+func (m *InvalidConsentCategoryError) Error() string {
+	return "Unknown consent category"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidDelegateError struct{}
+
+func (m *InvalidDelegateError) Error() string {
+	return "Delegate must be a doctor"
+}
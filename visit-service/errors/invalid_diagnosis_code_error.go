@@ -0,0 +1,7 @@
+package errors
+
+type InvalidDiagnosisCodeError struct{}
+
+func (m *InvalidDiagnosisCodeError) Error() string {
+	return "Unknown ICD-10 diagnosis code"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidDoctorError struct{}
+
+func (m *InvalidDoctorError) Error() string {
+	return "User is not a doctor"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidDocumentCategoryError struct{}
+
+func (m *InvalidDocumentCategoryError) Error() string {
+	return "Unknown document category"
+}
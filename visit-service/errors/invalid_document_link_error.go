@@ -0,0 +1,7 @@
+package errors
+
+type InvalidDocumentLinkError struct{}
+
+func (m *InvalidDocumentLinkError) Error() string {
+	return "Invalid or expired document link"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidEmergencyPatientError struct{}
+
+func (m *InvalidEmergencyPatientError) Error() string {
+	return "Emergency access must be to a patient"
+}
@@ -0,0 +1,9 @@
+package errors
+
+// InvalidGranteeError rejects a consent given to a user who is not a
+// doctor or to an unknown organization.
+type InvalidGranteeError struct{}
+
+func (m *InvalidGranteeError) Error() string {
+	return "Grantee must be a doctor or an organization"
+}
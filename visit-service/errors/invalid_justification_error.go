@@ -0,0 +1,7 @@
+package errors
+
+type InvalidJustificationError struct{}
+
+func (m *InvalidJustificationError) Error() string {
+	return "Justification must be at least 20 characters"
+}
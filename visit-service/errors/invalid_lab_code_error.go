@@ -0,0 +1,7 @@
+package errors
+
+type InvalidLabCodeError struct{}
+
+func (m *InvalidLabCodeError) Error() string {
+	return "Invalid LOINC code"
+}
@@ -0,0 +1,10 @@
+package errors
+
+// InvalidLabMessageError rejects an HL7 file which cannot be parsed at all.
+type InvalidLabMessageError struct {
+	Reason string
+}
+
+func (m *InvalidLabMessageError) Error() string {
+	return "Invalid HL7 message: " + m.Reason
+}
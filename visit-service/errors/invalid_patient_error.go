@@ -0,0 +1,7 @@
+package errors
+
+type InvalidPatientError struct{}
+
+func (m *InvalidPatientError) Error() string {
+	return "Recipient must be a patient"
+}
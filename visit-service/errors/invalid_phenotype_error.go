@@ -0,0 +1,7 @@
+package errors
+
+type InvalidPhenotypeError struct{}
+
+func (m *InvalidPhenotypeError) Error() string {
+	return "Unknown phenotype"
+}
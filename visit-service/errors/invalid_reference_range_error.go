@@ -0,0 +1,7 @@
+package errors
+
+type InvalidReferenceRangeError struct{}
+
+func (m *InvalidReferenceRangeError) Error() string {
+	return "Reference range low limit exceeds its high limit"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidReviewStatusError struct{}
+
+func (m *InvalidReviewStatusError) Error() string {
+	return "Invalid review status"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidSearchLocationError struct{}
+
+func (m *InvalidSearchLocationError) Error() string {
+	return "Either an address or valid 'lat' and 'lon' must be given"
+}
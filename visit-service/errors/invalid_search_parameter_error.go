@@ -0,0 +1,15 @@
+package errors
+
+import (
+	"fmt"
+)
+
+// InvalidSearchParameterError rejects a FHIR search parameter which is
+// unknown or malformed.
+type InvalidSearchParameterError struct {
+	Parameter string
+}
+
+func (m *InvalidSearchParameterError) Error() string {
+	return fmt.Sprintf("Unsupported or invalid search parameter '%s'", m.Parameter)
+}
@@ -0,0 +1,7 @@
+package errors
+
+type InvalidTimeRangeError struct{}
+
+func (m *InvalidTimeRangeError) Error() string {
+	return "Invalid time range"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type OfferExpiredError struct{}
+
+func (m *OfferExpiredError) Error() string {
+	return "Offer has expired"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type OfferNotFoundError struct{}
+
+func (m *OfferNotFoundError) Error() string {
+	return "Offer not found"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type PrescriptionNotActiveError struct{}
+
+func (m *PrescriptionNotActiveError) Error() string {
+	return "Prescription is not active"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type PrescriptionNotFoundError struct{}
+
+func (m *PrescriptionNotFoundError) Error() string {
+	return "Prescription not found"
+}
@@ -0,0 +1,15 @@
+package errors
+
+import (
+	"igaku/visit-service/models"
+)
+
+// SevereInteractionError carries the warnings which block issuing a
+// prescription until the doctor gives an override reason.
+type SevereInteractionError struct {
+	Warnings []models.InteractionWarning
+}
+
+func (m *SevereInteractionError) Error() string {
+	return "Severe interaction requires an override reason"
+}
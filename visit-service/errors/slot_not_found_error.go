@@ -0,0 +1,7 @@
+package errors
+
+type SlotNotFoundError struct{}
+
+func (m *SlotNotFoundError) Error() string {
+	return "Slot not found"
+}
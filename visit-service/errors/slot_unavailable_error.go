@@ -0,0 +1,7 @@
+package errors
+
+type SlotUnavailableError struct{}
+
+func (m *SlotUnavailableError) Error() string {
+	return "Slot is no longer available"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type UnknownDrugError struct{}
+
+func (m *UnknownDrugError) Error() string {
+	return "Unknown drug"
+}
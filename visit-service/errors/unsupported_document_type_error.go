@@ -0,0 +1,7 @@
+package errors
+
+type UnsupportedDocumentTypeError struct{}
+
+func (m *UnsupportedDocumentTypeError) Error() string {
+	return "Unsupported document type"
+}
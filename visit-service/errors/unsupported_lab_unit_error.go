@@ -0,0 +1,7 @@
+package errors
+
+type UnsupportedLabUnitError struct{}
+
+func (m *UnsupportedLabUnitError) Error() string {
+	return "Unsupported unit for the analyte"
+}
@@ -0,0 +1,7 @@
+package errors
+
+type WaitlistEntryNotFoundError struct{}
+
+func (m *WaitlistEntryNotFoundError) Error() string {
+	return "Waitlist entry not found"
+}
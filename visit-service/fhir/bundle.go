@@ -0,0 +1,85 @@
+package fhir
+
+type BundleLink struct {
+	Relation	string	`json:"relation"`
+	URL		string	`json:"url"`
+}
+
+type BundleEntrySearch struct {
+	Mode	string	`json:"mode"`
+}
+
+type BundleEntry struct {
+	FullURL		string			`json:"fullUrl"`
+	Resource	Resource		`json:"resource"`
+	Search		*BundleEntrySearch	`json:"search,omitempty"`
+}
+
+type Bundle struct {
+	ResourceType	string		`json:"resourceType"`
+	Type		string		`json:"type"`
+	Total		int64		`json:"total"`
+	Link		[]BundleLink	`json:"link"`
+	Entry		[]BundleEntry	`json:"entry"`
+}
+
+// SearchResult is a page of matches, along with the resources pulled in
+// by `_revinclude`, and the number of all matches.
+type SearchResult struct {
+	Matches		[]Resource
+	Included	[]Resource
+	Total		int64
+}
+
+// NewSearchBundle builds a `searchset` bundle with links to the adjacent
+// pages. baseURL is the root of the FHIR API.
+func NewSearchBundle(
+	baseURL, resourceType string, search *Search, result *SearchResult,
+) *Bundle {
+	bundle := &Bundle{
+		ResourceType: "Bundle",
+		Type: "searchset",
+		Total: result.Total,
+		Entry: make([]BundleEntry, 0, len(result.Matches)+len(result.Included)),
+	}
+
+	link := func(relation string, offset int) {
+		query := search.Query(offset).Encode()
+		bundle.Link = append(bundle.Link, BundleLink{
+			Relation: relation,
+			URL: baseURL + "/" + resourceType + "?" + query,
+		})
+	}
+
+	link("self", search.Offset)
+	link("first", 0)
+	if search.Count > 0 {
+		if search.Offset > 0 {
+			link("previous", max(search.Offset-search.Count, 0))
+		}
+		if next := search.Offset + search.Count; int64(next) < result.Total {
+			link("next", next)
+		}
+		if result.Total > 0 {
+			pages := int((result.Total - 1) / int64(search.Count))
+			link("last", pages*search.Count)
+		}
+	}
+
+	for _, r := range result.Matches {
+		bundle.Entry = append(bundle.Entry, BundleEntry{
+			FullURL: baseURL + "/" + r.Reference(),
+			Resource: r,
+			Search: &BundleEntrySearch{Mode: "match"},
+		})
+	}
+	for _, r := range result.Included {
+		bundle.Entry = append(bundle.Entry, BundleEntry{
+			FullURL: baseURL + "/" + r.Reference(),
+			Resource: r,
+			Search: &BundleEntrySearch{Mode: "include"},
+		})
+	}
+
+	return bundle
+}
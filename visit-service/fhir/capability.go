@@ -0,0 +1,112 @@
+package fhir
+
+import (
+	"time"
+)
+
+type CapabilityInteraction struct {
+	Code	string	`json:"code"`
+}
+
+type CapabilityOperation struct {
+	Name		string	`json:"name"`
+	Definition	string	`json:"definition"`
+}
+
+type CapabilityResource struct {
+	Type			string			`json:"type"`
+	Profile			string			`json:"profile"`
+	Interaction		[]CapabilityInteraction	`json:"interaction"`
+	SearchRevInclude	[]string		`json:"searchRevInclude,omitempty"`
+	SearchParam		[]SearchParam		`json:"searchParam"`
+	Operation		[]CapabilityOperation	`json:"operation"`
+}
+
+type CapabilitySecurity struct {
+	Service		[]CodeableConcept	`json:"service"`
+	Description	string			`json:"description"`
+}
+
+type CapabilityRest struct {
+	Mode		string			`json:"mode"`
+	Security	CapabilitySecurity	`json:"security"`
+	Resource	[]CapabilityResource	`json:"resource"`
+}
+
+type CapabilitySoftware struct {
+	Name	string	`json:"name"`
+}
+
+type CapabilityImplementation struct {
+	Description	string	`json:"description"`
+	URL		string	`json:"url"`
+}
+
+type CapabilityStatement struct {
+	ResourceType	string				`json:"resourceType"`
+	Status		string				`json:"status"`
+	Date		string				`json:"date"`
+	Kind		string				`json:"kind"`
+	Software	CapabilitySoftware		`json:"software"`
+	Implementation	CapabilityImplementation	`json:"implementation"`
+	FHIRVersion	string				`json:"fhirVersion"`
+	Format		[]string			`json:"format"`
+	Rest		[]CapabilityRest		`json:"rest"`
+}
+
+// NewCapabilityStatement describes the facade served at baseURL.
+func NewCapabilityStatement(baseURL string, now time.Time) *CapabilityStatement {
+	resources := make([]CapabilityResource, 0, len(ResourceTypes))
+	for _, t := range ResourceTypes {
+		resources = append(resources, CapabilityResource{
+			Type: t,
+			Profile: "http://hl7.org/fhir/StructureDefinition/" + t,
+			Interaction: []CapabilityInteraction{
+				{Code: "read"},
+				{Code: "search-type"},
+			},
+			SearchRevInclude: RevIncludes[t],
+			SearchParam: SearchParams[t],
+			Operation: []CapabilityOperation{
+				{
+					Name: "validate",
+					Definition: "http://hl7.org/fhir/OperationDefinition/Resource-validate",
+				},
+			},
+		})
+	}
+
+	return &CapabilityStatement{
+		ResourceType: "CapabilityStatement",
+		Status: "active",
+		Date: now.UTC().Format(time.RFC3339),
+		Kind: "instance",
+		Software: CapabilitySoftware{Name: "Igaku"},
+		Implementation: CapabilityImplementation{
+			Description: "Igaku FHIR facade",
+			URL: baseURL,
+		},
+		FHIRVersion: Version,
+		Format: []string{"json"},
+		Rest: []CapabilityRest{
+			{
+				Mode: "server",
+				Security: CapabilitySecurity{
+					Service: []CodeableConcept{
+						{
+							Coding: []Coding{
+								{
+									System: "http://terminology.hl7.org/CodeSystem/restful-security-service",
+									Code: "OAuth",
+								},
+							},
+							Text: "Bearer JWT issued by the auth service",
+						},
+					},
+					Description: "Send the access token in the Authorization header.",
+				},
+				Resource: resources,
+			},
+		},
+	}
+}
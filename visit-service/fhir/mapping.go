@@ -0,0 +1,198 @@
+package fhir
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsModels "igaku/commons/models"
+)
+
+const (
+	// UsernameSystem identifies Igaku usernames.
+	UsernameSystem = "urn:igaku:username"
+	// OSMSystem identifies OpenStreetMap objects.
+	OSMSystem = "https://www.openstreetmap.org"
+)
+
+// defaultAppointmentLength is assumed for appointments booked without a
+// slot, as booked appointments must have an end in FHIR.
+const defaultAppointmentLength = 30 * time.Minute
+
+var appointmentStatuses = map[models.AppointmentStatus]string{
+	models.Booked: "booked",
+	models.Cancelled: "cancelled",
+}
+
+// AppointmentStatus maps a FHIR appointment status onto Igaku's.
+func AppointmentStatus(status string) (models.AppointmentStatus, bool) {
+	for s, fhirStatus := range appointmentStatuses {
+		if fhirStatus == status {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+func NewPatient(user *commonsModels.User) *Patient {
+	return &Patient{
+		ResourceType: "Patient",
+		ID: user.ID.String(),
+		Identifier: userIdentifiers(user),
+		Active: true,
+		Name: []HumanName{{Use: "usual", Text: user.Username}},
+		Telecom: userTelecom(user),
+	}
+}
+
+func NewPractitioner(user *commonsModels.User) *Practitioner {
+	return &Practitioner{
+		ResourceType: "Practitioner",
+		ID: user.ID.String(),
+		Identifier: userIdentifiers(user),
+		Active: true,
+		Name: []HumanName{{Use: "usual", Text: user.Username}},
+		Telecom: userTelecom(user),
+	}
+}
+
+func userIdentifiers(user *commonsModels.User) []Identifier {
+	return []Identifier{
+		{Use: "usual", System: UsernameSystem, Value: user.Username},
+	}
+}
+
+func userTelecom(user *commonsModels.User) []ContactPoint {
+	if user.Email == "" {
+		return nil
+	}
+	return []ContactPoint{{System: "email", Value: user.Email, Use: "work"}}
+}
+
+func NewOrganization(org *models.Organization) *Organization {
+	o := &Organization{
+		ResourceType: "Organization",
+		ID: org.ID.String(),
+		Active: true,
+		Type: []CodeableConcept{
+			{
+				Coding: []Coding{
+					{
+						System: "http://terminology.hl7.org/CodeSystem/organization-type",
+						Code: "prov",
+						Display: "Healthcare Provider",
+					},
+				},
+			},
+		},
+		Name: org.Name,
+	}
+	if org.Location.Name != "" {
+		o.Address = []Address{*newAddress(org)}
+	}
+	return o
+}
+
+// newAddress describes the address of an organization, with its components
+// when the geocoding provider knew them.
+func newAddress(org *models.Organization) *Address {
+	components := org.Location.Address
+	address := &Address{
+		Use: "work",
+		Type: "physical",
+		Text: org.Location.Name,
+		City: components.City,
+		District: components.County,
+		PostalCode: components.Postcode,
+		Country: components.Country,
+	}
+
+	line := strings.TrimSpace(components.HouseNumber + " " + components.Street)
+	if line != "" {
+		address.Line = []string{line}
+	}
+	return address
+}
+
+// NewLocation describes where an organization is. The location shares the
+// organization's ID.
+func NewLocation(org *models.Organization) *Location {
+	l := &Location{
+		ResourceType: "Location",
+		ID: org.ID.String(),
+		Status: "active",
+		Name: org.Name,
+		Mode: "instance",
+		ManagingOrganization: &Reference{
+			Reference: "Organization/" + org.ID.String(),
+			Display: org.Name,
+		},
+	}
+	if org.Location.ID != 0 {
+		l.Identifier = []Identifier{
+			{System: OSMSystem, Value: strconv.FormatInt(org.Location.ID, 10)},
+		}
+	}
+	if org.Location.Name != "" {
+		l.Address = newAddress(org)
+	}
+
+	_, latErr := strconv.ParseFloat(org.Location.Lat, 64)
+	_, lonErr := strconv.ParseFloat(org.Location.Lon, 64)
+	if latErr == nil && lonErr == nil {
+		l.Position = &Position{
+			Latitude: json.Number(org.Location.Lat),
+			Longitude: json.Number(org.Location.Lon),
+		}
+	}
+
+	return l
+}
+
+func NewAppointment(appt *models.Appointment) *Appointment {
+	end := appt.StartsAt.Add(defaultAppointmentLength)
+	if appt.Slot != nil {
+		end = appt.Slot.EndsAt
+	}
+
+	participantStatus := "accepted"
+	if appt.Status == models.Cancelled {
+		participantStatus = "declined"
+	}
+
+	return &Appointment{
+		ResourceType: "Appointment",
+		ID: appt.ID.String(),
+		Status: appointmentStatuses[appt.Status],
+		Start: appt.StartsAt.UTC().Format(time.RFC3339),
+		End: end.UTC().Format(time.RFC3339),
+		Participant: []AppointmentParticipant{
+			{
+				Actor: &Reference{
+					Reference: "Patient/" + appt.PatientID.String(),
+					Type: "Patient",
+				},
+				Required: "required",
+				Status: participantStatus,
+			},
+			{
+				Actor: &Reference{
+					Reference: "Practitioner/" + appt.DoctorID.String(),
+					Type: "Practitioner",
+				},
+				Required: "required",
+				Status: participantStatus,
+			},
+			{
+				Actor: &Reference{
+					Reference: "Location/" + appt.OrganizationID.String(),
+					Type: "Location",
+				},
+				Required: "required",
+				Status: participantStatus,
+			},
+		},
+	}
+}
@@ -0,0 +1,176 @@
+package fhir
+
+import (
+	"encoding/json"
+)
+
+// Version is the FHIR release the facade implements.
+const Version = "4.0.1"
+
+// ContentType is the media type of FHIR JSON resources.
+const ContentType = "application/fhir+json"
+
+// Resource is implemented by every resource the facade serves.
+type Resource interface {
+	// Reference returns the relative reference, e.g. `Patient/<id>`.
+	Reference() string
+}
+
+type Meta struct {
+	VersionID	string	`json:"versionId,omitempty"`
+	LastUpdated	string	`json:"lastUpdated,omitempty"`
+}
+
+type Identifier struct {
+	Use	string	`json:"use,omitempty"`
+	System	string	`json:"system,omitempty"`
+	Value	string	`json:"value,omitempty"`
+}
+
+type HumanName struct {
+	Use	string		`json:"use,omitempty"`
+	Text	string		`json:"text,omitempty"`
+	Family	string		`json:"family,omitempty"`
+	Given	[]string	`json:"given,omitempty"`
+}
+
+type ContactPoint struct {
+	System	string	`json:"system,omitempty"`
+	Value	string	`json:"value,omitempty"`
+	Use	string	`json:"use,omitempty"`
+}
+
+type Address struct {
+	Use		string		`json:"use,omitempty"`
+	Type		string		`json:"type,omitempty"`
+	Text		string		`json:"text,omitempty"`
+	Line		[]string	`json:"line,omitempty"`
+	City		string		`json:"city,omitempty"`
+	District	string		`json:"district,omitempty"`
+	PostalCode	string		`json:"postalCode,omitempty"`
+	Country		string		`json:"country,omitempty"`
+}
+
+type Coding struct {
+	System	string	`json:"system,omitempty"`
+	Code	string	`json:"code,omitempty"`
+	Display	string	`json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding	[]Coding	`json:"coding,omitempty"`
+	Text	string		`json:"text,omitempty"`
+}
+
+type Reference struct {
+	Reference	string	`json:"reference,omitempty"`
+	Type		string	`json:"type,omitempty"`
+	Display		string	`json:"display,omitempty"`
+}
+
+type Patient struct {
+	ResourceType	string		`json:"resourceType"`
+	ID		string		`json:"id"`
+	Meta		*Meta		`json:"meta,omitempty"`
+	Identifier	[]Identifier	`json:"identifier,omitempty"`
+	Active		bool		`json:"active"`
+	Name		[]HumanName	`json:"name,omitempty"`
+	Telecom		[]ContactPoint	`json:"telecom,omitempty"`
+}
+
+func (p *Patient) Reference() string {
+	return "Patient/" + p.ID
+}
+
+type Practitioner struct {
+	ResourceType	string		`json:"resourceType"`
+	ID		string		`json:"id"`
+	Meta		*Meta		`json:"meta,omitempty"`
+	Identifier	[]Identifier	`json:"identifier,omitempty"`
+	Active		bool		`json:"active"`
+	Name		[]HumanName	`json:"name,omitempty"`
+	Telecom		[]ContactPoint	`json:"telecom,omitempty"`
+}
+
+func (p *Practitioner) Reference() string {
+	return "Practitioner/" + p.ID
+}
+
+type Organization struct {
+	ResourceType	string			`json:"resourceType"`
+	ID		string			`json:"id"`
+	Meta		*Meta			`json:"meta,omitempty"`
+	Identifier	[]Identifier		`json:"identifier,omitempty"`
+	Active		bool			`json:"active"`
+	Type		[]CodeableConcept	`json:"type,omitempty"`
+	Name		string			`json:"name,omitempty"`
+	Address		[]Address		`json:"address,omitempty"`
+}
+
+func (o *Organization) Reference() string {
+	return "Organization/" + o.ID
+}
+
+type Position struct {
+	Longitude	json.Number	`json:"longitude"`
+	Latitude	json.Number	`json:"latitude"`
+}
+
+type Location struct {
+	ResourceType		string		`json:"resourceType"`
+	ID			string		`json:"id"`
+	Meta			*Meta		`json:"meta,omitempty"`
+	Identifier		[]Identifier	`json:"identifier,omitempty"`
+	Status			string		`json:"status,omitempty"`
+	Name			string		`json:"name,omitempty"`
+	Mode			string		`json:"mode,omitempty"`
+	Address			*Address	`json:"address,omitempty"`
+	Position		*Position	`json:"position,omitempty"`
+	ManagingOrganization	*Reference	`json:"managingOrganization,omitempty"`
+}
+
+func (l *Location) Reference() string {
+	return "Location/" + l.ID
+}
+
+type AppointmentParticipant struct {
+	Actor		*Reference	`json:"actor,omitempty"`
+	Required	string		`json:"required,omitempty"`
+	Status		string		`json:"status"`
+}
+
+type Appointment struct {
+	ResourceType	string				`json:"resourceType"`
+	ID		string				`json:"id"`
+	Meta		*Meta				`json:"meta,omitempty"`
+	Status		string				`json:"status"`
+	Start		string				`json:"start,omitempty"`
+	End		string				`json:"end,omitempty"`
+	Participant	[]AppointmentParticipant	`json:"participant"`
+}
+
+func (a *Appointment) Reference() string {
+	return "Appointment/" + a.ID
+}
+
+type OperationOutcomeIssue struct {
+	Severity	string		`json:"severity"`
+	Code		string		`json:"code"`
+	Diagnostics	string		`json:"diagnostics,omitempty"`
+	Expression	[]string	`json:"expression,omitempty"`
+}
+
+type OperationOutcome struct {
+	ResourceType	string			`json:"resourceType"`
+	Issue		[]OperationOutcomeIssue	`json:"issue"`
+}
+
+// NewOperationOutcome reports a single issue.
+func NewOperationOutcome(severity, code, diagnostics string) *OperationOutcome {
+	return &OperationOutcome{
+		ResourceType: "OperationOutcome",
+		Issue: []OperationOutcomeIssue{
+			{Severity: severity, Code: code, Diagnostics: diagnostics},
+		},
+	}
+}
@@ -0,0 +1,225 @@
+package fhir
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	igakuErrors "igaku/visit-service/errors"
+)
+
+const (
+	// DefaultCount is the page size used when `_count` is not given.
+	DefaultCount = 20
+	// MaxCount caps `_count`.
+	MaxCount = 100
+)
+
+type SearchParam struct {
+	Name		string	`json:"name"`
+	Type		string	`json:"type"`
+	Documentation	string	`json:"documentation,omitempty"`
+}
+
+// ResourceTypes lists the resources served by the facade.
+var ResourceTypes = []string{
+	"Patient",
+	"Practitioner",
+	"Organization",
+	"Location",
+	"Appointment",
+}
+
+var idParam = SearchParam{
+	Name: "_id",
+	Type: "token",
+	Documentation: "Logical ID of the resource",
+}
+
+// SearchParams lists the search parameters of each resource.
+var SearchParams = map[string][]SearchParam{
+	"Patient": {
+		idParam,
+		{Name: "name", Type: "string", Documentation: "Start of the username"},
+		{Name: "identifier", Type: "token", Documentation: "Username, optionally prefixed with `" + UsernameSystem + "|`"},
+		{Name: "email", Type: "token", Documentation: "E-mail address"},
+	},
+	"Practitioner": {
+		idParam,
+		{Name: "name", Type: "string", Documentation: "Start of the username"},
+		{Name: "identifier", Type: "token", Documentation: "Username, optionally prefixed with `" + UsernameSystem + "|`"},
+		{Name: "email", Type: "token", Documentation: "E-mail address"},
+	},
+	"Organization": {
+		idParam,
+		{Name: "name", Type: "string", Documentation: "Part of the organization name"},
+	},
+	"Location": {
+		idParam,
+		{Name: "name", Type: "string", Documentation: "Part of the location name"},
+		{Name: "organization", Type: "reference", Documentation: "Managing organization"},
+	},
+	"Appointment": {
+		idParam,
+		{Name: "patient", Type: "reference", Documentation: "Patient attending the appointment"},
+		{Name: "practitioner", Type: "reference", Documentation: "Practitioner attending the appointment"},
+		{Name: "location", Type: "reference", Documentation: "Location of the appointment"},
+		{Name: "status", Type: "token", Documentation: "booked | cancelled"},
+		{Name: "date", Type: "date", Documentation: "Start of the appointment; supports the eq, ge, gt, le and lt prefixes"},
+	},
+}
+
+// RevIncludes lists the supported `_revinclude` values of each resource.
+var RevIncludes = map[string][]string{
+	"Organization": {"Location:organization"},
+}
+
+// Search is a parsed search request.
+type Search struct {
+	Params		url.Values
+	RevInclude	[]string
+	Count		int
+	Offset		int
+}
+
+// ParseSearch checks the query against the search parameters of the
+// resource. Unknown parameters are rejected rather than ignored, so that
+// clients never mistake an unfiltered result for a filtered one.
+func ParseSearch(resourceType string, query url.Values) (*Search, error) {
+	known := make(map[string]bool)
+	for _, p := range SearchParams[resourceType] {
+		known[p.Name] = true
+	}
+
+	search := &Search{Params: url.Values{}, Count: DefaultCount}
+	for name, values := range query {
+		switch name {
+		case "_count":
+			count, err := strconv.Atoi(values[0])
+			if err != nil || count < 0 {
+				return nil, &igakuErrors.InvalidSearchParameterError{Parameter: name}
+			}
+			search.Count = min(count, MaxCount)
+		case "_offset":
+			offset, err := strconv.Atoi(values[0])
+			if err != nil || offset < 0 {
+				return nil, &igakuErrors.InvalidSearchParameterError{Parameter: name}
+			}
+			search.Offset = offset
+		case "_format":
+			if !isJSONFormat(values[0]) {
+				return nil, &igakuErrors.InvalidSearchParameterError{Parameter: name}
+			}
+		case "_revinclude":
+			for _, v := range values {
+				if !slices.Contains(RevIncludes[resourceType], v) {
+					return nil, &igakuErrors.InvalidSearchParameterError{Parameter: name}
+				}
+			}
+			search.RevInclude = values
+		default:
+			if !known[name] {
+				return nil, &igakuErrors.InvalidSearchParameterError{Parameter: name}
+			}
+			search.Params[name] = values
+		}
+	}
+
+	return search, nil
+}
+
+// Query encodes the search, moved to the given offset, as URL parameters.
+func (s *Search) Query(offset int) url.Values {
+	query := url.Values{}
+	for name, values := range s.Params {
+		query[name] = values
+	}
+	if len(s.RevInclude) > 0 {
+		query["_revinclude"] = s.RevInclude
+	}
+	query.Set("_count", strconv.Itoa(s.Count))
+	query.Set("_offset", strconv.Itoa(offset))
+	return query
+}
+
+// ReferenceID strips the resource type from a reference parameter such as
+// `Patient/<id>`, so that it can also be given as a bare ID.
+func ReferenceID(value, resourceType string) string {
+	return strings.TrimPrefix(value, resourceType+"/")
+}
+
+// TokenValue strips the system from a token parameter, returning false
+// when the system is not the expected one.
+func TokenValue(value, system string) (string, bool) {
+	i := strings.Index(value, "|")
+	if i < 0 {
+		return value, true
+	}
+	if value[:i] != "" && value[:i] != system {
+		return "", false
+	}
+	return value[i+1:], true
+}
+
+var datePrefixes = []string{"eq", "ge", "gt", "le", "lt"}
+
+// ParseDate parses a date search value, such as `ge2025-06-01`, into the
+// half-open range of start times it matches. Either bound may be nil.
+func ParseDate(value string) (from, until *time.Time, err error) {
+	prefix := "eq"
+	if len(value) > 2 && slices.Contains(datePrefixes, value[:2]) {
+		prefix = value[:2]
+		value = value[2:]
+	}
+
+	start, end, ok := parseDateRange(value)
+	if !ok {
+		return nil, nil, &igakuErrors.InvalidSearchParameterError{Parameter: "date"}
+	}
+
+	switch prefix {
+	case "eq":
+		return &start, &end, nil
+	case "ge":
+		return &start, nil, nil
+	case "gt":
+		return &end, nil, nil
+	case "le":
+		return nil, &end, nil
+	default:
+		return nil, &start, nil
+	}
+}
+
+// parseDateRange parses a FHIR date or dateTime into the range covered by
+// its precision.
+func parseDateRange(value string) (start, end time.Time, ok bool) {
+	layouts := []struct {
+		layout	string
+		next	func(time.Time) time.Time
+	}{
+		{"2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+		{"2006-01", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+		{"2006-01-02", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+		{time.RFC3339, func(t time.Time) time.Time { return t.Add(time.Second) }},
+	}
+
+	for _, l := range layouts {
+		t, err := time.Parse(l.layout, value)
+		if err == nil {
+			return t, l.next(t), true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func isJSONFormat(format string) bool {
+	switch format {
+	case "json", "application/json", ContentType:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,295 @@
+package fhir
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// element describes an element of a base resource or data type structure.
+type element struct {
+	typ		string
+	many		bool
+	required	bool
+	// codes lists the allowed values of elements bound to a required
+	// value set.
+	codes		[]string
+	// choice names the `[x]` element the element is a variant of.
+	choice		string
+}
+
+// structureSpecs lists the elements of the base R4 structures served by
+// the facade. Each element is written as `type`, followed by `*` when it
+// repeats, `!` when it is required and `=a|b` for required bindings.
+// Choice elements use the `name[x]` notation with a comma-separated list
+// of types. Elements of backbone elements are listed under
+// `Resource.element`.
+var structureSpecs = map[string]map[string]string{
+	"DomainResource": {
+		"id": "id",
+		"meta": "Meta",
+		"implicitRules": "uri",
+		"language": "code",
+		"text": "Narrative",
+		"contained": "Resource*",
+		"extension": "Extension*",
+		"modifierExtension": "Extension*",
+	},
+	"Patient": {
+		"identifier": "Identifier*",
+		"active": "boolean",
+		"name": "HumanName*",
+		"telecom": "ContactPoint*",
+		"gender": "code=male|female|other|unknown",
+		"birthDate": "date",
+		"deceased[x]": "boolean,dateTime",
+		"address": "Address*",
+		"maritalStatus": "CodeableConcept",
+		"multipleBirth[x]": "boolean,integer",
+		"photo": "Attachment*",
+		"contact": "Patient.contact*",
+		"communication": "Patient.communication*",
+		"generalPractitioner": "Reference*",
+		"managingOrganization": "Reference",
+		"link": "Patient.link*",
+	},
+	"Patient.contact": {
+		"relationship": "CodeableConcept*",
+		"name": "HumanName",
+		"telecom": "ContactPoint*",
+		"address": "Address",
+		"gender": "code=male|female|other|unknown",
+		"organization": "Reference",
+		"period": "Period",
+	},
+	"Patient.communication": {
+		"language": "CodeableConcept!",
+		"preferred": "boolean",
+	},
+	"Patient.link": {
+		"other": "Reference!",
+		"type": "code!=replaced-by|replaces|refer|seealso",
+	},
+	"Practitioner": {
+		"identifier": "Identifier*",
+		"active": "boolean",
+		"name": "HumanName*",
+		"telecom": "ContactPoint*",
+		"address": "Address*",
+		"gender": "code=male|female|other|unknown",
+		"birthDate": "date",
+		"photo": "Attachment*",
+		"qualification": "Practitioner.qualification*",
+		"communication": "CodeableConcept*",
+	},
+	"Practitioner.qualification": {
+		"identifier": "Identifier*",
+		"code": "CodeableConcept!",
+		"period": "Period",
+		"issuer": "Reference",
+	},
+	"Organization": {
+		"identifier": "Identifier*",
+		"active": "boolean",
+		"type": "CodeableConcept*",
+		"name": "string",
+		"alias": "string*",
+		"telecom": "ContactPoint*",
+		"address": "Address*",
+		"partOf": "Reference",
+		"contact": "Organization.contact*",
+		"endpoint": "Reference*",
+	},
+	"Organization.contact": {
+		"purpose": "CodeableConcept",
+		"name": "HumanName",
+		"telecom": "ContactPoint*",
+		"address": "Address",
+	},
+	"Location": {
+		"identifier": "Identifier*",
+		"status": "code=active|suspended|inactive",
+		"operationalStatus": "Coding",
+		"name": "string",
+		"alias": "string*",
+		"description": "string",
+		"mode": "code=instance|kind",
+		"type": "CodeableConcept*",
+		"telecom": "ContactPoint*",
+		"address": "Address",
+		"physicalType": "CodeableConcept",
+		"position": "Location.position",
+		"managingOrganization": "Reference",
+		"partOf": "Reference",
+		"hoursOfOperation": "Location.hoursOfOperation*",
+		"availabilityExceptions": "string",
+		"endpoint": "Reference*",
+	},
+	"Location.position": {
+		"longitude": "decimal!",
+		"latitude": "decimal!",
+		"altitude": "decimal",
+	},
+	"Location.hoursOfOperation": {
+		"daysOfWeek": "code*=mon|tue|wed|thu|fri|sat|sun",
+		"allDay": "boolean",
+		"openingTime": "time",
+		"closingTime": "time",
+	},
+	"Appointment": {
+		"identifier": "Identifier*",
+		"status": "code!=proposed|pending|booked|arrived|fulfilled|cancelled|noshow|entered-in-error|checked-in|waitlist",
+		"cancelationReason": "CodeableConcept",
+		"serviceCategory": "CodeableConcept*",
+		"serviceType": "CodeableConcept*",
+		"specialty": "CodeableConcept*",
+		"appointmentType": "CodeableConcept",
+		"reasonCode": "CodeableConcept*",
+		"reasonReference": "Reference*",
+		"priority": "unsignedInt",
+		"description": "string",
+		"supportingInformation": "Reference*",
+		"start": "instant",
+		"end": "instant",
+		"minutesDuration": "positiveInt",
+		"slot": "Reference*",
+		"created": "dateTime",
+		"comment": "string",
+		"patientInstruction": "string",
+		"basedOn": "Reference*",
+		"participant": "Appointment.participant*!",
+		"requestedPeriod": "Period*",
+	},
+	"Appointment.participant": {
+		"type": "CodeableConcept*",
+		"actor": "Reference",
+		"required": "code=required|optional|information-only",
+		"status": "code!=accepted|declined|tentative|needs-action",
+		"period": "Period",
+	},
+	"Meta": {
+		"versionId": "id",
+		"lastUpdated": "instant",
+		"source": "uri",
+		"profile": "canonical*",
+		"security": "Coding*",
+		"tag": "Coding*",
+	},
+	"Narrative": {
+		"status": "code!=generated|extensions|additional|empty",
+		"div": "string!",
+	},
+	"Identifier": {
+		"use": "code=usual|official|temp|secondary|old",
+		"type": "CodeableConcept",
+		"system": "uri",
+		"value": "string",
+		"period": "Period",
+		"assigner": "Reference",
+	},
+	"HumanName": {
+		"use": "code=usual|official|temp|nickname|anonymous|old|maiden",
+		"text": "string",
+		"family": "string",
+		"given": "string*",
+		"prefix": "string*",
+		"suffix": "string*",
+		"period": "Period",
+	},
+	"ContactPoint": {
+		"system": "code=phone|fax|email|pager|url|sms|other",
+		"value": "string",
+		"use": "code=home|work|temp|old|mobile",
+		"rank": "positiveInt",
+		"period": "Period",
+	},
+	"Address": {
+		"use": "code=home|work|temp|old|billing",
+		"type": "code=postal|physical|both",
+		"text": "string",
+		"line": "string*",
+		"city": "string",
+		"district": "string",
+		"state": "string",
+		"postalCode": "string",
+		"country": "string",
+		"period": "Period",
+	},
+	"Coding": {
+		"system": "uri",
+		"version": "string",
+		"code": "code",
+		"display": "string",
+		"userSelected": "boolean",
+	},
+	"CodeableConcept": {
+		"coding": "Coding*",
+		"text": "string",
+	},
+	"Reference": {
+		"reference": "string",
+		"type": "uri",
+		"identifier": "Identifier",
+		"display": "string",
+	},
+	"Period": {
+		"start": "dateTime",
+		"end": "dateTime",
+	},
+	"Attachment": {
+		"contentType": "code",
+		"language": "code",
+		"data": "base64Binary",
+		"url": "url",
+		"size": "unsignedInt",
+		"hash": "base64Binary",
+		"title": "string",
+		"creation": "dateTime",
+	},
+}
+
+// structures holds the parsed structureSpecs.
+var structures = parseStructures()
+
+func parseStructures() map[string]map[string]element {
+	parsed := make(map[string]map[string]element, len(structureSpecs))
+	for name, specs := range structureSpecs {
+		elements := make(map[string]element, len(specs))
+		for elementName, spec := range specs {
+			if choice, ok := strings.CutSuffix(elementName, "[x]"); ok {
+				for _, typ := range strings.Split(spec, ",") {
+					variant := choice + string(unicode.ToUpper(rune(typ[0]))) + typ[1:]
+					elements[variant] = element{typ: typ, choice: choice}
+				}
+				continue
+			}
+			elements[elementName] = parseElement(spec)
+		}
+		parsed[name] = elements
+	}
+	return parsed
+}
+
+func parseElement(spec string) element {
+	var e element
+	spec, codes, bound := strings.Cut(spec, "=")
+	if bound {
+		e.codes = strings.Split(codes, "|")
+	}
+	e.typ = strings.TrimRight(spec, "*!")
+	e.many = strings.Contains(spec, "*")
+	e.required = strings.Contains(spec, "!")
+	return e
+}
+
+// isResource reports whether the structure is a resource, rather than a
+// data type or backbone element.
+func isResource(name string) bool {
+	return slices.Contains(ResourceTypes, name)
+}
+
+// isBackbone reports whether the structure is a backbone element, which
+// may carry modifier extensions.
+func isBackbone(name string) bool {
+	return strings.Contains(name, ".")
+}
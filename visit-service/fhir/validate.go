@@ -0,0 +1,378 @@
+package fhir
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	idPattern	= regexp.MustCompile(`^[A-Za-z0-9\-.]{1,64}$`)
+	codePattern	= regexp.MustCompile(`^[^\s]+( [^\s]+)*$`)
+	datePattern	= regexp.MustCompile(`^\d{4}(-(0[1-9]|1[0-2])(-(0[1-9]|[12]\d|3[01]))?)?$`)
+	timePattern	= regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d:([0-5]\d|60)(\.\d+)?$`)
+)
+
+// Validate checks a JSON resource against its base R4 structure: the
+// resource type, known elements, cardinalities, primitive formats and
+// required bindings. An empty resourceType accepts any served resource.
+// The outcome lists every issue found, or a single informational issue
+// when the resource is valid.
+func Validate(data []byte, resourceType string) *OperationOutcome {
+	v := &validator{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return NewOperationOutcome("error", "structure", "Invalid JSON: "+err.Error())
+	}
+	if dec.More() {
+		return NewOperationOutcome("error", "structure", "Unexpected data after the resource")
+	}
+
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return NewOperationOutcome("error", "structure", "A resource must be a JSON object")
+	}
+
+	actualType, _ := obj["resourceType"].(string)
+	switch {
+	case actualType == "":
+		v.fail("required", "", "Missing resourceType")
+	case resourceType != "" && actualType != resourceType:
+		v.fail(
+			"invalid", "resourceType",
+			fmt.Sprintf("Expected a %s, got a %s", resourceType, actualType),
+		)
+	default:
+		v.resource(actualType, actualType, obj)
+	}
+
+	if len(v.issues) == 0 {
+		return NewOperationOutcome("information", "informational", "Validation successful")
+	}
+	return &OperationOutcome{ResourceType: "OperationOutcome", Issue: v.issues}
+}
+
+type validator struct {
+	issues []OperationOutcomeIssue
+}
+
+func (v *validator) fail(code, path, diagnostics string) {
+	issue := OperationOutcomeIssue{
+		Severity: "error",
+		Code: code,
+		Diagnostics: diagnostics,
+	}
+	if path != "" {
+		issue.Expression = []string{path}
+	}
+	v.issues = append(v.issues, issue)
+}
+
+func (v *validator) resource(path, resourceType string, obj map[string]any) {
+	if !isResource(resourceType) {
+		v.fail("not-supported", path, "Unsupported resource type "+resourceType)
+		return
+	}
+
+	v.object(path, resourceType, obj)
+
+	if resourceType == "Appointment" {
+		v.appointmentInvariants(path, obj)
+	}
+}
+
+// object validates the elements of a resource, data type or backbone
+// element.
+func (v *validator) object(path, structure string, obj map[string]any) {
+	if len(obj) == 0 {
+		v.fail("invariant", path, "Elements must have a value or children")
+		return
+	}
+
+	choices := make(map[string]string)
+	for name, value := range obj {
+		if name == "resourceType" && isResource(structure) {
+			continue
+		}
+
+		elementPath := path + "." + name
+		if base, ok := strings.CutPrefix(name, "_"); ok {
+			v.primitiveExtension(elementPath, structure, base, value)
+			continue
+		}
+
+		e, ok := lookupElement(structure, name)
+		if !ok {
+			v.fail("structure", elementPath, "Unknown element "+name)
+			continue
+		}
+
+		if e.choice != "" {
+			if other, seen := choices[e.choice]; seen {
+				v.fail(
+					"structure", elementPath,
+					fmt.Sprintf("Only one of %s and %s may be given", other, name),
+				)
+			}
+			choices[e.choice] = name
+		}
+
+		v.element(elementPath, e, value)
+	}
+
+	for name, e := range structures[structure] {
+		if e.required && obj[name] == nil {
+			v.fail("required", path+"."+name, "Missing required element "+name)
+		}
+	}
+}
+
+func lookupElement(structure, name string) (element, bool) {
+	if e, ok := structures[structure][name]; ok {
+		return e, true
+	}
+
+	switch {
+	case isResource(structure):
+		e, ok := structures["DomainResource"][name]
+		return e, ok
+	case name == "id":
+		return element{typ: "string"}, true
+	case name == "extension":
+		return element{typ: "Extension", many: true}, true
+	case name == "modifierExtension" && isBackbone(structure):
+		return element{typ: "Extension", many: true}, true
+	}
+	return element{}, false
+}
+
+func (v *validator) element(path string, e element, value any) {
+	if !e.many {
+		if _, isArray := value.([]any); isArray {
+			v.fail("structure", path, "Element does not repeat")
+			return
+		}
+		v.value(path, e, value)
+		return
+	}
+
+	items, isArray := value.([]any)
+	if !isArray {
+		v.fail("structure", path, "Repeating element must be an array")
+		return
+	}
+	if len(items) == 0 {
+		v.fail("invariant", path, "Arrays must not be empty")
+		return
+	}
+	for i, item := range items {
+		v.value(fmt.Sprintf("%s[%d]", path, i), e, item)
+	}
+}
+
+func (v *validator) value(path string, e element, value any) {
+	if value == nil {
+		v.fail("structure", path, "Elements must not be null")
+		return
+	}
+
+	if _, complex := structures[e.typ]; complex ||
+		e.typ == "Extension" || e.typ == "Resource" {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			v.fail("structure", path, "Expected a "+e.typ+" object")
+			return
+		}
+		switch e.typ {
+		case "Extension":
+			v.extension(path, obj)
+		case "Resource":
+			resourceType, _ := obj["resourceType"].(string)
+			v.resource(path, resourceType, obj)
+		default:
+			v.object(path, e.typ, obj)
+		}
+		return
+	}
+
+	if msg := checkPrimitive(e.typ, value); msg != "" {
+		v.fail("value", path, msg)
+		return
+	}
+	if len(e.codes) > 0 && !slices.Contains(e.codes, value.(string)) {
+		v.fail(
+			"code-invalid", path,
+			fmt.Sprintf(
+				"'%s' is not one of %s", value, strings.Join(e.codes, ", "),
+			),
+		)
+	}
+}
+
+// extension checks the shape of an extension. The value may be of any
+// type, so only its presence is checked.
+func (v *validator) extension(path string, obj map[string]any) {
+	url, _ := obj["url"].(string)
+	if url == "" {
+		v.fail("required", path+".url", "Extensions must have a url")
+	}
+
+	values := 0
+	for name, value := range obj {
+		switch {
+		case name == "url" || name == "id":
+		case name == "extension":
+			v.element(path+".extension", element{typ: "Extension", many: true}, value)
+		case strings.HasPrefix(name, "value"):
+			values++
+		default:
+			v.fail("structure", path+"."+name, "Unknown element "+name)
+		}
+	}
+	if values > 1 {
+		v.fail("structure", path, "Extensions may only have one value")
+	}
+}
+
+// primitiveExtension checks the `_element` companion carrying the id and
+// extensions of a primitive element.
+func (v *validator) primitiveExtension(path, structure, base string, value any) {
+	e, ok := lookupElement(structure, base)
+	if !ok || e.isComplex() {
+		v.fail("structure", path, "Unknown element "+base)
+		return
+	}
+
+	check := func(path string, value any) {
+		if value == nil {
+			return
+		}
+		obj, ok := value.(map[string]any)
+		if !ok {
+			v.fail("structure", path, "Expected an object")
+			return
+		}
+		v.object(path, "Element", obj)
+	}
+
+	if items, isArray := value.([]any); isArray && e.many {
+		for i, item := range items {
+			check(fmt.Sprintf("%s[%d]", path, i), item)
+		}
+		return
+	}
+	check(path, value)
+}
+
+func (e element) isComplex() bool {
+	_, complex := structures[e.typ]
+	return complex || e.typ == "Extension" || e.typ == "Resource"
+}
+
+// appointmentInvariants checks app-3: only proposed, cancelled and
+// waitlisted appointments may lack a start or an end.
+func (v *validator) appointmentInvariants(path string, obj map[string]any) {
+	status, _ := obj["status"].(string)
+	switch status {
+	case "proposed", "cancelled", "waitlist":
+		return
+	}
+
+	_, hasStart := obj["start"]
+	_, hasEnd := obj["end"]
+	if !hasStart || !hasEnd {
+		v.fail(
+			"invariant", path,
+			"Only proposed or cancelled appointments can be missing start/end dates",
+		)
+	}
+}
+
+// checkPrimitive returns why the value is not a valid primitive of the
+// type, or an empty string.
+func checkPrimitive(typ string, value any) string {
+	if typ == "boolean" {
+		if _, ok := value.(bool); !ok {
+			return "Expected a boolean"
+		}
+		return ""
+	}
+
+	if typ == "integer" || typ == "positiveInt" ||
+		typ == "unsignedInt" || typ == "decimal" {
+		n, ok := value.(json.Number)
+		if !ok {
+			return "Expected a number"
+		}
+		if typ == "decimal" {
+			if _, err := n.Float64(); err != nil {
+				return "Expected a decimal"
+			}
+			return ""
+		}
+		i, err := strconv.ParseInt(string(n), 10, 32)
+		if err != nil {
+			return "Expected a 32-bit integer"
+		}
+		if typ == "positiveInt" && i < 1 {
+			return "Expected a positive integer"
+		}
+		if typ == "unsignedInt" && i < 0 {
+			return "Expected a non-negative integer"
+		}
+		return ""
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "Expected a string"
+	}
+	if strings.TrimSpace(s) == "" {
+		return "Strings must not be empty"
+	}
+
+	switch typ {
+	case "id":
+		if !idPattern.MatchString(s) {
+			return "Invalid id"
+		}
+	case "code":
+		if !codePattern.MatchString(s) {
+			return "Invalid code"
+		}
+	case "uri", "url", "canonical":
+		if strings.ContainsAny(s, " \t\n") {
+			return "URIs must not contain whitespace"
+		}
+	case "date":
+		if !datePattern.MatchString(s) {
+			return "Invalid date"
+		}
+	case "dateTime":
+		if _, _, ok := parseDateRange(s); !ok {
+			return "Invalid dateTime"
+		}
+	case "instant":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return "Invalid instant"
+		}
+	case "time":
+		if !timePattern.MatchString(s) {
+			return "Invalid time"
+		}
+	case "base64Binary":
+		if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+			return "Invalid base64"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,194 @@
+// Package hl7 reads HL7 v2 messages in the pipe-delimited encoding.
+package hl7
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Delimiters are the encoding characters declared in MSH-1 and MSH-2.
+type Delimiters struct {
+	Field		byte
+	Component	byte
+	Repetition	byte
+	Escape		byte
+	Subcomponent	byte
+}
+
+// Segment is one line of a message. Line is its position in the parsed
+// data, starting at 1.
+type Segment struct {
+	Name	string
+	Line	int
+	fields	[]string
+	delims	Delimiters
+}
+
+// Field returns the raw content of a field, numbered as in the HL7
+// specification, or an empty string when the segment is shorter.
+func (s *Segment) Field(n int) string {
+	if n <= 0 || n >= len(s.fields) {
+		return ""
+	}
+	return s.fields[n]
+}
+
+// Component returns a component of the first repetition of a field, with
+// escape sequences resolved. Components are numbered from 1.
+func (s *Segment) Component(field, component int) string {
+	value := s.Field(field)
+	if s.Name == "MSH" && field <= 2 {
+		return value
+	}
+
+	value, _, _ = strings.Cut(value, string(s.delims.Repetition))
+	components := strings.Split(value, string(s.delims.Component))
+	if component <= 0 || component > len(components) {
+		return ""
+	}
+
+	value, _, _ = strings.Cut(components[component-1], string(s.delims.Subcomponent))
+	return s.unescape(value)
+}
+
+// Value returns the first component of a field.
+func (s *Segment) Value(field int) string {
+	return s.Component(field, 1)
+}
+
+func (s *Segment) unescape(value string) string {
+	escape := s.delims.Escape
+	if escape == 0 || strings.IndexByte(value, escape) < 0 {
+		return value
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(value, escape)
+		if start < 0 {
+			b.WriteString(value)
+			return b.String()
+		}
+		end := strings.IndexByte(value[start+1:], escape)
+		if end < 0 {
+			b.WriteString(value)
+			return b.String()
+		}
+		end += start + 1
+
+		b.WriteString(value[:start])
+		switch value[start+1 : end] {
+		case "F":
+			b.WriteByte(s.delims.Field)
+		case "S":
+			b.WriteByte(s.delims.Component)
+		case "T":
+			b.WriteByte(s.delims.Subcomponent)
+		case "R":
+			b.WriteByte(s.delims.Repetition)
+		case "E":
+			b.WriteByte(escape)
+		}
+		// Formatting and hexadecimal sequences are dropped.
+		value = value[end+1:]
+	}
+}
+
+// Message is a sequence of segments starting with an MSH header.
+type Message struct {
+	Segments []Segment
+}
+
+// Header returns the MSH segment.
+func (m *Message) Header() *Segment {
+	return &m.Segments[0]
+}
+
+// Type returns the message type and trigger event, e.g. "ORU^R01".
+func (m *Message) Type() string {
+	header := m.Header()
+	return header.Component(9, 1) + "^" + header.Component(9, 2)
+}
+
+// ControlID returns MSH-10, which identifies the message at its sender.
+func (m *Message) ControlID() string {
+	return m.Header().Value(10)
+}
+
+// Parse splits data into messages. Segments may be terminated by carriage
+// returns, line feeds or both. Batch and file header and trailer segments
+// are skipped.
+func Parse(data []byte) ([]Message, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\r")
+	text = strings.ReplaceAll(text, "\n", "\r")
+
+	var messages []Message
+	var delims Delimiters
+	for i, line := range strings.Split(text, "\r") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if len(line) < 3 {
+			return nil, fmt.Errorf("line %d: invalid segment", i+1)
+		}
+
+		name := line[:3]
+		switch name {
+		case "FHS", "FTS", "BHS", "BTS":
+			continue
+		case "MSH":
+			d, err := parseDelimiters(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			delims = d
+			messages = append(messages, Message{})
+		}
+
+		if len(messages) == 0 {
+			return nil, fmt.Errorf("line %d: expected an MSH segment", i+1)
+		}
+
+		fields := strings.Split(line, string(delims.Field))
+		if name == "MSH" {
+			// MSH-1 is the field separator itself.
+			fields = append(
+				[]string{"MSH", string(delims.Field)}, fields[1:]...,
+			)
+		}
+
+		msg := &messages[len(messages)-1]
+		msg.Segments = append(msg.Segments, Segment{
+			Name: fields[0],
+			Line: i + 1,
+			fields: fields,
+			delims: delims,
+		})
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages found")
+	}
+
+	return messages, nil
+}
+
+func parseDelimiters(header string) (Delimiters, error) {
+	if len(header) < 8 {
+		return Delimiters{}, fmt.Errorf("invalid MSH segment")
+	}
+
+	d := Delimiters{
+		Field: header[3],
+		Component: header[4],
+		Repetition: header[5],
+		Escape: header[6],
+		Subcomponent: header[7],
+	}
+	if d.Subcomponent == d.Field {
+		// Only three encoding characters were declared.
+		d.Subcomponent = 0
+	}
+	return d, nil
+}
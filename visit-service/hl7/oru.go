@@ -0,0 +1,187 @@
+package hl7
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LOINCSystem is the coding system of LOINC codes in coded elements.
+const LOINCSystem = "LN"
+
+// ORU is an unsolicited observation result message (ORU^R01).
+type ORU struct {
+	ControlID	string
+	// PatientID is the first identifier listed in PID-3.
+	PatientID	string
+	Observations	[]Observation
+}
+
+// Observation is an OBX segment, along with the time of its order when it
+// does not carry its own.
+type Observation struct {
+	Line		int
+	SetID		string
+	Code		string
+	Name		string
+	CodingSystem	string
+	ValueType	string
+	Value		string
+	Units		string
+	ReferenceRange	string
+	AbnormalFlag	string
+	Status		string
+	// ObservedAt is zero when neither the OBX nor its OBR tell the time.
+	ObservedAt	time.Time
+}
+
+// ParseORU reads the observations of an ORU^R01 message. Timestamps
+// without an offset are taken to be in loc.
+func ParseORU(msg *Message, loc *time.Location) (*ORU, error) {
+	if msg.Type() != "ORU^R01" {
+		return nil, fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	oru := &ORU{ControlID: msg.ControlID()}
+	if oru.ControlID == "" {
+		return nil, fmt.Errorf("missing message control ID")
+	}
+
+	var orderTime time.Time
+	for i := range msg.Segments {
+		seg := &msg.Segments[i]
+		switch seg.Name {
+		case "PID":
+			if oru.PatientID != "" {
+				return nil, fmt.Errorf("line %d: more than one patient", seg.Line)
+			}
+			oru.PatientID = seg.Value(3)
+		case "OBR":
+			orderTime = time.Time{}
+			if value := seg.Value(7); value != "" {
+				t, err := ParseTime(value, loc)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", seg.Line, err)
+				}
+				orderTime = t
+			}
+		case "OBX":
+			obs, err := parseObservation(seg, orderTime, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", seg.Line, err)
+			}
+			oru.Observations = append(oru.Observations, *obs)
+		}
+	}
+
+	if oru.PatientID == "" {
+		return nil, fmt.Errorf("missing patient identifier")
+	}
+
+	return oru, nil
+}
+
+func parseObservation(
+	seg *Segment, orderTime time.Time, loc *time.Location,
+) (*Observation, error) {
+	obs := &Observation{
+		Line: seg.Line,
+		SetID: seg.Value(1),
+		ValueType: seg.Value(2),
+		Code: seg.Component(3, 1),
+		Name: seg.Component(3, 2),
+		CodingSystem: seg.Component(3, 3),
+		Value: seg.Value(5),
+		Units: seg.Value(6),
+		ReferenceRange: seg.Value(7),
+		AbnormalFlag: seg.Value(8),
+		Status: seg.Value(11),
+		ObservedAt: orderTime,
+	}
+
+	// Prefer the alternate identifier when only it is a LOINC code.
+	if obs.CodingSystem != LOINCSystem && seg.Component(3, 6) == LOINCSystem {
+		obs.Code = seg.Component(3, 4)
+		obs.Name = seg.Component(3, 5)
+		obs.CodingSystem = LOINCSystem
+	}
+
+	if value := seg.Value(14); value != "" {
+		t, err := ParseTime(value, loc)
+		if err != nil {
+			return nil, err
+		}
+		obs.ObservedAt = t
+	}
+
+	return obs, nil
+}
+
+// ParseTime parses an HL7 timestamp, YYYY[MM[DD[HH[MM[SS[.S+]]]]]][+/-ZZZZ].
+func ParseTime(value string, loc *time.Location) (time.Time, error) {
+	layout := "20060102150405"
+
+	digits, offset := value, ""
+	if i := strings.LastIndexAny(value, "+-"); i > 0 {
+		digits, offset = value[:i], value[i:]
+	}
+	whole, _, _ := strings.Cut(digits, ".")
+	if n := len(whole); n < 4 || n > len(layout) || n%2 != 0 {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+	layout = layout[:len(whole)]
+
+	if offset == "" {
+		t, err := time.ParseInLocation(layout, digits, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+		}
+		return t, nil
+	}
+
+	t, err := time.Parse(layout+"-0700", digits+offset)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+	return t, nil
+}
+
+// ParseRange parses a reference range such as "3.5-5.1", "<5.0" or
+// ">=1.0". An empty range has no limits.
+func ParseRange(value string) (low, high *float64, ok bool) {
+	value = strings.ReplaceAll(value, " ", "")
+	if value == "" {
+		return nil, nil, true
+	}
+
+	for _, prefix := range []string{"<=", ">=", "<", ">"} {
+		rest, found := strings.CutPrefix(value, prefix)
+		if !found {
+			continue
+		}
+		limit, err := strconv.ParseFloat(rest, 64)
+		if err != nil {
+			return nil, nil, false
+		}
+		if prefix[0] == '<' {
+			return nil, &limit, true
+		}
+		return &limit, nil, true
+	}
+
+	// The separator is the first dash which is not a sign.
+	i := strings.Index(value[1:], "-")
+	if i < 0 {
+		return nil, nil, false
+	}
+	lowValue, err := strconv.ParseFloat(value[:i+1], 64)
+	if err != nil {
+		return nil, nil, false
+	}
+	highValue, err := strconv.ParseFloat(value[i+2:], 64)
+	if err != nil || highValue < lowValue {
+		return nil, nil, false
+	}
+	return &lowValue, &highValue, true
+}
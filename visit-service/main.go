@@ -11,20 +11,33 @@ import (
 	"fmt"
 	"os"
 	"log"
+	"strconv"
+	"time"
 
 	"igaku/visit-service/clients"
 	"igaku/visit-service/controllers"
+	"igaku/visit-service/dictionaries"
 	"igaku/visit-service/docs"
 	"igaku/visit-service/repositories"
+	"igaku/visit-service/schedulers"
 	"igaku/visit-service/services"
+	"igaku/visit-service/storage"
 	"igaku/visit-service/utils"
 	commonsUtils "igaku/commons/utils"
 )
 
+// osmTypeResolutionLockKey is the key of the advisory lock held while
+// resolving OSM types.
+const osmTypeResolutionLockKey int64 = 0x4f534d54
+
 // @title		Igaku Visit API
 // @version		0.0.1
 // @host		localhost:4000
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
 func main() {
 	dsn := fmt.Sprintf(
 		"host=%s "+
@@ -68,14 +81,287 @@ func main() {
 	}
 	defer geoClient.Shutdown()
 
+	userClient, err := clients.NewUserClient(amqpURI)
+	if err != nil {
+		log.Fatalf("Failed to create a user client: %v", err)
+	}
+	defer userClient.Shutdown()
+
+	mailClient, err := clients.NewMailClient(amqpURI)
+	if err != nil {
+		log.Fatalf("Failed to create a mail client: %v", err)
+	}
+	defer mailClient.Shutdown()
+
+	eventClient, err := clients.NewEventClient(amqpURI)
+	if err != nil {
+		log.Fatalf("Failed to create an event client: %v", err)
+	}
+	defer eventClient.Shutdown()
+
 	healthController := controllers.NewHealthController()
 	healthController.RegisterRoutes(router)
 
 	orgRepo := repositories.NewGormOrganizationRepository(db)
-	orgService := services.NewOrganizationService(orgRepo)
+	orgService := services.NewOrganizationService(orgRepo, geoClient)
 	orgController := controllers.NewOrganizationController(orgService)
 	orgController.RegisterRoutes(router)
 
+	// The lookups go to a rate-limited provider, so only one replica
+	// resolves OSM types at a time.
+	osmTypeScheduler := schedulers.NewScheduler(
+		"OSM type resolution",
+		schedulers.Exclusive(
+			"OSM type resolution",
+			repositories.NewGormAdvisoryLock(db),
+			osmTypeResolutionLockKey,
+			orgService.ResolveOsmTypes,
+		),
+		10*time.Minute,
+	)
+	osmTypeScheduler.Start()
+	defer osmTypeScheduler.Shutdown()
+
+	specialtyRepo := repositories.NewGormDoctorSpecialtyRepository(db)
+	specialtyService := services.NewDoctorSpecialtyService(
+		specialtyRepo, userClient,
+	)
+	specialtyController := controllers.NewDoctorSpecialtyController(
+		specialtyService,
+	)
+	specialtyController.RegisterRoutes(router)
+
+	reminderRepo := repositories.NewGormReminderRepository(db)
+	reminderService := services.NewReminderService(
+		reminderRepo, userClient, mailClient, os.Getenv("SMTP_FROM"),
+	)
+	reminderController := controllers.NewReminderController(reminderService)
+	reminderController.RegisterRoutes(router)
+
+	reminderInterval, err := strconv.Atoi(
+		os.Getenv("REMINDER_POLL_INTERVAL_IN_SECONDS"),
+	)
+	if err != nil || reminderInterval <= 0 {
+		reminderInterval = 60
+	}
+	reminderScheduler := schedulers.NewScheduler(
+		"reminder",
+		reminderService.SendDueReminders,
+		time.Duration(reminderInterval)*time.Second,
+	)
+	reminderScheduler.Start()
+	defer reminderScheduler.Shutdown()
+
+	offerHold, err := strconv.Atoi(
+		os.Getenv("WAITLIST_OFFER_HOLD_IN_MINUTES"),
+	)
+	if err != nil || offerHold <= 0 {
+		offerHold = 30
+	}
+	waitlistRepo := repositories.NewGormWaitlistRepository(db)
+	waitlistService := services.NewWaitlistService(
+		waitlistRepo, userClient, mailClient, eventClient,
+		time.Duration(offerHold)*time.Minute, os.Getenv("SMTP_FROM"),
+	)
+	waitlistController := controllers.NewWaitlistController(waitlistService)
+	waitlistController.RegisterRoutes(router)
+
+	offerScheduler := schedulers.NewScheduler(
+		"waitlist offer expiry",
+		waitlistService.ExpireOffers,
+		time.Duration(reminderInterval)*time.Second,
+	)
+	offerScheduler.Start()
+	defer offerScheduler.Shutdown()
+
+	apptRepo := repositories.NewGormAppointmentRepository(db)
+	slotRepo := repositories.NewGormSlotRepository(db)
+	homeAddressRepo := repositories.NewGormHomeAddressRepository(db)
+	apptService := services.NewAppointmentService(
+		apptRepo, slotRepo, orgRepo, waitlistService, eventClient,
+		homeAddressRepo, geoClient,
+	)
+	apptController := controllers.NewAppointmentController(apptService)
+	apptController.RegisterRoutes(router)
+
+	homeAddressService := services.NewHomeAddressService(
+		homeAddressRepo, geoClient,
+	)
+	homeAddressController := controllers.NewHomeAddressController(
+		homeAddressService,
+	)
+	homeAddressController.RegisterRoutes(router)
+
+	careTeamRepo := repositories.NewGormCareTeamRepository(db)
+	careTeamService := services.NewCareTeamService(
+		careTeamRepo, orgRepo, userClient,
+	)
+	careTeamController := controllers.NewCareTeamController(careTeamService)
+	careTeamController.RegisterRoutes(router)
+
+	consentRepo := repositories.NewGormConsentRepository(db)
+	consentService := services.NewConsentService(consentRepo, orgRepo, userClient)
+	consentController := controllers.NewConsentController(consentService)
+	consentController.RegisterRoutes(router)
+
+	emergencyTTL, err := strconv.Atoi(
+		os.Getenv("EMERGENCY_ACCESS_TTL_IN_MINUTES"),
+	)
+	if err != nil || emergencyTTL <= 0 {
+		emergencyTTL = 60
+	}
+	emergencyRepo := repositories.NewGormEmergencyAccessRepository(db)
+	emergencyService := services.NewEmergencyAccessService(
+		emergencyRepo, userClient, mailClient,
+		time.Duration(emergencyTTL)*time.Minute, os.Getenv("SMTP_FROM"),
+	)
+	emergencyController := controllers.NewEmergencyAccessController(
+		emergencyService,
+	)
+	emergencyController.RegisterRoutes(router)
+
+	access := services.NewPatientAccess(
+		careTeamRepo, consentRepo, slotRepo, emergencyRepo,
+	)
+
+	icd10, err := dictionaries.LoadICD10Dictionary(
+		"./visit-service/resources/icd10.csv",
+	)
+	if err != nil {
+		log.Fatalf("Failed to load ICD-10 dictionary: %v", err)
+	}
+
+	encounterRepo := repositories.NewGormEncounterRepository(db)
+	encounterService := services.NewEncounterService(
+		encounterRepo, apptRepo, access, userClient, icd10,
+	)
+	encounterController := controllers.NewEncounterController(encounterService)
+	encounterController.RegisterRoutes(router)
+
+	drugs, err := dictionaries.LoadDrugDictionary(
+		"./visit-service/resources/drugs.csv",
+	)
+	if err != nil {
+		log.Fatalf("Failed to load drug dictionary: %v", err)
+	}
+
+	interactions, err := dictionaries.LoadInteractionDataset(
+		"./visit-service/resources/interactions.csv",
+		"./visit-service/resources/allergy_classes.csv",
+	)
+	if err != nil {
+		log.Fatalf("Failed to load interaction dataset: %v", err)
+	}
+
+	guidelines, err := dictionaries.LoadPharmacogenomicGuidelines(
+		"./visit-service/resources/pgx_guidelines.csv",
+	)
+	if err != nil {
+		log.Fatalf("Failed to load pharmacogenomic guidelines: %v", err)
+	}
+
+	prescriptionRepo := repositories.NewGormPrescriptionRepository(db)
+	allergyRepo := repositories.NewGormAllergyRepository(db)
+	genotypeRepo := repositories.NewGormGenotypeRepository(db)
+	interactionChecker := services.NewInteractionChecker(
+		prescriptionRepo, allergyRepo, genotypeRepo, interactions, guidelines,
+	)
+	prescriptionService := services.NewPrescriptionService(
+		prescriptionRepo, encounterRepo, access, userClient, drugs,
+		interactionChecker,
+	)
+	prescriptionController := controllers.NewPrescriptionController(
+		prescriptionService,
+	)
+	prescriptionController.RegisterRoutes(router)
+
+	healthRecordRepo := repositories.NewGormHealthRecordRepository(db)
+	healthRecordService := services.NewHealthRecordService(
+		healthRecordRepo, access, encounterRepo, prescriptionRepo, icd10,
+	)
+	healthRecordController := controllers.NewHealthRecordController(
+		healthRecordService,
+	)
+	healthRecordController.RegisterRoutes(router)
+
+	labs, err := dictionaries.LoadLabDictionary(
+		"./visit-service/resources/lab_analytes.csv",
+		"./visit-service/resources/lab_units.csv",
+	)
+	if err != nil {
+		log.Fatalf("Failed to load lab dictionary: %v", err)
+	}
+
+	labResultRepo := repositories.NewGormLabResultRepository(db)
+	labResultService := services.NewLabResultService(
+		labResultRepo, access, userClient, eventClient, labs, time.Local,
+	)
+	labResultController := controllers.NewLabResultController(labResultService)
+	labResultController.RegisterRoutes(router)
+
+	fhirService := services.NewFHIRService(
+		userClient, orgRepo, apptRepo, careTeamRepo,
+	)
+	fhirController := controllers.NewFHIRController(fhirService)
+	fhirController.RegisterRoutes(router)
+
+	documentPath := os.Getenv("DOCUMENT_STORAGE_PATH")
+	if documentPath == "" {
+		documentPath = "./data/documents"
+	}
+	blobStore, err := storage.New(storage.Config{
+		Backend: storage.Backend(os.Getenv("DOCUMENT_STORAGE")),
+		LocalPath: documentPath,
+		S3: storage.S3Config{
+			Endpoint: os.Getenv("S3_ENDPOINT"),
+			Region: os.Getenv("S3_REGION"),
+			Bucket: os.Getenv("S3_BUCKET"),
+			AccessKeyID: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create document storage: %v", err)
+	}
+
+	linkTTL, err := strconv.Atoi(
+		os.Getenv("DOCUMENT_LINK_TTL_IN_SECONDS"),
+	)
+	if err != nil || linkTTL <= 0 {
+		linkTTL = 300
+	}
+	// Without a secret of their own, links are signed with a key derived
+	// from the JWT secret.
+	linkSecret := os.Getenv("DOCUMENT_LINK_SECRET")
+	if linkSecret == "" {
+		linkSecret = os.Getenv("SECRET_KEY")
+	}
+	documentRepo := repositories.NewGormDocumentRepository(db)
+	documentService := services.NewDocumentService(
+		documentRepo, access, encounterRepo, blobStore,
+		[]byte(linkSecret), time.Duration(linkTTL)*time.Second,
+	)
+	documentController := controllers.NewDocumentController(documentService)
+	documentController.RegisterRoutes(router)
+
+	conversationRepo := repositories.NewGormConversationRepository(db)
+	conversationService := services.NewConversationService(
+		conversationRepo, apptRepo, careTeamRepo, documentRepo, access,
+		userClient, mailClient, eventClient, os.Getenv("SMTP_FROM"),
+	)
+	conversationController := controllers.NewConversationController(
+		conversationService,
+	)
+	conversationController.RegisterRoutes(router)
+
+	prescriptionScheduler := schedulers.NewScheduler(
+		"prescription expiry",
+		prescriptionService.ExpirePrescriptions,
+		time.Duration(reminderInterval)*time.Second,
+	)
+	prescriptionScheduler.Start()
+	defer prescriptionScheduler.Shutdown()
+
 	router.GET(
 		"/visit/swagger/*any",
 		ginSwagger.WrapHandler(swaggerFiles.Handler),
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	"igaku/commons/dtos"
+)
+
+type AppointmentStatus string
+
+const (
+	Booked		AppointmentStatus = "booked"
+	Cancelled	AppointmentStatus = "cancelled"
+)
+
+type Appointment struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DoctorID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	OrganizationID	uuid.UUID		`gorm:"type:uuid;not null" json:"organization_id" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
+	Organization	Organization		`json:"-"`
+	SlotID		*uuid.UUID		`gorm:"type:uuid" json:"slot_id,omitempty" example:"3f1e2d4c-5b6a-4978-8c9d-0e1f2a3b4c5d"`
+	Slot		*Slot			`json:"-"`
+	StartsAt	time.Time		`gorm:"not null;index" json:"starts_at" example:"2025-06-02T09:30:00Z"`
+	Status		AppointmentStatus	`gorm:"not null;default:booked" json:"status" example:"booked"`
+	// TravelEstimates estimate the travel from the home address of the
+	// patient to the organization. They are given in booking
+	// confirmations only, and only to patients with a home address.
+	TravelEstimates	[]dtos.RouteEstimate	`gorm:"-" json:"travel_estimates,omitempty"`
+}
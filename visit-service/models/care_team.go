@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type CareTeamRole string
+
+const (
+	// PrimaryDoctor is the doctor mainly responsible for the patient. A
+	// patient has at most one.
+	PrimaryDoctor	CareTeamRole = "primary"
+	CareTeamMember	CareTeamRole = "member"
+)
+
+type CareAssignmentSource string
+
+const (
+	// AppointmentSource assignments are made when the patient books an
+	// appointment with the doctor, and end when the last one is cancelled.
+	AppointmentSource	CareAssignmentSource = "appointment"
+	ManualSource		CareAssignmentSource = "manual"
+)
+
+// CareAssignment places a doctor on the care team of a patient at one of
+// the organizations the doctor works at. Doctors only have access to the
+// patients whose care team they are on.
+type CareAssignment struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"6d7e8f9a-0b1c-4d2e-8f3a-4b5c6d7e8f9a"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;uniqueIndex:idx_care_assignments_active,where:ended_at IS NULL" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DoctorID	uuid.UUID		`gorm:"type:uuid;not null;uniqueIndex:idx_care_assignments_active,where:ended_at IS NULL;index" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	OrganizationID	uuid.UUID		`gorm:"type:uuid;not null;uniqueIndex:idx_care_assignments_active,where:ended_at IS NULL" json:"organization_id" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
+	Organization	Organization		`json:"-"`
+	Role		CareTeamRole		`gorm:"not null;default:member" json:"role" example:"member"`
+	Source		CareAssignmentSource	`gorm:"not null" json:"source" example:"appointment"`
+	// AssignedBy is the admin who made a manual assignment.
+	AssignedBy	*uuid.UUID		`gorm:"type:uuid" json:"assigned_by,omitempty" example:"7f8e9d0c-1b2a-4c3d-9e4f-5a6b7c8d9e0f"`
+	AssignedAt	time.Time		`gorm:"not null" json:"assigned_at" example:"2025-06-01T09:00:00Z"`
+	EndedAt		*time.Time		`json:"ended_at,omitempty" example:"2025-09-01T09:00:00Z"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+// ConsentCategory is a category of clinical data covered by a consent.
+type ConsentCategory string
+
+const (
+	RecordsCategory		ConsentCategory = "records"
+	GenomicsCategory	ConsentCategory = "genomics"
+	LabResultsCategory	ConsentCategory = "lab_results"
+	DocumentsCategory	ConsentCategory = "documents"
+	EncountersCategory	ConsentCategory = "encounters"
+	PrescriptionsCategory	ConsentCategory = "prescriptions"
+)
+
+var ConsentCategories = []ConsentCategory{
+	RecordsCategory,
+	GenomicsCategory,
+	LabResultsCategory,
+	DocumentsCategory,
+	EncountersCategory,
+	PrescriptionsCategory,
+}
+
+type GranteeType string
+
+const (
+	DoctorGrantee		GranteeType = "doctor"
+	OrganizationGrantee	GranteeType = "organization"
+)
+
+type ConsentDecision string
+
+const (
+	// Permit grants access to doctors who would otherwise have none.
+	Permit	ConsentDecision = "permit"
+	// Deny withdraws access, including that of treating doctors.
+	Deny	ConsentDecision = "deny"
+)
+
+// Consent is a patient's decision about who may see which categories of
+// their clinical data, and for how long. A consent given to an
+// organization applies to every doctor working there.
+type Consent struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	GranteeType	GranteeType		`gorm:"not null" json:"grantee_type" example:"doctor"`
+	GranteeID	uuid.UUID		`gorm:"type:uuid;not null" json:"grantee_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	Decision	ConsentDecision		`gorm:"not null;default:permit" json:"decision" example:"permit"`
+	Categories	[]ConsentCategory	`gorm:"type:jsonb;serializer:json;not null" json:"categories" example:"records,lab_results"`
+	ValidFrom	time.Time		`gorm:"not null" json:"valid_from" example:"2025-06-01T00:00:00Z"`
+	// ValidUntil is nil for consents given until revoked.
+	ValidUntil	*time.Time		`json:"valid_until,omitempty" example:"2025-12-01T00:00:00Z"`
+	CreatedAt	time.Time		`gorm:"not null" json:"created_at" example:"2025-06-01T09:00:00Z"`
+	RevokedAt	*time.Time		`json:"revoked_at,omitempty" example:"2025-07-01T09:00:00Z"`
+}
+
+// ActiveAt reports whether the consent is in force at the given time.
+func (c *Consent) ActiveAt(t time.Time) bool {
+	return c.RevokedAt == nil &&
+		!c.ValidFrom.After(t) &&
+		(c.ValidUntil == nil || c.ValidUntil.After(t))
+}
+
+type ConsentAction string
+
+const (
+	ConsentGranted	ConsentAction = "granted"
+	ConsentRevoked	ConsentAction = "revoked"
+)
+
+// ConsentEvent is an entry of the audit history of a patient's consents.
+// It copies the consent as it was when the event occurred.
+type ConsentEvent struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"`
+	ConsentID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"consent_id" example:"1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	Action		ConsentAction		`gorm:"not null" json:"action" example:"granted"`
+	ActorID		uuid.UUID		`gorm:"type:uuid;not null" json:"actor_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	OccurredAt	time.Time		`gorm:"not null" json:"occurred_at" example:"2025-06-01T09:00:00Z"`
+	GranteeType	GranteeType		`gorm:"not null" json:"grantee_type" example:"doctor"`
+	GranteeID	uuid.UUID		`gorm:"type:uuid;not null" json:"grantee_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	Decision	ConsentDecision		`gorm:"not null" json:"decision" example:"permit"`
+	Categories	[]ConsentCategory	`gorm:"type:jsonb;serializer:json;not null" json:"categories" example:"records,lab_results"`
+	ValidFrom	time.Time		`gorm:"not null" json:"valid_from" example:"2025-06-01T00:00:00Z"`
+	ValidUntil	*time.Time		`json:"valid_until,omitempty" example:"2025-12-01T00:00:00Z"`
+}
+
+// NewConsentEvent records an action on the consent.
+func NewConsentEvent(
+	c *Consent, action ConsentAction, actorID uuid.UUID, at time.Time,
+) *ConsentEvent {
+	return &ConsentEvent{
+		ID: uuid.New(),
+		ConsentID: c.ID,
+		PatientID: c.PatientID,
+		Action: action,
+		ActorID: actorID,
+		OccurredAt: at,
+		GranteeType: c.GranteeType,
+		GranteeID: c.GranteeID,
+		Decision: c.Decision,
+		Categories: c.Categories,
+		ValidFrom: c.ValidFrom,
+		ValidUntil: c.ValidUntil,
+	}
+}
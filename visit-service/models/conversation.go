@@ -0,0 +1,50 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+// Conversation is a thread of messages between a patient and a doctor who
+// treats them, either as a member of their care team or at an appointment.
+type Conversation struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	PatientID	uuid.UUID	`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DoctorID	uuid.UUID	`gorm:"type:uuid;not null;index" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	// AppointmentID is set for conversations about an appointment.
+	AppointmentID	*uuid.UUID	`gorm:"type:uuid;index" json:"appointment_id,omitempty" example:"5d8c7a8e-3b1f-4e2a-9c6d-8f7e6d5c4b3a"`
+	Subject		string		`gorm:"not null" json:"subject" example:"Blood pressure readings"`
+	CreatedAt	time.Time	`gorm:"not null" json:"created_at" example:"2025-06-01T09:00:00Z"`
+	LastMessageAt	time.Time	`gorm:"not null;index" json:"last_message_at" example:"2025-06-01T09:30:00Z"`
+	// Unread is the number of messages the user listing the conversations
+	// has not read yet.
+	Unread		int64		`gorm:"->;-:migration" json:"unread" example:"2"`
+}
+
+// Participant reports whether the user takes part in the conversation.
+func (c *Conversation) Participant(userID uuid.UUID) bool {
+	return c.PatientID == userID || c.DoctorID == userID
+}
+
+// Recipient returns the participant who receives the messages of the
+// sender.
+func (c *Conversation) Recipient(senderID uuid.UUID) uuid.UUID {
+	if senderID == c.PatientID {
+		return c.DoctorID
+	}
+	return c.PatientID
+}
+
+// Message is a message of a conversation. A document of the patient may
+// be attached to it.
+type Message struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a"`
+	ConversationID	uuid.UUID	`gorm:"type:uuid;not null;index" json:"conversation_id" example:"0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	SenderID	uuid.UUID	`gorm:"type:uuid;not null" json:"sender_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	Body		string		`gorm:"type:text;not null" json:"body" example:"My readings from this week are attached."`
+	DocumentID	*uuid.UUID	`gorm:"type:uuid" json:"document_id,omitempty" example:"9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"`
+	SentAt		time.Time	`gorm:"not null" json:"sent_at" example:"2025-06-01T09:30:00Z"`
+	// ReadAt is when the recipient read the message.
+	ReadAt		*time.Time	`json:"read_at,omitempty" example:"2025-06-01T10:00:00Z"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// DoctorSpecialty records one of the specialties of a doctor. Specialties
+// are stored in lower case.
+type DoctorSpecialty struct {
+	DoctorID	uuid.UUID	`gorm:"type:uuid;primary_key;" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	Specialty	string		`gorm:"primary_key;index;check:specialty <> ''" json:"specialty" example:"cardiology"`
+}
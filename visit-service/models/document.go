@@ -0,0 +1,55 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+// MaxDocumentSize is the largest document accepted for upload.
+const MaxDocumentSize = 25 << 20
+
+// DocumentContentTypes are the types of content accepted for upload, as
+// detected from the content itself.
+var DocumentContentTypes = []string{
+	"application/pdf",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+}
+
+type DocumentCategory string
+
+const (
+	ReferralDocument	DocumentCategory = "referral"
+	ScanDocument		DocumentCategory = "scan"
+	LabReportDocument	DocumentCategory = "lab_report"
+	ImagingDocument		DocumentCategory = "imaging"
+	OtherDocument		DocumentCategory = "other"
+)
+
+var DocumentCategories = []DocumentCategory{
+	ReferralDocument,
+	ScanDocument,
+	LabReportDocument,
+	ImagingDocument,
+	OtherDocument,
+}
+
+// Document is a file attached to the medical record of a patient. Its
+// content is kept in a blob store under StorageKey.
+type Document struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	EncounterID	*uuid.UUID		`gorm:"type:uuid;index" json:"encounter_id,omitempty" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	Title		string			`gorm:"not null" json:"title" example:"Cardiology referral"`
+	Category	DocumentCategory	`gorm:"not null;default:other" json:"category" example:"referral"`
+	FileName	string			`gorm:"not null" json:"file_name" example:"referral.pdf"`
+	ContentType	string			`gorm:"not null" json:"content_type" example:"application/pdf"`
+	Size		int64			`gorm:"not null" json:"size" example:"48213"`
+	// Checksum is the hex-encoded SHA-256 of the content.
+	Checksum	string			`gorm:"not null" json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	StorageKey	string			`gorm:"not null;uniqueIndex" json:"-"`
+	UploadedBy	uuid.UUID		`gorm:"type:uuid;not null" json:"uploaded_by" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	CreatedAt	time.Time		`gorm:"not null" json:"created_at" example:"2025-06-02T10:07:00Z"`
+}
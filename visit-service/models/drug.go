@@ -0,0 +1,11 @@
+package models
+
+// Drug is an entry of the drug dictionary. Name is the international
+// non-proprietary name of the active substance.
+type Drug struct {
+	ID		string	`json:"id" example:"atorvastatin-20-tab"`
+	Name		string	`json:"name" example:"Atorvastatin"`
+	ATCCode		string	`json:"atc_code" example:"C10AA05"`
+	Form		string	`json:"form" example:"tablet"`
+	Strength	string	`json:"strength" example:"20 mg"`
+}
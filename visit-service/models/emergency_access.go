@@ -0,0 +1,68 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+
+	commonsModels "igaku/commons/models"
+)
+
+// EmergencyRole is the role of the tokens issued for emergency access. It
+// is never assigned to a user: a doctor holding such a token may only read
+// the clinical data of the patient the access was granted to. Requests with
+// such a token are made under the ID of the access, not of the doctor.
+const EmergencyRole commonsModels.Role = "emergency"
+
+type EmergencyReviewStatus string
+
+const (
+	PendingReview	EmergencyReviewStatus = "pending"
+	// ApprovedReview marks the access as justified.
+	ApprovedReview	EmergencyReviewStatus = "approved"
+	// FlaggedReview marks the access as unjustified and ends it, if it is
+	// still in force.
+	FlaggedReview	EmergencyReviewStatus = "flagged"
+)
+
+var EmergencyReviewStatuses = []EmergencyReviewStatus{
+	PendingReview,
+	ApprovedReview,
+	FlaggedReview,
+}
+
+// EmergencyAccess is a time-boxed grant, requested by a doctor with a
+// justification, to read all of a patient's clinical data regardless of
+// their care team and consents. Every grant is reviewed by an admin
+// afterwards.
+type EmergencyAccess struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"8f9a0b1c-2d3e-4f5a-9b6c-7d8e9f0a1b2c"`
+	DoctorID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	Justification	string			`gorm:"not null" json:"justification" example:"Patient unconscious in the emergency room, allergies unknown"`
+	GrantedAt	time.Time		`gorm:"not null" json:"granted_at" example:"2025-06-01T09:00:00Z"`
+	ExpiresAt	time.Time		`gorm:"not null" json:"expires_at" example:"2025-06-01T10:00:00Z"`
+	RevokedAt	*time.Time		`json:"revoked_at,omitempty" example:"2025-06-01T09:30:00Z"`
+	Status		EmergencyReviewStatus	`gorm:"not null;default:pending;index" json:"status" example:"pending"`
+	ReviewedBy	*uuid.UUID		`gorm:"type:uuid" json:"reviewed_by,omitempty" example:"99ab51c4-a544-4352-a8df-4632ff8b105d"`
+	ReviewedAt	*time.Time		`json:"reviewed_at,omitempty" example:"2025-06-02T09:00:00Z"`
+	ReviewNote	string			`json:"review_note,omitempty" example:"Confirmed with the emergency room"`
+}
+
+// ActiveAt reports whether the access is in force at the given time.
+func (a *EmergencyAccess) ActiveAt(t time.Time) bool {
+	return a.RevokedAt == nil &&
+		!a.GrantedAt.After(t) &&
+		a.ExpiresAt.After(t)
+}
+
+// EmergencyAccessEvent records a read of a patient's clinical data under
+// emergency access.
+type EmergencyAccessEvent struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"9a0b1c2d-3e4f-4a5b-8c6d-7e8f9a0b1c2d"`
+	AccessID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"access_id" example:"8f9a0b1c-2d3e-4f5a-9b6c-7d8e9f0a1b2c"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DoctorID	uuid.UUID		`gorm:"type:uuid;not null" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	Categories	[]ConsentCategory	`gorm:"type:jsonb;serializer:json;not null" json:"categories" example:"records,lab_results"`
+	AccessedAt	time.Time		`gorm:"not null" json:"accessed_at" example:"2025-06-01T09:05:00Z"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+// Encounter is the clinical record of an appointment. Its notes are never
+// modified in place: every change is recorded as a new revision, so the
+// current state is the revision with the highest version.
+type Encounter struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	AppointmentID	uuid.UUID		`gorm:"type:uuid;not null;uniqueIndex" json:"appointment_id" example:"5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DoctorID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	CreatedAt	time.Time		`gorm:"not null" json:"created_at" example:"2025-06-02T10:05:00Z"`
+	Revisions	[]EncounterRevision	`json:"revisions"`
+	Attachments	[]EncounterAttachment	`json:"attachments"`
+	Delegations	[]EncounterDelegation	`json:"delegations"`
+}
+
+// EncounterRevision is an immutable version of the encounter notes. The
+// first revision is the original record, later ones are amendments.
+type EncounterRevision struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"2e3f4a5b-6c7d-4e8f-9a0b-1c2d3e4f5a6b"`
+	EncounterID	uuid.UUID	`gorm:"type:uuid;not null;uniqueIndex:idx_encounter_version" json:"encounter_id" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	Version		int		`gorm:"not null;uniqueIndex:idx_encounter_version" json:"version" example:"1"`
+	AuthorID	uuid.UUID	`gorm:"type:uuid;not null" json:"author_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	// Reason explains an amendment. It is empty for the original record.
+	Reason		string		`json:"reason,omitempty" example:"Corrected blood pressure reading"`
+	Subjective	string		`json:"subjective" example:"Headache for three days, worse in the morning."`
+	Objective	string		`json:"objective" example:"Alert and oriented, no focal neurological deficits."`
+	Assessment	string		`json:"assessment" example:"Tension-type headache."`
+	Plan		string		`json:"plan" example:"Ibuprofen as needed, follow up in two weeks."`
+	Diagnoses	[]Diagnosis	`gorm:"type:jsonb;serializer:json" json:"diagnoses"`
+	Vitals		Vitals		`gorm:"embedded;embeddedPrefix:vitals_" json:"vitals"`
+	CreatedAt	time.Time	`gorm:"not null" json:"created_at" example:"2025-06-02T10:05:00Z"`
+}
+
+type Diagnosis struct {
+	Code		string	`json:"code" example:"G44.209"`
+	Description	string	`json:"description" example:"Tension-type headache, unspecified, not intractable"`
+	Primary		bool	`json:"primary" example:"true"`
+}
+
+// Vitals holds the measurements taken during the visit. Measurements which
+// were not taken are nil.
+type Vitals struct {
+	HeartRate		*int		`json:"heart_rate,omitempty" binding:"omitempty,min=20,max=300" example:"72"`
+	SystolicBP		*int		`json:"systolic_bp,omitempty" binding:"omitempty,min=40,max=300" example:"120"`
+	DiastolicBP		*int		`json:"diastolic_bp,omitempty" binding:"omitempty,min=20,max=200" example:"80"`
+	RespiratoryRate		*int		`json:"respiratory_rate,omitempty" binding:"omitempty,min=4,max=80" example:"16"`
+	OxygenSaturation	*int		`json:"oxygen_saturation,omitempty" binding:"omitempty,min=50,max=100" example:"98"`
+	TemperatureC		*float64	`json:"temperature_c,omitempty" binding:"omitempty,min=25,max=45" example:"36.6"`
+	WeightKg		*float64	`json:"weight_kg,omitempty" binding:"omitempty,gt=0,max=500" example:"70.5"`
+	HeightCm		*float64	`json:"height_cm,omitempty" binding:"omitempty,gt=0,max=300" example:"175"`
+}
+
+type EncounterAttachment struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d"`
+	EncounterID	uuid.UUID	`gorm:"type:uuid;not null;index" json:"encounter_id" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	FileName	string		`gorm:"not null" json:"file_name" example:"ecg.pdf"`
+	ContentType	string		`gorm:"not null" json:"content_type" example:"application/pdf"`
+	Size		int64		`gorm:"not null" json:"size" example:"48213"`
+	// Checksum is the hex-encoded SHA-256 of the content.
+	Checksum	string		`gorm:"not null" json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Data		[]byte		`gorm:"not null" json:"-"`
+	UploadedBy	uuid.UUID	`gorm:"type:uuid;not null" json:"uploaded_by" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	CreatedAt	time.Time	`gorm:"not null" json:"created_at" example:"2025-06-02T10:07:00Z"`
+}
+
+// EncounterDelegation grants a doctor other than the treating one read
+// access to an encounter.
+type EncounterDelegation struct {
+	EncounterID	uuid.UUID	`gorm:"type:uuid;primary_key" json:"-"`
+	DoctorID	uuid.UUID	`gorm:"type:uuid;primary_key" json:"doctor_id" example:"b8f5a9e2-3c1d-4f7a-9e6b-2d4c8a1f3e5b"`
+	GrantedBy	uuid.UUID	`gorm:"type:uuid;not null" json:"granted_by" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	CreatedAt	time.Time	`gorm:"not null" json:"created_at" example:"2025-06-02T10:10:00Z"`
+}
+
+// ICD10Code is an entry of the ICD-10 dictionary.
+type ICD10Code struct {
+	Code		string	`json:"code" example:"G44.209"`
+	Description	string	`json:"description" example:"Tension-type headache, unspecified, not intractable"`
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type HealthRecordKind string
+
+const (
+	AllergyRecord		HealthRecordKind = "allergies"
+	ConditionRecord		HealthRecordKind = "conditions"
+	ImmunizationRecord	HealthRecordKind = "immunizations"
+	MedicationRecord	HealthRecordKind = "medications"
+	GenotypeRecord		HealthRecordKind = "genotypes"
+)
+
+var HealthRecordKinds = []HealthRecordKind{
+	AllergyRecord,
+	ConditionRecord,
+	ImmunizationRecord,
+	MedicationRecord,
+	GenotypeRecord,
+}
+
+// ConsentCategory returns the category of clinical data records of the
+// kind belong to.
+func (k HealthRecordKind) ConsentCategory() ConsentCategory {
+	if k == GenotypeRecord {
+		return GenomicsCategory
+	}
+	return RecordsCategory
+}
+
+type ConditionStatus string
+
+const (
+	ConditionActive		ConditionStatus = "active"
+	ConditionResolved	ConditionStatus = "resolved"
+)
+
+// HealthRecord holds the fields shared by every entry of the patient's
+// medical history, including its provenance.
+type HealthRecord struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f"`
+	PatientID	uuid.UUID	`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	// RecordedBy is the patient for self-reported entries, otherwise the
+	// doctor who recorded the entry.
+	RecordedBy	uuid.UUID	`gorm:"type:uuid;not null" json:"recorded_by" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	EncounterID	*uuid.UUID	`gorm:"type:uuid" json:"encounter_id,omitempty" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	RecordedAt	time.Time	`gorm:"not null" json:"recorded_at" example:"2025-01-15T09:00:00Z"`
+	UpdatedBy	*uuid.UUID	`gorm:"type:uuid" json:"updated_by,omitempty" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	UpdatedAt	*time.Time	`json:"updated_at,omitempty" example:"2025-02-01T11:30:00Z"`
+}
+
+// Record gives access to the shared fields of any health record.
+func (r *HealthRecord) Record() *HealthRecord {
+	return r
+}
+
+// Recordable is implemented by all health record entries.
+type Recordable interface {
+	Record() *HealthRecord
+}
+
+// NewHealthRecord returns an empty entry of the given kind.
+func NewHealthRecord(kind HealthRecordKind) (Recordable, bool) {
+	switch kind {
+	case AllergyRecord:
+		return &Allergy{}, true
+	case ConditionRecord:
+		return &ChronicCondition{}, true
+	case ImmunizationRecord:
+		return &Immunization{}, true
+	case MedicationRecord:
+		return &MedicationStatement{}, true
+	case GenotypeRecord:
+		return &Genotype{}, true
+	default:
+		return nil, false
+	}
+}
+
+type Allergy struct {
+	HealthRecord
+	// Substance is either a drug name or an allergen class such as
+	// `penicillin` or `nsaid`.
+	Substance	string			`gorm:"not null" json:"substance" example:"penicillin"`
+	Reaction	string			`json:"reaction" example:"Hives"`
+	Severity	InteractionSeverity	`gorm:"not null;default:moderate" json:"severity" example:"moderate"`
+}
+
+type ChronicCondition struct {
+	HealthRecord
+	Code		string		`gorm:"not null" json:"code" example:"I10"`
+	Description	string		`gorm:"not null" json:"description" example:"Essential (primary) hypertension"`
+	Status		ConditionStatus	`gorm:"not null;default:active" json:"status" example:"active"`
+	OnsetDate	*time.Time	`json:"onset_date,omitempty" example:"2019-04-01T00:00:00Z"`
+	Notes		string		`json:"notes,omitempty" example:"Controlled with medication"`
+}
+
+type Immunization struct {
+	HealthRecord
+	Vaccine		string		`gorm:"not null" json:"vaccine" example:"Tetanus, diphtheria (Td)"`
+	DoseNumber	int		`gorm:"not null;default:1" json:"dose_number" example:"1"`
+	AdministeredOn	time.Time	`gorm:"not null" json:"administered_on" example:"2024-10-03T00:00:00Z"`
+	LotNumber	string		`json:"lot_number,omitempty" example:"AB1234"`
+}
+
+// MedicationStatement records a medication the patient takes or took,
+// including those prescribed outside of the system.
+type MedicationStatement struct {
+	HealthRecord
+	DrugName	string		`gorm:"not null" json:"drug_name" example:"Metformin"`
+	Dosage		string		`json:"dosage,omitempty" example:"500 mg twice daily"`
+	StartedOn	*time.Time	`json:"started_on,omitempty" example:"2021-03-01T00:00:00Z"`
+	EndedOn		*time.Time	`json:"ended_on,omitempty" example:"2023-03-01T00:00:00Z"`
+}
+
+// Genotype is a pharmacogenomic test result. Phenotype uses the
+// standardized terms listed in Phenotypes.
+type Genotype struct {
+	HealthRecord
+	Gene		string		`gorm:"not null" json:"gene" example:"CYP2D6"`
+	Diplotype	string		`json:"diplotype,omitempty" example:"*4/*4"`
+	Phenotype	string		`gorm:"not null" json:"phenotype" example:"poor metabolizer"`
+	TestedOn	*time.Time	`json:"tested_on,omitempty" example:"2024-11-20T00:00:00Z"`
+	Laboratory	string		`json:"laboratory,omitempty" example:"MGH Molecular Diagnostics"`
+}
+
+// PatientSummary consolidates the medical history of a patient.
+type PatientSummary struct {
+	PatientID		uuid.UUID		`json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	Allergies		[]Allergy		`json:"allergies"`
+	Conditions		[]ChronicCondition	`json:"conditions"`
+	Immunizations		[]Immunization		`json:"immunizations"`
+	Medications		[]MedicationStatement	`json:"medications"`
+	Genotypes		[]Genotype		`json:"genotypes"`
+	ActivePrescriptions	[]Prescription		`json:"active_prescriptions"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// HomeAddress is where a patient lives, geocoded when it is saved, so that
+// the travel to appointments can be estimated.
+type HomeAddress struct {
+	PatientID	uuid.UUID	`gorm:"type:uuid;primary_key;" json:"-"`
+	Address		string		`gorm:"not null" json:"address" example:"10 Brattle Street, Cambridge"`
+	DisplayName	string		`gorm:"not null" json:"display_name" example:"10, Brattle Street, Harvard Square, Cambridge, Middlesex County, Massachusetts, 02138, United States"`
+	Lat		float64		`gorm:"not null" json:"lat" example:"42.3736158"`
+	Lon		float64		`gorm:"not null" json:"lon" example:"-71.1097335"`
+}
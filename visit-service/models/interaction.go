@@ -0,0 +1,60 @@
+package models
+
+type InteractionSeverity string
+
+const (
+	Minor		InteractionSeverity = "minor"
+	Moderate	InteractionSeverity = "moderate"
+	Severe		InteractionSeverity = "severe"
+)
+
+// Rank orders severities from least to most dangerous.
+func (s InteractionSeverity) Rank() int {
+	switch s {
+	case Minor:
+		return 1
+	case Moderate:
+		return 2
+	case Severe:
+		return 3
+	default:
+		return 0
+	}
+}
+
+type WarningKind string
+
+const (
+	DrugInteraction		WarningKind = "drug_interaction"
+	AllergyConflict		WarningKind = "allergy"
+	DuplicateTherapy	WarningKind = "duplicate_therapy"
+	Pharmacogenomic		WarningKind = "pharmacogenomic"
+)
+
+// InteractionWarning is a problem found when checking a drug against the
+// patient's active prescriptions, allergies and genotypes. Substance names
+// the conflicting drug or allergen, or the gene and phenotype.
+type InteractionWarning struct {
+	Kind		WarningKind		`json:"kind" example:"drug_interaction"`
+	Severity	InteractionSeverity	`json:"severity" example:"severe"`
+	Substance	string			`json:"substance" example:"Warfarin"`
+	Description	string			`json:"description" example:"NSAIDs increase the risk of serious bleeding in patients on warfarin."`
+}
+
+// InteractionRule is an entry of the interaction dataset. Each side is an
+// ATC code prefix, so a rule may cover a whole drug class.
+type InteractionRule struct {
+	ATCA		string
+	ATCB		string
+	Severity	InteractionSeverity
+	Description	string
+}
+
+// AllergyRule maps an allergen to the drugs, given by ATC code prefix,
+// which a patient allergic to it must not or should carefully receive.
+type AllergyRule struct {
+	Allergen	string
+	ATCPrefix	string
+	Severity	InteractionSeverity
+	Description	string
+}
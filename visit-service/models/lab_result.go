@@ -0,0 +1,145 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"math"
+	"strings"
+	"time"
+)
+
+// LabFlag marks a lab value outside of its reference range. It is empty
+// when there is no range to compare the value with.
+type LabFlag string
+
+const (
+	LabNormal	LabFlag = "N"
+	LabLow		LabFlag = "L"
+	LabHigh		LabFlag = "H"
+	LabCriticalLow	LabFlag = "LL"
+	LabCriticalHigh	LabFlag = "HH"
+)
+
+// Abnormal tells whether the flag reports a value outside of the range.
+func (f LabFlag) Abnormal() bool {
+	return f != "" && f != LabNormal
+}
+
+type LabSource string
+
+const (
+	LabSourceManual	LabSource = "manual"
+	LabSourceHL7	LabSource = "hl7"
+)
+
+// LabResult is a single numeric lab value. Values of analytes known to the
+// lab dictionary are stored in the analyte's canonical unit; the value and
+// unit as reported are kept alongside.
+type LabResult struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"6f7a8b9c-0d1e-4f2a-9b3c-4d5e6f7a8b9c"`
+	PatientID	uuid.UUID	`gorm:"type:uuid;not null;index:idx_lab_patient_code" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	LOINCCode	string		`gorm:"not null;index:idx_lab_patient_code" json:"loinc_code" example:"2345-7"`
+	Name		string		`gorm:"not null" json:"name" example:"Glucose"`
+	Value		float64		`gorm:"not null" json:"value" example:"5.6"`
+	Unit		string		`gorm:"not null" json:"unit" example:"mmol/L"`
+	ReportedValue	float64		`gorm:"not null" json:"reported_value" example:"101"`
+	ReportedUnit	string		`gorm:"not null" json:"reported_unit" example:"mg/dL"`
+	// ReferenceLow and ReferenceHigh bound the normal range, in Unit. Either
+	// may be open.
+	ReferenceLow	*float64	`json:"reference_low,omitempty" example:"3.9"`
+	ReferenceHigh	*float64	`json:"reference_high,omitempty" example:"5.5"`
+	Flag		LabFlag		`json:"flag,omitempty" example:"H"`
+	ObservedAt	time.Time	`gorm:"not null;index" json:"observed_at" example:"2025-06-02T08:00:00Z"`
+	RecordedBy	uuid.UUID	`gorm:"type:uuid;not null" json:"recorded_by" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	RecordedAt	time.Time	`gorm:"not null" json:"recorded_at" example:"2025-06-02T10:05:00Z"`
+	Source		LabSource	`gorm:"not null" json:"source" example:"hl7"`
+	// SourceID identifies the result in the message it was imported from,
+	// so that importing a message again does not duplicate it.
+	SourceID	*string		`gorm:"uniqueIndex" json:"-"`
+}
+
+// LabAnalyte is an entry of the lab dictionary.
+type LabAnalyte struct {
+	Code		string
+	Name		string
+	// Unit is the canonical unit values are normalized to.
+	Unit		string
+	ReferenceLow	*float64
+	ReferenceHigh	*float64
+	CriticalLow	*float64
+	CriticalHigh	*float64
+	// Factors convert values from other units: multiplying a value in the
+	// unit by its factor gives the value in the canonical unit. Keys are
+	// normalized with NormalizeLabUnit.
+	Factors		map[string]float64
+}
+
+// Convert converts a value in the given unit to the canonical unit. It
+// returns false when the unit is unknown.
+func (a *LabAnalyte) Convert(value float64, unit string) (float64, bool) {
+	unit = NormalizeLabUnit(unit)
+	if unit == NormalizeLabUnit(a.Unit) {
+		return value, true
+	}
+
+	factor, ok := a.Factors[unit]
+	if !ok {
+		return 0, false
+	}
+	return roundLabValue(value * factor), true
+}
+
+// NormalizeLabUnit returns the form in which units are compared: lower
+// case, without spaces and with the micro sign spelled as "u".
+func NormalizeLabUnit(unit string) string {
+	unit = strings.ToLower(strings.Join(strings.Fields(unit), ""))
+	return strings.NewReplacer("µ", "u", "μ", "u").Replace(unit)
+}
+
+// ValidLOINCCode checks the format and the mod 10 check digit of a LOINC
+// code, e.g. "2345-7".
+func ValidLOINCCode(code string) bool {
+	number, check, ok := strings.Cut(code, "-")
+	if !ok || len(number) == 0 || len(number) > 7 || len(check) != 1 {
+		return false
+	}
+
+	sum := 0
+	for i := len(number) - 1; i >= 0; i-- {
+		d := int(number[i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if (len(number)-1-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+
+	return int(check[0]-'0') == (10-sum%10)%10
+}
+
+// roundLabValue drops the noise introduced by unit conversion.
+func roundLabValue(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
+
+// LabSeries is the history of one analyte of a patient, oldest first.
+type LabSeries struct {
+	LOINCCode	string		`json:"loinc_code" example:"2345-7"`
+	Name		string		`json:"name" example:"Glucose"`
+	Unit		string		`json:"unit" example:"mmol/L"`
+	ReferenceLow	*float64	`json:"reference_low,omitempty" example:"3.9"`
+	ReferenceHigh	*float64	`json:"reference_high,omitempty" example:"5.5"`
+	Points		[]LabPoint	`json:"points"`
+}
+
+type LabPoint struct {
+	ResultID	uuid.UUID	`json:"result_id" example:"6f7a8b9c-0d1e-4f2a-9b3c-4d5e6f7a8b9c"`
+	ObservedAt	time.Time	`json:"observed_at" example:"2025-06-02T08:00:00Z"`
+	Value		float64		`json:"value" example:"5.6"`
+	Flag		LabFlag		`json:"flag,omitempty" example:"H"`
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"github.com/google/uuid"
 
+	"time"
+
 	"igaku/commons/dtos"
 )
 
@@ -10,4 +12,14 @@ type Organization struct {
 	ID	uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
 	Name	string		`json:"name" example:"The Lowell General Hospital"`
 	dtos.Location		`gorm:"embedded;embeddedPrefix:loc_" json:"location"`
+	// OsmTypeCheckedAt is when the OSM type of the location last failed to
+	// resolve.
+	OsmTypeCheckedAt	*time.Time	`json:"-"`
+}
+
+// NearbyOrganization is an organization found by a distance search along
+// with its great-circle distance from the searched point.
+type NearbyOrganization struct {
+	Organization
+	DistanceKm	float64	`gorm:"column:distance_km" json:"distance_km" example:"1.27"`
 }
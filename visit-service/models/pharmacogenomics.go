@@ -0,0 +1,33 @@
+package models
+
+// Phenotypes are the standardized terms describing how a gene variant
+// affects drug response.
+var Phenotypes = []string{
+	"ultrarapid metabolizer",
+	"rapid metabolizer",
+	"normal metabolizer",
+	"likely intermediate metabolizer",
+	"intermediate metabolizer",
+	"likely poor metabolizer",
+	"poor metabolizer",
+	"increased function",
+	"normal function",
+	"possible decreased function",
+	"decreased function",
+	"poor function",
+	"positive",
+	"negative",
+	"indeterminate",
+}
+
+// PharmacogenomicRule is an entry of the guideline table: the
+// recommendation for patients with the phenotype of the gene who receive a
+// drug, given by ATC code prefix. Severity reflects how strongly the
+// standard therapy should be changed.
+type PharmacogenomicRule struct {
+	Gene		string
+	Phenotype	string
+	ATCPrefix	string
+	Severity	InteractionSeverity
+	Recommendation	string
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type PrescriptionStatus string
+
+const (
+	PrescriptionIssued	PrescriptionStatus = "issued"
+	PrescriptionDispensed	PrescriptionStatus = "dispensed"
+	PrescriptionCancelled	PrescriptionStatus = "cancelled"
+	PrescriptionExpired	PrescriptionStatus = "expired"
+)
+
+// Prescription copies the drug details from the dictionary at issuance, so
+// it stays intact when the dictionary is updated.
+type Prescription struct {
+	ID			uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"6f5e4d3c-2b1a-4c9d-8e7f-6a5b4c3d2e1f"`
+	PatientID		uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DoctorID		uuid.UUID		`gorm:"type:uuid;not null;index" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	EncounterID		*uuid.UUID		`gorm:"type:uuid" json:"encounter_id,omitempty" example:"7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"`
+	DrugID			string			`gorm:"not null" json:"drug_id" example:"atorvastatin-20-tab"`
+	DrugName		string			`gorm:"not null" json:"drug_name" example:"Atorvastatin"`
+	ATCCode			string			`gorm:"not null;default:''" json:"atc_code" example:"C10AA05"`
+	Form			string			`gorm:"not null" json:"form" example:"tablet"`
+	Strength		string			`gorm:"not null" json:"strength" example:"20 mg"`
+	Dosage			string			`gorm:"not null" json:"dosage" example:"1 tablet once daily in the evening"`
+	Quantity		int			`gorm:"not null" json:"quantity" example:"30"`
+	Refills			int			`gorm:"not null" json:"refills" example:"2"`
+	// Fills counts how many times the prescription has been dispensed.
+	// It is fully dispensed once Fills exceeds Refills.
+	Fills			int			`gorm:"not null" json:"fills" example:"0"`
+	ValidFrom		time.Time		`gorm:"not null" json:"valid_from" example:"2025-06-02T00:00:00Z"`
+	ValidUntil		time.Time		`gorm:"not null;index" json:"valid_until" example:"2025-09-02T00:00:00Z"`
+	Status			PrescriptionStatus	`gorm:"not null;index" json:"status" example:"issued"`
+	VerificationCode	string			`gorm:"not null;uniqueIndex" json:"verification_code" example:"7K2M-QX4P-9TBR"`
+	IssuedAt		time.Time		`gorm:"not null" json:"issued_at" example:"2025-06-02T10:15:00Z"`
+	// Warnings are the interaction and allergy findings shown to the
+	// doctor when the prescription was issued.
+	Warnings		[]InteractionWarning	`gorm:"type:jsonb;serializer:json" json:"warnings"`
+	// Guidance holds the current pharmacogenomic recommendations for the
+	// drug. It is not stored and only filled in when a prescription is
+	// viewed.
+	Guidance		[]InteractionWarning	`gorm:"-" json:"guidance,omitempty"`
+	// OverrideReason is the doctor's justification for prescribing despite
+	// a severe warning.
+	OverrideReason		string			`gorm:"not null;default:''" json:"override_reason,omitempty" example:"Benefit outweighs the bleeding risk; INR monitored weekly"`
+}
+
+// IsActive reports whether the prescription can be dispensed at the given
+// time.
+func (p *Prescription) IsActive(now time.Time) bool {
+	return p.Status == PrescriptionIssued &&
+		!now.Before(p.ValidFrom) &&
+		now.Before(p.ValidUntil)
+}
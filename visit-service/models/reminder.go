@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type ReminderKind string
+
+const (
+	DayBefore	ReminderKind = "day_before"
+	HourBefore	ReminderKind = "hour_before"
+)
+
+// Reminder marks that a reminder of the given kind has already been
+// claimed for an appointment. The unique index guarantees that no
+// appointment is ever reminded twice about the same thing, no matter how
+// many visit-service replicas are running or how often they restart.
+type Reminder struct {
+	AppointmentID	uuid.UUID	`gorm:"type:uuid;primaryKey"`
+	Kind		ReminderKind	`gorm:"primaryKey"`
+	SentAt		time.Time	`gorm:"not null"`
+}
+
+type ReminderPreference struct {
+	UserID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"-"`
+	DayBefore	bool		`gorm:"not null" json:"day_before" example:"true"`
+	HourBefore	bool		`gorm:"not null" json:"hour_before" example:"false"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type SlotStatus string
+
+const (
+	Free	SlotStatus = "free"
+	Held	SlotStatus = "held"
+	Taken	SlotStatus = "taken"
+)
+
+type Slot struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"3f1e2d4c-5b6a-4978-8c9d-0e1f2a3b4c5d"`
+	DoctorID	uuid.UUID	`gorm:"type:uuid;not null;index" json:"doctor_id" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	OrganizationID	uuid.UUID	`gorm:"type:uuid;not null;index" json:"organization_id" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
+	Organization	Organization	`json:"-"`
+	StartsAt	time.Time	`gorm:"not null;index" json:"starts_at" example:"2025-06-02T09:30:00Z"`
+	EndsAt		time.Time	`gorm:"not null" json:"ends_at" example:"2025-06-02T10:00:00Z"`
+	Status		SlotStatus	`gorm:"not null;index" json:"status" example:"free"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"time"
+)
+
+type WaitlistEntryStatus string
+
+const (
+	Waiting		WaitlistEntryStatus = "waiting"
+	Offered		WaitlistEntryStatus = "offered"
+	Fulfilled	WaitlistEntryStatus = "fulfilled"
+	Withdrawn	WaitlistEntryStatus = "withdrawn"
+)
+
+// WaitlistEntry expresses a patient's interest in any slot matching the
+// given criteria. A nil DoctorID or OrganizationID matches any doctor or
+// organization respectively.
+type WaitlistEntry struct {
+	ID		uuid.UUID		`gorm:"type:uuid;primary_key;" json:"id" example:"9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"`
+	PatientID	uuid.UUID		`gorm:"type:uuid;not null;index" json:"patient_id" example:"0b6f13da-efb9-4221-9e89-e2729ae90030"`
+	DoctorID	*uuid.UUID		`gorm:"type:uuid" json:"doctor_id,omitempty" example:"e2c66717-12bb-4b6a-b7b6-3be939e170ad"`
+	OrganizationID	*uuid.UUID		`gorm:"type:uuid" json:"organization_id,omitempty" example:"86e6a1f3-d7aa-4e74-a20a-ea78bc13340b"`
+	From		time.Time		`gorm:"not null" json:"from" example:"2025-06-01T00:00:00Z"`
+	To		time.Time		`gorm:"not null" json:"to" example:"2025-06-14T00:00:00Z"`
+	Status		WaitlistEntryStatus	`gorm:"not null;index" json:"status" example:"waiting"`
+	CreatedAt	time.Time		`gorm:"not null" json:"created_at" example:"2025-05-20T12:00:00Z"`
+}
+
+type OfferStatus string
+
+const (
+	Pending		OfferStatus = "pending"
+	Accepted	OfferStatus = "accepted"
+	Declined	OfferStatus = "declined"
+	Expired		OfferStatus = "expired"
+)
+
+// WaitlistOffer is a freed slot held for a waitlisted patient until
+// ExpiresAt.
+type WaitlistOffer struct {
+	ID		uuid.UUID	`gorm:"type:uuid;primary_key;" json:"id" example:"1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e"`
+	EntryID		uuid.UUID	`gorm:"type:uuid;not null;index" json:"entry_id" example:"9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"`
+	Entry		WaitlistEntry	`json:"-"`
+	SlotID		uuid.UUID	`gorm:"type:uuid;not null;index" json:"slot_id" example:"3f1e2d4c-5b6a-4978-8c9d-0e1f2a3b4c5d"`
+	Slot		Slot		`json:"slot"`
+	ExpiresAt	time.Time	`gorm:"not null;index" json:"expires_at" example:"2025-05-28T12:30:00Z"`
+	Status		OfferStatus	`gorm:"not null" json:"status" example:"pending"`
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+
+	"context"
+	"log"
+
+	commonsErrors "igaku/commons/errors"
+)
+
+// AdvisoryLock runs work under PostgreSQL session advisory locks, so that
+// work scheduled on every replica only runs on one of them at a time.
+type AdvisoryLock interface {
+	// TryRun runs fn unless another session holds the lock of the key, and
+	// reports whether it ran. The error of fn is returned as is.
+	TryRun(key int64, fn func() error) (bool, error)
+}
+
+type gormAdvisoryLock struct {
+	db *gorm.DB
+}
+
+func NewGormAdvisoryLock(db *gorm.DB) AdvisoryLock {
+	return &gormAdvisoryLock{db: db}
+}
+
+func (l *gormAdvisoryLock) TryRun(key int64, fn func() error) (bool, error) {
+	ctx := context.Background()
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		log.Printf("Failed to access the database: %v", err)
+		return false, &commonsErrors.DatabaseError{}
+	}
+
+	// Session locks belong to a connection, so the same one must be used
+	// to unlock.
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		log.Printf("Failed to acquire a database connection: %v", err)
+		return false, &commonsErrors.DatabaseError{}
+	}
+	defer conn.Close()
+
+	var locked bool
+	err = conn.
+		QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).
+		Scan(&locked)
+	if err != nil {
+		log.Printf("Failed to take advisory lock %d: %v", key, err)
+		return false, &commonsErrors.DatabaseError{}
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+		if err != nil {
+			log.Printf("Failed to release advisory lock %d: %v", key, err)
+		}
+	}()
+
+	return true, fn()
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"log"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+)
+
+type AllergyRepository interface {
+	FindByPatientID(patientID uuid.UUID) ([]models.Allergy, error)
+}
+
+type gormAllergyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAllergyRepository(db *gorm.DB) AllergyRepository {
+	return &gormAllergyRepository{db: db}
+}
+
+func (r *gormAllergyRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Allergy, error) {
+	var allergies []models.Allergy
+	err := r.db.
+		Where("patient_id = ?", patientID).
+		Order("recorded_at asc").
+		Find(&allergies).
+		Error
+	if err != nil {
+		log.Printf("Failed to find allergies: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return allergies, nil
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type AppointmentRepository interface {
+	FindByID(id uuid.UUID) (*models.Appointment, error)
+	FindByPatientID(patientID uuid.UUID) ([]models.Appointment, error)
+	FindByDoctorID(doctorID uuid.UUID) ([]models.Appointment, error)
+	// Search returns a page of the matching appointments, with their
+	// slots, and the number of all matching appointments.
+	Search(filter dtos.AppointmentFilter) ([]models.Appointment, int64, error)
+	// Cancel marks the appointment as cancelled and releases its slot,
+	// if any.
+	Cancel(id uuid.UUID) (*models.Appointment, error)
+}
+
+type gormAppointmentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAppointmentRepository(db *gorm.DB) AppointmentRepository {
+	return &gormAppointmentRepository{db: db}
+}
+
+func (r *gormAppointmentRepository) FindByID(id uuid.UUID) (*models.Appointment, error) {
+	var appt models.Appointment
+	err := r.db.First(&appt, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.AppointmentNotFoundError{}
+		}
+		log.Printf("Failed to find appointment: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &appt, nil
+}
+
+func (r *gormAppointmentRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Appointment, error) {
+	return r.findBy("patient_id", patientID)
+}
+
+func (r *gormAppointmentRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.Appointment, error) {
+	return r.findBy("doctor_id", doctorID)
+}
+
+func (r *gormAppointmentRepository) findBy(
+	column string, id uuid.UUID,
+) ([]models.Appointment, error) {
+	var appts []models.Appointment
+	err := r.db.
+		Where(column+" = ?", id).
+		Order("starts_at asc").
+		Find(&appts).
+		Error
+	if err != nil {
+		log.Printf("Failed to find appointments by %s: %v", column, err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return appts, nil
+}
+
+func (r *gormAppointmentRepository) Search(
+	filter dtos.AppointmentFilter,
+) ([]models.Appointment, int64, error) {
+	query := r.db.Model(&models.Appointment{})
+	if filter.ID != nil {
+		query = query.Where("id = ?", *filter.ID)
+	}
+	if filter.PatientID != nil {
+		query = query.Where("patient_id = ?", *filter.PatientID)
+	}
+	if filter.DoctorID != nil {
+		query = query.Where("doctor_id = ?", *filter.DoctorID)
+	}
+	if filter.OrganizationID != nil {
+		query = query.Where("organization_id = ?", *filter.OrganizationID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.StartsFrom != nil {
+		query = query.Where("starts_at >= ?", *filter.StartsFrom)
+	}
+	if filter.StartsBefore != nil {
+		query = query.Where("starts_at < ?", *filter.StartsBefore)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Failed to count appointments: %v", err)
+		return nil, 0, &commonsErrors.DatabaseError{}
+	}
+
+	var appts []models.Appointment
+	err := query.
+		Preload("Slot").
+		Order("starts_at asc, id asc").
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&appts).
+		Error
+	if err != nil {
+		log.Printf("Failed to search appointments: %v", err)
+		return nil, 0, &commonsErrors.DatabaseError{}
+	}
+
+	return appts, count, nil
+}
+
+func (r *gormAppointmentRepository) Cancel(id uuid.UUID) (*models.Appointment, error) {
+	var appt models.Appointment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&appt, id).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &igakuErrors.AppointmentNotFoundError{}
+			}
+			return err
+		}
+
+		if appt.Status == models.Cancelled {
+			return nil
+		}
+
+		appt.Status = models.Cancelled
+		err = tx.Model(&appt).Update("status", appt.Status).Error
+		if err != nil {
+			return err
+		}
+
+		if err := leaveCareTeam(tx, &appt); err != nil {
+			return err
+		}
+
+		if appt.SlotID == nil {
+			return nil
+		}
+
+		return tx.
+			Model(&models.Slot{}).
+			Where("id = ?", *appt.SlotID).
+			Update("status", models.Free).
+			Error
+	})
+	if err != nil {
+		var notFoundErr *igakuErrors.AppointmentNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		log.Printf("Failed to cancel appointment: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return &appt, nil
+}
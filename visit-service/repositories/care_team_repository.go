@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type CareTeamRepository interface {
+	// Assign places the doctor on the patient's care team. An existing
+	// assignment at the same organization is updated instead; a new
+	// primary doctor replaces the previous one, who stays a member.
+	Assign(assignment *models.CareAssignment) (*models.CareAssignment, error)
+	// FindByPatientID returns the current care team of the patient, the
+	// primary doctor first.
+	FindByPatientID(patientID uuid.UUID) ([]models.CareAssignment, error)
+	// FindByDoctorID returns the current assignments of the doctor.
+	FindByDoctorID(doctorID uuid.UUID) ([]models.CareAssignment, error)
+	// FindPatientIDs returns the patients whose care team the doctor is
+	// currently on.
+	FindPatientIDs(doctorID uuid.UUID) ([]uuid.UUID, error)
+	IsMember(patientID, doctorID uuid.UUID) (bool, error)
+	// End removes one of the patient's assignments from the care team.
+	End(id, patientID uuid.UUID, at time.Time) (*models.CareAssignment, error)
+}
+
+type gormCareTeamRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCareTeamRepository(db *gorm.DB) CareTeamRepository {
+	return &gormCareTeamRepository{db: db}
+}
+
+func (r *gormCareTeamRepository) Assign(
+	assignment *models.CareAssignment,
+) (*models.CareAssignment, error) {
+	var result *models.CareAssignment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		result, err = assignCareTeam(tx, assignment)
+		return err
+	})
+	if err != nil {
+		log.Printf("Failed to assign care team member: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return result, nil
+}
+
+func (r *gormCareTeamRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.CareAssignment, error) {
+	var assignments []models.CareAssignment
+	err := r.db.
+		Where("patient_id = ? AND ended_at IS NULL", patientID).
+		Order("role = 'primary' desc, assigned_at asc").
+		Find(&assignments).
+		Error
+	if err != nil {
+		log.Printf("Failed to find care team: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return assignments, nil
+}
+
+func (r *gormCareTeamRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.CareAssignment, error) {
+	var assignments []models.CareAssignment
+	err := r.db.
+		Where("doctor_id = ? AND ended_at IS NULL", doctorID).
+		Order("assigned_at desc").
+		Find(&assignments).
+		Error
+	if err != nil {
+		log.Printf("Failed to find care assignments: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return assignments, nil
+}
+
+func (r *gormCareTeamRepository) FindPatientIDs(
+	doctorID uuid.UUID,
+) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.
+		Model(&models.CareAssignment{}).
+		Where("doctor_id = ? AND ended_at IS NULL", doctorID).
+		Distinct().
+		Pluck("patient_id", &ids).
+		Error
+	if err != nil {
+		log.Printf("Failed to find patients of doctor: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return ids, nil
+}
+
+func (r *gormCareTeamRepository) IsMember(
+	patientID, doctorID uuid.UUID,
+) (bool, error) {
+	var count int64
+	err := r.db.
+		Model(&models.CareAssignment{}).
+		Where(
+			"patient_id = ? AND doctor_id = ? AND ended_at IS NULL",
+			patientID, doctorID,
+		).
+		Count(&count).
+		Error
+	if err != nil {
+		log.Printf("Failed to check care team membership: %v", err)
+		return false, &commonsErrors.DatabaseError{}
+	}
+	return count > 0, nil
+}
+
+func (r *gormCareTeamRepository) End(
+	id, patientID uuid.UUID, at time.Time,
+) (*models.CareAssignment, error) {
+	var assignment models.CareAssignment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(
+				"id = ? AND patient_id = ? AND ended_at IS NULL",
+				id, patientID,
+			).
+			First(&assignment).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &igakuErrors.CareAssignmentNotFoundError{}
+			}
+			return err
+		}
+
+		assignment.EndedAt = &at
+		return tx.Model(&assignment).Update("ended_at", at).Error
+	})
+	if err != nil {
+		var notFoundErr *igakuErrors.CareAssignmentNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return nil, err
+		}
+		log.Printf("Failed to end care assignment: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return &assignment, nil
+}
+
+// assignCareTeam adds an assignment to the care team within the
+// transaction, or updates the doctor's current assignment at the
+// organization.
+func assignCareTeam(
+	tx *gorm.DB, assignment *models.CareAssignment,
+) (*models.CareAssignment, error) {
+	if assignment.Role == models.PrimaryDoctor {
+		err := tx.
+			Model(&models.CareAssignment{}).
+			Where(
+				"patient_id = ? AND role = ? AND ended_at IS NULL",
+				assignment.PatientID, models.PrimaryDoctor,
+			).
+			Update("role", models.CareTeamMember).
+			Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var current models.CareAssignment
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(
+			"patient_id = ? AND doctor_id = ? AND organization_id = ? "+
+			"AND ended_at IS NULL",
+			assignment.PatientID, assignment.DoctorID,
+			assignment.OrganizationID,
+		).
+		First(&current).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// A concurrent booking may have placed the doctor on the care
+		// team in the meantime.
+		err = tx.
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(assignment).
+			Error
+		if err != nil {
+			return nil, err
+		}
+		return assignment, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// An automatic assignment neither demotes a primary doctor nor turns
+	// a manual assignment into one ending with the appointments.
+	if assignment.Source == models.AppointmentSource {
+		return &current, nil
+	}
+	current.Role = assignment.Role
+	current.Source = models.ManualSource
+	current.AssignedBy = assignment.AssignedBy
+	err = tx.
+		Model(&current).
+		Select("role", "source", "assigned_by").
+		Updates(&current).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &current, nil
+}
+
+// joinCareTeam places the doctor of a newly booked appointment on the
+// patient's care team.
+func joinCareTeam(tx *gorm.DB, appt *models.Appointment) error {
+	_, err := assignCareTeam(tx, &models.CareAssignment{
+		ID: uuid.New(),
+		PatientID: appt.PatientID,
+		DoctorID: appt.DoctorID,
+		OrganizationID: appt.OrganizationID,
+		Role: models.CareTeamMember,
+		Source: models.AppointmentSource,
+		AssignedAt: time.Now(),
+	})
+	return err
+}
+
+// leaveCareTeam ends the automatic assignment of the doctor of a cancelled
+// appointment, unless the patient has other appointments with the doctor
+// at the organization.
+func leaveCareTeam(tx *gorm.DB, appt *models.Appointment) error {
+	var booked int64
+	err := tx.
+		Model(&models.Appointment{}).
+		Where(
+			"patient_id = ? AND doctor_id = ? AND organization_id = ? "+
+			"AND status = ?",
+			appt.PatientID, appt.DoctorID, appt.OrganizationID,
+			models.Booked,
+		).
+		Count(&booked).
+		Error
+	if err != nil || booked > 0 {
+		return err
+	}
+
+	return tx.
+		Model(&models.CareAssignment{}).
+		Where(
+			"patient_id = ? AND doctor_id = ? AND organization_id = ? "+
+			"AND source = ? AND ended_at IS NULL",
+			appt.PatientID, appt.DoctorID, appt.OrganizationID,
+			models.AppointmentSource,
+		).
+		Update("ended_at", time.Now()).
+		Error
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// ConsentRepository records every change to a consent in its audit
+// history within the same transaction.
+type ConsentRepository interface {
+	Create(consent *models.Consent, actorID uuid.UUID) error
+	// FindByPatientID returns every consent of the patient, newest first.
+	FindByPatientID(patientID uuid.UUID) ([]models.Consent, error)
+	// FindActive returns the consents of the patient in force at the given
+	// time.
+	FindActive(patientID uuid.UUID, at time.Time) ([]models.Consent, error)
+	// Revoke revokes one of the patient's consents.
+	Revoke(
+		id, patientID, actorID uuid.UUID, at time.Time,
+	) (*models.Consent, error)
+	// FindEvents returns the audit history of the patient's consents,
+	// newest first.
+	FindEvents(patientID uuid.UUID) ([]models.ConsentEvent, error)
+}
+
+type gormConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormConsentRepository(db *gorm.DB) ConsentRepository {
+	return &gormConsentRepository{db: db}
+}
+
+func (r *gormConsentRepository) Create(
+	consent *models.Consent, actorID uuid.UUID,
+) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(consent).Error; err != nil {
+			return err
+		}
+		event := models.NewConsentEvent(
+			consent, models.ConsentGranted, actorID, consent.CreatedAt,
+		)
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		log.Printf("Failed to create consent: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormConsentRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Consent, error) {
+	var consents []models.Consent
+	err := r.db.
+		Where("patient_id = ?", patientID).
+		Order("created_at desc").
+		Find(&consents).
+		Error
+	if err != nil {
+		log.Printf("Failed to find consents: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return consents, nil
+}
+
+func (r *gormConsentRepository) FindActive(
+	patientID uuid.UUID, at time.Time,
+) ([]models.Consent, error) {
+	var consents []models.Consent
+	err := r.db.
+		Where("patient_id = ? AND revoked_at IS NULL", patientID).
+		Where("valid_from <= ?", at).
+		Where("valid_until IS NULL OR valid_until > ?", at).
+		Find(&consents).
+		Error
+	if err != nil {
+		log.Printf("Failed to find active consents: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return consents, nil
+}
+
+func (r *gormConsentRepository) Revoke(
+	id, patientID, actorID uuid.UUID, at time.Time,
+) (*models.Consent, error) {
+	var consent models.Consent
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND patient_id = ?", id, patientID).
+			First(&consent).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &igakuErrors.ConsentNotFoundError{}
+			}
+			return err
+		}
+		if consent.RevokedAt != nil {
+			return &igakuErrors.ConsentRevokedError{}
+		}
+
+		consent.RevokedAt = &at
+		err = tx.
+			Model(&consent).
+			Select("revoked_at").
+			Updates(&consent).
+			Error
+		if err != nil {
+			return err
+		}
+
+		event := models.NewConsentEvent(
+			&consent, models.ConsentRevoked, actorID, at,
+		)
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		var notFoundErr *igakuErrors.ConsentNotFoundError
+		var revokedErr *igakuErrors.ConsentRevokedError
+		if errors.As(err, &notFoundErr) || errors.As(err, &revokedErr) {
+			return nil, err
+		}
+		log.Printf("Failed to revoke consent: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return &consent, nil
+}
+
+func (r *gormConsentRepository) FindEvents(
+	patientID uuid.UUID,
+) ([]models.ConsentEvent, error) {
+	var events []models.ConsentEvent
+	err := r.db.
+		Where("patient_id = ?", patientID).
+		Order("occurred_at desc").
+		Find(&events).
+		Error
+	if err != nil {
+		log.Printf("Failed to find consent events: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return events, nil
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"errors"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type ConversationRepository interface {
+	// Create starts the conversation with its first message.
+	Create(conversation *models.Conversation, message *models.Message) error
+	FindByID(id uuid.UUID) (*models.Conversation, error)
+	// FindByParticipant returns the conversations of the user, most
+	// recently active first, with the number of messages the user has not
+	// read yet.
+	FindByParticipant(userID uuid.UUID) ([]models.Conversation, error)
+	AddMessage(message *models.Message) error
+	// FindMessages returns the messages of the conversation, oldest first.
+	FindMessages(conversationID uuid.UUID) ([]models.Message, error)
+	// MarkRead marks the messages of the conversation sent to the reader as
+	// read, returning how many were unread.
+	MarkRead(conversationID, readerID uuid.UUID, at time.Time) (int64, error)
+	// CountUnread returns the number of messages sent to the user which
+	// they have not read yet, across all of their conversations.
+	CountUnread(userID uuid.UUID) (int64, error)
+}
+
+type gormConversationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormConversationRepository(db *gorm.DB) ConversationRepository {
+	return &gormConversationRepository{db: db}
+}
+
+func (r *gormConversationRepository) Create(
+	conversation *models.Conversation, message *models.Message,
+) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(conversation).Error; err != nil {
+			return err
+		}
+		return tx.Create(message).Error
+	})
+	if err != nil {
+		log.Printf("Failed to create conversation: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormConversationRepository) FindByID(
+	id uuid.UUID,
+) (*models.Conversation, error) {
+	var conversation models.Conversation
+	if err := r.db.First(&conversation, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.ConversationNotFoundError{}
+		}
+		log.Printf("Failed to find conversation: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &conversation, nil
+}
+
+func (r *gormConversationRepository) FindByParticipant(
+	userID uuid.UUID,
+) ([]models.Conversation, error) {
+	var conversations []models.Conversation
+	err := r.db.
+		Select(
+			"conversations.*, (?) AS unread",
+			r.db.
+				Model(&models.Message{}).
+				Select("COUNT(*)").
+				Where("messages.conversation_id = conversations.id").
+				Where("messages.sender_id <> ? AND messages.read_at IS NULL", userID),
+		).
+		Where("patient_id = ? OR doctor_id = ?", userID, userID).
+		Order("last_message_at desc").
+		Find(&conversations).
+		Error
+	if err != nil {
+		log.Printf("Failed to find conversations: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return conversations, nil
+}
+
+func (r *gormConversationRepository) AddMessage(message *models.Message) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		return tx.
+			Model(&models.Conversation{}).
+			Where("id = ?", message.ConversationID).
+			Update("last_message_at", message.SentAt).
+			Error
+	})
+	if err != nil {
+		log.Printf("Failed to add message: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormConversationRepository) FindMessages(
+	conversationID uuid.UUID,
+) ([]models.Message, error) {
+	var messages []models.Message
+	err := r.db.
+		Where("conversation_id = ?", conversationID).
+		Order("sent_at").
+		Find(&messages).
+		Error
+	if err != nil {
+		log.Printf("Failed to find messages: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return messages, nil
+}
+
+func (r *gormConversationRepository) MarkRead(
+	conversationID, readerID uuid.UUID, at time.Time,
+) (int64, error) {
+	result := r.db.
+		Model(&models.Message{}).
+		Where("conversation_id = ? AND sender_id <> ?", conversationID, readerID).
+		Where("read_at IS NULL").
+		Update("read_at", at)
+	if result.Error != nil {
+		log.Printf("Failed to mark messages as read: %v", result.Error)
+		return 0, &commonsErrors.DatabaseError{}
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *gormConversationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.
+		Model(&models.Message{}).
+		Joins("JOIN conversations ON conversations.id = messages.conversation_id").
+		Where("conversations.patient_id = ? OR conversations.doctor_id = ?", userID, userID).
+		Where("messages.sender_id <> ? AND messages.read_at IS NULL", userID).
+		Count(&count).
+		Error
+	if err != nil {
+		log.Printf("Failed to count unread messages: %v", err)
+		return 0, &commonsErrors.DatabaseError{}
+	}
+	return count, nil
+}
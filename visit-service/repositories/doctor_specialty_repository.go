@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"log"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+)
+
+type DoctorSpecialtyRepository interface {
+	// FindByDoctorID returns the specialties of the doctor in
+	// alphabetical order.
+	FindByDoctorID(doctorID uuid.UUID) ([]string, error)
+	// Replace sets the specialties of the doctor, removing all others.
+	Replace(doctorID uuid.UUID, specialties []string) error
+}
+
+type gormDoctorSpecialtyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDoctorSpecialtyRepository(db *gorm.DB) DoctorSpecialtyRepository {
+	return &gormDoctorSpecialtyRepository{db: db}
+}
+
+func (r *gormDoctorSpecialtyRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]string, error) {
+	specialties := []string{}
+	err := r.db.
+		Model(&models.DoctorSpecialty{}).
+		Where("doctor_id = ?", doctorID).
+		Order("specialty asc").
+		Pluck("specialty", &specialties).
+		Error
+	if err != nil {
+		log.Printf("Failed to find doctor specialties: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return specialties, nil
+}
+
+func (r *gormDoctorSpecialtyRepository) Replace(
+	doctorID uuid.UUID, specialties []string,
+) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where("doctor_id = ?", doctorID).
+			Delete(&models.DoctorSpecialty{}).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(specialties) == 0 {
+			return nil
+		}
+
+		rows := make([]models.DoctorSpecialty, len(specialties))
+		for i, specialty := range specialties {
+			rows[i] = models.DoctorSpecialty{
+				DoctorID: doctorID,
+				Specialty: specialty,
+			}
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		log.Printf("Failed to replace doctor specialties: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+
+	return nil
+}
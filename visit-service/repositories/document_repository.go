@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"errors"
+	"log"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// DocumentRepository stores the metadata of documents; their content is
+// kept in a blob store.
+type DocumentRepository interface {
+	Create(doc *models.Document) error
+	FindByID(id uuid.UUID) (*models.Document, error)
+	// FindByPatientID returns the documents of a patient, newest first.
+	FindByPatientID(patientID uuid.UUID) ([]models.Document, error)
+	Delete(doc *models.Document) error
+}
+
+type gormDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &gormDocumentRepository{db: db}
+}
+
+func (r *gormDocumentRepository) Create(doc *models.Document) error {
+	if err := r.db.Create(doc).Error; err != nil {
+		log.Printf("Failed to create document: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormDocumentRepository) FindByID(id uuid.UUID) (*models.Document, error) {
+	var doc models.Document
+	err := r.db.First(&doc, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.DocumentNotFoundError{}
+		}
+		log.Printf("Failed to find document: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &doc, nil
+}
+
+func (r *gormDocumentRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Document, error) {
+	var docs []models.Document
+	err := r.db.
+		Where("patient_id = ?", patientID).
+		Order("created_at desc").
+		Find(&docs).
+		Error
+	if err != nil {
+		log.Printf("Failed to find documents: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return docs, nil
+}
+
+func (r *gormDocumentRepository) Delete(doc *models.Document) error {
+	if err := r.db.Delete(doc).Error; err != nil {
+		log.Printf("Failed to delete document: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
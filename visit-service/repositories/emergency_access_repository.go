@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type EmergencyAccessRepository interface {
+	Create(access *models.EmergencyAccess) error
+	FindByID(id uuid.UUID) (*models.EmergencyAccess, error)
+	// FindActive returns the doctor's latest emergency access to the
+	// patient in force at the given time.
+	FindActive(
+		patientID, doctorID uuid.UUID, at time.Time,
+	) (*models.EmergencyAccess, error)
+	// FindByDoctorID returns every emergency access of the doctor, newest
+	// first.
+	FindByDoctorID(doctorID uuid.UUID) ([]models.EmergencyAccess, error)
+	// FindByStatus returns the emergency accesses with the review status,
+	// oldest first.
+	FindByStatus(
+		status models.EmergencyReviewStatus,
+	) ([]models.EmergencyAccess, error)
+	// Review records the review of a pending emergency access. Flagging it
+	// ends the access if it is still in force.
+	Review(
+		id, reviewerID uuid.UUID,
+		status models.EmergencyReviewStatus,
+		note string,
+		at time.Time,
+	) (*models.EmergencyAccess, error)
+	LogAccess(event *models.EmergencyAccessEvent) error
+	// FindEvents returns the reads made under the emergency access, oldest
+	// first.
+	FindEvents(accessID uuid.UUID) ([]models.EmergencyAccessEvent, error)
+}
+
+type gormEmergencyAccessRepository struct {
+	db *gorm.DB
+}
+
+func NewGormEmergencyAccessRepository(db *gorm.DB) EmergencyAccessRepository {
+	return &gormEmergencyAccessRepository{db: db}
+}
+
+func (r *gormEmergencyAccessRepository) Create(
+	access *models.EmergencyAccess,
+) error {
+	if err := r.db.Create(access).Error; err != nil {
+		log.Printf("Failed to create emergency access: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormEmergencyAccessRepository) FindByID(
+	id uuid.UUID,
+) (*models.EmergencyAccess, error) {
+	var access models.EmergencyAccess
+	if err := r.db.First(&access, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.EmergencyAccessNotFoundError{}
+		}
+		log.Printf("Failed to find emergency access: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &access, nil
+}
+
+func (r *gormEmergencyAccessRepository) FindActive(
+	patientID, doctorID uuid.UUID, at time.Time,
+) (*models.EmergencyAccess, error) {
+	var access models.EmergencyAccess
+	err := r.db.
+		Where("patient_id = ? AND doctor_id = ?", patientID, doctorID).
+		Where("revoked_at IS NULL AND granted_at <= ? AND expires_at > ?", at, at).
+		Order("granted_at desc").
+		First(&access).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.EmergencyAccessNotFoundError{}
+		}
+		log.Printf("Failed to find active emergency access: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &access, nil
+}
+
+func (r *gormEmergencyAccessRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.EmergencyAccess, error) {
+	var accesses []models.EmergencyAccess
+	err := r.db.
+		Where("doctor_id = ?", doctorID).
+		Order("granted_at desc").
+		Find(&accesses).
+		Error
+	if err != nil {
+		log.Printf("Failed to find emergency accesses: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return accesses, nil
+}
+
+func (r *gormEmergencyAccessRepository) FindByStatus(
+	status models.EmergencyReviewStatus,
+) ([]models.EmergencyAccess, error) {
+	var accesses []models.EmergencyAccess
+	err := r.db.
+		Where("status = ?", status).
+		Order("granted_at").
+		Find(&accesses).
+		Error
+	if err != nil {
+		log.Printf("Failed to find emergency accesses: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return accesses, nil
+}
+
+func (r *gormEmergencyAccessRepository) Review(
+	id, reviewerID uuid.UUID,
+	status models.EmergencyReviewStatus,
+	note string,
+	at time.Time,
+) (*models.EmergencyAccess, error) {
+	var access models.EmergencyAccess
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&access, "id = ?", id).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &igakuErrors.EmergencyAccessNotFoundError{}
+			}
+			return err
+		}
+		if access.Status != models.PendingReview {
+			return &igakuErrors.EmergencyAccessReviewedError{}
+		}
+
+		access.Status = status
+		access.ReviewedBy = &reviewerID
+		access.ReviewedAt = &at
+		access.ReviewNote = note
+		if status == models.FlaggedReview && access.ActiveAt(at) {
+			access.RevokedAt = &at
+		}
+
+		return tx.
+			Model(&access).
+			Select("status", "reviewed_by", "reviewed_at", "review_note", "revoked_at").
+			Updates(&access).
+			Error
+	})
+	if err != nil {
+		var notFoundErr *igakuErrors.EmergencyAccessNotFoundError
+		var reviewedErr *igakuErrors.EmergencyAccessReviewedError
+		if errors.As(err, &notFoundErr) || errors.As(err, &reviewedErr) {
+			return nil, err
+		}
+		log.Printf("Failed to review emergency access: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return &access, nil
+}
+
+func (r *gormEmergencyAccessRepository) LogAccess(
+	event *models.EmergencyAccessEvent,
+) error {
+	if err := r.db.Create(event).Error; err != nil {
+		log.Printf("Failed to log emergency access: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormEmergencyAccessRepository) FindEvents(
+	accessID uuid.UUID,
+) ([]models.EmergencyAccessEvent, error) {
+	var events []models.EmergencyAccessEvent
+	err := r.db.
+		Where("access_id = ?", accessID).
+		Order("accessed_at").
+		Find(&events).
+		Error
+	if err != nil {
+		log.Printf("Failed to find emergency access events: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return events, nil
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// EncounterRepository never updates or deletes revisions and attachments;
+// changes to an encounter are only ever appended.
+type EncounterRepository interface {
+	// Create persists an encounter together with its first revision.
+	Create(enc *models.Encounter, rev *models.EncounterRevision) error
+	FindByID(id uuid.UUID) (*models.Encounter, error)
+	FindByAppointmentID(appointmentID uuid.UUID) (*models.Encounter, error)
+	// FindAccessible returns the encounters the user is the patient or
+	// treating doctor of, or has been delegated.
+	FindAccessible(userID uuid.UUID) ([]models.Encounter, error)
+	// AppendRevision assigns the next version to the revision and
+	// persists it.
+	AppendRevision(rev *models.EncounterRevision) error
+	AddAttachment(att *models.EncounterAttachment) error
+	FindAttachment(
+		encounterID, id uuid.UUID,
+	) (*models.EncounterAttachment, error)
+	AddDelegation(del *models.EncounterDelegation) error
+	RemoveDelegation(encounterID, doctorID uuid.UUID) error
+}
+
+type gormEncounterRepository struct {
+	db *gorm.DB
+}
+
+func NewGormEncounterRepository(db *gorm.DB) EncounterRepository {
+	return &gormEncounterRepository{db: db}
+}
+
+func (r *gormEncounterRepository) Create(
+	enc *models.Encounter, rev *models.EncounterRevision,
+) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(enc)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return &igakuErrors.EncounterAlreadyExistsError{}
+		}
+
+		rev.EncounterID = enc.ID
+		rev.Version = 1
+		return tx.Create(rev).Error
+	})
+	if err != nil {
+		var existsErr *igakuErrors.EncounterAlreadyExistsError
+		if errors.As(err, &existsErr) {
+			return err
+		}
+		log.Printf("Failed to create encounter: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+
+	enc.Revisions = []models.EncounterRevision{*rev}
+	return nil
+}
+
+func (r *gormEncounterRepository) FindByID(id uuid.UUID) (*models.Encounter, error) {
+	return r.findOne("id = ?", id)
+}
+
+func (r *gormEncounterRepository) FindByAppointmentID(
+	appointmentID uuid.UUID,
+) (*models.Encounter, error) {
+	return r.findOne("appointment_id = ?", appointmentID)
+}
+
+func (r *gormEncounterRepository) findOne(
+	query string, arg uuid.UUID,
+) (*models.Encounter, error) {
+	var enc models.Encounter
+	err := r.withDetails(r.db).Where(query, arg).First(&enc).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.EncounterNotFoundError{}
+		}
+		log.Printf("Failed to find encounter: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &enc, nil
+}
+
+func (r *gormEncounterRepository) FindAccessible(
+	userID uuid.UUID,
+) ([]models.Encounter, error) {
+	var encs []models.Encounter
+	err := r.withDetails(r.db).
+		Where(
+			"patient_id = ? OR doctor_id = ? OR EXISTS (" +
+			"SELECT 1 FROM encounter_delegations d " +
+			"WHERE d.encounter_id = encounters.id AND d.doctor_id = ?)",
+			userID, userID, userID,
+		).
+		Order("created_at desc").
+		Find(&encs).
+		Error
+	if err != nil {
+		log.Printf("Failed to find encounters: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return encs, nil
+}
+
+// withDetails preloads the full revision history and the attachment
+// metadata, leaving out attachment contents.
+func (r *gormEncounterRepository) withDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("version asc")
+		}).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB {
+			return db.Omit("data").Order("created_at asc")
+		}).
+		Preload("Delegations")
+}
+
+func (r *gormEncounterRepository) AppendRevision(rev *models.EncounterRevision) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the encounter serializes concurrent amendments so
+		// that each gets a distinct version.
+		var enc models.Encounter
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&enc, rev.EncounterID).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &igakuErrors.EncounterNotFoundError{}
+			}
+			return err
+		}
+
+		var version int
+		err = tx.
+			Model(&models.EncounterRevision{}).
+			Where("encounter_id = ?", rev.EncounterID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&version).
+			Error
+		if err != nil {
+			return err
+		}
+
+		rev.Version = version + 1
+		return tx.Create(rev).Error
+	})
+	if err != nil {
+		var notFoundErr *igakuErrors.EncounterNotFoundError
+		if errors.As(err, &notFoundErr) {
+			return err
+		}
+		log.Printf("Failed to append encounter revision: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormEncounterRepository) AddAttachment(att *models.EncounterAttachment) error {
+	err := r.db.Create(att).Error
+	if err != nil {
+		log.Printf("Failed to persist encounter attachment: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormEncounterRepository) FindAttachment(
+	encounterID, id uuid.UUID,
+) (*models.EncounterAttachment, error) {
+	var att models.EncounterAttachment
+	err := r.db.
+		Where("id = ? AND encounter_id = ?", id, encounterID).
+		First(&att).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.AttachmentNotFoundError{}
+		}
+		log.Printf("Failed to find encounter attachment: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &att, nil
+}
+
+func (r *gormEncounterRepository) AddDelegation(del *models.EncounterDelegation) error {
+	err := r.db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(del).
+		Error
+	if err != nil {
+		log.Printf("Failed to persist encounter delegation: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormEncounterRepository) RemoveDelegation(encounterID, doctorID uuid.UUID) error {
+	err := r.db.
+		Where("encounter_id = ? AND doctor_id = ?", encounterID, doctorID).
+		Delete(&models.EncounterDelegation{}).
+		Error
+	if err != nil {
+		log.Printf("Failed to remove encounter delegation: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"log"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+)
+
+type GenotypeRepository interface {
+	// FindByPatientID returns the genotypes ordered by gene, the latest
+	// result of each gene first.
+	FindByPatientID(patientID uuid.UUID) ([]models.Genotype, error)
+}
+
+type gormGenotypeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormGenotypeRepository(db *gorm.DB) GenotypeRepository {
+	return &gormGenotypeRepository{db: db}
+}
+
+func (r *gormGenotypeRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Genotype, error) {
+	var genotypes []models.Genotype
+	err := r.db.
+		Where("patient_id = ?", patientID).
+		Order("gene asc, recorded_at desc").
+		Find(&genotypes).
+		Error
+	if err != nil {
+		log.Printf("Failed to find genotypes: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return genotypes, nil
+}
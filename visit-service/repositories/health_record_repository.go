@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"errors"
+	"log"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type HealthRecordRepository interface {
+	Create(record models.Recordable) error
+	FindByID(kind models.HealthRecordKind, id uuid.UUID) (models.Recordable, error)
+	FindByPatientID(
+		kind models.HealthRecordKind, patientID uuid.UUID,
+	) ([]models.Recordable, error)
+	Save(record models.Recordable) error
+	Delete(record models.Recordable) error
+	// Summary loads every kind of record of the patient. Active
+	// prescriptions are left to the caller.
+	Summary(patientID uuid.UUID) (*models.PatientSummary, error)
+}
+
+type gormHealthRecordRepository struct {
+	db *gorm.DB
+}
+
+func NewGormHealthRecordRepository(db *gorm.DB) HealthRecordRepository {
+	return &gormHealthRecordRepository{db: db}
+}
+
+func (r *gormHealthRecordRepository) Create(record models.Recordable) error {
+	if err := r.db.Create(record).Error; err != nil {
+		log.Printf("Failed to create health record: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormHealthRecordRepository) FindByID(
+	kind models.HealthRecordKind, id uuid.UUID,
+) (models.Recordable, error) {
+	record, ok := models.NewHealthRecord(kind)
+	if !ok {
+		return nil, &igakuErrors.HealthRecordNotFoundError{}
+	}
+
+	err := r.db.First(record, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.HealthRecordNotFoundError{}
+		}
+		log.Printf("Failed to find health record: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return record, nil
+}
+
+func (r *gormHealthRecordRepository) FindByPatientID(
+	kind models.HealthRecordKind, patientID uuid.UUID,
+) ([]models.Recordable, error) {
+	var records []models.Recordable
+	var err error
+
+	switch kind {
+	case models.AllergyRecord:
+		var entries []models.Allergy
+		err = findByPatient(r.db, &entries, kind, patientID)
+		records = asRecords(entries)
+	case models.ConditionRecord:
+		var entries []models.ChronicCondition
+		err = findByPatient(r.db, &entries, kind, patientID)
+		records = asRecords(entries)
+	case models.ImmunizationRecord:
+		var entries []models.Immunization
+		err = findByPatient(r.db, &entries, kind, patientID)
+		records = asRecords(entries)
+	case models.MedicationRecord:
+		var entries []models.MedicationStatement
+		err = findByPatient(r.db, &entries, kind, patientID)
+		records = asRecords(entries)
+	case models.GenotypeRecord:
+		var entries []models.Genotype
+		err = findByPatient(r.db, &entries, kind, patientID)
+		records = asRecords(entries)
+	}
+	if err != nil {
+		log.Printf("Failed to find health records: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return records, nil
+}
+
+func (r *gormHealthRecordRepository) Save(record models.Recordable) error {
+	if err := r.db.Save(record).Error; err != nil {
+		log.Printf("Failed to save health record: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormHealthRecordRepository) Delete(record models.Recordable) error {
+	if err := r.db.Delete(record).Error; err != nil {
+		log.Printf("Failed to delete health record: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormHealthRecordRepository) Summary(
+	patientID uuid.UUID,
+) (*models.PatientSummary, error) {
+	summary := &models.PatientSummary{PatientID: patientID}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := findByPatient(tx, &summary.Allergies, models.AllergyRecord, patientID)
+		if err != nil {
+			return err
+		}
+		err = findByPatient(tx, &summary.Conditions, models.ConditionRecord, patientID)
+		if err != nil {
+			return err
+		}
+		err = findByPatient(
+			tx, &summary.Immunizations, models.ImmunizationRecord, patientID,
+		)
+		if err != nil {
+			return err
+		}
+		err = findByPatient(
+			tx, &summary.Medications, models.MedicationRecord, patientID,
+		)
+		if err != nil {
+			return err
+		}
+		return findByPatient(
+			tx, &summary.Genotypes, models.GenotypeRecord, patientID,
+		)
+	})
+	if err != nil {
+		log.Printf("Failed to load patient summary: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return summary, nil
+}
+
+// recordOrder lists the most relevant entries of each kind first.
+var recordOrder = map[models.HealthRecordKind]string{
+	models.AllergyRecord: "recorded_at asc",
+	models.ConditionRecord: "status asc, recorded_at asc",
+	models.ImmunizationRecord: "administered_on desc",
+	models.MedicationRecord: "ended_on desc nulls first, started_on desc",
+	models.GenotypeRecord: "gene asc, recorded_at desc",
+}
+
+func findByPatient(
+	db *gorm.DB, dest any, kind models.HealthRecordKind, patientID uuid.UUID,
+) error {
+	return db.
+		Where("patient_id = ?", patientID).
+		Order(recordOrder[kind]).
+		Find(dest).
+		Error
+}
+
+func asRecords[T any, P interface {
+	*T
+	models.Recordable
+}](entries []T) []models.Recordable {
+	records := make([]models.Recordable, len(entries))
+	for i := range entries {
+		records[i] = P(&entries[i])
+	}
+	return records
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type HomeAddressRepository interface {
+	// FindByPatientID returns nil if the patient has no home address.
+	FindByPatientID(patientID uuid.UUID) (*models.HomeAddress, error)
+	Save(address *models.HomeAddress) error
+	Delete(patientID uuid.UUID) error
+}
+
+type gormHomeAddressRepository struct {
+	db *gorm.DB
+}
+
+func NewGormHomeAddressRepository(db *gorm.DB) HomeAddressRepository {
+	return &gormHomeAddressRepository{db: db}
+}
+
+func (r *gormHomeAddressRepository) FindByPatientID(
+	patientID uuid.UUID,
+) (*models.HomeAddress, error) {
+	var address models.HomeAddress
+	err := r.db.First(&address, "patient_id = ?", patientID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Printf("Failed to find home address: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &address, nil
+}
+
+func (r *gormHomeAddressRepository) Save(address *models.HomeAddress) error {
+	err := r.db.
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(address).
+		Error
+	if err != nil {
+		log.Printf("Failed to save home address: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormHomeAddressRepository) Delete(patientID uuid.UUID) error {
+	result := r.db.Delete(&models.HomeAddress{}, "patient_id = ?", patientID)
+	if result.Error != nil {
+		log.Printf("Failed to delete home address: %v", result.Error)
+		return &commonsErrors.DatabaseError{}
+	}
+	if result.RowsAffected == 0 {
+		return &igakuErrors.HomeAddressNotFoundError{}
+	}
+	return nil
+}
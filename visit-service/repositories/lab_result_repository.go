@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"log"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+)
+
+type LabResultRepository interface {
+	// Create stores the results, skipping those whose source ID has already
+	// been imported, and returns how many were stored.
+	Create(results []models.LabResult) (int64, error)
+	// FindByPatientID returns the patient's results matching the filter,
+	// oldest first.
+	FindByPatientID(
+		patientID uuid.UUID, filter dtos.LabResultFilter,
+	) ([]models.LabResult, error)
+}
+
+type gormLabResultRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLabResultRepository(db *gorm.DB) LabResultRepository {
+	return &gormLabResultRepository{db: db}
+}
+
+func (r *gormLabResultRepository) Create(results []models.LabResult) (int64, error) {
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&results)
+	if res.Error != nil {
+		log.Printf("Failed to persist lab results: %v", res.Error)
+		return 0, &commonsErrors.DatabaseError{}
+	}
+	return res.RowsAffected, nil
+}
+
+func (r *gormLabResultRepository) FindByPatientID(
+	patientID uuid.UUID, filter dtos.LabResultFilter,
+) ([]models.LabResult, error) {
+	query := r.db.Where("patient_id = ?", patientID)
+	if filter.LOINCCode != "" {
+		query = query.Where("loinc_code = ?", filter.LOINCCode)
+	}
+	if filter.From != nil {
+		query = query.Where("observed_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("observed_at < ?", *filter.To)
+	}
+
+	var results []models.LabResult
+	err := query.Order("observed_at asc, id asc").Find(&results).Error
+	if err != nil {
+		log.Printf("Failed to find lab results: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return results, nil
+}
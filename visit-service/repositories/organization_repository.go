@@ -4,14 +4,49 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"log"
+	"strings"
+	"time"
+
+	"igaku/commons/dtos"
 	"igaku/visit-service/errors"
+	commonsErrors "igaku/commons/errors"
 	"igaku/visit-service/models"
 )
 
 type OrganizationRepository interface {
 	FindByID(id uuid.UUID) (*models.Organization, error)
+	// Search returns a page of the organizations whose name contains the
+	// given text, ignoring case, and the number of all matches. An empty
+	// name matches every organization.
+	Search(
+		id *uuid.UUID, name string, offset, limit int,
+	) ([]models.Organization, int64, error)
+	// FindNearest returns the organizations within radiusKm of the point,
+	// closest first. A non-empty specialty keeps only the organizations
+	// where a doctor of the specialty has slots.
+	FindNearest(
+		lat, lon, radiusKm float64, specialty string, limit int,
+	) ([]models.NearbyOrganization, error)
+	// FindWithoutOsmType returns at most limit organizations located at an
+	// OSM object of unknown type, stored before types were tracked, which
+	// did not fail to resolve since checkedBefore. Organizations never
+	// checked come first, then those which failed the longest ago.
+	FindWithoutOsmType(
+		checkedBefore time.Time, limit int,
+	) ([]models.Organization, error)
+	SetOsmType(id uuid.UUID, osmType dtos.OsmType) error
+	// MarkOsmTypeUnresolved records that the OSM type of the organization
+	// failed to resolve, so that it is not retried before the others.
+	MarkOsmTypeUnresolved(id uuid.UUID, now time.Time) error
 }
 
+// orgEarthPoint locates an organization on the earth. It must match the
+// expression of the idx_organizations_earth_point index for the index to
+// be used. Organizations without valid coordinates have no point.
+const orgEarthPoint = "ll_to_earth(" +
+	"coordinate_or_null(loc_lat), coordinate_or_null(loc_lon))"
+
 type gormOrganizationRepository struct {
 	db *gorm.DB
 }
@@ -28,3 +63,133 @@ func (r *gormOrganizationRepository) FindByID(id uuid.UUID) (*models.Organizatio
 	}
 	return &org, nil
 }
+
+func (r *gormOrganizationRepository) Search(
+	id *uuid.UUID, name string, offset, limit int,
+) ([]models.Organization, int64, error) {
+	query := r.db.Model(&models.Organization{})
+	if id != nil {
+		query = query.Where("id = ?", *id)
+	}
+	if name != "" {
+		pattern := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).
+			Replace(strings.ToLower(name))
+		query = query.Where("LOWER(name) LIKE ?", "%"+pattern+"%")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		log.Printf("Failed to count organizations: %v", err)
+		return nil, 0, &commonsErrors.DatabaseError{}
+	}
+
+	var orgs []models.Organization
+	err := query.
+		Order("name asc, id asc").
+		Offset(offset).
+		Limit(limit).
+		Find(&orgs).
+		Error
+	if err != nil {
+		log.Printf("Failed to search organizations: %v", err)
+		return nil, 0, &commonsErrors.DatabaseError{}
+	}
+
+	return orgs, count, nil
+}
+
+func (r *gormOrganizationRepository) FindNearest(
+	lat, lon, radiusKm float64, specialty string, limit int,
+) ([]models.NearbyOrganization, error) {
+	radius := radiusKm * 1000
+	query := r.db.
+		Table("organizations").
+		Select(
+			"organizations.*, earth_distance(ll_to_earth(?, ?), "+
+				orgEarthPoint+") / 1000 AS distance_km",
+			lat, lon,
+		).
+		Where("earth_box(ll_to_earth(?, ?), ?) @> "+orgEarthPoint,
+			lat, lon, radius).
+		Where("earth_distance(ll_to_earth(?, ?), "+orgEarthPoint+") <= ?",
+			lat, lon, radius)
+	if specialty != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM slots s
+			JOIN doctor_specialties d ON d.doctor_id = s.doctor_id
+			WHERE s.organization_id = organizations.id
+				AND d.specialty = ?
+		)`, strings.ToLower(specialty))
+	}
+
+	var orgs []models.NearbyOrganization
+	err := query.
+		Order("distance_km asc, name asc, id asc").
+		Limit(limit).
+		Scan(&orgs).
+		Error
+	if err != nil {
+		log.Printf("Failed to find nearest organizations: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return orgs, nil
+}
+
+func (r *gormOrganizationRepository) FindWithoutOsmType(
+	checkedBefore time.Time, limit int,
+) ([]models.Organization, error) {
+	var orgs []models.Organization
+	err := r.db.
+		Where("loc_osm_type = '' AND loc_id <> 0").
+		Where(
+			"osm_type_checked_at IS NULL OR osm_type_checked_at < ?",
+			checkedBefore,
+		).
+		Order("osm_type_checked_at asc nulls first, id asc").
+		Limit(limit).
+		Find(&orgs).
+		Error
+	if err != nil {
+		log.Printf("Failed to find organizations without OSM type: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return orgs, nil
+}
+
+func (r *gormOrganizationRepository) SetOsmType(
+	id uuid.UUID, osmType dtos.OsmType,
+) error {
+	result := r.db.
+		Model(&models.Organization{}).
+		Where("id = ?", id).
+		Update("loc_osm_type", osmType)
+	if result.Error != nil {
+		log.Printf("Failed to set OSM type of organization: %v", result.Error)
+		return &commonsErrors.DatabaseError{}
+	}
+	if result.RowsAffected == 0 {
+		return &errors.OrganizationNotFoundError{}
+	}
+
+	return nil
+}
+
+func (r *gormOrganizationRepository) MarkOsmTypeUnresolved(
+	id uuid.UUID, now time.Time,
+) error {
+	result := r.db.
+		Model(&models.Organization{}).
+		Where("id = ?", id).
+		Update("osm_type_checked_at", now)
+	if result.Error != nil {
+		log.Printf("Failed to mark OSM type of organization as unresolved: %v", result.Error)
+		return &commonsErrors.DatabaseError{}
+	}
+	if result.RowsAffected == 0 {
+		return &errors.OrganizationNotFoundError{}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type PrescriptionRepository interface {
+	Persist(p *models.Prescription) error
+	FindByID(id uuid.UUID) (*models.Prescription, error)
+	FindByVerificationCode(code string) (*models.Prescription, error)
+	// FindByPatientID returns the patient's prescriptions. If activeAt is
+	// not nil, only prescriptions that can be dispensed at that time are
+	// returned.
+	FindByPatientID(
+		patientID uuid.UUID, activeAt *time.Time,
+	) ([]models.Prescription, error)
+	FindByDoctorID(doctorID uuid.UUID) ([]models.Prescription, error)
+	// Cancel cancels a prescription which is still issued.
+	Cancel(id uuid.UUID) (*models.Prescription, error)
+	// Dispense records a fill of the prescription with the given
+	// verification code, provided it is active at now.
+	Dispense(code string, now time.Time) (*models.Prescription, error)
+	// ExpireDue marks issued prescriptions whose validity ended before now
+	// as expired and returns how many were affected.
+	ExpireDue(now time.Time) (int64, error)
+}
+
+type gormPrescriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPrescriptionRepository(db *gorm.DB) PrescriptionRepository {
+	return &gormPrescriptionRepository{db: db}
+}
+
+func (r *gormPrescriptionRepository) Persist(p *models.Prescription) error {
+	err := r.db.Create(p).Error
+	if err != nil {
+		log.Printf("Failed to persist prescription: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormPrescriptionRepository) FindByID(id uuid.UUID) (*models.Prescription, error) {
+	return r.findOne("id = ?", id)
+}
+
+func (r *gormPrescriptionRepository) FindByVerificationCode(
+	code string,
+) (*models.Prescription, error) {
+	return r.findOne("verification_code = ?", code)
+}
+
+func (r *gormPrescriptionRepository) findOne(
+	query string, arg any,
+) (*models.Prescription, error) {
+	var p models.Prescription
+	err := r.db.Where(query, arg).First(&p).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.PrescriptionNotFoundError{}
+		}
+		log.Printf("Failed to find prescription: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &p, nil
+}
+
+func (r *gormPrescriptionRepository) FindByPatientID(
+	patientID uuid.UUID, activeAt *time.Time,
+) ([]models.Prescription, error) {
+	query := r.db.Where("patient_id = ?", patientID)
+	if activeAt != nil {
+		query = query.
+			Where("status = ?", models.PrescriptionIssued).
+			Where("valid_from <= ? AND valid_until > ?", *activeAt, *activeAt)
+	}
+
+	var ps []models.Prescription
+	err := query.Order("issued_at desc").Find(&ps).Error
+	if err != nil {
+		log.Printf("Failed to find prescriptions by patient: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return ps, nil
+}
+
+func (r *gormPrescriptionRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.Prescription, error) {
+	var ps []models.Prescription
+	err := r.db.
+		Where("doctor_id = ?", doctorID).
+		Order("issued_at desc").
+		Find(&ps).
+		Error
+	if err != nil {
+		log.Printf("Failed to find prescriptions by doctor: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return ps, nil
+}
+
+func (r *gormPrescriptionRepository) Cancel(id uuid.UUID) (*models.Prescription, error) {
+	return r.transition("id = ?", id, func(p *models.Prescription) error {
+		if p.Status != models.PrescriptionIssued {
+			return &igakuErrors.PrescriptionNotActiveError{}
+		}
+		p.Status = models.PrescriptionCancelled
+		return nil
+	})
+}
+
+func (r *gormPrescriptionRepository) Dispense(
+	code string, now time.Time,
+) (*models.Prescription, error) {
+	return r.transition("verification_code = ?", code, func(p *models.Prescription) error {
+		if !p.IsActive(now) {
+			return &igakuErrors.PrescriptionNotActiveError{}
+		}
+		p.Fills++
+		if p.Fills > p.Refills {
+			p.Status = models.PrescriptionDispensed
+		}
+		return nil
+	})
+}
+
+// transition locks a single prescription, applies the change and saves
+// the resulting fills and status.
+func (r *gormPrescriptionRepository) transition(
+	query string, arg any, apply func(p *models.Prescription) error,
+) (*models.Prescription, error) {
+	var p models.Prescription
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(query, arg).
+			First(&p).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &igakuErrors.PrescriptionNotFoundError{}
+			}
+			return err
+		}
+
+		if err := apply(&p); err != nil {
+			return err
+		}
+
+		return tx.
+			Model(&p).
+			Select("fills", "status").
+			Updates(&p).
+			Error
+	})
+	if err != nil {
+		var notFoundErr *igakuErrors.PrescriptionNotFoundError
+		var notActiveErr *igakuErrors.PrescriptionNotActiveError
+		if errors.As(err, &notFoundErr) || errors.As(err, &notActiveErr) {
+			return nil, err
+		}
+		log.Printf("Failed to update prescription: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return &p, nil
+}
+
+func (r *gormPrescriptionRepository) ExpireDue(now time.Time) (int64, error) {
+	res := r.db.
+		Model(&models.Prescription{}).
+		Where("status = ? AND valid_until <= ?", models.PrescriptionIssued, now).
+		Update("status", models.PrescriptionExpired)
+	if res.Error != nil {
+		log.Printf("Failed to expire prescriptions: %v", res.Error)
+		return 0, &commonsErrors.DatabaseError{}
+	}
+	return res.RowsAffected, nil
+}
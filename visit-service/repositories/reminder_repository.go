@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+)
+
+type ReminderRepository interface {
+	// ClaimDue atomically marks as reminded, and returns, all booked
+	// appointments starting within (from, to] for which a reminder of
+	// the given kind has not been claimed yet and which the patient has
+	// not opted out of.
+	ClaimDue(
+		kind models.ReminderKind, from, to time.Time,
+	) ([]models.Appointment, error)
+	FindPreference(userID uuid.UUID) (*models.ReminderPreference, error)
+	SavePreference(pref *models.ReminderPreference) error
+}
+
+type gormReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewGormReminderRepository(db *gorm.DB) ReminderRepository {
+	return &gormReminderRepository{db: db}
+}
+
+func (r *gormReminderRepository) ClaimDue(
+	kind models.ReminderKind, from, to time.Time,
+) ([]models.Appointment, error) {
+	var appts []models.Appointment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Rows locked by another replica are skipped rather than
+		// waited for, so concurrent schedulers split the work instead
+		// of serializing on it.
+		err := tx.
+			Joins("Organization").
+			Where("appointments.status = ?", models.Booked).
+			Where("appointments.starts_at > ? AND appointments.starts_at <= ?", from, to).
+			Where(
+				"NOT EXISTS (SELECT 1 FROM reminders r " +
+				"WHERE r.appointment_id = appointments.id AND r.kind = ?)",
+				kind,
+			).
+			Where(
+				fmt.Sprintf(
+					"COALESCE((SELECT p.%s FROM reminder_preferences p " +
+					"WHERE p.user_id = appointments.patient_id), TRUE)",
+					string(kind),
+				),
+			).
+			Clauses(clause.Locking{
+				Strength: "UPDATE",
+				Table: clause.Table{Name: "appointments"},
+				Options: "SKIP LOCKED",
+			}).
+			Find(&appts).
+			Error
+		if err != nil {
+			return err
+		}
+
+		// The insert is the actual claim: should a concurrent
+		// transaction have claimed an appointment in the meantime, the
+		// conflicting row is skipped and the appointment is dropped
+		// from the result.
+		now := time.Now()
+		claimed := make([]models.Appointment, 0, len(appts))
+		for _, appt := range appts {
+			res := tx.
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.Reminder{
+					AppointmentID: appt.ID,
+					Kind: kind,
+					SentAt: now,
+				})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected > 0 {
+				claimed = append(claimed, appt)
+			}
+		}
+		appts = claimed
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to claim due '%s' reminders: %v", kind, err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return appts, nil
+}
+
+func (r *gormReminderRepository) FindPreference(
+	userID uuid.UUID,
+) (*models.ReminderPreference, error) {
+	var pref models.ReminderPreference
+	err := r.db.First(&pref, "user_id = ?", userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Printf("Failed to find reminder preference: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &pref, nil
+}
+
+func (r *gormReminderRepository) SavePreference(
+	pref *models.ReminderPreference,
+) error {
+	err := r.db.
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(pref).
+		Error
+	if err != nil {
+		log.Printf("Failed to save reminder preference: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type SlotRepository interface {
+	Persist(slot *models.Slot) error
+	FindByID(id uuid.UUID) (*models.Slot, error)
+	FindFree(
+		doctorID, organizationID *uuid.UUID, from, to time.Time,
+	) ([]models.Slot, error)
+	// Book turns a free slot into an appointment for the given patient.
+	Book(slotID, patientID uuid.UUID) (*models.Appointment, error)
+	// FindOrganizationIDs returns the organizations the doctor offers
+	// slots at.
+	FindOrganizationIDs(doctorID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type gormSlotRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSlotRepository(db *gorm.DB) SlotRepository {
+	return &gormSlotRepository{db: db}
+}
+
+func (r *gormSlotRepository) Persist(slot *models.Slot) error {
+	err := r.db.Omit(clause.Associations).Create(slot).Error
+	if err != nil {
+		log.Printf("Failed to persist slot: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormSlotRepository) FindByID(id uuid.UUID) (*models.Slot, error) {
+	var slot models.Slot
+	err := r.db.First(&slot, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.SlotNotFoundError{}
+		}
+		log.Printf("Failed to find slot: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &slot, nil
+}
+
+func (r *gormSlotRepository) FindFree(
+	doctorID, organizationID *uuid.UUID, from, to time.Time,
+) ([]models.Slot, error) {
+	query := r.db.
+		Where("status = ?", models.Free).
+		Where("starts_at >= ? AND starts_at < ?", from, to)
+	if doctorID != nil {
+		query = query.Where("doctor_id = ?", *doctorID)
+	}
+	if organizationID != nil {
+		query = query.Where("organization_id = ?", *organizationID)
+	}
+
+	var slots []models.Slot
+	err := query.Order("starts_at asc").Find(&slots).Error
+	if err != nil {
+		log.Printf("Failed to find free slots: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return slots, nil
+}
+
+func (r *gormSlotRepository) Book(
+	slotID, patientID uuid.UUID,
+) (*models.Appointment, error) {
+	var appt *models.Appointment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		slot, err := lockSlot(tx, slotID)
+		if err != nil {
+			return err
+		}
+
+		if slot.Status != models.Free {
+			return &igakuErrors.SlotUnavailableError{}
+		}
+
+		appt, err = bookSlot(tx, slot, patientID)
+		return err
+	})
+	if err != nil {
+		return nil, slotError(err)
+	}
+
+	return appt, nil
+}
+
+func (r *gormSlotRepository) FindOrganizationIDs(
+	doctorID uuid.UUID,
+) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.
+		Model(&models.Slot{}).
+		Where("doctor_id = ?", doctorID).
+		Distinct().
+		Pluck("organization_id", &ids).
+		Error
+	if err != nil {
+		log.Printf("Failed to find organizations of doctor: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return ids, nil
+}
+
+func lockSlot(tx *gorm.DB, id uuid.UUID) (*models.Slot, error) {
+	var slot models.Slot
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&slot, id).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.SlotNotFoundError{}
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// bookSlot creates an appointment for a slot which must already be locked
+// by the transaction, and places the doctor on the patient's care team.
+func bookSlot(
+	tx *gorm.DB, slot *models.Slot, patientID uuid.UUID,
+) (*models.Appointment, error) {
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		PatientID: patientID,
+		DoctorID: slot.DoctorID,
+		OrganizationID: slot.OrganizationID,
+		SlotID: &slot.ID,
+		StartsAt: slot.StartsAt,
+		Status: models.Booked,
+	}
+	if err := tx.Omit(clause.Associations).Create(appt).Error; err != nil {
+		return nil, err
+	}
+
+	err := tx.Model(slot).Update("status", models.Taken).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := joinCareTeam(tx, appt); err != nil {
+		return nil, err
+	}
+
+	return appt, nil
+}
+
+// slotError passes through domain errors and logs any other as a database
+// failure.
+func slotError(err error) error {
+	var notFoundErr *igakuErrors.SlotNotFoundError
+	var unavailableErr *igakuErrors.SlotUnavailableError
+	if errors.As(err, &notFoundErr) || errors.As(err, &unavailableErr) {
+		return err
+	}
+	log.Printf("Failed to book slot: %v", err)
+	return &commonsErrors.DatabaseError{}
+}
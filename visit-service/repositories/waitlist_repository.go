@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"errors"
+	"log"
+	"time"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type WaitlistRepository interface {
+	Persist(entry *models.WaitlistEntry) error
+	FindByID(id uuid.UUID) (*models.WaitlistEntry, error)
+	FindByPatientID(patientID uuid.UUID) ([]models.WaitlistEntry, error)
+	// Withdraw removes the entry from the waitlist. If a slot was being
+	// held for it, the slot is released and its ID returned.
+	Withdraw(id uuid.UUID) (*uuid.UUID, error)
+	// OfferSlot holds a free slot for the longest waiting matching entry
+	// until expiresAt. It returns nil if no entry matches the slot or the
+	// slot is not free.
+	OfferSlot(slotID uuid.UUID, expiresAt time.Time) (*models.WaitlistOffer, error)
+	FindOfferByID(id uuid.UUID) (*models.WaitlistOffer, error)
+	AcceptOffer(id uuid.UUID, now time.Time) (*models.Appointment, error)
+	// DeclineOffer releases the slot held by the offer and returns the
+	// entry to the waitlist.
+	DeclineOffer(id uuid.UUID) error
+	// ExpireOffers releases the slots of all offers that expired before
+	// now, returning their IDs.
+	ExpireOffers(now time.Time) ([]uuid.UUID, error)
+}
+
+type gormWaitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &gormWaitlistRepository{db: db}
+}
+
+func (r *gormWaitlistRepository) Persist(entry *models.WaitlistEntry) error {
+	err := r.db.Create(entry).Error
+	if err != nil {
+		log.Printf("Failed to persist waitlist entry: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+	return nil
+}
+
+func (r *gormWaitlistRepository) FindByID(id uuid.UUID) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := r.db.First(&entry, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.WaitlistEntryNotFoundError{}
+		}
+		log.Printf("Failed to find waitlist entry: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &entry, nil
+}
+
+func (r *gormWaitlistRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.
+		Where("patient_id = ?", patientID).
+		Order("created_at asc").
+		Find(&entries).
+		Error
+	if err != nil {
+		log.Printf("Failed to find waitlist entries: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return entries, nil
+}
+
+func (r *gormWaitlistRepository) Withdraw(id uuid.UUID) (*uuid.UUID, error) {
+	var freedSlotID *uuid.UUID
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var entry models.WaitlistEntry
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&entry, id).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &igakuErrors.WaitlistEntryNotFoundError{}
+			}
+			return err
+		}
+
+		var offer models.WaitlistOffer
+		err = tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("entry_id = ? AND status = ?", id, models.Pending).
+			First(&offer).
+			Error
+		if err == nil {
+			if err := releaseOffer(tx, &offer, models.Declined); err != nil {
+				return err
+			}
+			freedSlotID = &offer.SlotID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		return tx.
+			Model(&entry).
+			Update("status", models.Withdrawn).
+			Error
+	})
+	if err != nil {
+		return nil, waitlistError(err)
+	}
+
+	return freedSlotID, nil
+}
+
+func (r *gormWaitlistRepository) OfferSlot(
+	slotID uuid.UUID, expiresAt time.Time,
+) (*models.WaitlistOffer, error) {
+	var offer *models.WaitlistOffer
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		slot, err := lockSlot(tx, slotID)
+		if err != nil {
+			return err
+		}
+
+		if slot.Status != models.Free || !slot.StartsAt.After(time.Now()) {
+			return nil
+		}
+
+		// Patients who already let an offer for this very slot lapse
+		// are not offered it again.
+		var entry models.WaitlistEntry
+		err = tx.
+			Where("status = ?", models.Waiting).
+			Where("doctor_id IS NULL OR doctor_id = ?", slot.DoctorID).
+			Where("organization_id IS NULL OR organization_id = ?", slot.OrganizationID).
+			Where("? BETWEEN \"from\" AND \"to\"", slot.StartsAt).
+			Where(
+				"NOT EXISTS (SELECT 1 FROM waitlist_offers o " +
+				"WHERE o.entry_id = waitlist_entries.id AND o.slot_id = ?)",
+				slot.ID,
+			).
+			Order("created_at asc").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			First(&entry).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		offer = &models.WaitlistOffer{
+			ID: uuid.New(),
+			EntryID: entry.ID,
+			SlotID: slot.ID,
+			ExpiresAt: expiresAt,
+			Status: models.Pending,
+		}
+		if err := tx.Omit(clause.Associations).Create(offer).Error; err != nil {
+			return err
+		}
+
+		err = tx.Model(slot).Update("status", models.Held).Error
+		if err != nil {
+			return err
+		}
+
+		entry.Status = models.Offered
+		err = tx.Model(&entry).Update("status", entry.Status).Error
+		if err != nil {
+			return err
+		}
+
+		offer.Entry = entry
+		offer.Slot = *slot
+		return tx.First(&offer.Slot.Organization, slot.OrganizationID).Error
+	})
+	if err != nil {
+		return nil, waitlistError(err)
+	}
+
+	return offer, nil
+}
+
+func (r *gormWaitlistRepository) FindOfferByID(id uuid.UUID) (*models.WaitlistOffer, error) {
+	var offer models.WaitlistOffer
+	err := r.db.Preload("Entry").Preload("Slot").First(&offer, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.OfferNotFoundError{}
+		}
+		log.Printf("Failed to find waitlist offer: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+	return &offer, nil
+}
+
+func (r *gormWaitlistRepository) AcceptOffer(
+	id uuid.UUID, now time.Time,
+) (*models.Appointment, error) {
+	var appt *models.Appointment
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		offer, err := lockPendingOffer(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if !offer.ExpiresAt.After(now) {
+			return &igakuErrors.OfferExpiredError{}
+		}
+
+		slot, err := lockSlot(tx, offer.SlotID)
+		if err != nil {
+			return err
+		}
+
+		appt, err = bookSlot(tx, slot, offer.Entry.PatientID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(offer).Update("status", models.Accepted).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.
+			Model(&offer.Entry).
+			Update("status", models.Fulfilled).
+			Error
+	})
+	if err != nil {
+		return nil, waitlistError(err)
+	}
+
+	return appt, nil
+}
+
+func (r *gormWaitlistRepository) DeclineOffer(id uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		offer, err := lockPendingOffer(tx, id)
+		if err != nil {
+			return err
+		}
+
+		return releaseOffer(tx, offer, models.Declined)
+	})
+	if err != nil {
+		return waitlistError(err)
+	}
+
+	return nil
+}
+
+func (r *gormWaitlistRepository) ExpireOffers(now time.Time) ([]uuid.UUID, error) {
+	var slotIDs []uuid.UUID
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var offers []models.WaitlistOffer
+		err := tx.
+			Where("status = ? AND expires_at <= ?", models.Pending, now).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&offers).
+			Error
+		if err != nil {
+			return err
+		}
+
+		for _, offer := range offers {
+			if err := releaseOffer(tx, &offer, models.Expired); err != nil {
+				return err
+			}
+			slotIDs = append(slotIDs, offer.SlotID)
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to expire waitlist offers: %v", err)
+		return nil, &commonsErrors.DatabaseError{}
+	}
+
+	return slotIDs, nil
+}
+
+func lockPendingOffer(tx *gorm.DB, id uuid.UUID) (*models.WaitlistOffer, error) {
+	var offer models.WaitlistOffer
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("status = ?", models.Pending).
+		First(&offer, id).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &igakuErrors.OfferNotFoundError{}
+		}
+		return nil, err
+	}
+
+	err = tx.First(&offer.Entry, offer.EntryID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &offer, nil
+}
+
+// releaseOffer closes a pending offer with the given status, frees its slot
+// and puts the entry back on the waitlist.
+func releaseOffer(
+	tx *gorm.DB, offer *models.WaitlistOffer, status models.OfferStatus,
+) error {
+	err := tx.Model(offer).Update("status", status).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.
+		Model(&models.Slot{}).
+		Where("id = ? AND status = ?", offer.SlotID, models.Held).
+		Update("status", models.Free).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return tx.
+		Model(&models.WaitlistEntry{}).
+		Where("id = ? AND status = ?", offer.EntryID, models.Offered).
+		Update("status", models.Waiting).
+		Error
+}
+
+func waitlistError(err error) error {
+	var entryNotFoundErr *igakuErrors.WaitlistEntryNotFoundError
+	var offerNotFoundErr *igakuErrors.OfferNotFoundError
+	var offerExpiredErr *igakuErrors.OfferExpiredError
+	var slotNotFoundErr *igakuErrors.SlotNotFoundError
+	if errors.As(err, &entryNotFoundErr) ||
+		errors.As(err, &offerNotFoundErr) ||
+		errors.As(err, &offerExpiredErr) ||
+		errors.As(err, &slotNotFoundErr) {
+		return err
+	}
+	log.Printf("Waitlist operation failed: %v", err)
+	return &commonsErrors.DatabaseError{}
+}
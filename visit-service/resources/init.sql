@@ -1,18 +1,225 @@
-INSERT INTO organizations (id, name, loc_id, loc_lat, loc_lon, loc_name)
+INSERT INTO organizations (
+    id, name, loc_id, loc_osm_type, loc_lat, loc_lon, loc_name,
+    loc_house_number, loc_street, loc_city, loc_postcode, loc_county,
+    loc_country, loc_country_code
+)
 VALUES
     (
         '86e6a1f3-d7aa-4e74-a20a-ea78bc13340b',
         'Massachusetts General Hospital',
         117853077,
+        'W',
         '42.3628605',
         '-71.0687530',
-        'Massachusetts General Hospital, 55, Fruit Street, West End, Boston, Suffolk County, Massachusetts, 02114, United States'
+        'Massachusetts General Hospital, 55, Fruit Street, West End, Boston, Suffolk County, Massachusetts, 02114, United States',
+        '55',
+        'Fruit Street',
+        'Boston',
+        '02114',
+        'Suffolk County',
+        'United States',
+        'us'
     ),
     (
         'a6868293-b590-44f9-bf7e-1381beaf17d6',
         'McLean Hospital',
         495950127,
+        'W',
         '42.3932679',
         '-71.1902447',
-        'McLean Hospital, 115, Mill Street, Kendall Gardens, Belmont, Middlesex County, Massachusetts, 02478, United States'
+        'McLean Hospital, 115, Mill Street, Kendall Gardens, Belmont, Middlesex County, Massachusetts, 02478, United States',
+        '115',
+        'Mill Street',
+        'Belmont',
+        '02478',
+        'Middlesex County',
+        'United States',
+        'us'
+    );
+
+INSERT INTO doctor_specialties (doctor_id, specialty)
+VALUES
+    ('e2c66717-12bb-4b6a-b7b6-3be939e170ad', 'cardiology'),
+    ('e2c66717-12bb-4b6a-b7b6-3be939e170ad', 'internal medicine');
+
+INSERT INTO slots (id, doctor_id, organization_id, starts_at, ends_at, status)
+VALUES
+    (
+        '9a1f4c2e-6b7d-4e8a-8c3f-2d5e6f7a8b90',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        'a6868293-b590-44f9-bf7e-1381beaf17d6',
+        NOW() + INTERVAL '7 days',
+        NOW() + INTERVAL '7 days 30 minutes',
+        'taken'
+    ),
+    (
+        '3e7b2d1c-4a5f-4b6e-9d8c-1f2a3b4c5d6e',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        '86e6a1f3-d7aa-4e74-a20a-ea78bc13340b',
+        NOW() + INTERVAL '3 days',
+        NOW() + INTERVAL '3 days 30 minutes',
+        'free'
+    );
+
+INSERT INTO appointments (id, patient_id, doctor_id, organization_id, slot_id, starts_at, status)
+VALUES
+    (
+        '5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        '86e6a1f3-d7aa-4e74-a20a-ea78bc13340b',
+        NULL,
+        NOW() + INTERVAL '20 hours',
+        'booked'
+    ),
+    (
+        'c4b0a1b2-8d6e-4c2f-9e1a-7f3d5b9a0c11',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        'a6868293-b590-44f9-bf7e-1381beaf17d6',
+        '9a1f4c2e-6b7d-4e8a-8c3f-2d5e6f7a8b90',
+        NOW() + INTERVAL '7 days',
+        'booked'
+    );
+
+INSERT INTO care_assignments (id, patient_id, doctor_id, organization_id, role, source, assigned_by, assigned_at)
+VALUES
+    (
+        '6d7e8f9a-0b1c-4d2e-8f3a-4b5c6d7e8f9a',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        '86e6a1f3-d7aa-4e74-a20a-ea78bc13340b',
+        'primary',
+        'manual',
+        '99ab51c4-a544-4352-a8df-4632ff8b105d',
+        NOW() - INTERVAL '30 days'
+    ),
+    (
+        '7e8f9a0b-1c2d-4e3f-9a4b-5c6d7e8f9a0b',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        'a6868293-b590-44f9-bf7e-1381beaf17d6',
+        'member',
+        'appointment',
+        NULL,
+        NOW()
+    );
+
+INSERT INTO allergies (id, patient_id, recorded_by, recorded_at, substance, reaction, severity)
+VALUES
+    (
+        '3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        NOW() - INTERVAL '1 year',
+        'penicillin',
+        'Hives',
+        'severe'
+    );
+
+INSERT INTO chronic_conditions (id, patient_id, recorded_by, recorded_at, code, description, status)
+VALUES
+    (
+        '4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        NOW() - INTERVAL '1 year',
+        'I10',
+        'Essential (primary) hypertension',
+        'active'
+    );
+
+INSERT INTO immunizations (id, patient_id, recorded_by, recorded_at, vaccine, dose_number, administered_on)
+VALUES
+    (
+        '5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        NOW() - INTERVAL '6 months',
+        'Tetanus, diphtheria (Td)',
+        1,
+        NOW() - INTERVAL '2 years'
+    );
+
+INSERT INTO genotypes (id, patient_id, recorded_by, recorded_at, gene, diplotype, phenotype, tested_on, laboratory)
+VALUES
+    (
+        '8b9c0d1e-2f3a-4b4c-9d5e-6f7a8b9c0d1e',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        NOW() - INTERVAL '3 months',
+        'CYP2C19',
+        '*1/*2',
+        'intermediate metabolizer',
+        NOW() - INTERVAL '4 months',
+        'MGH Molecular Diagnostics'
+    );
+
+INSERT INTO lab_results (id, patient_id, loinc_code, name, value, unit, reported_value, reported_unit, reference_low, reference_high, flag, observed_at, recorded_by, recorded_at, source)
+VALUES
+    (
+        '6f7a8b9c-0d1e-4f2a-9b3c-4d5e6f7a8b9c',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        '2345-7',
+        'Glucose',
+        5.2,
+        'mmol/L',
+        5.2,
+        'mmol/L',
+        3.9,
+        5.5,
+        'N',
+        NOW() - INTERVAL '6 months',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        NOW() - INTERVAL '6 months',
+        'manual'
+    ),
+    (
+        '7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        '2345-7',
+        'Glucose',
+        6.105,
+        'mmol/L',
+        110,
+        'mg/dL',
+        3.9,
+        5.5,
+        'H',
+        NOW() - INTERVAL '1 month',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        NOW() - INTERVAL '1 month',
+        'manual'
+    );
+
+
+INSERT INTO conversations (id, patient_id, doctor_id, appointment_id, subject, created_at, last_message_at)
+VALUES
+    (
+        '0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        NULL,
+        'Blood glucose follow-up',
+        NOW() - INTERVAL '2 days',
+        NOW() - INTERVAL '1 day'
+    );
+
+INSERT INTO messages (id, conversation_id, sender_id, body, sent_at, read_at)
+VALUES
+    (
+        '1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a',
+        '0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f',
+        'e2c66717-12bb-4b6a-b7b6-3be939e170ad',
+        'Your last glucose result was elevated. Please book a fasting test.',
+        NOW() - INTERVAL '2 days',
+        NOW() - INTERVAL '2 days'
+    ),
+    (
+        '2e3f4a5b-6c7d-4e8f-9a0b-1c2d3e4f5a6b',
+        '0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f',
+        '0b6f13da-efb9-4221-9e89-e2729ae90030',
+        'Thank you, I will book one this week.',
+        NOW() - INTERVAL '1 day',
+        NULL
     );
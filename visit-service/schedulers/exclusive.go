@@ -0,0 +1,26 @@
+package schedulers
+
+import (
+	"log"
+	"time"
+)
+
+// Lock runs work only if no one else holds the lock of the key.
+type Lock interface {
+	TryRun(key int64, fn func() error) (bool, error)
+}
+
+// Exclusive returns a job running the given job under the lock of the key,
+// so that a job scheduled on every replica only runs on one of them at a
+// time. Runs finding the lock held are skipped.
+func Exclusive(name string, lock Lock, key int64, job Job) Job {
+	return func(now time.Time) error {
+		ran, err := lock.TryRun(key, func() error {
+			return job(now)
+		})
+		if err == nil && !ran {
+			log.Printf("Skipped '%s': running on another replica", name)
+		}
+		return err
+	}
+}
@@ -0,0 +1,52 @@
+package schedulers
+
+import (
+	"log"
+	"time"
+)
+
+// Job is a unit of periodic work. It is passed the time at which the run
+// started.
+type Job func(now time.Time) error
+
+// Scheduler runs a job immediately after Start and then once every
+// interval until Shutdown is called.
+type Scheduler struct {
+	name		string
+	job		Job
+	interval	time.Duration
+	done		chan struct{}
+}
+
+func NewScheduler(name string, job Job, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		name: name,
+		job: job,
+		interval: interval,
+		done: make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start() {
+	go func() {
+		log.Printf(" [*] Running '%s' every %s", s.name, s.interval)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.job(time.Now()); err != nil {
+				log.Printf("Job '%s' failed: %v", s.name, err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) Shutdown() {
+	close(s.done)
+}
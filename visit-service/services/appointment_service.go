@@ -0,0 +1,203 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"log"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type AppointmentService interface {
+	CreateSlot(
+		doctorID uuid.UUID, req dtos.SlotRequest,
+	) (*models.Slot, error)
+	ListFreeSlots(
+		doctorID, organizationID *uuid.UUID, from, to time.Time,
+	) ([]models.Slot, error)
+	Book(slotID, patientID uuid.UUID) (*models.Appointment, error)
+	ListAppointments(
+		userID uuid.UUID, role commonsModels.Role,
+	) ([]models.Appointment, error)
+	Cancel(
+		id, userID uuid.UUID, role commonsModels.Role,
+	) (*models.Appointment, error)
+}
+
+type appointmentService struct {
+	apptRepo	repositories.AppointmentRepository
+	slotRepo	repositories.SlotRepository
+	orgRepo		repositories.OrganizationRepository
+	waitlist	WaitlistService
+	eventClient	clients.EventClient
+	homeRepo	repositories.HomeAddressRepository
+	geoClient	clients.GeoClient
+}
+
+func NewAppointmentService(
+	apptRepo repositories.AppointmentRepository,
+	slotRepo repositories.SlotRepository,
+	orgRepo repositories.OrganizationRepository,
+	waitlist WaitlistService,
+	eventClient clients.EventClient,
+	homeRepo repositories.HomeAddressRepository,
+	geoClient clients.GeoClient,
+) AppointmentService {
+	return &appointmentService{
+		apptRepo: apptRepo,
+		slotRepo: slotRepo,
+		orgRepo: orgRepo,
+		waitlist: waitlist,
+		eventClient: eventClient,
+		homeRepo: homeRepo,
+		geoClient: geoClient,
+	}
+}
+
+func (s *appointmentService) CreateSlot(
+	doctorID uuid.UUID, req dtos.SlotRequest,
+) (*models.Slot, error) {
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, &igakuErrors.InvalidTimeRangeError{}
+	}
+
+	if _, err := s.orgRepo.FindByID(req.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	slot := &models.Slot{
+		ID: uuid.New(),
+		DoctorID: doctorID,
+		OrganizationID: req.OrganizationID,
+		StartsAt: req.StartsAt,
+		EndsAt: req.EndsAt,
+		Status: models.Free,
+	}
+	if err := s.slotRepo.Persist(slot); err != nil {
+		return nil, err
+	}
+
+	return slot, nil
+}
+
+func (s *appointmentService) ListFreeSlots(
+	doctorID, organizationID *uuid.UUID, from, to time.Time,
+) ([]models.Slot, error) {
+	if !to.After(from) {
+		return nil, &igakuErrors.InvalidTimeRangeError{}
+	}
+
+	return s.slotRepo.FindFree(doctorID, organizationID, from, to)
+}
+
+func (s *appointmentService) Book(
+	slotID, patientID uuid.UUID,
+) (*models.Appointment, error) {
+	appt, err := s.slotRepo.Book(slotID, patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(
+		s.eventClient, commonsDtos.AppointmentBooked, appt.ID,
+		appt.PatientID, appt.DoctorID,
+	)
+
+	appt.TravelEstimates = s.estimateTravel(appt)
+
+	return appt, nil
+}
+
+// estimateTravel estimates the travel of the patient from home to the
+// organization of the appointment. The appointment has already been booked,
+// so failures are only logged and leave the confirmation without
+// estimates.
+func (s *appointmentService) estimateTravel(
+	appt *models.Appointment,
+) []commonsDtos.RouteEstimate {
+	home, err := s.homeRepo.FindByPatientID(appt.PatientID)
+	if err != nil || home == nil {
+		return nil
+	}
+
+	org, err := s.orgRepo.FindByID(appt.OrganizationID)
+	if err != nil {
+		return nil
+	}
+	lat, latErr := commonsDtos.ParseCoordinate(org.Location.Lat)
+	lon, lonErr := commonsDtos.ParseCoordinate(org.Location.Lon)
+	if latErr != nil || lonErr != nil {
+		log.Printf("Organization %s has no valid coordinates", org.ID)
+		return nil
+	}
+
+	estimates, err := s.geoClient.EstimateRoute(commonsDtos.RouteRequest{
+		FromLat: commonsDtos.Coordinate(home.Lat),
+		FromLon: commonsDtos.Coordinate(home.Lon),
+		ToLat: lat,
+		ToLon: lon,
+	})
+	if err != nil {
+		log.Printf(
+			"Failed to estimate the travel to appointment %s: %v",
+			appt.ID, err,
+		)
+		return nil
+	}
+
+	return estimates
+}
+
+func (s *appointmentService) ListAppointments(
+	userID uuid.UUID, role commonsModels.Role,
+) ([]models.Appointment, error) {
+	if role == commonsModels.Doctor {
+		return s.apptRepo.FindByDoctorID(userID)
+	}
+	return s.apptRepo.FindByPatientID(userID)
+}
+
+func (s *appointmentService) Cancel(
+	id, userID uuid.UUID, role commonsModels.Role,
+) (*models.Appointment, error) {
+	appt, err := s.apptRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != commonsModels.Admin &&
+		appt.PatientID != userID &&
+		appt.DoctorID != userID {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	appt, err = s.apptRepo.Cancel(id)
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(
+		s.eventClient, commonsDtos.AppointmentCancelled, appt.ID,
+		appt.PatientID, appt.DoctorID,
+	)
+
+	if appt.SlotID != nil {
+		// The cancellation itself succeeded, so failing to pass the
+		// slot on to the waitlist must not be reported to the caller.
+		if err := s.waitlist.OfferSlot(*appt.SlotID); err != nil {
+			log.Printf(
+				"Failed to offer slot %s to the waitlist: %v",
+				*appt.SlotID, err,
+			)
+		}
+	}
+
+	return appt, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"errors"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// CareTeamService manages the doctors treating each patient. Doctors join
+// a patient's care team when the patient books an appointment with them;
+// admins may also assign doctors, e.g. a primary doctor, and remove them.
+type CareTeamService interface {
+	// List returns the care team of the patient. Only the patient, admins
+	// and the members of the care team may see it.
+	List(
+		patientID, userID uuid.UUID, role commonsModels.Role,
+	) ([]models.CareAssignment, error)
+	Assign(
+		patientID, adminID uuid.UUID, req dtos.CareAssignmentRequest,
+	) (*models.CareAssignment, error)
+	Remove(id, patientID uuid.UUID) (*models.CareAssignment, error)
+	// Patients returns the current assignments of the doctor, one per
+	// patient and organization.
+	Patients(doctorID uuid.UUID) ([]models.CareAssignment, error)
+}
+
+type careTeamService struct {
+	repo		repositories.CareTeamRepository
+	orgRepo		repositories.OrganizationRepository
+	userClient	clients.UserClient
+}
+
+func NewCareTeamService(
+	repo repositories.CareTeamRepository,
+	orgRepo repositories.OrganizationRepository,
+	userClient clients.UserClient,
+) CareTeamService {
+	return &careTeamService{
+		repo: repo,
+		orgRepo: orgRepo,
+		userClient: userClient,
+	}
+}
+
+func (s *careTeamService) List(
+	patientID, userID uuid.UUID, role commonsModels.Role,
+) ([]models.CareAssignment, error) {
+	switch role {
+	case commonsModels.Admin:
+	case commonsModels.Patient:
+		if patientID != userID {
+			return nil, &igakuErrors.AccessDeniedError{}
+		}
+	case commonsModels.Doctor:
+		member, err := s.repo.IsMember(patientID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !member {
+			return nil, &igakuErrors.AccessDeniedError{}
+		}
+	default:
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	return s.repo.FindByPatientID(patientID)
+}
+
+func (s *careTeamService) Assign(
+	patientID, adminID uuid.UUID, req dtos.CareAssignmentRequest,
+) (*models.CareAssignment, error) {
+	err := s.checkUser(
+		patientID, commonsModels.Patient,
+		&igakuErrors.InvalidCareTeamPatientError{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = s.checkUser(
+		req.DoctorID, commonsModels.Doctor,
+		&igakuErrors.InvalidCareTeamMemberError{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.orgRepo.FindByID(req.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.CareTeamMember
+	}
+
+	return s.repo.Assign(&models.CareAssignment{
+		ID: uuid.New(),
+		PatientID: patientID,
+		DoctorID: req.DoctorID,
+		OrganizationID: req.OrganizationID,
+		Role: role,
+		Source: models.ManualSource,
+		AssignedBy: &adminID,
+		AssignedAt: time.Now(),
+	})
+}
+
+func (s *careTeamService) Remove(
+	id, patientID uuid.UUID,
+) (*models.CareAssignment, error) {
+	return s.repo.End(id, patientID, time.Now())
+}
+
+func (s *careTeamService) Patients(
+	doctorID uuid.UUID,
+) ([]models.CareAssignment, error) {
+	return s.repo.FindByDoctorID(doctorID)
+}
+
+// checkUser makes sure the user exists and has the role, returning invalid
+// otherwise.
+func (s *careTeamService) checkUser(
+	id uuid.UUID, role commonsModels.Role, invalid error,
+) error {
+	user, err := s.userClient.FindByID(id)
+	if errors.Is(err, &commonsErrors.UserNotFoundError{}) {
+		return invalid
+	}
+	if err != nil {
+		return err
+	}
+	if user.Role != role {
+		return invalid
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"errors"
+	"slices"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// ConsentService manages the consents of the currently logged-in patient.
+// They are enforced by PatientAccess.
+type ConsentService interface {
+	Grant(patientID uuid.UUID, req dtos.ConsentRequest) (*models.Consent, error)
+	// List returns the consents of the patient, newest first. activeOnly
+	// leaves out those revoked, expired or not in force yet.
+	List(patientID uuid.UUID, activeOnly bool) ([]models.Consent, error)
+	Revoke(id, patientID uuid.UUID) (*models.Consent, error)
+	// History returns the audit history of the patient's consents.
+	History(patientID uuid.UUID) ([]models.ConsentEvent, error)
+}
+
+type consentService struct {
+	repo		repositories.ConsentRepository
+	orgRepo		repositories.OrganizationRepository
+	userClient	clients.UserClient
+}
+
+func NewConsentService(
+	repo repositories.ConsentRepository,
+	orgRepo repositories.OrganizationRepository,
+	userClient clients.UserClient,
+) ConsentService {
+	return &consentService{
+		repo: repo,
+		orgRepo: orgRepo,
+		userClient: userClient,
+	}
+}
+
+func (s *consentService) Grant(
+	patientID uuid.UUID, req dtos.ConsentRequest,
+) (*models.Consent, error) {
+	categories := []models.ConsentCategory{}
+	for _, category := range req.Categories {
+		if !slices.Contains(models.ConsentCategories, category) {
+			return nil, &igakuErrors.InvalidConsentCategoryError{}
+		}
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+
+	now := time.Now()
+	validFrom := now
+	if req.ValidFrom != nil {
+		validFrom = *req.ValidFrom
+	}
+	if req.ValidUntil != nil &&
+		(!req.ValidUntil.After(validFrom) || !req.ValidUntil.After(now)) {
+		return nil, &igakuErrors.InvalidTimeRangeError{}
+	}
+
+	if err := s.checkGrantee(req.GranteeType, req.GranteeID); err != nil {
+		return nil, err
+	}
+
+	decision := req.Decision
+	if decision == "" {
+		decision = models.Permit
+	}
+
+	consent := &models.Consent{
+		ID: uuid.New(),
+		PatientID: patientID,
+		GranteeType: req.GranteeType,
+		GranteeID: req.GranteeID,
+		Decision: decision,
+		Categories: categories,
+		ValidFrom: validFrom,
+		ValidUntil: req.ValidUntil,
+		CreatedAt: now,
+	}
+	if err := s.repo.Create(consent, patientID); err != nil {
+		return nil, err
+	}
+
+	return consent, nil
+}
+
+func (s *consentService) List(
+	patientID uuid.UUID, activeOnly bool,
+) ([]models.Consent, error) {
+	consents, err := s.repo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if activeOnly {
+		now := time.Now()
+		consents = slices.DeleteFunc(consents, func(c models.Consent) bool {
+			return !c.ActiveAt(now)
+		})
+	}
+
+	return consents, nil
+}
+
+func (s *consentService) Revoke(
+	id, patientID uuid.UUID,
+) (*models.Consent, error) {
+	return s.repo.Revoke(id, patientID, patientID, time.Now())
+}
+
+func (s *consentService) History(
+	patientID uuid.UUID,
+) ([]models.ConsentEvent, error) {
+	return s.repo.FindEvents(patientID)
+}
+
+// checkGrantee makes sure the grantee is a doctor or a known
+// organization.
+func (s *consentService) checkGrantee(
+	granteeType models.GranteeType, granteeID uuid.UUID,
+) error {
+	switch granteeType {
+	case models.DoctorGrantee:
+		user, err := s.userClient.FindByID(granteeID)
+		if errors.Is(err, &commonsErrors.UserNotFoundError{}) {
+			return &igakuErrors.InvalidGranteeError{}
+		}
+		if err != nil {
+			return err
+		}
+		if user.Role != commonsModels.Doctor {
+			return &igakuErrors.InvalidGranteeError{}
+		}
+	case models.OrganizationGrantee:
+		_, err := s.orgRepo.FindByID(granteeID)
+		if errors.Is(err, &igakuErrors.OrganizationNotFoundError{}) {
+			return &igakuErrors.InvalidGranteeError{}
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return &igakuErrors.InvalidGranteeError{}
+	}
+
+	return nil
+}
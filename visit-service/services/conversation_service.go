@@ -0,0 +1,302 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"fmt"
+	"log"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// ConversationService handles the secure messaging between patients and
+// the doctors treating them. Recipients are notified of new messages by
+// mail, without their contents, and by a notification event.
+type ConversationService interface {
+	// Start starts a conversation between the user and the participant
+	// with its first message. The doctor must be on the patient's care
+	// team, unless the conversation is about one of their appointments.
+	Start(
+		userID uuid.UUID, role commonsModels.Role, req dtos.ConversationRequest,
+	) (*models.Conversation, error)
+	// List returns the conversations of the user, most recently active
+	// first.
+	List(userID uuid.UUID) ([]models.Conversation, error)
+	// Unread returns the number of messages the user has not read yet.
+	Unread(userID uuid.UUID) (*dtos.UnreadCount, error)
+	Messages(id, userID uuid.UUID) ([]models.Message, error)
+	Send(
+		id, userID uuid.UUID, req dtos.MessageRequest,
+	) (*models.Message, error)
+	// MarkRead marks the messages of the conversation sent to the user as
+	// read.
+	MarkRead(id, userID uuid.UUID) error
+}
+
+type conversationService struct {
+	repo		repositories.ConversationRepository
+	apptRepo	repositories.AppointmentRepository
+	careTeamRepo	repositories.CareTeamRepository
+	documentRepo	repositories.DocumentRepository
+	access		PatientAccess
+	userClient	clients.UserClient
+	mailClient	clients.MailClient
+	eventClient	clients.EventClient
+	from		string
+}
+
+func NewConversationService(
+	repo repositories.ConversationRepository,
+	apptRepo repositories.AppointmentRepository,
+	careTeamRepo repositories.CareTeamRepository,
+	documentRepo repositories.DocumentRepository,
+	access PatientAccess,
+	userClient clients.UserClient,
+	mailClient clients.MailClient,
+	eventClient clients.EventClient,
+	from string,
+) ConversationService {
+	return &conversationService{
+		repo: repo,
+		apptRepo: apptRepo,
+		careTeamRepo: careTeamRepo,
+		documentRepo: documentRepo,
+		access: access,
+		userClient: userClient,
+		mailClient: mailClient,
+		eventClient: eventClient,
+		from: from,
+	}
+}
+
+func (s *conversationService) Start(
+	userID uuid.UUID, role commonsModels.Role, req dtos.ConversationRequest,
+) (*models.Conversation, error) {
+	patientID, doctorID := userID, req.ParticipantID
+	if role == commonsModels.Doctor {
+		patientID, doctorID = req.ParticipantID, userID
+	}
+
+	err := s.checkRelationship(patientID, doctorID, req.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	conversation := &models.Conversation{
+		ID: uuid.New(),
+		PatientID: patientID,
+		DoctorID: doctorID,
+		AppointmentID: req.AppointmentID,
+		Subject: req.Subject,
+		CreatedAt: now,
+		LastMessageAt: now,
+	}
+
+	message, err := s.newMessage(conversation, userID, req.Message, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(conversation, message); err != nil {
+		return nil, err
+	}
+
+	s.notify(conversation, userID)
+
+	return conversation, nil
+}
+
+func (s *conversationService) List(
+	userID uuid.UUID,
+) ([]models.Conversation, error) {
+	return s.repo.FindByParticipant(userID)
+}
+
+func (s *conversationService) Unread(userID uuid.UUID) (*dtos.UnreadCount, error) {
+	count, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.UnreadCount{Unread: count}, nil
+}
+
+func (s *conversationService) Messages(
+	id, userID uuid.UUID,
+) ([]models.Message, error) {
+	if _, err := s.find(id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindMessages(id)
+}
+
+func (s *conversationService) Send(
+	id, userID uuid.UUID, req dtos.MessageRequest,
+) (*models.Message, error) {
+	conversation, err := s.find(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.newMessage(conversation, userID, req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddMessage(message); err != nil {
+		return nil, err
+	}
+
+	s.notify(conversation, userID)
+
+	return message, nil
+}
+
+func (s *conversationService) MarkRead(id, userID uuid.UUID) error {
+	if _, err := s.find(id, userID); err != nil {
+		return err
+	}
+
+	_, err := s.repo.MarkRead(id, userID, time.Now())
+	return err
+}
+
+// find loads a conversation of the user. Conversations of others are
+// reported as not found.
+func (s *conversationService) find(
+	id, userID uuid.UUID,
+) (*models.Conversation, error) {
+	conversation, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.Participant(userID) {
+		return nil, &igakuErrors.ConversationNotFoundError{}
+	}
+
+	return conversation, nil
+}
+
+// checkRelationship makes sure the doctor treats the patient, at the
+// appointment if one is given or as a member of their care team.
+func (s *conversationService) checkRelationship(
+	patientID, doctorID uuid.UUID, appointmentID *uuid.UUID,
+) error {
+	if appointmentID != nil {
+		appt, err := s.apptRepo.FindByID(*appointmentID)
+		if err != nil {
+			return err
+		}
+		if appt.PatientID != patientID || appt.DoctorID != doctorID {
+			return &igakuErrors.CareRelationshipRequiredError{}
+		}
+		return nil
+	}
+
+	member, err := s.careTeamRepo.IsMember(patientID, doctorID)
+	if err != nil {
+		return err
+	}
+	if !member {
+		return &igakuErrors.CareRelationshipRequiredError{}
+	}
+	return nil
+}
+
+func (s *conversationService) newMessage(
+	conversation *models.Conversation,
+	senderID uuid.UUID,
+	req dtos.MessageRequest,
+	at time.Time,
+) (*models.Message, error) {
+	if req.DocumentID != nil {
+		if err := s.checkAttachment(conversation, *req.DocumentID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.Message{
+		ID: uuid.New(),
+		ConversationID: conversation.ID,
+		SenderID: senderID,
+		Body: req.Body,
+		DocumentID: req.DocumentID,
+		SentAt: at,
+	}, nil
+}
+
+// checkAttachment makes sure the document belongs to the patient and that
+// the doctor may see the patient's documents, so that attaching it does
+// not get around the patient's consents.
+func (s *conversationService) checkAttachment(
+	conversation *models.Conversation, documentID uuid.UUID,
+) error {
+	doc, err := s.documentRepo.FindByID(documentID)
+	if err != nil {
+		return err
+	}
+	if doc.PatientID != conversation.PatientID {
+		return &igakuErrors.DocumentNotFoundError{}
+	}
+
+	return s.access.Check(
+		conversation.PatientID, conversation.DoctorID,
+		commonsModels.Doctor, models.DocumentsCategory,
+	)
+}
+
+// notify tells the recipient of a message about it. The message has
+// already been sent, so failures are only logged.
+func (s *conversationService) notify(
+	conversation *models.Conversation, senderID uuid.UUID,
+) {
+	recipientID := conversation.Recipient(senderID)
+	publishEvent(
+		s.eventClient, commonsDtos.MessageReceived, conversation.ID,
+		recipientID,
+	)
+	if err := s.sendNotification(recipientID, senderID); err != nil {
+		log.Printf(
+			"Failed to notify user %s about a message in conversation %s: %v",
+			recipientID, conversation.ID, err,
+		)
+	}
+}
+
+func (s *conversationService) sendNotification(
+	recipientID, senderID uuid.UUID,
+) error {
+	recipient, err := s.userClient.FindByID(recipientID)
+	if err != nil {
+		return err
+	}
+	sender, err := s.userClient.FindByID(senderID)
+	if err != nil {
+		return err
+	}
+
+	// Neither the subject nor the body of the message are included, as
+	// mail is not a secure channel.
+	to := []string{recipient.Email}
+	msg := []byte(
+		fmt.Sprintf("From: %s\r\n", s.from) +
+		fmt.Sprintf("To: %s\r\n", recipient.Email) +
+		"Subject: New message in Igaku\r\n" +
+		"\r\n" +
+		fmt.Sprintf("Hello %s,\r\n", recipient.Username) +
+		"\r\n" +
+		fmt.Sprintf("You have a new message from %s.\r\n", sender.Username) +
+		"Log in to Igaku to read it.\r\n",
+	)
+
+	return s.mailClient.SendMail(to, msg)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"errors"
+	"slices"
+	"strings"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/repositories"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// DoctorSpecialtyService manages the specialties of doctors, which patients
+// may use to narrow their search for organizations.
+type DoctorSpecialtyService interface {
+	List(doctorID uuid.UUID) ([]string, error)
+	// Replace sets the specialties of the doctor and returns them
+	// normalized: in lower case, sorted and without duplicates.
+	Replace(doctorID uuid.UUID, specialties []string) ([]string, error)
+}
+
+type doctorSpecialtyService struct {
+	repo		repositories.DoctorSpecialtyRepository
+	userClient	clients.UserClient
+}
+
+func NewDoctorSpecialtyService(
+	repo repositories.DoctorSpecialtyRepository,
+	userClient clients.UserClient,
+) DoctorSpecialtyService {
+	return &doctorSpecialtyService{repo: repo, userClient: userClient}
+}
+
+func (s *doctorSpecialtyService) List(doctorID uuid.UUID) ([]string, error) {
+	return s.repo.FindByDoctorID(doctorID)
+}
+
+func (s *doctorSpecialtyService) Replace(
+	doctorID uuid.UUID, specialties []string,
+) ([]string, error) {
+	user, err := s.userClient.FindByID(doctorID)
+	if errors.Is(err, &commonsErrors.UserNotFoundError{}) {
+		return nil, &igakuErrors.InvalidDoctorError{}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != commonsModels.Doctor {
+		return nil, &igakuErrors.InvalidDoctorError{}
+	}
+
+	normalized := make([]string, 0, len(specialties))
+	for _, specialty := range specialties {
+		specialty = strings.ToLower(strings.TrimSpace(specialty))
+		if specialty != "" {
+			normalized = append(normalized, specialty)
+		}
+	}
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+
+	if err := s.repo.Replace(doctorID, normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
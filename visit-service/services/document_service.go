@@ -0,0 +1,369 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/storage"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type DocumentService interface {
+	// Upload stores a document of the patient. The content is checked
+	// against the size and type limits before it reaches the blob store.
+	Upload(
+		patientID, userID uuid.UUID,
+		role commonsModels.Role,
+		upload dtos.DocumentUpload,
+		content io.Reader,
+	) (*models.Document, error)
+	List(
+		patientID, userID uuid.UUID, role commonsModels.Role,
+	) ([]models.Document, error)
+	Get(
+		id, userID uuid.UUID, role commonsModels.Role,
+	) (*models.Document, error)
+	// Delete removes a document. Only its uploader and admins may delete
+	// it.
+	Delete(id, userID uuid.UUID, role commonsModels.Role) error
+	// Link issues a short-lived URL through which the user can download
+	// the document.
+	Link(
+		id, userID uuid.UUID, role commonsModels.Role,
+	) (*dtos.DocumentLink, error)
+	// Open checks a link and opens the content of the document. Access is
+	// checked again, as it may have been revoked since the link was issued.
+	Open(
+		id uuid.UUID, params dtos.DocumentLinkParams,
+	) (*models.Document, io.ReadCloser, error)
+}
+
+type documentService struct {
+	repo		repositories.DocumentRepository
+	access		PatientAccess
+	encounterRepo	repositories.EncounterRepository
+	store		storage.BlobStore
+	linkKey		[]byte
+	linkTTL		time.Duration
+}
+
+// documentLinkContext sets the key of document links apart from any other
+// key derived from the same secret, such as the JWT signing key.
+const documentLinkContext = "document-link"
+
+func NewDocumentService(
+	repo repositories.DocumentRepository,
+	access PatientAccess,
+	encounterRepo repositories.EncounterRepository,
+	store storage.BlobStore,
+	linkSecret []byte,
+	linkTTL time.Duration,
+) DocumentService {
+	// Links are signed with a key of their own, so that a signature can
+	// never pass for anything else signed with the secret.
+	mac := hmac.New(sha256.New, linkSecret)
+	mac.Write([]byte(documentLinkContext))
+
+	return &documentService{
+		repo: repo,
+		access: access,
+		encounterRepo: encounterRepo,
+		store: store,
+		linkKey: mac.Sum(nil),
+		linkTTL: linkTTL,
+	}
+}
+
+func (s *documentService) Upload(
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+	upload dtos.DocumentUpload,
+	content io.Reader,
+) (*models.Document, error) {
+	if role == commonsModels.Admin {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+	err := s.access.Check(patientID, userID, role, models.DocumentsCategory)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEncounter(upload.EncounterID, patientID, userID, role); err != nil {
+		return nil, err
+	}
+
+	category := upload.Category
+	if category == "" {
+		category = models.OtherDocument
+	}
+	if !slices.Contains(models.DocumentCategories, category) {
+		return nil, &igakuErrors.InvalidDocumentCategoryError{}
+	}
+
+	// Spool the content to disk, so that it is only stored once its size,
+	// type and checksum are known.
+	tmp, err := os.CreateTemp("", "igaku-document-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(
+		io.MultiWriter(tmp, hash),
+		io.LimitReader(content, models.MaxDocumentSize+1),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if size > models.MaxDocumentSize {
+		return nil, &igakuErrors.DocumentTooLargeError{}
+	}
+	if size == 0 {
+		return nil, &igakuErrors.EmptyDocumentError{}
+	}
+
+	contentType, err := sniffContentType(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(models.DocumentContentTypes, contentType) {
+		return nil, &igakuErrors.UnsupportedDocumentTypeError{}
+	}
+
+	fileName := documentFileName(upload.FileName)
+	title := strings.TrimSpace(upload.Title)
+	if title == "" {
+		title = fileName
+	}
+
+	id := uuid.New()
+	doc := &models.Document{
+		ID: id,
+		PatientID: patientID,
+		EncounterID: upload.EncounterID,
+		Title: title,
+		Category: category,
+		FileName: fileName,
+		ContentType: contentType,
+		Size: size,
+		Checksum: hex.EncodeToString(hash.Sum(nil)),
+		StorageKey: "patients/" + patientID.String() + "/" + id.String(),
+		UploadedBy: userID,
+		CreatedAt: time.Now(),
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	blob := storage.Blob{
+		Key: doc.StorageKey,
+		ContentType: doc.ContentType,
+		Size: doc.Size,
+		Checksum: doc.Checksum,
+	}
+	if err := s.store.Put(blob, tmp); err != nil {
+		log.Printf("Failed to store document: %v", err)
+		return nil, err
+	}
+
+	if err := s.repo.Create(doc); err != nil {
+		if err := s.store.Delete(doc.StorageKey); err != nil {
+			log.Printf("Failed to remove orphaned document: %v", err)
+		}
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (s *documentService) List(
+	patientID, userID uuid.UUID, role commonsModels.Role,
+) ([]models.Document, error) {
+	err := s.access.Check(patientID, userID, role, models.DocumentsCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByPatientID(patientID)
+}
+
+func (s *documentService) Get(
+	id, userID uuid.UUID, role commonsModels.Role,
+) (*models.Document, error) {
+	doc, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.access.Check(
+		doc.PatientID, userID, role, models.DocumentsCategory,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (s *documentService) Delete(
+	id, userID uuid.UUID, role commonsModels.Role,
+) error {
+	doc, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if role != commonsModels.Admin && doc.UploadedBy != userID {
+		return &igakuErrors.AccessDeniedError{}
+	}
+
+	if err := s.repo.Delete(doc); err != nil {
+		return err
+	}
+
+	// The document is gone once its metadata is; leftover content is only
+	// logged.
+	if err := s.store.Delete(doc.StorageKey); err != nil {
+		log.Printf("Failed to remove document content: %v", err)
+	}
+
+	return nil
+}
+
+func (s *documentService) Link(
+	id, userID uuid.UUID, role commonsModels.Role,
+) (*dtos.DocumentLink, error) {
+	doc, err := s.Get(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.linkTTL).Truncate(time.Second)
+	params := dtos.DocumentLinkParams{
+		UserID: userID,
+		Role: role,
+		Expires: expiresAt.Unix(),
+	}
+	params.Signature = s.sign(doc.ID, params)
+
+	query := url.Values{}
+	query.Set("user", params.UserID.String())
+	query.Set("role", string(params.Role))
+	query.Set("expires", strconv.FormatInt(params.Expires, 10))
+	query.Set("signature", params.Signature)
+
+	return &dtos.DocumentLink{
+		URL: "/visit/documents/" + doc.ID.String() + "/content?" + query.Encode(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *documentService) Open(
+	id uuid.UUID, params dtos.DocumentLinkParams,
+) (*models.Document, io.ReadCloser, error) {
+	expected := s.sign(id, params)
+	if !hmac.Equal([]byte(expected), []byte(params.Signature)) {
+		return nil, nil, &igakuErrors.InvalidDocumentLinkError{}
+	}
+	if time.Now().Unix() > params.Expires {
+		return nil, nil, &igakuErrors.InvalidDocumentLinkError{}
+	}
+
+	doc, err := s.Get(id, params.UserID, params.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(doc.StorageKey)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Missing content of document %s", doc.ID)
+			return nil, nil, &igakuErrors.DocumentNotFoundError{}
+		}
+		log.Printf("Failed to open document: %v", err)
+		return nil, nil, err
+	}
+
+	return doc, content, nil
+}
+
+// checkEncounter makes sure the referenced encounter is one of the
+// patient's and, for doctors, one they led.
+func (s *documentService) checkEncounter(
+	encounterID *uuid.UUID,
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+) error {
+	if encounterID == nil {
+		return nil
+	}
+
+	enc, err := s.encounterRepo.FindByID(*encounterID)
+	if err != nil {
+		return err
+	}
+	if enc.PatientID != patientID ||
+		(role == commonsModels.Doctor && enc.DoctorID != userID) {
+		return &igakuErrors.AccessDeniedError{}
+	}
+
+	return nil
+}
+
+func (s *documentService) sign(id uuid.UUID, params dtos.DocumentLinkParams) string {
+	mac := hmac.New(sha256.New, s.linkKey)
+	mac.Write([]byte(strings.Join([]string{
+		id.String(),
+		params.UserID.String(),
+		string(params.Role),
+		strconv.FormatInt(params.Expires, 10),
+	}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// sniffContentType detects the type of a file from its first bytes.
+func sniffContentType(file *os.File) (string, error) {
+	head := make([]byte, 512)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return "", &igakuErrors.UnsupportedDocumentTypeError{}
+	}
+	return mediaType, nil
+}
+
+// documentFileName keeps the base name of an uploaded file, whichever
+// separator the client uses.
+func documentFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" {
+		return "document"
+	}
+	return name
+}
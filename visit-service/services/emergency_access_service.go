@@ -0,0 +1,199 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// minJustificationLength keeps doctors from waving emergency access
+// through with a word or two.
+const minJustificationLength = 20
+
+// EmergencyAccessService grants doctors "break-the-glass" access to
+// patients outside their care team. The access is enforced by
+// PatientAccess, which logs every read made under it.
+type EmergencyAccessService interface {
+	// Request grants the doctor emergency access to the patient, notifying
+	// the patient by mail, and returns the token to use for it.
+	Request(
+		doctorID uuid.UUID, req dtos.EmergencyAccessRequest,
+	) (*dtos.EmergencyAccessGrant, error)
+	// List returns the emergency accesses of the doctor, newest first.
+	List(doctorID uuid.UUID) ([]models.EmergencyAccess, error)
+	// Queue returns the emergency accesses with the review status, oldest
+	// first.
+	Queue(status models.EmergencyReviewStatus) ([]models.EmergencyAccess, error)
+	Review(
+		id, adminID uuid.UUID, req dtos.EmergencyReviewRequest,
+	) (*models.EmergencyAccess, error)
+	// Events returns the reads made under the emergency access.
+	Events(id uuid.UUID) ([]models.EmergencyAccessEvent, error)
+}
+
+type emergencyAccessService struct {
+	repo		repositories.EmergencyAccessRepository
+	userClient	clients.UserClient
+	mailClient	clients.MailClient
+	ttl		time.Duration
+	from		string
+}
+
+func NewEmergencyAccessService(
+	repo repositories.EmergencyAccessRepository,
+	userClient clients.UserClient,
+	mailClient clients.MailClient,
+	ttl time.Duration,
+	from string,
+) EmergencyAccessService {
+	return &emergencyAccessService{
+		repo: repo,
+		userClient: userClient,
+		mailClient: mailClient,
+		ttl: ttl,
+		from: from,
+	}
+}
+
+func (s *emergencyAccessService) Request(
+	doctorID uuid.UUID, req dtos.EmergencyAccessRequest,
+) (*dtos.EmergencyAccessGrant, error) {
+	justification := strings.TrimSpace(req.Justification)
+	if len([]rune(justification)) < minJustificationLength {
+		return nil, &igakuErrors.InvalidJustificationError{}
+	}
+
+	patient, err := s.userClient.FindByID(req.PatientID)
+	if errors.Is(err, &commonsErrors.UserNotFoundError{}) {
+		return nil, &igakuErrors.InvalidEmergencyPatientError{}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if patient.Role != commonsModels.Patient {
+		return nil, &igakuErrors.InvalidEmergencyPatientError{}
+	}
+
+	now := time.Now()
+	access := &models.EmergencyAccess{
+		ID: uuid.New(),
+		DoctorID: doctorID,
+		PatientID: req.PatientID,
+		Justification: justification,
+		GrantedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+		Status: models.PendingReview,
+	}
+
+	token, err := utils.GenerateEmergencyJWTToken(access)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(access); err != nil {
+		return nil, err
+	}
+
+	log.Printf(
+		"[EMERGENCY] Doctor %s granted emergency access %s to patient %s until %s",
+		doctorID, access.ID, access.PatientID,
+		access.ExpiresAt.Format(time.RFC3339),
+	)
+
+	// The access has already been granted, so a failed notification is
+	// only logged.
+	if err := s.notify(patient, access); err != nil {
+		log.Printf(
+			"Failed to notify patient about emergency access %s: %v",
+			access.ID, err,
+		)
+	}
+
+	return &dtos.EmergencyAccessGrant{Access: access, Token: token}, nil
+}
+
+func (s *emergencyAccessService) List(
+	doctorID uuid.UUID,
+) ([]models.EmergencyAccess, error) {
+	return s.repo.FindByDoctorID(doctorID)
+}
+
+func (s *emergencyAccessService) Queue(
+	status models.EmergencyReviewStatus,
+) ([]models.EmergencyAccess, error) {
+	if status == "" {
+		status = models.PendingReview
+	}
+	if !slices.Contains(models.EmergencyReviewStatuses, status) {
+		return nil, &igakuErrors.InvalidReviewStatusError{}
+	}
+
+	return s.repo.FindByStatus(status)
+}
+
+func (s *emergencyAccessService) Review(
+	id, adminID uuid.UUID, req dtos.EmergencyReviewRequest,
+) (*models.EmergencyAccess, error) {
+	if req.Status != models.ApprovedReview && req.Status != models.FlaggedReview {
+		return nil, &igakuErrors.InvalidReviewStatusError{}
+	}
+
+	return s.repo.Review(
+		id, adminID, req.Status, strings.TrimSpace(req.Note), time.Now(),
+	)
+}
+
+func (s *emergencyAccessService) Events(
+	id uuid.UUID,
+) ([]models.EmergencyAccessEvent, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindEvents(id)
+}
+
+func (s *emergencyAccessService) notify(
+	patient *commonsModels.User, access *models.EmergencyAccess,
+) error {
+	doctor, err := s.userClient.FindByID(access.DoctorID)
+	if err != nil {
+		return err
+	}
+
+	to := []string{patient.Email}
+	msg := []byte(
+		fmt.Sprintf("From: %s\r\n", s.from) +
+		fmt.Sprintf("To: %s\r\n", patient.Email) +
+		"Subject: Igaku emergency access to your records\r\n" +
+		"\r\n" +
+		fmt.Sprintf("Hello %s,\r\n", patient.Username) +
+		"\r\n" +
+		fmt.Sprintf(
+			"Dr. %s was granted emergency access to your clinical data until %s, giving the following reason:\r\n",
+			doctor.Username,
+			access.ExpiresAt.Format("2006-01-02 15:04 MST"),
+		) +
+		"\r\n" +
+		fmt.Sprintf("%s\r\n", access.Justification) +
+		"\r\n" +
+		"Every access is logged and reviewed by our staff. " +
+		"Please contact us if you have any concerns.\r\n",
+	)
+
+	return s.mailClient.SendMail(to, msg)
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// diagnosisSearchLimit caps the number of ICD-10 codes returned by a
+// single search.
+const diagnosisSearchLimit = 25
+
+type EncounterService interface {
+	Create(
+		appointmentID, doctorID uuid.UUID, req dtos.EncounterNotesRequest,
+	) (*models.Encounter, error)
+	Get(
+		id, userID uuid.UUID, role commonsModels.Role,
+	) (*models.Encounter, error)
+	GetByAppointment(
+		appointmentID, userID uuid.UUID, role commonsModels.Role,
+	) (*models.Encounter, error)
+	List(userID uuid.UUID) ([]models.Encounter, error)
+	Amend(
+		id, doctorID uuid.UUID, req dtos.EncounterAmendmentRequest,
+	) (*models.EncounterRevision, error)
+	AddAttachment(
+		id, doctorID uuid.UUID, fileName, contentType string, data []byte,
+	) (*models.EncounterAttachment, error)
+	GetAttachment(
+		id, attachmentID, userID uuid.UUID, role commonsModels.Role,
+	) (*models.EncounterAttachment, error)
+	// Delegate grants another doctor read access to the encounter. Only
+	// the patient and the treating doctor may delegate.
+	Delegate(id, userID, doctorID uuid.UUID) (*models.EncounterDelegation, error)
+	RevokeDelegation(id, userID, doctorID uuid.UUID) error
+	SearchDiagnoses(query string) []models.ICD10Code
+}
+
+type encounterService struct {
+	repo		repositories.EncounterRepository
+	apptRepo	repositories.AppointmentRepository
+	access		PatientAccess
+	userClient	clients.UserClient
+	icd10		dictionaries.ICD10Dictionary
+}
+
+func NewEncounterService(
+	repo repositories.EncounterRepository,
+	apptRepo repositories.AppointmentRepository,
+	access PatientAccess,
+	userClient clients.UserClient,
+	icd10 dictionaries.ICD10Dictionary,
+) EncounterService {
+	return &encounterService{
+		repo: repo,
+		apptRepo: apptRepo,
+		access: access,
+		userClient: userClient,
+		icd10: icd10,
+	}
+}
+
+func (s *encounterService) Create(
+	appointmentID, doctorID uuid.UUID, req dtos.EncounterNotesRequest,
+) (*models.Encounter, error) {
+	appt, err := s.apptRepo.FindByID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appt.DoctorID != doctorID {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	if appt.Status == models.Cancelled {
+		return nil, &igakuErrors.AppointmentCancelledError{}
+	}
+
+	rev, err := s.newRevision(doctorID, req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	enc := &models.Encounter{
+		ID: uuid.New(),
+		AppointmentID: appt.ID,
+		PatientID: appt.PatientID,
+		DoctorID: appt.DoctorID,
+		CreatedAt: rev.CreatedAt,
+		Attachments: []models.EncounterAttachment{},
+		Delegations: []models.EncounterDelegation{},
+	}
+	if err := s.repo.Create(enc, rev); err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+func (s *encounterService) Get(
+	id, userID uuid.UUID, role commonsModels.Role,
+) (*models.Encounter, error) {
+	enc, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.canRead(enc, userID, role); err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+func (s *encounterService) GetByAppointment(
+	appointmentID, userID uuid.UUID, role commonsModels.Role,
+) (*models.Encounter, error) {
+	enc, err := s.repo.FindByAppointmentID(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.canRead(enc, userID, role); err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+func (s *encounterService) List(userID uuid.UUID) ([]models.Encounter, error) {
+	encs, err := s.repo.FindAccessible(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delegations are void for patients who since denied the doctor their
+	// encounters.
+	denied := make(map[uuid.UUID]bool)
+	accessible := []models.Encounter{}
+	for _, enc := range encs {
+		if enc.PatientID == userID || enc.DoctorID == userID {
+			accessible = append(accessible, enc)
+			continue
+		}
+
+		isDenied, checked := denied[enc.PatientID]
+		if !checked {
+			decision, err := s.access.Consent(
+				enc.PatientID, userID, models.EncountersCategory,
+			)
+			if err != nil {
+				return nil, err
+			}
+			isDenied = decision == models.Deny
+			denied[enc.PatientID] = isDenied
+		}
+		if !isDenied {
+			accessible = append(accessible, enc)
+		}
+	}
+
+	return accessible, nil
+}
+
+func (s *encounterService) Amend(
+	id, doctorID uuid.UUID, req dtos.EncounterAmendmentRequest,
+) (*models.EncounterRevision, error) {
+	if _, err := s.treatedBy(id, doctorID); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.newRevision(doctorID, req.EncounterNotesRequest, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	rev.EncounterID = id
+
+	if err := s.repo.AppendRevision(rev); err != nil {
+		return nil, err
+	}
+
+	return rev, nil
+}
+
+func (s *encounterService) AddAttachment(
+	id, doctorID uuid.UUID, fileName, contentType string, data []byte,
+) (*models.EncounterAttachment, error) {
+	if _, err := s.treatedBy(id, doctorID); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	att := &models.EncounterAttachment{
+		ID: uuid.New(),
+		EncounterID: id,
+		FileName: fileName,
+		ContentType: contentType,
+		Size: int64(len(data)),
+		Checksum: hex.EncodeToString(sum[:]),
+		Data: data,
+		UploadedBy: doctorID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.AddAttachment(att); err != nil {
+		return nil, err
+	}
+
+	return att, nil
+}
+
+func (s *encounterService) GetAttachment(
+	id, attachmentID, userID uuid.UUID, role commonsModels.Role,
+) (*models.EncounterAttachment, error) {
+	if _, err := s.Get(id, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindAttachment(id, attachmentID)
+}
+
+func (s *encounterService) Delegate(
+	id, userID, doctorID uuid.UUID,
+) (*models.EncounterDelegation, error) {
+	enc, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if enc.PatientID != userID && enc.DoctorID != userID {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	doctor, err := s.userClient.FindByID(doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if doctor.Role != commonsModels.Doctor || doctor.ID == enc.DoctorID {
+		return nil, &igakuErrors.InvalidDelegateError{}
+	}
+
+	del := &models.EncounterDelegation{
+		EncounterID: id,
+		DoctorID: doctorID,
+		GrantedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.AddDelegation(del); err != nil {
+		return nil, err
+	}
+
+	return del, nil
+}
+
+func (s *encounterService) RevokeDelegation(id, userID, doctorID uuid.UUID) error {
+	enc, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if enc.PatientID != userID && enc.DoctorID != userID {
+		return &igakuErrors.AccessDeniedError{}
+	}
+
+	return s.repo.RemoveDelegation(id, doctorID)
+}
+
+func (s *encounterService) SearchDiagnoses(query string) []models.ICD10Code {
+	return s.icd10.Search(query, diagnosisSearchLimit)
+}
+
+// treatedBy loads the encounter and makes sure the doctor is the one who
+// treated the patient, as only they may change the record.
+func (s *encounterService) treatedBy(
+	id, doctorID uuid.UUID,
+) (*models.Encounter, error) {
+	enc, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if enc.DoctorID != doctorID {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	return enc, nil
+}
+
+// newRevision builds an unversioned revision, resolving the diagnosis codes
+// against the ICD-10 dictionary.
+func (s *encounterService) newRevision(
+	authorID uuid.UUID, req dtos.EncounterNotesRequest, reason string,
+) (*models.EncounterRevision, error) {
+	diagnoses := make([]models.Diagnosis, 0, len(req.Diagnoses))
+	for _, d := range req.Diagnoses {
+		entry, ok := s.icd10.Lookup(d.Code)
+		if !ok {
+			return nil, &igakuErrors.InvalidDiagnosisCodeError{}
+		}
+		diagnoses = append(diagnoses, models.Diagnosis{
+			Code: entry.Code,
+			Description: entry.Description,
+			Primary: d.Primary,
+		})
+	}
+
+	return &models.EncounterRevision{
+		ID: uuid.New(),
+		AuthorID: authorID,
+		Reason: reason,
+		Subjective: req.Subjective,
+		Objective: req.Objective,
+		Assessment: req.Assessment,
+		Plan: req.Plan,
+		Diagnoses: diagnoses,
+		Vitals: req.Vitals,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// canRead checks whether the user may read the encounter. Its patient and
+// treating doctor always may, delegated doctors unless the patient denied
+// them encounters, and anyone else the patient's encounters are accessible
+// to, including doctors with emergency access.
+func (s *encounterService) canRead(
+	enc *models.Encounter, userID uuid.UUID, role commonsModels.Role,
+) error {
+	if enc.PatientID == userID || enc.DoctorID == userID {
+		return nil
+	}
+
+	if role == commonsModels.Doctor {
+		for _, del := range enc.Delegations {
+			if del.DoctorID != userID {
+				continue
+			}
+
+			decision, err := s.access.Consent(
+				enc.PatientID, userID, models.EncountersCategory,
+			)
+			if err != nil {
+				return err
+			}
+			if decision == models.Deny {
+				return &igakuErrors.AccessDeniedError{}
+			}
+			return nil
+		}
+	}
+
+	return s.access.Check(
+		enc.PatientID, userID, role, models.EncountersCategory,
+	)
+}
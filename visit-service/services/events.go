@@ -0,0 +1,26 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"log"
+
+	"igaku/visit-service/clients"
+	commonsDtos "igaku/commons/dtos"
+)
+
+// publishEvent tells the users concerned about a change. The change has
+// already been committed, so failures are only logged.
+func publishEvent(
+	client clients.EventClient,
+	eventType commonsDtos.EventType,
+	resourceID uuid.UUID,
+	recipients ...uuid.UUID,
+) {
+	if err := client.Publish(eventType, resourceID, recipients...); err != nil {
+		log.Printf(
+			"Failed to publish '%s' event about %s: %v",
+			eventType, resourceID, err,
+		)
+	}
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"slices"
+	"strings"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/fhir"
+	"igaku/visit-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// FHIRService serves Igaku data as FHIR R4 resources.
+//
+// Patients only see themselves and their own appointments, doctors the
+// patients whose care team they are on and their own appointments.
+// Practitioners, organizations and locations are visible to everyone
+// signed in.
+type FHIRService interface {
+	Read(
+		resourceType string, id, userID uuid.UUID, role commonsModels.Role,
+	) (fhir.Resource, error)
+	Search(
+		resourceType string,
+		search *fhir.Search,
+		userID uuid.UUID,
+		role commonsModels.Role,
+	) (*fhir.SearchResult, error)
+}
+
+type fhirService struct {
+	userClient	clients.UserClient
+	orgRepo		repositories.OrganizationRepository
+	apptRepo	repositories.AppointmentRepository
+	careTeamRepo	repositories.CareTeamRepository
+}
+
+func NewFHIRService(
+	userClient clients.UserClient,
+	orgRepo repositories.OrganizationRepository,
+	apptRepo repositories.AppointmentRepository,
+	careTeamRepo repositories.CareTeamRepository,
+) FHIRService {
+	return &fhirService{
+		userClient: userClient,
+		orgRepo: orgRepo,
+		apptRepo: apptRepo,
+		careTeamRepo: careTeamRepo,
+	}
+}
+
+func (s *fhirService) Read(
+	resourceType string, id, userID uuid.UUID, role commonsModels.Role,
+) (fhir.Resource, error) {
+	search := &fhir.Search{
+		Params: map[string][]string{"_id": {id.String()}},
+		Count: 1,
+	}
+
+	result, err := s.Search(resourceType, search, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Matches) == 0 {
+		if resourceType == "Appointment" {
+			return nil, &igakuErrors.AppointmentNotFoundError{}
+		}
+		if resourceType == "Organization" || resourceType == "Location" {
+			return nil, &igakuErrors.OrganizationNotFoundError{}
+		}
+		return nil, &commonsErrors.UserNotFoundError{}
+	}
+
+	return result.Matches[0], nil
+}
+
+func (s *fhirService) Search(
+	resourceType string,
+	search *fhir.Search,
+	userID uuid.UUID,
+	role commonsModels.Role,
+) (*fhir.SearchResult, error) {
+	switch resourceType {
+	case "Patient":
+		switch role {
+		case commonsModels.Patient:
+			return s.searchUsers(
+				commonsModels.Patient, search, []uuid.UUID{userID},
+			)
+		case commonsModels.Doctor:
+			ids, err := s.careTeamRepo.FindPatientIDs(userID)
+			if err != nil {
+				return nil, err
+			}
+			if len(ids) == 0 {
+				return &fhir.SearchResult{}, nil
+			}
+			return s.searchUsers(commonsModels.Patient, search, ids)
+		case commonsModels.Admin:
+			return s.searchUsers(commonsModels.Patient, search, nil)
+		}
+		// Emergency access is granted per patient and does not extend to
+		// the patient directory.
+		return &fhir.SearchResult{}, nil
+	case "Practitioner":
+		return s.searchUsers(commonsModels.Doctor, search, nil)
+	case "Organization", "Location":
+		return s.searchOrganizations(resourceType, search)
+	case "Appointment":
+		return s.searchAppointments(search, userID, role)
+	default:
+		return nil, &igakuErrors.InvalidSearchParameterError{Parameter: resourceType}
+	}
+}
+
+// searchUsers searches the users of the role. only, unless nil, restricts
+// the search to the given users.
+func (s *fhirService) searchUsers(
+	role commonsModels.Role, search *fhir.Search, only []uuid.UUID,
+) (*fhir.SearchResult, error) {
+	req := commonsDtos.UserSearchRequest{
+		Role: role,
+		Offset: search.Offset,
+		Limit: search.Count,
+	}
+
+	ids, ok, err := idParam(search)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &fhir.SearchResult{}, nil
+	}
+	req.IDs = ids
+
+	if only != nil {
+		if len(req.IDs) > 0 {
+			req.IDs = slices.DeleteFunc(req.IDs, func(id uuid.UUID) bool {
+				return !slices.Contains(only, id)
+			})
+			if len(req.IDs) == 0 {
+				return &fhir.SearchResult{}, nil
+			}
+		} else {
+			req.IDs = only
+		}
+	}
+
+	if req.UsernamePrefix, err = singleParam(search, "name"); err != nil {
+		return nil, err
+	}
+	if req.Email, err = singleParam(search, "email"); err != nil {
+		return nil, err
+	}
+	identifier, err := singleParam(search, "identifier")
+	if err != nil {
+		return nil, err
+	}
+	if identifier != "" {
+		username, ok := fhir.TokenValue(identifier, fhir.UsernameSystem)
+		if !ok {
+			return &fhir.SearchResult{}, nil
+		}
+		req.Username = username
+	}
+
+	if search.Count == 0 {
+		// `_count=0` only asks for the total.
+		req.Limit = 1
+	}
+
+	found, err := s.userClient.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fhir.SearchResult{Total: found.Total}
+	if search.Count == 0 {
+		return result, nil
+	}
+	for i := range found.Users {
+		if role == commonsModels.Patient {
+			result.Matches = append(result.Matches, fhir.NewPatient(&found.Users[i]))
+		} else {
+			result.Matches = append(result.Matches, fhir.NewPractitioner(&found.Users[i]))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *fhirService) searchOrganizations(
+	resourceType string, search *fhir.Search,
+) (*fhir.SearchResult, error) {
+	id, ok, err := singleIDParam(search, "_id", "")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &fhir.SearchResult{}, nil
+	}
+
+	if resourceType == "Location" {
+		orgID, ok, err := singleIDParam(search, "organization", "Organization")
+		if err != nil {
+			return nil, err
+		}
+		if !ok || (id != nil && orgID != nil && *id != *orgID) {
+			return &fhir.SearchResult{}, nil
+		}
+		if orgID != nil {
+			id = orgID
+		}
+	}
+
+	name, err := singleParam(search, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, total, err := s.orgRepo.Search(id, name, search.Offset, search.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fhir.SearchResult{Total: total}
+	for i := range orgs {
+		if resourceType == "Location" {
+			result.Matches = append(result.Matches, fhir.NewLocation(&orgs[i]))
+			continue
+		}
+		result.Matches = append(result.Matches, fhir.NewOrganization(&orgs[i]))
+		if len(search.RevInclude) > 0 {
+			result.Included = append(result.Included, fhir.NewLocation(&orgs[i]))
+		}
+	}
+
+	return result, nil
+}
+
+func (s *fhirService) searchAppointments(
+	search *fhir.Search, userID uuid.UUID, role commonsModels.Role,
+) (*fhir.SearchResult, error) {
+	filter := dtos.AppointmentFilter{Offset: search.Offset, Limit: search.Count}
+
+	params := []struct {
+		name		string
+		resourceType	string
+		dest		**uuid.UUID
+	}{
+		{"_id", "", &filter.ID},
+		{"patient", "Patient", &filter.PatientID},
+		{"practitioner", "Practitioner", &filter.DoctorID},
+		{"location", "Location", &filter.OrganizationID},
+	}
+	for _, p := range params {
+		id, ok, err := singleIDParam(search, p.name, p.resourceType)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return &fhir.SearchResult{}, nil
+		}
+		*p.dest = id
+	}
+
+	// Restrict the search to the user's own appointments.
+	var own **uuid.UUID
+	switch role {
+	case commonsModels.Patient:
+		own = &filter.PatientID
+	case commonsModels.Doctor:
+		own = &filter.DoctorID
+	case commonsModels.Admin:
+	default:
+		return &fhir.SearchResult{}, nil
+	}
+	if own != nil {
+		if *own != nil && **own != userID {
+			return &fhir.SearchResult{}, nil
+		}
+		*own = &userID
+	}
+
+	status, err := singleParam(search, "status")
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		apptStatus, ok := fhir.AppointmentStatus(status)
+		if !ok {
+			return &fhir.SearchResult{}, nil
+		}
+		filter.Status = &apptStatus
+	}
+
+	for _, value := range search.Params["date"] {
+		from, until, err := fhir.ParseDate(value)
+		if err != nil {
+			return nil, err
+		}
+		if from != nil && (filter.StartsFrom == nil || from.After(*filter.StartsFrom)) {
+			filter.StartsFrom = from
+		}
+		if until != nil && (filter.StartsBefore == nil || until.Before(*filter.StartsBefore)) {
+			filter.StartsBefore = until
+		}
+	}
+
+	appts, total, err := s.apptRepo.Search(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fhir.SearchResult{Total: total}
+	for i := range appts {
+		result.Matches = append(result.Matches, fhir.NewAppointment(&appts[i]))
+	}
+
+	return result, nil
+}
+
+// singleParam returns the value of a search parameter which may be given
+// at most once.
+func singleParam(search *fhir.Search, name string) (string, error) {
+	values := search.Params[name]
+	if len(values) > 1 {
+		return "", &igakuErrors.InvalidSearchParameterError{Parameter: name}
+	}
+	if len(values) == 0 {
+		return "", nil
+	}
+	return values[0], nil
+}
+
+// singleIDParam parses a search parameter holding a single ID or reference.
+// ok is false when the value cannot match any resource.
+func singleIDParam(
+	search *fhir.Search, name, resourceType string,
+) (id *uuid.UUID, ok bool, err error) {
+	value, err := singleParam(search, name)
+	if err != nil || value == "" {
+		return nil, true, err
+	}
+
+	parsed, parseErr := uuid.Parse(fhir.ReferenceID(value, resourceType))
+	if parseErr != nil {
+		return nil, false, nil
+	}
+	return &parsed, true, nil
+}
+
+// idParam parses the `_id` parameter, which may list several IDs separated
+// by commas. ok is false when none of them can match any resource.
+func idParam(search *fhir.Search) (ids []uuid.UUID, ok bool, err error) {
+	value, err := singleParam(search, "_id")
+	if err != nil || value == "" {
+		return nil, true, err
+	}
+
+	for _, v := range strings.Split(value, ",") {
+		if id, err := uuid.Parse(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, len(ids) > 0, nil
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"slices"
+	"strings"
+	"time"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// HealthRecordService manages the medical history of patients, including
+// their pharmacogenomic test results.
+//
+// Patients see their whole history and may record entries themselves,
+// which they can change until a doctor does. Doctors the patient's
+// consents give access to may read and record entries; genotypes are
+// covered by the genomics category, every other kind by records. Admins
+// may read, correct and remove entries, but not record new ones.
+type HealthRecordService interface {
+	Summary(
+		patientID, userID uuid.UUID, role commonsModels.Role,
+	) (*models.PatientSummary, error)
+	List(
+		kind models.HealthRecordKind,
+		patientID, userID uuid.UUID,
+		role commonsModels.Role,
+	) ([]models.Recordable, error)
+	Create(
+		kind models.HealthRecordKind,
+		patientID, userID uuid.UUID,
+		role commonsModels.Role,
+		req dtos.HealthRecordRequest,
+	) (models.Recordable, error)
+	Update(
+		kind models.HealthRecordKind,
+		id, patientID, userID uuid.UUID,
+		role commonsModels.Role,
+		req dtos.HealthRecordRequest,
+	) (models.Recordable, error)
+	Delete(
+		kind models.HealthRecordKind,
+		id, patientID, userID uuid.UUID,
+		role commonsModels.Role,
+	) error
+}
+
+type healthRecordService struct {
+	repo			repositories.HealthRecordRepository
+	access			PatientAccess
+	encounterRepo		repositories.EncounterRepository
+	prescriptionRepo	repositories.PrescriptionRepository
+	icd10			dictionaries.ICD10Dictionary
+}
+
+func NewHealthRecordService(
+	repo repositories.HealthRecordRepository,
+	access PatientAccess,
+	encounterRepo repositories.EncounterRepository,
+	prescriptionRepo repositories.PrescriptionRepository,
+	icd10 dictionaries.ICD10Dictionary,
+) HealthRecordService {
+	return &healthRecordService{
+		repo: repo,
+		access: access,
+		encounterRepo: encounterRepo,
+		prescriptionRepo: prescriptionRepo,
+		icd10: icd10,
+	}
+}
+
+func (s *healthRecordService) Summary(
+	patientID, userID uuid.UUID, role commonsModels.Role,
+) (*models.PatientSummary, error) {
+	categories, err := s.access.Categories(patientID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(categories, models.RecordsCategory) {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	summary, err := s.repo.Summary(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sections of other categories are left empty unless the user may see
+	// them.
+	if !slices.Contains(categories, models.GenomicsCategory) {
+		summary.Genotypes = []models.Genotype{}
+	}
+	summary.ActivePrescriptions = []models.Prescription{}
+	if slices.Contains(categories, models.PrescriptionsCategory) {
+		now := time.Now()
+		summary.ActivePrescriptions, err = s.prescriptionRepo.FindByPatientID(
+			patientID, &now,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *healthRecordService) List(
+	kind models.HealthRecordKind,
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+) ([]models.Recordable, error) {
+	if err := s.canRead(kind, patientID, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByPatientID(kind, patientID)
+}
+
+func (s *healthRecordService) Create(
+	kind models.HealthRecordKind,
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+	req dtos.HealthRecordRequest,
+) (models.Recordable, error) {
+	if role == commonsModels.Admin || role == models.EmergencyRole {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+	if err := s.canRead(kind, patientID, userID, role); err != nil {
+		return nil, err
+	}
+	if err := s.checkEncounter(req.Encounter(), patientID, userID, role); err != nil {
+		return nil, err
+	}
+
+	record, ok := models.NewHealthRecord(kind)
+	if !ok {
+		return nil, &igakuErrors.HealthRecordNotFoundError{}
+	}
+	req.Apply(record)
+	if err := s.resolve(record); err != nil {
+		return nil, err
+	}
+
+	base := record.Record()
+	base.ID = uuid.New()
+	base.PatientID = patientID
+	base.RecordedBy = userID
+	base.EncounterID = req.Encounter()
+	base.RecordedAt = time.Now()
+
+	if err := s.repo.Create(record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *healthRecordService) Update(
+	kind models.HealthRecordKind,
+	id, patientID, userID uuid.UUID,
+	role commonsModels.Role,
+	req dtos.HealthRecordRequest,
+) (models.Recordable, error) {
+	record, err := s.findWritable(kind, id, patientID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	encounterID := record.Record().EncounterID
+	if req.Encounter() != nil {
+		err := s.checkEncounter(req.Encounter(), patientID, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		encounterID = req.Encounter()
+	}
+
+	req.Apply(record)
+	if err := s.resolve(record); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	base := record.Record()
+	base.EncounterID = encounterID
+	base.UpdatedBy = &userID
+	base.UpdatedAt = &now
+
+	if err := s.repo.Save(record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *healthRecordService) Delete(
+	kind models.HealthRecordKind,
+	id, patientID, userID uuid.UUID,
+	role commonsModels.Role,
+) error {
+	record, err := s.findWritable(kind, id, patientID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Delete(record)
+}
+
+// canRead checks whether the user may see the patient's records of the
+// kind.
+func (s *healthRecordService) canRead(
+	kind models.HealthRecordKind,
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+) error {
+	return s.access.Check(patientID, userID, role, kind.ConsentCategory())
+}
+
+// findWritable loads a record the user may change. Patients may only
+// change the entries they recorded themselves, as long as no one else has
+// updated them since. Emergency access is read-only.
+func (s *healthRecordService) findWritable(
+	kind models.HealthRecordKind,
+	id, patientID, userID uuid.UUID,
+	role commonsModels.Role,
+) (models.Recordable, error) {
+	if role == models.EmergencyRole {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+	if err := s.canRead(kind, patientID, userID, role); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.FindByID(kind, id)
+	if err != nil {
+		return nil, err
+	}
+
+	base := record.Record()
+	if base.PatientID != patientID {
+		return nil, &igakuErrors.HealthRecordNotFoundError{}
+	}
+
+	if role == commonsModels.Patient {
+		if base.RecordedBy != userID ||
+			(base.UpdatedBy != nil && *base.UpdatedBy != userID) {
+			return nil, &igakuErrors.AccessDeniedError{}
+		}
+	}
+
+	return record, nil
+}
+
+// checkEncounter makes sure the referenced encounter is one of the doctor's
+// encounters with the patient.
+func (s *healthRecordService) checkEncounter(
+	encounterID *uuid.UUID,
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+) error {
+	if encounterID == nil {
+		return nil
+	}
+	if role != commonsModels.Doctor {
+		return &igakuErrors.AccessDeniedError{}
+	}
+
+	enc, err := s.encounterRepo.FindByID(*encounterID)
+	if err != nil {
+		return err
+	}
+	if enc.PatientID != patientID || enc.DoctorID != userID {
+		return &igakuErrors.AccessDeniedError{}
+	}
+
+	return nil
+}
+
+// resolve fills in the fields taken from dictionaries and normalizes the
+// coded ones.
+func (s *healthRecordService) resolve(record models.Recordable) error {
+	switch r := record.(type) {
+	case *models.ChronicCondition:
+		entry, ok := s.icd10.Lookup(r.Code)
+		if !ok {
+			return &igakuErrors.InvalidDiagnosisCodeError{}
+		}
+		r.Code = entry.Code
+		r.Description = entry.Description
+	case *models.Genotype:
+		r.Gene = strings.ToUpper(strings.TrimSpace(r.Gene))
+		r.Diplotype = strings.TrimSpace(r.Diplotype)
+		r.Phenotype = strings.ToLower(strings.Join(strings.Fields(r.Phenotype), " "))
+		if !slices.Contains(models.Phenotypes, r.Phenotype) {
+			return &igakuErrors.InvalidPhenotypeError{}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"errors"
+	"log"
+	"strings"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type HomeAddressService interface {
+	Get(patientID uuid.UUID) (*models.HomeAddress, error)
+	// Set geocodes the address and saves it as the home address of the
+	// patient, replacing the previous one.
+	Set(patientID uuid.UUID, address string) (*models.HomeAddress, error)
+	Delete(patientID uuid.UUID) error
+}
+
+type homeAddressService struct {
+	repo		repositories.HomeAddressRepository
+	geoClient	clients.GeoClient
+}
+
+func NewHomeAddressService(
+	repo repositories.HomeAddressRepository,
+	geoClient clients.GeoClient,
+) HomeAddressService {
+	return &homeAddressService{repo: repo, geoClient: geoClient}
+}
+
+func (s *homeAddressService) Get(
+	patientID uuid.UUID,
+) (*models.HomeAddress, error) {
+	address, err := s.repo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil {
+		return nil, &igakuErrors.HomeAddressNotFoundError{}
+	}
+	return address, nil
+}
+
+func (s *homeAddressService) Set(
+	patientID uuid.UUID, address string,
+) (*models.HomeAddress, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, &igakuErrors.AddressNotFoundError{}
+	}
+
+	locations, err := s.geoClient.Search(address)
+	if errors.Is(err, &commonsErrors.InternalError{}) {
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("Failed to geocode a home address: %v", err)
+		return nil, &igakuErrors.GeocodingFailedError{}
+	}
+	if len(locations) == 0 {
+		return nil, &igakuErrors.AddressNotFoundError{}
+	}
+
+	lat, latErr := commonsDtos.ParseCoordinate(locations[0].Lat)
+	lon, lonErr := commonsDtos.ParseCoordinate(locations[0].Lon)
+	if latErr != nil || lonErr != nil {
+		log.Printf(
+			"Geocoded home address has invalid coordinates: %s, %s",
+			locations[0].Lat, locations[0].Lon,
+		)
+		return nil, &igakuErrors.GeocodingFailedError{}
+	}
+
+	home := &models.HomeAddress{
+		PatientID: patientID,
+		Address: address,
+		DisplayName: locations[0].Name,
+		Lat: float64(lat),
+		Lon: float64(lon),
+	}
+	if err := s.repo.Save(home); err != nil {
+		return nil, err
+	}
+
+	return home, nil
+}
+
+func (s *homeAddressService) Delete(patientID uuid.UUID) error {
+	return s.repo.Delete(patientID)
+}
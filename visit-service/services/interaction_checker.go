@@ -0,0 +1,175 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"sort"
+	"strings"
+	"time"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+)
+
+type InteractionChecker interface {
+	// Check compares a drug against the patient's active prescriptions,
+	// recorded allergies and pharmacogenomic results. Warnings are ordered
+	// from the most severe.
+	Check(patientID uuid.UUID, drug models.Drug) ([]models.InteractionWarning, error)
+	// Guidance returns only the pharmacogenomic recommendations for the
+	// drug, based on the patient's current genotypes.
+	Guidance(patientID uuid.UUID, drug models.Drug) ([]models.InteractionWarning, error)
+}
+
+type interactionChecker struct {
+	prescriptionRepo	repositories.PrescriptionRepository
+	allergyRepo		repositories.AllergyRepository
+	genotypeRepo		repositories.GenotypeRepository
+	dataset			dictionaries.InteractionDataset
+	guidelines		dictionaries.PharmacogenomicGuidelines
+}
+
+func NewInteractionChecker(
+	prescriptionRepo repositories.PrescriptionRepository,
+	allergyRepo repositories.AllergyRepository,
+	genotypeRepo repositories.GenotypeRepository,
+	dataset dictionaries.InteractionDataset,
+	guidelines dictionaries.PharmacogenomicGuidelines,
+) InteractionChecker {
+	return &interactionChecker{
+		prescriptionRepo: prescriptionRepo,
+		allergyRepo: allergyRepo,
+		genotypeRepo: genotypeRepo,
+		dataset: dataset,
+		guidelines: guidelines,
+	}
+}
+
+func (c *interactionChecker) Check(
+	patientID uuid.UUID, drug models.Drug,
+) ([]models.InteractionWarning, error) {
+	now := time.Now()
+	active, err := c.prescriptionRepo.FindByPatientID(patientID, &now)
+	if err != nil {
+		return nil, err
+	}
+
+	allergies, err := c.allergyRepo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []models.InteractionWarning{}
+
+	for _, p := range active {
+		current := models.Drug{
+			ID: p.DrugID,
+			Name: p.DrugName,
+			ATCCode: p.ATCCode,
+		}
+
+		if current.ATCCode != "" && current.ATCCode == drug.ATCCode {
+			warnings = append(warnings, models.InteractionWarning{
+				Kind: models.DuplicateTherapy,
+				Severity: models.Moderate,
+				Substance: current.Name,
+				Description: "The patient already has an active prescription for " +
+					"the same substance.",
+			})
+			continue
+		}
+
+		for _, rule := range c.dataset.Interactions(drug, current) {
+			warnings = append(warnings, models.InteractionWarning{
+				Kind: models.DrugInteraction,
+				Severity: rule.Severity,
+				Substance: current.Name,
+				Description: rule.Description,
+			})
+		}
+	}
+
+	for _, allergy := range allergies {
+		allergen := dictionaries.NormalizeAllergen(allergy.Substance)
+		if allergen == dictionaries.NormalizeAllergen(drug.Name) {
+			warnings = append(warnings, models.InteractionWarning{
+				Kind: models.AllergyConflict,
+				Severity: models.Severe,
+				Substance: allergy.Substance,
+				Description: "The patient is allergic to " +
+					strings.ToLower(drug.Name) + ".",
+			})
+			continue
+		}
+
+		for _, rule := range c.dataset.AllergyRules(allergen, drug) {
+			warnings = append(warnings, models.InteractionWarning{
+				Kind: models.AllergyConflict,
+				Severity: rule.Severity,
+				Substance: allergy.Substance,
+				Description: rule.Description,
+			})
+		}
+	}
+
+	guidance, err := c.Guidance(patientID, drug)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, guidance...)
+
+	sortWarnings(warnings)
+
+	return warnings, nil
+}
+
+func (c *interactionChecker) Guidance(
+	patientID uuid.UUID, drug models.Drug,
+) ([]models.InteractionWarning, error) {
+	genotypes, err := c.genotypeRepo.FindByPatientID(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []models.InteractionWarning{}
+
+	// Only the latest result of each gene is considered.
+	seen := make(map[string]bool)
+	for _, g := range genotypes {
+		gene := strings.ToUpper(g.Gene)
+		if seen[gene] {
+			continue
+		}
+		seen[gene] = true
+
+		for _, rule := range c.guidelines.Recommendations(g.Gene, g.Phenotype, drug) {
+			warnings = append(warnings, models.InteractionWarning{
+				Kind: models.Pharmacogenomic,
+				Severity: rule.Severity,
+				Substance: g.Gene + " " + g.Phenotype,
+				Description: rule.Recommendation,
+			})
+		}
+	}
+
+	sortWarnings(warnings)
+
+	return warnings, nil
+}
+
+func sortWarnings(warnings []models.InteractionWarning) {
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Severity.Rank() > warnings[j].Severity.Rank()
+	})
+}
+
+// hasSevere reports whether any of the warnings is severe.
+func hasSevere(warnings []models.InteractionWarning) bool {
+	for _, w := range warnings {
+		if w.Severity == models.Severe {
+			return true
+		}
+	}
+	return false
+}
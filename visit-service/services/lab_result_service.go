@@ -0,0 +1,439 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/hl7"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// LabResultService records lab results and shows their trends.
+//
+// Values of analytes known to the lab dictionary are normalized to the
+// analyte's canonical unit and flagged against the reported reference
+// range, or the dictionary range when none was reported. Results are
+// visible to whoever may see the patient's medical history. Doctors record
+// results of their patients; HL7 files may be imported by doctors, for
+// their patients, and by admins.
+type LabResultService interface {
+	Create(
+		patientID, userID uuid.UUID,
+		role commonsModels.Role,
+		req dtos.LabResultRequest,
+	) (*models.LabResult, error)
+	// Import stores the observations of the ORU^R01 messages in an HL7
+	// file. Messages and observations which cannot be imported are
+	// reported without failing the others.
+	Import(
+		data []byte, userID uuid.UUID, role commonsModels.Role,
+	) (*dtos.LabImportResult, error)
+	// List returns the patient's results, newest first.
+	List(
+		patientID, userID uuid.UUID,
+		role commonsModels.Role,
+		filter dtos.LabResultFilter,
+	) ([]models.LabResult, error)
+	// Series returns the history of one analyte of the patient.
+	Series(
+		patientID, userID uuid.UUID,
+		role commonsModels.Role,
+		filter dtos.LabResultFilter,
+	) (*models.LabSeries, error)
+}
+
+type labResultService struct {
+	repo		repositories.LabResultRepository
+	access		PatientAccess
+	userClient	clients.UserClient
+	eventClient	clients.EventClient
+	labs		dictionaries.LabDictionary
+	// loc is the time zone of HL7 timestamps without an offset.
+	loc		*time.Location
+}
+
+func NewLabResultService(
+	repo repositories.LabResultRepository,
+	access PatientAccess,
+	userClient clients.UserClient,
+	eventClient clients.EventClient,
+	labs dictionaries.LabDictionary,
+	loc *time.Location,
+) LabResultService {
+	return &labResultService{
+		repo: repo,
+		access: access,
+		userClient: userClient,
+		eventClient: eventClient,
+		labs: labs,
+		loc: loc,
+	}
+}
+
+func (s *labResultService) Create(
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+	req dtos.LabResultRequest,
+) (*models.LabResult, error) {
+	if role != commonsModels.Doctor {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+	err := s.access.Check(patientID, userID, role, models.LabResultsCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.LabResult{
+		ID: uuid.New(),
+		PatientID: patientID,
+		LOINCCode: strings.TrimSpace(req.LOINCCode),
+		Name: strings.TrimSpace(req.Name),
+		ReportedValue: *req.Value,
+		ReportedUnit: strings.TrimSpace(req.Unit),
+		ReferenceLow: req.ReferenceLow,
+		ReferenceHigh: req.ReferenceHigh,
+		ObservedAt: req.ObservedAt,
+		RecordedBy: userID,
+		RecordedAt: time.Now(),
+		Source: models.LabSourceManual,
+	}
+	if err := s.normalize(result); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Create([]models.LabResult{*result}); err != nil {
+		return nil, err
+	}
+
+	publishEvent(
+		s.eventClient, commonsDtos.LabResultRecorded, patientID, patientID,
+	)
+
+	return result, nil
+}
+
+func (s *labResultService) Import(
+	data []byte, userID uuid.UUID, role commonsModels.Role,
+) (*dtos.LabImportResult, error) {
+	if role != commonsModels.Doctor && role != commonsModels.Admin {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	messages, err := hl7.Parse(data)
+	if err != nil {
+		return nil, &igakuErrors.InvalidLabMessageError{Reason: err.Error()}
+	}
+
+	summary := &dtos.LabImportResult{
+		Messages: len(messages),
+		Rejected: []dtos.LabImportIssue{},
+	}
+	now := time.Now()
+	for i := range messages {
+		msg := &messages[i]
+		reject := func(line int, reason string) {
+			summary.Rejected = append(summary.Rejected, dtos.LabImportIssue{
+				ControlID: msg.ControlID(),
+				Line: line,
+				Reason: reason,
+			})
+		}
+
+		oru, err := hl7.ParseORU(msg, s.loc)
+		if err != nil {
+			reject(msg.Header().Line, err.Error())
+			continue
+		}
+
+		patientID, reason, err := s.importPatient(oru.PatientID, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			reject(msg.Header().Line, reason)
+			continue
+		}
+
+		results := make([]models.LabResult, 0, len(oru.Observations))
+		for j, obs := range oru.Observations {
+			result, err := s.fromObservation(&obs)
+			if err != nil {
+				reject(obs.Line, err.Error())
+				continue
+			}
+
+			setID := obs.SetID
+			if setID == "" {
+				setID = strconv.Itoa(j + 1)
+			}
+			sourceID := oru.ControlID + "/" + setID
+
+			result.ID = uuid.New()
+			result.PatientID = patientID
+			result.RecordedBy = userID
+			result.RecordedAt = now
+			result.Source = models.LabSourceHL7
+			result.SourceID = &sourceID
+			results = append(results, *result)
+		}
+
+		imported, err := s.repo.Create(results)
+		if err != nil {
+			return nil, err
+		}
+		summary.Imported += int(imported)
+		summary.Duplicates += len(results) - int(imported)
+
+		if imported > 0 {
+			publishEvent(
+				s.eventClient, commonsDtos.LabResultRecorded,
+				patientID, patientID,
+			)
+		}
+	}
+
+	return summary, nil
+}
+
+// importPatient resolves the patient of a message. It returns the reason
+// to reject the message when the patient is unknown or out of the user's
+// reach.
+func (s *labResultService) importPatient(
+	identifier string, userID uuid.UUID, role commonsModels.Role,
+) (uuid.UUID, string, error) {
+	patientID, err := uuid.Parse(identifier)
+	if err != nil {
+		return uuid.Nil, "Unknown patient identifier '" + identifier + "'", nil
+	}
+
+	patient, err := s.userClient.FindByID(patientID)
+	if errors.Is(err, &commonsErrors.UserNotFoundError{}) {
+		return uuid.Nil, "Unknown patient " + identifier, nil
+	}
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if patient.Role != commonsModels.Patient {
+		return uuid.Nil, "User " + identifier + " is not a patient", nil
+	}
+
+	err = s.access.Check(patientID, userID, role, models.LabResultsCategory)
+	if errors.Is(err, &igakuErrors.AccessDeniedError{}) {
+		return uuid.Nil, "Access denied to patient " + identifier, nil
+	}
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	return patientID, "", nil
+}
+
+// fromObservation turns an OBX segment into a normalized result.
+func (s *labResultService) fromObservation(
+	obs *hl7.Observation,
+) (*models.LabResult, error) {
+	if obs.CodingSystem != hl7.LOINCSystem {
+		return nil, fmt.Errorf("Observation %s is not coded in LOINC", obs.Code)
+	}
+	if obs.Status != "" && obs.Status != "F" && obs.Status != "C" {
+		return nil, fmt.Errorf("Result status '%s' is not final", obs.Status)
+	}
+	if obs.ValueType != "NM" {
+		return nil, fmt.Errorf("Value type '%s' is not numeric", obs.ValueType)
+	}
+	if obs.ObservedAt.IsZero() {
+		return nil, fmt.Errorf("Missing observation time")
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(obs.Value), 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid numeric value '%s'", obs.Value)
+	}
+	low, high, ok := hl7.ParseRange(obs.ReferenceRange)
+	if !ok {
+		return nil, fmt.Errorf("Invalid reference range '%s'", obs.ReferenceRange)
+	}
+
+	result := &models.LabResult{
+		LOINCCode: obs.Code,
+		Name: obs.Name,
+		ReportedValue: value,
+		ReportedUnit: obs.Units,
+		ReferenceLow: low,
+		ReferenceHigh: high,
+		ObservedAt: obs.ObservedAt,
+	}
+	if err := s.normalize(result); err != nil {
+		return nil, fmt.Errorf("%s for %s", err.Error(), obs.Code)
+	}
+
+	// Trust the lab's own flag when there is no range to check against.
+	if result.Flag == "" {
+		switch flag := models.LabFlag(obs.AbnormalFlag); flag {
+		case models.LabNormal, models.LabLow, models.LabHigh,
+			models.LabCriticalLow, models.LabCriticalHigh:
+			result.Flag = flag
+		}
+	}
+
+	return result, nil
+}
+
+// normalize converts the reported value and reference range to the
+// canonical unit of the analyte, fills in the dictionary defaults and
+// flags the value.
+func (s *labResultService) normalize(result *models.LabResult) error {
+	if !models.ValidLOINCCode(result.LOINCCode) {
+		return &igakuErrors.InvalidLabCodeError{}
+	}
+	if result.ReferenceLow != nil && result.ReferenceHigh != nil &&
+		*result.ReferenceLow > *result.ReferenceHigh {
+		return &igakuErrors.InvalidReferenceRangeError{}
+	}
+
+	analyte, ok := s.labs.Lookup(result.LOINCCode)
+	if !ok {
+		if result.Name == "" {
+			result.Name = result.LOINCCode
+		}
+		result.Value = result.ReportedValue
+		result.Unit = result.ReportedUnit
+		result.Flag = labFlag(result.Value, result.ReferenceLow, result.ReferenceHigh, nil)
+		return nil
+	}
+
+	value, ok := analyte.Convert(result.ReportedValue, result.ReportedUnit)
+	if !ok {
+		return &igakuErrors.UnsupportedLabUnitError{}
+	}
+	for _, limit := range []**float64{&result.ReferenceLow, &result.ReferenceHigh} {
+		if *limit == nil {
+			continue
+		}
+		converted, _ := analyte.Convert(**limit, result.ReportedUnit)
+		*limit = &converted
+	}
+	if result.ReferenceLow == nil && result.ReferenceHigh == nil {
+		result.ReferenceLow = analyte.ReferenceLow
+		result.ReferenceHigh = analyte.ReferenceHigh
+	}
+
+	result.Name = analyte.Name
+	result.Value = value
+	result.Unit = analyte.Unit
+	result.Flag = labFlag(value, result.ReferenceLow, result.ReferenceHigh, analyte)
+	return nil
+}
+
+// labFlag compares a value with its reference range and, for known
+// analytes, their critical limits.
+func labFlag(
+	value float64, low, high *float64, analyte *models.LabAnalyte,
+) models.LabFlag {
+	if analyte != nil {
+		if analyte.CriticalLow != nil && value < *analyte.CriticalLow {
+			return models.LabCriticalLow
+		}
+		if analyte.CriticalHigh != nil && value > *analyte.CriticalHigh {
+			return models.LabCriticalHigh
+		}
+	}
+
+	switch {
+	case low == nil && high == nil:
+		return ""
+	case low != nil && value < *low:
+		return models.LabLow
+	case high != nil && value > *high:
+		return models.LabHigh
+	default:
+		return models.LabNormal
+	}
+}
+
+func (s *labResultService) List(
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+	filter dtos.LabResultFilter,
+) ([]models.LabResult, error) {
+	if filter.LOINCCode != "" && !models.ValidLOINCCode(filter.LOINCCode) {
+		return nil, &igakuErrors.InvalidLabCodeError{}
+	}
+	err := s.access.Check(patientID, userID, role, models.LabResultsCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.FindByPatientID(patientID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(results)
+	return results, nil
+}
+
+func (s *labResultService) Series(
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+	filter dtos.LabResultFilter,
+) (*models.LabSeries, error) {
+	if !models.ValidLOINCCode(filter.LOINCCode) {
+		return nil, &igakuErrors.InvalidLabCodeError{}
+	}
+	err := s.access.Check(patientID, userID, role, models.LabResultsCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.FindByPatientID(patientID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &models.LabSeries{
+		LOINCCode: filter.LOINCCode,
+		Name: filter.LOINCCode,
+		Points: []models.LabPoint{},
+	}
+	if analyte, ok := s.labs.Lookup(filter.LOINCCode); ok {
+		series.Name = analyte.Name
+		series.Unit = analyte.Unit
+		series.ReferenceLow = analyte.ReferenceLow
+		series.ReferenceHigh = analyte.ReferenceHigh
+	} else if len(results) > 0 {
+		// Values of unknown analytes are not normalized, so plot only
+		// those in the unit of the latest one.
+		latest := results[len(results)-1]
+		series.Name = latest.Name
+		series.Unit = latest.Unit
+	}
+
+	for _, r := range results {
+		if r.Unit != series.Unit {
+			continue
+		}
+		series.Points = append(series.Points, models.LabPoint{
+			ResultID: r.ID,
+			ObservedAt: r.ObservedAt,
+			Value: r.Value,
+			Flag: r.Flag,
+		})
+	}
+
+	return series, nil
+}
@@ -3,20 +3,50 @@ package services
 import (
 	"github.com/google/uuid"
 
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
 	"igaku/visit-service/models"
 	"igaku/visit-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
 )
 
 type OrganizationService interface {
 	GetOrganizationByID(id uuid.UUID) (*models.Organization, error)
+	// FindNearest returns the organizations around the searched location,
+	// closest first.
+	FindNearest(
+		query dtos.NearestOrganizationQuery,
+	) ([]models.NearbyOrganization, error)
+	// ResolveOsmTypes looks up the types of the OSM objects organizations
+	// stored before types were tracked are located at.
+	ResolveOsmTypes(now time.Time) error
 }
 
+// osmTypeBatchSize is the number of organizations whose OSM type is
+// resolved per run, so as not to flood the geo service.
+const osmTypeBatchSize = 50
+
+// osmTypeRetryAfter is how long organizations whose OSM type failed to
+// resolve wait before being looked up again.
+const osmTypeRetryAfter = 24 * time.Hour
+
 type organizationService struct {
-	repo repositories.OrganizationRepository
+	repo		repositories.OrganizationRepository
+	geoClient	clients.GeoClient
 }
 
-func NewOrganizationService(repo repositories.OrganizationRepository) OrganizationService {
-	return &organizationService{repo: repo}
+func NewOrganizationService(
+	repo repositories.OrganizationRepository,
+	geoClient clients.GeoClient,
+) OrganizationService {
+	return &organizationService{repo: repo, geoClient: geoClient}
 }
 
 func (s *organizationService) GetOrganizationByID(id uuid.UUID) (*models.Organization, error) {
@@ -28,3 +58,108 @@ func (s *organizationService) GetOrganizationByID(id uuid.UUID) (*models.Organiz
 
 	return org, nil
 }
+
+func (s *organizationService) FindNearest(
+	query dtos.NearestOrganizationQuery,
+) ([]models.NearbyOrganization, error) {
+	lat, lon, err := s.locate(query)
+	if err != nil {
+		return nil, err
+	}
+
+	radius := query.RadiusKm
+	if radius <= 0 {
+		radius = dtos.DefaultSearchRadiusKm
+	}
+	radius = min(radius, dtos.MaxSearchRadiusKm)
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = dtos.DefaultNearestLimit
+	}
+	limit = min(limit, dtos.MaxNearestLimit)
+
+	specialty := strings.TrimSpace(query.Specialty)
+
+	return s.repo.FindNearest(lat, lon, radius, specialty, limit)
+}
+
+// locate returns the coordinates of the searched location, geocoding the
+// address if one is given.
+func (s *organizationService) locate(
+	query dtos.NearestOrganizationQuery,
+) (float64, float64, error) {
+	address := strings.TrimSpace(query.Address)
+	hasCoords := query.Lat != nil || query.Lon != nil
+
+	if address == "" {
+		if query.Lat == nil || query.Lon == nil ||
+			!commonsDtos.ValidCoordinates(*query.Lat, *query.Lon) {
+			return 0, 0, &igakuErrors.InvalidSearchLocationError{}
+		}
+		return *query.Lat, *query.Lon, nil
+	}
+	if hasCoords {
+		return 0, 0, &igakuErrors.InvalidSearchLocationError{}
+	}
+
+	locations, err := s.geoClient.Search(address)
+	if errors.Is(err, &commonsErrors.InternalError{}) {
+		return 0, 0, err
+	}
+	if err != nil {
+		log.Printf("Failed to geocode an address: %v", err)
+		return 0, 0, &igakuErrors.GeocodingFailedError{}
+	}
+	if len(locations) == 0 {
+		return 0, 0, &igakuErrors.AddressNotFoundError{}
+	}
+
+	lat, err := commonsDtos.ParseCoordinate(locations[0].Lat)
+	if err != nil {
+		log.Printf("Geocoded location has an invalid latitude: %v", err)
+		return 0, 0, &igakuErrors.GeocodingFailedError{}
+	}
+	lon, err := commonsDtos.ParseCoordinate(locations[0].Lon)
+	if err != nil {
+		log.Printf("Geocoded location has an invalid longitude: %v", err)
+		return 0, 0, &igakuErrors.GeocodingFailedError{}
+	}
+
+	return float64(lat), float64(lon), nil
+}
+
+// ResolveOsmTypes looks the objects up the way they were before types were
+// tracked, i.e. as nodes, ways and relations in turn, so that they resolve
+// to the objects they always meant.
+func (s *organizationService) ResolveOsmTypes(now time.Time) error {
+	orgs, err := s.repo.FindWithoutOsmType(
+		now.Add(-osmTypeRetryAfter), osmTypeBatchSize,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, org := range orgs {
+		location, err := s.geoClient.LookupLocation("", org.Location.ID)
+		if err != nil {
+			return err
+		}
+		if location == nil || location.OsmType == "" {
+			log.Printf(
+				"Failed to resolve the OSM type of organization %s: no object with ID %d",
+				org.ID, org.Location.ID,
+			)
+			if err := s.repo.MarkOsmTypeUnresolved(org.ID, now); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.repo.SetOsmType(org.ID, location.OsmType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// PatientAccess decides who may see a patient's clinical data. Every
+// service returning clinical data checks access through it.
+type PatientAccess interface {
+	// Check checks whether the user may see the category of the patient's
+	// data. Under the emergency role the user is the emergency access,
+	// which only extends to the patient it was granted to.
+	Check(
+		patientID, userID uuid.UUID,
+		role commonsModels.Role,
+		category models.ConsentCategory,
+	) error
+	// Categories returns the categories of the patient's data the user may
+	// see: admins and the patient see all of them, doctors those the
+	// patient permitted them, plus, if they are on the patient's care team,
+	// those the patient did not deny them. Doctors with emergency access to
+	// the patient see all of them, regardless of consents.
+	Categories(
+		patientID, userID uuid.UUID, role commonsModels.Role,
+	) ([]models.ConsentCategory, error)
+	// Consent returns the decision of the patient's consents in force for
+	// the doctor and category, or an empty decision when none applies.
+	// Deny takes precedence over permit.
+	Consent(
+		patientID, doctorID uuid.UUID, category models.ConsentCategory,
+	) (models.ConsentDecision, error)
+}
+
+type patientAccess struct {
+	careTeamRepo	repositories.CareTeamRepository
+	consentRepo	repositories.ConsentRepository
+	slotRepo	repositories.SlotRepository
+	emergencyRepo	repositories.EmergencyAccessRepository
+}
+
+func NewPatientAccess(
+	careTeamRepo repositories.CareTeamRepository,
+	consentRepo repositories.ConsentRepository,
+	slotRepo repositories.SlotRepository,
+	emergencyRepo repositories.EmergencyAccessRepository,
+) PatientAccess {
+	return &patientAccess{
+		careTeamRepo: careTeamRepo,
+		consentRepo: consentRepo,
+		slotRepo: slotRepo,
+		emergencyRepo: emergencyRepo,
+	}
+}
+
+func (a *patientAccess) Check(
+	patientID, userID uuid.UUID,
+	role commonsModels.Role,
+	category models.ConsentCategory,
+) error {
+	var categories []models.ConsentCategory
+	var err error
+	if role == models.EmergencyRole {
+		// Only the category actually read is logged.
+		categories, err = a.emergency(patientID, userID, category)
+	} else {
+		categories, err = a.Categories(patientID, userID, role)
+	}
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(categories, category) {
+		return &igakuErrors.AccessDeniedError{}
+	}
+	return nil
+}
+
+func (a *patientAccess) Categories(
+	patientID, userID uuid.UUID, role commonsModels.Role,
+) ([]models.ConsentCategory, error) {
+	switch role {
+	case commonsModels.Admin:
+		return models.ConsentCategories, nil
+	case commonsModels.Patient:
+		if patientID == userID {
+			return models.ConsentCategories, nil
+		}
+		return nil, nil
+	case models.EmergencyRole:
+		return a.emergency(patientID, userID, models.ConsentCategories...)
+	case commonsModels.Doctor:
+	default:
+		return nil, nil
+	}
+
+	decisions, err := a.decisions(patientID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The care team is only looked up when the consents leave some
+	// category undecided.
+	treating := false
+	if len(decisions) < len(models.ConsentCategories) {
+		treating, err = a.careTeamRepo.IsMember(patientID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var categories []models.ConsentCategory
+	for _, category := range models.ConsentCategories {
+		decision, decided := decisions[category]
+		if decision == models.Permit || (!decided && treating) {
+			categories = append(categories, category)
+		}
+	}
+	return categories, nil
+}
+
+func (a *patientAccess) Consent(
+	patientID, doctorID uuid.UUID, category models.ConsentCategory,
+) (models.ConsentDecision, error) {
+	decisions, err := a.decisions(patientID, doctorID)
+	if err != nil {
+		return "", err
+	}
+	return decisions[category], nil
+}
+
+// emergency returns the categories read under the emergency access if it
+// was granted for the patient and is still in force, logging the read. A
+// read which cannot be logged is refused.
+func (a *patientAccess) emergency(
+	patientID, accessID uuid.UUID, categories ...models.ConsentCategory,
+) ([]models.ConsentCategory, error) {
+	now := time.Now()
+	access, err := a.emergencyRepo.FindByID(accessID)
+	if errors.Is(err, &igakuErrors.EmergencyAccessNotFoundError{}) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if access.PatientID != patientID || !access.ActiveAt(now) {
+		return nil, nil
+	}
+
+	err = a.emergencyRepo.LogAccess(&models.EmergencyAccessEvent{
+		ID: uuid.New(),
+		AccessID: access.ID,
+		PatientID: patientID,
+		DoctorID: access.DoctorID,
+		Categories: categories,
+		AccessedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf(
+		"[EMERGENCY] Doctor %s read %v of patient %s under emergency access %s",
+		access.DoctorID, categories, patientID, access.ID,
+	)
+
+	return categories, nil
+}
+
+// decisions combines the patient's consents in force which apply to the
+// doctor, directly or through one of their organizations.
+func (a *patientAccess) decisions(
+	patientID, doctorID uuid.UUID,
+) (map[models.ConsentCategory]models.ConsentDecision, error) {
+	consents, err := a.consentRepo.FindActive(patientID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var organizationIDs []uuid.UUID
+	for _, c := range consents {
+		if c.GranteeType == models.OrganizationGrantee {
+			organizationIDs, err = a.slotRepo.FindOrganizationIDs(doctorID)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	decisions := make(map[models.ConsentCategory]models.ConsentDecision)
+	for _, c := range consents {
+		applies := (c.GranteeType == models.DoctorGrantee && c.GranteeID == doctorID) ||
+			(c.GranteeType == models.OrganizationGrantee &&
+				slices.Contains(organizationIDs, c.GranteeID))
+		if !applies {
+			continue
+		}
+
+		for _, category := range c.Categories {
+			if decisions[category] != models.Deny {
+				decisions[category] = c.Decision
+			}
+		}
+	}
+	return decisions, nil
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// drugSearchLimit caps the number of drugs returned by a single search.
+const drugSearchLimit = 25
+
+// verificationAlphabet is Crockford's base32, which leaves out letters
+// easily confused with digits when a code is typed in from paper.
+const verificationAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// interactionCategories are the categories of the patient's data read by the
+// interaction checker: prescriptions, allergies and genotypes.
+var interactionCategories = []models.ConsentCategory{
+	models.PrescriptionsCategory,
+	models.RecordsCategory,
+	models.GenomicsCategory,
+}
+
+type PrescriptionService interface {
+	Issue(
+		doctorID uuid.UUID, role commonsModels.Role,
+		req dtos.PrescriptionRequest,
+	) (*models.Prescription, error)
+	// Check previews the warnings Issue would raise for the drug.
+	Check(
+		patientID, userID uuid.UUID, role commonsModels.Role, drugID string,
+	) ([]models.InteractionWarning, error)
+	// Get returns a prescription to its patient, its issuing doctor and
+	// anyone else the patient's prescriptions are accessible to.
+	Get(
+		id, userID uuid.UUID, role commonsModels.Role,
+	) (*models.Prescription, error)
+	// List returns the prescriptions of a patient, or those issued by a
+	// doctor. activeOnly only applies to patients.
+	List(
+		userID uuid.UUID, role commonsModels.Role, activeOnly bool,
+	) ([]models.Prescription, error)
+	Cancel(
+		id, userID uuid.UUID, role commonsModels.Role,
+	) (*models.Prescription, error)
+	Dispense(code string) (*models.Prescription, error)
+	Verify(code string) (*dtos.PrescriptionVerification, error)
+	RenderPDF(
+		id, userID uuid.UUID, role commonsModels.Role,
+	) ([]byte, error)
+	ExpirePrescriptions(now time.Time) error
+	SearchDrugs(query string) []models.Drug
+}
+
+type prescriptionService struct {
+	repo		repositories.PrescriptionRepository
+	encounterRepo	repositories.EncounterRepository
+	access		PatientAccess
+	userClient	clients.UserClient
+	drugs		dictionaries.DrugDictionary
+	checker		InteractionChecker
+}
+
+func NewPrescriptionService(
+	repo repositories.PrescriptionRepository,
+	encounterRepo repositories.EncounterRepository,
+	access PatientAccess,
+	userClient clients.UserClient,
+	drugs dictionaries.DrugDictionary,
+	checker InteractionChecker,
+) PrescriptionService {
+	return &prescriptionService{
+		repo: repo,
+		encounterRepo: encounterRepo,
+		access: access,
+		userClient: userClient,
+		drugs: drugs,
+		checker: checker,
+	}
+}
+
+func (s *prescriptionService) Issue(
+	doctorID uuid.UUID, role commonsModels.Role,
+	req dtos.PrescriptionRequest,
+) (*models.Prescription, error) {
+	drug, ok := s.drugs.Lookup(req.DrugID)
+	if !ok {
+		return nil, &igakuErrors.UnknownDrugError{}
+	}
+
+	now := time.Now()
+	validFrom := now
+	if req.ValidFrom != nil {
+		validFrom = *req.ValidFrom
+	}
+	if !req.ValidUntil.After(validFrom) || !req.ValidUntil.After(now) {
+		return nil, &igakuErrors.InvalidTimeRangeError{}
+	}
+
+	patient, err := s.userClient.FindByID(req.PatientID)
+	if err != nil {
+		return nil, err
+	}
+	if patient.Role != commonsModels.Patient {
+		return nil, &igakuErrors.InvalidPatientError{}
+	}
+	if err := s.checkInteractionAccess(req.PatientID, doctorID, role); err != nil {
+		return nil, err
+	}
+
+	if req.EncounterID != nil {
+		enc, err := s.encounterRepo.FindByID(*req.EncounterID)
+		if err != nil {
+			return nil, err
+		}
+		if enc.DoctorID != doctorID || enc.PatientID != req.PatientID {
+			return nil, &igakuErrors.AccessDeniedError{}
+		}
+	}
+
+	warnings, err := s.checker.Check(req.PatientID, *drug)
+	if err != nil {
+		return nil, err
+	}
+	overrideReason := strings.TrimSpace(req.OverrideReason)
+	if hasSevere(warnings) && overrideReason == "" {
+		return nil, &igakuErrors.SevereInteractionError{Warnings: warnings}
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &models.Prescription{
+		ID: uuid.New(),
+		PatientID: req.PatientID,
+		DoctorID: doctorID,
+		EncounterID: req.EncounterID,
+		DrugID: drug.ID,
+		DrugName: drug.Name,
+		ATCCode: drug.ATCCode,
+		Form: drug.Form,
+		Strength: drug.Strength,
+		Dosage: req.Dosage,
+		Quantity: req.Quantity,
+		Refills: req.Refills,
+		ValidFrom: validFrom,
+		ValidUntil: req.ValidUntil,
+		Status: models.PrescriptionIssued,
+		VerificationCode: code,
+		IssuedAt: now,
+		Warnings: warnings,
+		OverrideReason: overrideReason,
+	}
+	if err := s.repo.Persist(p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (s *prescriptionService) Check(
+	patientID, userID uuid.UUID, role commonsModels.Role, drugID string,
+) ([]models.InteractionWarning, error) {
+	drug, ok := s.drugs.Lookup(drugID)
+	if !ok {
+		return nil, &igakuErrors.UnknownDrugError{}
+	}
+	if err := s.checkInteractionAccess(patientID, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.checker.Check(patientID, *drug)
+}
+
+// checkInteractionAccess checks whether the user may see every category of
+// the patient's data the interaction warnings are built from.
+func (s *prescriptionService) checkInteractionAccess(
+	patientID, userID uuid.UUID, role commonsModels.Role,
+) error {
+	for _, category := range interactionCategories {
+		if err := s.access.Check(patientID, userID, role, category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *prescriptionService) Get(
+	id, userID uuid.UUID, role commonsModels.Role,
+) (*models.Prescription, error) {
+	p, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.PatientID != userID {
+		err := s.checkInteractionAccess(p.PatientID, userID, role)
+		if errors.Is(err, &igakuErrors.AccessDeniedError{}) {
+			// Users who may see the prescription but not the allergies
+			// and genotypes the warnings and guidance are built from get
+			// it without them.
+			if p.DoctorID != userID {
+				err := s.access.Check(
+					p.PatientID, userID, role, models.PrescriptionsCategory,
+				)
+				if err != nil {
+					return nil, err
+				}
+			}
+			p.Warnings = nil
+			return p, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Genotypes may have been recorded after the prescription was issued,
+	// so the recommendations are looked up on every read.
+	guidance, err := s.checker.Guidance(p.PatientID, models.Drug{
+		ID: p.DrugID,
+		Name: p.DrugName,
+		ATCCode: p.ATCCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.Guidance = guidance
+
+	return p, nil
+}
+
+func (s *prescriptionService) List(
+	userID uuid.UUID, role commonsModels.Role, activeOnly bool,
+) ([]models.Prescription, error) {
+	if role == commonsModels.Doctor {
+		ps, err := s.repo.FindByDoctorID(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		// As in Get, the warnings are withheld from doctors who may no
+		// longer see the data they are built from.
+		allowed := make(map[uuid.UUID]bool)
+		for i := range ps {
+			patientID := ps[i].PatientID
+			ok, checked := allowed[patientID]
+			if !checked {
+				err := s.checkInteractionAccess(patientID, userID, role)
+				if err != nil && !errors.Is(err, &igakuErrors.AccessDeniedError{}) {
+					return nil, err
+				}
+				ok = err == nil
+				allowed[patientID] = ok
+			}
+			if !ok {
+				ps[i].Warnings = nil
+			}
+		}
+		return ps, nil
+	}
+
+	if activeOnly {
+		now := time.Now()
+		return s.repo.FindByPatientID(userID, &now)
+	}
+	return s.repo.FindByPatientID(userID, nil)
+}
+
+func (s *prescriptionService) Cancel(
+	id, userID uuid.UUID, role commonsModels.Role,
+) (*models.Prescription, error) {
+	p, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if role != commonsModels.Admin && p.DoctorID != userID {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	return s.repo.Cancel(id)
+}
+
+func (s *prescriptionService) Dispense(code string) (*models.Prescription, error) {
+	return s.repo.Dispense(normalizeVerificationCode(code), time.Now())
+}
+
+func (s *prescriptionService) Verify(code string) (*dtos.PrescriptionVerification, error) {
+	p, err := s.repo.FindByVerificationCode(normalizeVerificationCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.PrescriptionVerification{
+		VerificationCode: p.VerificationCode,
+		Status: p.Status,
+		DrugName: p.DrugName,
+		Form: p.Form,
+		Strength: p.Strength,
+		Dosage: p.Dosage,
+		Quantity: p.Quantity,
+		Refills: p.Refills,
+		Fills: p.Fills,
+		ValidFrom: p.ValidFrom,
+		ValidUntil: p.ValidUntil,
+		IssuedAt: p.IssuedAt,
+	}, nil
+}
+
+func (s *prescriptionService) RenderPDF(
+	id, userID uuid.UUID, role commonsModels.Role,
+) ([]byte, error) {
+	p, err := s.Get(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	patient, err := s.userClient.FindByID(p.PatientID)
+	if err != nil {
+		return nil, err
+	}
+	doctor, err := s.userClient.FindByID(p.DoctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	const dateLayout = "2006-01-02"
+	lines := []string{
+		fmt.Sprintf("Patient: %s", patient.Username),
+		fmt.Sprintf("Prescribed by: Dr. %s", doctor.Username),
+		fmt.Sprintf("Issued: %s", p.IssuedAt.Format("2006-01-02 15:04 MST")),
+		"",
+		fmt.Sprintf("Medication: %s %s (%s)", p.DrugName, p.Strength, p.Form),
+		fmt.Sprintf("Dosage: %s", p.Dosage),
+		fmt.Sprintf("Quantity: %d", p.Quantity),
+		fmt.Sprintf("Refills: %d", p.Refills),
+		fmt.Sprintf(
+			"Valid: %s to %s",
+			p.ValidFrom.Format(dateLayout), p.ValidUntil.Format(dateLayout),
+		),
+		"",
+		fmt.Sprintf("Verification code: %s", p.VerificationCode),
+		"The pharmacy can confirm that this prescription is genuine and " +
+		"still valid by entering the verification code in Igaku.",
+	}
+
+	return utils.RenderTextPDF("Igaku Prescription", lines), nil
+}
+
+func (s *prescriptionService) ExpirePrescriptions(now time.Time) error {
+	_, err := s.repo.ExpireDue(now)
+	return err
+}
+
+func (s *prescriptionService) SearchDrugs(query string) []models.Drug {
+	return s.drugs.Search(query, drugSearchLimit)
+}
+
+// newVerificationCode returns a random code of the form XXXX-XXXX-XXXX.
+func newVerificationCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, v := range buf {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(verificationAlphabet[int(v)%len(verificationAlphabet)])
+	}
+	return b.String(), nil
+}
+
+// normalizeVerificationCode accepts codes typed in lower case, without
+// dashes or with the letters Crockford's base32 maps to digits.
+func normalizeVerificationCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer(
+		"-", "", " ", "", "O", "0", "I", "1", "L", "1",
+	).Replace(code)
+
+	if len(code) != 12 {
+		return code
+	}
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12]
+}
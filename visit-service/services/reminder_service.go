@@ -0,0 +1,129 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"fmt"
+	"log"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+)
+
+type ReminderService interface {
+	GetPreferences(userID uuid.UUID) (*models.ReminderPreference, error)
+	UpdatePreferences(pref *models.ReminderPreference) error
+	SendDueReminders(now time.Time) error
+}
+
+// Reminder kinds ordered from the longest lead time to the shortest. Each
+// kind is only sent while the appointment is further away than the lead
+// time of the next one, so that booking a visit at short notice does not
+// trigger a burst of outdated reminders.
+var reminderLeadTimes = []struct {
+	kind	models.ReminderKind
+	lead	time.Duration
+}{
+	{models.DayBefore, 24 * time.Hour},
+	{models.HourBefore, time.Hour},
+}
+
+type reminderService struct {
+	repo		repositories.ReminderRepository
+	userClient	clients.UserClient
+	mailClient	clients.MailClient
+	from		string
+}
+
+func NewReminderService(
+	repo repositories.ReminderRepository,
+	userClient clients.UserClient,
+	mailClient clients.MailClient,
+	from string,
+) ReminderService {
+	return &reminderService{
+		repo: repo,
+		userClient: userClient,
+		mailClient: mailClient,
+		from: from,
+	}
+}
+
+func (s *reminderService) GetPreferences(
+	userID uuid.UUID,
+) (*models.ReminderPreference, error) {
+	pref, err := s.repo.FindPreference(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pref == nil {
+		pref = &models.ReminderPreference{
+			UserID: userID,
+			DayBefore: true,
+			HourBefore: true,
+		}
+	}
+
+	return pref, nil
+}
+
+func (s *reminderService) UpdatePreferences(
+	pref *models.ReminderPreference,
+) error {
+	return s.repo.SavePreference(pref)
+}
+
+func (s *reminderService) SendDueReminders(now time.Time) error {
+	for i, r := range reminderLeadTimes {
+		from := now
+		if i+1 < len(reminderLeadTimes) {
+			from = now.Add(reminderLeadTimes[i+1].lead)
+		}
+
+		appts, err := s.repo.ClaimDue(r.kind, from, now.Add(r.lead))
+		if err != nil {
+			return err
+		}
+
+		for _, appt := range appts {
+			// Reminders are claimed before being sent, so a failure
+			// here results in a missed reminder rather than a
+			// duplicated one on the next run.
+			if err := s.sendReminder(&appt); err != nil {
+				log.Printf(
+					"Failed to send '%s' reminder for appointment %s: %v",
+					r.kind, appt.ID, err,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *reminderService) sendReminder(appt *models.Appointment) error {
+	patient, err := s.userClient.FindByID(appt.PatientID)
+	if err != nil {
+		return err
+	}
+
+	to := []string{patient.Email}
+	msg := []byte(
+		fmt.Sprintf("From: %s\r\n", s.from) +
+		fmt.Sprintf("To: %s\r\n", patient.Email) +
+		"Subject: Igaku appointment reminder\r\n" +
+		"\r\n" +
+		fmt.Sprintf("Hello %s,\r\n", patient.Username) +
+		"\r\n" +
+		fmt.Sprintf(
+			"This is a reminder of your appointment at %s on %s.\r\n",
+			appt.Organization.Name,
+			appt.StartsAt.Format("2006-01-02 15:04 MST"),
+		),
+	)
+
+	return s.mailClient.SendMail(to, msg)
+}
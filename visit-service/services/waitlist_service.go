@@ -0,0 +1,223 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"fmt"
+	"log"
+	"time"
+
+	"igaku/visit-service/clients"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	commonsDtos "igaku/commons/dtos"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type WaitlistService interface {
+	Join(
+		patientID uuid.UUID, req dtos.WaitlistRequest,
+	) (*models.WaitlistEntry, error)
+	List(patientID uuid.UUID) ([]models.WaitlistEntry, error)
+	Withdraw(id, patientID uuid.UUID) error
+	AcceptOffer(id, patientID uuid.UUID) (*models.Appointment, error)
+	DeclineOffer(id, patientID uuid.UUID) error
+	// OfferSlot offers a freed slot to the next matching waitlisted
+	// patient, if there is any.
+	OfferSlot(slotID uuid.UUID) error
+	ExpireOffers(now time.Time) error
+}
+
+type waitlistService struct {
+	repo		repositories.WaitlistRepository
+	userClient	clients.UserClient
+	mailClient	clients.MailClient
+	eventClient	clients.EventClient
+	holdFor		time.Duration
+	from		string
+}
+
+func NewWaitlistService(
+	repo repositories.WaitlistRepository,
+	userClient clients.UserClient,
+	mailClient clients.MailClient,
+	eventClient clients.EventClient,
+	holdFor time.Duration,
+	from string,
+) WaitlistService {
+	return &waitlistService{
+		repo: repo,
+		userClient: userClient,
+		mailClient: mailClient,
+		eventClient: eventClient,
+		holdFor: holdFor,
+		from: from,
+	}
+}
+
+func (s *waitlistService) Join(
+	patientID uuid.UUID, req dtos.WaitlistRequest,
+) (*models.WaitlistEntry, error) {
+	if !req.To.After(req.From) {
+		return nil, &igakuErrors.InvalidTimeRangeError{}
+	}
+
+	entry := &models.WaitlistEntry{
+		ID: uuid.New(),
+		PatientID: patientID,
+		DoctorID: req.DoctorID,
+		OrganizationID: req.OrganizationID,
+		From: req.From,
+		To: req.To,
+		Status: models.Waiting,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Persist(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (s *waitlistService) List(patientID uuid.UUID) ([]models.WaitlistEntry, error) {
+	return s.repo.FindByPatientID(patientID)
+}
+
+func (s *waitlistService) Withdraw(id, patientID uuid.UUID) error {
+	entry, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if entry.PatientID != patientID {
+		return &igakuErrors.AccessDeniedError{}
+	}
+
+	freedSlotID, err := s.repo.Withdraw(id)
+	if err != nil {
+		return err
+	}
+
+	if freedSlotID != nil {
+		s.passOn(*freedSlotID)
+	}
+
+	return nil
+}
+
+func (s *waitlistService) AcceptOffer(
+	id, patientID uuid.UUID,
+) (*models.Appointment, error) {
+	offer, err := s.repo.FindOfferByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.Entry.PatientID != patientID {
+		return nil, &igakuErrors.AccessDeniedError{}
+	}
+
+	appt, err := s.repo.AcceptOffer(id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(
+		s.eventClient, commonsDtos.AppointmentBooked, appt.ID,
+		appt.PatientID, appt.DoctorID,
+	)
+
+	return appt, nil
+}
+
+func (s *waitlistService) DeclineOffer(id, patientID uuid.UUID) error {
+	offer, err := s.repo.FindOfferByID(id)
+	if err != nil {
+		return err
+	}
+
+	if offer.Entry.PatientID != patientID {
+		return &igakuErrors.AccessDeniedError{}
+	}
+
+	if err := s.repo.DeclineOffer(id); err != nil {
+		return err
+	}
+
+	s.passOn(offer.SlotID)
+
+	return nil
+}
+
+func (s *waitlistService) OfferSlot(slotID uuid.UUID) error {
+	offer, err := s.repo.OfferSlot(slotID, time.Now().Add(s.holdFor))
+	if err != nil {
+		return err
+	}
+
+	if offer == nil {
+		return nil
+	}
+
+	if err := s.notify(offer); err != nil {
+		log.Printf(
+			"Failed to notify patient about waitlist offer %s: %v",
+			offer.ID, err,
+		)
+	}
+
+	return nil
+}
+
+func (s *waitlistService) ExpireOffers(now time.Time) error {
+	slotIDs, err := s.repo.ExpireOffers(now)
+	if err != nil {
+		return err
+	}
+
+	for _, slotID := range slotIDs {
+		s.passOn(slotID)
+	}
+
+	return nil
+}
+
+// passOn offers a released slot to the next patient in line, logging
+// rather than returning failures since the release itself has already
+// been committed.
+func (s *waitlistService) passOn(slotID uuid.UUID) {
+	if err := s.OfferSlot(slotID); err != nil {
+		log.Printf(
+			"Failed to offer slot %s to the waitlist: %v", slotID, err,
+		)
+	}
+}
+
+func (s *waitlistService) notify(offer *models.WaitlistOffer) error {
+	patient, err := s.userClient.FindByID(offer.Entry.PatientID)
+	if err != nil {
+		return err
+	}
+
+	to := []string{patient.Email}
+	msg := []byte(
+		fmt.Sprintf("From: %s\r\n", s.from) +
+		fmt.Sprintf("To: %s\r\n", patient.Email) +
+		"Subject: Igaku appointment slot available\r\n" +
+		"\r\n" +
+		fmt.Sprintf("Hello %s,\r\n", patient.Username) +
+		"\r\n" +
+		fmt.Sprintf(
+			"A slot matching your waitlist request became available at %s on %s.\r\n",
+			offer.Slot.Organization.Name,
+			offer.Slot.StartsAt.Format("2006-01-02 15:04 MST"),
+		) +
+		fmt.Sprintf(
+			"It is held for you until %s. Log in to Igaku to accept or decline it.\r\n",
+			offer.ExpiresAt.Format("2006-01-02 15:04 MST"),
+		),
+	)
+
+	return s.mailClient.SendMail(to, msg)
+}
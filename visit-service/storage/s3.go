@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// s3Timeout bounds a single request, including the transfer of the
+// content.
+const s3Timeout = 5 * time.Minute
+
+// S3Config locates a bucket of an S3-compatible service such as MinIO.
+// Objects are addressed path-style, e.g. http://minio:9000/bucket/key.
+type S3Config struct {
+	Endpoint	string
+	Region		string
+	Bucket		string
+	AccessKeyID	string
+	SecretAccessKey	string
+}
+
+type s3Store struct {
+	cfg	S3Config
+	client	*http.Client
+}
+
+// NewS3Store creates a blob store backed by an S3 bucket, creating the
+// bucket if it does not exist yet.
+func NewS3Store(cfg S3Config) (BlobStore, error) {
+	if _, err := url.ParseRequestURI(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("missing S3 bucket")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+
+	s := &s3Store{cfg: cfg, client: &http.Client{Timeout: s3Timeout}}
+	if err := s.ensureBucket(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *s3Store) Put(blob Blob, body io.Reader) error {
+	req, err := s.request(http.MethodPut, blob.Key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = blob.Size
+	if blob.ContentType != "" {
+		req.Header.Set("Content-Type", blob.ContentType)
+	}
+
+	resp, err := s.do(req, blob.Checksum)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(key string) (io.ReadCloser, error) {
+	req, err := s.request(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("object %s: %w", key, fs.ErrNotExist)
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(resp)
+	}
+}
+
+func (s *s3Store) Delete(key string) error {
+	req, err := s.request(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return s3Error(resp)
+	}
+}
+
+func (s *s3Store) ensureBucket() error {
+	req, err := s.request(http.MethodHead, "", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+	default:
+		return fmt.Errorf("failed to access bucket %s: %s", s.cfg.Bucket, resp.Status)
+	}
+
+	req, err = s.request(http.MethodPut, "", nil)
+	if err != nil {
+		return err
+	}
+	resp, err = s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// request builds a request for an object, or for the bucket itself when
+// key is empty.
+func (s *s3Store) request(method, key string, body io.Reader) (*http.Request, error) {
+	path := "/" + s.cfg.Bucket
+	if key != "" {
+		path += "/" + key
+	}
+	return http.NewRequest(method, s.cfg.Endpoint+uriEscape(path, false), body)
+}
+
+func (s *s3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	SignRequest(req, s.cfg, payloadHash, time.Now())
+	return s.client.Do(req)
+}
+
+func s3Error(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("S3 request failed: %s: %s", resp.Status, msg)
+}
+
+// SignRequest adds an AWS Signature Version 4 to the request. The host,
+// the x-amz-* headers and the Content-Type and Range headers are signed.
+func SignRequest(req *http.Request, cfg S3Config, payloadHash string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	scope := date + "/" + region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") ||
+			lower == "content-type" || lower == "range" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEscape(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" +
+		hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+
+		cfg.AccessKeyID+"/"+scope+", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+func canonicalQuery(values url.Values) string {
+	pairs := make([]string, 0, len(values))
+	for name, list := range values {
+		for _, value := range list {
+			pairs = append(pairs, uriEscape(name, true)+"="+uriEscape(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEscape percent-encodes everything but unreserved characters, and
+// slashes unless escapeSlash is set, as required by Signature Version 4.
+func uriEscape(value string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~',
+			c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteString("%" + strings.ToUpper(strconv.FormatUint(uint64(c)|0x100, 16)[1:]))
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
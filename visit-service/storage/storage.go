@@ -0,0 +1,57 @@
+// Package storage keeps the content of documents in a blob store.
+package storage
+
+import (
+	"fmt"
+	"io"
+)
+
+// Blob describes content to be stored.
+type Blob struct {
+	Key		string
+	ContentType	string
+	Size		int64
+	// Checksum is the hex-encoded SHA-256 of the content.
+	Checksum	string
+}
+
+type BlobStore interface {
+	// Put stores blob.Size bytes read from body under blob.Key, replacing
+	// any previous content.
+	Put(blob Blob, body io.Reader) error
+	// Get opens the content stored under key. The error wraps
+	// fs.ErrNotExist when there is none.
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the content stored under key, if any.
+	Delete(key string) error
+}
+
+// Backend selects the kind of blob store.
+type Backend string
+
+const (
+	Local	Backend = "local"
+	S3	Backend = "s3"
+)
+
+// Config holds the settings of every backend; only those of the selected
+// one are used.
+type Config struct {
+	Backend		Backend
+	// LocalPath is the directory of the local backend.
+	LocalPath	string
+	S3		S3Config
+}
+
+// New creates the blob store selected by cfg. The local backend is the
+// default.
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case Local, "":
+		return NewLocalStore(cfg.LocalPath)
+	case S3:
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
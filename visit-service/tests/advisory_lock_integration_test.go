@@ -0,0 +1,51 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+)
+
+func TestGormAdvisoryLock(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := testUtils.SetupTestDatabase(ctx, t, utils.MigrateSchema)
+	defer cleanup()
+
+	lock := repositories.NewGormAdvisoryLock(db)
+	other := repositories.NewGormAdvisoryLock(db)
+
+	var nestedRan, otherKeyRan bool
+	ran, err := lock.TryRun(1, func() error {
+		// Another replica cannot take the lock while it is held...
+		ran, err := other.TryRun(1, func() error {
+			nestedRan = true
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, ran)
+
+		// ...but may take locks of other keys.
+		_, err = other.TryRun(2, func() error {
+			otherKeyRan = true
+			return nil
+		})
+		return err
+	})
+	require.NoError(t, err)
+	assert.True(t, ran)
+	assert.False(t, nestedRan)
+	assert.True(t, otherKeyRan)
+
+	// The lock is released once the work is done.
+	ran, err = other.TryRun(1, func() error { return nil })
+	require.NoError(t, err)
+	assert.True(t, ran)
+}
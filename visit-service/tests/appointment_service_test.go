@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// newTestEventClient accepts any event, for tests which are not about
+// them.
+func newTestEventClient() *mocks.EventClient {
+	client := new(mocks.EventClient)
+	client.On("Publish", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil).
+		Maybe()
+	return client
+}
+
+// newTestHomeAddressRepository has no home addresses, for tests which are
+// not about travel estimates.
+func newTestHomeAddressRepository() *mocks.HomeAddressRepository {
+	repo := new(mocks.HomeAddressRepository)
+	repo.On("FindByPatientID", mock.Anything).Return(nil, nil).Maybe()
+	return repo
+}
+
+func TestAppointmentService_Book_PublishesEvent(t *testing.T) {
+	mockSlotRepo := new(mocks.SlotRepository)
+	mockEventClient := new(mocks.EventClient)
+	service := services.NewAppointmentService(
+		new(mocks.AppointmentRepository), mockSlotRepo,
+		new(MockOrganizationRepository),
+		newTestWaitlistService(
+			new(mocks.WaitlistRepository),
+			new(mocks.UserClient),
+			new(mocks.MailClient),
+		),
+		mockEventClient, newTestHomeAddressRepository(), new(mocks.GeoClient),
+	)
+
+	slotID := uuid.New()
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		SlotID: &slotID,
+		Status: models.Booked,
+	}
+	mockSlotRepo.On("Book", slotID, appt.PatientID).Return(appt, nil).Once()
+	// A failed event does not fail the booking.
+	mockEventClient.
+		On(
+			"Publish", commonsDtos.AppointmentBooked, appt.ID,
+			[]uuid.UUID{appt.PatientID, appt.DoctorID},
+		).
+		Return(&commonsErrors.MessageBrokerError{}).
+		Once()
+
+	res, err := service.Book(slotID, appt.PatientID)
+	require.NoError(t, err)
+	assert.Equal(t, appt.ID, res.ID)
+	mockEventClient.AssertExpectations(t)
+}
+
+func TestAppointmentService_Cancel_OffersFreedSlot(t *testing.T) {
+	mockApptRepo := new(mocks.AppointmentRepository)
+	mockWaitlistRepo := new(mocks.WaitlistRepository)
+	mockEventClient := new(mocks.EventClient)
+	waitlistService := newTestWaitlistService(
+		mockWaitlistRepo, new(mocks.UserClient), new(mocks.MailClient),
+	)
+	service := services.NewAppointmentService(
+		mockApptRepo, new(mocks.SlotRepository),
+		new(MockOrganizationRepository), waitlistService, mockEventClient,
+		newTestHomeAddressRepository(), new(mocks.GeoClient),
+	)
+
+	patientID := uuid.New()
+	slotID := uuid.New()
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		PatientID: patientID,
+		DoctorID: uuid.New(),
+		SlotID: &slotID,
+		StartsAt: time.Now().Add(48 * time.Hour),
+		Status: models.Booked,
+	}
+	cancelled := *appt
+	cancelled.Status = models.Cancelled
+
+	mockApptRepo.On("FindByID", appt.ID).Return(appt, nil).Once()
+	mockApptRepo.On("Cancel", appt.ID).Return(&cancelled, nil).Once()
+	mockWaitlistRepo.On("OfferSlot", slotID, mock.Anything).Return(nil, nil).Once()
+	mockEventClient.
+		On(
+			"Publish", commonsDtos.AppointmentCancelled, appt.ID,
+			[]uuid.UUID{appt.PatientID, appt.DoctorID},
+		).
+		Return(nil).
+		Once()
+
+	res, err := service.Cancel(appt.ID, patientID, commonsModels.Patient)
+	require.NoError(t, err)
+	assert.Equal(t, models.Cancelled, res.Status)
+
+	mockApptRepo.AssertExpectations(t)
+	mockWaitlistRepo.AssertExpectations(t)
+	mockEventClient.AssertExpectations(t)
+}
+
+func TestAppointmentService_Cancel_OtherPatient(t *testing.T) {
+	mockApptRepo := new(mocks.AppointmentRepository)
+	service := services.NewAppointmentService(
+		mockApptRepo, new(mocks.SlotRepository),
+		new(MockOrganizationRepository),
+		newTestWaitlistService(
+			new(mocks.WaitlistRepository),
+			new(mocks.UserClient),
+			new(mocks.MailClient),
+		),
+		newTestEventClient(), newTestHomeAddressRepository(),
+		new(mocks.GeoClient),
+	)
+
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		Status: models.Booked,
+	}
+	mockApptRepo.On("FindByID", appt.ID).Return(appt, nil).Once()
+
+	_, err := service.Cancel(appt.ID, uuid.New(), commonsModels.Patient)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockApptRepo.AssertNotCalled(t, "Cancel", mock.Anything)
+}
+
+func TestAppointmentService_Book_EstimatesTravelFromHome(t *testing.T) {
+	mockSlotRepo := new(mocks.SlotRepository)
+	mockOrgRepo := new(MockOrganizationRepository)
+	mockHomeRepo := new(mocks.HomeAddressRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewAppointmentService(
+		new(mocks.AppointmentRepository), mockSlotRepo, mockOrgRepo,
+		newTestWaitlistService(
+			new(mocks.WaitlistRepository),
+			new(mocks.UserClient),
+			new(mocks.MailClient),
+		),
+		newTestEventClient(), mockHomeRepo, mockGeoClient,
+	)
+
+	slotID := uuid.New()
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		OrganizationID: uuid.New(),
+		SlotID: &slotID,
+		Status: models.Booked,
+	}
+	estimates := []commonsDtos.RouteEstimate{
+		{Mode: commonsDtos.Driving, DistanceMeters: 6100, DurationSeconds: 720},
+	}
+	mockSlotRepo.On("Book", slotID, appt.PatientID).Return(appt, nil).Once()
+	mockHomeRepo.On("FindByPatientID", appt.PatientID).
+		Return(&models.HomeAddress{
+			PatientID: appt.PatientID, Lat: 42.3736158, Lon: -71.1097335,
+		}, nil).
+		Once()
+	mockOrgRepo.On("FindByID", appt.OrganizationID).
+		Return(&models.Organization{
+			ID: appt.OrganizationID,
+			Location: commonsDtos.Location{
+				Lat: "42.3628605", Lon: "-71.068753",
+			},
+		}, nil).
+		Once()
+	mockGeoClient.On("EstimateRoute", commonsDtos.RouteRequest{
+		FromLat: 42.3736158, FromLon: -71.1097335,
+		ToLat: 42.3628605, ToLon: -71.068753,
+	}).Return(estimates, nil).Once()
+
+	res, err := service.Book(slotID, appt.PatientID)
+	require.NoError(t, err)
+	assert.Equal(t, estimates, res.TravelEstimates)
+	mockGeoClient.AssertExpectations(t)
+}
+
+func TestAppointmentService_Book_WithoutHomeAddress(t *testing.T) {
+	mockSlotRepo := new(mocks.SlotRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewAppointmentService(
+		new(mocks.AppointmentRepository), mockSlotRepo,
+		new(MockOrganizationRepository),
+		newTestWaitlistService(
+			new(mocks.WaitlistRepository),
+			new(mocks.UserClient),
+			new(mocks.MailClient),
+		),
+		newTestEventClient(), newTestHomeAddressRepository(), mockGeoClient,
+	)
+
+	slotID := uuid.New()
+	appt := &models.Appointment{
+		ID: uuid.New(), PatientID: uuid.New(), SlotID: &slotID,
+	}
+	mockSlotRepo.On("Book", slotID, appt.PatientID).Return(appt, nil).Once()
+
+	res, err := service.Book(slotID, appt.PatientID)
+	require.NoError(t, err)
+	assert.Nil(t, res.TravelEstimates)
+	mockGeoClient.AssertNotCalled(t, "EstimateRoute", mock.Anything)
+}
+
+func TestAppointmentService_Book_GeoServiceDown(t *testing.T) {
+	mockSlotRepo := new(mocks.SlotRepository)
+	mockOrgRepo := new(MockOrganizationRepository)
+	mockHomeRepo := new(mocks.HomeAddressRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewAppointmentService(
+		new(mocks.AppointmentRepository), mockSlotRepo, mockOrgRepo,
+		newTestWaitlistService(
+			new(mocks.WaitlistRepository),
+			new(mocks.UserClient),
+			new(mocks.MailClient),
+		),
+		newTestEventClient(), mockHomeRepo, mockGeoClient,
+	)
+
+	slotID := uuid.New()
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		OrganizationID: uuid.New(),
+		SlotID: &slotID,
+	}
+	mockSlotRepo.On("Book", slotID, appt.PatientID).Return(appt, nil).Once()
+	mockHomeRepo.On("FindByPatientID", appt.PatientID).
+		Return(&models.HomeAddress{PatientID: appt.PatientID}, nil).
+		Once()
+	mockOrgRepo.On("FindByID", appt.OrganizationID).
+		Return(&models.Organization{
+			ID: appt.OrganizationID,
+			Location: commonsDtos.Location{Lat: "42.36", Lon: "-71.06"},
+		}, nil).
+		Once()
+	mockGeoClient.On("EstimateRoute", mock.Anything).
+		Return(nil, &commonsErrors.InternalError{}).
+		Once()
+
+	// The booking succeeds without estimates.
+	res, err := service.Book(slotID, appt.PatientID)
+	require.NoError(t, err)
+	assert.Equal(t, appt.ID, res.ID)
+	assert.Nil(t, res.TravelEstimates)
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func setupCareTeamRouter(mockRepo *mocks.CareTeamRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	careTeamService := services.NewCareTeamService(
+		mockRepo, new(MockOrganizationRepository), new(mocks.UserClient),
+	)
+	careTeamController := controllers.NewCareTeamController(careTeamService)
+
+	router := gin.Default()
+	careTeamController.RegisterRoutes(router)
+	return router
+}
+
+func TestCareTeamController_Patients(t *testing.T) {
+	mockRepo := new(mocks.CareTeamRepository)
+	router := setupCareTeamRouter(mockRepo)
+
+	doctorID := uuid.New()
+	mockRepo.On("FindByDoctorID", doctorID).Return([]models.CareAssignment{
+		{ID: uuid.New(), PatientID: uuid.New(), DoctorID: doctorID},
+	}, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/visit/patients", nil)
+	req.Header.Set("Authorization", genToken(t, doctorID, commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var assignments []models.CareAssignment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &assignments))
+	assert.Len(t, assignments, 1)
+
+	req, _ = http.NewRequest(http.MethodGet, "/visit/patients", nil)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Patient))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCareTeamController_Assign_AdminOnly(t *testing.T) {
+	mockRepo := new(mocks.CareTeamRepository)
+	router := setupCareTeamRouter(mockRepo)
+
+	body, _ := json.Marshal(map[string]any{
+		"doctor_id": uuid.New(),
+		"organization_id": uuid.New(),
+	})
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/patients/"+uuid.NewString()+"/care-team",
+		bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "Assign", mock.Anything)
+}
+
+func TestCareTeamController_Remove_NotFound(t *testing.T) {
+	mockRepo := new(mocks.CareTeamRepository)
+	router := setupCareTeamRouter(mockRepo)
+
+	id, patientID := uuid.New(), uuid.New()
+	mockRepo.On("End", id, patientID, mock.Anything).
+		Return(nil, &igakuErrors.CareAssignmentNotFoundError{})
+
+	req, _ := http.NewRequest(
+		http.MethodDelete,
+		"/visit/patients/"+patientID.String()+"/care-team/"+id.String(),
+		nil,
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Admin))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,105 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormCareTeamRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+	orgID := uuid.MustParse("86e6a1f3-d7aa-4e74-a20a-ea78bc13340b")
+	freeSlotID := uuid.MustParse("3e7b2d1c-4a5f-4b6e-9d8c-1f2a3b4c5d6e")
+	apptID := uuid.MustParse("c4b0a1b2-8d6e-4c2f-9e1a-7f3d5b9a0c11")
+
+	t.Run("BookingAndCancellation", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormCareTeamRepository(db)
+		slotRepo := repositories.NewGormSlotRepository(db)
+		apptRepo := repositories.NewGormAppointmentRepository(db)
+
+		team, err := repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		require.Len(t, team, 2)
+		assert.Equal(t, models.PrimaryDoctor, team[0].Role)
+
+		// Only the automatic assignment at the organization of the
+		// cancelled appointment ends.
+		_, err = apptRepo.Cancel(apptID)
+		require.NoError(t, err)
+		team, err = repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		require.Len(t, team, 1)
+		assert.Equal(t, orgID, team[0].OrganizationID)
+
+		newPatientID := uuid.New()
+		_, err = slotRepo.Book(freeSlotID, newPatientID)
+		require.NoError(t, err)
+
+		member, err := repo.IsMember(newPatientID, doctorID)
+		require.NoError(t, err)
+		assert.True(t, member)
+
+		patientIDs, err := repo.FindPatientIDs(doctorID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{patientID, newPatientID}, patientIDs)
+	})
+
+	t.Run("Assign_ReplacesPrimaryDoctor", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormCareTeamRepository(db)
+		otherDoctorID := uuid.New()
+		assigned, err := repo.Assign(&models.CareAssignment{
+			ID: uuid.New(),
+			PatientID: patientID,
+			DoctorID: otherDoctorID,
+			OrganizationID: orgID,
+			Role: models.PrimaryDoctor,
+			Source: models.ManualSource,
+			AssignedAt: time.Now(),
+		})
+		require.NoError(t, err)
+
+		team, err := repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		require.Len(t, team, 3)
+		assert.Equal(t, otherDoctorID, team[0].DoctorID)
+		for _, a := range team[1:] {
+			assert.Equal(t, models.CareTeamMember, a.Role)
+		}
+
+		_, err = repo.End(assigned.ID, patientID, time.Now())
+		require.NoError(t, err)
+		_, err = repo.End(assigned.ID, patientID, time.Now())
+		assert.ErrorIs(t, err, &igakuErrors.CareAssignmentNotFoundError{})
+
+		member, err := repo.IsMember(patientID, otherDoctorID)
+		require.NoError(t, err)
+		assert.False(t, member)
+	})
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestCareTeamService_List_Access(t *testing.T) {
+	mockRepo := new(mocks.CareTeamRepository)
+	service := services.NewCareTeamService(
+		mockRepo, new(MockOrganizationRepository), new(mocks.UserClient),
+	)
+
+	patientID, memberID, otherID := uuid.New(), uuid.New(), uuid.New()
+	mockRepo.On("IsMember", patientID, memberID).Return(true, nil)
+	mockRepo.On("IsMember", patientID, otherID).Return(false, nil)
+	mockRepo.On("FindByPatientID", patientID).Return([]models.CareAssignment{
+		{PatientID: patientID, DoctorID: memberID, Role: models.PrimaryDoctor},
+	}, nil)
+
+	_, err := service.List(patientID, memberID, commonsModels.Doctor)
+	assert.NoError(t, err)
+	_, err = service.List(patientID, patientID, commonsModels.Patient)
+	assert.NoError(t, err)
+	_, err = service.List(patientID, otherID, commonsModels.Doctor)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	_, err = service.List(patientID, uuid.New(), commonsModels.Patient)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestCareTeamService_Assign(t *testing.T) {
+	mockRepo := new(mocks.CareTeamRepository)
+	mockOrgRepo := new(MockOrganizationRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := services.NewCareTeamService(mockRepo, mockOrgRepo, mockUserClient)
+
+	patientID, doctorID, orgID, adminID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", patientID).
+		Return(&commonsModels.User{ID: patientID, Role: commonsModels.Patient}, nil)
+	mockUserClient.On("FindByID", doctorID).
+		Return(&commonsModels.User{ID: doctorID, Role: commonsModels.Doctor}, nil)
+	mockOrgRepo.On("FindByID", orgID).Return(&models.Organization{ID: orgID}, nil)
+	mockRepo.On("Assign", mock.MatchedBy(func(a *models.CareAssignment) bool {
+		return a.PatientID == patientID && a.DoctorID == doctorID &&
+			a.OrganizationID == orgID && a.Role == models.CareTeamMember &&
+			a.Source == models.ManualSource && *a.AssignedBy == adminID
+	})).
+		Return(&models.CareAssignment{ID: uuid.New()}, nil).
+		Once()
+
+	_, err := service.Assign(patientID, adminID, dtos.CareAssignmentRequest{
+		DoctorID: doctorID, OrganizationID: orgID,
+	})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCareTeamService_Assign_Invalid(t *testing.T) {
+	mockRepo := new(mocks.CareTeamRepository)
+	mockOrgRepo := new(MockOrganizationRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := services.NewCareTeamService(mockRepo, mockOrgRepo, mockUserClient)
+
+	patientID, doctorID, orgID := uuid.New(), uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", patientID).
+		Return(&commonsModels.User{ID: patientID, Role: commonsModels.Patient}, nil)
+	mockUserClient.On("FindByID", doctorID).
+		Return(&commonsModels.User{ID: doctorID, Role: commonsModels.Doctor}, nil)
+	mockUserClient.On("FindByID", mock.Anything).
+		Return(nil, &commonsErrors.UserNotFoundError{})
+	mockOrgRepo.On("FindByID", orgID).
+		Return(nil, &igakuErrors.OrganizationNotFoundError{})
+
+	_, err := service.Assign(doctorID, uuid.New(), dtos.CareAssignmentRequest{
+		DoctorID: doctorID, OrganizationID: orgID,
+	})
+	assert.ErrorIs(t, err, &igakuErrors.InvalidCareTeamPatientError{})
+
+	_, err = service.Assign(patientID, uuid.New(), dtos.CareAssignmentRequest{
+		DoctorID: uuid.New(), OrganizationID: orgID,
+	})
+	assert.ErrorIs(t, err, &igakuErrors.InvalidCareTeamMemberError{})
+
+	_, err = service.Assign(patientID, uuid.New(), dtos.CareAssignmentRequest{
+		DoctorID: doctorID, OrganizationID: orgID,
+	})
+	assert.ErrorIs(t, err, &igakuErrors.OrganizationNotFoundError{})
+	mockRepo.AssertNotCalled(t, "Assign", mock.Anything)
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func setupConsentRouter(
+	mockRepo *mocks.ConsentRepository,
+	mockOrgRepo *MockOrganizationRepository,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	consentService := services.NewConsentService(
+		mockRepo, mockOrgRepo, new(mocks.UserClient),
+	)
+	consentController := controllers.NewConsentController(consentService)
+
+	router := gin.Default()
+	consentController.RegisterRoutes(router)
+	return router
+}
+
+func TestConsentController_Grant_Organization(t *testing.T) {
+	mockRepo := new(mocks.ConsentRepository)
+	mockOrgRepo := new(MockOrganizationRepository)
+	router := setupConsentRouter(mockRepo, mockOrgRepo)
+
+	patientID, orgID := uuid.New(), uuid.New()
+	mockOrgRepo.On("FindByID", orgID).Return(&models.Organization{ID: orgID}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Consent"), patientID).
+		Return(nil)
+
+	body, _ := json.Marshal(map[string]any{
+		"grantee_type": "organization",
+		"grantee_id": orgID,
+		"decision": "deny",
+		"categories": []string{"genomics"},
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/visit/consents", bytes.NewReader(body))
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var consent models.Consent
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &consent))
+	assert.Equal(t, models.Deny, consent.Decision)
+	assert.Equal(t, orgID, consent.GranteeID)
+}
+
+func TestConsentController_Grant_InvalidPayload(t *testing.T) {
+	mockRepo := new(mocks.ConsentRepository)
+	router := setupConsentRouter(mockRepo, new(MockOrganizationRepository))
+
+	body, _ := json.Marshal(map[string]any{
+		"grantee_type": "pharmacy",
+		"grantee_id": uuid.New(),
+		"categories": []string{"records"},
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/visit/consents", bytes.NewReader(body))
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestConsentController_DoctorForbidden(t *testing.T) {
+	router := setupConsentRouter(
+		new(mocks.ConsentRepository), new(MockOrganizationRepository),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "/visit/consents", nil)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestConsentController_Revoke(t *testing.T) {
+	mockRepo := new(mocks.ConsentRepository)
+	router := setupConsentRouter(mockRepo, new(MockOrganizationRepository))
+
+	patientID := uuid.New()
+	revoked, missing := uuid.New(), uuid.New()
+	mockRepo.On("Revoke", revoked, patientID, patientID, mock.Anything).
+		Return(nil, &igakuErrors.ConsentRevokedError{})
+	mockRepo.On("Revoke", missing, patientID, patientID, mock.Anything).
+		Return(nil, &igakuErrors.ConsentNotFoundError{})
+
+	tests := []struct {
+		id	uuid.UUID
+		code	int
+	}{
+		{revoked, http.StatusConflict},
+		{missing, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(
+			http.MethodDelete, "/visit/consents/"+tt.id.String(), nil,
+		)
+		req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code)
+	}
+}
@@ -0,0 +1,94 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormConsentRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+
+	t.Run("GrantRevokeHistory", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormConsentRepository(db)
+		now := time.Now()
+		later := now.Add(time.Hour)
+		consent := &models.Consent{
+			ID: uuid.New(),
+			PatientID: patientID,
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Deny,
+			Categories: []models.ConsentCategory{models.GenomicsCategory},
+			ValidFrom: now,
+			CreatedAt: now,
+		}
+		pending := &models.Consent{
+			ID: uuid.New(),
+			PatientID: patientID,
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Permit,
+			Categories: []models.ConsentCategory{models.RecordsCategory},
+			ValidFrom: later,
+			CreatedAt: now.Add(time.Second),
+		}
+		require.NoError(t, repo.Create(consent, patientID))
+		require.NoError(t, repo.Create(pending, patientID))
+
+		consents, err := repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		require.Len(t, consents, 2)
+		assert.Equal(t, pending.ID, consents[0].ID)
+
+		active, err := repo.FindActive(patientID, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		assert.Equal(t, consent.ID, active[0].ID)
+		assert.Equal(t, consent.Categories, active[0].Categories)
+
+		revoked, err := repo.Revoke(consent.ID, patientID, patientID, later)
+		require.NoError(t, err)
+		require.NotNil(t, revoked.RevokedAt)
+
+		_, err = repo.Revoke(consent.ID, patientID, patientID, later)
+		assert.ErrorIs(t, err, &igakuErrors.ConsentRevokedError{})
+		_, err = repo.Revoke(consent.ID, uuid.New(), patientID, later)
+		assert.ErrorIs(t, err, &igakuErrors.ConsentNotFoundError{})
+
+		active, err = repo.FindActive(patientID, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Empty(t, active)
+
+		events, err := repo.FindEvents(patientID)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		assert.Equal(t, models.ConsentRevoked, events[0].Action)
+		assert.Equal(t, consent.ID, events[0].ConsentID)
+		assert.Equal(t, models.Deny, events[0].Decision)
+
+		// The history cannot be rewritten.
+		err = db.Delete(&events[0]).Error
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestConsentService_Grant_Doctor(t *testing.T) {
+	mockRepo := new(mocks.ConsentRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := services.NewConsentService(
+		mockRepo, new(MockOrganizationRepository), mockUserClient,
+	)
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", doctorID).
+		Return(&commonsModels.User{ID: doctorID, Role: commonsModels.Doctor}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Consent"), patientID).
+		Return(nil).
+		Once()
+
+	consent, err := service.Grant(patientID, dtos.ConsentRequest{
+		GranteeType: models.DoctorGrantee,
+		GranteeID: doctorID,
+		Categories: []models.ConsentCategory{
+			models.LabResultsCategory, models.LabResultsCategory,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, patientID, consent.PatientID)
+	assert.Equal(t, models.Permit, consent.Decision)
+	assert.Equal(t, []models.ConsentCategory{models.LabResultsCategory}, consent.Categories)
+	assert.Nil(t, consent.ValidUntil)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConsentService_Grant_Invalid(t *testing.T) {
+	mockRepo := new(mocks.ConsentRepository)
+	mockOrgRepo := new(MockOrganizationRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := services.NewConsentService(mockRepo, mockOrgRepo, mockUserClient)
+
+	patientID, userID, orgID := uuid.New(), uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", userID).
+		Return(&commonsModels.User{ID: userID, Role: commonsModels.Patient}, nil)
+	mockUserClient.On("FindByID", mock.Anything).
+		Return(nil, &commonsErrors.UserNotFoundError{})
+	mockOrgRepo.On("FindByID", orgID).
+		Return(nil, &igakuErrors.OrganizationNotFoundError{})
+
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name	string
+		req	dtos.ConsentRequest
+		err	error
+	}{
+		{
+			"unknown category",
+			dtos.ConsentRequest{
+				GranteeType: models.DoctorGrantee,
+				GranteeID: userID,
+				Categories: []models.ConsentCategory{"billing"},
+			},
+			&igakuErrors.InvalidConsentCategoryError{},
+		},
+		{
+			"expired",
+			dtos.ConsentRequest{
+				GranteeType: models.DoctorGrantee,
+				GranteeID: userID,
+				Categories: []models.ConsentCategory{models.RecordsCategory},
+				ValidUntil: &past,
+			},
+			&igakuErrors.InvalidTimeRangeError{},
+		},
+		{
+			"grantee not a doctor",
+			dtos.ConsentRequest{
+				GranteeType: models.DoctorGrantee,
+				GranteeID: userID,
+				Categories: []models.ConsentCategory{models.RecordsCategory},
+			},
+			&igakuErrors.InvalidGranteeError{},
+		},
+		{
+			"unknown doctor",
+			dtos.ConsentRequest{
+				GranteeType: models.DoctorGrantee,
+				GranteeID: uuid.New(),
+				Categories: []models.ConsentCategory{models.RecordsCategory},
+			},
+			&igakuErrors.InvalidGranteeError{},
+		},
+		{
+			"unknown organization",
+			dtos.ConsentRequest{
+				GranteeType: models.OrganizationGrantee,
+				GranteeID: orgID,
+				Categories: []models.ConsentCategory{models.RecordsCategory},
+			},
+			&igakuErrors.InvalidGranteeError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Grant(patientID, tt.req)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestConsentService_List_ActiveOnly(t *testing.T) {
+	mockRepo := new(mocks.ConsentRepository)
+	service := services.NewConsentService(
+		mockRepo, new(MockOrganizationRepository), new(mocks.UserClient),
+	)
+
+	patientID := uuid.New()
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	active := models.Consent{ID: uuid.New(), ValidFrom: now.Add(-2 * time.Hour)}
+	mockRepo.On("FindByPatientID", patientID).Return([]models.Consent{
+		active,
+		{ID: uuid.New(), ValidFrom: now.Add(-2 * time.Hour), ValidUntil: &expired},
+		{ID: uuid.New(), ValidFrom: now.Add(-2 * time.Hour), RevokedAt: &expired},
+	}, nil)
+
+	consents, err := service.List(patientID, true)
+	require.NoError(t, err)
+	require.Len(t, consents, 1)
+	assert.Equal(t, active.ID, consents[0].ID)
+
+	consents, err = service.List(patientID, false)
+	require.NoError(t, err)
+	assert.Len(t, consents, 3)
+}
+
+func TestConsentService_Revoke(t *testing.T) {
+	mockRepo := new(mocks.ConsentRepository)
+	service := services.NewConsentService(
+		mockRepo, new(MockOrganizationRepository), new(mocks.UserClient),
+	)
+
+	id, patientID := uuid.New(), uuid.New()
+	mockRepo.On("Revoke", id, patientID, patientID, mock.AnythingOfType("time.Time")).
+		Return(nil, &igakuErrors.ConsentRevokedError{})
+
+	_, err := service.Revoke(id, patientID)
+	assert.ErrorIs(t, err, &igakuErrors.ConsentRevokedError{})
+}
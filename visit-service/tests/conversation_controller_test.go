@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	commonsModels "igaku/commons/models"
+)
+
+func setupConversationRouter() (*gin.Engine, *conversationMocks) {
+	gin.SetMode(gin.TestMode)
+
+	service, m := newTestConversationService()
+	conversationController := controllers.NewConversationController(service)
+
+	router := gin.Default()
+	conversationController.RegisterRoutes(router)
+	return router, m
+}
+
+func TestConversationController_Unread(t *testing.T) {
+	router, m := setupConversationRouter()
+
+	userID := uuid.New()
+	m.repo.On("CountUnread", userID).Return(int64(3), nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "/visit/conversations/unread", nil)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var count dtos.UnreadCount
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &count))
+	assert.EqualValues(t, 3, count.Unread)
+}
+
+func TestConversationController_MarkRead(t *testing.T) {
+	router, m := setupConversationRouter()
+
+	patientID := uuid.New()
+	conversation := &models.Conversation{
+		ID: uuid.New(), PatientID: patientID, DoctorID: uuid.New(),
+	}
+	m.repo.On("FindByID", conversation.ID).Return(conversation, nil)
+	m.repo.On("MarkRead", conversation.ID, patientID, mock.Anything).
+		Return(int64(1), nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/conversations/"+conversation.ID.String()+"/read", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	m.repo.AssertExpectations(t)
+}
+
+func TestConversationController_AdminAndEmergencyForbidden(t *testing.T) {
+	router, m := setupConversationRouter()
+
+	for _, token := range []string{
+		genToken(t, uuid.New(), commonsModels.Admin),
+		genEmergencyToken(t, newTestEmergencyAccess(uuid.New(), uuid.New())),
+	} {
+		req, _ := http.NewRequest(http.MethodGet, "/visit/conversations", nil)
+		req.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+	m.repo.AssertNotCalled(t, "FindByParticipant", mock.Anything)
+}
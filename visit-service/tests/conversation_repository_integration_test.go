@@ -0,0 +1,91 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormConversationRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+	conversationID := uuid.MustParse("0c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f")
+
+	t.Run("UnreadAndReadReceipts", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormConversationRepository(db)
+
+		count, err := repo.CountUnread(doctorID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+		count, err = repo.CountUnread(patientID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		conversations, err := repo.FindByParticipant(doctorID)
+		require.NoError(t, err)
+		require.Len(t, conversations, 1)
+		assert.EqualValues(t, 1, conversations[0].Unread)
+
+		read, err := repo.MarkRead(conversationID, doctorID, time.Now())
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, read)
+
+		messages, err := repo.FindMessages(conversationID)
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		for _, msg := range messages {
+			assert.NotNil(t, msg.ReadAt)
+		}
+	})
+
+	t.Run("AddMessage", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormConversationRepository(db)
+		now := time.Now().Truncate(time.Microsecond)
+		err := repo.AddMessage(&models.Message{
+			ID: uuid.New(),
+			ConversationID: conversationID,
+			SenderID: doctorID,
+			Body: "Your fasting result is back.",
+			SentAt: now,
+		})
+		require.NoError(t, err)
+
+		conversation, err := repo.FindByID(conversationID)
+		require.NoError(t, err)
+		assert.True(t, conversation.LastMessageAt.Equal(now))
+
+		conversations, err := repo.FindByParticipant(patientID)
+		require.NoError(t, err)
+		require.Len(t, conversations, 1)
+		assert.EqualValues(t, 1, conversations[0].Unread)
+
+		_, err = repo.FindByID(uuid.New())
+		assert.ErrorIs(t, err, &igakuErrors.ConversationNotFoundError{})
+	})
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"strings"
+	"testing"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+type conversationMocks struct {
+	repo		*mocks.ConversationRepository
+	apptRepo	*mocks.AppointmentRepository
+	careTeamRepo	*mocks.CareTeamRepository
+	documentRepo	*mocks.DocumentRepository
+	userClient	*mocks.UserClient
+	mailClient	*mocks.MailClient
+	eventClient	*mocks.EventClient
+}
+
+func newTestConversationService() (services.ConversationService, *conversationMocks) {
+	m := &conversationMocks{
+		repo: new(mocks.ConversationRepository),
+		apptRepo: new(mocks.AppointmentRepository),
+		careTeamRepo: new(mocks.CareTeamRepository),
+		documentRepo: new(mocks.DocumentRepository),
+		userClient: new(mocks.UserClient),
+		mailClient: new(mocks.MailClient),
+		eventClient: newTestEventClient(),
+	}
+	service := services.NewConversationService(
+		m.repo, m.apptRepo, m.careTeamRepo, m.documentRepo,
+		newTestPatientAccess(m.careTeamRepo), m.userClient, m.mailClient,
+		m.eventClient, "igaku@mail.com",
+	)
+	return service, m
+}
+
+func TestConversationService_Start_CareTeam(t *testing.T) {
+	service, m := newTestConversationService()
+
+	patient := &commonsModels.User{
+		ID: uuid.New(), Username: "jdoe", Email: "jdoe@mail.com",
+		Role: commonsModels.Patient,
+	}
+	doctor := &commonsModels.User{
+		ID: uuid.New(), Username: "ghouse", Email: "ghouse@mail.com",
+		Role: commonsModels.Doctor,
+	}
+	m.careTeamRepo.On("IsMember", patient.ID, doctor.ID).Return(true, nil)
+	m.userClient.On("FindByID", patient.ID).Return(patient, nil)
+	m.userClient.On("FindByID", doctor.ID).Return(doctor, nil)
+	m.repo.On("Create",
+		mock.MatchedBy(func(c *models.Conversation) bool {
+			return c.PatientID == patient.ID && c.DoctorID == doctor.ID
+		}),
+		mock.MatchedBy(func(msg *models.Message) bool {
+			return msg.SenderID == doctor.ID
+		}),
+	).Return(nil).Once()
+	// The notification never carries the contents of the message.
+	m.mailClient.
+		On("SendMail", []string{patient.Email}, mock.MatchedBy(func(msg []byte) bool {
+			return strings.Contains(string(msg), "ghouse") &&
+				!strings.Contains(string(msg), "Glucose") &&
+				!strings.Contains(string(msg), "fasting")
+		})).
+		Return(nil).
+		Once()
+
+	conversation, err := service.Start(doctor.ID, commonsModels.Doctor, dtos.ConversationRequest{
+		ParticipantID: patient.ID,
+		Subject: "Glucose follow-up",
+		Message: dtos.MessageRequest{Body: "Please book a fasting test."},
+	})
+	require.NoError(t, err)
+	m.repo.AssertExpectations(t)
+	m.mailClient.AssertExpectations(t)
+	m.eventClient.AssertCalled(
+		t, "Publish", commonsDtos.MessageReceived, conversation.ID,
+		[]uuid.UUID{patient.ID},
+	)
+}
+
+func TestConversationService_Start_NoCareRelationship(t *testing.T) {
+	service, m := newTestConversationService()
+
+	patientID, doctorID, otherDoctorID := uuid.New(), uuid.New(), uuid.New()
+	apptID := uuid.New()
+	m.careTeamRepo.On("IsMember", patientID, doctorID).Return(false, nil)
+	m.apptRepo.On("FindByID", apptID).Return(&models.Appointment{
+		ID: apptID, PatientID: patientID, DoctorID: otherDoctorID,
+	}, nil)
+
+	_, err := service.Start(patientID, commonsModels.Patient, dtos.ConversationRequest{
+		ParticipantID: doctorID,
+		Subject: "Question",
+		Message: dtos.MessageRequest{Body: "Hello"},
+	})
+	assert.ErrorIs(t, err, &igakuErrors.CareRelationshipRequiredError{})
+
+	_, err = service.Start(patientID, commonsModels.Patient, dtos.ConversationRequest{
+		ParticipantID: doctorID,
+		AppointmentID: &apptID,
+		Subject: "Question",
+		Message: dtos.MessageRequest{Body: "Hello"},
+	})
+	assert.ErrorIs(t, err, &igakuErrors.CareRelationshipRequiredError{})
+	m.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestConversationService_Send_Attachment(t *testing.T) {
+	service, m := newTestConversationService()
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	conversation := &models.Conversation{
+		ID: uuid.New(), PatientID: patientID, DoctorID: doctorID,
+	}
+	ownDoc := &models.Document{ID: uuid.New(), PatientID: patientID}
+	otherDoc := &models.Document{ID: uuid.New(), PatientID: uuid.New()}
+	m.repo.On("FindByID", conversation.ID).Return(conversation, nil)
+	m.documentRepo.On("FindByID", ownDoc.ID).Return(ownDoc, nil)
+	m.documentRepo.On("FindByID", otherDoc.ID).Return(otherDoc, nil)
+	m.careTeamRepo.On("IsMember", patientID, doctorID).Return(true, nil)
+	m.repo.On("AddMessage", mock.MatchedBy(func(msg *models.Message) bool {
+		return *msg.DocumentID == ownDoc.ID
+	})).Return(nil).Once()
+	// A failed notification does not fail the message.
+	m.userClient.On("FindByID", mock.Anything).
+		Return(nil, &commonsErrors.UserNotFoundError{})
+
+	_, err := service.Send(conversation.ID, patientID, dtos.MessageRequest{
+		Body: "My readings", DocumentID: &ownDoc.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = service.Send(conversation.ID, patientID, dtos.MessageRequest{
+		Body: "Someone else's readings", DocumentID: &otherDoc.ID,
+	})
+	assert.ErrorIs(t, err, &igakuErrors.DocumentNotFoundError{})
+	m.repo.AssertExpectations(t)
+}
+
+func TestConversationService_OtherUsersConversation(t *testing.T) {
+	service, m := newTestConversationService()
+
+	conversation := &models.Conversation{
+		ID: uuid.New(), PatientID: uuid.New(), DoctorID: uuid.New(),
+	}
+	m.repo.On("FindByID", conversation.ID).Return(conversation, nil)
+
+	_, err := service.Messages(conversation.ID, uuid.New())
+	assert.ErrorIs(t, err, &igakuErrors.ConversationNotFoundError{})
+	err = service.MarkRead(conversation.ID, uuid.New())
+	assert.ErrorIs(t, err, &igakuErrors.ConversationNotFoundError{})
+	m.repo.AssertNotCalled(t, "FindMessages", mock.Anything)
+}
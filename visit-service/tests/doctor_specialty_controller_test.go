@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+)
+
+func setupDoctorSpecialtyRouter(
+	mockRepo *mocks.DoctorSpecialtyRepository,
+	mockUserClient *mocks.UserClient,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	specialtyService := services.NewDoctorSpecialtyService(
+		mockRepo, mockUserClient,
+	)
+	specialtyController := controllers.NewDoctorSpecialtyController(
+		specialtyService,
+	)
+
+	router := gin.Default()
+	specialtyController.RegisterRoutes(router)
+	return router
+}
+
+func TestDoctorSpecialtyController_List(t *testing.T) {
+	mockRepo := new(mocks.DoctorSpecialtyRepository)
+	router := setupDoctorSpecialtyRouter(mockRepo, new(mocks.UserClient))
+
+	doctorID := uuid.New()
+	mockRepo.On("FindByDoctorID", doctorID).
+		Return([]string{"cardiology"}, nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		"/visit/doctors/"+doctorID.String()+"/specialties",
+		nil,
+	)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var specialties []string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &specialties))
+	assert.Equal(t, []string{"cardiology"}, specialties)
+}
+
+func TestDoctorSpecialtyController_Replace(t *testing.T) {
+	mockRepo := new(mocks.DoctorSpecialtyRepository)
+	mockUserClient := new(mocks.UserClient)
+	router := setupDoctorSpecialtyRouter(mockRepo, mockUserClient)
+
+	doctorID, patientID := uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", doctorID).
+		Return(&commonsModels.User{ID: doctorID, Role: commonsModels.Doctor}, nil)
+	mockUserClient.On("FindByID", patientID).
+		Return(&commonsModels.User{ID: patientID, Role: commonsModels.Patient}, nil)
+	mockRepo.On("Replace", doctorID, []string{"cardiology"}).
+		Return(nil).
+		Once()
+
+	send := func(id uuid.UUID, role commonsModels.Role) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]any{
+			"specialties": []string{"Cardiology"},
+		})
+		req, _ := http.NewRequest(
+			http.MethodPut,
+			"/visit/doctors/"+id.String()+"/specialties",
+			bytes.NewReader(body),
+		)
+		req.Header.Set("Authorization", genToken(t, uuid.New(), role))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(doctorID, commonsModels.Doctor)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = send(patientID, commonsModels.Admin)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = send(doctorID, commonsModels.Admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	var specialties []string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &specialties))
+	assert.Equal(t, []string{"cardiology"}, specialties)
+
+	mockRepo.AssertNumberOfCalls(t, "Replace", 1)
+	mockRepo.AssertNotCalled(t, "Replace", patientID, mock.Anything)
+}
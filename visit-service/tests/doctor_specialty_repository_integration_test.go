@@ -0,0 +1,66 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+)
+
+func TestGormDoctorSpecialtyRepository(t *testing.T) {
+	t.Run("FindByDoctorID", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormDoctorSpecialtyRepository(db)
+
+		doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+		specialties, err := repo.FindByDoctorID(doctorID)
+		require.NoError(t, err)
+		assert.Equal(
+			t, []string{"cardiology", "internal medicine"}, specialties,
+		)
+
+		specialties, err = repo.FindByDoctorID(uuid.New())
+		require.NoError(t, err)
+		assert.Empty(t, specialties)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormDoctorSpecialtyRepository(db)
+
+		doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+		err := repo.Replace(doctorID, []string{"neurology"})
+		require.NoError(t, err)
+
+		specialties, err := repo.FindByDoctorID(doctorID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"neurology"}, specialties)
+
+		err = repo.Replace(doctorID, nil)
+		require.NoError(t, err)
+
+		specialties, err = repo.FindByDoctorID(doctorID)
+		require.NoError(t, err)
+		assert.Empty(t, specialties)
+	})
+}
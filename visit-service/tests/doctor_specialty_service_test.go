@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestDoctorSpecialtyService_Replace(t *testing.T) {
+	mockRepo := new(mocks.DoctorSpecialtyRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := services.NewDoctorSpecialtyService(mockRepo, mockUserClient)
+
+	doctorID := uuid.New()
+	mockUserClient.On("FindByID", doctorID).
+		Return(&commonsModels.User{ID: doctorID, Role: commonsModels.Doctor}, nil)
+	expected := []string{"cardiology", "internal medicine"}
+	mockRepo.On("Replace", doctorID, expected).Return(nil).Once()
+
+	specialties, err := service.Replace(doctorID, []string{
+		"Internal Medicine", " cardiology", "", "CARDIOLOGY",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, expected, specialties)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDoctorSpecialtyService_Replace_NotDoctor(t *testing.T) {
+	mockRepo := new(mocks.DoctorSpecialtyRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := services.NewDoctorSpecialtyService(mockRepo, mockUserClient)
+
+	patientID, unknownID := uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", patientID).
+		Return(&commonsModels.User{ID: patientID, Role: commonsModels.Patient}, nil)
+	mockUserClient.On("FindByID", unknownID).
+		Return(nil, &commonsErrors.UserNotFoundError{})
+
+	_, err := service.Replace(patientID, []string{"cardiology"})
+	assert.ErrorIs(t, err, &igakuErrors.InvalidDoctorError{})
+	_, err = service.Replace(unknownID, []string{"cardiology"})
+	assert.ErrorIs(t, err, &igakuErrors.InvalidDoctorError{})
+	mockRepo.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+)
+
+func setupDocumentRouter(t *testing.T, mockRepo *mocks.DocumentRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	documentService, _ := newTestDocumentService(
+		t, mockRepo, new(mocks.CareTeamRepository), time.Minute,
+	)
+	documentController := controllers.NewDocumentController(documentService)
+
+	router := gin.Default()
+	documentController.RegisterRoutes(router)
+	return router
+}
+
+func newDocumentUploadRequest(
+	t *testing.T, patientID uuid.UUID, fields map[string]string, content []byte,
+) *http.Request {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	part, err := writer.CreateFormFile("file", "referral.pdf")
+	require.NoError(t, err)
+	part.Write(content)
+	writer.Close()
+
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/patients/"+patientID.String()+"/documents", body,
+	)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	return req
+}
+
+func TestDocumentController_UploadAndDownload(t *testing.T) {
+	mockRepo := new(mocks.DocumentRepository)
+	router := setupDocumentRouter(t, mockRepo)
+
+	patientID := uuid.New()
+	var stored *models.Document
+	mockRepo.On("Create", mock.AnythingOfType("*models.Document")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*models.Document)
+		}).
+		Return(nil).
+		Once()
+
+	req := newDocumentUploadRequest(t, patientID, map[string]string{
+		"title": "Cardiology referral",
+		"category": "referral",
+	}, testPDF)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "Cardiology referral", doc.Title)
+	assert.Equal(t, models.ReferralDocument, doc.Category)
+
+	mockRepo.On("FindByID", doc.ID).Return(stored, nil)
+
+	req, _ = http.NewRequest(
+		http.MethodPost, "/visit/documents/"+doc.ID.String()+"/link", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var link dtos.DocumentLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+
+	req, _ = http.NewRequest(http.MethodGet, link.URL, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, testPDF, w.Body.Bytes())
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, "\""+doc.Checksum+"\"", w.Header().Get("ETag"))
+}
+
+func TestDocumentController_Upload_UnsupportedType(t *testing.T) {
+	mockRepo := new(mocks.DocumentRepository)
+	router := setupDocumentRouter(t, mockRepo)
+
+	patientID := uuid.New()
+	req := newDocumentUploadRequest(
+		t, patientID, nil, []byte("<html><script>alert(1)</script></html>"),
+	)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestDocumentController_Download_InvalidSignature(t *testing.T) {
+	router := setupDocumentRouter(t, new(mocks.DocumentRepository))
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		"/visit/documents/"+uuid.NewString()+"/content?expires=9999999999&signature=forged",
+		nil,
+	)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDocumentController_Delete_EmergencyAccess(t *testing.T) {
+	mockRepo := new(mocks.DocumentRepository)
+	router := setupDocumentRouter(t, mockRepo)
+
+	req, _ := http.NewRequest(
+		http.MethodDelete, "/visit/documents/"+uuid.New().String(), nil,
+	)
+	req.Header.Set("Authorization", genEmergencyToken(t, newTestEmergencyAccess(uuid.New(), uuid.New())))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
@@ -0,0 +1,67 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormDocumentRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+
+	t.Run("CreateFindDelete", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormDocumentRepository(db)
+		newDocument := func(createdAt time.Time) *models.Document {
+			id := uuid.New()
+			return &models.Document{
+				ID: id,
+				PatientID: patientID,
+				Title: "Referral",
+				Category: models.ReferralDocument,
+				FileName: "referral.pdf",
+				ContentType: "application/pdf",
+				Size: 42,
+				Checksum: "00",
+				StorageKey: "patients/" + patientID.String() + "/" + id.String(),
+				UploadedBy: patientID,
+				CreatedAt: createdAt,
+			}
+		}
+		older := newDocument(time.Now().Add(-time.Hour))
+		newer := newDocument(time.Now())
+		require.NoError(t, repo.Create(older))
+		require.NoError(t, repo.Create(newer))
+
+		docs, err := repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+		assert.Equal(t, newer.ID, docs[0].ID)
+
+		found, err := repo.FindByID(older.ID)
+		require.NoError(t, err)
+		assert.Equal(t, older.StorageKey, found.StorageKey)
+
+		require.NoError(t, repo.Delete(found))
+		_, err = repo.FindByID(older.ID)
+		assert.ErrorIs(t, err, &igakuErrors.DocumentNotFoundError{})
+	})
+}
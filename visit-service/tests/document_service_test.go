@@ -0,0 +1,283 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/storage"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n%%EOF\n")
+
+func newTestDocumentService(
+	t *testing.T,
+	repo *mocks.DocumentRepository,
+	careTeamRepo *mocks.CareTeamRepository,
+	linkTTL time.Duration,
+) (services.DocumentService, storage.BlobStore) {
+	t.Helper()
+
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	service := services.NewDocumentService(
+		repo, newTestPatientAccess(careTeamRepo), new(mocks.EncounterRepository),
+		store,
+		[]byte("test-secret"), linkTTL,
+	)
+	return service, store
+}
+
+// linkParams reads the query parameters of a document link.
+func linkParams(t *testing.T, link *dtos.DocumentLink) (uuid.UUID, dtos.DocumentLinkParams) {
+	t.Helper()
+
+	u, err := url.Parse(link.URL)
+	require.NoError(t, err)
+	id := uuid.MustParse(path.Base(path.Dir(u.Path)))
+
+	query := u.Query()
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	require.NoError(t, err)
+	return id, dtos.DocumentLinkParams{
+		UserID: uuid.MustParse(query.Get("user")),
+		Role: commonsModels.Role(query.Get("role")),
+		Expires: expires,
+		Signature: query.Get("signature"),
+	}
+}
+
+func TestDocumentService_Upload(t *testing.T) {
+	mockRepo := new(mocks.DocumentRepository)
+	service, store := newTestDocumentService(
+		t, mockRepo, new(mocks.CareTeamRepository), time.Minute,
+	)
+
+	patientID := uuid.New()
+	mockRepo.On("Create", mock.AnythingOfType("*models.Document")).
+		Return(nil).
+		Once()
+
+	upload := dtos.DocumentUpload{
+		Category: models.ReferralDocument,
+		FileName: `C:\scans\referral.pdf`,
+	}
+	doc, err := service.Upload(
+		patientID, patientID, commonsModels.Patient, upload,
+		bytes.NewReader(testPDF),
+	)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(testPDF)
+	assert.Equal(t, "referral.pdf", doc.FileName)
+	assert.Equal(t, "referral.pdf", doc.Title)
+	assert.Equal(t, "application/pdf", doc.ContentType)
+	assert.Equal(t, int64(len(testPDF)), doc.Size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), doc.Checksum)
+	assert.Equal(t, patientID, doc.UploadedBy)
+
+	rc, err := store.Get(doc.StorageKey)
+	require.NoError(t, err)
+	defer rc.Close()
+	stored, _ := io.ReadAll(rc)
+	assert.Equal(t, testPDF, stored)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDocumentService_Upload_Limits(t *testing.T) {
+	patientID := uuid.New()
+	tests := []struct {
+		name	string
+		content	io.Reader
+		err	error
+	}{
+		{"Empty", strings.NewReader(""), &igakuErrors.EmptyDocumentError{}},
+		{
+			"UnsupportedType",
+			strings.NewReader("#!/bin/sh\nrm -rf /\n"),
+			&igakuErrors.UnsupportedDocumentTypeError{},
+		},
+		{
+			"TooLarge",
+			io.MultiReader(
+				bytes.NewReader(testPDF),
+				io.LimitReader(zeroReader{}, models.MaxDocumentSize),
+			),
+			&igakuErrors.DocumentTooLargeError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.DocumentRepository)
+			service, _ := newTestDocumentService(
+				t, mockRepo, new(mocks.CareTeamRepository), time.Minute,
+			)
+
+			_, err := service.Upload(
+				patientID, patientID, commonsModels.Patient,
+				dtos.DocumentUpload{FileName: "file"}, tt.content,
+			)
+			assert.ErrorIs(t, err, tt.err)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestDocumentService_Upload_DoctorOutsideCareTeam(t *testing.T) {
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service, _ := newTestDocumentService(
+		t, new(mocks.DocumentRepository), mockCareTeamRepo, time.Minute,
+	)
+
+	patientID := uuid.New()
+	mockCareTeamRepo.On("IsMember", patientID, mock.Anything).
+		Return(false, nil).
+		Once()
+
+	_, err := service.Upload(
+		patientID, uuid.New(), commonsModels.Doctor,
+		dtos.DocumentUpload{FileName: "scan.pdf"}, bytes.NewReader(testPDF),
+	)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestDocumentService_LinkAndOpen(t *testing.T) {
+	mockRepo := new(mocks.DocumentRepository)
+	service, store := newTestDocumentService(
+		t, mockRepo, new(mocks.CareTeamRepository), time.Minute,
+	)
+
+	patientID := uuid.New()
+	doc := &models.Document{
+		ID: uuid.New(),
+		PatientID: patientID,
+		StorageKey: "doc",
+		Size: int64(len(testPDF)),
+	}
+	require.NoError(t, store.Put(
+		storage.Blob{Key: doc.StorageKey, Size: doc.Size},
+		bytes.NewReader(testPDF),
+	))
+	mockRepo.On("FindByID", doc.ID).Return(doc, nil)
+
+	link, err := service.Link(doc.ID, patientID, commonsModels.Patient)
+	require.NoError(t, err)
+	id, params := linkParams(t, link)
+	assert.Equal(t, doc.ID, id)
+
+	_, content, err := service.Open(id, params)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	content.Close()
+	assert.Equal(t, testPDF, data)
+
+	t.Run("TamperedRole", func(t *testing.T) {
+		tampered := params
+		tampered.Role = commonsModels.Admin
+		_, _, err := service.Open(id, tampered)
+		assert.ErrorIs(t, err, &igakuErrors.InvalidDocumentLinkError{})
+	})
+
+	t.Run("OtherDocument", func(t *testing.T) {
+		_, _, err := service.Open(uuid.New(), params)
+		assert.ErrorIs(t, err, &igakuErrors.InvalidDocumentLinkError{})
+	})
+
+	t.Run("SignedWithSecret", func(t *testing.T) {
+		// Links must not be signed with the secret itself, which also
+		// signs JWT tokens.
+		mac := hmac.New(sha256.New, []byte("test-secret"))
+		mac.Write([]byte(strings.Join([]string{
+			id.String(),
+			params.UserID.String(),
+			string(params.Role),
+			strconv.FormatInt(params.Expires, 10),
+		}, "\n")))
+		forged := params
+		forged.Signature = base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+		assert.NotEqual(t, params.Signature, forged.Signature)
+		_, _, err := service.Open(id, forged)
+		assert.ErrorIs(t, err, &igakuErrors.InvalidDocumentLinkError{})
+	})
+
+	t.Run("MissingContent", func(t *testing.T) {
+		require.NoError(t, store.Delete(doc.StorageKey))
+		_, _, err := service.Open(id, params)
+		assert.ErrorIs(t, err, &igakuErrors.DocumentNotFoundError{})
+	})
+}
+
+func TestDocumentService_Open_Expired(t *testing.T) {
+	mockRepo := new(mocks.DocumentRepository)
+	service, _ := newTestDocumentService(
+		t, mockRepo, new(mocks.CareTeamRepository), -time.Minute,
+	)
+
+	patientID := uuid.New()
+	doc := &models.Document{ID: uuid.New(), PatientID: patientID}
+	mockRepo.On("FindByID", doc.ID).Return(doc, nil).Once()
+
+	link, err := service.Link(doc.ID, patientID, commonsModels.Patient)
+	require.NoError(t, err)
+
+	_, _, err = service.Open(linkParams(t, link))
+	assert.ErrorIs(t, err, &igakuErrors.InvalidDocumentLinkError{})
+}
+
+func TestDocumentService_Delete(t *testing.T) {
+	mockRepo := new(mocks.DocumentRepository)
+	service, store := newTestDocumentService(
+		t, mockRepo, new(mocks.CareTeamRepository), time.Minute,
+	)
+
+	uploader := uuid.New()
+	doc := &models.Document{
+		ID: uuid.New(), PatientID: uuid.New(), UploadedBy: uploader,
+		StorageKey: "doc", Size: 1,
+	}
+	require.NoError(t, store.Put(
+		storage.Blob{Key: doc.StorageKey, Size: 1}, strings.NewReader("x"),
+	))
+	mockRepo.On("FindByID", doc.ID).Return(doc, nil)
+
+	err := service.Delete(doc.ID, uuid.New(), commonsModels.Doctor)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+
+	mockRepo.On("Delete", doc).Return(nil).Once()
+	require.NoError(t, service.Delete(doc.ID, uploader, commonsModels.Doctor))
+
+	_, err = store.Get(doc.StorageKey)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/models"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func setupEmergencyAccessRouter(mockRepo *mocks.EmergencyAccessRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	emergencyService := newTestEmergencyAccessService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient),
+	)
+	emergencyController := controllers.NewEmergencyAccessController(emergencyService)
+
+	router := gin.Default()
+	emergencyController.RegisterRoutes(router)
+	return router
+}
+
+func TestEmergencyAccessController_Request_DoctorOnly(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	router := setupEmergencyAccessRouter(mockRepo)
+
+	body, _ := json.Marshal(map[string]any{
+		"patient_id": uuid.New(),
+		"justification": testJustification,
+	})
+	for _, token := range []string{
+		genToken(t, uuid.New(), commonsModels.Patient),
+		genToken(t, uuid.New(), commonsModels.Admin),
+		genEmergencyToken(t, newTestEmergencyAccess(uuid.New(), uuid.New())),
+	} {
+		req, _ := http.NewRequest(
+			http.MethodPost, "/visit/emergency-access", bytes.NewReader(body),
+		)
+		req.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestEmergencyAccessController_TokenWithoutAccessRejected(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	router := setupEmergencyAccessRouter(mockRepo)
+
+	// Emergency tokens must name the access they were issued for.
+	req, _ := http.NewRequest(http.MethodPost, "/visit/emergency-access", nil)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), models.EmergencyRole))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestEmergencyAccessController_Review_AlreadyReviewed(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	router := setupEmergencyAccessRouter(mockRepo)
+
+	id, adminID := uuid.New(), uuid.New()
+	mockRepo.On(
+		"Review", id, adminID, models.FlaggedReview, "No emergency", mock.Anything,
+	).Return(nil, &igakuErrors.EmergencyAccessReviewedError{})
+
+	body, _ := json.Marshal(map[string]any{
+		"status": "flagged",
+		"note": "No emergency",
+	})
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/emergency-access/"+id.String()+"/review",
+		bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, adminID, commonsModels.Admin))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestEmergencyAccessController_Review_InvalidStatus(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	router := setupEmergencyAccessRouter(mockRepo)
+
+	body, _ := json.Marshal(map[string]any{"status": "pending"})
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/emergency-access/"+uuid.NewString()+"/review",
+		bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Admin))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(
+		t, "Review", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything,
+	)
+}
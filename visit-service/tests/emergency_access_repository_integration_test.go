@@ -0,0 +1,127 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormEmergencyAccessRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+	adminID := uuid.MustParse("99ab51c4-a544-4352-a8df-4632ff8b105d")
+
+	newAccess := func(grantedAt time.Time) *models.EmergencyAccess {
+		return &models.EmergencyAccess{
+			ID: uuid.New(),
+			DoctorID: doctorID,
+			PatientID: patientID,
+			Justification: testJustification,
+			GrantedAt: grantedAt,
+			ExpiresAt: grantedAt.Add(time.Hour),
+			Status: models.PendingReview,
+		}
+	}
+
+	t.Run("FindActive", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormEmergencyAccessRepository(db)
+		now := time.Now()
+		expired := newAccess(now.Add(-2 * time.Hour))
+		active := newAccess(now.Add(-time.Minute))
+		require.NoError(t, repo.Create(expired))
+		require.NoError(t, repo.Create(active))
+
+		found, err := repo.FindActive(patientID, doctorID, now)
+		require.NoError(t, err)
+		assert.Equal(t, active.ID, found.ID)
+
+		_, err = repo.FindActive(patientID, doctorID, now.Add(2*time.Hour))
+		assert.ErrorIs(t, err, &igakuErrors.EmergencyAccessNotFoundError{})
+		_, err = repo.FindActive(doctorID, doctorID, now)
+		assert.ErrorIs(t, err, &igakuErrors.EmergencyAccessNotFoundError{})
+
+		queue, err := repo.FindByStatus(models.PendingReview)
+		require.NoError(t, err)
+		require.Len(t, queue, 2)
+		assert.Equal(t, expired.ID, queue[0].ID)
+	})
+
+	t.Run("Review_FlagEndsAccess", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormEmergencyAccessRepository(db)
+		now := time.Now()
+		access := newAccess(now.Add(-time.Minute))
+		require.NoError(t, repo.Create(access))
+
+		reviewed, err := repo.Review(
+			access.ID, adminID, models.FlaggedReview, "No emergency", now,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, models.FlaggedReview, reviewed.Status)
+		assert.NotNil(t, reviewed.RevokedAt)
+
+		_, err = repo.FindActive(patientID, doctorID, now.Add(time.Second))
+		assert.ErrorIs(t, err, &igakuErrors.EmergencyAccessNotFoundError{})
+
+		_, err = repo.Review(access.ID, adminID, models.ApprovedReview, "", now)
+		assert.ErrorIs(t, err, &igakuErrors.EmergencyAccessReviewedError{})
+		_, err = repo.Review(uuid.New(), adminID, models.ApprovedReview, "", now)
+		assert.ErrorIs(t, err, &igakuErrors.EmergencyAccessNotFoundError{})
+	})
+
+	t.Run("Events_AppendOnly", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormEmergencyAccessRepository(db)
+		access := newAccess(time.Now())
+		require.NoError(t, repo.Create(access))
+
+		event := &models.EmergencyAccessEvent{
+			ID: uuid.New(),
+			AccessID: access.ID,
+			PatientID: patientID,
+			DoctorID: doctorID,
+			Categories: []models.ConsentCategory{models.RecordsCategory},
+			AccessedAt: time.Now(),
+		}
+		require.NoError(t, repo.LogAccess(event))
+
+		events, err := repo.FindEvents(access.ID)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, []models.ConsentCategory{models.RecordsCategory}, events[0].Categories)
+
+		err = db.Delete(&models.EmergencyAccessEvent{}, "id = ?", event.ID).Error
+		assert.Error(t, err)
+	})
+}
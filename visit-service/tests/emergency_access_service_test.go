@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	"igaku/visit-service/utils"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+const testJustification = "Patient unconscious in the emergency room, allergies unknown"
+
+func newTestEmergencyAccessService(
+	repo *mocks.EmergencyAccessRepository,
+	userClient *mocks.UserClient,
+	mailClient *mocks.MailClient,
+) services.EmergencyAccessService {
+	return services.NewEmergencyAccessService(
+		repo, userClient, mailClient, time.Hour, "igaku@mail.com",
+	)
+}
+
+// newTestEmergencyAccess returns an access of the doctor to the patient in
+// force for the next hour.
+func newTestEmergencyAccess(patientID, doctorID uuid.UUID) *models.EmergencyAccess {
+	now := time.Now()
+	return &models.EmergencyAccess{
+		ID: uuid.New(),
+		DoctorID: doctorID,
+		PatientID: patientID,
+		Justification: testJustification,
+		GrantedAt: now.Add(-time.Minute),
+		ExpiresAt: now.Add(time.Hour),
+		Status: models.PendingReview,
+	}
+}
+
+func TestEmergencyAccessService_Request(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockMailClient := new(mocks.MailClient)
+	service := newTestEmergencyAccessService(mockRepo, mockUserClient, mockMailClient)
+
+	patient := &commonsModels.User{
+		ID: uuid.New(), Username: "jdoe", Email: "jdoe@mail.com",
+		Role: commonsModels.Patient,
+	}
+	doctor := &commonsModels.User{
+		ID: uuid.New(), Username: "ghouse", Role: commonsModels.Doctor,
+	}
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil)
+	mockUserClient.On("FindByID", doctor.ID).Return(doctor, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(a *models.EmergencyAccess) bool {
+		return a.PatientID == patient.ID && a.DoctorID == doctor.ID &&
+			a.Status == models.PendingReview &&
+			a.ExpiresAt.Sub(a.GrantedAt) == time.Hour
+	})).Return(nil).Once()
+	mockMailClient.
+		On("SendMail", []string{patient.Email}, mock.MatchedBy(func(msg []byte) bool {
+			return strings.Contains(string(msg), "ghouse") &&
+				strings.Contains(string(msg), testJustification)
+		})).
+		Return(nil).
+		Once()
+
+	grant, err := service.Request(doctor.ID, dtos.EmergencyAccessRequest{
+		PatientID: patient.ID,
+		Justification: "  " + testJustification + "  ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, testJustification, grant.Access.Justification)
+	mockRepo.AssertExpectations(t)
+	mockMailClient.AssertExpectations(t)
+
+	claims := utils.EmergencyClaims{}
+	_, err = jwt.ParseWithClaims(
+		grant.Token, &claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return []byte(os.Getenv("SECRET_KEY")), nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, models.EmergencyRole, claims.Role)
+	assert.Equal(t, doctor.ID.String(), claims.Subject)
+	assert.Equal(t, grant.Access.ID.String(), claims.AccessID)
+	assert.Equal(t, patient.ID.String(), claims.PatientID)
+	assert.WithinDuration(t, grant.Access.ExpiresAt, claims.ExpiresAt.Time, time.Second)
+}
+
+func TestEmergencyAccessService_Request_MailFailureIsIgnored(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockMailClient := new(mocks.MailClient)
+	service := newTestEmergencyAccessService(mockRepo, mockUserClient, mockMailClient)
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", patientID).
+		Return(&commonsModels.User{ID: patientID, Role: commonsModels.Patient}, nil)
+	mockUserClient.On("FindByID", doctorID).
+		Return(&commonsModels.User{ID: doctorID, Role: commonsModels.Doctor}, nil)
+	mockRepo.On("Create", mock.Anything).Return(nil)
+	mockMailClient.On("SendMail", mock.Anything, mock.Anything).
+		Return(&commonsErrors.MessageBrokerError{})
+
+	_, err := service.Request(doctorID, dtos.EmergencyAccessRequest{
+		PatientID: patientID, Justification: testJustification,
+	})
+	assert.NoError(t, err)
+}
+
+func TestEmergencyAccessService_Request_Invalid(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := newTestEmergencyAccessService(
+		mockRepo, mockUserClient, new(mocks.MailClient),
+	)
+
+	doctorID, otherDoctorID := uuid.New(), uuid.New()
+	mockUserClient.On("FindByID", otherDoctorID).
+		Return(&commonsModels.User{ID: otherDoctorID, Role: commonsModels.Doctor}, nil)
+	mockUserClient.On("FindByID", mock.Anything).
+		Return(nil, &commonsErrors.UserNotFoundError{})
+
+	_, err := service.Request(doctorID, dtos.EmergencyAccessRequest{
+		PatientID: uuid.New(), Justification: "   urgent   ",
+	})
+	assert.ErrorIs(t, err, &igakuErrors.InvalidJustificationError{})
+
+	_, err = service.Request(doctorID, dtos.EmergencyAccessRequest{
+		PatientID: otherDoctorID, Justification: testJustification,
+	})
+	assert.ErrorIs(t, err, &igakuErrors.InvalidEmergencyPatientError{})
+
+	_, err = service.Request(doctorID, dtos.EmergencyAccessRequest{
+		PatientID: uuid.New(), Justification: testJustification,
+	})
+	assert.ErrorIs(t, err, &igakuErrors.InvalidEmergencyPatientError{})
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestEmergencyAccessService_Queue(t *testing.T) {
+	mockRepo := new(mocks.EmergencyAccessRepository)
+	service := newTestEmergencyAccessService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient),
+	)
+
+	mockRepo.On("FindByStatus", models.PendingReview).
+		Return([]models.EmergencyAccess{{ID: uuid.New()}}, nil).
+		Once()
+
+	accesses, err := service.Queue("")
+	require.NoError(t, err)
+	assert.Len(t, accesses, 1)
+
+	_, err = service.Queue("closed")
+	assert.ErrorIs(t, err, &igakuErrors.InvalidReviewStatusError{})
+	mockRepo.AssertExpectations(t)
+}
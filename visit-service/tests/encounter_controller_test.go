@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/models"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+)
+
+func setupEncounterRouter(
+	mockRepo *mocks.EncounterRepository,
+	mockApptRepo *mocks.AppointmentRepository,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	encounterService := newTestEncounterService(
+		mockRepo, mockApptRepo, new(mocks.UserClient),
+	)
+	encounterController := controllers.NewEncounterController(encounterService)
+
+	router := gin.Default()
+	encounterController.RegisterRoutes(router)
+	return router
+}
+
+func TestEncounterController_Create_Patient(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockApptRepo := new(mocks.AppointmentRepository)
+	router := setupEncounterRouter(mockRepo, mockApptRepo)
+
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/visit/appointments/"+uuid.New().String()+"/encounter",
+		bytes.NewReader([]byte("{}")),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockApptRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestEncounterController_Create_InvalidVitals(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockApptRepo := new(mocks.AppointmentRepository)
+	router := setupEncounterRouter(mockRepo, mockApptRepo)
+
+	body, _ := json.Marshal(map[string]any{
+		"vitals": map[string]any{"oxygen_saturation": 140},
+	})
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/visit/appointments/"+uuid.New().String()+"/encounter",
+		bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockApptRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestEncounterController_GetByID_AccessDenied(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	router := setupEncounterRouter(mockRepo, new(mocks.AppointmentRepository))
+
+	enc := &models.Encounter{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+	}
+	mockRepo.On("FindByID", enc.ID).Return(enc, nil).Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/visit/encounters/"+enc.ID.String(), nil,
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,116 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormEncounterRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+	apptID := uuid.MustParse("5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a")
+
+	newEncounter := func() (*models.Encounter, *models.EncounterRevision) {
+		enc := &models.Encounter{
+			ID: uuid.New(),
+			AppointmentID: apptID,
+			PatientID: patientID,
+			DoctorID: doctorID,
+			CreatedAt: time.Now(),
+		}
+		rev := &models.EncounterRevision{
+			ID: uuid.New(),
+			AuthorID: doctorID,
+			Assessment: "Hypertension",
+			Diagnoses: []models.Diagnosis{
+				{Code: "I10", Description: "Essential (primary) hypertension"},
+			},
+			CreatedAt: time.Now(),
+		}
+		return enc, rev
+	}
+
+	t.Run("AppendRevision_KeepsHistory", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormEncounterRepository(db)
+		enc, rev := newEncounter()
+		require.NoError(t, repo.Create(enc, rev))
+
+		amendment := &models.EncounterRevision{
+			ID: uuid.New(),
+			EncounterID: enc.ID,
+			AuthorID: doctorID,
+			Reason: "Corrected assessment",
+			Assessment: "White coat hypertension",
+			CreatedAt: time.Now(),
+		}
+		require.NoError(t, repo.AppendRevision(amendment))
+		assert.Equal(t, 2, amendment.Version)
+
+		found, err := repo.FindByAppointmentID(apptID)
+		require.NoError(t, err)
+		require.Len(t, found.Revisions, 2)
+		assert.Equal(t, "Hypertension", found.Revisions[0].Assessment)
+		assert.Equal(t, "I10", found.Revisions[0].Diagnoses[0].Code)
+		assert.Equal(t, "White coat hypertension", found.Revisions[1].Assessment)
+	})
+
+	t.Run("Create_OncePerAppointment", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormEncounterRepository(db)
+		enc, rev := newEncounter()
+		require.NoError(t, repo.Create(enc, rev))
+
+		enc, rev = newEncounter()
+		err := repo.Create(enc, rev)
+		assert.ErrorIs(t, err, &igakuErrors.EncounterAlreadyExistsError{})
+	})
+
+	t.Run("Revisions_AreAppendOnly", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormEncounterRepository(db)
+		enc, rev := newEncounter()
+		require.NoError(t, repo.Create(enc, rev))
+
+		err := db.
+			Model(&models.EncounterRevision{}).
+			Where("id = ?", rev.ID).
+			Update("assessment", "Tampered").
+			Error
+		assert.Error(t, err, "Expected revisions to reject updates")
+
+		err = db.Delete(&models.EncounterRevision{}, rev.ID).Error
+		assert.Error(t, err, "Expected revisions to reject deletes")
+	})
+}
@@ -0,0 +1,289 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+var testICD10 = dictionaries.NewICD10Dictionary([]models.ICD10Code{
+	{Code: "I10", Description: "Essential (primary) hypertension"},
+	{Code: "R51.9", Description: "Headache, unspecified"},
+})
+
+func newTestEncounterService(
+	repo *mocks.EncounterRepository,
+	apptRepo *mocks.AppointmentRepository,
+	userClient *mocks.UserClient,
+) services.EncounterService {
+	careTeamRepo := new(mocks.CareTeamRepository)
+	careTeamRepo.On("IsMember", mock.Anything, mock.Anything).
+		Return(false, nil).
+		Maybe()
+
+	return services.NewEncounterService(
+		repo, apptRepo, newTestPatientAccess(careTeamRepo), userClient, testICD10,
+	)
+}
+
+func TestEncounterService_Create_ResolvesDiagnoses(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockApptRepo := new(mocks.AppointmentRepository)
+	service := newTestEncounterService(
+		mockRepo, mockApptRepo, new(mocks.UserClient),
+	)
+
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		Status: models.Booked,
+	}
+	mockApptRepo.On("FindByID", appt.ID).Return(appt, nil).Once()
+	mockRepo.
+		On("Create", mock.AnythingOfType("*models.Encounter"),
+			mock.AnythingOfType("*models.EncounterRevision")).
+		Return(nil).
+		Once()
+
+	enc, err := service.Create(appt.ID, appt.DoctorID, dtos.EncounterNotesRequest{
+		Assessment: "Hypertension",
+		Diagnoses: []dtos.DiagnosisRequest{{Code: "i10", Primary: true}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, appt.PatientID, enc.PatientID)
+
+	rev := mockRepo.Calls[0].Arguments.Get(1).(*models.EncounterRevision)
+	require.Len(t, rev.Diagnoses, 1)
+	assert.Equal(t, "I10", rev.Diagnoses[0].Code)
+	assert.Equal(t, "Essential (primary) hypertension", rev.Diagnoses[0].Description)
+	assert.Empty(t, rev.Reason)
+}
+
+func TestEncounterService_Create_UnknownDiagnosis(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockApptRepo := new(mocks.AppointmentRepository)
+	service := newTestEncounterService(
+		mockRepo, mockApptRepo, new(mocks.UserClient),
+	)
+
+	appt := &models.Appointment{
+		ID: uuid.New(),
+		DoctorID: uuid.New(),
+		Status: models.Booked,
+	}
+	mockApptRepo.On("FindByID", appt.ID).Return(appt, nil).Once()
+
+	_, err := service.Create(appt.ID, appt.DoctorID, dtos.EncounterNotesRequest{
+		Diagnoses: []dtos.DiagnosisRequest{{Code: "X99.999"}},
+	})
+
+	assert.ErrorIs(t, err, &igakuErrors.InvalidDiagnosisCodeError{})
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestEncounterService_Create_OtherDoctor(t *testing.T) {
+	mockApptRepo := new(mocks.AppointmentRepository)
+	service := newTestEncounterService(
+		new(mocks.EncounterRepository), mockApptRepo, new(mocks.UserClient),
+	)
+
+	appt := &models.Appointment{ID: uuid.New(), DoctorID: uuid.New()}
+	mockApptRepo.On("FindByID", appt.ID).Return(appt, nil).Once()
+
+	_, err := service.Create(appt.ID, uuid.New(), dtos.EncounterNotesRequest{})
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestEncounterService_Get_Access(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service := services.NewEncounterService(
+		mockRepo, new(mocks.AppointmentRepository),
+		newTestPatientAccess(mockCareTeamRepo), new(mocks.UserClient),
+		testICD10,
+	)
+
+	delegateID, careTeamID := uuid.New(), uuid.New()
+	enc := &models.Encounter{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		Delegations: []models.EncounterDelegation{{DoctorID: delegateID}},
+	}
+	mockRepo.On("FindByID", enc.ID).Return(enc, nil)
+	mockCareTeamRepo.On("IsMember", enc.PatientID, careTeamID).Return(true, nil)
+	mockCareTeamRepo.On("IsMember", enc.PatientID, mock.Anything).Return(false, nil)
+
+	_, err := service.Get(enc.ID, enc.PatientID, commonsModels.Patient)
+	assert.NoError(t, err)
+	for _, userID := range []uuid.UUID{enc.DoctorID, delegateID, careTeamID} {
+		_, err := service.Get(enc.ID, userID, commonsModels.Doctor)
+		assert.NoError(t, err)
+	}
+
+	_, err = service.Get(enc.ID, uuid.New(), commonsModels.Doctor)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	_, err = service.Get(enc.ID, uuid.New(), commonsModels.Patient)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestEncounterService_Get_EmergencyAccess(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockEmergencyRepo := new(mocks.EmergencyAccessRepository)
+	access := services.NewPatientAccess(
+		new(mocks.CareTeamRepository), new(mocks.ConsentRepository),
+		new(mocks.SlotRepository), mockEmergencyRepo,
+	)
+	service := services.NewEncounterService(
+		mockRepo, new(mocks.AppointmentRepository), access,
+		new(mocks.UserClient), testICD10,
+	)
+
+	doctorID := uuid.New()
+	enc := &models.Encounter{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+	}
+	other := &models.Encounter{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+	}
+	grant := newTestEmergencyAccess(enc.PatientID, doctorID)
+	mockRepo.On("FindByID", enc.ID).Return(enc, nil)
+	mockRepo.On("FindByID", other.ID).Return(other, nil)
+	mockEmergencyRepo.On("FindByID", grant.ID).Return(grant, nil)
+	mockEmergencyRepo.On("LogAccess", mock.MatchedBy(func(e *models.EmergencyAccessEvent) bool {
+		return e.AccessID == grant.ID && e.PatientID == enc.PatientID &&
+			e.DoctorID == doctorID && len(e.Categories) == 1 &&
+			e.Categories[0] == models.EncountersCategory
+	})).Return(nil).Once()
+
+	got, err := service.Get(enc.ID, grant.ID, models.EmergencyRole)
+	require.NoError(t, err)
+	assert.Equal(t, enc.ID, got.ID)
+
+	// The token does not extend to other patients.
+	_, err = service.Get(other.ID, grant.ID, models.EmergencyRole)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockEmergencyRepo.AssertExpectations(t)
+}
+
+func TestEncounterService_Get_DelegationDenied(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockConsentRepo := new(mocks.ConsentRepository)
+	access := services.NewPatientAccess(
+		new(mocks.CareTeamRepository), mockConsentRepo,
+		new(mocks.SlotRepository), new(mocks.EmergencyAccessRepository),
+	)
+	service := services.NewEncounterService(
+		mockRepo, new(mocks.AppointmentRepository), access,
+		new(mocks.UserClient), testICD10,
+	)
+
+	delegateID := uuid.New()
+	enc := &models.Encounter{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		Delegations: []models.EncounterDelegation{{DoctorID: delegateID}},
+	}
+	mockRepo.On("FindByID", enc.ID).Return(enc, nil)
+	mockConsentRepo.On("FindActive", enc.PatientID, mock.Anything).
+		Return([]models.Consent{{
+			PatientID: enc.PatientID,
+			GranteeType: models.DoctorGrantee,
+			GranteeID: delegateID,
+			Decision: models.Deny,
+			Categories: []models.ConsentCategory{models.EncountersCategory},
+		}}, nil)
+
+	_, err := service.Get(enc.ID, delegateID, commonsModels.Doctor)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestEncounterService_Amend_DelegateCannotAmend(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	service := newTestEncounterService(
+		mockRepo, new(mocks.AppointmentRepository), new(mocks.UserClient),
+	)
+
+	delegateID := uuid.New()
+	enc := &models.Encounter{
+		ID: uuid.New(),
+		DoctorID: uuid.New(),
+		Delegations: []models.EncounterDelegation{{DoctorID: delegateID}},
+	}
+	mockRepo.On("FindByID", enc.ID).Return(enc, nil)
+
+	_, err := service.Amend(enc.ID, delegateID, dtos.EncounterAmendmentRequest{
+		Reason: "Typo",
+	})
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "AppendRevision", mock.Anything)
+}
+
+func TestEncounterService_Amend_AppendsRevision(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	service := newTestEncounterService(
+		mockRepo, new(mocks.AppointmentRepository), new(mocks.UserClient),
+	)
+
+	enc := &models.Encounter{ID: uuid.New(), DoctorID: uuid.New()}
+	mockRepo.On("FindByID", enc.ID).Return(enc, nil)
+	mockRepo.
+		On("AppendRevision", mock.MatchedBy(func(rev *models.EncounterRevision) bool {
+			return rev.EncounterID == enc.ID &&
+				rev.AuthorID == enc.DoctorID &&
+				rev.Reason == "Corrected assessment"
+		})).
+		Return(nil).
+		Once()
+
+	_, err := service.Amend(enc.ID, enc.DoctorID, dtos.EncounterAmendmentRequest{
+		EncounterNotesRequest: dtos.EncounterNotesRequest{
+			Assessment: "Migraine",
+		},
+		Reason: "Corrected assessment",
+	})
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEncounterService_Delegate_RequiresDoctor(t *testing.T) {
+	mockRepo := new(mocks.EncounterRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := newTestEncounterService(
+		mockRepo, new(mocks.AppointmentRepository), mockUserClient,
+	)
+
+	enc := &models.Encounter{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+	}
+	other := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Patient}
+	mockRepo.On("FindByID", enc.ID).Return(enc, nil)
+	mockUserClient.On("FindByID", other.ID).Return(other, nil).Once()
+
+	_, err := service.Delegate(enc.ID, enc.PatientID, other.ID)
+
+	assert.ErrorIs(t, err, &igakuErrors.InvalidDelegateError{})
+	mockRepo.AssertNotCalled(t, "AddDelegation", mock.Anything)
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/fhir"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+)
+
+func setupFHIRRouter(
+	mockUserClient *mocks.UserClient,
+	mockOrgRepo *MockOrganizationRepository,
+	mockApptRepo *mocks.AppointmentRepository,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	fhirService := services.NewFHIRService(
+		mockUserClient, mockOrgRepo, mockApptRepo, new(mocks.CareTeamRepository),
+	)
+	fhirController := controllers.NewFHIRController(fhirService)
+
+	router := gin.Default()
+	fhirController.RegisterRoutes(router)
+	return router
+}
+
+func TestFHIRController_Metadata_NoToken(t *testing.T) {
+	router := setupFHIRRouter(
+		new(mocks.UserClient), new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "/visit/fhir/metadata", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), fhir.ContentType)
+
+	var statement fhir.CapabilityStatement
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statement))
+	assert.Equal(t, "CapabilityStatement", statement.ResourceType)
+	assert.Equal(t, fhir.Version, statement.FHIRVersion)
+}
+
+func TestFHIRController_Search_UnknownParameter(t *testing.T) {
+	mockOrgRepo := new(MockOrganizationRepository)
+	router := setupFHIRRouter(
+		new(mocks.UserClient), mockOrgRepo, new(mocks.AppointmentRepository),
+	)
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/visit/fhir/Organization?colour=blue", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var outcome fhir.OperationOutcome
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &outcome))
+	assert.Equal(t, "invalid", outcome.Issue[0].Code)
+	mockOrgRepo.AssertNotCalled(t, "Search", mock.Anything)
+}
+
+func TestFHIRController_Search_UnsupportedType(t *testing.T) {
+	router := setupFHIRRouter(
+		new(mocks.UserClient), new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "/visit/fhir/Observation", nil)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "not-supported")
+}
+
+func TestFHIRController_Search_PagingLinks(t *testing.T) {
+	mockOrgRepo := new(MockOrganizationRepository)
+	router := setupFHIRRouter(
+		new(mocks.UserClient), mockOrgRepo, new(mocks.AppointmentRepository),
+	)
+
+	org := models.Organization{ID: uuid.New(), Name: "McLean Hospital"}
+	mockOrgRepo.On("Search", (*uuid.UUID)(nil), "", 2, 2).
+		Return([]models.Organization{org}, int64(5), nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/visit/fhir/Organization?_count=2&_offset=2", nil,
+	)
+	req.Host = "localhost"
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var bundle struct {
+		Total	int64			`json:"total"`
+		Link	[]fhir.BundleLink	`json:"link"`
+		Entry	[]map[string]any	`json:"entry"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle))
+	assert.EqualValues(t, 5, bundle.Total)
+	assert.Len(t, bundle.Entry, 1)
+
+	links := map[string]string{}
+	for _, l := range bundle.Link {
+		links[l.Relation] = l.URL
+	}
+	base := "http://localhost/visit/fhir/Organization?"
+	assert.Equal(t, base+"_count=2&_offset=0", links["previous"])
+	assert.Equal(t, base+"_count=2&_offset=4", links["next"])
+	assert.Equal(t, base+"_count=2&_offset=4", links["last"])
+	mockOrgRepo.AssertExpectations(t)
+}
+
+func TestFHIRController_Read_InvalidID(t *testing.T) {
+	router := setupFHIRRouter(
+		new(mocks.UserClient), new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "/visit/fhir/Patient/42", nil)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Admin))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "not-found")
+}
+
+func TestFHIRController_Validate(t *testing.T) {
+	router := setupFHIRRouter(
+		new(mocks.UserClient), new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	body := []byte(`{"resourceType": "Organization", "active": "yes"}`)
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/fhir/Organization/$validate",
+		bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var outcome fhir.OperationOutcome
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &outcome))
+	require.Len(t, outcome.Issue, 1)
+	assert.Equal(t, "error", outcome.Issue[0].Severity)
+}
@@ -0,0 +1,294 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"net/url"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/fhir"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func newTestFHIRService(
+	userClient *mocks.UserClient,
+	orgRepo *MockOrganizationRepository,
+	apptRepo *mocks.AppointmentRepository,
+) services.FHIRService {
+	return services.NewFHIRService(
+		userClient, orgRepo, apptRepo, new(mocks.CareTeamRepository),
+	)
+}
+
+func parseTestSearch(t *testing.T, resourceType, query string) *fhir.Search {
+	t.Helper()
+
+	values, err := url.ParseQuery(query)
+	require.NoError(t, err)
+	search, err := fhir.ParseSearch(resourceType, values)
+	require.NoError(t, err)
+	return search
+}
+
+func TestFHIRService_SearchPatients_PatientOnlySelf(t *testing.T) {
+	mockUserClient := new(mocks.UserClient)
+	service := newTestFHIRService(
+		mockUserClient, new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	patient := commonsModels.User{
+		ID: uuid.New(), Username: "jdoe", Role: commonsModels.Patient,
+	}
+	mockUserClient.On("Search", commonsDtos.UserSearchRequest{
+		IDs: []uuid.UUID{patient.ID},
+		Role: commonsModels.Patient,
+		UsernamePrefix: "jd",
+		Limit: fhir.DefaultCount,
+	}).
+		Return(&commonsDtos.UserSearchResult{
+			Users: []commonsModels.User{patient},
+			Total: 1,
+		}, nil).
+		Once()
+
+	result, err := service.Search(
+		"Patient", parseTestSearch(t, "Patient", "name=jd"),
+		patient.ID, commonsModels.Patient,
+	)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, result.Total)
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "Patient/"+patient.ID.String(), result.Matches[0].Reference())
+	mockUserClient.AssertExpectations(t)
+}
+
+func TestFHIRService_SearchPatients_DoctorOwnPatientsOnly(t *testing.T) {
+	mockUserClient := new(mocks.UserClient)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service := services.NewFHIRService(
+		mockUserClient, new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository), mockCareTeamRepo,
+	)
+
+	doctorID, own, other := uuid.New(), uuid.New(), uuid.New()
+	mockCareTeamRepo.On("FindPatientIDs", doctorID).Return([]uuid.UUID{own}, nil)
+	mockUserClient.On("Search", commonsDtos.UserSearchRequest{
+		IDs: []uuid.UUID{own},
+		Role: commonsModels.Patient,
+		Limit: fhir.DefaultCount,
+	}).
+		Return(&commonsDtos.UserSearchResult{
+			Users: []commonsModels.User{{ID: own, Role: commonsModels.Patient}},
+			Total: 1,
+		}, nil).
+		Twice()
+
+	result, err := service.Search(
+		"Patient", parseTestSearch(t, "Patient", ""), doctorID, commonsModels.Doctor,
+	)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, result.Total)
+
+	result, err = service.Search(
+		"Patient",
+		parseTestSearch(t, "Patient", "_id="+own.String()+","+other.String()),
+		doctorID, commonsModels.Doctor,
+	)
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+
+	result, err = service.Search(
+		"Patient", parseTestSearch(t, "Patient", "_id="+other.String()),
+		doctorID, commonsModels.Doctor,
+	)
+	require.NoError(t, err)
+	assert.Empty(t, result.Matches)
+	mockUserClient.AssertExpectations(t)
+}
+
+func TestFHIRService_SearchPatients_PatientOtherID(t *testing.T) {
+	mockUserClient := new(mocks.UserClient)
+	service := newTestFHIRService(
+		mockUserClient, new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	result, err := service.Search(
+		"Patient", parseTestSearch(t, "Patient", "_id="+uuid.NewString()),
+		uuid.New(), commonsModels.Patient,
+	)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Matches)
+	mockUserClient.AssertNotCalled(t, "Search", mock.Anything)
+}
+
+func TestFHIRService_Search_EmergencyAccessFindsNothing(t *testing.T) {
+	mockUserClient := new(mocks.UserClient)
+	mockApptRepo := new(mocks.AppointmentRepository)
+	service := newTestFHIRService(
+		mockUserClient, new(MockOrganizationRepository), mockApptRepo,
+	)
+
+	for _, resourceType := range []string{"Patient", "Appointment"} {
+		result, err := service.Search(
+			resourceType, parseTestSearch(t, resourceType, ""),
+			uuid.New(), models.EmergencyRole,
+		)
+		require.NoError(t, err)
+		assert.Empty(t, result.Matches)
+	}
+	mockUserClient.AssertNotCalled(t, "Search", mock.Anything)
+	mockApptRepo.AssertNotCalled(t, "Search", mock.Anything)
+}
+
+func TestFHIRService_SearchAppointments_DoctorOwnOnly(t *testing.T) {
+	mockApptRepo := new(mocks.AppointmentRepository)
+	service := newTestFHIRService(
+		new(mocks.UserClient), new(MockOrganizationRepository), mockApptRepo,
+	)
+
+	doctorID := uuid.New()
+	patientID := uuid.New()
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockApptRepo.On("Search", mock.MatchedBy(func(f dtos.AppointmentFilter) bool {
+		return f.DoctorID != nil && *f.DoctorID == doctorID &&
+			f.PatientID != nil && *f.PatientID == patientID &&
+			f.Status != nil && *f.Status == models.Booked &&
+			f.StartsFrom != nil && f.StartsFrom.Equal(from)
+	})).
+		Return([]models.Appointment{{
+			ID: uuid.New(),
+			PatientID: patientID,
+			DoctorID: doctorID,
+			OrganizationID: uuid.New(),
+			Status: models.Booked,
+			StartsAt: from.Add(10 * time.Hour),
+		}}, int64(1), nil).
+		Once()
+
+	search := parseTestSearch(
+		t, "Appointment",
+		"patient=Patient/"+patientID.String()+"&status=booked&date=ge2025-05-01",
+	)
+	result, err := service.Search("Appointment", search, doctorID, commonsModels.Doctor)
+	require.NoError(t, err)
+
+	require.Len(t, result.Matches, 1)
+	mockApptRepo.AssertExpectations(t)
+}
+
+func TestFHIRService_SearchAppointments_OtherPractitioner(t *testing.T) {
+	mockApptRepo := new(mocks.AppointmentRepository)
+	service := newTestFHIRService(
+		new(mocks.UserClient), new(MockOrganizationRepository), mockApptRepo,
+	)
+
+	search := parseTestSearch(t, "Appointment", "practitioner="+uuid.NewString())
+	result, err := service.Search(
+		"Appointment", search, uuid.New(), commonsModels.Doctor,
+	)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Matches)
+	mockApptRepo.AssertNotCalled(t, "Search", mock.Anything)
+}
+
+func TestFHIRService_SearchOrganizations_RevIncludeLocations(t *testing.T) {
+	mockOrgRepo := new(MockOrganizationRepository)
+	service := newTestFHIRService(
+		new(mocks.UserClient), mockOrgRepo, new(mocks.AppointmentRepository),
+	)
+
+	org := models.Organization{ID: uuid.New(), Name: "Massachusetts General Hospital"}
+	mockOrgRepo.On("Search", (*uuid.UUID)(nil), "general", 0, fhir.DefaultCount).
+		Return([]models.Organization{org}, int64(1), nil).
+		Once()
+
+	search := parseTestSearch(
+		t, "Organization", "name=general&_revinclude=Location:organization",
+	)
+	result, err := service.Search(
+		"Organization", search, uuid.New(), commonsModels.Patient,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, result.Matches, 1)
+	require.Len(t, result.Included, 1)
+	assert.Equal(t, "Location/"+org.ID.String(), result.Included[0].Reference())
+}
+
+func TestFHIRService_Read_NotFound(t *testing.T) {
+	mockUserClient := new(mocks.UserClient)
+	service := newTestFHIRService(
+		mockUserClient, new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	mockUserClient.On("Search", mock.Anything).
+		Return(&commonsDtos.UserSearchResult{}, nil).
+		Once()
+
+	_, err := service.Read(
+		"Practitioner", uuid.New(), uuid.New(), commonsModels.Patient,
+	)
+
+	assert.ErrorIs(t, err, &commonsErrors.UserNotFoundError{})
+}
+
+func TestFHIRService_SearchAppointments_InvalidDate(t *testing.T) {
+	service := newTestFHIRService(
+		new(mocks.UserClient), new(MockOrganizationRepository),
+		new(mocks.AppointmentRepository),
+	)
+
+	search := parseTestSearch(t, "Appointment", "date=yesterday")
+	_, err := service.Search("Appointment", search, uuid.New(), commonsModels.Admin)
+
+	var invalid *igakuErrors.InvalidSearchParameterError
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestFHIRMapping_LocationAddressComponents(t *testing.T) {
+	org := models.Organization{
+		ID: uuid.New(),
+		Name: "Massachusetts General Hospital",
+		Location: commonsDtos.Location{
+			ID: 117853077,
+			Lat: "42.3628605",
+			Lon: "-71.0687530",
+			Name: "Massachusetts General Hospital, 55, Fruit Street, Boston",
+			Address: commonsDtos.Address{
+				HouseNumber: "55",
+				Street: "Fruit Street",
+				City: "Boston",
+				Postcode: "02114",
+				County: "Suffolk County",
+				Country: "United States",
+			},
+		},
+	}
+
+	location := fhir.NewLocation(&org)
+
+	require.NotNil(t, location.Address)
+	assert.Equal(t, []string{"55 Fruit Street"}, location.Address.Line)
+	assert.Equal(t, "Boston", location.Address.City)
+	assert.Equal(t, "Suffolk County", location.Address.District)
+	assert.Equal(t, "02114", location.Address.PostalCode)
+	assert.Equal(t, "United States", location.Address.Country)
+	assert.Equal(t, org.Location.Name, location.Address.Text)
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"testing"
+
+	"igaku/visit-service/fhir"
+)
+
+func issueCodes(outcome *fhir.OperationOutcome) []string {
+	codes := make([]string, 0, len(outcome.Issue))
+	for _, issue := range outcome.Issue {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestFHIRValidate_ValidPatient(t *testing.T) {
+	outcome := fhir.Validate([]byte(`{
+		"resourceType": "Patient",
+		"id": "0b6f13da-efb9-4221-9e89-e2729ae90030",
+		"active": true,
+		"name": [{"use": "usual", "text": "jdoe"}],
+		"gender": "unknown",
+		"birthDate": "1990-04-12"
+	}`), "Patient")
+
+	assert.Equal(t, []string{"informational"}, issueCodes(outcome))
+}
+
+func TestFHIRValidate_UnknownElement(t *testing.T) {
+	outcome := fhir.Validate(
+		[]byte(`{"resourceType": "Patient", "nickname": "jd"}`), "Patient",
+	)
+
+	assert.Len(t, outcome.Issue, 1)
+	assert.Equal(t, "error", outcome.Issue[0].Severity)
+	assert.Equal(t, []string{"Patient.nickname"}, outcome.Issue[0].Expression)
+}
+
+func TestFHIRValidate_InvalidCodeAndDate(t *testing.T) {
+	outcome := fhir.Validate([]byte(`{
+		"resourceType": "Patient",
+		"gender": "robot",
+		"birthDate": "1990-13-01"
+	}`), "Patient")
+
+	assert.Len(t, outcome.Issue, 2)
+	for _, issue := range outcome.Issue {
+		assert.Equal(t, "error", issue.Severity)
+	}
+}
+
+func TestFHIRValidate_TypeMismatch(t *testing.T) {
+	outcome := fhir.Validate([]byte(`{"resourceType": "Patient"}`), "Location")
+
+	assert.Equal(t, []string{"invalid"}, issueCodes(outcome))
+}
+
+func TestFHIRValidate_AppointmentWithoutEnd(t *testing.T) {
+	outcome := fhir.Validate([]byte(`{
+		"resourceType": "Appointment",
+		"status": "booked",
+		"start": "2025-05-01T10:00:00+02:00",
+		"participant": [{"status": "accepted", "actor": {"reference": "Patient/1"}}]
+	}`), "Appointment")
+
+	assert.Len(t, outcome.Issue, 1)
+	assert.Equal(t, "error", outcome.Issue[0].Severity)
+}
+
+func TestFHIRValidate_ChoiceConflict(t *testing.T) {
+	outcome := fhir.Validate([]byte(`{
+		"resourceType": "Patient",
+		"deceasedBoolean": false,
+		"deceasedDateTime": "2020-01-01"
+	}`), "Patient")
+
+	assert.Len(t, outcome.Issue, 1)
+	assert.Equal(t, "error", outcome.Issue[0].Severity)
+}
+
+func TestFHIRValidate_InvalidJSON(t *testing.T) {
+	outcome := fhir.Validate([]byte(`{"resourceType": `), "Patient")
+
+	assert.Equal(t, []string{"structure"}, issueCodes(outcome))
+}
@@ -30,6 +30,7 @@ var mockNominatim *httptest.Server
 
 var fireDoriLoc = dtos.Location{
 	ID:   153108796,
+	OsmType: dtos.OsmWay,
 	Lat:  "35.6656280",
 	Lon:  "139.7016220",
 	Name: "ファイアー通り, 神南一丁目, 神南, 渋谷区, 東京都, 150-0041, 日本",
@@ -46,36 +47,53 @@ func TestMain(m *testing.M) {
 			if lat == fireDoriLoc.Lat && lon == fireDoriLoc.Lon {
 				w.Header().Set("Content-Type", "application/json")
 				json.NewEncoder(w).Encode(fireDoriLoc)
-			} else if lat == "0.0" && lon == "0.0" {
+			} else if lat == "0.0000000" && lon == "0.0000000" {
 				time.Sleep(4 * time.Second)
 				w.WriteHeader(http.StatusOK)
-			} else if lat == "foo" && lon == "bar" {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusBadRequest)
-				w.Write([]byte("{\"error\":{\"code\":400,\"message\":\"Parameter 'lon' must be a number.\"}}"))
-			} else if lat == "90" && lon == "90" {
+			} else if lat == "90.0000000" && lon == "90.0000000" {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte("{\"error\":\"Unable to geocode\"}"))
 			} else {
 				http.NotFound(w, r)
 			}
+		} else if path == "/search" {
+			q := r.URL.Query().Get("q")
+
+			if q == "Fire-dori" {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode([]dtos.Location{
+					fireDoriLoc,
+				})
+			} else {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte("[]"))
+			}
 		} else if path == "/lookup" {
 			ids := r.URL.Query().Get("osm_ids")
 
-			if ids == fmt.Sprintf("N%d,W%d", fireDoriLoc.ID, fireDoriLoc.ID) {
+			if ids == fmt.Sprintf("W%d", fireDoriLoc.ID) ||
+				ids == fmt.Sprintf("N%d,W%d,R%d", fireDoriLoc.ID, fireDoriLoc.ID, fireDoriLoc.ID) {
 				w.Header().Set("Content-Type", "application/json")
 				json.NewEncoder(w).Encode([]dtos.Location{
 					fireDoriLoc,
 				})
-			} else if ids == "N408,W408" {
+			} else if ids == "N408,W408,R408" {
 				time.Sleep(4 * time.Second)
 				w.WriteHeader(http.StatusOK)
-			} else if ids == "N0,W0" {
+			} else if ids == "N0,W0,R0" {
 				w.Header().Set("Content-Type", "application/json")
 				w.Write([]byte("[]"))
 			} else {
 				http.NotFound(w, r)
 			}
+		} else if strings.HasPrefix(path, "/route/v1/driving/") {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(path, fmt.Sprintf("%s,%s", fireDoriLoc.Lon, fireDoriLoc.Lat)) {
+				w.Write([]byte(`{"code":"Ok","routes":[{"distance":1204.5,"duration":183.2}]}`))
+			} else {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":"NoRoute","message":"Impossible route between points"}`))
+			}
 		} else {
 			http.NotFound(w, r)
 		}
@@ -125,8 +143,8 @@ func TestGeoClient_ReverseGeocode_Success(t *testing.T) {
 	require.NoError(t, err)
 	defer geoClient.Shutdown()
 
-	lat := fireDoriLoc.Lat
-	lon := fireDoriLoc.Lon
+	lat := 35.665628
+	lon := 139.701622
 	expectedLocation := &fireDoriLoc
 	location, err := geoClient.ReverseGeocode(lat, lon)
 	require.NoError(t, err)
@@ -141,8 +159,8 @@ func TestGeoClient_ReverseGeocode_Timeout(t *testing.T) {
 	require.NoError(t, err)
 	defer geoClient.Shutdown()
 
-	lat := "0.0"
-	lon := "0.0"
+	lat := 0.0
+	lon := 0.0
 
 	_, err = geoClient.ReverseGeocode(lat, lon)
 	require.Error(t, err)
@@ -154,8 +172,8 @@ func TestGeoClient_ReverseGeocode_NonGeocodableLocation(t *testing.T) {
 	require.NoError(t, err)
 	defer geoClient.Shutdown()
 
-	lat := "90"
-	lon := "90"
+	lat := 90.0
+	lon := 90.0
 
 	_, err = geoClient.ReverseGeocode(lat, lon)
 	require.Error(t, err)
@@ -167,12 +185,12 @@ func TestGeoClient_ReverseGeocode_InvalidParams(t *testing.T) {
 	require.NoError(t, err)
 	defer geoClient.Shutdown()
 
-	lat := "foo"
-	lon := "bar"
+	lat := 91.0
+	lon := 0.0
 
 	_, err = geoClient.ReverseGeocode(lat, lon)
 	require.Error(t, err)
-	assert.Contains(t, strings.ToLower(err.Error()), "must be a number")
+	assert.Contains(t, strings.ToLower(err.Error()), "latitude must be between")
 }
 
 func TestGeoClient_LocationLookup_Success(t *testing.T) {
@@ -182,7 +200,7 @@ func TestGeoClient_LocationLookup_Success(t *testing.T) {
 
 	id := fireDoriLoc.ID
 	expectedLocation := &fireDoriLoc
-	location, err := geoClient.LookupLocation(int64(id))
+	location, err := geoClient.LookupLocation("", int64(id))
 	require.NoError(t, err)
 	assert.Equal(t, expectedLocation.ID, location.ID)
 	assert.Equal(t, expectedLocation.Lat, location.Lat)
@@ -197,7 +215,7 @@ func TestGeoClient_LocationLookup_Timeout(t *testing.T) {
 
 	id := 408
 
-	_, err = geoClient.LookupLocation(int64(id))
+	_, err = geoClient.LookupLocation("", int64(id))
 	require.Error(t, err)
 	assert.Contains(t, strings.ToLower(err.Error()), "request timed out")
 }
@@ -209,7 +227,84 @@ func TestGeoClient_LocationLookup_NoResult(t *testing.T) {
 
 	id := 0
 
-	ret, err := geoClient.LookupLocation(int64(id))
+	ret, err := geoClient.LookupLocation("", int64(id))
 	require.NoError(t, err)
 	assert.Nil(t, ret)
 }
+
+func TestGeoClient_Search_Success(t *testing.T) {
+	geoClient, err := clients.NewGeoClient(amqpURL)
+	require.NoError(t, err)
+	defer geoClient.Shutdown()
+
+	locations, err := geoClient.Search("Fire-dori")
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	assert.Equal(t, fireDoriLoc.ID, locations[0].ID)
+	assert.Equal(t, fireDoriLoc.Lat, locations[0].Lat)
+	assert.Equal(t, fireDoriLoc.Lon, locations[0].Lon)
+}
+
+func TestGeoClient_Search_NoResult(t *testing.T) {
+	geoClient, err := clients.NewGeoClient(amqpURL)
+	require.NoError(t, err)
+	defer geoClient.Shutdown()
+
+	locations, err := geoClient.Search("Nowhere")
+	require.NoError(t, err)
+	assert.Empty(t, locations)
+}
+
+func TestGeoClient_LocationLookup_Typed(t *testing.T) {
+	geoClient, err := clients.NewGeoClient(amqpURL)
+	require.NoError(t, err)
+	defer geoClient.Shutdown()
+
+	location, err := geoClient.LookupLocation(dtos.OsmWay, fireDoriLoc.ID)
+	require.NoError(t, err)
+	require.NotNil(t, location)
+	assert.Equal(t, fireDoriLoc.ID, location.ID)
+	assert.Equal(t, dtos.OsmWay, location.OsmType)
+}
+
+func TestGeoClient_EstimateRoute_Success(t *testing.T) {
+	geoClient, err := clients.NewGeoClient(amqpURL)
+	require.NoError(t, err)
+	defer geoClient.Shutdown()
+
+	estimates, err := geoClient.EstimateRoute(dtos.RouteRequest{
+		FromLat: 35.6580339, FromLon: 139.7016358,
+		ToLat: 35.665628, ToLon: 139.701622,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []dtos.RouteEstimate{{
+		Mode: dtos.Driving, DistanceMeters: 1204.5, DurationSeconds: 183.2,
+	}}, estimates)
+}
+
+func TestGeoClient_EstimateRoute_NoRoute(t *testing.T) {
+	geoClient, err := clients.NewGeoClient(amqpURL)
+	require.NoError(t, err)
+	defer geoClient.Shutdown()
+
+	estimates, err := geoClient.EstimateRoute(dtos.RouteRequest{
+		FromLat: 35.6580339, FromLon: 139.7016358,
+		ToLat: 21.3069444, ToLon: -157.8583333,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, estimates)
+}
+
+func TestGeoClient_EstimateRoute_UnsupportedMode(t *testing.T) {
+	geoClient, err := clients.NewGeoClient(amqpURL)
+	require.NoError(t, err)
+	defer geoClient.Shutdown()
+
+	_, err = geoClient.EstimateRoute(dtos.RouteRequest{
+		FromLat: 35.6580339, FromLon: 139.7016358,
+		ToLat: 35.665628, ToLon: 139.701622,
+		Modes: []dtos.TravelMode{dtos.Walking},
+	})
+	require.Error(t, err)
+	assert.Contains(t, strings.ToLower(err.Error()), "travel mode not supported")
+}
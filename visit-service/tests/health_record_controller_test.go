@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+)
+
+func setupHealthRecordRouter(mockRepo *mocks.HealthRecordRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	healthRecordService := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), new(mocks.PrescriptionRepository),
+	)
+	healthRecordController := controllers.NewHealthRecordController(
+		healthRecordService,
+	)
+
+	router := gin.Default()
+	healthRecordController.RegisterRoutes(router)
+	return router
+}
+
+func TestHealthRecordController_Create_UnknownKind(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	router := setupHealthRecordRouter(mockRepo)
+
+	patientID := uuid.New()
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/visit/patients/"+patientID.String()+"/records/surgeries",
+		bytes.NewReader([]byte(`{"substance": "latex"}`)),
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestHealthRecordController_Create_SelfReportedAllergy(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	router := setupHealthRecordRouter(mockRepo)
+
+	patientID := uuid.New()
+	mockRepo.On("Create", mock.AnythingOfType("*models.Allergy")).
+		Return(nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/visit/patients/"+patientID.String()+"/records/allergies",
+		bytes.NewReader([]byte(`{"substance": "latex", "severity": "moderate"}`)),
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"recorded_by":"`+patientID.String()+`"`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHealthRecordController_Summary_OtherPatient(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	router := setupHealthRecordRouter(mockRepo)
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/visit/patients/"+uuid.New().String()+"/summary", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "Summary", mock.Anything)
+}
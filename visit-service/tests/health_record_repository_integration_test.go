@@ -0,0 +1,82 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormHealthRecordRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+
+	t.Run("Summary_ReturnsSeededRecords", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormHealthRecordRepository(db)
+		summary, err := repo.Summary(patientID)
+		require.NoError(t, err)
+
+		require.Len(t, summary.Allergies, 1)
+		assert.Equal(t, "penicillin", summary.Allergies[0].Substance)
+		require.Len(t, summary.Conditions, 1)
+		assert.Equal(t, "I10", summary.Conditions[0].Code)
+		require.Len(t, summary.Immunizations, 1)
+		assert.Empty(t, summary.Medications)
+		require.Len(t, summary.Genotypes, 1)
+		assert.Equal(t, "CYP2C19", summary.Genotypes[0].Gene)
+	})
+
+	t.Run("CreateSaveDelete", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormHealthRecordRepository(db)
+		med := &models.MedicationStatement{
+			HealthRecord: models.HealthRecord{
+				ID: uuid.New(),
+				PatientID: patientID,
+				RecordedBy: doctorID,
+				RecordedAt: time.Now(),
+			},
+			DrugName: "Metformin",
+		}
+		require.NoError(t, repo.Create(med))
+
+		med.Dosage = "500 mg twice daily"
+		require.NoError(t, repo.Save(med))
+
+		found, err := repo.FindByID(models.MedicationRecord, med.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "500 mg twice daily", found.(*models.MedicationStatement).Dosage)
+
+		records, err := repo.FindByPatientID(models.MedicationRecord, patientID)
+		require.NoError(t, err)
+		assert.Len(t, records, 1)
+
+		require.NoError(t, repo.Delete(found))
+		_, err = repo.FindByID(models.MedicationRecord, med.ID)
+		assert.ErrorIs(t, err, &igakuErrors.HealthRecordNotFoundError{})
+	})
+}
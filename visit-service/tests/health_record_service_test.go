@@ -0,0 +1,238 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func newTestHealthRecordService(
+	repo *mocks.HealthRecordRepository,
+	careTeamRepo *mocks.CareTeamRepository,
+	encounterRepo *mocks.EncounterRepository,
+	prescriptionRepo *mocks.PrescriptionRepository,
+) services.HealthRecordService {
+	return services.NewHealthRecordService(
+		repo, newTestPatientAccess(careTeamRepo), encounterRepo, prescriptionRepo,
+		testICD10,
+	)
+}
+
+func TestHealthRecordService_Create_DoctorWithEncounter(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	mockEncounterRepo := new(mocks.EncounterRepository)
+	service := newTestHealthRecordService(
+		mockRepo, mockCareTeamRepo, mockEncounterRepo,
+		new(mocks.PrescriptionRepository),
+	)
+
+	patientID := uuid.New()
+	doctorID := uuid.New()
+	enc := &models.Encounter{ID: uuid.New(), PatientID: patientID, DoctorID: doctorID}
+	mockCareTeamRepo.On("IsMember", patientID, doctorID).Return(true, nil).Once()
+	mockEncounterRepo.On("FindByID", enc.ID).Return(enc, nil).Once()
+	mockRepo.On("Create", mock.AnythingOfType("*models.ChronicCondition")).
+		Return(nil).
+		Once()
+
+	req := &dtos.ConditionRequest{
+		RecordSource: dtos.RecordSource{EncounterID: &enc.ID},
+		Code: "i10",
+		Status: models.ConditionActive,
+	}
+	record, err := service.Create(
+		models.ConditionRecord, patientID, doctorID, commonsModels.Doctor, req,
+	)
+	require.NoError(t, err)
+
+	condition := record.(*models.ChronicCondition)
+	assert.Equal(t, "I10", condition.Code)
+	assert.Equal(t, "Essential (primary) hypertension", condition.Description)
+	assert.Equal(t, patientID, condition.PatientID)
+	assert.Equal(t, doctorID, condition.RecordedBy)
+	assert.Equal(t, &enc.ID, condition.EncounterID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHealthRecordService_Create_DoctorOutsideCareTeam(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service := newTestHealthRecordService(
+		mockRepo, mockCareTeamRepo, new(mocks.EncounterRepository),
+		new(mocks.PrescriptionRepository),
+	)
+
+	patientID := uuid.New()
+	doctorID := uuid.New()
+	mockCareTeamRepo.On("IsMember", patientID, doctorID).Return(false, nil).Once()
+
+	req := &dtos.AllergyRequest{Substance: "latex", Severity: models.Moderate}
+	_, err := service.Create(
+		models.AllergyRecord, patientID, doctorID, commonsModels.Doctor, req,
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestHealthRecordService_Create_EmergencyAccessIsReadOnly(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository), new(mocks.EncounterRepository),
+		new(mocks.PrescriptionRepository),
+	)
+
+	req := &dtos.AllergyRequest{Substance: "latex", Severity: models.Moderate}
+	_, err := service.Create(
+		models.AllergyRecord, uuid.New(), uuid.New(), models.EmergencyRole, req,
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestHealthRecordService_Create_PatientCannotCiteEncounter(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), new(mocks.PrescriptionRepository),
+	)
+
+	patientID := uuid.New()
+	encounterID := uuid.New()
+	req := &dtos.AllergyRequest{
+		RecordSource: dtos.RecordSource{EncounterID: &encounterID},
+		Substance: "latex",
+		Severity: models.Moderate,
+	}
+	_, err := service.Create(
+		models.AllergyRecord, patientID, patientID, commonsModels.Patient, req,
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestHealthRecordService_Update_PatientRecordedByDoctor(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), new(mocks.PrescriptionRepository),
+	)
+
+	patientID := uuid.New()
+	allergy := &models.Allergy{
+		HealthRecord: models.HealthRecord{
+			ID: uuid.New(),
+			PatientID: patientID,
+			RecordedBy: uuid.New(),
+		},
+		Substance: "penicillin",
+	}
+	mockRepo.On("FindByID", models.AllergyRecord, allergy.ID).
+		Return(allergy, nil).
+		Once()
+
+	req := &dtos.AllergyRequest{Substance: "latex", Severity: models.Minor}
+	_, err := service.Update(
+		models.AllergyRecord, allergy.ID, patientID, patientID,
+		commonsModels.Patient, req,
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestHealthRecordService_Update_KeepsProvenance(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), new(mocks.PrescriptionRepository),
+	)
+
+	patientID := uuid.New()
+	adminID := uuid.New()
+	recordedAt := time.Now().Add(-24 * time.Hour)
+	immunization := &models.Immunization{
+		HealthRecord: models.HealthRecord{
+			ID: uuid.New(),
+			PatientID: patientID,
+			RecordedBy: patientID,
+			RecordedAt: recordedAt,
+		},
+		Vaccine: "Td",
+	}
+	mockRepo.On("FindByID", models.ImmunizationRecord, immunization.ID).
+		Return(immunization, nil).
+		Once()
+	mockRepo.On("Save", immunization).Return(nil).Once()
+
+	req := &dtos.ImmunizationRequest{
+		Vaccine: "Tetanus, diphtheria (Td)",
+		DoseNumber: 2,
+		AdministeredOn: time.Now().Add(-48 * time.Hour),
+	}
+	record, err := service.Update(
+		models.ImmunizationRecord, immunization.ID, patientID, adminID,
+		commonsModels.Admin, req,
+	)
+	require.NoError(t, err)
+
+	updated := record.(*models.Immunization)
+	assert.Equal(t, "Tetanus, diphtheria (Td)", updated.Vaccine)
+	assert.Equal(t, patientID, updated.RecordedBy)
+	assert.Equal(t, recordedAt, updated.RecordedAt)
+	assert.Equal(t, &adminID, updated.UpdatedBy)
+	assert.NotNil(t, updated.UpdatedAt)
+}
+
+func TestHealthRecordService_Delete_OtherPatient(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), new(mocks.PrescriptionRepository),
+	)
+
+	err := service.Delete(
+		models.AllergyRecord, uuid.New(), uuid.New(), uuid.New(),
+		commonsModels.Patient,
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestHealthRecordService_Summary_IncludesActivePrescriptions(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	mockPrescriptionRepo := new(mocks.PrescriptionRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), mockPrescriptionRepo,
+	)
+
+	patientID := uuid.New()
+	mockRepo.On("Summary", patientID).
+		Return(&models.PatientSummary{PatientID: patientID}, nil).
+		Once()
+	mockPrescriptionRepo.On("FindByPatientID", patientID, mock.Anything).
+		Return([]models.Prescription{warfarin}, nil).
+		Once()
+
+	summary, err := service.Summary(patientID, patientID, commonsModels.Patient)
+	require.NoError(t, err)
+
+	require.Len(t, summary.ActivePrescriptions, 1)
+	assert.Equal(t, "Warfarin", summary.ActivePrescriptions[0].DrugName)
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"strings"
+	"testing"
+	"time"
+
+	"igaku/visit-service/hl7"
+)
+
+const testORU = "MSH|^~\\&|LAB|MGH|IGAKU|IGAKU|20250602081500+0200||ORU^R01|MSG00001|P|2.5.1\r" +
+	"PID|1||0b6f13da-efb9-4221-9e89-e2729ae90030^^^IGAKU||Doe^John\r" +
+	"OBR|1|||24331-1^Lipid panel^LN|||202506020700\r" +
+	"OBX|1|NM|2093-3^Cholesterol^LN||212|mg/dL|<200|H|||F\r" +
+	"OBX|2|NM|GLU^Glucose^L^2345-7^Glucose^LN||5.1|mmol/L|3.9-5.5|N|||F|||20250602073000\r" +
+	"OBX|3|ST|8251-1^Comment^LN||Fasting \\T\\ rested||||||F\r"
+
+func TestHL7_ParseORU(t *testing.T) {
+	loc := time.FixedZone("CEST", 2*60*60)
+
+	messages, err := hl7.Parse([]byte(testORU + "\n" + strings.ReplaceAll(testORU, "MSG00001", "MSG00002")))
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "ORU^R01", messages[0].Type())
+	assert.Equal(t, "MSG00002", messages[1].ControlID())
+
+	oru, err := hl7.ParseORU(&messages[0], loc)
+	require.NoError(t, err)
+	assert.Equal(t, "0b6f13da-efb9-4221-9e89-e2729ae90030", oru.PatientID)
+	require.Len(t, oru.Observations, 3)
+
+	cholesterol := oru.Observations[0]
+	assert.Equal(t, "2093-3", cholesterol.Code)
+	assert.Equal(t, "212", cholesterol.Value)
+	assert.Equal(t, "<200", cholesterol.ReferenceRange)
+	assert.Equal(t, 4, cholesterol.Line)
+	assert.True(
+		t, cholesterol.ObservedAt.Equal(time.Date(2025, 6, 2, 7, 0, 0, 0, loc)),
+		"Expected the order time to be used",
+	)
+
+	glucose := oru.Observations[1]
+	assert.Equal(t, "2345-7", glucose.Code, "Expected the alternate LOINC identifier")
+	assert.Equal(t, hl7.LOINCSystem, glucose.CodingSystem)
+	assert.True(t, glucose.ObservedAt.Equal(time.Date(2025, 6, 2, 7, 30, 0, 0, loc)))
+
+	assert.Equal(t, "Fasting & rested", oru.Observations[2].Value)
+}
+
+func TestHL7_ParseORU_WrongType(t *testing.T) {
+	messages, err := hl7.Parse([]byte(strings.Replace(testORU, "ORU^R01", "ADT^A01", 1)))
+	require.NoError(t, err)
+
+	_, err = hl7.ParseORU(&messages[0], time.UTC)
+	assert.Error(t, err)
+}
+
+func TestHL7_Parse_MissingHeader(t *testing.T) {
+	_, err := hl7.Parse([]byte("PID|1||42\r"))
+	assert.Error(t, err)
+}
+
+func TestHL7_ParseTime(t *testing.T) {
+	ts, err := hl7.ParseTime("20250602081530.25-0500", time.UTC)
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(time.Date(2025, 6, 2, 13, 15, 30, 250000000, time.UTC)))
+
+	ts, err = hl7.ParseTime("20250602", time.UTC)
+	require.NoError(t, err)
+	assert.True(t, ts.Equal(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)))
+
+	_, err = hl7.ParseTime("2025060", time.UTC)
+	assert.Error(t, err)
+}
+
+func TestHL7_ParseRange(t *testing.T) {
+	low, high, ok := hl7.ParseRange("3.5 - 5.1")
+	require.True(t, ok)
+	assert.Equal(t, 3.5, *low)
+	assert.Equal(t, 5.1, *high)
+
+	low, high, ok = hl7.ParseRange("-2-2")
+	require.True(t, ok)
+	assert.Equal(t, -2.0, *low)
+	assert.Equal(t, 2.0, *high)
+
+	low, high, ok = hl7.ParseRange(">=1.0")
+	require.True(t, ok)
+	assert.Equal(t, 1.0, *low)
+	assert.Nil(t, high)
+
+	_, _, ok = hl7.ParseRange("normal")
+	assert.False(t, ok)
+}
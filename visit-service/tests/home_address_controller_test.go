@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsDtos "igaku/commons/dtos"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func setupHomeAddressRouter(
+	mockRepo *mocks.HomeAddressRepository, mockGeoClient *mocks.GeoClient,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	service := services.NewHomeAddressService(mockRepo, mockGeoClient)
+	controller := controllers.NewHomeAddressController(service)
+
+	router := gin.Default()
+	controller.RegisterRoutes(router)
+	return router
+}
+
+func serveHomeAddress(
+	t *testing.T, router *gin.Engine, method string, body []byte,
+	userID uuid.UUID, role commonsModels.Role,
+) *httptest.ResponseRecorder {
+	req, err := http.NewRequest(
+		method, "/visit/home-address", bytes.NewBuffer(body),
+	)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", genToken(t, userID, role))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHomeAddressController_Set_Success(t *testing.T) {
+	mockRepo := new(mocks.HomeAddressRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	router := setupHomeAddressRouter(mockRepo, mockGeoClient)
+
+	patientID := uuid.New()
+	mockGeoClient.On("Search", "10 Brattle Street, Cambridge").
+		Return([]commonsDtos.Location{{
+			ID: 1, Lat: "42.3736158", Lon: "-71.1097335",
+			Name: "10, Brattle Street, Cambridge",
+		}}, nil).
+		Once()
+	mockRepo.On("Save", &models.HomeAddress{
+		PatientID: patientID,
+		Address: "10 Brattle Street, Cambridge",
+		DisplayName: "10, Brattle Street, Cambridge",
+		Lat: 42.3736158,
+		Lon: -71.1097335,
+	}).Return(nil).Once()
+
+	body, _ := json.Marshal(map[string]string{
+		"address": " 10 Brattle Street, Cambridge ",
+	})
+	rec := serveHomeAddress(
+		t, router, http.MethodPut, body, patientID, commonsModels.Patient,
+	)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var address models.HomeAddress
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &address))
+	assert.Equal(t, 42.3736158, address.Lat)
+	mockRepo.AssertExpectations(t)
+	mockGeoClient.AssertExpectations(t)
+}
+
+func TestHomeAddressController_Set_GeocodingErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		locations	[]commonsDtos.Location
+		err		error
+		status		int
+	}{
+		"NotFound": {[]commonsDtos.Location{}, nil, http.StatusBadRequest},
+		"Failed": {
+			nil, errors.New("Request timed out"),
+			http.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(mocks.HomeAddressRepository)
+			mockGeoClient := new(mocks.GeoClient)
+			router := setupHomeAddressRouter(mockRepo, mockGeoClient)
+
+			mockGeoClient.On("Search", "Nowhere").
+				Return(tc.locations, tc.err).
+				Once()
+
+			body, _ := json.Marshal(map[string]string{"address": "Nowhere"})
+			rec := serveHomeAddress(
+				t, router, http.MethodPut, body, uuid.New(),
+				commonsModels.Patient,
+			)
+
+			assert.Equal(t, tc.status, rec.Code)
+			mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+		})
+	}
+}
+
+func TestHomeAddressController_Set_InvalidPayload(t *testing.T) {
+	mockGeoClient := new(mocks.GeoClient)
+	router := setupHomeAddressRouter(
+		new(mocks.HomeAddressRepository), mockGeoClient,
+	)
+
+	rec := serveHomeAddress(
+		t, router, http.MethodPut, []byte(`{}`), uuid.New(),
+		commonsModels.Patient,
+	)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockGeoClient.AssertNotCalled(t, "Search", mock.Anything)
+}
+
+func TestHomeAddressController_Get_NotFound(t *testing.T) {
+	mockRepo := new(mocks.HomeAddressRepository)
+	router := setupHomeAddressRouter(mockRepo, new(mocks.GeoClient))
+
+	patientID := uuid.New()
+	mockRepo.On("FindByPatientID", patientID).Return(nil, nil).Once()
+
+	rec := serveHomeAddress(
+		t, router, http.MethodGet, nil, patientID, commonsModels.Patient,
+	)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHomeAddressController_Delete(t *testing.T) {
+	mockRepo := new(mocks.HomeAddressRepository)
+	router := setupHomeAddressRouter(mockRepo, new(mocks.GeoClient))
+
+	patientID := uuid.New()
+	mockRepo.On("Delete", patientID).Return(nil).Once()
+	mockRepo.On("Delete", mock.Anything).
+		Return(&igakuErrors.HomeAddressNotFoundError{}).
+		Once()
+
+	rec := serveHomeAddress(
+		t, router, http.MethodDelete, nil, patientID, commonsModels.Patient,
+	)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serveHomeAddress(
+		t, router, http.MethodDelete, nil, uuid.New(), commonsModels.Patient,
+	)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHomeAddressController_DoctorForbidden(t *testing.T) {
+	mockRepo := new(mocks.HomeAddressRepository)
+	router := setupHomeAddressRouter(mockRepo, new(mocks.GeoClient))
+
+	rec := serveHomeAddress(
+		t, router, http.MethodGet, nil, uuid.New(), commonsModels.Doctor,
+	)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockRepo.AssertNotCalled(t, "FindByPatientID", mock.Anything)
+}
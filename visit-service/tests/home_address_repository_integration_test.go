@@ -0,0 +1,77 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	igakuErrors "igaku/visit-service/errors"
+	testUtils "igaku/commons/utils"
+)
+
+func TestGormHomeAddressRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+
+	t.Run("Save_Overwrites", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormHomeAddressRepository(db)
+
+		address, err := repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		assert.Nil(t, address)
+
+		for _, street := range []string{"Brattle Street", "Fruit Street"} {
+			err = repo.Save(&models.HomeAddress{
+				PatientID: patientID,
+				Address: "10 " + street,
+				DisplayName: "10, " + street,
+				Lat: 42.37,
+				Lon: -71.11,
+			})
+			require.NoError(t, err)
+		}
+
+		address, err = repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		require.NotNil(t, address)
+		assert.Equal(t, "10 Fruit Street", address.Address)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormHomeAddressRepository(db)
+		err := repo.Save(&models.HomeAddress{
+			PatientID: patientID, Address: "10 Brattle Street",
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(patientID))
+
+		address, err := repo.FindByPatientID(patientID)
+		require.NoError(t, err)
+		assert.Nil(t, address)
+
+		err = repo.Delete(patientID)
+		assert.ErrorIs(t, err, &igakuErrors.HomeAddressNotFoundError{})
+	})
+}
@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"igaku/visit-service/dictionaries"
+)
+
+func TestICD10Dictionary_Lookup(t *testing.T) {
+	dict, err := dictionaries.LoadICD10Dictionary("../resources/icd10.csv")
+	require.NoError(t, err)
+
+	entry, ok := dict.Lookup(" i10 ")
+	require.True(t, ok, "Expected lookup to ignore case and whitespace")
+	assert.Equal(t, "I10", entry.Code)
+	assert.Equal(t, "Essential (primary) hypertension", entry.Description)
+
+	_, ok = dict.Lookup("X99.999")
+	assert.False(t, ok)
+}
+
+func TestICD10Dictionary_Search(t *testing.T) {
+	dict, err := dictionaries.LoadICD10Dictionary("../resources/icd10.csv")
+	require.NoError(t, err)
+
+	byCode := dict.Search("E11", 10)
+	require.NotEmpty(t, byCode)
+	for _, entry := range byCode {
+		assert.Contains(t, entry.Code, "E11")
+	}
+
+	byDescription := dict.Search("asthma", 10)
+	require.Len(t, byDescription, 1)
+	assert.Equal(t, "J45.909", byDescription[0].Code)
+
+	assert.Len(t, dict.Search("unspecified", 3), 3)
+}
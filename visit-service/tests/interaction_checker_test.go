@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"errors"
+	"testing"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+var warfarin = models.Prescription{
+	DrugID: "warfarin-5-tab",
+	DrugName: "Warfarin",
+	ATCCode: "B01AA03",
+	Status: models.PrescriptionIssued,
+}
+
+func TestInteractionDataset_Load(t *testing.T) {
+	dataset, err := dictionaries.LoadInteractionDataset(
+		"../resources/interactions.csv", "../resources/allergy_classes.csv",
+	)
+	require.NoError(t, err)
+
+	ibuprofen := models.Drug{Name: "Ibuprofen", ATCCode: "M01AE01"}
+	warfarinDrug := models.Drug{Name: "Warfarin", ATCCode: "B01AA03"}
+	rules := dataset.Interactions(ibuprofen, warfarinDrug)
+	require.NotEmpty(t, rules, "Expected rules to match in either order")
+	assert.Equal(t, models.Severe, rules[0].Severity)
+
+	amoxicillin := models.Drug{Name: "Amoxicillin", ATCCode: "J01CA04"}
+	allergyRules := dataset.AllergyRules("Penicillins", amoxicillin)
+	require.NotEmpty(t, allergyRules)
+	assert.Equal(t, models.Severe, allergyRules[0].Severity)
+
+	atorvastatin := models.Drug{Name: "Atorvastatin", ATCCode: "C10AA05"}
+	assert.Empty(t, dataset.Interactions(atorvastatin, amoxicillin))
+}
+
+func TestInteractionChecker_Check(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	mockGenotypeRepo := new(mocks.GenotypeRepository)
+	checker := services.NewInteractionChecker(
+		mockRepo, mockAllergyRepo, mockGenotypeRepo, testInteractions,
+		testGuidelines,
+	)
+
+	patientID := uuid.New()
+	current := []models.Prescription{
+		warfarin,
+		{DrugName: "Ibuprofen", ATCCode: "M01AE01"},
+	}
+	mockRepo.On("FindByPatientID", patientID, mock.Anything).
+		Return(current, nil).
+		Once()
+	mockAllergyRepo.On("FindByPatientID", patientID).
+		Return([]models.Allergy{{Substance: "Ibuprofen"}}, nil).
+		Once()
+	mockGenotypeRepo.On("FindByPatientID", patientID).
+		Return([]models.Genotype{}, nil).
+		Once()
+
+	drug, _ := testDrugs.Lookup("ibuprofen-400-tab")
+	warnings, err := checker.Check(patientID, *drug)
+	require.NoError(t, err)
+
+	require.Len(t, warnings, 3)
+	assert.Equal(t, models.Severe, warnings[0].Severity)
+	assert.Equal(t, models.Severe, warnings[1].Severity)
+	assert.Equal(t, models.DuplicateTherapy, warnings[2].Kind)
+	assert.Equal(t, models.Moderate, warnings[2].Severity)
+}
+
+func TestPrescriptionService_Issue_SevereRequiresOverride(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	service := newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), mockUserClient,
+		mockAllergyRepo,
+	)
+
+	patient := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Patient}
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil)
+	mockRepo.On("FindByPatientID", patient.ID, mock.Anything).
+		Return([]models.Prescription{warfarin}, nil)
+	mockAllergyRepo.On("FindByPatientID", patient.ID).
+		Return([]models.Allergy{}, nil)
+
+	req := newPrescriptionRequest(patient.ID)
+	req.DrugID = "ibuprofen-400-tab"
+	_, err := service.Issue(uuid.New(), commonsModels.Doctor, req)
+
+	var severe *igakuErrors.SevereInteractionError
+	require.True(t, errors.As(err, &severe))
+	require.Len(t, severe.Warnings, 1)
+	assert.Equal(t, models.DrugInteraction, severe.Warnings[0].Kind)
+	assert.Equal(t, "Warfarin", severe.Warnings[0].Substance)
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+
+	mockRepo.On("Persist", mock.AnythingOfType("*models.Prescription")).
+		Return(nil).
+		Once()
+
+	req.OverrideReason = "  INR monitored weekly  "
+	p, err := service.Issue(uuid.New(), commonsModels.Doctor, req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "INR monitored weekly", p.OverrideReason)
+	require.Len(t, p.Warnings, 1)
+	assert.Equal(t, models.Severe, p.Warnings[0].Severity)
+}
+
+func TestPrescriptionService_Check_AllergyClass(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	service := newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), new(mocks.UserClient),
+		mockAllergyRepo,
+	)
+
+	patientID := uuid.New()
+	mockRepo.On("FindByPatientID", patientID, mock.Anything).
+		Return([]models.Prescription{}, nil)
+	mockAllergyRepo.On("FindByPatientID", patientID).
+		Return([]models.Allergy{{Substance: "Penicillins"}}, nil)
+
+	warnings, err := service.Check(patientID, uuid.New(), commonsModels.Doctor, "amoxicillin-500-cap")
+	require.NoError(t, err)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, models.AllergyConflict, warnings[0].Kind)
+	assert.Equal(t, "Penicillins", warnings[0].Substance)
+
+	_, err = service.Check(patientID, uuid.New(), commonsModels.Doctor, "unknown")
+	assert.ErrorIs(t, err, &igakuErrors.UnknownDrugError{})
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/models"
+)
+
+func TestLabDictionary_Convert(t *testing.T) {
+	labs, err := dictionaries.LoadLabDictionary(
+		"../resources/lab_analytes.csv", "../resources/lab_units.csv",
+	)
+	require.NoError(t, err)
+
+	glucose, ok := labs.Lookup("2345-7")
+	require.True(t, ok)
+	assert.Equal(t, "mmol/L", glucose.Unit)
+
+	value, ok := glucose.Convert(100, "mg/dl")
+	require.True(t, ok, "Expected units to be compared ignoring case")
+	assert.Equal(t, 5.55, value)
+
+	value, ok = glucose.Convert(5.2, "mmol/L")
+	require.True(t, ok)
+	assert.Equal(t, 5.2, value)
+
+	_, ok = glucose.Convert(5.2, "g/L")
+	assert.False(t, ok)
+
+	creatinine, ok := labs.Lookup("2160-0")
+	require.True(t, ok)
+	value, ok = creatinine.Convert(80, "µmol/L")
+	require.True(t, ok, "Expected the micro sign to match 'u'")
+	assert.Equal(t, 80.0, value)
+}
+
+func TestValidLOINCCode(t *testing.T) {
+	assert.True(t, models.ValidLOINCCode("2345-7"))
+	assert.True(t, models.ValidLOINCCode("718-7"))
+	assert.True(t, models.ValidLOINCCode("13457-7"))
+	assert.False(t, models.ValidLOINCCode("2345-6"), "Expected a wrong check digit to be rejected")
+	assert.False(t, models.ValidLOINCCode("2345"))
+	assert.False(t, models.ValidLOINCCode("23a5-7"))
+}
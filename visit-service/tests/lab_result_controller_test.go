@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+)
+
+func setupLabResultRouter(
+	mockRepo *mocks.LabResultRepository,
+	mockUserClient *mocks.UserClient,
+) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	labResultService := newTestLabResultService(
+		mockRepo, new(mocks.CareTeamRepository), mockUserClient,
+	)
+	labResultController := controllers.NewLabResultController(labResultService)
+
+	router := gin.Default()
+	labResultController.RegisterRoutes(router)
+	return router
+}
+
+func TestLabResultController_Create_Patient(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	router := setupLabResultRouter(mockRepo, new(mocks.UserClient))
+
+	patientID := uuid.New()
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/patients/"+patientID.String()+"/lab-results",
+		bytes.NewReader([]byte("{}")),
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLabResultController_Series_InvalidCode(t *testing.T) {
+	router := setupLabResultRouter(
+		new(mocks.LabResultRepository), new(mocks.UserClient),
+	)
+
+	patientID := uuid.New()
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		"/visit/patients/"+patientID.String()+"/lab-results/2345-6/series",
+		nil,
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLabResultController_Import(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	mockUserClient := new(mocks.UserClient)
+	router := setupLabResultRouter(mockRepo, mockUserClient)
+
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	mockUserClient.On("FindByID", patientID).
+		Return(&commonsModels.User{ID: patientID, Role: commonsModels.Patient}, nil).
+		Once()
+	mockRepo.On("Create", mock.AnythingOfType("[]models.LabResult")).
+		Return(int64(2), nil).
+		Once()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", "results.hl7")
+	part.Write([]byte(testORU))
+	writer.Close()
+
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/lab-results/import", &body,
+	)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Admin))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var summary dtos.LabImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+	assert.Equal(t, 1, summary.Messages)
+	assert.Equal(t, 2, summary.Imported)
+	assert.Len(t, summary.Rejected, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLabResultController_List(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	router := setupLabResultRouter(mockRepo, new(mocks.UserClient))
+
+	patientID := uuid.New()
+	mockRepo.On("FindByPatientID", patientID, mock.MatchedBy(func(f dtos.LabResultFilter) bool {
+		return f.LOINCCode == "2345-7" && f.From != nil && f.To == nil
+	})).
+		Return([]models.LabResult{{Value: 5.2}, {Value: 6.1}}, nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		"/visit/patients/"+patientID.String()+
+			"/lab-results?code=2345-7&from=2025-01-01T00:00:00Z",
+		nil,
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var results []models.LabResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, 6.1, results[0].Value, "Expected the newest result first")
+}
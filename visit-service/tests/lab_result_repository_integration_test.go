@@ -0,0 +1,88 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+)
+
+func TestGormLabResultRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+
+	t.Run("FindByPatientID_ReturnsSeededSeries", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormLabResultRepository(db)
+		results, err := repo.FindByPatientID(
+			patientID, dtos.LabResultFilter{LOINCCode: "2345-7"},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, results, 2)
+		assert.True(t, results[0].ObservedAt.Before(results[1].ObservedAt))
+		assert.Equal(t, models.LabHigh, results[1].Flag)
+
+		from := time.Now().Add(-60 * 24 * time.Hour)
+		results, err = repo.FindByPatientID(
+			patientID, dtos.LabResultFilter{From: &from},
+		)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+
+	t.Run("Create_SkipsImportedResults", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormLabResultRepository(db)
+		newResult := func() models.LabResult {
+			sourceID := "MSG00001/1"
+			return models.LabResult{
+				ID: uuid.New(),
+				PatientID: patientID,
+				LOINCCode: "2823-3",
+				Name: "Potassium",
+				Value: 4.2,
+				Unit: "mmol/L",
+				ReportedValue: 4.2,
+				ReportedUnit: "mmol/L",
+				Flag: models.LabNormal,
+				ObservedAt: time.Now(),
+				RecordedBy: doctorID,
+				RecordedAt: time.Now(),
+				Source: models.LabSourceHL7,
+				SourceID: &sourceID,
+			}
+		}
+
+		created, err := repo.Create([]models.LabResult{newResult()})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, created)
+
+		created, err = repo.Create([]models.LabResult{newResult()})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, created)
+	})
+}
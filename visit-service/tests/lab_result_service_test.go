@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"strings"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var testLabs = dictionaries.NewLabDictionary([]models.LabAnalyte{
+	{
+		Code: "2345-7",
+		Name: "Glucose",
+		Unit: "mmol/L",
+		ReferenceLow: ptr(3.9),
+		ReferenceHigh: ptr(5.5),
+		CriticalLow: ptr(2.5),
+		CriticalHigh: ptr(25.0),
+		Factors: map[string]float64{"mg/dl": 0.0555},
+	},
+	{
+		Code: "2093-3",
+		Name: "Cholesterol",
+		Unit: "mmol/L",
+		ReferenceHigh: ptr(5.0),
+		Factors: map[string]float64{"mg/dl": 0.02586},
+	},
+})
+
+func newTestLabResultService(
+	repo *mocks.LabResultRepository,
+	careTeamRepo *mocks.CareTeamRepository,
+	userClient *mocks.UserClient,
+) services.LabResultService {
+	return services.NewLabResultService(repo, newTestPatientAccess(careTeamRepo), userClient, newTestEventClient(), testLabs, time.UTC)
+}
+
+// expectTreatingDoctor makes the doctor the only one on the patient's care
+// team.
+func expectTreatingDoctor(
+	careTeamRepo *mocks.CareTeamRepository, patientID, doctorID uuid.UUID,
+) {
+	careTeamRepo.On("IsMember", patientID, doctorID).Return(true, nil)
+	careTeamRepo.On("IsMember", patientID, mock.Anything).Return(false, nil)
+}
+
+func TestLabResultService_Create_NormalizesAndFlags(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service := newTestLabResultService(mockRepo, mockCareTeamRepo, new(mocks.UserClient))
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	expectTreatingDoctor(mockCareTeamRepo, patientID, doctorID)
+	mockRepo.On("Create", mock.AnythingOfType("[]models.LabResult")).
+		Return(int64(1), nil).
+		Once()
+
+	result, err := service.Create(
+		patientID, doctorID, commonsModels.Doctor,
+		dtos.LabResultRequest{
+			LOINCCode: "2345-7",
+			Value: ptr(110.0),
+			Unit: "mg/dL",
+			ObservedAt: time.Now(),
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Glucose", result.Name)
+	assert.Equal(t, 6.105, result.Value)
+	assert.Equal(t, "mmol/L", result.Unit)
+	assert.Equal(t, 110.0, result.ReportedValue)
+	assert.Equal(t, "mg/dL", result.ReportedUnit)
+	assert.Equal(t, 5.5, *result.ReferenceHigh, "Expected the dictionary range")
+	assert.Equal(t, models.LabHigh, result.Flag)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLabResultService_Create_CriticalValue(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service := newTestLabResultService(mockRepo, mockCareTeamRepo, new(mocks.UserClient))
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	expectTreatingDoctor(mockCareTeamRepo, patientID, doctorID)
+	mockRepo.On("Create", mock.Anything).Return(int64(1), nil).Once()
+
+	result, err := service.Create(
+		patientID, doctorID, commonsModels.Doctor,
+		dtos.LabResultRequest{
+			LOINCCode: "2345-7",
+			Value: ptr(2.1),
+			Unit: "mmol/L",
+			ObservedAt: time.Now(),
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.LabCriticalLow, result.Flag)
+}
+
+func TestLabResultService_Create_UnsupportedUnit(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service := newTestLabResultService(mockRepo, mockCareTeamRepo, new(mocks.UserClient))
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	expectTreatingDoctor(mockCareTeamRepo, patientID, doctorID)
+
+	_, err := service.Create(
+		patientID, doctorID, commonsModels.Doctor,
+		dtos.LabResultRequest{
+			LOINCCode: "2345-7",
+			Value: ptr(1.0),
+			Unit: "g/L",
+			ObservedAt: time.Now(),
+		},
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.UnsupportedLabUnitError{})
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLabResultService_Create_UnrelatedDoctor(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	service := newTestLabResultService(mockRepo, mockCareTeamRepo, new(mocks.UserClient))
+
+	patientID := uuid.New()
+	expectTreatingDoctor(mockCareTeamRepo, patientID, uuid.New())
+
+	_, err := service.Create(
+		patientID, uuid.New(), commonsModels.Doctor,
+		dtos.LabResultRequest{
+			LOINCCode: "2345-7",
+			Value: ptr(5.0),
+			Unit: "mmol/L",
+			ObservedAt: time.Now(),
+		},
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestLabResultService_Import(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := newTestLabResultService(
+		mockRepo, new(mocks.CareTeamRepository), mockUserClient,
+	)
+
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	mockUserClient.On("FindByID", patientID).
+		Return(&commonsModels.User{ID: patientID, Role: commonsModels.Patient}, nil).
+		Once()
+	unknownID := uuid.New()
+	mockUserClient.On("FindByID", unknownID).
+		Return(nil, &commonsErrors.UserNotFoundError{}).
+		Once()
+
+	var stored []models.LabResult
+	mockRepo.On("Create", mock.AnythingOfType("[]models.LabResult")).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(0).([]models.LabResult)
+		}).
+		Return(int64(1), nil).
+		Once()
+
+	data := testORU + strings.NewReplacer(
+		"MSG00001", "MSG00002", patientID.String(), unknownID.String(),
+	).Replace(testORU)
+	summary, err := service.Import([]byte(data), uuid.New(), commonsModels.Admin)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, summary.Messages)
+	assert.Equal(t, 1, summary.Imported)
+	assert.Equal(t, 1, summary.Duplicates)
+	require.Len(t, summary.Rejected, 2)
+	assert.Equal(t, 6, summary.Rejected[0].Line, "Expected the text observation to be rejected")
+	assert.Equal(t, "MSG00002", summary.Rejected[1].ControlID)
+
+	require.Len(t, stored, 2)
+	assert.Equal(t, 5.482, stored[0].Value)
+	assert.Equal(t, models.LabHigh, stored[0].Flag)
+	assert.Equal(t, "MSG00001/1", *stored[0].SourceID)
+	assert.Equal(t, models.LabSourceHL7, stored[0].Source)
+	assert.Equal(t, models.LabNormal, stored[1].Flag)
+	mockUserClient.AssertExpectations(t)
+}
+
+func TestLabResultService_Import_NotHL7(t *testing.T) {
+	service := newTestLabResultService(
+		new(mocks.LabResultRepository), new(mocks.CareTeamRepository),
+		new(mocks.UserClient),
+	)
+
+	_, err := service.Import([]byte("name,value\n"), uuid.New(), commonsModels.Admin)
+
+	var invalid *igakuErrors.InvalidLabMessageError
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestLabResultService_Series_UnknownAnalyteKeepsLatestUnit(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	service := newTestLabResultService(
+		mockRepo, new(mocks.CareTeamRepository), new(mocks.UserClient),
+	)
+
+	patientID := uuid.New()
+	filter := dtos.LabResultFilter{LOINCCode: "1751-7"}
+	mockRepo.On("FindByPatientID", patientID, filter).
+		Return([]models.LabResult{
+			{Name: "Albumin", Value: 3.9, Unit: "g/dL"},
+			{Name: "Albumin", Value: 41, Unit: "g/L"},
+			{Name: "Albumin", Value: 44, Unit: "g/L"},
+		}, nil).
+		Once()
+
+	series, err := service.Series(patientID, patientID, commonsModels.Patient, filter)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Albumin", series.Name)
+	assert.Equal(t, "g/L", series.Unit)
+	assert.Len(t, series.Points, 2)
+}
+
+func TestLabResultService_List_OtherPatient(t *testing.T) {
+	mockRepo := new(mocks.LabResultRepository)
+	service := newTestLabResultService(
+		mockRepo, new(mocks.CareTeamRepository), new(mocks.UserClient),
+	)
+
+	_, err := service.List(
+		uuid.New(), uuid.New(), commonsModels.Patient, dtos.LabResultFilter{},
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "FindByPatientID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/models"
+)
+
+type AllergyRepository struct {
+	mock.Mock
+}
+
+func (m *AllergyRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Allergy, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.Allergy
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Allergy)
+	}
+
+	return r0, args.Error(1)
+}
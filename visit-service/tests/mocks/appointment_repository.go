@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+)
+
+type AppointmentRepository struct {
+	mock.Mock
+}
+
+func (m *AppointmentRepository) FindByID(id uuid.UUID) (*models.Appointment, error) {
+	args := m.Called(id)
+
+	var r0 *models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Appointment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *AppointmentRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Appointment, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Appointment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *AppointmentRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.Appointment, error) {
+	args := m.Called(doctorID)
+
+	var r0 []models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Appointment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *AppointmentRepository) Search(
+	filter dtos.AppointmentFilter,
+) ([]models.Appointment, int64, error) {
+	args := m.Called(filter)
+
+	var r0 []models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Appointment)
+	}
+
+	return r0, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *AppointmentRepository) Cancel(id uuid.UUID) (*models.Appointment, error) {
+	args := m.Called(id)
+
+	var r0 *models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Appointment)
+	}
+
+	return r0, args.Error(1)
+}
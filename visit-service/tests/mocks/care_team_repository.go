@@ -0,0 +1,85 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type CareTeamRepository struct {
+	mock.Mock
+}
+
+func (m *CareTeamRepository) Assign(
+	assignment *models.CareAssignment,
+) (*models.CareAssignment, error) {
+	args := m.Called(assignment)
+
+	var r0 *models.CareAssignment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.CareAssignment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *CareTeamRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.CareAssignment, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.CareAssignment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.CareAssignment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *CareTeamRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.CareAssignment, error) {
+	args := m.Called(doctorID)
+
+	var r0 []models.CareAssignment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.CareAssignment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *CareTeamRepository) FindPatientIDs(
+	doctorID uuid.UUID,
+) ([]uuid.UUID, error) {
+	args := m.Called(doctorID)
+
+	var r0 []uuid.UUID
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]uuid.UUID)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *CareTeamRepository) IsMember(patientID, doctorID uuid.UUID) (bool, error) {
+	args := m.Called(patientID, doctorID)
+
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *CareTeamRepository) End(
+	id, patientID uuid.UUID, at time.Time,
+) (*models.CareAssignment, error) {
+	args := m.Called(id, patientID, at)
+
+	var r0 *models.CareAssignment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.CareAssignment)
+	}
+
+	return r0, args.Error(1)
+}
@@ -0,0 +1,74 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type ConsentRepository struct {
+	mock.Mock
+}
+
+func (m *ConsentRepository) Create(
+	consent *models.Consent, actorID uuid.UUID,
+) error {
+	args := m.Called(consent, actorID)
+
+	return args.Error(0)
+}
+
+func (m *ConsentRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Consent, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.Consent
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Consent)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ConsentRepository) FindActive(
+	patientID uuid.UUID, at time.Time,
+) ([]models.Consent, error) {
+	args := m.Called(patientID, at)
+
+	var r0 []models.Consent
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Consent)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ConsentRepository) Revoke(
+	id, patientID, actorID uuid.UUID, at time.Time,
+) (*models.Consent, error) {
+	args := m.Called(id, patientID, actorID, at)
+
+	var r0 *models.Consent
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Consent)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ConsentRepository) FindEvents(
+	patientID uuid.UUID,
+) ([]models.ConsentEvent, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.ConsentEvent
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.ConsentEvent)
+	}
+
+	return r0, args.Error(1)
+}
@@ -0,0 +1,81 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type ConversationRepository struct {
+	mock.Mock
+}
+
+func (m *ConversationRepository) Create(
+	conversation *models.Conversation, message *models.Message,
+) error {
+	args := m.Called(conversation, message)
+
+	return args.Error(0)
+}
+
+func (m *ConversationRepository) FindByID(
+	id uuid.UUID,
+) (*models.Conversation, error) {
+	args := m.Called(id)
+
+	var r0 *models.Conversation
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Conversation)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ConversationRepository) FindByParticipant(
+	userID uuid.UUID,
+) ([]models.Conversation, error) {
+	args := m.Called(userID)
+
+	var r0 []models.Conversation
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Conversation)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ConversationRepository) AddMessage(message *models.Message) error {
+	args := m.Called(message)
+
+	return args.Error(0)
+}
+
+func (m *ConversationRepository) FindMessages(
+	conversationID uuid.UUID,
+) ([]models.Message, error) {
+	args := m.Called(conversationID)
+
+	var r0 []models.Message
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Message)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ConversationRepository) MarkRead(
+	conversationID, readerID uuid.UUID, at time.Time,
+) (int64, error) {
+	args := m.Called(conversationID, readerID, at)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ConversationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+
+	return args.Get(0).(int64), args.Error(1)
+}
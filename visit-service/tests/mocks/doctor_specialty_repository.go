@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type DoctorSpecialtyRepository struct {
+	mock.Mock
+}
+
+func (m *DoctorSpecialtyRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]string, error) {
+	args := m.Called(doctorID)
+
+	var r0 []string
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]string)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *DoctorSpecialtyRepository) Replace(
+	doctorID uuid.UUID, specialties []string,
+) error {
+	args := m.Called(doctorID, specialties)
+
+	return args.Error(0)
+}
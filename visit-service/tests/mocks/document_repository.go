@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/models"
+)
+
+type DocumentRepository struct {
+	mock.Mock
+}
+
+func (m *DocumentRepository) Create(doc *models.Document) error {
+	args := m.Called(doc)
+
+	return args.Error(0)
+}
+
+func (m *DocumentRepository) FindByID(id uuid.UUID) (*models.Document, error) {
+	args := m.Called(id)
+
+	var r0 *models.Document
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Document)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *DocumentRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Document, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.Document
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Document)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *DocumentRepository) Delete(doc *models.Document) error {
+	args := m.Called(doc)
+
+	return args.Error(0)
+}
@@ -0,0 +1,109 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type EmergencyAccessRepository struct {
+	mock.Mock
+}
+
+func (m *EmergencyAccessRepository) Create(access *models.EmergencyAccess) error {
+	args := m.Called(access)
+
+	return args.Error(0)
+}
+
+func (m *EmergencyAccessRepository) FindByID(
+	id uuid.UUID,
+) (*models.EmergencyAccess, error) {
+	args := m.Called(id)
+
+	var r0 *models.EmergencyAccess
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.EmergencyAccess)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EmergencyAccessRepository) FindActive(
+	patientID, doctorID uuid.UUID, at time.Time,
+) (*models.EmergencyAccess, error) {
+	args := m.Called(patientID, doctorID, at)
+
+	var r0 *models.EmergencyAccess
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.EmergencyAccess)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EmergencyAccessRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.EmergencyAccess, error) {
+	args := m.Called(doctorID)
+
+	var r0 []models.EmergencyAccess
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.EmergencyAccess)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EmergencyAccessRepository) FindByStatus(
+	status models.EmergencyReviewStatus,
+) ([]models.EmergencyAccess, error) {
+	args := m.Called(status)
+
+	var r0 []models.EmergencyAccess
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.EmergencyAccess)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EmergencyAccessRepository) Review(
+	id, reviewerID uuid.UUID,
+	status models.EmergencyReviewStatus,
+	note string,
+	at time.Time,
+) (*models.EmergencyAccess, error) {
+	args := m.Called(id, reviewerID, status, note, at)
+
+	var r0 *models.EmergencyAccess
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.EmergencyAccess)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EmergencyAccessRepository) LogAccess(
+	event *models.EmergencyAccessEvent,
+) error {
+	args := m.Called(event)
+
+	return args.Error(0)
+}
+
+func (m *EmergencyAccessRepository) FindEvents(
+	accessID uuid.UUID,
+) ([]models.EmergencyAccessEvent, error) {
+	args := m.Called(accessID)
+
+	var r0 []models.EmergencyAccessEvent
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.EmergencyAccessEvent)
+	}
+
+	return r0, args.Error(1)
+}
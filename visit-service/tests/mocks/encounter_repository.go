@@ -0,0 +1,94 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/models"
+)
+
+type EncounterRepository struct {
+	mock.Mock
+}
+
+func (m *EncounterRepository) Create(
+	enc *models.Encounter, rev *models.EncounterRevision,
+) error {
+	args := m.Called(enc, rev)
+
+	return args.Error(0)
+}
+
+func (m *EncounterRepository) FindByID(id uuid.UUID) (*models.Encounter, error) {
+	args := m.Called(id)
+
+	var r0 *models.Encounter
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Encounter)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EncounterRepository) FindByAppointmentID(
+	appointmentID uuid.UUID,
+) (*models.Encounter, error) {
+	args := m.Called(appointmentID)
+
+	var r0 *models.Encounter
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Encounter)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EncounterRepository) FindAccessible(
+	userID uuid.UUID,
+) ([]models.Encounter, error) {
+	args := m.Called(userID)
+
+	var r0 []models.Encounter
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Encounter)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EncounterRepository) AppendRevision(rev *models.EncounterRevision) error {
+	args := m.Called(rev)
+
+	return args.Error(0)
+}
+
+func (m *EncounterRepository) AddAttachment(att *models.EncounterAttachment) error {
+	args := m.Called(att)
+
+	return args.Error(0)
+}
+
+func (m *EncounterRepository) FindAttachment(
+	encounterID, id uuid.UUID,
+) (*models.EncounterAttachment, error) {
+	args := m.Called(encounterID, id)
+
+	var r0 *models.EncounterAttachment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.EncounterAttachment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *EncounterRepository) AddDelegation(del *models.EncounterDelegation) error {
+	args := m.Called(del)
+
+	return args.Error(0)
+}
+
+func (m *EncounterRepository) RemoveDelegation(encounterID, doctorID uuid.UUID) error {
+	args := m.Called(encounterID, doctorID)
+
+	return args.Error(0)
+}
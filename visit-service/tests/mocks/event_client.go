@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/commons/dtos"
+)
+
+type EventClient struct {
+	mock.Mock
+}
+
+func (m *EventClient) Publish(
+	eventType dtos.EventType, resourceID uuid.UUID, recipients ...uuid.UUID,
+) error {
+	args := m.Called(eventType, resourceID, recipients)
+
+	return args.Error(0)
+}
+
+func (m *EventClient) Shutdown() {}
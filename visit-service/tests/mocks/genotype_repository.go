@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/models"
+)
+
+type GenotypeRepository struct {
+	mock.Mock
+}
+
+func (m *GenotypeRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.Genotype, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.Genotype
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Genotype)
+	}
+
+	return r0, args.Error(1)
+}
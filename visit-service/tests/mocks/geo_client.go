@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"igaku/commons/dtos"
+)
+
+type GeoClient struct {
+	mock.Mock
+}
+
+func (m *GeoClient) ReverseGeocode(lat, lon float64) (*dtos.Location, error) {
+	args := m.Called(lat, lon)
+
+	var r0 *dtos.Location
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*dtos.Location)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *GeoClient) LookupLocation(
+	osmType dtos.OsmType, id int64,
+) (*dtos.Location, error) {
+	args := m.Called(osmType, id)
+
+	var r0 *dtos.Location
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*dtos.Location)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *GeoClient) Search(address string) ([]dtos.Location, error) {
+	args := m.Called(address)
+
+	var r0 []dtos.Location
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]dtos.Location)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *GeoClient) EstimateRoute(
+	req dtos.RouteRequest,
+) ([]dtos.RouteEstimate, error) {
+	args := m.Called(req)
+
+	var r0 []dtos.RouteEstimate
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]dtos.RouteEstimate)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *GeoClient) Shutdown() {}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/models"
+)
+
+type HealthRecordRepository struct {
+	mock.Mock
+}
+
+func (m *HealthRecordRepository) Create(record models.Recordable) error {
+	args := m.Called(record)
+
+	return args.Error(0)
+}
+
+func (m *HealthRecordRepository) FindByID(
+	kind models.HealthRecordKind, id uuid.UUID,
+) (models.Recordable, error) {
+	args := m.Called(kind, id)
+
+	var r0 models.Recordable
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(models.Recordable)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *HealthRecordRepository) FindByPatientID(
+	kind models.HealthRecordKind, patientID uuid.UUID,
+) ([]models.Recordable, error) {
+	args := m.Called(kind, patientID)
+
+	var r0 []models.Recordable
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Recordable)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *HealthRecordRepository) Save(record models.Recordable) error {
+	args := m.Called(record)
+
+	return args.Error(0)
+}
+
+func (m *HealthRecordRepository) Delete(record models.Recordable) error {
+	args := m.Called(record)
+
+	return args.Error(0)
+}
+
+func (m *HealthRecordRepository) Summary(
+	patientID uuid.UUID,
+) (*models.PatientSummary, error) {
+	args := m.Called(patientID)
+
+	var r0 *models.PatientSummary
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.PatientSummary)
+	}
+
+	return r0, args.Error(1)
+}
@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/models"
+)
+
+type HomeAddressRepository struct {
+	mock.Mock
+}
+
+func (m *HomeAddressRepository) FindByPatientID(
+	patientID uuid.UUID,
+) (*models.HomeAddress, error) {
+	args := m.Called(patientID)
+
+	var r0 *models.HomeAddress
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.HomeAddress)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *HomeAddressRepository) Save(address *models.HomeAddress) error {
+	args := m.Called(address)
+
+	return args.Error(0)
+}
+
+func (m *HomeAddressRepository) Delete(patientID uuid.UUID) error {
+	args := m.Called(patientID)
+
+	return args.Error(0)
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+)
+
+type LabResultRepository struct {
+	mock.Mock
+}
+
+func (m *LabResultRepository) Create(results []models.LabResult) (int64, error) {
+	args := m.Called(results)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *LabResultRepository) FindByPatientID(
+	patientID uuid.UUID, filter dtos.LabResultFilter,
+) ([]models.LabResult, error) {
+	args := m.Called(patientID, filter)
+
+	var r0 []models.LabResult
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.LabResult)
+	}
+
+	return r0, args.Error(1)
+}
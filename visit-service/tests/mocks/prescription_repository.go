@@ -0,0 +1,100 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type PrescriptionRepository struct {
+	mock.Mock
+}
+
+func (m *PrescriptionRepository) Persist(p *models.Prescription) error {
+	args := m.Called(p)
+
+	return args.Error(0)
+}
+
+func (m *PrescriptionRepository) FindByID(id uuid.UUID) (*models.Prescription, error) {
+	args := m.Called(id)
+
+	var r0 *models.Prescription
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Prescription)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *PrescriptionRepository) FindByVerificationCode(
+	code string,
+) (*models.Prescription, error) {
+	args := m.Called(code)
+
+	var r0 *models.Prescription
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Prescription)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *PrescriptionRepository) FindByPatientID(
+	patientID uuid.UUID, activeAt *time.Time,
+) ([]models.Prescription, error) {
+	args := m.Called(patientID, activeAt)
+
+	var r0 []models.Prescription
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Prescription)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *PrescriptionRepository) FindByDoctorID(
+	doctorID uuid.UUID,
+) ([]models.Prescription, error) {
+	args := m.Called(doctorID)
+
+	var r0 []models.Prescription
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Prescription)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *PrescriptionRepository) Cancel(id uuid.UUID) (*models.Prescription, error) {
+	args := m.Called(id)
+
+	var r0 *models.Prescription
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Prescription)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *PrescriptionRepository) Dispense(
+	code string, now time.Time,
+) (*models.Prescription, error) {
+	args := m.Called(code, now)
+
+	var r0 *models.Prescription
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Prescription)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *PrescriptionRepository) ExpireDue(now time.Time) (int64, error) {
+	args := m.Called(now)
+
+	return args.Get(0).(int64), args.Error(1)
+}
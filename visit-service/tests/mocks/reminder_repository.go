@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type ReminderRepository struct {
+	mock.Mock
+}
+
+func (m *ReminderRepository) ClaimDue(
+	kind models.ReminderKind, from, to time.Time,
+) ([]models.Appointment, error) {
+	args := m.Called(kind, from, to)
+
+	var r0 []models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Appointment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ReminderRepository) FindPreference(
+	userID uuid.UUID,
+) (*models.ReminderPreference, error) {
+	args := m.Called(userID)
+
+	var r0 *models.ReminderPreference
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.ReminderPreference)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *ReminderRepository) SavePreference(
+	pref *models.ReminderPreference,
+) error {
+	args := m.Called(pref)
+
+	return args.Error(0)
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type SlotRepository struct {
+	mock.Mock
+}
+
+func (m *SlotRepository) Persist(slot *models.Slot) error {
+	args := m.Called(slot)
+
+	return args.Error(0)
+}
+
+func (m *SlotRepository) FindByID(id uuid.UUID) (*models.Slot, error) {
+	args := m.Called(id)
+
+	var r0 *models.Slot
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Slot)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *SlotRepository) FindFree(
+	doctorID, organizationID *uuid.UUID, from, to time.Time,
+) ([]models.Slot, error) {
+	args := m.Called(doctorID, organizationID, from, to)
+
+	var r0 []models.Slot
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Slot)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *SlotRepository) Book(
+	slotID, patientID uuid.UUID,
+) (*models.Appointment, error) {
+	args := m.Called(slotID, patientID)
+
+	var r0 *models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Appointment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *SlotRepository) FindOrganizationIDs(
+	doctorID uuid.UUID,
+) ([]uuid.UUID, error) {
+	args := m.Called(doctorID)
+
+	var r0 []uuid.UUID
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]uuid.UUID)
+	}
+
+	return r0, args.Error(1)
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"igaku/commons/dtos"
+	"igaku/commons/models"
+)
+
+type UserClient struct {
+	mock.Mock
+}
+
+func (m *UserClient) FindByID(id uuid.UUID) (*models.User, error) {
+	args := m.Called(id)
+
+	var r0 *models.User
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.User)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *UserClient) Search(
+	req dtos.UserSearchRequest,
+) (*dtos.UserSearchResult, error) {
+	args := m.Called(req)
+
+	var r0 *dtos.UserSearchResult
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*dtos.UserSearchResult)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *UserClient) Shutdown() {}
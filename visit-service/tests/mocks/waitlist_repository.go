@@ -0,0 +1,109 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+
+	"time"
+
+	"igaku/visit-service/models"
+)
+
+type WaitlistRepository struct {
+	mock.Mock
+}
+
+func (m *WaitlistRepository) Persist(entry *models.WaitlistEntry) error {
+	args := m.Called(entry)
+
+	return args.Error(0)
+}
+
+func (m *WaitlistRepository) FindByID(id uuid.UUID) (*models.WaitlistEntry, error) {
+	args := m.Called(id)
+
+	var r0 *models.WaitlistEntry
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.WaitlistEntry)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *WaitlistRepository) FindByPatientID(
+	patientID uuid.UUID,
+) ([]models.WaitlistEntry, error) {
+	args := m.Called(patientID)
+
+	var r0 []models.WaitlistEntry
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.WaitlistEntry)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *WaitlistRepository) Withdraw(id uuid.UUID) (*uuid.UUID, error) {
+	args := m.Called(id)
+
+	var r0 *uuid.UUID
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*uuid.UUID)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *WaitlistRepository) OfferSlot(
+	slotID uuid.UUID, expiresAt time.Time,
+) (*models.WaitlistOffer, error) {
+	args := m.Called(slotID, expiresAt)
+
+	var r0 *models.WaitlistOffer
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.WaitlistOffer)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *WaitlistRepository) FindOfferByID(id uuid.UUID) (*models.WaitlistOffer, error) {
+	args := m.Called(id)
+
+	var r0 *models.WaitlistOffer
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.WaitlistOffer)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *WaitlistRepository) AcceptOffer(
+	id uuid.UUID, now time.Time,
+) (*models.Appointment, error) {
+	args := m.Called(id, now)
+
+	var r0 *models.Appointment
+	if args.Get(0) != nil {
+		r0 = args.Get(0).(*models.Appointment)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *WaitlistRepository) DeclineOffer(id uuid.UUID) error {
+	args := m.Called(id)
+
+	return args.Error(0)
+}
+
+func (m *WaitlistRepository) ExpireOffers(now time.Time) ([]uuid.UUID, error) {
+	args := m.Called(now)
+
+	var r0 []uuid.UUID
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]uuid.UUID)
+	}
+
+	return r0, args.Error(1)
+}
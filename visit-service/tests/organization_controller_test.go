@@ -11,12 +11,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"igaku/commons/dtos"
 	"igaku/visit-service/controllers"
 	"igaku/visit-service/errors"
 	"igaku/visit-service/models"
 	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
 )
 
 type MockOrganizationRepository struct {
@@ -36,10 +38,67 @@ func (m *MockOrganizationRepository) FindByID(id uuid.UUID) (*models.Organizatio
 	return r0, r1
 }
 
+func (m *MockOrganizationRepository) Search(
+	id *uuid.UUID, name string, offset, limit int,
+) ([]models.Organization, int64, error) {
+	args := m.Called(id, name, offset, limit)
+
+	var r0 []models.Organization
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Organization)
+	}
+
+	return r0, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockOrganizationRepository) FindNearest(
+	lat, lon, radiusKm float64, specialty string, limit int,
+) ([]models.NearbyOrganization, error) {
+	args := m.Called(lat, lon, radiusKm, specialty, limit)
+
+	var r0 []models.NearbyOrganization
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.NearbyOrganization)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindWithoutOsmType(
+	checkedBefore time.Time, limit int,
+) ([]models.Organization, error) {
+	args := m.Called(checkedBefore, limit)
+
+	var r0 []models.Organization
+	if args.Get(0) != nil {
+		r0 = args.Get(0).([]models.Organization)
+	}
+
+	return r0, args.Error(1)
+}
+
+func (m *MockOrganizationRepository) SetOsmType(
+	id uuid.UUID, osmType dtos.OsmType,
+) error {
+	args := m.Called(id, osmType)
+
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) MarkOsmTypeUnresolved(
+	id uuid.UUID, now time.Time,
+) error {
+	args := m.Called(id, now)
+
+	return args.Error(0)
+}
+
 func setupOrgRouter(mockRepo *MockOrganizationRepository) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 
-	orgService := services.NewOrganizationService(mockRepo)
+	orgService := services.NewOrganizationService(
+		mockRepo, new(mocks.GeoClient),
+	)
 	orgController := controllers.NewOrganizationController(orgService)
 
 	router := gin.Default()
@@ -150,3 +209,66 @@ func TestOrganizationController_GetByID_InvalidUUID(t *testing.T) {
 
 	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything)
 }
+
+func TestOrganizationController_Nearest_Success(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	router := setupOrgRouter(mockRepo)
+
+	orgID := uuid.New()
+	mockRepo.On("FindNearest", 42.36, -71.06, 5.0, "cardiology", 20).
+		Return([]models.NearbyOrganization{
+			{
+				Organization: models.Organization{ID: orgID, Name: "Test Org"},
+				DistanceKm: 1.5,
+			},
+		}, nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet,
+		"/visit/organizations/nearest?lat=42.36&lon=-71.06&radius_km=5&specialty=cardiology",
+		nil,
+	)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var orgs []models.NearbyOrganization
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &orgs))
+	if assert.Len(t, orgs, 1) {
+		assert.Equal(t, orgID, orgs[0].ID)
+		assert.Equal(t, 1.5, orgs[0].DistanceKm)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestOrganizationController_Nearest_InvalidParameters(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	router := setupOrgRouter(mockRepo)
+
+	for _, query := range []string{
+		"",
+		"lat=42.36",
+		"lat=91&lon=0",
+		"lat=north&lon=0",
+		"lat=42.36&lon=-71.06&radius_km=501",
+		"lat=42.36&lon=-71.06&limit=101",
+		"address=Boston&lat=42.36&lon=-71.06",
+	} {
+		req, _ := http.NewRequest(
+			http.MethodGet, "/visit/organizations/nearest?"+query, nil,
+		)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+
+	mockRepo.AssertNotCalled(
+		t, "FindNearest",
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything,
+	)
+}
@@ -7,13 +7,21 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/stretchr/testify/mock"
+
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"igaku/visit-service/models"
 	"igaku/visit-service/repositories"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
 	"igaku/visit-service/utils"
 	igakuErrors "igaku/visit-service/errors"
+	commonsDtos "igaku/commons/dtos"
 	testUtils "igaku/commons/utils"
 )
 
@@ -48,6 +56,10 @@ func TestGormOrganizationRepository(t *testing.T) {
 			t, int64(117853077), org.Location.ID,
 			"Expected organiztion's location ID to match",
 		)
+		assert.Equal(
+			t, commonsDtos.OsmWay, org.Location.OsmType,
+			"Expected organiztion's location type to match",
+		)
 		assert.Equal(
 			t, "42.3628605", org.Location.Lat,
 			"Expected organiztion's location latitude to match",
@@ -62,6 +74,20 @@ func TestGormOrganizationRepository(t *testing.T) {
 			org.Location.Name,
 			"Expected organiztion's location name to match",
 		)
+		assert.Equal(
+			t,
+			commonsDtos.Address{
+				HouseNumber: "55",
+				Street: "Fruit Street",
+				City: "Boston",
+				Postcode: "02114",
+				County: "Suffolk County",
+				Country: "United States",
+				CountryCode: "us",
+			},
+			org.Location.Address,
+			"Expected organiztion's address components to match",
+		)
 	})
 
 	t.Run("FindByID_NotFound", func(t *testing.T) {
@@ -91,4 +117,200 @@ func TestGormOrganizationRepository(t *testing.T) {
 			"Expected organization to be nil when not found",
 		)
 	})
+
+	t.Run("FindNearest", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormOrganizationRepository(db)
+
+		// Boston Common, about 0.9 km from Massachusetts General
+		// Hospital and 10 km from McLean Hospital.
+		orgs, err := repo.FindNearest(42.3550, -71.0656, 20, "", 10)
+		require.NoError(t, err)
+		require.Len(t, orgs, 2)
+		assert.Equal(t, "Massachusetts General Hospital", orgs[0].Name)
+		assert.Equal(t, "McLean Hospital", orgs[1].Name)
+		assert.InDelta(t, 0.9, orgs[0].DistanceKm, 0.1)
+		assert.Less(t, orgs[0].DistanceKm, orgs[1].DistanceKm)
+		assert.Equal(t, "42.3628605", orgs[0].Location.Lat)
+
+		orgs, err = repo.FindNearest(42.3550, -71.0656, 5, "", 10)
+		require.NoError(t, err)
+		require.Len(t, orgs, 1)
+		assert.Equal(t, "Massachusetts General Hospital", orgs[0].Name)
+
+		orgs, err = repo.FindNearest(42.3550, -71.0656, 20, "", 1)
+		require.NoError(t, err)
+		assert.Len(t, orgs, 1)
+	})
+
+	t.Run("FindNearest_MalformedCoordinates", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		err := db.Create(&models.Organization{
+			ID: uuid.New(),
+			Name: "Unmapped Clinic",
+			Location: commonsDtos.Location{Lat: "n/a", Lon: "-71.0656"},
+		}).Error
+		require.NoError(t, err)
+
+		// The schema is migrated on every start.
+		require.NoError(t, utils.MigrateSchema(db))
+
+		repo := repositories.NewGormOrganizationRepository(db)
+		orgs, err := repo.FindNearest(42.3550, -71.0656, 20, "", 10)
+		require.NoError(t, err)
+		require.Len(t, orgs, 2)
+	})
+
+	t.Run("FindNearest_Specialty", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormOrganizationRepository(db)
+
+		orgs, err := repo.FindNearest(42.3550, -71.0656, 20, "Cardiology", 10)
+		require.NoError(t, err)
+		assert.Len(t, orgs, 2)
+
+		orgs, err = repo.FindNearest(42.3550, -71.0656, 20, "dermatology", 10)
+		require.NoError(t, err)
+		assert.Empty(t, orgs)
+	})
+
+	t.Run("OsmType", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormOrganizationRepository(db)
+
+		orgID := uuid.MustParse("a6868293-b590-44f9-bf7e-1381beaf17d6")
+		err := db.Exec(
+			"UPDATE organizations SET loc_osm_type = '' WHERE id = ?", orgID,
+		).Error
+		require.NoError(t, err)
+
+		now := time.Now()
+		orgs, err := repo.FindWithoutOsmType(now, 10)
+		require.NoError(t, err)
+		require.Len(t, orgs, 1)
+		assert.Equal(t, orgID, orgs[0].ID)
+
+		err = repo.MarkOsmTypeUnresolved(orgID, now)
+		require.NoError(t, err)
+		orgs, err = repo.FindWithoutOsmType(now, 10)
+		require.NoError(t, err)
+		assert.Empty(t, orgs, "Expected a recent failure not to be retried")
+		orgs, err = repo.FindWithoutOsmType(now.Add(time.Second), 10)
+		require.NoError(t, err)
+		assert.Len(t, orgs, 1)
+
+		err = repo.SetOsmType(orgID, commonsDtos.OsmRelation)
+		require.NoError(t, err)
+
+		orgs, err = repo.FindWithoutOsmType(now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, orgs)
+
+		err = repo.SetOsmType(uuid.New(), commonsDtos.OsmWay)
+		assert.ErrorIs(t, err, &igakuErrors.OrganizationNotFoundError{})
+		err = repo.MarkOsmTypeUnresolved(uuid.New(), now)
+		assert.ErrorIs(t, err, &igakuErrors.OrganizationNotFoundError{})
+	})
+
+	t.Run("UnresolvableOsmTypesDoNotStallBackfill", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		// More than one batch of organizations at objects which no
+		// longer exist.
+		const unresolvable = 60
+		for i := 0; i < unresolvable; i++ {
+			err := db.Exec(
+				"INSERT INTO organizations (id, name, loc_id, loc_osm_type) " +
+				"VALUES (?, ?, ?, '')",
+				uuid.New(), fmt.Sprintf("Closed clinic %d", i), 900000+i,
+			).Error
+			require.NoError(t, err)
+		}
+
+		mockGeoClient := new(mocks.GeoClient)
+		mockGeoClient.On("LookupLocation", mock.Anything, mock.Anything).
+			Return(nil, nil)
+		service := services.NewOrganizationService(
+			repositories.NewGormOrganizationRepository(db), mockGeoClient,
+		)
+		lookedUp := func() map[int64]bool {
+			ids := make(map[int64]bool)
+			for _, call := range mockGeoClient.Calls {
+				ids[call.Arguments.Get(1).(int64)] = true
+			}
+			mockGeoClient.Calls = nil
+			return ids
+		}
+
+		now := time.Now()
+		require.NoError(t, service.ResolveOsmTypes(now))
+		first := lookedUp()
+		assert.Len(t, first, 50)
+
+		require.NoError(t, service.ResolveOsmTypes(now.Add(10*time.Minute)))
+		second := lookedUp()
+		assert.Len(t, second, unresolvable-50)
+		for id := range second {
+			assert.False(t, first[id], "Expected %d not to be looked up again", id)
+		}
+
+		require.NoError(t, service.ResolveOsmTypes(now.Add(20*time.Minute)))
+		assert.Empty(t, lookedUp())
+
+		// Failures are retried after a day, the oldest first.
+		require.NoError(t, service.ResolveOsmTypes(now.Add(25*time.Hour)))
+		assert.Equal(t, first, lookedUp())
+	})
+
+	t.Run("OsmObjectsOfDifferentTypes", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		// McLean Hospital is moved to the relation sharing the ID of
+		// the way Massachusetts General Hospital is located at.
+		err := db.Exec(`
+			UPDATE organizations SET loc_id = 117853077, loc_osm_type = 'R'
+			WHERE id = 'a6868293-b590-44f9-bf7e-1381beaf17d6'
+		`).Error
+		require.NoError(t, err)
+
+		err = db.Exec(`
+			UPDATE organizations SET loc_osm_type = 'W'
+			WHERE id = 'a6868293-b590-44f9-bf7e-1381beaf17d6'
+		`).Error
+		assert.Error(t, err, "Expected the same object to be unique")
+	})
 }
@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"errors"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsDtos "igaku/commons/dtos"
+	commonsErrors "igaku/commons/errors"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestOrganizationService_FindNearest_Coordinates(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewOrganizationService(mockRepo, mockGeoClient)
+
+	mockRepo.On("FindNearest", 42.36, -71.06, 10.0, "", 20).
+		Return([]models.NearbyOrganization{}, nil).
+		Once()
+
+	_, err := service.FindNearest(dtos.NearestOrganizationQuery{
+		Lat: ptr(42.36), Lon: ptr(-71.06),
+	})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockGeoClient.AssertNotCalled(t, "Search", mock.Anything)
+}
+
+func TestOrganizationService_FindNearest_Address(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewOrganizationService(mockRepo, mockGeoClient)
+
+	mockGeoClient.On("Search", "55 Fruit Street, Boston").
+		Return([]commonsDtos.Location{
+			{ID: 117853077, Lat: "42.3628605", Lon: "-71.0687530"},
+		}, nil).
+		Once()
+	mockRepo.On("FindNearest", 42.3628605, -71.068753, 2.5, "cardiology", 5).
+		Return([]models.NearbyOrganization{}, nil).
+		Once()
+
+	_, err := service.FindNearest(dtos.NearestOrganizationQuery{
+		Address: " 55 Fruit Street, Boston ",
+		RadiusKm: 2.5,
+		Specialty: "cardiology",
+		Limit: 5,
+	})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockGeoClient.AssertExpectations(t)
+}
+
+func TestOrganizationService_FindNearest_AddressNotFound(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewOrganizationService(mockRepo, mockGeoClient)
+
+	mockGeoClient.On("Search", "Nowhere").
+		Return([]commonsDtos.Location{}, nil).
+		Once()
+
+	_, err := service.FindNearest(dtos.NearestOrganizationQuery{
+		Address: "Nowhere",
+	})
+	assert.ErrorIs(t, err, &igakuErrors.AddressNotFoundError{})
+}
+
+func TestOrganizationService_FindNearest_GeocodingFailed(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewOrganizationService(mockRepo, mockGeoClient)
+
+	mockGeoClient.On("Search", "Boston").
+		Return(nil, errors.New("Request timed out")).
+		Once()
+	mockGeoClient.On("Search", "Belmont").
+		Return(nil, &commonsErrors.InternalError{}).
+		Once()
+
+	_, err := service.FindNearest(dtos.NearestOrganizationQuery{
+		Address: "Boston",
+	})
+	assert.ErrorIs(t, err, &igakuErrors.GeocodingFailedError{})
+
+	_, err = service.FindNearest(dtos.NearestOrganizationQuery{
+		Address: "Belmont",
+	})
+	assert.ErrorIs(t, err, &commonsErrors.InternalError{})
+}
+
+func TestOrganizationService_FindNearest_InvalidLocation(t *testing.T) {
+	service := services.NewOrganizationService(
+		new(MockOrganizationRepository), new(mocks.GeoClient),
+	)
+
+	for _, query := range []dtos.NearestOrganizationQuery{
+		{},
+		{Lat: ptr(42.36)},
+		{Lat: ptr(42.36), Lon: ptr(181.0)},
+		{Address: "Boston", Lon: ptr(-71.06)},
+	} {
+		_, err := service.FindNearest(query)
+		assert.ErrorIs(t, err, &igakuErrors.InvalidSearchLocationError{})
+	}
+}
+
+func TestOrganizationService_ResolveOsmTypes(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewOrganizationService(mockRepo, mockGeoClient)
+
+	now := time.Now()
+	resolvedID, missingID := uuid.New(), uuid.New()
+	mockRepo.On("FindWithoutOsmType", now.Add(-24*time.Hour), 50).
+		Return([]models.Organization{
+			{ID: resolvedID, Location: commonsDtos.Location{ID: 117853077}},
+			{ID: missingID, Location: commonsDtos.Location{ID: 1}},
+		}, nil).
+		Once()
+	mockGeoClient.On("LookupLocation", commonsDtos.OsmType(""), int64(117853077)).
+		Return(&commonsDtos.Location{
+			ID: 117853077, OsmType: commonsDtos.OsmWay,
+		}, nil).
+		Once()
+	mockGeoClient.On("LookupLocation", commonsDtos.OsmType(""), int64(1)).
+		Return(nil, nil).
+		Once()
+	mockRepo.On("SetOsmType", resolvedID, commonsDtos.OsmWay).
+		Return(nil).
+		Once()
+	mockRepo.On("MarkOsmTypeUnresolved", missingID, now).
+		Return(nil).
+		Once()
+
+	require.NoError(t, service.ResolveOsmTypes(now))
+	mockRepo.AssertExpectations(t)
+	mockGeoClient.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "SetOsmType", missingID, mock.Anything)
+}
+
+func TestOrganizationService_ResolveOsmTypes_GeoServiceDown(t *testing.T) {
+	mockRepo := new(MockOrganizationRepository)
+	mockGeoClient := new(mocks.GeoClient)
+	service := services.NewOrganizationService(mockRepo, mockGeoClient)
+
+	mockRepo.On("FindWithoutOsmType", mock.Anything, mock.Anything).
+		Return([]models.Organization{
+			{ID: uuid.New(), Location: commonsDtos.Location{ID: 1}},
+			{ID: uuid.New(), Location: commonsDtos.Location{ID: 2}},
+		}, nil).
+		Once()
+	mockGeoClient.On("LookupLocation", commonsDtos.OsmType(""), int64(1)).
+		Return(nil, &commonsErrors.InternalError{}).
+		Once()
+
+	err := service.ResolveOsmTypes(time.Now())
+	assert.ErrorIs(t, err, &commonsErrors.InternalError{})
+	mockGeoClient.AssertNumberOfCalls(t, "LookupLocation", 1)
+}
@@ -0,0 +1,222 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+// newTestPatientAccess returns the access policy of a patient without any
+// consents, so that access only depends on the care team.
+func newTestPatientAccess(careTeamRepo *mocks.CareTeamRepository) services.PatientAccess {
+	consentRepo := new(mocks.ConsentRepository)
+	consentRepo.On("FindActive", mock.Anything, mock.Anything).
+		Return([]models.Consent{}, nil).
+		Maybe()
+
+	return services.NewPatientAccess(
+		careTeamRepo, consentRepo, new(mocks.SlotRepository),
+		new(mocks.EmergencyAccessRepository),
+	)
+}
+
+func TestPatientAccess_CareTeamWithoutConsents(t *testing.T) {
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	access := newTestPatientAccess(mockCareTeamRepo)
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	mockCareTeamRepo.On("IsMember", patientID, doctorID).Return(true, nil)
+	mockCareTeamRepo.On("IsMember", patientID, mock.Anything).Return(false, nil)
+
+	categories, err := access.Categories(patientID, doctorID, commonsModels.Doctor)
+	require.NoError(t, err)
+	assert.Equal(t, models.ConsentCategories, categories)
+
+	err = access.Check(patientID, uuid.New(), commonsModels.Doctor, models.RecordsCategory)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestPatientAccess_DenyOverridesCareTeam(t *testing.T) {
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	mockConsentRepo := new(mocks.ConsentRepository)
+	access := services.NewPatientAccess(
+		mockCareTeamRepo, mockConsentRepo, new(mocks.SlotRepository),
+		new(mocks.EmergencyAccessRepository),
+	)
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	mockConsentRepo.On("FindActive", patientID, mock.Anything).Return([]models.Consent{
+		{
+			PatientID: patientID,
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Permit,
+			Categories: []models.ConsentCategory{models.GenomicsCategory},
+		},
+		{
+			PatientID: patientID,
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Deny,
+			Categories: []models.ConsentCategory{
+				models.GenomicsCategory, models.DocumentsCategory,
+			},
+		},
+	}, nil)
+	mockCareTeamRepo.On("IsMember", patientID, doctorID).Return(true, nil)
+	mockCareTeamRepo.On("IsMember", patientID, mock.Anything).Return(false, nil)
+
+	categories, err := access.Categories(patientID, doctorID, commonsModels.Doctor)
+	require.NoError(t, err)
+	assert.NotContains(t, categories, models.GenomicsCategory)
+	assert.NotContains(t, categories, models.DocumentsCategory)
+	assert.Contains(t, categories, models.RecordsCategory)
+
+	// The patient's own access is never restricted.
+	err = access.Check(patientID, patientID, commonsModels.Patient, models.GenomicsCategory)
+	assert.NoError(t, err)
+}
+
+func TestPatientAccess_PermitGrantsAccessOutsideCareTeam(t *testing.T) {
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	mockConsentRepo := new(mocks.ConsentRepository)
+	access := services.NewPatientAccess(
+		mockCareTeamRepo, mockConsentRepo, new(mocks.SlotRepository),
+		new(mocks.EmergencyAccessRepository),
+	)
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	mockConsentRepo.On("FindActive", patientID, mock.Anything).Return([]models.Consent{
+		{
+			PatientID: patientID,
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Permit,
+			Categories: []models.ConsentCategory{models.LabResultsCategory},
+		},
+	}, nil)
+	mockCareTeamRepo.On("IsMember", patientID, doctorID).Return(false, nil)
+
+	err := access.Check(patientID, doctorID, commonsModels.Doctor, models.LabResultsCategory)
+	assert.NoError(t, err)
+	err = access.Check(patientID, doctorID, commonsModels.Doctor, models.RecordsCategory)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
+
+func TestPatientAccess_OrganizationConsentAppliesToItsDoctors(t *testing.T) {
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	mockConsentRepo := new(mocks.ConsentRepository)
+	mockSlotRepo := new(mocks.SlotRepository)
+	access := services.NewPatientAccess(
+		mockCareTeamRepo, mockConsentRepo, mockSlotRepo,
+		new(mocks.EmergencyAccessRepository),
+	)
+
+	patientID, doctorID, orgID := uuid.New(), uuid.New(), uuid.New()
+	mockConsentRepo.On("FindActive", patientID, mock.Anything).Return([]models.Consent{
+		{
+			PatientID: patientID,
+			GranteeType: models.OrganizationGrantee,
+			GranteeID: orgID,
+			Decision: models.Permit,
+			Categories: models.ConsentCategories,
+		},
+	}, nil)
+	mockSlotRepo.On("FindOrganizationIDs", doctorID).Return([]uuid.UUID{orgID}, nil).Once()
+
+	categories, err := access.Categories(patientID, doctorID, commonsModels.Doctor)
+	require.NoError(t, err)
+	assert.Equal(t, models.ConsentCategories, categories)
+	// Every category is decided, so the care team is not looked up.
+	mockCareTeamRepo.AssertNotCalled(t, "IsMember", mock.Anything, mock.Anything)
+	mockSlotRepo.AssertExpectations(t)
+}
+
+func TestPatientAccess_EmergencyAccessIsLogged(t *testing.T) {
+	mockEmergencyRepo := new(mocks.EmergencyAccessRepository)
+	access := services.NewPatientAccess(
+		new(mocks.CareTeamRepository), new(mocks.ConsentRepository),
+		new(mocks.SlotRepository), mockEmergencyRepo,
+	)
+
+	patientID, doctorID, otherPatientID := uuid.New(), uuid.New(), uuid.New()
+	grant := newTestEmergencyAccess(patientID, doctorID)
+	mockEmergencyRepo.On("FindByID", grant.ID).Return(grant, nil)
+	mockEmergencyRepo.On("LogAccess", mock.MatchedBy(func(e *models.EmergencyAccessEvent) bool {
+		return e.AccessID == grant.ID && e.PatientID == patientID &&
+			e.DoctorID == doctorID &&
+			len(e.Categories) == 1 && e.Categories[0] == models.GenomicsCategory
+	})).Return(nil).Once()
+
+	err := access.Check(patientID, grant.ID, models.EmergencyRole, models.GenomicsCategory)
+	require.NoError(t, err)
+
+	// The token does not extend to other patients, even ones the doctor
+	// has emergency access to as well.
+	err = access.Check(otherPatientID, grant.ID, models.EmergencyRole, models.GenomicsCategory)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockEmergencyRepo.AssertExpectations(t)
+}
+
+func TestPatientAccess_EmergencyAccessEnded(t *testing.T) {
+	mockEmergencyRepo := new(mocks.EmergencyAccessRepository)
+	access := services.NewPatientAccess(
+		new(mocks.CareTeamRepository), new(mocks.ConsentRepository),
+		new(mocks.SlotRepository), mockEmergencyRepo,
+	)
+
+	patientID := uuid.New()
+	expired := newTestEmergencyAccess(patientID, uuid.New())
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	revoked := newTestEmergencyAccess(patientID, uuid.New())
+	now := time.Now()
+	revoked.RevokedAt = &now
+	mockEmergencyRepo.On("FindByID", expired.ID).Return(expired, nil)
+	mockEmergencyRepo.On("FindByID", revoked.ID).Return(revoked, nil)
+
+	for _, grant := range []*models.EmergencyAccess{expired, revoked} {
+		err := access.Check(patientID, grant.ID, models.EmergencyRole, models.RecordsCategory)
+		assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	}
+	mockEmergencyRepo.AssertNotCalled(t, "LogAccess", mock.Anything)
+}
+
+func TestPatientAccess_EmergencyAccessRefusedWhenNotLogged(t *testing.T) {
+	mockEmergencyRepo := new(mocks.EmergencyAccessRepository)
+	access := services.NewPatientAccess(
+		new(mocks.CareTeamRepository), new(mocks.ConsentRepository),
+		new(mocks.SlotRepository), mockEmergencyRepo,
+	)
+
+	grant := newTestEmergencyAccess(uuid.New(), uuid.New())
+	mockEmergencyRepo.On("FindByID", grant.ID).Return(grant, nil)
+	mockEmergencyRepo.On("LogAccess", mock.Anything).
+		Return(&commonsErrors.DatabaseError{})
+
+	_, err := access.Categories(grant.PatientID, grant.ID, models.EmergencyRole)
+	assert.ErrorIs(t, err, &commonsErrors.DatabaseError{})
+}
+
+func TestConsent_ActiveAt(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	consent := models.Consent{ValidFrom: now, ValidUntil: &later}
+
+	assert.False(t, consent.ActiveAt(now.Add(-time.Minute)))
+	assert.True(t, consent.ActiveAt(now.Add(time.Minute)))
+	assert.False(t, consent.ActiveAt(later))
+
+	consent.RevokedAt = &now
+	assert.False(t, consent.ActiveAt(now.Add(time.Minute)))
+}
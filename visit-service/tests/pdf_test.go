@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"igaku/visit-service/utils"
+)
+
+func TestRenderTextPDF_Structure(t *testing.T) {
+	pdf := utils.RenderTextPDF("Title (draft)", []string{"Dosage: 1 tablet", "Żółć ☃"})
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), `(Title \(draft\)) Tj`)
+	assert.Contains(t, string(pdf), "(Dosage: 1 tablet) Tj")
+	assert.Contains(t, string(pdf), `(Z\363lc ?) Tj`)
+
+	// Every xref entry must point at the start of its object.
+	s := string(pdf)
+	xrefStart, err := strconv.Atoi(
+		regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(s)[1],
+	)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(s[xrefStart:], "xref\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(s, -1)
+	assert.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(
+			t, strings.HasPrefix(s[offset:], fmt.Sprintf("%d 0 obj", i+1)),
+			"Expected xref entry %d to point at its object", i+1,
+		)
+	}
+}
+
+func TestRenderTextPDF_Paginates(t *testing.T) {
+	lines := make([]string, 120)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("Line %d", i)
+	}
+
+	pdf := string(utils.RenderTextPDF("Long", lines))
+
+	assert.Contains(t, pdf, "/Count 3")
+	assert.Contains(t, pdf, "(Page 3 of 3) Tj")
+	assert.Contains(t, pdf, "(Line 119) Tj")
+}
@@ -0,0 +1,281 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+var testGuidelines = dictionaries.NewPharmacogenomicGuidelines(
+	[]models.PharmacogenomicRule{
+		{
+			Gene: "CYP2C19",
+			Phenotype: "poor metabolizer",
+			ATCPrefix: "B01AC04",
+			Severity: models.Severe,
+			Recommendation: "Avoid clopidogrel.",
+		},
+		{
+			Gene: "CYP2C9",
+			Phenotype: "poor metabolizer",
+			ATCPrefix: "M01AE01",
+			Severity: models.Moderate,
+			Recommendation: "Start ibuprofen at a lower dose.",
+		},
+	},
+)
+
+var clopidogrel = models.Drug{
+	ID: "clopidogrel-75-tab",
+	Name: "Clopidogrel",
+	ATCCode: "B01AC04",
+}
+
+func TestPharmacogenomicGuidelines_Load(t *testing.T) {
+	guidelines, err := dictionaries.LoadPharmacogenomicGuidelines(
+		"../resources/pgx_guidelines.csv",
+	)
+	require.NoError(t, err)
+
+	rules := guidelines.Recommendations("cyp2c19", "Poor Metabolizer", clopidogrel)
+	require.Len(t, rules, 1)
+	assert.Equal(t, models.Severe, rules[0].Severity)
+
+	omeprazole := models.Drug{Name: "Omeprazole", ATCCode: "A02BC01"}
+	rules = guidelines.Recommendations("CYP2C19", "ultrarapid metabolizer", omeprazole)
+	require.Len(t, rules, 1, "Expected the rule to cover the drug class")
+
+	assert.Empty(t, guidelines.Recommendations(
+		"CYP2C19", "normal metabolizer", clopidogrel,
+	))
+}
+
+func TestInteractionChecker_Check_Pharmacogenomic(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	mockGenotypeRepo := new(mocks.GenotypeRepository)
+	checker := services.NewInteractionChecker(
+		mockRepo, mockAllergyRepo, mockGenotypeRepo, testInteractions,
+		testGuidelines,
+	)
+
+	patientID := uuid.New()
+	mockRepo.On("FindByPatientID", patientID, mock.Anything).
+		Return([]models.Prescription{}, nil).
+		Once()
+	mockAllergyRepo.On("FindByPatientID", patientID).
+		Return([]models.Allergy{}, nil).
+		Once()
+	mockGenotypeRepo.On("FindByPatientID", patientID).
+		Return([]models.Genotype{
+			{Gene: "CYP2C19", Phenotype: "poor metabolizer"},
+			// Superseded by the result above.
+			{Gene: "CYP2C19", Phenotype: "normal metabolizer"},
+		}, nil).
+		Once()
+
+	warnings, err := checker.Check(patientID, clopidogrel)
+	require.NoError(t, err)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, models.Pharmacogenomic, warnings[0].Kind)
+	assert.Equal(t, models.Severe, warnings[0].Severity)
+	assert.Equal(t, "CYP2C19 poor metabolizer", warnings[0].Substance)
+	assert.Equal(t, "Avoid clopidogrel.", warnings[0].Description)
+}
+
+func TestPrescriptionService_Get_IncludesGuidance(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockGenotypeRepo := new(mocks.GenotypeRepository)
+	checker := services.NewInteractionChecker(
+		mockRepo, new(mocks.AllergyRepository), mockGenotypeRepo,
+		testInteractions, testGuidelines,
+	)
+	service := services.NewPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository),
+		newTestPatientAccess(new(mocks.CareTeamRepository)),
+		new(mocks.UserClient), testDrugs, checker,
+	)
+
+	p := &models.Prescription{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		DrugID: "ibuprofen-400-tab",
+		DrugName: "Ibuprofen",
+		ATCCode: "M01AE01",
+	}
+	mockRepo.On("FindByID", p.ID).Return(p, nil).Once()
+	mockGenotypeRepo.On("FindByPatientID", p.PatientID).
+		Return([]models.Genotype{
+			{Gene: "CYP2C9", Phenotype: "poor metabolizer"},
+		}, nil).
+		Once()
+
+	got, err := service.Get(p.ID, p.PatientID, commonsModels.Patient)
+	require.NoError(t, err)
+
+	require.Len(t, got.Guidance, 1)
+	assert.Equal(t, models.Moderate, got.Guidance[0].Severity)
+	assert.Empty(t, got.Warnings)
+}
+
+func TestPrescriptionService_Get_GenomicsDenied(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockGenotypeRepo := new(mocks.GenotypeRepository)
+	checker := services.NewInteractionChecker(
+		mockRepo, new(mocks.AllergyRepository), mockGenotypeRepo,
+		testInteractions, testGuidelines,
+	)
+
+	doctorID := uuid.New()
+	careTeamRepo := new(mocks.CareTeamRepository)
+	careTeamRepo.On("IsMember", mock.Anything, doctorID).Return(true, nil)
+	consentRepo := new(mocks.ConsentRepository)
+	consentRepo.On("FindActive", mock.Anything, mock.Anything).
+		Return([]models.Consent{{
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Deny,
+			Categories: []models.ConsentCategory{models.GenomicsCategory},
+		}}, nil)
+	service := services.NewPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository),
+		services.NewPatientAccess(
+			careTeamRepo, consentRepo, new(mocks.SlotRepository),
+			new(mocks.EmergencyAccessRepository),
+		),
+		new(mocks.UserClient), testDrugs, checker,
+	)
+
+	p := &models.Prescription{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: doctorID,
+		DrugID: "ibuprofen-400-tab",
+		DrugName: "Ibuprofen",
+		ATCCode: "M01AE01",
+	}
+	mockRepo.On("FindByID", p.ID).Return(p, nil).Once()
+
+	got, err := service.Get(p.ID, doctorID, commonsModels.Doctor)
+	require.NoError(t, err)
+
+	assert.Empty(t, got.Guidance)
+	mockGenotypeRepo.AssertNotCalled(t, "FindByPatientID", mock.Anything)
+}
+
+func TestHealthRecordService_Create_Genotype(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), new(mocks.PrescriptionRepository),
+	)
+
+	patientID := uuid.New()
+	mockRepo.On("Create", mock.AnythingOfType("*models.Genotype")).
+		Return(nil).
+		Once()
+
+	req := &dtos.GenotypeRequest{
+		Gene: " cyp2d6 ",
+		Diplotype: "*4/*4 ",
+		Phenotype: "Poor  Metabolizer",
+	}
+	record, err := service.Create(
+		models.GenotypeRecord, patientID, patientID, commonsModels.Patient, req,
+	)
+	require.NoError(t, err)
+
+	genotype := record.(*models.Genotype)
+	assert.Equal(t, "CYP2D6", genotype.Gene)
+	assert.Equal(t, "*4/*4", genotype.Diplotype)
+	assert.Equal(t, "poor metabolizer", genotype.Phenotype)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHealthRecordService_Create_UnknownPhenotype(t *testing.T) {
+	mockRepo := new(mocks.HealthRecordRepository)
+	service := newTestHealthRecordService(
+		mockRepo, new(mocks.CareTeamRepository),
+		new(mocks.EncounterRepository), new(mocks.PrescriptionRepository),
+	)
+
+	patientID := uuid.New()
+	req := &dtos.GenotypeRequest{Gene: "CYP2D6", Phenotype: "slow"}
+	_, err := service.Create(
+		models.GenotypeRecord, patientID, patientID, commonsModels.Patient, req,
+	)
+
+	assert.ErrorIs(t, err, &igakuErrors.InvalidPhenotypeError{})
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPrescriptionService_Get_EmergencyAccess(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockGenotypeRepo := new(mocks.GenotypeRepository)
+	mockEmergencyRepo := new(mocks.EmergencyAccessRepository)
+	checker := services.NewInteractionChecker(
+		mockRepo, new(mocks.AllergyRepository), mockGenotypeRepo,
+		testInteractions, testGuidelines,
+	)
+	access := services.NewPatientAccess(
+		new(mocks.CareTeamRepository), new(mocks.ConsentRepository),
+		new(mocks.SlotRepository), mockEmergencyRepo,
+	)
+	service := services.NewPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), access,
+		new(mocks.UserClient), testDrugs, checker,
+	)
+
+	doctorID := uuid.New()
+	p := &models.Prescription{
+		ID: uuid.New(),
+		PatientID: uuid.New(),
+		DoctorID: uuid.New(),
+		DrugID: "ibuprofen-400-tab",
+		DrugName: "Ibuprofen",
+		ATCCode: "M01AE01",
+	}
+	grant := newTestEmergencyAccess(p.PatientID, doctorID)
+	mockRepo.On("FindByID", p.ID).Return(p, nil)
+	mockGenotypeRepo.On("FindByPatientID", p.PatientID).
+		Return([]models.Genotype{}, nil).
+		Once()
+	mockEmergencyRepo.On("FindByID", grant.ID).Return(grant, nil)
+	// The guidance is built from genotypes, so their read is logged too.
+	var logged []models.ConsentCategory
+	mockEmergencyRepo.On("LogAccess", mock.MatchedBy(func(e *models.EmergencyAccessEvent) bool {
+		return e.AccessID == grant.ID && len(e.Categories) == 1
+	})).
+		Run(func(args mock.Arguments) {
+			e := args.Get(0).(*models.EmergencyAccessEvent)
+			logged = append(logged, e.Categories...)
+		}).
+		Return(nil)
+
+	got, err := service.Get(p.ID, grant.ID, models.EmergencyRole)
+	require.NoError(t, err)
+	assert.Equal(t, p.ID, got.ID)
+	assert.ElementsMatch(t, []models.ConsentCategory{
+		models.PrescriptionsCategory, models.RecordsCategory,
+		models.GenomicsCategory,
+	}, logged)
+
+	unknownID := uuid.New()
+	mockEmergencyRepo.On("FindByID", unknownID).
+		Return(nil, &igakuErrors.EmergencyAccessNotFoundError{})
+	_, err = service.Get(p.ID, unknownID, models.EmergencyRole)
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+}
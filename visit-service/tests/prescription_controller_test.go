@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+)
+
+func setupPrescriptionRouter(
+	mockRepo *mocks.PrescriptionRepository,
+	mockUserClient *mocks.UserClient,
+	mockAllergyRepo *mocks.AllergyRepository,
+) *gin.Engine {
+	return newPrescriptionRouter(newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), mockUserClient,
+		mockAllergyRepo,
+	))
+}
+
+func newPrescriptionRouter(service services.PrescriptionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	prescriptionController := controllers.NewPrescriptionController(service)
+
+	router := gin.Default()
+	prescriptionController.RegisterRoutes(router)
+	return router
+}
+
+func TestPrescriptionController_Issue_Patient(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	router := setupPrescriptionRouter(
+		mockRepo, new(mocks.UserClient), new(mocks.AllergyRepository),
+	)
+
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/prescriptions", bytes.NewReader([]byte("{}")),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+}
+
+func TestPrescriptionController_Verify_NoToken(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	router := setupPrescriptionRouter(
+		mockRepo, new(mocks.UserClient), new(mocks.AllergyRepository),
+	)
+
+	mockRepo.On("FindByVerificationCode", "7K2M-QX4P-9TBR").
+		Return(&models.Prescription{
+			PatientID: uuid.New(),
+			VerificationCode: "7K2M-QX4P-9TBR",
+			Status: models.PrescriptionIssued,
+		}, nil).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/visit/prescriptions/verify/7K2M-QX4P-9TBR", nil,
+	)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "patient")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrescriptionController_GetPDF_Success(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockUserClient := new(mocks.UserClient)
+	router := setupPrescriptionRouter(
+		mockRepo, mockUserClient, new(mocks.AllergyRepository),
+	)
+
+	patient := &commonsModels.User{ID: uuid.New(), Username: "jdoe"}
+	doctor := &commonsModels.User{ID: uuid.New(), Username: "ghouse"}
+	p := &models.Prescription{
+		ID: uuid.New(),
+		PatientID: patient.ID,
+		DoctorID: doctor.ID,
+		DrugName: "Atorvastatin",
+		VerificationCode: "7K2M-QX4P-9TBR",
+	}
+	mockRepo.On("FindByID", p.ID).Return(p, nil).Once()
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil).Once()
+	mockUserClient.On("FindByID", doctor.ID).Return(doctor, nil).Once()
+
+	req, _ := http.NewRequest(
+		http.MethodGet, "/visit/prescriptions/"+p.ID.String()+"/pdf", nil,
+	)
+	req.Header.Set("Authorization", genToken(t, patient.ID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "(Verification code: 7K2M-QX4P-9TBR) Tj")
+}
+
+func TestPrescriptionController_Issue_SevereInteraction(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	router := setupPrescriptionRouter(mockRepo, mockUserClient, mockAllergyRepo)
+
+	patient := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Patient}
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil).Once()
+	mockRepo.On("FindByPatientID", patient.ID, mock.Anything).
+		Return([]models.Prescription{warfarin}, nil).
+		Once()
+	mockAllergyRepo.On("FindByPatientID", patient.ID).
+		Return([]models.Allergy{}, nil).
+		Once()
+
+	prescription := newPrescriptionRequest(patient.ID)
+	prescription.DrugID = "ibuprofen-400-tab"
+	body, _ := json.Marshal(prescription)
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/prescriptions", bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var resp dtos.InteractionConflictResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Warnings, 1)
+	assert.Equal(t, models.Severe, resp.Warnings[0].Severity)
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+}
+
+func TestPrescriptionController_Check_OutsideCareTeam(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	router := newPrescriptionRouter(newTestPrescriptionServiceWithAccess(
+		mockRepo, new(mocks.EncounterRepository), new(mocks.UserClient),
+		mockAllergyRepo, newTestPatientAccess(mockCareTeamRepo),
+	))
+
+	patientID, doctorID := uuid.New(), uuid.New()
+	mockCareTeamRepo.On("IsMember", patientID, doctorID).Return(false, nil)
+
+	body, _ := json.Marshal(dtos.InteractionCheckRequest{
+		PatientID: patientID,
+		DrugID: "ibuprofen-400-tab",
+	})
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/prescriptions/check", bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, doctorID, commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "FindByPatientID", mock.Anything, mock.Anything)
+	mockAllergyRepo.AssertNotCalled(t, "FindByPatientID", mock.Anything)
+}
+
+func TestPrescriptionController_Issue_ConsentDenied(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	mockCareTeamRepo := new(mocks.CareTeamRepository)
+	mockConsentRepo := new(mocks.ConsentRepository)
+	access := services.NewPatientAccess(
+		mockCareTeamRepo, mockConsentRepo, new(mocks.SlotRepository),
+		new(mocks.EmergencyAccessRepository),
+	)
+	router := newPrescriptionRouter(newTestPrescriptionServiceWithAccess(
+		mockRepo, new(mocks.EncounterRepository), mockUserClient,
+		mockAllergyRepo, access,
+	))
+
+	patient := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Patient}
+	doctorID := uuid.New()
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil).Once()
+	mockCareTeamRepo.On("IsMember", patient.ID, doctorID).Return(true, nil)
+	mockConsentRepo.On("FindActive", patient.ID, mock.Anything).
+		Return([]models.Consent{{
+			PatientID: patient.ID,
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Deny,
+			Categories: []models.ConsentCategory{models.PrescriptionsCategory},
+		}}, nil)
+
+	prescription := newPrescriptionRequest(patient.ID)
+	prescription.DrugID = "ibuprofen-400-tab"
+	body, _ := json.Marshal(prescription)
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/prescriptions", bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, doctorID, commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// The warnings, built from the patient's data, must not be returned.
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), "warnings")
+	mockRepo.AssertNotCalled(t, "FindByPatientID", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+}
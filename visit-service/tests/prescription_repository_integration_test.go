@@ -0,0 +1,101 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormPrescriptionRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+
+	newPrescription := func(code string, validUntil time.Time) *models.Prescription {
+		return &models.Prescription{
+			ID: uuid.New(),
+			PatientID: patientID,
+			DoctorID: doctorID,
+			DrugID: "atorvastatin-20-tab",
+			DrugName: "Atorvastatin",
+			Form: "tablet",
+			Strength: "20 mg",
+			Dosage: "1 tablet once daily",
+			Quantity: 30,
+			Refills: 1,
+			ValidFrom: time.Now().Add(-time.Hour),
+			ValidUntil: validUntil,
+			Status: models.PrescriptionIssued,
+			VerificationCode: code,
+			IssuedAt: time.Now(),
+		}
+	}
+
+	t.Run("Dispense_UsesRefills", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormPrescriptionRepository(db)
+		p := newPrescription("AAAA-BBBB-CCCC", time.Now().Add(24*time.Hour))
+		require.NoError(t, repo.Persist(p))
+
+		p, err := repo.Dispense("AAAA-BBBB-CCCC", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.Fills)
+		assert.Equal(t, models.PrescriptionIssued, p.Status)
+
+		p, err = repo.Dispense("AAAA-BBBB-CCCC", time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, models.PrescriptionDispensed, p.Status)
+
+		_, err = repo.Dispense("AAAA-BBBB-CCCC", time.Now())
+		assert.ErrorIs(t, err, &igakuErrors.PrescriptionNotActiveError{})
+
+		_, err = repo.Cancel(p.ID)
+		assert.ErrorIs(t, err, &igakuErrors.PrescriptionNotActiveError{})
+	})
+
+	t.Run("ExpireDue_And_FindActive", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormPrescriptionRepository(db)
+		active := newPrescription("AAAA-BBBB-CCCC", time.Now().Add(24*time.Hour))
+		lapsed := newPrescription("DDDD-EEEE-FFFF", time.Now().Add(-time.Minute))
+		require.NoError(t, repo.Persist(active))
+		require.NoError(t, repo.Persist(lapsed))
+
+		now := time.Now()
+		found, err := repo.FindByPatientID(patientID, &now)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, active.ID, found[0].ID)
+
+		n, err := repo.ExpireDue(now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		p, err := repo.FindByID(lapsed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.PrescriptionExpired, p.Status)
+	})
+}
@@ -0,0 +1,358 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"regexp"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dictionaries"
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+var testDrugs = dictionaries.NewDrugDictionary([]models.Drug{
+	{
+		ID: "atorvastatin-20-tab",
+		Name: "Atorvastatin",
+		ATCCode: "C10AA05",
+		Form: "tablet",
+		Strength: "20 mg",
+	},
+	{
+		ID: "ibuprofen-400-tab",
+		Name: "Ibuprofen",
+		ATCCode: "M01AE01",
+		Form: "tablet",
+		Strength: "400 mg",
+	},
+	{
+		ID: "amoxicillin-500-cap",
+		Name: "Amoxicillin",
+		ATCCode: "J01CA04",
+		Form: "capsule",
+		Strength: "500 mg",
+	},
+})
+
+var testInteractions = dictionaries.NewInteractionDataset(
+	[]models.InteractionRule{
+		{
+			ATCA: "B01AA03",
+			ATCB: "M01A",
+			Severity: models.Severe,
+			Description: "NSAIDs increase the bleeding risk of warfarin.",
+		},
+	},
+	[]models.AllergyRule{
+		{
+			Allergen: "penicillin",
+			ATCPrefix: "J01C",
+			Severity: models.Severe,
+			Description: "Penicillin allergy.",
+		},
+	},
+)
+
+// newTestPrescriptionService returns a prescription service for patients
+// whose care team includes every doctor.
+func newTestPrescriptionService(
+	repo *mocks.PrescriptionRepository,
+	encounterRepo *mocks.EncounterRepository,
+	userClient *mocks.UserClient,
+	allergyRepo *mocks.AllergyRepository,
+) services.PrescriptionService {
+	careTeamRepo := new(mocks.CareTeamRepository)
+	careTeamRepo.On("IsMember", mock.Anything, mock.Anything).
+		Return(true, nil).
+		Maybe()
+
+	return newTestPrescriptionServiceWithAccess(
+		repo, encounterRepo, userClient, allergyRepo,
+		newTestPatientAccess(careTeamRepo),
+	)
+}
+
+func newTestPrescriptionServiceWithAccess(
+	repo *mocks.PrescriptionRepository,
+	encounterRepo *mocks.EncounterRepository,
+	userClient *mocks.UserClient,
+	allergyRepo *mocks.AllergyRepository,
+	access services.PatientAccess,
+) services.PrescriptionService {
+	genotypeRepo := new(mocks.GenotypeRepository)
+	genotypeRepo.On("FindByPatientID", mock.Anything).
+		Return([]models.Genotype{}, nil).
+		Maybe()
+	checker := services.NewInteractionChecker(
+		repo, allergyRepo, genotypeRepo, testInteractions, testGuidelines,
+	)
+	return services.NewPrescriptionService(
+		repo, encounterRepo, access, userClient, testDrugs, checker,
+	)
+}
+
+func newPrescriptionRequest(patientID uuid.UUID) dtos.PrescriptionRequest {
+	return dtos.PrescriptionRequest{
+		PatientID: patientID,
+		DrugID: "atorvastatin-20-tab",
+		Dosage: "1 tablet once daily",
+		Quantity: 30,
+		Refills: 2,
+		ValidUntil: time.Now().Add(90 * 24 * time.Hour),
+	}
+}
+
+func TestPrescriptionService_Issue_Success(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockAllergyRepo := new(mocks.AllergyRepository)
+	service := newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), mockUserClient,
+		mockAllergyRepo,
+	)
+
+	patient := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Patient}
+	doctorID := uuid.New()
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil).Once()
+	mockRepo.On("FindByPatientID", patient.ID, mock.Anything).
+		Return([]models.Prescription{}, nil).
+		Once()
+	mockAllergyRepo.On("FindByPatientID", patient.ID).
+		Return([]models.Allergy{}, nil).
+		Once()
+	mockRepo.On("Persist", mock.AnythingOfType("*models.Prescription")).
+		Return(nil).
+		Once()
+
+	p, err := service.Issue(doctorID, commonsModels.Doctor, newPrescriptionRequest(patient.ID))
+	require.NoError(t, err)
+
+	assert.Equal(t, models.PrescriptionIssued, p.Status)
+	assert.Equal(t, doctorID, p.DoctorID)
+	assert.Equal(t, "Atorvastatin", p.DrugName)
+	assert.Equal(t, "20 mg", p.Strength)
+	assert.Equal(t, "C10AA05", p.ATCCode)
+	assert.Empty(t, p.Warnings)
+	assert.Regexp(
+		t, regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}(-[0-9A-HJKMNP-TV-Z]{4}){2}$`),
+		p.VerificationCode,
+	)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrescriptionService_Issue_UnknownDrug(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	service := newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), new(mocks.UserClient),
+		new(mocks.AllergyRepository),
+	)
+
+	req := newPrescriptionRequest(uuid.New())
+	req.DrugID = "unobtainium-1-tab"
+	_, err := service.Issue(uuid.New(), commonsModels.Doctor, req)
+
+	assert.ErrorIs(t, err, &igakuErrors.UnknownDrugError{})
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+}
+
+func TestPrescriptionService_Issue_RecipientNotPatient(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	mockUserClient := new(mocks.UserClient)
+	service := newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), mockUserClient,
+		new(mocks.AllergyRepository),
+	)
+
+	doctor := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Doctor}
+	mockUserClient.On("FindByID", doctor.ID).Return(doctor, nil).Once()
+
+	_, err := service.Issue(uuid.New(), commonsModels.Doctor, newPrescriptionRequest(doctor.ID))
+
+	assert.ErrorIs(t, err, &igakuErrors.InvalidPatientError{})
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+}
+
+func TestPrescriptionService_Issue_ExpiredValidity(t *testing.T) {
+	service := newTestPrescriptionService(
+		new(mocks.PrescriptionRepository),
+		new(mocks.EncounterRepository),
+		new(mocks.UserClient),
+		new(mocks.AllergyRepository),
+	)
+
+	req := newPrescriptionRequest(uuid.New())
+	req.ValidUntil = time.Now().Add(-time.Hour)
+	_, err := service.Issue(uuid.New(), commonsModels.Doctor, req)
+
+	assert.ErrorIs(t, err, &igakuErrors.InvalidTimeRangeError{})
+}
+
+func TestPrescriptionService_Cancel_OtherDoctor(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	service := newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), new(mocks.UserClient),
+		new(mocks.AllergyRepository),
+	)
+
+	p := &models.Prescription{ID: uuid.New(), DoctorID: uuid.New()}
+	mockRepo.On("FindByID", p.ID).Return(p, nil).Once()
+
+	_, err := service.Cancel(p.ID, uuid.New(), commonsModels.Doctor)
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "Cancel", mock.Anything)
+}
+
+func TestPrescriptionService_Verify_NormalizesCode(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	service := newTestPrescriptionService(
+		mockRepo, new(mocks.EncounterRepository), new(mocks.UserClient),
+		new(mocks.AllergyRepository),
+	)
+
+	p := &models.Prescription{
+		VerificationCode: "7K2M-QX4P-9TB0",
+		Status: models.PrescriptionIssued,
+		DrugName: "Atorvastatin",
+	}
+	mockRepo.On("FindByVerificationCode", "7K2M-QX4P-9TB0").Return(p, nil).Once()
+
+	v, err := service.Verify("7k2mqx4p9tbo")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.PrescriptionIssued, v.Status)
+	assert.Equal(t, "Atorvastatin", v.DrugName)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPrescription_IsActive(t *testing.T) {
+	now := time.Now()
+	p := models.Prescription{
+		Status: models.PrescriptionIssued,
+		ValidFrom: now.Add(-time.Hour),
+		ValidUntil: now.Add(time.Hour),
+	}
+	assert.True(t, p.IsActive(now))
+	assert.False(t, p.IsActive(now.Add(2*time.Hour)))
+	assert.False(t, p.IsActive(now.Add(-2*time.Hour)))
+
+	p.Status = models.PrescriptionCancelled
+	assert.False(t, p.IsActive(now))
+}
+
+// newDeniedPrescriptionService returns a prescription service for patients
+// whose care team includes every doctor, but who denied doctorID the
+// category.
+func newDeniedPrescriptionService(
+	repo *mocks.PrescriptionRepository,
+	userClient *mocks.UserClient,
+	genotypeRepo *mocks.GenotypeRepository,
+	doctorID uuid.UUID,
+	category models.ConsentCategory,
+) services.PrescriptionService {
+	careTeamRepo := new(mocks.CareTeamRepository)
+	careTeamRepo.On("IsMember", mock.Anything, mock.Anything).
+		Return(true, nil).
+		Maybe()
+	consentRepo := new(mocks.ConsentRepository)
+	consentRepo.On("FindActive", mock.Anything, mock.Anything).
+		Return([]models.Consent{{
+			GranteeType: models.DoctorGrantee,
+			GranteeID: doctorID,
+			Decision: models.Deny,
+			Categories: []models.ConsentCategory{category},
+		}}, nil)
+	access := services.NewPatientAccess(
+		careTeamRepo, consentRepo, new(mocks.SlotRepository),
+		new(mocks.EmergencyAccessRepository),
+	)
+	checker := services.NewInteractionChecker(
+		repo, new(mocks.AllergyRepository), genotypeRepo, testInteractions,
+		testGuidelines,
+	)
+	return services.NewPrescriptionService(
+		repo, new(mocks.EncounterRepository), access, userClient, testDrugs,
+		checker,
+	)
+}
+
+func TestPrescriptionService_Get_InteractionDataDenied(t *testing.T) {
+	for _, category := range []models.ConsentCategory{
+		models.GenomicsCategory, models.RecordsCategory,
+	} {
+		t.Run(string(category), func(t *testing.T) {
+			mockRepo := new(mocks.PrescriptionRepository)
+			mockUserClient := new(mocks.UserClient)
+			mockGenotypeRepo := new(mocks.GenotypeRepository)
+
+			patient := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Patient}
+			doctor := &commonsModels.User{ID: uuid.New(), Role: commonsModels.Doctor}
+			service := newDeniedPrescriptionService(
+				mockRepo, mockUserClient, mockGenotypeRepo, doctor.ID, category,
+			)
+
+			p := &models.Prescription{
+				ID: uuid.New(),
+				PatientID: patient.ID,
+				DoctorID: doctor.ID,
+				DrugID: "ibuprofen-400-tab",
+				DrugName: "Ibuprofen",
+				ATCCode: "M01AE01",
+				Warnings: []models.InteractionWarning{{
+					Kind: models.Pharmacogenomic,
+					Severity: models.Moderate,
+					Substance: "CYP2C9 poor metabolizer",
+				}},
+			}
+			mockRepo.On("FindByID", p.ID).Return(p, nil)
+			mockUserClient.On("FindByID", patient.ID).Return(patient, nil)
+			mockUserClient.On("FindByID", doctor.ID).Return(doctor, nil)
+
+			// Even the issuing doctor only gets the prescription itself.
+			got, err := service.Get(p.ID, doctor.ID, commonsModels.Doctor)
+			require.NoError(t, err)
+			assert.Equal(t, p.ID, got.ID)
+			assert.Empty(t, got.Warnings)
+			assert.Empty(t, got.Guidance)
+
+			pdf, err := service.RenderPDF(p.ID, doctor.ID, commonsModels.Doctor)
+			require.NoError(t, err)
+			assert.NotContains(t, string(pdf), "CYP2C9")
+			mockGenotypeRepo.AssertNotCalled(t, "FindByPatientID", mock.Anything)
+		})
+	}
+}
+
+func TestPrescriptionService_List_InteractionDataDenied(t *testing.T) {
+	mockRepo := new(mocks.PrescriptionRepository)
+	doctorID := uuid.New()
+	service := newDeniedPrescriptionService(
+		mockRepo, new(mocks.UserClient), new(mocks.GenotypeRepository),
+		doctorID, models.RecordsCategory,
+	)
+
+	mockRepo.On("FindByDoctorID", doctorID).
+		Return([]models.Prescription{{
+			ID: uuid.New(),
+			PatientID: uuid.New(),
+			DoctorID: doctorID,
+			Warnings: []models.InteractionWarning{{
+				Kind: models.AllergyConflict, Substance: "Penicillins",
+			}},
+		}}, nil).
+		Once()
+
+	ps, err := service.List(doctorID, commonsModels.Doctor, false)
+	require.NoError(t, err)
+	require.Len(t, ps, 1)
+	assert.Empty(t, ps[0].Warnings)
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	"igaku/visit-service/utils"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+	commonsUtils "igaku/commons/utils"
+)
+
+func setupReminderRouter(mockRepo *mocks.ReminderRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	reminderService := services.NewReminderService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient), "",
+	)
+	reminderController := controllers.NewReminderController(reminderService)
+
+	router := gin.Default()
+	reminderController.RegisterRoutes(router)
+	return router
+}
+
+func genToken(t *testing.T, id uuid.UUID, role commonsModels.Role) string {
+	t.Helper()
+
+	user := &commonsModels.User{ID: id, Role: role}
+	token, err := commonsUtils.GenerateJWTToken(
+		user, time.Now(), time.Now().Add(time.Hour),
+	)
+	require.NoError(t, err)
+
+	return token
+}
+
+// genEmergencyToken returns a token for the emergency access.
+func genEmergencyToken(t *testing.T, access *models.EmergencyAccess) string {
+	t.Helper()
+
+	token, err := utils.GenerateEmergencyJWTToken(access)
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestReminderController_GetPreferences_NoToken(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	router := setupReminderRouter(mockRepo)
+
+	req, err := http.NewRequest(
+		http.MethodGet, "/visit/reminders/preferences", nil,
+	)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	mockRepo.AssertNotCalled(t, "FindPreference", mock.Anything)
+}
+
+func TestReminderController_GetPreferences_Success(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	router := setupReminderRouter(mockRepo)
+
+	userID := uuid.New()
+	mockRepo.On("FindPreference", userID).Return(&models.ReminderPreference{
+		UserID: userID,
+		DayBefore: true,
+		HourBefore: false,
+	}, nil).Once()
+
+	req, err := http.NewRequest(
+		http.MethodGet, "/visit/reminders/preferences", nil,
+	)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Patient))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var pref models.ReminderPreference
+	err = json.Unmarshal(rec.Body.Bytes(), &pref)
+	require.NoError(t, err)
+	assert.True(t, pref.DayBefore)
+	assert.False(t, pref.HourBefore)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReminderController_UpdatePreferences_Success(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	router := setupReminderRouter(mockRepo)
+
+	userID := uuid.New()
+	expected := &models.ReminderPreference{
+		UserID: userID,
+		DayBefore: false,
+		HourBefore: true,
+	}
+	mockRepo.On("SavePreference", expected).Return(nil).Once()
+
+	body := []byte(`{"day_before": false, "hour_before": true}`)
+	req, err := http.NewRequest(
+		http.MethodPut,
+		"/visit/reminders/preferences",
+		bytes.NewBuffer(body),
+	)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Patient))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReminderController_UpdatePreferences_DatabaseError(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	router := setupReminderRouter(mockRepo)
+
+	userID := uuid.New()
+	mockRepo.
+		On("SavePreference", mock.Anything).
+		Return(&commonsErrors.DatabaseError{}).
+		Once()
+
+	body := []byte(`{"day_before": true, "hour_before": true}`)
+	req, err := http.NewRequest(
+		http.MethodPut,
+		"/visit/reminders/preferences",
+		bytes.NewBuffer(body),
+	)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", genToken(t, userID, commonsModels.Patient))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,106 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+)
+
+func TestGormReminderRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	apptID := uuid.MustParse("5d8c7a8e-3c4f-4f6a-9a54-0f0c3b7e1d2a")
+
+	t.Run("ClaimDue_ClaimsOnlyOnce", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormReminderRepository(db)
+		now := time.Now()
+
+		appts, err := repo.ClaimDue(
+			models.DayBefore, now.Add(time.Hour), now.Add(24*time.Hour),
+		)
+		require.NoError(t, err)
+		require.Len(t, appts, 1)
+		assert.Equal(t, apptID, appts[0].ID)
+		assert.Equal(
+			t, "Massachusetts General Hospital",
+			appts[0].Organization.Name,
+		)
+
+		appts, err = repo.ClaimDue(
+			models.DayBefore, now.Add(time.Hour), now.Add(24*time.Hour),
+		)
+		require.NoError(t, err)
+		assert.Empty(t, appts, "Expected reminder not to be claimed twice")
+	})
+
+	t.Run("ClaimDue_RespectsOptOut", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormReminderRepository(db)
+		err := repo.SavePreference(&models.ReminderPreference{
+			UserID: patientID,
+			DayBefore: false,
+			HourBefore: true,
+		})
+		require.NoError(t, err)
+
+		now := time.Now()
+		appts, err := repo.ClaimDue(
+			models.DayBefore, now.Add(time.Hour), now.Add(24*time.Hour),
+		)
+		require.NoError(t, err)
+		assert.Empty(t, appts, "Expected opted-out reminder to be skipped")
+	})
+
+	t.Run("SavePreference_Overwrites", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormReminderRepository(db)
+
+		pref, err := repo.FindPreference(patientID)
+		require.NoError(t, err)
+		assert.Nil(t, pref)
+
+		for _, dayBefore := range []bool{false, true} {
+			err = repo.SavePreference(&models.ReminderPreference{
+				UserID: patientID,
+				DayBefore: dayBefore,
+				HourBefore: false,
+			})
+			require.NoError(t, err)
+		}
+
+		pref, err = repo.FindPreference(patientID)
+		require.NoError(t, err)
+		require.NotNil(t, pref)
+		assert.True(t, pref.DayBefore)
+		assert.False(t, pref.HourBefore)
+	})
+}
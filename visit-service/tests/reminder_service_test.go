@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsErrors "igaku/commons/errors"
+	commonsModels "igaku/commons/models"
+)
+
+func TestReminderService_SendDueReminders_ClaimsNonOverlappingWindows(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockMailClient := new(mocks.MailClient)
+	service := services.NewReminderService(
+		mockRepo, mockUserClient, mockMailClient, "igaku@mail.com",
+	)
+
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	mockRepo.
+		On("ClaimDue", models.DayBefore, now.Add(time.Hour), now.Add(24*time.Hour)).
+		Return([]models.Appointment{}, nil).
+		Once()
+	mockRepo.
+		On("ClaimDue", models.HourBefore, now, now.Add(time.Hour)).
+		Return([]models.Appointment{}, nil).
+		Once()
+
+	err := service.SendDueReminders(now)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockMailClient.AssertNotCalled(t, "SendMail", mock.Anything, mock.Anything)
+}
+
+func TestReminderService_SendDueReminders_MailsPatient(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockMailClient := new(mocks.MailClient)
+	service := services.NewReminderService(
+		mockRepo, mockUserClient, mockMailClient, "igaku@mail.com",
+	)
+
+	now := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	patient := &commonsModels.User{
+		ID: uuid.New(),
+		Username: "jdoe",
+		Email: "jdoe@mail.com",
+		Role: commonsModels.Patient,
+	}
+	appt := models.Appointment{
+		ID: uuid.New(),
+		PatientID: patient.ID,
+		DoctorID: uuid.New(),
+		StartsAt: now.Add(20 * time.Hour),
+		Status: models.Booked,
+		Organization: models.Organization{
+			Name: "Massachusetts General Hospital",
+		},
+	}
+
+	mockRepo.
+		On("ClaimDue", models.DayBefore, mock.Anything, mock.Anything).
+		Return([]models.Appointment{appt}, nil).
+		Once()
+	mockRepo.
+		On("ClaimDue", models.HourBefore, mock.Anything, mock.Anything).
+		Return([]models.Appointment{}, nil).
+		Once()
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil).Once()
+	mockMailClient.
+		On("SendMail", []string{patient.Email}, mock.MatchedBy(func(msg []byte) bool {
+			return assert.Contains(t, string(msg), "Massachusetts General Hospital") &&
+				assert.Contains(t, string(msg), "2025-06-02 05:00 UTC")
+		})).
+		Return(nil).
+		Once()
+
+	err := service.SendDueReminders(now)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockUserClient.AssertExpectations(t)
+	mockMailClient.AssertExpectations(t)
+}
+
+func TestReminderService_SendDueReminders_ContinuesAfterMailFailure(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockMailClient := new(mocks.MailClient)
+	service := services.NewReminderService(
+		mockRepo, mockUserClient, mockMailClient, "igaku@mail.com",
+	)
+
+	now := time.Now()
+	missing := models.Appointment{ID: uuid.New(), PatientID: uuid.New()}
+	patient := &commonsModels.User{ID: uuid.New(), Email: "jdoe@mail.com"}
+	present := models.Appointment{ID: uuid.New(), PatientID: patient.ID}
+
+	mockRepo.
+		On("ClaimDue", models.DayBefore, mock.Anything, mock.Anything).
+		Return([]models.Appointment{}, nil).
+		Once()
+	mockRepo.
+		On("ClaimDue", models.HourBefore, mock.Anything, mock.Anything).
+		Return([]models.Appointment{missing, present}, nil).
+		Once()
+	mockUserClient.
+		On("FindByID", missing.PatientID).
+		Return(nil, &commonsErrors.UserNotFoundError{}).
+		Once()
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil).Once()
+	mockMailClient.
+		On("SendMail", []string{patient.Email}, mock.Anything).
+		Return(nil).
+		Once()
+
+	err := service.SendDueReminders(now)
+	require.NoError(t, err)
+
+	mockUserClient.AssertExpectations(t)
+	mockMailClient.AssertExpectations(t)
+}
+
+func TestReminderService_GetPreferences_DefaultsToAllEnabled(t *testing.T) {
+	mockRepo := new(mocks.ReminderRepository)
+	service := services.NewReminderService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient), "",
+	)
+
+	userID := uuid.New()
+	mockRepo.On("FindPreference", userID).Return(nil, nil).Once()
+
+	pref, err := service.GetPreferences(userID)
+	require.NoError(t, err)
+	assert.Equal(t, userID, pref.UserID)
+	assert.True(t, pref.DayBefore)
+	assert.True(t, pref.HourBefore)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+
+	"igaku/visit-service/storage"
+)
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(
+		ctx,
+		testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image: "minio/minio:latest",
+				Cmd: []string{"server", "/data"},
+				Env: map[string]string{
+					"MINIO_ROOT_USER": "igaku",
+					"MINIO_ROOT_PASSWORD": "P@ssw0rd!",
+				},
+				ExposedPorts: []string{"9000/tcp"},
+				WaitingFor: wait.ForHTTP("/minio/health/live").
+					WithPort("9000/tcp").
+					WithStartupTimeout(30 * time.Second),
+			},
+			Started: true,
+		},
+	)
+	require.NoError(t, err, "Failed to start MinIO container")
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Errorf("Failed to terminate MinIO container: %v", err)
+		}
+	}()
+
+	endpoint, err := container.PortEndpoint(ctx, "9000/tcp", "http")
+	require.NoError(t, err)
+
+	store, err := storage.NewS3Store(storage.S3Config{
+		Endpoint: endpoint,
+		Bucket: "documents",
+		AccessKeyID: "igaku",
+		SecretAccessKey: "P@ssw0rd!",
+	})
+	require.NoError(t, err, "Expected the bucket to be created")
+
+	sum := sha256.Sum256(testPDF)
+	blob := storage.Blob{
+		Key: "patients/0b6f13da-efb9-4221-9e89-e2729ae90030/doc",
+		ContentType: "application/pdf",
+		Size: int64(len(testPDF)),
+		Checksum: hex.EncodeToString(sum[:]),
+	}
+	require.NoError(t, store.Put(blob, bytes.NewReader(testPDF)))
+
+	rc, err := store.Get(blob.Key)
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, testPDF, data)
+
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		other := blob
+		other.Key = "corrupted"
+		other.Checksum = hex.EncodeToString(make([]byte, sha256.Size))
+		assert.Error(t, store.Put(other, bytes.NewReader(testPDF)))
+	})
+
+	require.NoError(t, store.Delete(blob.Key))
+	_, err = store.Get(blob.Key)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = storage.NewS3Store(storage.S3Config{
+		Endpoint: endpoint,
+		Bucket: "documents",
+		AccessKeyID: "igaku",
+		SecretAccessKey: "wrong",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"testing"
+	"time"
+
+	"igaku/visit-service/schedulers"
+	commonsErrors "igaku/commons/errors"
+)
+
+// fakeLock is held by another replica while held is set.
+type fakeLock struct {
+	held	bool
+	keys	[]int64
+}
+
+func (l *fakeLock) TryRun(key int64, fn func() error) (bool, error) {
+	l.keys = append(l.keys, key)
+	if l.held {
+		return false, nil
+	}
+	return true, fn()
+}
+
+func TestExclusive(t *testing.T) {
+	lock := &fakeLock{}
+	var runs []time.Time
+	job := schedulers.Exclusive("test", lock, 42, func(now time.Time) error {
+		runs = append(runs, now)
+		return &commonsErrors.InternalError{}
+	})
+
+	now := time.Now()
+	err := job(now)
+	assert.ErrorIs(t, err, &commonsErrors.InternalError{})
+	assert.Equal(t, []time.Time{now}, runs)
+
+	lock.held = true
+	assert.NoError(t, job(now.Add(time.Minute)))
+	assert.Len(t, runs, 1, "Expected the job to be skipped while the lock is held")
+	assert.Equal(t, []int64{42, 42}, lock.keys)
+}
@@ -36,6 +36,8 @@ func SetupTestServices(
 		"GRAFANA_URL":		"",
 		"NOMINATIM_URL":	nominatimURL,
 		"NOMINATIM_TIMEOUT":	"2",
+		// The stub of Nominatim routes like OSRM as well.
+		"OSRM_DRIVING_URL":	nominatimURL,
 	}
 
 	err = stack.
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"igaku/visit-service/controllers"
+	"igaku/visit-service/models"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func setupWaitlistRouter(mockRepo *mocks.WaitlistRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	waitlistService := newTestWaitlistService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient),
+	)
+	waitlistController := controllers.NewWaitlistController(waitlistService)
+
+	router := gin.Default()
+	waitlistController.RegisterRoutes(router)
+	return router
+}
+
+func TestWaitlistController_Join_Success(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	router := setupWaitlistRouter(mockRepo)
+
+	patientID := uuid.New()
+	mockRepo.On("Persist", mock.AnythingOfType("*models.WaitlistEntry")).
+		Return(nil).
+		Once()
+
+	body, _ := json.Marshal(map[string]any{
+		"from": time.Now().Format(time.RFC3339),
+		"to": time.Now().Add(7 * 24 * time.Hour).Format(time.RFC3339),
+	})
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/waitlist", bytes.NewReader(body),
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var entry models.WaitlistEntry
+	err := json.Unmarshal(w.Body.Bytes(), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, patientID, entry.PatientID)
+	assert.Equal(t, models.Waiting, entry.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWaitlistController_Join_Doctor(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	router := setupWaitlistRouter(mockRepo)
+
+	req, _ := http.NewRequest(
+		http.MethodPost, "/visit/waitlist", bytes.NewReader([]byte("{}")),
+	)
+	req.Header.Set("Authorization", genToken(t, uuid.New(), commonsModels.Doctor))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+}
+
+func TestWaitlistController_AcceptOffer_Expired(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	router := setupWaitlistRouter(mockRepo)
+
+	patientID := uuid.New()
+	offerID := uuid.New()
+	mockRepo.On("FindOfferByID", offerID).Return(&models.WaitlistOffer{
+		ID: offerID,
+		Entry: models.WaitlistEntry{PatientID: patientID},
+	}, nil).Once()
+	mockRepo.On("AcceptOffer", offerID, mock.Anything).
+		Return(nil, &igakuErrors.OfferExpiredError{}).
+		Once()
+
+	req, _ := http.NewRequest(
+		http.MethodPost,
+		"/visit/waitlist/offers/"+offerID.String()+"/accept",
+		nil,
+	)
+	req.Header.Set("Authorization", genToken(t, patientID, commonsModels.Patient))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,128 @@
+//go:build integration
+
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"testing"
+	"time"
+
+	"igaku/visit-service/models"
+	"igaku/visit-service/repositories"
+	"igaku/visit-service/utils"
+	testUtils "igaku/commons/utils"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func TestGormWaitlistRepository(t *testing.T) {
+	patientID := uuid.MustParse("0b6f13da-efb9-4221-9e89-e2729ae90030")
+	doctorID := uuid.MustParse("e2c66717-12bb-4b6a-b7b6-3be939e170ad")
+	freeSlotID := uuid.MustParse("3e7b2d1c-4a5f-4b6e-9d8c-1f2a3b4c5d6e")
+	takenSlotID := uuid.MustParse("9a1f4c2e-6b7d-4e8a-8c3f-2d5e6f7a8b90")
+	apptID := uuid.MustParse("c4b0a1b2-8d6e-4c2f-9e1a-7f3d5b9a0c11")
+
+	newEntry := func() *models.WaitlistEntry {
+		return &models.WaitlistEntry{
+			ID: uuid.New(),
+			PatientID: patientID,
+			DoctorID: &doctorID,
+			From: time.Now(),
+			To: time.Now().Add(14 * 24 * time.Hour),
+			Status: models.Waiting,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	t.Run("OfferSlot_AcceptOffer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormWaitlistRepository(db)
+		slotRepo := repositories.NewGormSlotRepository(db)
+		entry := newEntry()
+		require.NoError(t, repo.Persist(entry))
+
+		offer, err := repo.OfferSlot(freeSlotID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, offer, "Expected the free slot to be offered")
+		assert.Equal(t, entry.ID, offer.EntryID)
+
+		slot, err := slotRepo.FindByID(freeSlotID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Held, slot.Status)
+
+		_, err = slotRepo.Book(freeSlotID, uuid.New())
+		assert.ErrorIs(
+			t, err, &igakuErrors.SlotUnavailableError{},
+			"Expected a held slot not to be bookable by others",
+		)
+
+		appt, err := repo.AcceptOffer(offer.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, patientID, appt.PatientID)
+
+		entry, err = repo.FindByID(entry.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Fulfilled, entry.Status)
+	})
+
+	t.Run("AcceptOffer_Expired", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormWaitlistRepository(db)
+		require.NoError(t, repo.Persist(newEntry()))
+
+		offer, err := repo.OfferSlot(freeSlotID, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, offer)
+
+		_, err = repo.AcceptOffer(offer.ID, time.Now().Add(time.Hour))
+		assert.ErrorIs(t, err, &igakuErrors.OfferExpiredError{})
+
+		slotIDs, err := repo.ExpireOffers(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{freeSlotID}, slotIDs)
+
+		offer, err = repo.OfferSlot(freeSlotID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Nil(
+			t, offer,
+			"Expected the slot not to be offered to the same entry twice",
+		)
+	})
+
+	t.Run("Cancel_FreesSlot", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db, cleanup := testUtils.SetupTestDatabase(
+			ctx, t, utils.MigrateSchema,
+		)
+		defer cleanup()
+
+		repo := repositories.NewGormWaitlistRepository(db)
+		apptRepo := repositories.NewGormAppointmentRepository(db)
+		require.NoError(t, repo.Persist(newEntry()))
+
+		appt, err := apptRepo.Cancel(apptID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Cancelled, appt.Status)
+
+		offer, err := repo.OfferSlot(takenSlotID, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, offer, "Expected the freed slot to be offered")
+		assert.Equal(t, takenSlotID, offer.SlotID)
+	})
+}
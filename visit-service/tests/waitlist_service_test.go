@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"strings"
+	"testing"
+	"time"
+
+	"igaku/visit-service/dtos"
+	"igaku/visit-service/models"
+	"igaku/visit-service/services"
+	"igaku/visit-service/tests/mocks"
+	commonsModels "igaku/commons/models"
+	igakuErrors "igaku/visit-service/errors"
+)
+
+func newTestWaitlistService(
+	repo *mocks.WaitlistRepository,
+	userClient *mocks.UserClient,
+	mailClient *mocks.MailClient,
+) services.WaitlistService {
+	return services.NewWaitlistService(
+		repo, userClient, mailClient, newTestEventClient(),
+		30*time.Minute, "igaku@mail.com",
+	)
+}
+
+func TestWaitlistService_Join_InvalidTimeRange(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	service := newTestWaitlistService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient),
+	)
+
+	now := time.Now()
+	_, err := service.Join(uuid.New(), dtos.WaitlistRequest{
+		From: now.Add(24 * time.Hour),
+		To: now,
+	})
+
+	assert.ErrorIs(t, err, &igakuErrors.InvalidTimeRangeError{})
+	mockRepo.AssertNotCalled(t, "Persist", mock.Anything)
+}
+
+func TestWaitlistService_OfferSlot_MailsPatient(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	mockUserClient := new(mocks.UserClient)
+	mockMailClient := new(mocks.MailClient)
+	service := newTestWaitlistService(mockRepo, mockUserClient, mockMailClient)
+
+	patient := &commonsModels.User{
+		ID: uuid.New(),
+		Username: "jdoe",
+		Email: "jdoe@mail.com",
+		Role: commonsModels.Patient,
+	}
+	slotID := uuid.New()
+	offer := &models.WaitlistOffer{
+		ID: uuid.New(),
+		SlotID: slotID,
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status: models.Pending,
+		Entry: models.WaitlistEntry{PatientID: patient.ID},
+		Slot: models.Slot{
+			ID: slotID,
+			StartsAt: time.Now().Add(48 * time.Hour),
+			Organization: models.Organization{
+				Name: "Massachusetts General Hospital",
+			},
+		},
+	}
+
+	mockRepo.On("OfferSlot", slotID, mock.Anything).Return(offer, nil).Once()
+	mockUserClient.On("FindByID", patient.ID).Return(patient, nil).Once()
+	mockMailClient.
+		On("SendMail", []string{patient.Email}, mock.MatchedBy(func(msg []byte) bool {
+			return strings.Contains(string(msg), "Massachusetts General Hospital")
+		})).
+		Return(nil).
+		Once()
+
+	err := service.OfferSlot(slotID)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockUserClient.AssertExpectations(t)
+	mockMailClient.AssertExpectations(t)
+}
+
+func TestWaitlistService_OfferSlot_NoMatchingEntry(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	mockMailClient := new(mocks.MailClient)
+	service := newTestWaitlistService(
+		mockRepo, new(mocks.UserClient), mockMailClient,
+	)
+
+	slotID := uuid.New()
+	mockRepo.On("OfferSlot", slotID, mock.Anything).Return(nil, nil).Once()
+
+	err := service.OfferSlot(slotID)
+	require.NoError(t, err)
+
+	mockMailClient.AssertNotCalled(t, "SendMail", mock.Anything, mock.Anything)
+}
+
+func TestWaitlistService_DeclineOffer_OtherPatient(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	service := newTestWaitlistService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient),
+	)
+
+	offerID := uuid.New()
+	mockRepo.On("FindOfferByID", offerID).Return(&models.WaitlistOffer{
+		ID: offerID,
+		Entry: models.WaitlistEntry{PatientID: uuid.New()},
+	}, nil).Once()
+
+	err := service.DeclineOffer(offerID, uuid.New())
+
+	assert.ErrorIs(t, err, &igakuErrors.AccessDeniedError{})
+	mockRepo.AssertNotCalled(t, "DeclineOffer", mock.Anything)
+}
+
+func TestWaitlistService_ExpireOffers_OffersSlotsAgain(t *testing.T) {
+	mockRepo := new(mocks.WaitlistRepository)
+	service := newTestWaitlistService(
+		mockRepo, new(mocks.UserClient), new(mocks.MailClient),
+	)
+
+	now := time.Now()
+	slotIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	mockRepo.On("ExpireOffers", now).Return(slotIDs, nil).Once()
+	mockRepo.On("OfferSlot", slotIDs[0], mock.Anything).Return(nil, nil).Once()
+	mockRepo.On("OfferSlot", slotIDs[1], mock.Anything).Return(nil, nil).Once()
+
+	err := service.ExpireOffers(now)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
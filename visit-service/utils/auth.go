@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+
+	"log"
+	"os"
+
+	"igaku/visit-service/models"
+	commonsErrors "igaku/commons/errors"
+	commonsUtils "igaku/commons/utils"
+)
+
+var jwtSecretKey = []byte(os.Getenv("SECRET_KEY"))
+
+// EmergencyClaims are the claims of the tokens issued for emergency access.
+// Besides the doctor, as the subject, they name the access and the patient
+// it was granted to, so that the token does not extend to other patients.
+type EmergencyClaims struct {
+	commonsUtils.Claims
+	AccessID	string	`json:"access_id,omitempty"`
+	PatientID	string	`json:"patient_id,omitempty"`
+}
+
+// GenerateEmergencyJWTToken issues a token for the emergency access, valid
+// for as long as the access.
+func GenerateEmergencyJWTToken(access *models.EmergencyAccess) (string, error) {
+	claims := &EmergencyClaims{
+		Claims: commonsUtils.Claims{
+			Role: models.EmergencyRole,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:	access.DoctorID.String(),
+				IssuedAt:	jwt.NewNumericDate(access.GrantedAt),
+				ExpiresAt:	jwt.NewNumericDate(access.ExpiresAt),
+				Issuer:		"igaku",
+			},
+		},
+		AccessID: access.ID.String(),
+		PatientID: access.PatientID.String(),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
+		SignedString(jwtSecretKey)
+	if err != nil {
+		log.Println("Failed to generate an emergency access JWT token")
+		return "", &commonsErrors.TokenGenerationError{}
+	}
+
+	return token, nil
+}
@@ -9,16 +9,164 @@ import (
 	commonsModels "igaku/commons/models"
 )
 
+// appendOnlyTables hold clinical records which must never change once
+// written. The application only ever inserts into them; the triggers guard
+// against anything else touching the rows.
+var appendOnlyTables = []string{
+	"encounter_revisions",
+	"encounter_attachments",
+	"consent_events",
+	"emergency_access_events",
+}
+
 func MigrateSchema(db *gorm.DB) error {
 	err := db.AutoMigrate(
 		&models.Organization{},
+		&models.DoctorSpecialty{},
+		&models.Slot{},
+		&models.Appointment{},
+		&models.CareAssignment{},
+		&models.WaitlistEntry{},
+		&models.WaitlistOffer{},
+		&models.Reminder{},
+		&models.ReminderPreference{},
+		&models.HomeAddress{},
+		&models.Encounter{},
+		&models.EncounterRevision{},
+		&models.EncounterAttachment{},
+		&models.EncounterDelegation{},
+		&models.Prescription{},
+		&models.Allergy{},
+		&models.ChronicCondition{},
+		&models.Immunization{},
+		&models.MedicationStatement{},
+		&models.Genotype{},
+		&models.LabResult{},
+		&models.Document{},
+		&models.Consent{},
+		&models.ConsentEvent{},
+		&models.EmergencyAccess{},
+		&models.EmergencyAccessEvent{},
+		&models.Conversation{},
+		&models.Message{},
 		&commonsModels.Setting{},
 	)
 
 	if err != nil {
 		log.Printf("Failed to migrate DB schema: %w", err)
 		return &commonsErrors.DatabaseError{}
-	} else {
-		return nil
 	}
+
+	// Locations used to be unique by ID alone, which OSM objects of
+	// different types may share.
+	if err := db.Exec("DROP INDEX IF EXISTS idx_organizations_loc_id").Error; err != nil {
+		log.Printf("Failed to drop the location ID index: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+
+	if err := createDistanceIndex(db); err != nil {
+		log.Printf("Failed to create distance index: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+
+	if err := createAppendOnlyTriggers(db); err != nil {
+		log.Printf("Failed to create append-only triggers: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+
+	if err := backfillCareTeams(db); err != nil {
+		log.Printf("Failed to backfill care teams: %v", err)
+		return &commonsErrors.DatabaseError{}
+	}
+
+	return nil
+}
+
+// backfillCareTeams places doctors on the care teams of the patients who
+// booked appointments with them before care teams existed. Doctors who
+// were ever on a patient's care team at the organization are left alone,
+// so that removals are not undone.
+func backfillCareTeams(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO care_assignments
+			(id, patient_id, doctor_id, organization_id, role, source, assigned_at)
+		SELECT gen_random_uuid(), a.patient_id, a.doctor_id, a.organization_id,
+			'member', 'appointment', NOW()
+		FROM appointments a
+		WHERE a.status = 'booked'
+			AND NOT EXISTS (
+				SELECT 1 FROM care_assignments c
+				WHERE c.patient_id = a.patient_id
+					AND c.doctor_id = a.doctor_id
+					AND c.organization_id = a.organization_id
+			)
+		GROUP BY a.patient_id, a.doctor_id, a.organization_id
+	`).Error
+}
+
+// createDistanceIndex indexes the locations of the organizations for the
+// nearest organization search. The expression must match the one the
+// organization repository searches with.
+func createDistanceIndex(db *gorm.DB) error {
+	for _, ext := range []string{"cube", "earthdistance"} {
+		err := db.Exec("CREATE EXTENSION IF NOT EXISTS " + ext).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	// Coordinates are stored as text, so a single malformed one would
+	// fail a plain cast and with it the whole index. Such coordinates
+	// are treated as missing instead.
+	err := db.Exec(`
+		CREATE OR REPLACE FUNCTION coordinate_or_null(value text)
+		RETURNS float8 AS $$
+			SELECT CASE
+				WHEN value ~ '^\s*[-+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)\s*$'
+				THEN value::float8
+			END
+		$$ LANGUAGE sql IMMUTABLE STRICT
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	// The index used to cast the coordinates directly.
+	err = db.Exec("DROP INDEX IF EXISTS idx_organizations_loc_earth").Error
+	if err != nil {
+		return err
+	}
+
+	return db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_organizations_earth_point
+		ON organizations USING gist (ll_to_earth(
+			coordinate_or_null(loc_lat), coordinate_or_null(loc_lon)
+		))
+	`).Error
+}
+
+func createAppendOnlyTriggers(db *gorm.DB) error {
+	err := db.Exec(`
+		CREATE OR REPLACE FUNCTION reject_modification() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'table % is append-only', TG_TABLE_NAME;
+		END;
+		$$ LANGUAGE plpgsql
+	`).Error
+	if err != nil {
+		return err
+	}
+
+	for _, table := range appendOnlyTables {
+		err := db.Exec(
+			"CREATE OR REPLACE TRIGGER " + table + "_append_only " +
+			"BEFORE UPDATE OR DELETE ON " + table + " " +
+			"FOR EACH ROW EXECUTE FUNCTION reject_modification()",
+		).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pdfPageWidth	= 595
+	pdfPageHeight	= 842
+	pdfMargin	= 56
+	pdfTitleSize	= 18
+	pdfBodySize	= 11
+	pdfLineHeight	= 16
+	// pdfLineWidth is the number of characters that safely fit a line
+	// of body text in Helvetica between the margins.
+	pdfLineWidth	= 85
+)
+
+// RenderTextPDF lays out a title and lines of text on as many A4 pages as
+// needed. It uses the standard Helvetica fonts, which PDF readers provide
+// without embedding, so Polish letters are transliterated and any other
+// characters outside Latin-1 are replaced by '?'.
+// Long lines are wrapped at word boundaries; empty lines are kept as
+// vertical space.
+func RenderTextPDF(title string, lines []string) []byte {
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapPDFLine(line)...)
+	}
+
+	perPage := (pdfPageHeight - 2*pdfMargin - 2*pdfLineHeight) / pdfLineHeight
+	var pages [][]string
+	for len(wrapped) > perPage {
+		pages = append(pages, wrapped[:perPage])
+		wrapped = wrapped[perPage:]
+	}
+	pages = append(pages, wrapped)
+
+	// Objects 1-5 are the catalog, page tree, fonts and document info.
+	// Every page then takes two objects: the page and its content stream.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Title (%s) /Producer (Igaku) >>", escapePDFText(title)),
+	}
+
+	kids := make([]string, 0, len(pages))
+	for i, pageLines := range pages {
+		pageObj := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj))
+
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin - pdfTitleSize
+		if i == 0 {
+			fmt.Fprintf(
+				&content, "BT /F2 %d Tf %d %d Td (%s) Tj ET\n",
+				pdfTitleSize, pdfMargin, y, escapePDFText(title),
+			)
+		}
+		y -= 2 * pdfLineHeight
+		for _, line := range pageLines {
+			if line != "" {
+				fmt.Fprintf(
+					&content, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n",
+					pdfBodySize, pdfMargin, y, escapePDFText(line),
+				)
+			}
+			y -= pdfLineHeight
+		}
+		fmt.Fprintf(
+			&content, "BT /F1 9 Tf %d %d Td (Page %d of %d) Tj ET\n",
+			pdfMargin, pdfMargin/2, i+1, len(pages),
+		)
+
+		objects = append(objects,
+			fmt.Sprintf(
+				"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+				"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> "+
+				"/Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, pageObj+1,
+			),
+			fmt.Sprintf(
+				"<< /Length %d >>\nstream\n%sendstream",
+				content.Len(), content.String(),
+			),
+		)
+	}
+	objects[1] = fmt.Sprintf(
+		"<< /Type /Pages /Kids [%s] /Count %d >>",
+		strings.Join(kids, " "), len(pages),
+	)
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(
+		&out,
+		"trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, xref,
+	)
+
+	return out.Bytes()
+}
+
+func wrapPDFLine(line string) []string {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	current := words[0]
+	for _, word := range words[1:] {
+		if len(current)+1+len(word) > pdfLineWidth {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current += " " + word
+	}
+
+	return append(lines, current)
+}
+
+// pdfTransliterations maps letters missing from WinAnsiEncoding which
+// commonly appear in Polish names to their closest ASCII letter.
+var pdfTransliterations = map[rune]rune{
+	'ą': 'a', 'ć': 'c', 'ę': 'e', 'ł': 'l', 'ń': 'n', 'ś': 's', 'ź': 'z', 'ż': 'z',
+	'Ą': 'A', 'Ć': 'C', 'Ę': 'E', 'Ł': 'L', 'Ń': 'N', 'Ś': 'S', 'Ź': 'Z', 'Ż': 'Z',
+}
+
+// escapePDFText encodes a string as the body of a PDF literal string in
+// WinAnsiEncoding, which matches Latin-1 for printable characters.
+func escapePDFText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if t, ok := pdfTransliterations[r]; ok {
+			r = t
+		}
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}